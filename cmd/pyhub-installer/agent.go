@@ -0,0 +1,348 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"time"
+
+	"github.com/pyhub-kr/pyhub-installer/internal/agent"
+	"github.com/pyhub-kr/pyhub-installer/internal/exitcode"
+	"github.com/pyhub-kr/pyhub-installer/internal/i18n"
+	"github.com/pyhub-kr/pyhub-installer/internal/notify"
+	"github.com/pyhub-kr/pyhub-installer/internal/ui"
+	"github.com/pyhub-kr/pyhub-installer/pkg/config"
+	"github.com/spf13/cobra"
+)
+
+var agentCmd = &cobra.Command{
+	Use:   "agent",
+	Short: "Update installed tools periodically according to their update policy",
+	Long: `Check the installed tools for newer releases every interval and install the
+updates their policy allows. Policies are set per tool in config.json under
+"repos" ("update") and for all other tools under "agent":
+
+  latest   any newer release (default)
+  minor    newer releases of the installed major version
+  patch    newer releases of the installed minor version
+  pinned   never updated
+
+A version constraint such as ">=2, <3" is accepted too. Results are appended to
+agent.log in the installer data directory; with --notify (or "notify" under "agent")
+they are also shown as a desktop notification.
+
+Run "agent --once" from a scheduler, or let "agent install" set one up: a systemd
+user timer on Linux, a launchd agent on macOS, a scheduled task on Windows.
+
+Examples:
+  pyhub-installer agent --once --dry-run
+  pyhub-installer agent --interval 6h
+  pyhub-installer agent install --interval 12h`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		if err := runAgent(cmd, args); err != nil {
+			fmt.Fprint(os.Stderr, i18n.T("Error: %v\n", err))
+			os.Exit(exitcode.Code(err))
+		}
+	},
+}
+
+var agentInstallCmd = &cobra.Command{
+	Use:   "install",
+	Short: "Schedule the agent with the service manager of this system",
+	Args:  cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		if err := runAgentInstall(cmd, args); err != nil {
+			fmt.Fprint(os.Stderr, i18n.T("Error: %v\n", err))
+			os.Exit(exitcode.Code(err))
+		}
+	},
+}
+
+var agentUninstallCmd = &cobra.Command{
+	Use:   "uninstall",
+	Short: "Remove the agent schedule",
+	Args:  cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		if err := runAgentUninstall(cmd, args); err != nil {
+			fmt.Fprint(os.Stderr, i18n.T("Error: %v\n", err))
+			os.Exit(exitcode.Code(err))
+		}
+	},
+}
+
+func init() {
+	agentCmd.Flags().Bool("once", false, "Check and update once, then exit")
+	agentCmd.Flags().Bool("dry-run", false, "Report available updates without installing them")
+	agentCmd.Flags().Bool("notify", false, "Show a desktop notification for available and installed updates (default: agent.notify in config.json)")
+	agentCmd.Flags().Duration("interval", 0, "Time between runs (default: agent.interval in config.json)")
+	agentInstallCmd.Flags().Duration("interval", 0, "Time between runs (default: agent.interval in config.json)")
+
+	agentCmd.AddCommand(agentInstallCmd)
+	agentCmd.AddCommand(agentUninstallCmd)
+	rootCmd.AddCommand(agentCmd)
+}
+
+// agentInterval returns the --interval flag, or the configured interval
+func agentInterval(cmd *cobra.Command, cfg *config.Config) (time.Duration, error) {
+	interval, _ := cmd.Flags().GetDuration("interval")
+	if interval != 0 {
+		if interval < 0 {
+			return 0, exitcode.Wrap(exitcode.Usage, fmt.Errorf("--interval must be positive"))
+		}
+		return interval, nil
+	}
+	// Validated when the config is loaded
+	return time.ParseDuration(cfg.Agent.Interval)
+}
+
+// runAgent implements the agent command
+func runAgent(cmd *cobra.Command, args []string) error {
+	once, _ := cmd.Flags().GetBool("once")
+	dryRun, _ := cmd.Flags().GetBool("dry-run")
+	notifyFlag, _ := cmd.Flags().GetBool("notify")
+
+	cfg, err := config.Load()
+	if err != nil {
+		return err
+	}
+	notifyDesktop := notifyFlag || cfg.Agent.Notify
+	interval, err := agentInterval(cmd, cfg)
+	if err != nil {
+		return err
+	}
+
+	ctx := cmd.Context()
+
+	for {
+		if err := runAgentOnce(ctx, cfg, dryRun, notifyDesktop); err != nil {
+			if once {
+				return err
+			}
+			fmt.Fprint(os.Stderr, i18n.T("Warning: %v\n", err))
+		}
+		if once {
+			return nil
+		}
+		fmt.Print(i18n.T("Next check at %s\n", time.Now().Add(interval).Format("2006-01-02 15:04")))
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-time.After(interval):
+		}
+	}
+}
+
+// runAgentOnce checks the installed tools and installs the allowed updates, logging
+// each result to agent.log and optionally summarizing them in a desktop notification
+func runAgentOnce(ctx context.Context, cfg *config.Config, dryRun, notifyDesktop bool) error {
+	installed := loadState()
+	if installed == nil || len(installed.Receipts) == 0 {
+		fmt.Println(i18n.T("No installed tools to update"))
+		return nil
+	}
+
+	results := agent.Check(ctx, installed.Receipts, cfg.UpdatePolicyFor, releaseResolver(cfg))
+
+	logFile := openAgentLog()
+	if logFile != nil {
+		defer logFile.Close()
+	}
+	failed := 0
+	var available, updated []string
+	for _, result := range results {
+		var outcome string
+		switch result.Status {
+		case agent.Available:
+			if dryRun {
+				outcome = fmt.Sprintf("%s -> %s available", result.From, result.To)
+				available = append(available, fmt.Sprintf("%s %s → %s", result.Tool, result.From, result.To))
+				fmt.Print(i18n.T("%s %s: %s -> %s available\n", ui.Warn("!"), result.Tool, result.From, result.To))
+				break
+			}
+			fmt.Print(i18n.T("Updating %s: %s -> %s\n", result.Tool, result.From, result.To))
+			args := []string{"install"}
+			if result.InstallPath != "" {
+				args = append(args, "--output", result.InstallPath)
+			}
+			if result.Channel != "" {
+				args = append(args, "--channel", result.Channel)
+			}
+			if result.Built {
+				args = append(args, "--build-from-source")
+			}
+			args = append(args, "--", result.Source+"@"+result.To)
+			if code := runSelf(ctx, args, os.Stdout); code != exitcode.OK {
+				failed++
+				outcome = fmt.Sprintf("%s -> %s failed (exit code %d)", result.From, result.To, code)
+				fmt.Print(i18n.T("%s %s: update to %s failed\n", ui.Failure("✗"), result.Tool, result.To))
+			} else {
+				outcome = fmt.Sprintf("%s -> %s updated", result.From, result.To)
+				updated = append(updated, fmt.Sprintf("%s %s → %s", result.Tool, result.From, result.To))
+				fmt.Print(i18n.T("%s %s updated to %s\n", ui.Success("✓"), result.Tool, result.To))
+			}
+		case agent.Failed:
+			failed++
+			outcome = fmt.Sprintf("%s check failed: %v", result.From, result.Err)
+			fmt.Print(i18n.T("%s %s: %v\n", ui.Failure("✗"), result.Tool, result.Err))
+		case agent.UpToDate:
+			outcome = result.From + " up to date"
+		case agent.Skipped:
+			outcome = result.From + " skipped"
+		}
+		if logFile != nil {
+			fmt.Fprintf(logFile, "%s %s %s\n", time.Now().Format(time.RFC3339), result.Tool, outcome)
+		}
+	}
+	if notifyDesktop {
+		notifyUpdates(available, updated)
+	}
+	if failed > 0 {
+		return fmt.Errorf("%d of %d tools could not be updated", failed, len(results))
+	}
+	return nil
+}
+
+// releaseResolver returns the resolver of update checks, which looks up releases like
+// install does, under the configured latest policies
+func releaseResolver(cfg *config.Config) agent.Resolver {
+	return func(ctx context.Context, source, version, channel string) (string, error) {
+		prov, src, err := parseSource(source)
+		if err != nil {
+			return "", err
+		}
+		latest, err := latestPolicy(cfg, src)
+		if err != nil {
+			return "", err
+		}
+		release, err := resolveRelease(ctx, prov, src, version, channel, latest)
+		if err != nil {
+			return "", err
+		}
+		return release.TagName, nil
+	}
+}
+
+// notifyUpdates shows a desktop notification listing available and installed updates;
+// failures are reported and otherwise ignored, e.g. without a desktop session
+func notifyUpdates(available, updated []string) {
+	var title string
+	var lines []string
+	switch {
+	case len(updated) > 0:
+		title, lines = i18n.T("%d tools updated", len(updated)), updated
+	case len(available) > 0:
+		title, lines = i18n.T("%d tool updates available", len(available)), available
+	default:
+		return
+	}
+	if err := notify.Send(title, strings.Join(lines, "\n")); err != nil {
+		fmt.Print(i18n.T("Warning: %v\n", err))
+	}
+}
+
+// openAgentLog opens agent.log in the data directory for appending, or returns nil
+// after a warning
+func openAgentLog() io.WriteCloser {
+	dataDir, err := config.DataDir()
+	if err == nil {
+		err = os.MkdirAll(dataDir, 0755)
+	}
+	if err != nil {
+		fmt.Print(i18n.T("Warning: %v\n", err))
+		return nil
+	}
+	f, err := os.OpenFile(filepath.Join(dataDir, "agent.log"), os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		fmt.Print(i18n.T("Warning: %v\n", err))
+		return nil
+	}
+	return f
+}
+
+// agentSchedule returns the schedule of the agent on this system
+func agentSchedule(interval time.Duration) (*agent.Schedule, error) {
+	executable, err := os.Executable()
+	if err != nil {
+		return nil, fmt.Errorf("failed to locate the installer executable: %w", err)
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return nil, err
+	}
+	schedule, err := agent.NewSchedule(runtime.GOOS, executable, home, interval)
+	if err != nil {
+		return nil, exitcode.Wrap(exitcode.Usage, err)
+	}
+	return schedule, nil
+}
+
+// runAgentInstall implements the agent install command
+func runAgentInstall(cmd *cobra.Command, args []string) error {
+	cfg, err := config.Load()
+	if err != nil {
+		return err
+	}
+	interval, err := agentInterval(cmd, cfg)
+	if err != nil {
+		return err
+	}
+	schedule, err := agentSchedule(interval)
+	if err != nil {
+		return err
+	}
+
+	for _, file := range schedule.Files {
+		if err := os.MkdirAll(filepath.Dir(file.Path), 0755); err != nil {
+			return err
+		}
+		if err := os.WriteFile(file.Path, []byte(file.Content), 0644); err != nil {
+			return fmt.Errorf("failed to write %s: %w", file.Path, err)
+		}
+		fmt.Print(i18n.T("Wrote %s\n", file.Path))
+	}
+	for _, command := range schedule.Enable {
+		if err := runServiceCommand(command); err != nil {
+			return err
+		}
+	}
+	fmt.Print(i18n.T("%s The agent runs every %s\n", ui.Success("✓"), interval))
+	return nil
+}
+
+// runAgentUninstall implements the agent uninstall command
+func runAgentUninstall(cmd *cobra.Command, args []string) error {
+	// The interval does not matter for removal
+	schedule, err := agentSchedule(time.Hour)
+	if err != nil {
+		return err
+	}
+	for _, command := range schedule.Disable {
+		if err := runServiceCommand(command); err != nil {
+			fmt.Print(i18n.T("Warning: %v\n", err))
+		}
+	}
+	for _, file := range schedule.Files {
+		if err := os.Remove(file.Path); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+	}
+	fmt.Print(i18n.T("%s The agent schedule was removed\n", ui.Success("✓")))
+	return nil
+}
+
+// runServiceCommand runs a service manager command with its output on the terminal
+func runServiceCommand(command []string) error {
+	c := exec.Command(command[0], command[1:]...)
+	c.Stdout = os.Stdout
+	c.Stderr = os.Stderr
+	if err := c.Run(); err != nil {
+		return fmt.Errorf("%s failed: %w", strings.Join(command, " "), err)
+	}
+	return nil
+}
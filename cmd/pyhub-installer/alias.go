@@ -0,0 +1,213 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/pyhub-kr/pyhub-installer/internal/alias"
+	"github.com/pyhub-kr/pyhub-installer/internal/exitcode"
+	"github.com/pyhub-kr/pyhub-installer/internal/i18n"
+	"github.com/pyhub-kr/pyhub-installer/internal/shim"
+	"github.com/pyhub-kr/pyhub-installer/internal/state"
+	"github.com/spf13/cobra"
+)
+
+var aliasCmd = &cobra.Command{
+	Use:   "alias [NAME TOOL [-- ARGS...]]",
+	Short: "Add a command that runs an installed tool, optionally with default arguments",
+	Long: `Create the command NAME in the install directory of TOOL, running its executable
+with ARGS followed by the arguments NAME is given. Without ARGS the command is a
+symlink (a .cmd shim on Windows), otherwise a shim. TOOL is an installed tool or
+its alias; the executable defaults to TOOL as given and can be chosen with --bin.
+Aliases are recorded with the tool they run. Without arguments, aliases are listed.
+
+Examples:
+  pyhub-installer alias rgs rg -- --smart-case --hidden
+  pyhub-installer alias g cli --bin gh
+  pyhub-installer alias
+  pyhub-installer alias --remove rgs`,
+	Args: cobra.ArbitraryArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		if err := runAlias(cmd, args); err != nil {
+			fmt.Fprint(os.Stderr, i18n.T("Error: %v\n", err))
+			os.Exit(exitcode.Code(err))
+		}
+	},
+}
+
+func init() {
+	aliasCmd.Flags().String("bin", "", "Executable of the tool to run (default: TOOL)")
+	aliasCmd.Flags().Bool("remove", false, "Remove the aliases named by the arguments")
+
+	rootCmd.AddCommand(aliasCmd)
+}
+
+// runAlias implements the alias command
+func runAlias(cmd *cobra.Command, args []string) error {
+	bin, _ := cmd.Flags().GetString("bin")
+	remove, _ := cmd.Flags().GetBool("remove")
+
+	db, err := state.DefaultDB()
+	if err != nil {
+		return err
+	}
+	if remove {
+		if len(args) == 0 {
+			return exitcode.Wrap(exitcode.Usage, fmt.Errorf("--remove needs the names of the aliases to remove"))
+		}
+		return removeAliases(db, args)
+	}
+
+	positional, defaultArgs := args, []string(nil)
+	if dash := cmd.ArgsLenAtDash(); dash >= 0 {
+		positional, defaultArgs = args[:dash], args[dash:]
+	}
+	if len(positional) == 0 && len(defaultArgs) == 0 {
+		return listAliases(db)
+	}
+	if len(positional) == 2 {
+		return addAlias(db, positional[0], positional[1], bin, defaultArgs)
+	}
+	return exitcode.Wrap(exitcode.Usage, fmt.Errorf("usage: alias NAME TOOL [-- ARGS...]"))
+}
+
+// addAlias creates an alias for an installed tool and records it
+func addAlias(db *state.DB, name, tool, bin string, args []string) error {
+	if name == "" || strings.ContainsAny(name, `/\`) {
+		return exitcode.Wrap(exitcode.Usage, fmt.Errorf("invalid alias name %q", name))
+	}
+
+	return db.Update(func(s *state.State) error {
+		receipt := findInstalled(s, tool)
+		if receipt == nil {
+			return exitcode.Wrap(exitcode.NotFound, fmt.Errorf("%s is not installed", tool))
+		}
+		if bin == "" {
+			bin = tool
+		}
+		target, err := toolExecutable(receipt.InstallPath, bin)
+		if err != nil {
+			return err
+		}
+
+		// Replace an alias of the same name, but never another file
+		if existing := s.GetAlias(name); existing != nil {
+			if err := os.Remove(existing.Path); err != nil && !os.IsNotExist(err) {
+				return fmt.Errorf("failed to replace alias %s: %w", name, err)
+			}
+		}
+		path, err := createAlias(receipt.InstallPath, name, target, args)
+		if err != nil {
+			return err
+		}
+
+		s.PutAlias(state.Alias{Name: name, Tool: receipt.Name, Target: target, Args: args, Path: path})
+		fmt.Print(i18n.T("Created %s running %s\n", path, strings.Join(append([]string{target}, args...), " ")))
+		return nil
+	})
+}
+
+// findInstalled returns the receipt of a tool given by its name or alias, or nil
+func findInstalled(s *state.State, tool string) *state.Receipt {
+	if receipt := s.Get(tool); receipt != nil {
+		return receipt
+	}
+	registry, err := alias.Load()
+	if err != nil {
+		return nil
+	}
+	source, ok := registry.Lookup(tool)
+	if !ok {
+		return nil
+	}
+	for i := range s.Receipts {
+		if s.Receipts[i].Source == source {
+			return &s.Receipts[i]
+		}
+	}
+	return nil
+}
+
+// toolExecutable returns the path of an executable in an install directory, adding
+// .exe on Windows
+func toolExecutable(dir, bin string) (string, error) {
+	candidates := []string{filepath.Join(dir, bin)}
+	if runtime.GOOS == "windows" && filepath.Ext(bin) == "" {
+		candidates = append([]string{filepath.Join(dir, bin+".exe")}, candidates...)
+	}
+	for _, path := range candidates {
+		if info, err := os.Stat(path); err == nil && !info.IsDir() {
+			return path, nil
+		}
+	}
+	return "", exitcode.Wrap(exitcode.NotFound, fmt.Errorf("no executable %s in %s; choose one with --bin", bin, dir))
+}
+
+// createAlias creates the alias command: a symlink where possible, otherwise a shim
+func createAlias(dir, name, target string, args []string) (string, error) {
+	path := filepath.Join(dir, name)
+	if runtime.GOOS == "windows" {
+		path += ".cmd"
+	}
+	if _, err := os.Lstat(path); err == nil {
+		return "", fmt.Errorf("%s already exists", path)
+	}
+
+	if len(args) > 0 || runtime.GOOS == "windows" {
+		return shim.Create(dir, name, target, args)
+	}
+	if err := os.Symlink(target, path); err != nil {
+		return "", fmt.Errorf("failed to create alias %s: %w", name, err)
+	}
+	return path, nil
+}
+
+// removeAliases deletes aliases and their files
+func removeAliases(db *state.DB, names []string) error {
+	return db.Update(func(s *state.State) error {
+		for _, name := range names {
+			removed := s.RemoveAlias(name)
+			if removed == nil {
+				return exitcode.Wrap(exitcode.NotFound, fmt.Errorf("no alias named %s", name))
+			}
+			if err := removeAliasFiles([]state.Alias{*removed}); err != nil {
+				return err
+			}
+			fmt.Print(i18n.T("Removed alias %s\n", name))
+		}
+		return nil
+	})
+}
+
+// removeAliasFiles deletes the symlinks and shims of aliases
+func removeAliasFiles(aliases []state.Alias) error {
+	for _, a := range aliases {
+		if err := os.Remove(a.Path); err != nil && !os.IsNotExist(err) {
+			return fmt.Errorf("failed to remove alias %s: %w", a.Name, err)
+		}
+	}
+	return nil
+}
+
+// listAliases prints the recorded aliases
+func listAliases(db *state.DB) error {
+	installed, err := db.Load()
+	if err != nil {
+		return err
+	}
+	if len(installed.Aliases) == 0 {
+		fmt.Println(i18n.T("No aliases; create one with: pyhub-installer alias NAME TOOL [-- ARGS...]"))
+		return nil
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "ALIAS\tTOOL\tCOMMAND")
+	for _, a := range installed.Aliases {
+		fmt.Fprintf(w, "%s\t%s\t%s\n", a.Name, a.Tool, strings.Join(append([]string{a.Target}, a.Args...), " "))
+	}
+	return w.Flush()
+}
@@ -0,0 +1,109 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/pyhub-kr/pyhub-installer/internal/exitcode"
+	"github.com/pyhub-kr/pyhub-installer/internal/extract"
+	"github.com/pyhub-kr/pyhub-installer/internal/i18n"
+	"github.com/pyhub-kr/pyhub-installer/internal/install"
+	"github.com/pyhub-kr/pyhub-installer/internal/platform"
+	"github.com/pyhub-kr/pyhub-installer/internal/provider"
+	"github.com/pyhub-kr/pyhub-installer/internal/tempdir"
+)
+
+// sourceBuildAsset returns the source archive of the release for --build-from-source,
+// if the provider serves one
+func sourceBuildAsset(ctx context.Context, prov provider.ReleaseProvider, src provider.Source, release *provider.Release) (*provider.Asset, error) {
+	archiver, ok := prov.(provider.SourceArchiver)
+	if !ok {
+		return nil, fmt.Errorf("%s sources cannot be built from source", src.Scheme)
+	}
+	return archiver.SourceArchive(ctx, src, release)
+}
+
+// buildFromSource unpacks a downloaded source archive of a Go module and builds the
+// tool's main package for the target platform ("" for this machine). It returns the
+// path of the executable, named after the tool, in a temporary directory that cleanup
+// removes.
+func buildFromSource(ctx context.Context, archivePath, tool, target string) (string, func(), error) {
+	goTool, err := exec.LookPath("go")
+	if err != nil {
+		return "", nil, fmt.Errorf("--build-from-source needs the Go toolchain, but go was not found in PATH")
+	}
+
+	tmpDir, err := tempdir.MkdirTemp("build-*")
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to create temporary directory: %w", err)
+	}
+	cleanup := func() { os.RemoveAll(tmpDir) }
+
+	srcDir := filepath.Join(tmpDir, "src")
+	extractor := extract.NewExtractor(archivePath, srcDir)
+	extractor.SetAutoFlatten(true)
+	if err := extractor.ExtractContext(ctx); err != nil {
+		cleanup()
+		return "", nil, exitcode.Wrap(exitcode.Extraction, fmt.Errorf("failed to extract the source archive: %w", err))
+	}
+	if _, err := os.Stat(filepath.Join(srcDir, "go.mod")); err != nil {
+		cleanup()
+		return "", nil, exitcode.Wrap(exitcode.NotFound, fmt.Errorf("%s is not a Go module (no go.mod at the top of its source), so it cannot be built from source", tool))
+	}
+
+	// Projects with several commands keep the tool's in cmd/<tool>
+	pkg := "."
+	if info, err := os.Stat(filepath.Join(srcDir, "cmd", tool)); err == nil && info.IsDir() {
+		pkg = "./cmd/" + tool
+	}
+
+	if target == "" {
+		target = platform.Native()
+	}
+	goos, goarch := platform.Split(target)
+	binary := filepath.Join(tmpDir, tool)
+	if goos == "windows" {
+		binary += ".exe"
+	}
+
+	fmt.Print(i18n.T("Building %s from source for %s...\n", tool, target))
+	cmd := exec.CommandContext(ctx, goTool, "build", "-trimpath", "-o", binary, pkg)
+	cmd.Dir = srcDir
+	cmd.Env = append(os.Environ(), goEnv(goos, goarch)...)
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	if err := cmd.Run(); err != nil {
+		cleanup()
+		return "", nil, fmt.Errorf("go build failed: %w", err)
+	}
+	return binary, cleanup, nil
+}
+
+// goEnv returns the environment selecting a platform for go build. The ARM versions
+// of the platform names map to GOARM, and cross builds disable cgo, which would need
+// a C cross compiler.
+func goEnv(goos, goarch string) []string {
+	env := []string{"GOOS=" + goos}
+	if version, ok := strings.CutPrefix(goarch, "armv"); ok {
+		env = append(env, "GOARCH=arm", "GOARM="+version)
+	} else {
+		env = append(env, "GOARCH="+goarch)
+	}
+	if goos+"-"+goarch != platform.Native() {
+		env = append(env, "CGO_ENABLED=0")
+	}
+	return env
+}
+
+// installBuilt installs an executable built from source into output
+func installBuilt(binary, output string) ([]string, error) {
+	dest := filepath.Join(output, filepath.Base(binary))
+	if err := install.NewInstaller(binary, dest, "755").Install(); err != nil {
+		return nil, err
+	}
+	return []string{dest}, nil
+}
@@ -0,0 +1,139 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/pyhub-kr/pyhub-installer/internal/clean"
+	"github.com/pyhub-kr/pyhub-installer/internal/exitcode"
+	"github.com/pyhub-kr/pyhub-installer/internal/i18n"
+	"github.com/pyhub-kr/pyhub-installer/pkg/config"
+	"github.com/spf13/cobra"
+)
+
+// autoPruneInterval is how often the cache is pruned after a command
+const autoPruneInterval = 24 * time.Hour
+
+// pruneStampFile records the last automatic prune in the cache directory. It is
+// rewritten after each prune, so it is among the last entries to be evicted.
+const pruneStampFile = ".last-prune"
+
+var cacheCmd = &cobra.Command{
+	Use:   "cache",
+	Short: "Manage the download cache",
+}
+
+var cachePruneCmd = &cobra.Command{
+	Use:   "prune",
+	Short: "Evict cache entries over the configured age and total size",
+	Long: `Remove cache files older than cache_max_age_days, then the least recently
+modified files until the cache fits cache_max_size_mb. The same pruning runs
+automatically once a day after any command unless auto_prune_cache is false.
+
+Examples:
+  pyhub-installer cache prune --dry-run
+  pyhub-installer cache prune --max-size 200 --max-age 168h`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		if err := runCachePrune(cmd, args); err != nil {
+			fmt.Fprint(os.Stderr, i18n.T("Error: %v\n", err))
+			os.Exit(exitcode.Code(err))
+		}
+	},
+}
+
+func init() {
+	cachePruneCmd.Flags().Bool("dry-run", false, "Show what would be removed without removing anything")
+	cachePruneCmd.Flags().Int64("max-size", 0, "Maximum total cache size in MB, 0 for no limit (default: cache_max_size_mb from config)")
+	cachePruneCmd.Flags().Duration("max-age", 0, "Age after which cache entries are removed (default: cache_max_age_days from config)")
+
+	cacheCmd.AddCommand(cachePruneCmd)
+	rootCmd.AddCommand(cacheCmd)
+}
+
+// runCachePrune implements the cache prune command
+func runCachePrune(cmd *cobra.Command, args []string) error {
+	dryRun, _ := cmd.Flags().GetBool("dry-run")
+
+	cfg, err := config.Load()
+	if err != nil {
+		return err
+	}
+	cleaner, err := cachePruner(cfg)
+	if err != nil {
+		return err
+	}
+	cleaner.DryRun = dryRun
+	if cmd.Flags().Changed("max-size") {
+		maxSize, _ := cmd.Flags().GetInt64("max-size")
+		if maxSize < 0 {
+			return exitcode.Wrap(exitcode.Usage, fmt.Errorf("--max-size cannot be negative"))
+		}
+		cleaner.MaxCacheSize = maxSize * 1024 * 1024
+	}
+	if cmd.Flags().Changed("max-age") {
+		cleaner.MaxAge, _ = cmd.Flags().GetDuration("max-age")
+	}
+
+	result, err := cleaner.PruneCache()
+	if err != nil {
+		return err
+	}
+
+	action := "Removed"
+	if dryRun {
+		action = "Would remove"
+	}
+	for _, path := range result.Removed {
+		fmt.Printf("%s: %s\n", action, path)
+	}
+
+	if len(result.Removed) == 0 {
+		fmt.Println(i18n.T("✓ Nothing to prune"))
+		return nil
+	}
+
+	if dryRun {
+		fmt.Print(i18n.T("Would reclaim %s\n", clean.FormatSize(result.ReclaimedBytes)))
+	} else {
+		fmt.Print(i18n.T("✓ Reclaimed %s\n", clean.FormatSize(result.ReclaimedBytes)))
+	}
+	return nil
+}
+
+// cachePruner returns a cleaner of the cache directory with the configured limits
+func cachePruner(cfg *config.Config) (*clean.Cleaner, error) {
+	cacheDir, err := config.CacheDir()
+	if err != nil {
+		return nil, err
+	}
+	cleaner := clean.NewCleaner("", "", cacheDir, time.Duration(cfg.CacheMaxAgeDays)*24*time.Hour)
+	cleaner.MaxCacheSize = int64(cfg.CacheMaxSizeMB) * 1024 * 1024
+	return cleaner, nil
+}
+
+// autoPruneCache prunes the cache if the last automatic prune is older than a day.
+// Like the update check, it is best-effort and never fails a command.
+func autoPruneCache() {
+	cfg, err := config.Load()
+	if err != nil || !cfg.AutoPruneCache {
+		return
+	}
+	cleaner, err := cachePruner(cfg)
+	if err != nil {
+		return
+	}
+
+	stamp := filepath.Join(cleaner.CacheDir, pruneStampFile)
+	if info, err := os.Stat(stamp); err == nil && time.Since(info.ModTime()) < autoPruneInterval {
+		return
+	}
+	if _, err := cleaner.PruneCache(); err != nil {
+		return
+	}
+	if err := os.MkdirAll(cleaner.CacheDir, 0755); err == nil {
+		os.WriteFile(stamp, nil, 0644)
+	}
+}
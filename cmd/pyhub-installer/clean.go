@@ -0,0 +1,101 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/pyhub-kr/pyhub-installer/internal/clean"
+	"github.com/pyhub-kr/pyhub-installer/internal/exitcode"
+	"github.com/pyhub-kr/pyhub-installer/internal/i18n"
+	"github.com/pyhub-kr/pyhub-installer/internal/install"
+	"github.com/pyhub-kr/pyhub-installer/pkg/config"
+	"github.com/spf13/cobra"
+)
+
+var cleanCmd = &cobra.Command{
+	Use:   "clean",
+	Short: "Remove superseded versions, orphaned links, stale backups and old cache entries",
+	Args:  cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		if err := runClean(cmd, args); err != nil {
+			fmt.Fprint(os.Stderr, i18n.T("Error: %v\n", err))
+			os.Exit(exitcode.Code(err))
+		}
+	},
+}
+
+func init() {
+	cleanCmd.Flags().Bool("dry-run", false, "Show what would be removed without removing anything")
+	cleanCmd.Flags().Duration("older-than", 0, "Age after which backups and cache entries are removed (default: clean_max_age_days from config)")
+
+	rootCmd.AddCommand(cleanCmd)
+}
+
+// runClean implements the clean command
+func runClean(cmd *cobra.Command, args []string) error {
+	dryRun, _ := cmd.Flags().GetBool("dry-run")
+
+	cfg, err := config.Load()
+	if err != nil {
+		return err
+	}
+
+	maxAge := time.Duration(cfg.CleanMaxAgeDays) * 24 * time.Hour
+	if cmd.Flags().Changed("older-than") {
+		maxAge, _ = cmd.Flags().GetDuration("older-than")
+	}
+
+	storeDir, err := config.StoreDir()
+	if err != nil {
+		return err
+	}
+	backupDir, err := config.BackupDir()
+	if err != nil {
+		return err
+	}
+	cacheDir, err := config.CacheDir()
+	if err != nil {
+		return err
+	}
+
+	cleaner := clean.NewCleaner(storeDir, backupDir, cacheDir, maxAge)
+	cleaner.MaxCacheSize = int64(cfg.CacheMaxSizeMB) * 1024 * 1024
+	cleaner.DryRun = dryRun
+
+	// Links of the store layout point into the store
+	if binDir, err := config.BinDir(); err == nil {
+		cleaner.BinDirs = append(cleaner.BinDirs, binDir)
+	}
+	// Links created by the standard install layout point into its install root
+	if installPath, binPath, err := install.GetStandardInstallPath(config.AppName); err == nil {
+		cleaner.BinDirs = append(cleaner.BinDirs, binPath)
+		cleaner.ManagedRoots = append(cleaner.ManagedRoots, filepath.Dir(installPath))
+	}
+
+	result, err := cleaner.Clean()
+	if err != nil {
+		return err
+	}
+
+	action := "Removed"
+	if dryRun {
+		action = "Would remove"
+	}
+	for _, path := range result.Removed {
+		fmt.Printf("%s: %s\n", action, path)
+	}
+
+	if len(result.Removed) == 0 {
+		fmt.Println(i18n.T("✓ Nothing to clean"))
+		return nil
+	}
+
+	if dryRun {
+		fmt.Print(i18n.T("Would reclaim %s\n", clean.FormatSize(result.ReclaimedBytes)))
+	} else {
+		fmt.Print(i18n.T("✓ Reclaimed %s\n", clean.FormatSize(result.ReclaimedBytes)))
+	}
+	return nil
+}
@@ -0,0 +1,143 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/pyhub-kr/pyhub-installer/internal/clean"
+	"github.com/pyhub-kr/pyhub-installer/internal/exitcode"
+	"github.com/pyhub-kr/pyhub-installer/internal/extract"
+	"github.com/pyhub-kr/pyhub-installer/internal/i18n"
+	"github.com/pyhub-kr/pyhub-installer/internal/state"
+	"github.com/pyhub-kr/pyhub-installer/internal/store"
+	"github.com/pyhub-kr/pyhub-installer/internal/tempdir"
+	"github.com/pyhub-kr/pyhub-installer/internal/treediff"
+	"github.com/pyhub-kr/pyhub-installer/pkg/config"
+	"github.com/spf13/cobra"
+)
+
+var diffCmd = &cobra.Command{
+	Use:   "diff TOOL VERSION_A VERSION_B",
+	Short: "Compare the files of two versions of a tool",
+	Long: `Compare the files of two installed versions of a tool and list the files added,
+removed or changed between them, by SHA256 hash. Versions are looked up in the
+versioned directories of the store; a directory or a downloaded archive may be given
+instead of a version, e.g. to inspect an update before installing it.
+
+Examples:
+  pyhub-installer diff mytool 1.2.0 1.3.0
+  pyhub-installer diff mytool 1.2.0 ./mytool-1.3.0-linux-amd64.tar.gz`,
+	Args: cobra.ExactArgs(3),
+	Run: func(cmd *cobra.Command, args []string) {
+		if err := runDiff(cmd, args); err != nil {
+			fmt.Fprint(os.Stderr, i18n.T("Error: %v\n", err))
+			os.Exit(exitcode.Code(err))
+		}
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(diffCmd)
+}
+
+// runDiff implements the diff command
+func runDiff(cmd *cobra.Command, args []string) error {
+	tool := args[0]
+	var manifests [2]treediff.Manifest
+	for i, version := range args[1:] {
+		dir, cleanup, err := diffTree(tool, version)
+		if err != nil {
+			return err
+		}
+		manifests[i], err = treediff.Scan(dir)
+		cleanup()
+		if err != nil {
+			return err
+		}
+	}
+
+	changes := treediff.Compare(manifests[0], manifests[1])
+	var added, removed, changed int
+	for _, change := range changes {
+		switch change.Kind {
+		case treediff.Added:
+			added++
+			fmt.Printf("+ %s (%s)\n", change.Path, describeEntry(change.New))
+		case treediff.Removed:
+			removed++
+			fmt.Printf("- %s (%s)\n", change.Path, describeEntry(change.Old))
+		case treediff.Changed:
+			changed++
+			fmt.Printf("M %s (%s -> %s)\n", change.Path, describeEntry(change.Old), describeEntry(change.New))
+		}
+	}
+	if len(changes) == 0 {
+		fmt.Print(i18n.T("%s %s and %s have the same files\n", tool, args[1], args[2]))
+		return nil
+	}
+	fmt.Print(i18n.T("%d added, %d removed, %d changed\n", added, removed, changed))
+	return nil
+}
+
+// describeEntry summarizes a file for the diff output: its link target, or its size
+// and the start of its hash
+func describeEntry(entry *treediff.Entry) string {
+	if entry.Link != "" {
+		return "-> " + entry.Link
+	}
+	if entry.SHA256 == "" {
+		return entry.Mode.String()
+	}
+	return clean.FormatSize(entry.Size) + ", " + entry.SHA256[:12]
+}
+
+// diffTree returns the directory holding a version of a tool: a directory or archive
+// path, or a version directory in the store. Archives are extracted into a temporary
+// directory that cleanup removes.
+func diffTree(tool, version string) (string, func(), error) {
+	noop := func() {}
+	if info, err := os.Stat(version); err == nil {
+		if info.IsDir() {
+			return version, noop, nil
+		}
+		dir, err := tempdir.MkdirTemp("diff-")
+		if err != nil {
+			return "", noop, err
+		}
+		cleanup := func() { os.RemoveAll(dir) }
+		if err := extract.NewExtractor(version, dir).Extract(); err != nil {
+			cleanup()
+			return "", noop, fmt.Errorf("failed to extract %s: %w", version, err)
+		}
+		return dir, cleanup, nil
+	}
+
+	storeDir, err := config.StoreDir()
+	if err != nil {
+		return "", noop, err
+	}
+	db, err := state.DefaultDB()
+	if err != nil {
+		return "", noop, err
+	}
+	s, err := db.Load()
+	if err != nil {
+		return "", noop, err
+	}
+	name := tool
+	if receipt := findInstalled(s, tool); receipt != nil {
+		name = receipt.Name
+	}
+	toolDir := filepath.Join(storeDir, name)
+	versions, err := store.Versions(toolDir)
+	if err != nil || len(versions) == 0 {
+		return "", noop, exitcode.Wrap(exitcode.NotFound, fmt.Errorf("%s is not installed in versioned directories of %s", name, storeDir))
+	}
+	match := matchVersion(versions, version)
+	if match == "" {
+		return "", noop, exitcode.Wrap(exitcode.NotFound, fmt.Errorf("%s %s is not installed (installed: %s)", name, version, strings.Join(versions, ", ")))
+	}
+	return filepath.Join(toolDir, match), noop, nil
+}
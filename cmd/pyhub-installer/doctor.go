@@ -0,0 +1,176 @@
+package main
+
+import (
+	"fmt"
+	"net/http"
+	"os"
+	"runtime"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/pyhub-kr/pyhub-installer/internal/doctor"
+	"github.com/pyhub-kr/pyhub-installer/internal/exitcode"
+	"github.com/pyhub-kr/pyhub-installer/internal/github"
+	"github.com/pyhub-kr/pyhub-installer/internal/i18n"
+	"github.com/pyhub-kr/pyhub-installer/internal/install"
+	"github.com/pyhub-kr/pyhub-installer/internal/sysproxy"
+	"github.com/pyhub-kr/pyhub-installer/internal/ui"
+	"github.com/pyhub-kr/pyhub-installer/pkg/config"
+	"github.com/spf13/cobra"
+)
+
+var doctorCmd = &cobra.Command{
+	Use:   "doctor",
+	Short: "Diagnose installer environment problems",
+	Long: `Check the environment the installer depends on and suggest fixes: the install
+directory and PATH, network access to GitHub, proxy settings, GITHUB_TOKEN and,
+on Windows, whether symbolic links can be created.`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		if err := runDoctor(cmd, args); err != nil {
+			fmt.Fprint(os.Stderr, i18n.T("Error: %v\n", err))
+			os.Exit(exitcode.Code(err))
+		}
+	},
+}
+
+var doctorPathsCmd = &cobra.Command{
+	Use:   "paths",
+	Short: "Explain how the installation directory is selected",
+	Args:  cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		if err := runDoctorPaths(cmd, args); err != nil {
+			fmt.Fprint(os.Stderr, i18n.T("Error: %v\n", err))
+			os.Exit(exitcode.Code(err))
+		}
+	},
+}
+
+func init() {
+	doctorCmd.AddCommand(doctorPathsCmd)
+	rootCmd.AddCommand(doctorCmd)
+}
+
+// runDoctor implements the doctor command
+func runDoctor(cmd *cobra.Command, args []string) error {
+	results := doctor.CheckInstallDir(install.ExplainInstallPath(), runtime.GOOS)
+
+	httpClient := &http.Client{Timeout: 10 * time.Second}
+	for _, endpoint := range doctor.Endpoints {
+		results = append(results, doctor.CheckReachable(httpClient, endpoint))
+	}
+	results = append(results, doctor.CheckProxy(os.Getenv, systemProxy()))
+	results = append(results, doctor.CheckToken(github.NewClient(github.WithTimeout(10*time.Second))))
+	if runtime.GOOS == "windows" {
+		results = append(results, doctor.CheckSymlinks(os.TempDir()))
+	}
+
+	failed := 0
+	for _, r := range results {
+		fmt.Printf("%s %s: %s\n", statusSymbol(r.Status), r.Name, r.Detail)
+		if r.Fix != "" {
+			fmt.Print(i18n.T("  Fix: %s\n", r.Fix))
+		}
+		if r.Status == doctor.Failed {
+			failed++
+		}
+	}
+
+	fmt.Println()
+	if failed > 0 {
+		return fmt.Errorf("%d of %d checks failed", failed, len(results))
+	}
+	fmt.Print(i18n.T("%s No problems found that prevent installs\n", ui.Success("✓")))
+	return nil
+}
+
+// statusSymbol returns the colored marker of a check result
+func statusSymbol(status doctor.Status) string {
+	switch status {
+	case doctor.OK:
+		return ui.Success(status.Symbol())
+	case doctor.Warning:
+		return ui.Warn(status.Symbol())
+	default:
+		return ui.Failure(status.Symbol())
+	}
+}
+
+// runDoctorPaths implements the doctor paths command
+func runDoctorPaths(cmd *cobra.Command, args []string) error {
+	candidates := install.ExplainInstallPath()
+
+	fmt.Println(i18n.T("Install directory candidates, in the order they are tried:"))
+	fmt.Println()
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "  \tDIRECTORY\tIN PATH\tWRITABLE\tNOTES")
+
+	var chosen string
+	for _, c := range candidates {
+		marker := " "
+		if c.Chosen {
+			marker = "→"
+			chosen = c.Path
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n", marker, c.Path, yesNo(c.InPath), yesNo(c.Writable), candidateNotes(c))
+	}
+	w.Flush()
+
+	fmt.Println()
+	if reason := systemInstallReason(); reason != "" {
+		fmt.Print(reason)
+		return nil
+	}
+	if chosen == "" {
+		fmt.Println(i18n.T("No usable directory found; pass --output to choose one explicitly"))
+		return nil
+	}
+
+	fmt.Print(i18n.T("Selected: %s\n", chosen))
+	if !install.IsPathInEnv(chosen) {
+		fmt.Print(i18n.T("Note: %s is not in PATH; installed tools will not be found by name\n", chosen))
+	}
+	return nil
+}
+
+// candidateNotes summarizes why a candidate ranks where it does
+func candidateNotes(c install.PathCandidate) string {
+	var notes []string
+	if c.SkipReason != "" {
+		notes = append(notes, "skipped: "+c.SkipReason)
+	}
+	if _, err := os.Stat(c.Path); os.IsNotExist(err) && c.SkipReason == "" {
+		if c.Fallback {
+			notes = append(notes, "will be created")
+		} else {
+			notes = append(notes, "does not exist")
+		}
+	}
+	if c.LanguageSpecific {
+		notes = append(notes, "language-specific (tried last)")
+	}
+	if c.Fallback {
+		notes = append(notes, "fallback")
+	}
+	return strings.Join(notes, ", ")
+}
+
+// yesNo formats a boolean for tabular output
+func yesNo(b bool) string {
+	if b {
+		return "yes"
+	}
+	return "no"
+}
+
+// systemProxy returns the detected proxy of the operating system, or nil if none is
+// configured or system_proxy is disabled
+func systemProxy() *sysproxy.Settings {
+	if cfg, err := config.Load(); err == nil && !cfg.SystemProxy {
+		return nil
+	}
+	settings, _ := sysproxy.Detect()
+	return settings
+}
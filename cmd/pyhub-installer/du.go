@@ -0,0 +1,195 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/pyhub-kr/pyhub-installer/internal/clean"
+	"github.com/pyhub-kr/pyhub-installer/internal/exitcode"
+	"github.com/pyhub-kr/pyhub-installer/internal/i18n"
+	"github.com/pyhub-kr/pyhub-installer/internal/tempdir"
+	"github.com/pyhub-kr/pyhub-installer/pkg/config"
+	"github.com/spf13/cobra"
+)
+
+var duCmd = &cobra.Command{
+	Use:   "du",
+	Short: "Report the disk usage of installed tools, the cache and backups",
+	Long: `Report how much disk space the installer manages: each tool and version in the
+store (* marks the active version), tools installed elsewhere with their recorded
+files, and the install prefixes, store, cache, backup and temp directories. Ends with
+what "clean" and "cache prune" would reclaim.
+
+Examples:
+  pyhub-installer du`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		if err := runDu(cmd, args); err != nil {
+			fmt.Fprint(os.Stderr, i18n.T("Error: %v\n", err))
+			os.Exit(exitcode.Code(err))
+		}
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(duCmd)
+}
+
+// runDu implements the du command
+func runDu(cmd *cobra.Command, args []string) error {
+	cfg, err := config.Load()
+	if err != nil {
+		return err
+	}
+	storeDir, err := config.StoreDir()
+	if err != nil {
+		return err
+	}
+	backupDir, err := config.BackupDir()
+	if err != nil {
+		return err
+	}
+	cacheDir, err := config.CacheDir()
+	if err != nil {
+		return err
+	}
+
+	tools, err := clean.StoreUsage(storeDir)
+	if err != nil {
+		return fmt.Errorf("failed to measure the store: %w", err)
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "TOOL\tVERSION\tSIZE\tLOCATION")
+	for _, tool := range tools {
+		for _, v := range tool.Versions {
+			version := v.Version
+			if v.Current {
+				version += " *"
+			}
+			fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", tool.Name, version, clean.FormatSize(v.Size), filepath.Join(storeDir, tool.Name, v.Version))
+		}
+	}
+
+	// Tools installed outside the store are measured by the files recorded for them
+	var prefixes []string
+	if installed := loadState(); installed != nil {
+		for _, receipt := range installed.Receipts {
+			prefixes = appendUnique(prefixes, receipt.InstallPath)
+			if underDir(receipt.InstallPath, storeDir) {
+				continue
+			}
+			files := receipt.Files
+			if receipt.Asset != "" {
+				files = append(files, filepath.Join(receipt.InstallPath, receipt.Asset))
+			}
+			var size int64
+			for _, file := range files {
+				if underDir(file, storeDir) {
+					continue
+				}
+				if n, err := clean.DirSize(file); err == nil {
+					size += n
+				}
+			}
+			fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", receipt.Name, receipt.Version, clean.FormatSize(size), receipt.InstallPath)
+		}
+	}
+	if err := w.Flush(); err != nil {
+		return err
+	}
+	sort.Strings(prefixes)
+
+	fmt.Println()
+	w = tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "DIRECTORY\tSIZE\tPATH")
+	var total int64
+	dirs := [][2]string{{"store", storeDir}, {"cache", cacheDir}, {"backups", backupDir}, {"temp", tempdir.Root()}}
+	for _, prefix := range prefixes {
+		if !underDir(prefix, storeDir) {
+			dirs = append(dirs, [2]string{"prefix", prefix})
+		}
+	}
+	for _, dir := range dirs {
+		size, err := clean.DirSize(dir[1])
+		if os.IsNotExist(err) {
+			continue
+		}
+		if err != nil {
+			fmt.Print(i18n.T("Warning: %v\n", err))
+			continue
+		}
+		// Prefixes may be shared with other software, so they are not added to the total
+		if dir[0] != "prefix" {
+			total += size
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\n", dir[0], clean.FormatSize(size), dir[1])
+	}
+	if err := w.Flush(); err != nil {
+		return err
+	}
+	fmt.Print(i18n.T("Total managed by the installer: %s\n", clean.FormatSize(total)))
+
+	// What clean would remove, measured with a dry run
+	cleaner := clean.NewCleaner(storeDir, backupDir, cacheDir, time.Duration(cfg.CleanMaxAgeDays)*24*time.Hour)
+	cleaner.MaxCacheSize = int64(cfg.CacheMaxSizeMB) * 1024 * 1024
+	cleaner.DryRun = true
+	result, err := cleaner.Clean()
+	if err != nil {
+		return err
+	}
+	var versions, cache, backups int64
+	for _, tool := range tools {
+		versions += tool.Superseded()
+	}
+	for _, path := range result.Removed {
+		size, _ := clean.DirSize(path)
+		switch {
+		case underDir(path, cacheDir):
+			cache += size
+		case underDir(path, backupDir):
+			backups += size
+		}
+	}
+
+	fmt.Println()
+	if result.ReclaimedBytes == 0 {
+		fmt.Println(i18n.T("✓ Nothing to clean"))
+		return nil
+	}
+	fmt.Print(i18n.T("\"pyhub-installer clean\" would reclaim %s:\n", clean.FormatSize(result.ReclaimedBytes)))
+	if versions > 0 {
+		fmt.Print(i18n.T("  %s in superseded versions\n", clean.FormatSize(versions)))
+	}
+	if backups > 0 {
+		fmt.Print(i18n.T("  %s in backups older than %d days\n", clean.FormatSize(backups), cfg.CleanMaxAgeDays))
+	}
+	if cache > 0 {
+		fmt.Print(i18n.T("  %s in the cache (also removed by \"pyhub-installer cache prune\")\n", clean.FormatSize(cache)))
+	}
+	return nil
+}
+
+// underDir reports whether path is dir or lies below it
+func underDir(path, dir string) bool {
+	path, dir = filepath.Clean(path), filepath.Clean(dir)
+	return path == dir || strings.HasPrefix(path, dir+string(os.PathSeparator))
+}
+
+// appendUnique appends a non-empty value that is not yet in list
+func appendUnique(list []string, value string) []string {
+	if value == "" {
+		return list
+	}
+	for _, v := range list {
+		if v == value {
+			return list
+		}
+	}
+	return append(list, value)
+}
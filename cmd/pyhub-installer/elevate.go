@@ -0,0 +1,59 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"runtime"
+
+	"github.com/pyhub-kr/pyhub-installer/internal/i18n"
+	"github.com/pyhub-kr/pyhub-installer/internal/platform"
+	"github.com/pyhub-kr/pyhub-installer/internal/prompt"
+	"github.com/pyhub-kr/pyhub-installer/internal/wsl"
+)
+
+// offerPkexec offers to rerun the command as root with pkexec when a system-wide
+// install cannot write to dir in a Linux desktop session, and exits with the exit code
+// of the elevated run if the user accepts. It returns when pkexec is unavailable, the
+// offer is declined or the authentication is dismissed.
+func offerPkexec(dir string) {
+	if platform.IsElevated() || wsl.Detect() || platform.Container() != "" {
+		return
+	}
+	if !platform.PkexecAvailable(runtime.GOOS, os.Getenv, exec.LookPath) {
+		return
+	}
+	if !prompt.IsInteractive() && !prompt.Default().AssumeYes {
+		return
+	}
+	ok, err := prompt.Default().Confirm(i18n.T("%s is not writable. Install system-wide with pkexec (asks for an administrator password)?", dir), true)
+	if err != nil || !ok {
+		return
+	}
+	executable, err := os.Executable()
+	if err != nil {
+		fmt.Fprint(os.Stderr, i18n.T("Warning: %v\n", err))
+		return
+	}
+
+	command := platform.PkexecCommand(executable, os.Args[1:], os.Environ())
+	c := exec.Command(command[0], command[1:]...)
+	c.Stdin = os.Stdin
+	c.Stdout = os.Stdout
+	c.Stderr = os.Stderr
+	err = c.Run()
+	var exitErr *exec.ExitError
+	switch {
+	case errors.As(err, &exitErr):
+		// pkexec exits with 126 when the dialog is dismissed and 127 when not authorized
+		if code := exitErr.ExitCode(); code != 126 && code != 127 {
+			os.Exit(code)
+		}
+		fmt.Fprintln(os.Stderr, i18n.T("Elevation with pkexec was not authorized"))
+	case err != nil:
+		fmt.Fprint(os.Stderr, i18n.T("Warning: %v\n", err))
+	default:
+		os.Exit(0)
+	}
+}
@@ -0,0 +1,92 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/pyhub-kr/pyhub-installer/internal/exitcode"
+	"github.com/pyhub-kr/pyhub-installer/internal/i18n"
+	"github.com/pyhub-kr/pyhub-installer/internal/shellenv"
+	"github.com/pyhub-kr/pyhub-installer/internal/state"
+	"github.com/spf13/cobra"
+)
+
+var envCmd = &cobra.Command{
+	Use:   "env",
+	Short: "Print shell code that puts the install directories on PATH",
+	Long: `Print the commands that add the directories of installed tools and aliases,
+and the default install directory, to PATH. Directories already in PATH are
+skipped when the code runs, so it can be evaluated from a shell profile.
+
+Examples:
+  eval "$(pyhub-installer env)"                           # ~/.bashrc or ~/.zshrc
+  pyhub-installer env --shell fish | source               # ~/.config/fish/config.fish
+  pyhub-installer env --shell powershell | Out-String | Invoke-Expression   # $PROFILE`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		if err := runEnv(cmd, args); err != nil {
+			fmt.Fprint(os.Stderr, i18n.T("Error: %v\n", err))
+			os.Exit(exitcode.Code(err))
+		}
+	},
+}
+
+func init() {
+	envCmd.Flags().String("shell", "", "Shell syntax: "+strings.Join(shellenv.Shells, ", ")+" (default: detected from SHELL)")
+
+	rootCmd.AddCommand(envCmd)
+}
+
+// runEnv implements the env command
+func runEnv(cmd *cobra.Command, args []string) error {
+	shell, _ := cmd.Flags().GetString("shell")
+	if shell == "" {
+		shell = shellenv.Detect(os.Getenv)
+	}
+
+	dirs, err := managedDirs()
+	if err != nil {
+		return err
+	}
+	script, err := shellenv.Script(shell, dirs)
+	if err != nil {
+		return exitcode.Wrap(exitcode.Usage, err)
+	}
+	fmt.Print(script)
+	return nil
+}
+
+// managedDirs returns the existing directories tools and aliases were installed to,
+// followed by the default install directory
+func managedDirs() ([]string, error) {
+	db, err := state.DefaultDB()
+	if err != nil {
+		return nil, err
+	}
+	installed, err := db.Load()
+	if err != nil {
+		return nil, err
+	}
+
+	var dirs []string
+	seen := make(map[string]bool)
+	add := func(dir string) {
+		if dir == "" || seen[dir] {
+			return
+		}
+		seen[dir] = true
+		if info, err := os.Stat(dir); err == nil && info.IsDir() {
+			dirs = append(dirs, dir)
+		}
+	}
+	for _, receipt := range installed.Receipts {
+		add(receipt.InstallPath)
+	}
+	for _, a := range installed.Aliases {
+		add(filepath.Dir(a.Path))
+	}
+	add(getDefaultInstallPath())
+	return dirs, nil
+}
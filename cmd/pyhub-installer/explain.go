@@ -0,0 +1,102 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/pyhub-kr/pyhub-installer/internal/github"
+	"github.com/pyhub-kr/pyhub-installer/internal/i18n"
+	"github.com/pyhub-kr/pyhub-installer/internal/platform"
+	"github.com/pyhub-kr/pyhub-installer/internal/ui"
+)
+
+// assetSelection records how the asset of an install was chosen
+type assetSelection int
+
+const (
+	selectedByScore       assetSelection = iota // Best platform score
+	selectedByPattern                           // --asset or a manifest asset pattern
+	selectedSourceArchive                       // The release has nothing but its source archive
+	selectedByUser                              // Picked from the prompt
+	selectedSourceBuild                         // Source archive built with --build-from-source
+)
+
+// explainAsset prints the platform score of every asset of the job's release, what
+// made up each score and why the chosen asset won, for install --explain
+func explainAsset(j *installJob, opts installOptions) {
+	target := opts.Platform
+	if target == "" {
+		target = platform.Native()
+	}
+	ranked, err := j.release.RankAssets(target, assetScoring(opts.Config))
+	if err != nil {
+		fmt.Print(i18n.T("Asset scores are not available: %v\n", err))
+		return
+	}
+
+	_, arch := platform.Split(target)
+	fmt.Print(i18n.T("Asset scores for %s:\n", target))
+	for _, scored := range ranked {
+		marker := " "
+		if scored.Asset.Name == j.asset.Name {
+			marker = ui.Success("→")
+		}
+		fmt.Printf("  %s %4d  %s\n", marker, scored.Score, scored.Asset.Name)
+		fmt.Printf("          %s\n", describeScore(scored, arch))
+	}
+	fmt.Print(i18n.T("Reason: %s\n", selectionReason(j, ranked, target, opts)))
+}
+
+// describeScore lists the keywords and adjustments that make up an asset's score
+func describeScore(scored github.ScoredAsset, arch string) string {
+	var parts []string
+	if len(scored.Keywords) > 0 {
+		parts = append(parts, i18n.T("keywords: %s", strings.Join(scored.Keywords, ", ")))
+	} else {
+		parts = append(parts, i18n.T("no platform keywords"))
+	}
+
+	keys := make([]string, 0, len(scored.Adjustments))
+	for key := range scored.Adjustments {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		parts = append(parts, fmt.Sprintf("%s %+d", key, scored.Adjustments[key]))
+	}
+
+	if !scored.Runnable {
+		parts = append(parts, i18n.T("built for another architecture than %s", arch))
+	}
+	return strings.Join(parts, "; ")
+}
+
+// selectionReason explains why the job's asset was chosen among the ranked assets
+func selectionReason(j *installJob, ranked []github.ScoredAsset, target string, opts installOptions) string {
+	switch j.selection {
+	case selectedByPattern:
+		return i18n.T("it matches the asset pattern %q; platform scores were not used", opts.AssetPattern)
+	case selectedSourceArchive:
+		return i18n.T("the release has no assets besides its source archive")
+	case selectedByUser:
+		return i18n.T("it was selected from the list")
+	case selectedSourceBuild:
+		return i18n.T("no asset matches the platform, so the source archive is built with --build-from-source")
+	}
+
+	if len(ranked) == 0 || ranked[0].Asset.Name != j.asset.Name {
+		if _, note, ok := platform.Fallback(target); ok {
+			return note
+		}
+		return i18n.T("it has the best score")
+	}
+	best := ranked[0]
+	if tied := github.TiedAssets(ranked); len(tied) > 1 {
+		return i18n.T("%d assets share the top score %d and the first in release order was used; select one with --asset or --interactive", len(tied), best.Score)
+	}
+	if len(ranked) > 1 && ranked[1].Score == best.Score {
+		return i18n.T("it has the top score %d and, unlike the assets with the same score, runs on %s", best.Score, target)
+	}
+	return i18n.T("it has the top score %d for %s", best.Score, target)
+}
@@ -0,0 +1,75 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/pyhub-kr/pyhub-installer/internal/exitcode"
+	"github.com/pyhub-kr/pyhub-installer/internal/export"
+	"github.com/pyhub-kr/pyhub-installer/internal/i18n"
+	"github.com/pyhub-kr/pyhub-installer/internal/state"
+	"github.com/spf13/cobra"
+)
+
+var exportCmd = &cobra.Command{
+	Use:   "export TOOL",
+	Short: "Generate a Scoop manifest or Homebrew formula for an installed tool",
+	Long: `Generate a package manifest from the install receipt of a tool: its download
+URL, SHA256 and executables. The receipt describes the asset installed on this
+machine, so export on Windows for Scoop and on macOS or Linux for Homebrew.
+
+Examples:
+  pyhub-installer export pyhub-mcptools --format scoop > pyhub-mcptools.json
+  pyhub-installer export rg --format brew --bin rg --bin complete/rg.bash`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		if err := runExport(cmd, args); err != nil {
+			fmt.Fprint(os.Stderr, i18n.T("Error: %v\n", err))
+			os.Exit(exitcode.Code(err))
+		}
+	},
+}
+
+func init() {
+	exportCmd.Flags().StringP("format", "f", "", "Manifest format: "+strings.Join(export.Formats, " or "))
+	exportCmd.Flags().StringSlice("bin", nil, "Executable path inside the archive (repeatable, default: the tool name)")
+	exportCmd.Flags().StringP("output", "o", "", "Write the manifest to a file instead of stdout")
+	exportCmd.MarkFlagRequired("format")
+
+	rootCmd.AddCommand(exportCmd)
+}
+
+// runExport implements the export command
+func runExport(cmd *cobra.Command, args []string) error {
+	format, _ := cmd.Flags().GetString("format")
+	bins, _ := cmd.Flags().GetStringSlice("bin")
+	output, _ := cmd.Flags().GetString("output")
+
+	db, err := state.DefaultDB()
+	if err != nil {
+		return err
+	}
+	installed, err := db.Load()
+	if err != nil {
+		return err
+	}
+	receipt := installed.Get(args[0])
+	if receipt == nil {
+		return exitcode.Wrap(exitcode.NotFound, fmt.Errorf("%s is not installed", args[0]))
+	}
+
+	manifest, err := export.Manifest(format, *receipt, bins)
+	if err != nil {
+		return err
+	}
+
+	if output == "" {
+		_, err = os.Stdout.Write(manifest)
+		return err
+	}
+	if err := os.WriteFile(output, manifest, 0644); err != nil {
+		return fmt.Errorf("failed to write manifest: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,81 @@
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/pyhub-kr/pyhub-installer/internal/exitcode"
+	"github.com/pyhub-kr/pyhub-installer/internal/i18n"
+	"github.com/pyhub-kr/pyhub-installer/internal/platform"
+	"github.com/pyhub-kr/pyhub-installer/internal/state"
+	"github.com/pyhub-kr/pyhub-installer/internal/toolset"
+	"github.com/spf13/cobra"
+)
+
+var freezeCmd = &cobra.Command{
+	Use:   "freeze",
+	Short: "Write a manifest and lockfile of the installed tools",
+	Long: `Describe every installed tool as a project manifest pinning its installed
+version, for sync on another machine. With --file the manifest is written to a
+file and a lockfile next to it (pyhub-tools.lock for pyhub-tools.yaml) pins the
+installed assets and their checksums; otherwise the manifest is printed.
+
+Examples:
+  pyhub-installer freeze
+  pyhub-installer freeze --file pyhub-tools.yaml
+  pyhub-installer sync --file pyhub-tools.yaml   # on the other machine`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		if err := runFreeze(cmd, args); err != nil {
+			fmt.Fprint(os.Stderr, i18n.T("Error: %v\n", err))
+			os.Exit(exitcode.Code(err))
+		}
+	},
+}
+
+func init() {
+	freezeCmd.Flags().StringP("file", "f", "", "Write the manifest and its lockfile instead of printing the manifest")
+
+	rootCmd.AddCommand(freezeCmd)
+}
+
+// runFreeze implements the freeze command
+func runFreeze(cmd *cobra.Command, args []string) error {
+	path, _ := cmd.Flags().GetString("file")
+
+	db, err := state.DefaultDB()
+	if err != nil {
+		return err
+	}
+	installed, err := db.Load()
+	if err != nil {
+		return err
+	}
+
+	file, lockfile := toolset.Freeze(installed.Receipts, platform.Native())
+	if len(file.Tools) == 0 {
+		return exitcode.Wrap(exitcode.NotFound, fmt.Errorf("no installed tools to freeze"))
+	}
+	manifest, err := file.Marshal()
+	if err != nil {
+		return err
+	}
+	if path == "" {
+		_, err = os.Stdout.Write(manifest)
+		return err
+	}
+
+	lockData, err := lockfile.Marshal()
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(path, manifest, 0644); err != nil {
+		return fmt.Errorf("failed to write manifest: %w", err)
+	}
+	lockPath := toolset.LockPath(path)
+	if err := os.WriteFile(lockPath, lockData, 0644); err != nil {
+		return fmt.Errorf("failed to write lockfile: %w", err)
+	}
+	fmt.Print(i18n.T("Froze %d tools to %s and %s\n", len(file.Tools), path, lockPath))
+	return nil
+}
@@ -0,0 +1,116 @@
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/pyhub-kr/pyhub-installer/internal/alias"
+	"github.com/pyhub-kr/pyhub-installer/internal/brew"
+	"github.com/pyhub-kr/pyhub-installer/internal/exitcode"
+	"github.com/pyhub-kr/pyhub-installer/internal/i18n"
+	"github.com/pyhub-kr/pyhub-installer/internal/profile"
+	"github.com/pyhub-kr/pyhub-installer/internal/ui"
+	"github.com/pyhub-kr/pyhub-installer/pkg/config"
+	"github.com/spf13/cobra"
+)
+
+var importCmd = &cobra.Command{
+	Use:   "import --brewfile FILE",
+	Short: "Install the GitHub releases of the tools in a Brewfile",
+	Long: `Read the brew and cask entries of a Brewfile, map them to GitHub repositories
+with the tool aliases (formula names such as ripgrep and git-delta are known), and
+install the latest release of each. Entries without an alias are listed and skipped;
+add them to aliases.json to include them.
+
+Examples:
+  pyhub-installer import --brewfile Brewfile --dry-run
+  pyhub-installer import --brewfile ~/Brewfile --output ~/.local/bin`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		if err := runImport(cmd, args); err != nil {
+			fmt.Fprint(os.Stderr, i18n.T("Error: %v\n", err))
+			os.Exit(exitcode.Code(err))
+		}
+	},
+}
+
+func init() {
+	importCmd.Flags().String("brewfile", "", "Brewfile to import")
+	importCmd.Flags().StringP("output", "o", getDefaultInstallPath(), "Installation directory")
+	importCmd.Flags().String("platform", "", "Target platform (auto-detect if empty)")
+	importCmd.Flags().IntP("jobs", "j", 4, "Number of tools to resolve and download concurrently")
+	importCmd.Flags().Bool("dry-run", false, "Show how the entries map to sources without installing")
+	importCmd.MarkFlagRequired("brewfile")
+
+	rootCmd.AddCommand(importCmd)
+}
+
+// runImport implements the import command
+func runImport(cmd *cobra.Command, args []string) error {
+	defer finishProfile(cmd)
+	path, _ := cmd.Flags().GetString("brewfile")
+	output, _ := cmd.Flags().GetString("output")
+	platform, _ := cmd.Flags().GetString("platform")
+	jobs, _ := cmd.Flags().GetInt("jobs")
+	dryRun, _ := cmd.Flags().GetBool("dry-run")
+
+	entries, err := brew.LoadBrewfile(path)
+	if err != nil {
+		return err
+	}
+	registry, err := alias.Load()
+	if err != nil {
+		return err
+	}
+
+	var targets []*installJob
+	seen := make(map[string]bool)
+	skipped := 0
+	for _, entry := range entries {
+		source, ok := registry.LookupBrew(entry.ShortName())
+		if !ok {
+			fmt.Print(i18n.T("Skipping %s %s: no known GitHub repository\n", entry.Kind, entry.Name))
+			skipped++
+			continue
+		}
+		fmt.Printf("%s %s → %s\n", entry.Kind, entry.Name, source)
+		if !seen[source] {
+			seen[source] = true
+			targets = append(targets, &installJob{Input: source, Version: "latest"})
+		}
+	}
+	if len(targets) == 0 {
+		return exitcode.Wrap(exitcode.NotFound, fmt.Errorf("no entries of %s map to a GitHub repository", path))
+	}
+	if dryRun {
+		fmt.Print(i18n.T("Would install %d tools; %d entries skipped\n", len(targets), skipped))
+		return nil
+	}
+	fmt.Println()
+
+	cfg, err := config.Load()
+	if err != nil {
+		return err
+	}
+	output, dirLock, err := prepareOutput(output, false)
+	if err != nil {
+		return err
+	}
+	defer dirLock.Release()
+
+	opts := installOptions{
+		Version:  "latest",
+		Platform: platform,
+		Output:   output,
+		Jobs:     jobs,
+		Config:   cfg,
+	}
+	if err := installJobs(profile.WithProfiler(cmd.Context(), profiler), targets, opts); err != nil {
+		return err
+	}
+	fmt.Print(i18n.T("%s Installation completed to: %s\n", ui.Success("✓"), output))
+	if skipped > 0 {
+		fmt.Print(i18n.T("Note: %d Brewfile entries were skipped; add aliases for them to import them\n", skipped))
+	}
+	return nil
+}
@@ -0,0 +1,259 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/pyhub-kr/pyhub-installer/internal/clean"
+	"github.com/pyhub-kr/pyhub-installer/internal/exitcode"
+	"github.com/pyhub-kr/pyhub-installer/internal/github"
+	"github.com/pyhub-kr/pyhub-installer/internal/i18n"
+	"github.com/pyhub-kr/pyhub-installer/internal/platform"
+	"github.com/pyhub-kr/pyhub-installer/internal/provider"
+	"github.com/pyhub-kr/pyhub-installer/internal/state"
+	"github.com/pyhub-kr/pyhub-installer/pkg/config"
+	"github.com/spf13/cobra"
+)
+
+var infoCmd = &cobra.Command{
+	Use:   "info SOURCE[@VERSION]",
+	Short: "Show a release, its assets per platform and whether it is installed",
+	Long: `Show what an install would get before installing: the release a source resolves
+to and a summary of its notes, the asset selected for each platform and how it can
+be verified, and the version installed locally, if any.
+
+Examples:
+  pyhub-installer info cli/cli
+  pyhub-installer info github:cli/cli@v2.40.0
+  pyhub-installer info github:cli/cli --version v2.40.0 --notes
+  pyhub-installer info rg --json`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		if err := runInfo(cmd, args); err != nil {
+			fmt.Fprint(os.Stderr, i18n.T("Error: %v\n", err))
+			os.Exit(exitcode.Code(err))
+		}
+	},
+}
+
+// releaseDetails is the JSON representation of the info command's output
+type releaseDetails struct {
+	Source      string          `json:"source"`
+	Tag         string          `json:"tag"`
+	Name        string          `json:"name"`
+	PublishedAt time.Time       `json:"published_at"`
+	Prerelease  bool            `json:"prerelease"`
+	Draft       bool            `json:"draft"`
+	URL         string          `json:"url,omitempty"`
+	Notes       string          `json:"notes,omitempty"`
+	Platforms   []platformAsset `json:"platforms"`
+	Assets      []assetDetails  `json:"assets"`
+	Installed   *state.Receipt  `json:"installed,omitempty"`
+}
+
+// platformAsset is the asset an install selects for a platform
+type platformAsset struct {
+	Platform     string `json:"platform"`
+	Asset        string `json:"asset,omitempty"` // Empty if no asset matches
+	Verification string `json:"verification,omitempty"`
+}
+
+// assetDetails describes a release asset
+type assetDetails struct {
+	Name   string `json:"name"`
+	Size   int64  `json:"size"`
+	Digest string `json:"digest,omitempty"`
+	// Selected marks the asset an install selects for this machine's platform
+	Selected bool `json:"selected,omitempty"`
+}
+
+// notesSummaryLines is how many lines of release notes info shows without --notes
+const notesSummaryLines = 5
+
+func init() {
+	infoCmd.Flags().String("version", "", "Release to show: a tag or semver constraint (default: latest)")
+	infoCmd.Flags().Bool("notes", false, "Show the full release notes instead of a summary")
+	infoCmd.Flags().Bool("json", false, "Output as JSON")
+
+	rootCmd.AddCommand(infoCmd)
+}
+
+// runInfo implements the info command
+func runInfo(cmd *cobra.Command, args []string) error {
+	version, _ := cmd.Flags().GetString("version")
+	fullNotes, _ := cmd.Flags().GetBool("notes")
+	jsonOutput, _ := cmd.Flags().GetBool("json")
+
+	cfg, err := config.Load()
+	if err != nil {
+		return err
+	}
+	target := parseTarget(args[0], "latest")
+	if version != "" {
+		if target.Version != "latest" {
+			return exitcode.Wrap(exitcode.Usage, fmt.Errorf("give the version either as SOURCE@VERSION or with --version, not both"))
+		}
+		target.Version = version
+	}
+	_, src, err := parseSource(target.Input)
+	if err != nil {
+		return err
+	}
+	release, err := resolveManifestRelease(cmd.Context(), cfg, target.Input, target.Version)
+	if err != nil {
+		return err
+	}
+
+	details := releaseDetails{
+		Source:      src.String(),
+		Tag:         release.TagName,
+		Name:        release.Name,
+		PublishedAt: release.PublishedAt,
+		Prerelease:  release.Prerelease,
+		Draft:       release.Draft,
+		URL:         release.HTMLURL,
+		Notes:       release.Body,
+		Platforms:   platformAssets(release, assetScoring(cfg)),
+	}
+	selected, _ := selectPlatformAsset(release, platform.Native(), assetScoring(cfg))
+	for _, asset := range release.Assets {
+		details.Assets = append(details.Assets, assetDetails{
+			Name:     asset.Name,
+			Size:     asset.Size,
+			Digest:   asset.Digest,
+			Selected: selected != nil && asset.Name == selected.Name,
+		})
+	}
+	if installed := loadState(); installed != nil {
+		if receipt := installed.Get(src.Name()); receipt != nil && receipt.Source == src.String() {
+			details.Installed = receipt
+		}
+	}
+
+	if jsonOutput {
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(details)
+	}
+	printReleaseDetails(details, release, fullNotes)
+	return nil
+}
+
+// platformAssets returns the asset selected for every known platform, sorted by platform
+func platformAssets(release *provider.Release, scoring *github.Scoring) []platformAsset {
+	targets := make([]string, 0, len(scoring.Platforms))
+	for target := range scoring.Platforms {
+		targets = append(targets, target)
+	}
+	sort.Strings(targets)
+
+	var result []platformAsset
+	for _, target := range targets {
+		entry := platformAsset{Platform: target}
+		if asset, err := selectPlatformAsset(release, target, scoring); err == nil {
+			entry.Asset = asset.Name
+			entry.Verification = verification(release, asset)
+		}
+		result = append(result, entry)
+	}
+	return result
+}
+
+// verification describes how an install verifies an asset: with the checksum published
+// by the provider, a signature or checksum file of the release, or not at all
+func verification(release *provider.Release, asset *provider.Asset) string {
+	if strings.HasPrefix(asset.Digest, "sha256:") {
+		return "published sha256 digest"
+	}
+	if sig, err := release.FindSignatureAsset(asset.Name); err == nil {
+		return sig.Name
+	}
+	return "none"
+}
+
+// printReleaseDetails prints the info command's output, with the release notes in
+// full or summarized
+func printReleaseDetails(d releaseDetails, release *provider.Release, fullNotes bool) {
+	fmt.Printf("%s %s (%s", d.Source, d.Tag, releaseType(releaseInfo{Prerelease: d.Prerelease, Draft: d.Draft}))
+	if !d.PublishedAt.IsZero() {
+		fmt.Printf(", published %s", d.PublishedAt.Local().Format("2006-01-02"))
+	}
+	fmt.Println(")")
+	if d.Name != "" && d.Name != d.Tag {
+		fmt.Print(i18n.T("Name: %s\n", d.Name))
+	}
+	if d.URL != "" {
+		fmt.Print(i18n.T("URL: %s\n", d.URL))
+	}
+
+	switch {
+	case d.Installed == nil:
+		fmt.Println(i18n.T("Installed: no"))
+	case d.Installed.Version == d.Tag:
+		fmt.Print(i18n.T("Installed: %s in %s (up to date)\n", d.Installed.Version, d.Installed.InstallPath))
+	default:
+		fmt.Print(i18n.T("Installed: %s in %s\n", d.Installed.Version, d.Installed.InstallPath))
+	}
+
+	printReleaseNotes(d, release, fullNotes)
+
+	fmt.Println()
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "PLATFORM\tASSET\tVERIFICATION")
+	current := platform.Native()
+	for _, p := range d.Platforms {
+		name := p.Platform
+		if name == current {
+			name += " *"
+		}
+		if p.Asset == "" {
+			fmt.Fprintf(w, "%s\t-\t-\n", name)
+			continue
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\n", name, p.Asset, p.Verification)
+	}
+	w.Flush()
+
+	fmt.Println()
+	fmt.Print(i18n.T("Assets (%d):\n", len(d.Assets)))
+	w = tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	for _, asset := range d.Assets {
+		size := "-"
+		if asset.Size > 0 {
+			size = clean.FormatSize(asset.Size)
+		}
+		if asset.Selected {
+			fmt.Fprintf(w, "  %s\t%s\t%s\n", asset.Name, size, i18n.T("<- selected for %s", current))
+			continue
+		}
+		fmt.Fprintf(w, "  %s\t%s\n", asset.Name, size)
+	}
+	w.Flush()
+}
+
+// printReleaseNotes prints the release notes, or their first lines unless fullNotes
+func printReleaseNotes(d releaseDetails, release *provider.Release, fullNotes bool) {
+	if strings.TrimSpace(d.Notes) == "" {
+		return
+	}
+	fmt.Println()
+	fmt.Println(i18n.T("Release notes:"))
+	if fullNotes {
+		for _, line := range strings.Split(strings.TrimSpace(d.Notes), "\n") {
+			fmt.Printf("  %s\n", strings.TrimRight(line, "\r"))
+		}
+		return
+	}
+	lines, truncated := release.NotesSummary(notesSummaryLines)
+	for _, line := range lines {
+		fmt.Printf("  %s\n", line)
+	}
+	if truncated {
+		fmt.Print(i18n.T("  ... (show all with --notes)\n"))
+	}
+}
@@ -0,0 +1,805 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pyhub-kr/pyhub-installer/internal/alias"
+	"github.com/pyhub-kr/pyhub-installer/internal/cacheproxy"
+	"github.com/pyhub-kr/pyhub-installer/internal/download"
+	"github.com/pyhub-kr/pyhub-installer/internal/exitcode"
+	"github.com/pyhub-kr/pyhub-installer/internal/extract"
+	"github.com/pyhub-kr/pyhub-installer/internal/github"
+	"github.com/pyhub-kr/pyhub-installer/internal/hooks"
+	"github.com/pyhub-kr/pyhub-installer/internal/i18n"
+	"github.com/pyhub-kr/pyhub-installer/internal/install"
+	"github.com/pyhub-kr/pyhub-installer/internal/lifecycle"
+	"github.com/pyhub-kr/pyhub-installer/internal/profile"
+	"github.com/pyhub-kr/pyhub-installer/internal/prompt"
+	"github.com/pyhub-kr/pyhub-installer/internal/provider"
+	"github.com/pyhub-kr/pyhub-installer/internal/state"
+	"github.com/pyhub-kr/pyhub-installer/internal/tempdir"
+	"github.com/pyhub-kr/pyhub-installer/internal/ui"
+	"github.com/pyhub-kr/pyhub-installer/internal/verify"
+	"github.com/pyhub-kr/pyhub-installer/pkg/config"
+)
+
+// installOptions are the install settings shared by all targets of one command
+type installOptions struct {
+	Version          string
+	Platform         string
+	AssetPattern     string
+	Output           string
+	Channel          string
+	IncludeDrafts    bool
+	Interactive      bool
+	Explain          bool                  // Print the asset scores and why the asset was chosen
+	Run              *provider.RunSelector // Install CI artifacts instead of a release
+	SkipCurrent      bool                  // Skip tools whose resolved release is already installed
+	RefuseDeprecated bool                  // Fail targets whose release is deprecated, yanked or EOL
+	SkipReceipt      bool                  // Don't record the installs, e.g. Windows copies installed from WSL
+	StoreLayout      bool                  // Unpack into the store and link into Output (layout "store")
+	KeepVersions     bool                  // Install executables under version-suffixed names
+	BuildFromSource  bool                  // Build Go projects from source when no asset matches
+	Mirrors          []string              // Mirror templates of --mirror, tried before the configured ones
+	Jobs             int
+	Config           *config.Config
+}
+
+// hooks returns the runner of the configured hooks, or nil without a config
+func (o installOptions) hooks() *hooks.Runner {
+	if o.Config == nil {
+		return nil
+	}
+	return hooks.NewRunner(o.Config.Hooks)
+}
+
+// installJob tracks one target through the install pipeline
+type installJob struct {
+	Input           string // Source as given, e.g. "rg" or "github:cli/cli"
+	Version         string
+	Output          string           // Install directory, overriding the shared option
+	AssetPattern    string           // Asset name pattern, overriding the shared option
+	Deprecations    []lifecycle.Rule // Version statuses declared by the project manifest
+	SHA256          string           // Checksum the downloaded asset must have, from a lockfile
+	StoreLayout     bool             // Install into the store, overriding the shared option
+	Channel         string           // Release channel, overriding the shared option
+	BuildFromSource bool             // Build from source, overriding the shared option
+
+	group       *installJob // First job of a source installing several assets of one release
+	chained     bool        // Whether the target has a configured source chain
+	fallbacks   []string    // Sources left to try if the current one fails
+	prov        provider.ReleaseProvider
+	src         provider.Source
+	release     *provider.Release
+	asset       *provider.Asset
+	selection   assetSelection
+	archivePath string
+	current     bool // Already installed, nothing to do
+	err         error
+}
+
+// parseTarget splits "SOURCE@VERSION" into a job, using defaultVersion without a suffix
+func parseTarget(arg, defaultVersion string) *installJob {
+	if i := strings.LastIndex(arg, "@"); i > 0 && !strings.Contains(arg[i:], "/") {
+		return &installJob{Input: arg[:i], Version: arg[i+1:]}
+	}
+	return &installJob{Input: arg, Version: defaultVersion}
+}
+
+// readSourceArgs replaces a "-" argument with the sources read from r, one per line,
+// e.g. piped from search --quiet. Blank lines and lines starting with # are skipped.
+func readSourceArgs(args []string, r io.Reader) ([]string, error) {
+	var sources []string
+	for _, arg := range args {
+		if arg != "-" {
+			sources = append(sources, arg)
+			continue
+		}
+		scanner := bufio.NewScanner(r)
+		for scanner.Scan() {
+			line := strings.TrimSpace(scanner.Text())
+			if line != "" && !strings.HasPrefix(line, "#") {
+				sources = append(sources, line)
+			}
+		}
+		if err := scanner.Err(); err != nil {
+			return nil, fmt.Errorf("failed to read sources: %w", err)
+		}
+	}
+	if len(sources) == 0 {
+		return nil, exitcode.Wrap(exitcode.Usage, fmt.Errorf("no sources to install"))
+	}
+	return sources, nil
+}
+
+// options returns the install options of the job: the shared ones with the job's own
+// install directory, asset pattern, layout and channel, if set
+func (j *installJob) options(opts installOptions) installOptions {
+	if j.Output != "" {
+		opts.Output = j.Output
+	}
+	if j.AssetPattern != "" {
+		opts.AssetPattern = j.AssetPattern
+	}
+	if j.StoreLayout {
+		opts.StoreLayout = true
+	}
+	if j.Channel != "" {
+		opts.Channel = j.Channel
+	}
+	if j.BuildFromSource {
+		opts.BuildFromSource = true
+	}
+	return opts
+}
+
+// name returns the best available display name of the job
+func (j *installJob) name() string {
+	name := j.Input
+	if j.src.Path != "" {
+		name = j.src.String()
+	}
+	if j.group != nil {
+		name = fmt.Sprintf("%s [%s]", name, j.AssetPattern)
+	}
+	return name
+}
+
+// companion reports whether the job installs another asset of the release resolved
+// by the first job of its group
+func (j *installJob) companion() bool {
+	return j.group != nil && j.group != j
+}
+
+// resolveCompanion selects the job's asset from the release of its group
+func (j *installJob) resolveCompanion(ctx context.Context, opts installOptions) error {
+	if j.group.err != nil {
+		return fmt.Errorf("skipped: the release of %s could not be resolved", j.group.Input)
+	}
+	j.prov, j.src, j.release = j.group.prov, j.group.src, j.group.release
+	return j.selectAsset(ctx, opts)
+}
+
+// resolve finds the release and asset to install, trying the configured fallback
+// sources of the target in order
+func (j *installJob) resolve(ctx context.Context, opts installOptions) error {
+	chain := sourceChain(opts.Config, j.Input)
+	j.chained = len(chain) > 1
+	return j.resolveChain(ctx, opts, chain)
+}
+
+// resolveChain resolves the first source of chain that works and keeps the rest as fallbacks
+func (j *installJob) resolveChain(ctx context.Context, opts installOptions, chain []string) error {
+	var err error
+	for i, input := range chain {
+		if err = j.resolveSource(ctx, opts, input); err == nil {
+			j.fallbacks = chain[i+1:]
+			return nil
+		}
+		if i < len(chain)-1 {
+			fmt.Print(i18n.T("Warning: %s: %v; trying %s\n", input, err, chain[i+1]))
+		}
+	}
+	return err
+}
+
+// sourceChain returns the sources to try for a target: the chain configured for the
+// target as given or for the source it resolves to, otherwise just the target
+func sourceChain(cfg *config.Config, input string) []string {
+	if cfg == nil {
+		return []string{input}
+	}
+	if sources := cfg.SourcesFor(input); len(sources) > 0 {
+		return sources
+	}
+	if registry, err := alias.Load(); err == nil {
+		if resolved, err := registry.Resolve(input); err == nil {
+			if sources := cfg.SourcesFor(resolved); len(sources) > 0 {
+				return sources
+			}
+		}
+	}
+	return []string{input}
+}
+
+// resolveSource finds the release and asset to install from one source
+func (j *installJob) resolveSource(ctx context.Context, opts installOptions, input string) error {
+	prov, src, err := parseSource(input)
+	if err != nil {
+		return fmt.Errorf("invalid repository: %w", err)
+	}
+	j.prov, j.src = prov, src
+
+	if opts.IncludeDrafts {
+		draftProv, ok := prov.(provider.DraftProvider)
+		if !ok {
+			return fmt.Errorf("--include-drafts is not supported for %s sources", src.Scheme)
+		}
+		if err := draftProv.SetIncludeDrafts(true); err != nil {
+			return err
+		}
+	}
+
+	if opts.Run != nil {
+		runProv, ok := prov.(provider.RunProvider)
+		if !ok {
+			return fmt.Errorf("workflow artifacts are not supported for %s sources", src.Scheme)
+		}
+		if err := runProv.SetWorkflowRun(*opts.Run); err != nil {
+			return err
+		}
+		j.release, err = prov.Resolve(ctx, src, "latest")
+		if err != nil {
+			return fmt.Errorf("failed to get workflow run: %w", err)
+		}
+	} else {
+		latest, err := latestPolicy(opts.Config, src)
+		if err != nil {
+			return err
+		}
+
+		j.release, err = resolveRelease(ctx, prov, src, j.Version, opts.Channel, latest)
+		if errors.Is(err, provider.ErrNotFound) {
+			return exitcode.Wrap(exitcode.NotFound, fmt.Errorf("failed to get release: %w", err))
+		}
+		if err != nil {
+			return fmt.Errorf("failed to get release: %w", err)
+		}
+	}
+
+	j.release.Assets, err = prov.Assets(ctx, src, j.release)
+	if err != nil {
+		return fmt.Errorf("failed to list assets: %w", err)
+	}
+	return j.selectAsset(ctx, opts)
+}
+
+// selectAsset picks the asset to install from the resolved release
+func (j *installJob) selectAsset(ctx context.Context, opts installOptions) error {
+	prov, src := j.prov, j.src
+	var err error
+
+	// Find asset for platform, unless one was selected explicitly
+	if len(j.release.Assets) == 1 && github.IsSourceArchive(&j.release.Assets[0]) {
+		j.asset = &j.release.Assets[0]
+		if opts.BuildFromSource {
+			j.selection = selectedSourceBuild
+		} else {
+			j.selection = selectedSourceArchive
+			fmt.Print(i18n.T("Note: %s %s has no release assets, installing its source archive\n", src, j.release.TagName))
+		}
+	} else if opts.AssetPattern != "" {
+		j.asset, err = j.release.FindAssetByPattern(opts.AssetPattern)
+		j.selection = selectedByPattern
+	} else {
+		scoring := assetScoring(opts.Config)
+		j.asset, err = selectPlatformAsset(j.release, opts.Platform, scoring)
+		// Without a matching asset, build from source if asked, or else let a terminal
+		// user pick one from the list instead
+		interactive := opts.Interactive || (err != nil && prompt.IsInteractive())
+		if err != nil && opts.BuildFromSource {
+			fmt.Print(i18n.T("Note: no asset of %s %s matches the platform, building it from source\n", src, j.release.TagName))
+			j.asset, err = sourceBuildAsset(ctx, prov, src, j.release)
+			j.selection = selectedSourceBuild
+		} else if err == nil || interactive {
+			picked := j.asset
+			label := fmt.Sprintf("%s %s", src, j.release.TagName)
+			j.asset, err = chooseAsset(j.release, j.asset, opts.Platform, scoring, interactive, label)
+			if err == nil && (picked == nil || j.asset.Name != picked.Name) {
+				j.selection = selectedByUser
+			}
+		}
+	}
+	if err != nil {
+		if _, ok := prov.(provider.SourceArchiver); ok && !opts.BuildFromSource && opts.AssetPattern == "" {
+			err = fmt.Errorf("%w; Go projects can be built with --build-from-source", err)
+		}
+		return exitcode.Wrap(exitcode.NotFound, fmt.Errorf("failed to find asset: %w", err))
+	}
+
+	if mirrorProv, ok := prov.(provider.MirrorProvider); ok && len(downloadMirrors(opts)) > 0 {
+		mirrorProv.SetMirrors(downloadMirrors(opts))
+		if j.asset.Digest == "" {
+			j.asset.Digest = releaseChecksum(ctx, prov, j.release, j.asset)
+		}
+	}
+	return nil
+}
+
+// downloadMirrors returns the mirror templates to try before GitHub: those of --mirror,
+// the team cache server, then the configured mirrors
+func downloadMirrors(opts installOptions) []string {
+	mirrors := slices.Clone(opts.Mirrors)
+	if cacheServer != "" {
+		mirrors = append(mirrors, cacheproxy.MirrorTemplate(cacheServer))
+	}
+	if opts.Config != nil {
+		mirrors = append(mirrors, opts.Config.Mirrors...)
+	}
+	return mirrors
+}
+
+// releaseChecksum returns the digest of an asset from the release's checksum file,
+// fetched from the original source, or "" if the release publishes none
+func releaseChecksum(ctx context.Context, prov provider.ReleaseProvider, release *provider.Release, asset *provider.Asset) string {
+	sigAsset, err := release.FindSignatureAsset(asset.Name)
+	if err != nil {
+		return ""
+	}
+	sigURL, sigHeaders, err := provider.AssetRequest(ctx, prov, sigAsset)
+	if err != nil {
+		return ""
+	}
+
+	verifier := verify.NewVerifier("")
+	verifier.Headers = sigHeaders
+	checksum, err := verifier.FetchChecksum(ctx, sigURL, asset.Name)
+	if err != nil {
+		return ""
+	}
+	return "sha256:" + checksum
+}
+
+// download fetches the selected asset into the output directory. If the download
+// fails, the remaining fallback sources are resolved and tried in turn.
+func (j *installJob) download(ctx context.Context, opts installOptions) error {
+	for {
+		j.archivePath = filepath.Join(opts.Output, j.asset.Name)
+		if err := opts.hooks().Run(ctx, hooks.PreDownload, j.hookContext(opts.Output)); err != nil {
+			return err
+		}
+		err := j.prov.Download(ctx, j.asset, j.archivePath)
+		if err == nil {
+			return nil
+		}
+		if ctx.Err() != nil {
+			return exitcode.Wrap(exitcode.Interrupted, fmt.Errorf("download of %s interrupted; run the install again to resume it", j.asset.Name))
+		}
+		if len(j.fallbacks) == 0 {
+			if errors.Is(err, provider.ErrNotFound) {
+				return exitcode.Wrap(exitcode.NotFound, fmt.Errorf("download failed: %w", err))
+			}
+			return exitcode.Wrap(exitcode.Network, fmt.Errorf("download failed: %w", err))
+		}
+
+		os.Remove(j.archivePath)
+		fmt.Print(i18n.T("Warning: download from %s failed: %v; trying %s\n", j.src, err, j.fallbacks[0]))
+		if err := j.resolveChain(ctx, opts, j.fallbacks); err != nil {
+			return err
+		}
+		fmt.Print(i18n.T("Found asset: %s %s (%d bytes)\n", j.src, j.asset.Name, j.asset.Size))
+	}
+}
+
+// hookContext describes the job to hook commands
+func (j *installJob) hookContext(output string) hooks.Context {
+	return hooks.Context{
+		Tool:        j.src.Name(),
+		Source:      j.src.String(),
+		Version:     j.release.TagName,
+		Asset:       j.asset.Name,
+		URL:         j.asset.BrowserDownloadURL,
+		File:        j.archivePath,
+		InstallPath: output,
+	}
+}
+
+// finish verifies and unpacks the downloaded asset and records the installation
+func (j *installJob) finish(ctx context.Context, opts installOptions) {
+	output := opts.Output
+	endVerify := profile.Start(ctx, profile.Verify, j.Input)
+	if j.SHA256 != "" {
+		fmt.Println(i18n.T("Verifying locked checksum..."))
+		if err := verify.NewVerifier(j.archivePath).VerifyWithString(j.SHA256); err != nil {
+			endVerify()
+			os.Remove(j.archivePath)
+			j.err = exitcode.Wrap(exitcode.Verification, fmt.Errorf("%s does not match the lockfile: %w", j.asset.Name, err))
+			return
+		}
+	}
+	// Prefer the checksum published with the asset, which needs no further download,
+	// then try to find and verify a signature or checksum file
+	if expected, ok := strings.CutPrefix(j.asset.Digest, "sha256:"); ok {
+		fmt.Println(i18n.T("Verifying published checksum..."))
+		if err := verify.NewVerifier(j.archivePath).VerifyWithString(expected); err != nil {
+			endVerify()
+			os.Remove(j.archivePath)
+			j.err = exitcode.Wrap(exitcode.Verification, fmt.Errorf("%s does not match its published checksum: %w", j.asset.Name, err))
+			return
+		}
+	} else if sigAsset, err := j.release.FindSignatureAsset(j.asset.Name); err == nil {
+		fmt.Println(i18n.T("Found signature file, verifying..."))
+		verifier := verify.NewVerifier(j.archivePath)
+		sigURL, sigHeaders, err := provider.AssetRequest(ctx, j.prov, sigAsset)
+		if err == nil {
+			verifier.Headers = sigHeaders
+			err = verifier.VerifyWithURL(ctx, sigURL)
+		}
+		if err != nil {
+			fmt.Print(i18n.T("Warning: signature verification failed: %v\n", err))
+		}
+	} else {
+		fmt.Println(i18n.T("No signature file found, skipping verification"))
+	}
+	err := opts.hooks().Run(ctx, hooks.PostVerify, j.hookContext(output))
+	endVerify()
+	if err != nil {
+		os.Remove(j.archivePath)
+		j.err = exitcode.Wrap(exitcode.Verification, err)
+		return
+	}
+
+	// Build a source archive into the executable to install in place of the download
+	endExtract := profile.Start(ctx, profile.Extract, j.Input)
+	if j.selection == selectedSourceBuild {
+		binary, cleanup, err := buildFromSource(ctx, j.archivePath, j.src.Name(), opts.Platform)
+		os.Remove(j.archivePath)
+		if err != nil {
+			endExtract()
+			j.err = err
+			return
+		}
+		defer cleanup()
+		j.archivePath = binary
+	}
+
+	// Let providers with their own layout install the asset, install only the listed
+	// binaries when the provider names them, otherwise extract if it's an archive
+	var files []string
+	if installer, ok := j.prov.(provider.AssetInstaller); ok && j.selection != selectedSourceBuild {
+		files, err = installer.Install(ctx, j.release, j.asset, j.archivePath, output)
+		if err != nil {
+			endExtract()
+			j.err = err
+			return
+		}
+	} else if opts.StoreLayout {
+		files, err = installToStore(ctx, j.archivePath, j.asset, j.src.Name(), j.release.TagName, output)
+		if err != nil {
+			endExtract()
+			j.err = err
+			return
+		}
+	} else if opts.KeepVersions {
+		files, err = installVersioned(ctx, j.archivePath, j.asset, j.src.Name(), j.release.TagName, output)
+		if err != nil {
+			endExtract()
+			j.err = err
+			return
+		}
+	} else if j.selection == selectedSourceBuild {
+		files, err = installBuilt(j.archivePath, output)
+		if err != nil {
+			endExtract()
+			j.err = err
+			return
+		}
+	} else if len(j.asset.Binaries) > 0 {
+		files, err = installBinaries(ctx, j.archivePath, output, j.asset.Binaries)
+		if err != nil {
+			endExtract()
+			j.err = err
+			return
+		}
+	} else {
+		extractor := extract.NewExtractor(j.archivePath, output)
+		if err := extractor.ExtractContext(ctx); err != nil {
+			if ctx.Err() != nil {
+				// Interrupted: the extracted files were removed, so remove the download too
+				endExtract()
+				os.Remove(j.archivePath)
+				j.err = err
+				return
+			}
+			fmt.Print(i18n.T("Note: Not an archive or extraction failed: %v\n", err))
+		} else {
+			files = extractor.Files()
+			// Set executable permissions for extracted files
+			installer := install.NewInstaller(output, output, "755")
+			if err := installer.InstallDirectory(); err != nil {
+				fmt.Print(i18n.T("Warning: failed to set permissions: %v\n", err))
+			}
+		}
+	}
+
+	endExtract()
+
+	// Record the installation in the state database
+	defer profile.Start(ctx, profile.Install, j.Input)()
+	receipt := state.Receipt{
+		Name:        j.src.Name(),
+		Source:      j.src.String(),
+		Version:     j.release.TagName,
+		Asset:       j.asset.Name,
+		URL:         j.asset.BrowserDownloadURL,
+		InstallPath: output,
+		InstalledAt: time.Now(),
+		Files:       files,
+	}
+	if opts.Channel != provider.ChannelStable {
+		receipt.Channel = opts.Channel
+	}
+	receipt.Built = j.selection == selectedSourceBuild
+	if sum, err := verify.NewVerifier(j.archivePath).GetSHA256(); err == nil {
+		receipt.SHA256 = sum
+	}
+	// The store and versioned names keep the unpacked files, not the download next to them
+	if opts.StoreLayout || opts.KeepVersions {
+		os.Remove(j.archivePath)
+	}
+	// Receipts are per tool name and describe the primary install, not extra copies;
+	// the files of further assets of the release are added to the receipt of the first
+	if !opts.SkipReceipt {
+		record := recordReceipt
+		if j.companion() && j.group.err == nil {
+			record = addReceiptFiles
+		}
+		if err := record(receipt); err != nil {
+			fmt.Print(i18n.T("Warning: failed to record installation: %v\n", err))
+		}
+	}
+
+	if err := opts.hooks().Run(ctx, hooks.PostInstall, j.hookContext(output)); err != nil {
+		fmt.Print(i18n.T("Warning: %v\n", err))
+	}
+}
+
+// installBinaries extracts an archive to a temporary directory and installs the files
+// matching patterns (slash-separated globs relative to the archive root) into output
+func installBinaries(ctx context.Context, archivePath, output string, patterns []string) ([]string, error) {
+	tmpDir, err := tempdir.MkdirTemp("staging-*")
+	if err != nil {
+		return nil, fmt.Errorf("failed to create temporary directory: %w", err)
+	}
+	defer os.RemoveAll(tmpDir)
+
+	if err := extract.NewExtractor(archivePath, tmpDir).ExtractContext(ctx); err != nil {
+		return nil, exitcode.Wrap(exitcode.Extraction, fmt.Errorf("failed to extract archive: %w", err))
+	}
+
+	var installed []string
+	err = filepath.Walk(tmpDir, func(filePath string, info os.FileInfo, err error) error {
+		if err != nil || info.IsDir() {
+			return err
+		}
+		rel, err := filepath.Rel(tmpDir, filePath)
+		if err != nil || !matchesAny(patterns, filepath.ToSlash(rel)) {
+			return err
+		}
+		dest := filepath.Join(output, info.Name())
+		installed = append(installed, dest)
+		return install.NewInstaller(filePath, dest, "755").Install()
+	})
+	if err != nil {
+		return installed, fmt.Errorf("failed to install binaries: %w", err)
+	}
+	if len(installed) == 0 {
+		return nil, fmt.Errorf("no files in %s match %s", filepath.Base(archivePath), strings.Join(patterns, ", "))
+	}
+	return installed, nil
+}
+
+// matchesAny reports whether name matches one of the glob patterns
+func matchesAny(patterns []string, name string) bool {
+	for _, pattern := range patterns {
+		if ok, _ := path.Match(pattern, name); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// forEachJob runs fn for the pending jobs that have not failed, at most limit at a time
+func forEachJob(jobs []*installJob, limit int, fn func(j *installJob) error) {
+	if limit < 1 {
+		limit = 1
+	}
+
+	var wg sync.WaitGroup
+	sem := make(chan struct{}, limit)
+	for _, j := range jobs {
+		if j.err != nil || j.current {
+			continue
+		}
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(j *installJob) {
+			defer wg.Done()
+			defer func() { <-sem }()
+			j.err = fn(j)
+		}(j)
+	}
+	wg.Wait()
+}
+
+// installJobs runs the install pipeline for all jobs: releases are resolved and
+// assets downloaded concurrently, then each asset is verified and unpacked in turn
+func installJobs(ctx context.Context, jobs []*installJob, opts installOptions) error {
+	// Downloads run one at a time in low-memory mode, so resolve one source at a time too
+	if download.LowMemory() {
+		opts.Jobs = 1
+	}
+	multiple := len(jobs) > 1
+
+	forEachJob(jobs, opts.Jobs, func(j *installJob) error {
+		if j.companion() {
+			return nil
+		}
+		defer profile.Start(ctx, profile.Resolve, j.Input)()
+		return j.resolve(ctx, j.options(opts))
+	})
+	// Further assets of a release are selected once the first job of the group resolved it
+	forEachJob(jobs, opts.Jobs, func(j *installJob) error {
+		if !j.companion() {
+			return nil
+		}
+		return j.resolveCompanion(ctx, j.options(opts))
+	})
+
+	var installed *state.State
+	if opts.SkipCurrent {
+		installed = loadState()
+	}
+
+	for _, j := range jobs {
+		fmt.Print(i18n.T("Installing %s...\n", j.name()))
+		if j.err != nil {
+			if multiple {
+				fmt.Printf("%s %v\n", ui.Failure("✗"), j.err)
+			}
+			continue
+		}
+		fmt.Print(i18n.T("Found release: %s\n", j.release.TagName))
+		if rule := j.lifecycleRule(opts.Config); rule != nil {
+			if opts.RefuseDeprecated {
+				j.err = fmt.Errorf("%s %s is %s (refused by --refuse-deprecated)", j.name(), j.release.TagName, rule.Describe())
+				if multiple {
+					fmt.Printf("%s %v\n", ui.Failure("✗"), j.err)
+				}
+				continue
+			}
+			fmt.Print(i18n.T("Warning: %s %s is %s\n", j.name(), j.release.TagName, rule.Describe()))
+		}
+		if j.isCurrent(installed, j.options(opts).Output) {
+			j.current = true
+			fmt.Print(i18n.T("%s %s %s is already installed\n", ui.Success("✓"), j.name(), j.release.TagName))
+			continue
+		}
+		fmt.Print(i18n.T("Found asset: %s (%d bytes)\n", j.asset.Name, j.asset.Size))
+		if opts.Explain {
+			explainAsset(j, j.options(opts))
+		}
+	}
+
+	// Concurrent downloads share one display, a line per file, and a limit on
+	// concurrent requests
+	downloadCtx := ctx
+	progress := ui.NewMultiProgress()
+	if multiple {
+		downloadCtx = download.WithProgressGroup(ctx, progress)
+		connections := config.DefaultConfig().MaxConnections
+		if opts.Config != nil {
+			connections = opts.Config.MaxConnections
+		}
+		downloadCtx = download.WithLimiter(downloadCtx, download.NewLimiter(connections))
+	}
+	forEachJob(jobs, opts.Jobs, func(j *installJob) error {
+		defer profile.Start(ctx, profile.Download, j.Input)()
+		return j.download(downloadCtx, j.options(opts))
+	})
+	if multiple {
+		progress.Finish()
+	}
+
+	for _, j := range jobs {
+		if j.err != nil || j.current {
+			continue
+		}
+		if multiple {
+			fmt.Printf("==> %s %s\n", j.name(), j.release.TagName)
+		}
+		if j.chained {
+			fmt.Print(i18n.T("Served by %s\n", j.src))
+		}
+		if err := ctx.Err(); err != nil {
+			j.err = err
+			continue
+		}
+		j.finish(ctx, j.options(opts))
+	}
+
+	if !multiple {
+		return jobs[0].err
+	}
+	return printInstallSummary(jobs)
+}
+
+// lifecycleRule returns the rule marking the job's release deprecated, yanked or EOL, or nil
+func (j *installJob) lifecycleRule(cfg *config.Config) *lifecycle.Rule {
+	return findLifecycleRule(cfg, j.Deprecations, []string{j.src.String(), j.Input}, j.release.TagName)
+}
+
+// findLifecycleRule returns the rule matching a release tag among those declared by a
+// project manifest, then those configured for the sources, or nil
+func findLifecycleRule(cfg *config.Config, declared []lifecycle.Rule, sources []string, tag string) *lifecycle.Rule {
+	if rule := lifecycle.Find(declared, tag); rule != nil {
+		return rule
+	}
+	if cfg == nil {
+		return nil
+	}
+	for _, source := range sources {
+		if rule := lifecycle.Find(cfg.DeprecationsFor(source), tag); rule != nil {
+			return rule
+		}
+	}
+	return nil
+}
+
+// loadState returns the installed-packages database, or nil if it cannot be read
+func loadState() *state.State {
+	db, err := state.DefaultDB()
+	if err != nil {
+		return nil
+	}
+	s, err := db.Load()
+	if err != nil {
+		fmt.Print(i18n.T("Warning: failed to read installed tools: %v\n", err))
+		return nil
+	}
+	return s
+}
+
+// isCurrent reports whether the job's release is already installed in output
+func (j *installJob) isCurrent(installed *state.State, output string) bool {
+	if installed == nil {
+		return false
+	}
+	receipt := installed.Get(j.src.Name())
+	return receipt != nil && receipt.Source == j.src.String() && receipt.Version == j.release.TagName &&
+		receipt.InstallPath == output
+}
+
+// printInstallSummary reports the outcome of each job and fails if any job failed.
+// The error has the Partial exit code if other jobs succeeded, otherwise the code of
+// the first failure.
+func printInstallSummary(jobs []*installJob) error {
+	failed := 0
+	var firstErr error
+	fmt.Println()
+	fmt.Println(i18n.T("Summary:"))
+	for _, j := range jobs {
+		if j.err != nil {
+			failed++
+			if firstErr == nil {
+				firstErr = j.err
+			}
+			fmt.Printf("  %s %s: %v\n", ui.Failure("✗"), j.name(), j.err)
+			continue
+		}
+		if j.current {
+			fmt.Print(i18n.T("  %s %s %s (already installed)\n", ui.Success("✓"), j.name(), j.release.TagName))
+			continue
+		}
+		fmt.Printf("  %s %s %s\n", ui.Success("✓"), j.name(), j.release.TagName)
+	}
+
+	if failed == len(jobs) {
+		return exitcode.Wrap(exitcode.Code(firstErr), fmt.Errorf("%d of %d installs failed", failed, len(jobs)))
+	}
+	if failed > 0 {
+		return exitcode.Wrap(exitcode.Partial, fmt.Errorf("%d of %d installs failed", failed, len(jobs)))
+	}
+	return nil
+}
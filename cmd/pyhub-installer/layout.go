@@ -0,0 +1,169 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+
+	"github.com/pyhub-kr/pyhub-installer/internal/exitcode"
+	"github.com/pyhub-kr/pyhub-installer/internal/extract"
+	"github.com/pyhub-kr/pyhub-installer/internal/i18n"
+	"github.com/pyhub-kr/pyhub-installer/internal/install"
+	"github.com/pyhub-kr/pyhub-installer/internal/provider"
+	"github.com/pyhub-kr/pyhub-installer/internal/store"
+	"github.com/pyhub-kr/pyhub-installer/internal/tempdir"
+	"github.com/pyhub-kr/pyhub-installer/internal/ui"
+	"github.com/pyhub-kr/pyhub-installer/pkg/config"
+)
+
+// storeLayoutOutput returns the bin directory of the store layout and true if installs
+// use it: when config.json selects the layout and no other directory was chosen, or
+// when the bin directory is chosen explicitly, as by updates of tools installed there
+func storeLayoutOutput(cfg *config.Config, output string, chosen bool) (string, bool, error) {
+	if cfg.Layout != config.LayoutStore {
+		return output, false, nil
+	}
+	binDir, err := config.BinDir()
+	if err != nil {
+		return "", false, err
+	}
+	if chosen && filepath.Clean(output) != binDir {
+		return output, false, nil
+	}
+	return binDir, true, nil
+}
+
+// installToStore unpacks a downloaded asset into <store>/<tool>/<version>, makes the
+// version current and links its executables into binDir. It returns the version
+// directory and the links for the receipt.
+func installToStore(ctx context.Context, archivePath string, asset *provider.Asset, tool, version, binDir string) ([]string, error) {
+	storeDir, err := config.StoreDir()
+	if err != nil {
+		return nil, err
+	}
+	toolDir := filepath.Join(storeDir, tool)
+	versionDir := filepath.Join(toolDir, version)
+
+	// Unpack next to the version directory so it is replaced in one rename
+	tmpDir := filepath.Join(toolDir, ".pyhub-new-"+version)
+	os.RemoveAll(tmpDir)
+	defer os.RemoveAll(tmpDir)
+
+	if err := unpackAsset(ctx, archivePath, asset, tool, tmpDir); err != nil {
+		return nil, err
+	}
+	if err := install.NewInstaller(tmpDir, tmpDir, "755").InstallDirectory(); err != nil {
+		fmt.Print(i18n.T("Warning: failed to set permissions: %v\n", err))
+	}
+
+	if err := os.RemoveAll(versionDir); err != nil {
+		return nil, fmt.Errorf("failed to remove previous install: %w", err)
+	}
+	if err := os.Rename(tmpDir, versionDir); err != nil {
+		return nil, exitcode.Wrap(exitcode.Extraction, fmt.Errorf("failed to move %s into the store: %w", tool, err))
+	}
+	if err := store.SetCurrent(toolDir, version); err != nil {
+		return nil, err
+	}
+	fmt.Print(i18n.T("%s Installed to: %s\n", ui.Success("✓"), versionDir))
+
+	links, conflicts, err := store.Link(binDir, versionDir, storeDir)
+	for _, link := range links {
+		fmt.Print(i18n.T("%s Linked %s\n", ui.Success("✓"), link))
+	}
+	for _, conflict := range conflicts {
+		fmt.Print(i18n.T("Warning: %s exists and is not managed by the store; not linked\n", conflict))
+	}
+	return append([]string{versionDir}, links...), err
+}
+
+// installVersioned installs the executables of a downloaded asset into dir under
+// version-suffixed names, e.g. tool-1.8.0, and points their unversioned names at them.
+// Versions installed before stay callable by their suffixed names. It returns the
+// installed files and links for the receipt.
+func installVersioned(ctx context.Context, archivePath string, asset *provider.Asset, tool, version, dir string) ([]string, error) {
+	staging, err := tempdir.MkdirTemp("versions-*")
+	if err != nil {
+		return nil, fmt.Errorf("failed to create temporary directory: %w", err)
+	}
+	defer os.RemoveAll(staging)
+
+	if err := unpackAsset(ctx, archivePath, asset, tool, staging); err != nil {
+		return nil, err
+	}
+	executables, err := store.Executables(staging)
+	if err == nil && len(executables) == 0 {
+		// Archives made on Windows carry no executable bits
+		if err := install.NewInstaller(staging, staging, "755").InstallDirectory(); err != nil {
+			fmt.Print(i18n.T("Warning: failed to set permissions: %v\n", err))
+		}
+		executables, err = store.Executables(staging)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if len(executables) == 0 {
+		return nil, exitcode.Wrap(exitcode.Extraction, fmt.Errorf("%s contains no executables", asset.Name))
+	}
+
+	var files []string
+	for _, exe := range executables {
+		name := filepath.Base(exe)
+		versioned := install.VersionedName(name, version)
+		if err := install.NewInstaller(exe, filepath.Join(dir, versioned), "755").Install(); err != nil {
+			return files, err
+		}
+		files = append(files, filepath.Join(dir, versioned))
+
+		link, err := install.LinkDefault(dir, name, versioned)
+		if err != nil {
+			fmt.Print(i18n.T("Warning: %v\n", err))
+			continue
+		}
+		fmt.Print(i18n.T("%s Linked %s\n", ui.Success("✓"), link))
+		files = append(files, link)
+	}
+	return files, nil
+}
+
+// unpackAsset puts the files of a downloaded asset into dir: the provider's listed
+// binaries, the flattened contents of an archive with their modes, or the asset itself
+// as an executable named after the tool
+func unpackAsset(ctx context.Context, archivePath string, asset *provider.Asset, tool, dir string) error {
+	switch {
+	case len(asset.Binaries) > 0:
+		if _, err := installBinaries(ctx, archivePath, dir, asset.Binaries); err != nil {
+			return err
+		}
+	case isArchive(archivePath):
+		extractor := extract.NewExtractor(archivePath, dir)
+		extractor.SetAutoFlatten(true)
+		if err := extractor.ExtractContext(ctx); err != nil {
+			return exitcode.Wrap(exitcode.Extraction, fmt.Errorf("failed to extract %s: %w", asset.Name, err))
+		}
+	default:
+		// A single executable, named after the tool
+		name := tool
+		if runtime.GOOS == "windows" || strings.EqualFold(filepath.Ext(asset.Name), ".exe") {
+			name += ".exe"
+		}
+		if err := install.NewInstaller(archivePath, filepath.Join(dir, name), "755").Install(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// isArchive reports whether a file has an extension the extractor unpacks
+func isArchive(path string) bool {
+	name := strings.ToLower(path)
+	for _, ext := range []string{".zip", ".tar", ".gz"} {
+		if strings.HasSuffix(name, ext) {
+			return true
+		}
+	}
+	return false
+}
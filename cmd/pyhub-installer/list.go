@@ -0,0 +1,72 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"text/tabwriter"
+
+	"github.com/pyhub-kr/pyhub-installer/internal/exitcode"
+	"github.com/pyhub-kr/pyhub-installer/internal/i18n"
+	"github.com/pyhub-kr/pyhub-installer/internal/state"
+	"github.com/spf13/cobra"
+)
+
+var listCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List installed tools",
+	Long: `List the tools recorded in the installed-tools database with their version,
+source, install directory and install time.
+
+Examples:
+  pyhub-installer list
+  pyhub-installer list --json`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		if err := runList(cmd, args); err != nil {
+			fmt.Fprint(os.Stderr, i18n.T("Error: %v\n", err))
+			os.Exit(exitcode.Code(err))
+		}
+	},
+}
+
+func init() {
+	listCmd.Flags().Bool("json", false, "Output as JSON")
+
+	rootCmd.AddCommand(listCmd)
+}
+
+// runList implements the list command
+func runList(cmd *cobra.Command, args []string) error {
+	jsonOutput, _ := cmd.Flags().GetBool("json")
+
+	db, err := state.DefaultDB()
+	if err != nil {
+		return err
+	}
+	installed, err := db.Load()
+	if err != nil {
+		return err
+	}
+
+	if jsonOutput {
+		receipts := installed.Receipts
+		if receipts == nil {
+			receipts = []state.Receipt{}
+		}
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(receipts)
+	}
+
+	if len(installed.Receipts) == 0 {
+		fmt.Println(i18n.T("No tools installed"))
+		return nil
+	}
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "NAME\tVERSION\tSOURCE\tPATH\tINSTALLED")
+	for _, receipt := range installed.Receipts {
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n", receipt.Name, receipt.Version, receipt.Source, receipt.InstallPath, receipt.InstalledAt.Local().Format("2006-01-02 15:04"))
+	}
+	return w.Flush()
+}
@@ -2,18 +2,50 @@ package main
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"maps"
+	"net/http"
 	"os"
 	"path/filepath"
 	"runtime"
+	"runtime/debug"
+	"slices"
 	"strings"
+	"time"
 
 	"github.com/spf13/cobra"
+	"github.com/pyhub-kr/pyhub-installer/internal/alias"
+	"github.com/pyhub-kr/pyhub-installer/internal/cacheproxy"
+	"github.com/pyhub-kr/pyhub-installer/internal/clean"
+	"github.com/pyhub-kr/pyhub-installer/internal/credential"
 	"github.com/pyhub-kr/pyhub-installer/internal/download"
+	"github.com/pyhub-kr/pyhub-installer/internal/exitcode"
+	"github.com/pyhub-kr/pyhub-installer/internal/i18n"
 	"github.com/pyhub-kr/pyhub-installer/internal/verify"
 	"github.com/pyhub-kr/pyhub-installer/internal/extract"
-	"github.com/pyhub-kr/pyhub-installer/internal/install"
 	"github.com/pyhub-kr/pyhub-installer/internal/github"
+	"github.com/pyhub-kr/pyhub-installer/internal/hooks"
+	"github.com/pyhub-kr/pyhub-installer/internal/install"
+	"github.com/pyhub-kr/pyhub-installer/internal/platform"
+	"github.com/pyhub-kr/pyhub-installer/internal/profile"
+	"github.com/pyhub-kr/pyhub-installer/internal/prompt"
+	"github.com/pyhub-kr/pyhub-installer/internal/provider"
+	"github.com/pyhub-kr/pyhub-installer/internal/proxyauth"
+	"github.com/pyhub-kr/pyhub-installer/internal/lock"
+	"github.com/pyhub-kr/pyhub-installer/internal/replace"
+	"github.com/pyhub-kr/pyhub-installer/internal/retry"
+	"github.com/pyhub-kr/pyhub-installer/internal/semver"
+	"github.com/pyhub-kr/pyhub-installer/internal/state"
+	"github.com/pyhub-kr/pyhub-installer/internal/sysproxy"
+	"github.com/pyhub-kr/pyhub-installer/internal/tempdir"
+	"github.com/pyhub-kr/pyhub-installer/internal/tlsconfig"
+	"github.com/pyhub-kr/pyhub-installer/internal/ui"
+	"github.com/pyhub-kr/pyhub-installer/internal/update"
+	"github.com/pyhub-kr/pyhub-installer/internal/urltemplate"
+	"github.com/pyhub-kr/pyhub-installer/internal/useragent"
+	"github.com/pyhub-kr/pyhub-installer/internal/wsl"
+	"github.com/pyhub-kr/pyhub-installer/pkg/config"
 )
 
 // Version information set by ldflags
@@ -32,6 +64,62 @@ var rootCmd = &cobra.Command{
 - Extracts ZIP/TAR archives
 - Installs to specified paths with proper permissions
 - Supports Windows, macOS, and Linux`,
+	PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+		noColor, _ := cmd.Flags().GetBool("no-color")
+		noProgress, _ := cmd.Flags().GetBool("no-progress")
+		ui.Configure(ui.Options{NoColor: noColor, NoProgress: noProgress})
+
+		// Before the rest, whose warnings are translated too
+		lang, _ := cmd.Flags().GetString("lang")
+		lang, err := i18n.Detect(lang, os.Getenv)
+		if err != nil {
+			return err
+		}
+		i18n.SetLanguage(lang)
+
+		yes, _ := cmd.Flags().GetBool("yes")
+		nonInteractive, _ := cmd.Flags().GetBool("non-interactive")
+		if noInput, _ := cmd.Flags().GetBool("no-input"); noInput {
+			nonInteractive = true
+		}
+		prompt.Configure(prompt.Options{AssumeYes: yes, NonInteractive: nonInteractive})
+		// Read from the root, so no command's own flag can take the GitHub token's place
+		token, _ := cmd.Root().PersistentFlags().GetString("token")
+		github.DefaultToken = token
+		github.WaitForRateLimit, _ = cmd.Flags().GetBool("wait-for-rate-limit")
+		if err := configureProxy(cmd); err != nil {
+			return err
+		}
+		if err := configureTLS(cmd); err != nil {
+			return err
+		}
+		configureCredentials()
+		configureCacheServer()
+		configureAPICache()
+		configureLowMemory(cmd)
+		configureTempDir()
+		if err := configureRetry(cmd); err != nil {
+			return err
+		}
+
+		profileFlag, _ := cmd.Flags().GetBool("profile")
+		profileTrace, _ := cmd.Flags().GetString("profile-trace")
+		if profileFlag || profileTrace != "" {
+			profiler = profile.New()
+		}
+
+		if insecure, _ := cmd.Flags().GetBool("insecure"); insecure {
+			fmt.Fprint(os.Stderr, i18n.T("%s WARNING: --insecure disables TLS certificate verification. Anyone on the network path can replace downloads, checksums and signatures without notice.\n", ui.Warn("!")))
+		}
+		return nil
+	},
+	PersistentPostRun: func(cmd *cobra.Command, args []string) {
+		noUpdateCheck, _ := cmd.Flags().GetBool("no-update-check")
+		if !noUpdateCheck {
+			notifyUpdate()
+		}
+		autoPruneCache()
+	},
 }
 
 var downloadCmd = &cobra.Command{
@@ -40,20 +128,20 @@ var downloadCmd = &cobra.Command{
 	Args:  cobra.ExactArgs(1),
 	Run: func(cmd *cobra.Command, args []string) {
 		if err := runDownload(cmd, args); err != nil {
-			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
-			os.Exit(1)
+			fmt.Fprint(os.Stderr, i18n.T("Error: %v\n", err))
+			os.Exit(exitcode.Code(err))
 		}
 	},
 }
 
 var installCmd = &cobra.Command{
-	Use:   "install [GITHUB_REPO]",
-	Short: "Install from GitHub release (e.g., github:pyhub-kr/pyhub-mcptools)",
-	Args:  cobra.ExactArgs(1),
+	Use:   "install SOURCE[@VERSION]...",
+	Short: "Install from release sources (e.g., github:pyhub-kr/pyhub-mcptools or an alias like rg)",
+	Args:  cobra.MinimumNArgs(1),
 	Run: func(cmd *cobra.Command, args []string) {
 		if err := runInstall(cmd, args); err != nil {
-			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
-			os.Exit(1)
+			fmt.Fprint(os.Stderr, i18n.T("Error: %v\n", err))
+			os.Exit(exitcode.Code(err))
 		}
 	},
 }
@@ -69,10 +157,32 @@ var versionCmd = &cobra.Command{
 	},
 }
 
+// systemInstallReason explains why the system directory is used without searching PATH
+// for a writable directory: the installer runs in a container or as root (administrator
+// on Windows). It returns "" otherwise.
+func systemInstallReason() string {
+	dir := install.SystemInstallPath()
+	// Container images put /usr/local/bin in PATH, and their user is usually root
+	if container := platform.Container(); container != "" && install.Writable(dir) {
+		return i18n.T("Running in a %s container; installing to %s\n", container, dir)
+	}
+	if platform.IsElevated() {
+		if runtime.GOOS == "windows" {
+			return i18n.T("Running as administrator; installing system-wide to %s\n", dir)
+		}
+		return i18n.T("Running as root; installing system-wide to %s\n", dir)
+	}
+	return ""
+}
+
 // getDefaultInstallPath returns platform-specific default installation path
 func getDefaultInstallPath() string {
 	switch runtime.GOOS {
 	case "windows":
+		// Elevated prompts install for all users, under Program Files
+		if platform.IsElevated() {
+			return install.SystemInstallPath()
+		}
 		// Windows: Use %LOCALAPPDATA%\Programs or fallback to %USERPROFILE%\bin
 		if localAppData := os.Getenv("LOCALAPPDATA"); localAppData != "" {
 			return filepath.Join(localAppData, "Programs")
@@ -89,6 +199,25 @@ func getDefaultInstallPath() string {
 }
 
 func init() {
+	rootCmd.PersistentFlags().Bool("no-update-check", false, "Don't check for a newer pyhub-installer release")
+	rootCmd.PersistentFlags().Bool("no-color", false, "Disable colored output (also set by NO_COLOR)")
+	rootCmd.PersistentFlags().Bool("no-progress", false, "Print progress as percentage lines instead of progress bars")
+	rootCmd.PersistentFlags().String("token", "", "GitHub API token (default: GITHUB_TOKEN or GH_TOKEN); other users can see it in the process list, so prefer the variables on shared machines")
+	rootCmd.PersistentFlags().Bool("wait-for-rate-limit", false, "Wait for an exhausted GitHub API rate limit to reset, up to an hour, instead of failing")
+	rootCmd.PersistentFlags().String("lang", "", "Message language: en or ko (default from LANG)")
+	rootCmd.PersistentFlags().BoolP("yes", "y", false, "Answer yes to confirmations and accept default choices without prompting")
+	rootCmd.PersistentFlags().Bool("non-interactive", false, "Never prompt; fail when an answer is required")
+	rootCmd.PersistentFlags().Bool("no-input", false, "Same as --non-interactive")
+	rootCmd.PersistentFlags().Bool("profile", false, "Report the time spent per phase (resolve, download, verify, extract, install)")
+	rootCmd.PersistentFlags().String("proxy", "", "Proxy for all requests, e.g. http://proxy:8080 or socks5://127.0.0.1:1080 (default: HTTPS_PROXY, HTTP_PROXY or the system proxy); NO_PROXY still applies")
+	rootCmd.PersistentFlags().String("ca-cert", "", "PEM file of extra trusted root certificates, e.g. of a TLS-intercepting proxy (default: ca_cert in config.json)")
+	rootCmd.PersistentFlags().String("client-cert", "", "PEM client certificate for servers requiring mutual TLS (default: client_cert in config.json)")
+	rootCmd.PersistentFlags().String("client-key", "", "PEM private key of --client-cert, if not in the certificate file (default: client_key in config.json)")
+	rootCmd.PersistentFlags().Bool("insecure", false, "Skip TLS certificate verification of downloads and API requests, for internal servers with self-signed certificates; unsafe")
+	rootCmd.PersistentFlags().Int("retries", 0, "Times to retry a failed request or interrupted download (default: retry.attempts in config.json minus one)")
+	rootCmd.PersistentFlags().Bool("low-memory", false, "Download one file at a time in a single stream with small buffers, for machines with little memory (also set by PYHUB_LOW_MEMORY)")
+	rootCmd.PersistentFlags().String("profile-trace", "", "Also write the phases as a Chrome trace file (chrome://tracing, ui.perfetto.dev)")
+
 	// Download command flags
 	downloadCmd.Flags().StringP("output", "o", ".", "Output directory")
 	downloadCmd.Flags().BoolP("verify", "v", false, "Verify signature")
@@ -98,11 +227,32 @@ func init() {
 	downloadCmd.Flags().BoolP("remove-archive", "r", false, "Remove archive after extraction")
 	downloadCmd.Flags().BoolP("flatten", "f", false, "Remove top-level directory when extracting")
 	downloadCmd.Flags().Bool("no-flatten", false, "Disable automatic flattening of single top-level directory")
+	downloadCmd.Flags().String("version", "", "Version substituted for {version} in the URL")
+	downloadCmd.Flags().StringP("platform", "p", "", "Platform substituted for {os} and {arch} in the URL (default: current)")
+	downloadCmd.Flags().StringArray("mirror", nil, "Other URL of the same file, tried in order when the download fails (repeatable; templates are expanded like the URL)")
+	downloadCmd.Flags().StringArray("header", nil, "Extra request header as 'Name: value', e.g. for API keys of artifact repositories (repeatable)")
+	downloadCmd.Flags().String("user-agent", "", "User-Agent header sent instead of pyhub-installer/<version>")
 	
 	// Install command flags
-	installCmd.Flags().String("version", "latest", "Version to install")
+	installCmd.Flags().String("version", "latest", "Version or semver constraint to install (e.g. v1.2.3, \"^1.4\", \">=2,<3\")")
 	installCmd.Flags().String("platform", "", "Target platform (auto-detect if empty)")
+	installCmd.Flags().StringArray("asset", nil, "Glob or regular expression selecting the asset to install (skips platform detection; repeat to install several assets of the release)")
+	installCmd.Flags().Bool("pre", false, "Allow pre-releases (newest release of any kind)")
+	installCmd.Flags().String("channel", "", "Release channel: stable, beta, rc or nightly")
+	installCmd.Flags().Bool("include-drafts", false, "Allow --version to name a draft release (requires GITHUB_TOKEN with push access)")
 	installCmd.Flags().StringP("output", "o", getDefaultInstallPath(), "Installation directory")
+	installCmd.Flags().Int64("run-id", 0, "Install artifacts of a GitHub Actions workflow run (requires GITHUB_TOKEN)")
+	installCmd.Flags().String("branch", "", "Install artifacts of the latest successful workflow run on a branch")
+	installCmd.Flags().String("workflow", "", "Workflow file name or ID for --branch (e.g. build.yml)")
+	installCmd.Flags().BoolP("interactive", "i", false, "Choose the asset to install from a list")
+	installCmd.Flags().Bool("keep-versions", false, "Install executables with the version in their names (e.g. tool-1.8.0) and link the unversioned names to this version")
+	installCmd.Flags().StringArray("mirror", nil, "Mirror URL template for GitHub release assets, containing {url} or {path}; tried before the configured mirrors (repeatable)")
+	installCmd.Flags().Bool("build-from-source", false, "Build Go projects from their source archive with the Go toolchain when no release asset matches the platform")
+	installCmd.Flags().Bool("explain", false, "Show the platform score of every asset and why the installed one was chosen")
+	installCmd.Flags().IntP("jobs", "j", 4, "Number of sources to resolve and download concurrently")
+	installCmd.Flags().Bool("system", false, "Install to the system directory ("+install.SystemInstallPath()+") without searching PATH for a writable one")
+	installCmd.Flags().Bool("with-windows", false, "Under WSL, also install the Windows build into %LOCALAPPDATA%\\Programs on the Windows side")
+	installCmd.Flags().Bool("refuse-deprecated", false, "Fail when the version to install is marked deprecated, yanked or EOL instead of warning")
 	
 	rootCmd.AddCommand(downloadCmd)
 	rootCmd.AddCommand(installCmd)
@@ -111,16 +261,45 @@ func init() {
 
 // runDownload implements the download command
 func runDownload(cmd *cobra.Command, args []string) error {
-	url := args[0]
+	defer finishProfile(cmd)
+	url, err := expandURL(cmd, args[0])
+	if err != nil {
+		return exitcode.Wrap(exitcode.Usage, err)
+	}
+	mirrors, _ := cmd.Flags().GetStringArray("mirror")
+	for i := range mirrors {
+		if mirrors[i], err = expandURL(cmd, mirrors[i]); err != nil {
+			return exitcode.Wrap(exitcode.Usage, err)
+		}
+	}
+	headerFlags, _ := cmd.Flags().GetStringArray("header")
+	extraHeaders, err := download.ParseHeaders(headerFlags)
+	if err != nil {
+		return exitcode.Wrap(exitcode.Usage, err)
+	}
+	// Set for every request of the command, including those to other mirrors
+	if userAgent, _ := cmd.Flags().GetString("user-agent"); userAgent != "" {
+		useragent.Default = userAgent
+	}
 	output, _ := cmd.Flags().GetString("output")
 	verifyFlag, _ := cmd.Flags().GetBool("verify")
 	extractFlag, _ := cmd.Flags().GetBool("extract")
 	signature, _ := cmd.Flags().GetString("signature")
+	// A signature fetched over an unverified connection can be replaced with the file
+	if insecure, _ := cmd.Flags().GetBool("insecure"); insecure && (verifyFlag || signature != "") {
+		return exitcode.Wrap(exitcode.Usage, errors.New(i18n.T("--insecure cannot be combined with --verify or --signature, as signatures fetched without TLS verification prove nothing")))
+	}
 	chmod, _ := cmd.Flags().GetString("chmod")
 	removeArchive, _ := cmd.Flags().GetBool("remove-archive")
 	flatten, _ := cmd.Flags().GetBool("flatten")
 	noFlatten, _ := cmd.Flags().GetBool("no-flatten")
 
+	cfg, err := config.Load()
+	if err != nil {
+		return err
+	}
+	runner := hooks.NewRunner(cfg.Hooks)
+
 	// If user specified a system directory and doesn't have write permission, find alternative
 	systemDirs := []string{"/usr/local/bin", "/usr/bin", "/opt", "/usr/local"}
 	isSystemDir := false
@@ -135,7 +314,7 @@ func runDownload(cmd *cobra.Command, args []string) error {
 		// Try to create directory first to test permission
 		if err := os.MkdirAll(output, 0755); err != nil {
 			if writableDir, pathErr := install.FindWritableInstallPath(); pathErr == nil {
-				fmt.Printf("Permission denied for %s, using writable directory: %s\n", output, writableDir)
+				fmt.Print(i18n.T("Permission denied for %s, using writable directory: %s\n", output, writableDir))
 				output = writableDir
 			}
 		}
@@ -146,6 +325,16 @@ func runDownload(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("failed to create output directory: %w", err)
 	}
 
+	// Serialize with other installer processes writing to the same directory
+	dirLock, err := lock.AcquireDir(output)
+	if err != nil {
+		return err
+	}
+	defer dirLock.Release()
+
+	// Remove executables left behind by earlier in-use replacements
+	replace.CleanupStale(output)
+
 	// Determine filename from URL
 	filename := filepath.Base(url)
 	if filename == "/" || filename == "." {
@@ -155,29 +344,62 @@ func runDownload(cmd *cobra.Command, args []string) error {
 	// Create full output path
 	outputPath := filepath.Join(output, filename)
 
-	fmt.Printf("Downloading %s...\n", url)
+	fmt.Print(i18n.T("Downloading %s...\n", url))
+
+	ctx := profile.WithProfiler(cmd.Context(), profiler)
+	hookContext := hooks.Context{Tool: filename, URL: url, File: outputPath, InstallPath: output}
+	endDownload := profile.Start(ctx, profile.Download, filename)
+	if err := runner.Run(ctx, hooks.PreDownload, hookContext); err != nil {
+		return err
+	}
 
-	// Download file
-	downloader := download.NewChunkDownloader(url, outputPath)
-	ctx := context.Background()
-	if err := downloader.Download(ctx); err != nil {
-		return fmt.Errorf("download failed: %w", err)
+	// Download file. Release assets of private repositories are fetched through the API.
+	downloadURL, headers, err := github.NewClient().ReleaseDownloadURL(url)
+	if err != nil {
+		endDownload()
+		return exitcode.Wrap(exitcode.Network, fmt.Errorf("download failed: %w", err))
+	}
+	downloader := download.NewChunkDownloader(downloadURL, outputPath)
+	downloader.Headers = headers
+	if len(extraHeaders) > 0 {
+		downloader.Headers = make(map[string]string, len(headers)+len(extraHeaders))
+		maps.Copy(downloader.Headers, headers)
+		maps.Copy(downloader.Headers, extraHeaders)
+	}
+	downloader.Mirrors = mirrors
+	downloader.MirrorHeaders = extraHeaders
+	err = downloader.Download(ctx)
+	endDownload()
+	if errors.Is(err, download.ErrNotFound) {
+		return exitcode.Wrap(exitcode.NotFound, fmt.Errorf("download failed: %w", err))
+	}
+	if err != nil {
+		return exitcode.Wrap(exitcode.Network, fmt.Errorf("download failed: %w", err))
 	}
 
-	fmt.Printf("✓ Downloaded to: %s\n", outputPath)
+	fmt.Print(i18n.T("%s Downloaded to: %s\n", ui.Success("✓"), outputPath))
 
 	// Verify signature if requested
+	endVerify := profile.Start(ctx, profile.Verify, filename)
 	if verifyFlag && signature != "" {
-		fmt.Println("Verifying signature...")
+		fmt.Println(i18n.T("Verifying signature..."))
 		verifier := verify.NewVerifier(outputPath)
-		if err := verifier.VerifyWithURL(signature); err != nil {
-			return fmt.Errorf("verification failed: %w", err)
+		verifier.Headers = extraHeaders
+		if err := verifier.VerifyWithURL(ctx, signature); err != nil {
+			return exitcode.Wrap(exitcode.Verification, fmt.Errorf("verification failed: %w", err))
 		}
 	}
+	err = runner.Run(ctx, hooks.PostVerify, hookContext)
+	endVerify()
+	if err != nil {
+		os.Remove(outputPath)
+		return exitcode.Wrap(exitcode.Verification, err)
+	}
 
 	// Extract if requested
 	if extractFlag {
-		fmt.Println("Extracting archive...")
+		endExtract := profile.Start(ctx, profile.Extract, filename)
+		fmt.Println(i18n.T("Extracting archive..."))
 		extractor := extract.NewExtractor(outputPath, output)
 		
 		// Configure flatten behavior
@@ -188,20 +410,23 @@ func runDownload(cmd *cobra.Command, args []string) error {
 			extractor.SetAutoFlatten(true)
 		}
 		
-		if err := extractor.Extract(); err != nil {
-			return fmt.Errorf("extraction failed: %w", err)
+		err := extractor.ExtractContext(ctx)
+		endExtract()
+		if err != nil {
+			return exitcode.Wrap(exitcode.Extraction, fmt.Errorf("extraction failed: %w", err))
 		}
 		
 		// Remove archive after successful extraction if requested
 		if removeArchive {
-			fmt.Printf("Removing archive: %s\n", outputPath)
+			fmt.Print(i18n.T("Removing archive: %s\n", outputPath))
 			if err := os.Remove(outputPath); err != nil {
-				fmt.Printf("Warning: failed to remove archive: %v\n", err)
+				fmt.Print(i18n.T("Warning: failed to remove archive: %v\n", err))
 			}
 		}
 	}
 
 	// Install with permissions
+	defer profile.Start(ctx, profile.Install, filename)()
 	if chmod != "" && !extractFlag {
 		installer := install.NewInstaller(outputPath, outputPath, chmod)
 		if err := installer.Install(); err != nil {
@@ -209,22 +434,243 @@ func runDownload(cmd *cobra.Command, args []string) error {
 		}
 	}
 
+	if err := runner.Run(ctx, hooks.PostInstall, hookContext); err != nil {
+		fmt.Print(i18n.T("Warning: %v\n", err))
+	}
 	return nil
 }
 
 // runInstall implements the install command
 func runInstall(cmd *cobra.Command, args []string) error {
-	repo := args[0]
+	defer finishProfile(cmd)
 	version, _ := cmd.Flags().GetString("version")
 	platform, _ := cmd.Flags().GetString("platform")
+	assetPatterns, _ := cmd.Flags().GetStringArray("asset")
 	output, _ := cmd.Flags().GetString("output")
+	pre, _ := cmd.Flags().GetBool("pre")
+	channel, _ := cmd.Flags().GetString("channel")
+	includeDrafts, _ := cmd.Flags().GetBool("include-drafts")
+	interactive, _ := cmd.Flags().GetBool("interactive")
+	explain, _ := cmd.Flags().GetBool("explain")
+	keepVersions, _ := cmd.Flags().GetBool("keep-versions")
+	buildFromSource, _ := cmd.Flags().GetBool("build-from-source")
+	mirrors, _ := cmd.Flags().GetStringArray("mirror")
+	jobs, _ := cmd.Flags().GetInt("jobs")
+	refuseDeprecated, _ := cmd.Flags().GetBool("refuse-deprecated")
+	system, _ := cmd.Flags().GetBool("system")
+	withWindows, _ := cmd.Flags().GetBool("with-windows")
+	runID, _ := cmd.Flags().GetInt64("run-id")
+	branch, _ := cmd.Flags().GetString("branch")
+	workflow, _ := cmd.Flags().GetString("workflow")
+
+	args, err := readSourceArgs(args, os.Stdin)
+	if err != nil {
+		return err
+	}
+	cfg, err := config.Load()
+	if err != nil {
+		return err
+	}
+
+	if pre {
+		channel = provider.ChannelPre
+	}
+	if channel != "" {
+		if err := provider.ValidateChannel(channel); err != nil {
+			return err
+		}
+	}
+	if len(assetPatterns) > 0 && len(args) > 1 {
+		return fmt.Errorf("--asset can only be used with a single source")
+	}
+	for _, pattern := range assetPatterns {
+		if err := github.ValidateAssetPattern(pattern); err != nil {
+			return exitcode.Wrap(exitcode.Usage, err)
+		}
+	}
+	for _, mirror := range mirrors {
+		if err := config.ValidateMirror(mirror); err != nil {
+			return exitcode.Wrap(exitcode.Usage, err)
+		}
+	}
+	var assetPattern string
+	if len(assetPatterns) == 1 {
+		assetPattern = assetPatterns[0]
+	}
+	if withWindows {
+		if !wsl.Detect() {
+			return exitcode.Wrap(exitcode.Usage, fmt.Errorf("--with-windows is only supported under WSL"))
+		}
+		if platform != "" || len(assetPatterns) > 0 {
+			return exitcode.Wrap(exitcode.Usage, fmt.Errorf("--with-windows cannot be combined with --platform or --asset"))
+		}
+	}
+	if system {
+		if cmd.Flags().Changed("output") {
+			return exitcode.Wrap(exitcode.Usage, fmt.Errorf("--system cannot be combined with --output"))
+		}
+		output = install.SystemInstallPath()
+	}
+	output, storeLayout, err := storeLayoutOutput(cfg, output, system || cmd.Flags().Changed("output"))
+	if err != nil {
+		return err
+	}
+	if keepVersions && storeLayout {
+		return exitcode.Wrap(exitcode.Usage, fmt.Errorf("--keep-versions is not needed with the store layout, which keeps every version; switch versions with 'use'"))
+	}
+
+	var run *provider.RunSelector
+	if runID != 0 || branch != "" || workflow != "" {
+		if runID != 0 && (branch != "" || workflow != "") {
+			return fmt.Errorf("--run-id cannot be combined with --branch or --workflow")
+		}
+		if version != "latest" || channel != "" || includeDrafts {
+			return fmt.Errorf("workflow artifacts cannot be combined with --version, --pre, --channel or --include-drafts")
+		}
+		run = &provider.RunSelector{ID: runID, Branch: branch, Workflow: workflow}
+	}
+
+	output, dirLock, err := prepareOutput(output, system)
+	if err != nil {
+		return err
+	}
+	defer dirLock.Release()
+
+	var targets []*installJob
+	for _, arg := range args {
+		targets = append(targets, parseTarget(arg, version))
+	}
+	// Several --asset patterns install one asset each from the release of the source
+	if len(assetPatterns) > 1 {
+		first := targets[0]
+		targets = nil
+		for _, pattern := range assetPatterns {
+			job := *first
+			job.AssetPattern = pattern
+			targets = append(targets, &job)
+		}
+		for _, job := range targets {
+			job.group = targets[0]
+		}
+	}
+
+	opts := installOptions{
+		Version:          version,
+		Platform:         platform,
+		AssetPattern:     assetPattern,
+		Output:           output,
+		Channel:          channel,
+		IncludeDrafts:    includeDrafts,
+		Interactive:      interactive,
+		Explain:          explain,
+		Run:              run,
+		Jobs:             jobs,
+		Config:           cfg,
+		RefuseDeprecated: refuseDeprecated,
+		StoreLayout:      storeLayout,
+		KeepVersions:     keepVersions,
+		BuildFromSource:  buildFromSource,
+		Mirrors:          mirrors,
+	}
+	ctx := profile.WithProfiler(cmd.Context(), profiler)
+	if err := installJobs(ctx, targets, opts); err != nil {
+		return err
+	}
+	fmt.Print(i18n.T("%s Installation completed to: %s\n", ui.Success("✓"), output))
+
+	if withWindows {
+		return installWindowsCopies(ctx, targets, opts)
+	}
+	return nil
+}
+
+// installWindowsCopies installs the Windows builds of the installed releases into the
+// Windows user's install directory, for tools used from both sides of WSL. The copies
+// are not recorded.
+func installWindowsCopies(ctx context.Context, installed []*installJob, opts installOptions) error {
+	dir, err := wsl.WindowsInstallDir()
+	if err != nil {
+		return err
+	}
+	output, dirLock, err := prepareOutput(dir, false)
+	if err != nil {
+		return err
+	}
+	defer dirLock.Release()
+
+	fmt.Println()
+	fmt.Print(i18n.T("Installing the Windows builds into %s\n", output))
+	targets := make([]*installJob, len(installed))
+	for i, j := range installed {
+		targets[i] = &installJob{Input: j.Input, Version: j.release.TagName}
+	}
+	opts.Platform = "windows-" + runtime.GOARCH
+	opts.Output = output
+	opts.SkipCurrent = false
+	opts.SkipReceipt = true
+	opts.StoreLayout = false
+	if err := installJobs(ctx, targets, opts); err != nil {
+		return err
+	}
+	fmt.Print(i18n.T("%s Installation completed to: %s\n", ui.Success("✓"), output))
+	return nil
+}
+
+// expandURL substitutes the {os}, {arch} and {version} placeholders of a download URL
+func expandURL(cmd *cobra.Command, rawURL string) (string, error) {
+	if !urltemplate.IsTemplate(rawURL) {
+		return rawURL, nil
+	}
+	version, _ := cmd.Flags().GetString("version")
+	target, _ := cmd.Flags().GetString("platform")
+	if target == "" {
+		target = platform.Current()
+	}
+
+	goos, goarch := platform.Split(target)
+	if goarch == "" {
+		return "", fmt.Errorf("invalid platform %q, expected os-arch such as linux-amd64", target)
+	}
+	if version == "" && urltemplate.Uses(rawURL, urltemplate.Version) {
+		return "", fmt.Errorf("the URL contains {version}; pass the version with --version")
+	}
+	return urltemplate.Expand(rawURL, urltemplate.Values{OS: goos, Arch: goarch, Version: version})
+}
+
+// prepareOutput creates and locks an install directory. The default directory is
+// replaced by a writable one in PATH if possible, so the chosen directory is returned.
+// System installs keep it as is, as do root and containers when it is the system directory.
+// Where a system directory is not writable, Linux desktops offer to elevate with pkexec.
+func prepareOutput(output string, system bool) (string, *lock.Lock, error) {
+	output, err := resolveOutput(output, system)
+	if err != nil {
+		return "", nil, err
+	}
+	dirLock, err := lockOutput(output)
+	if err != nil {
+		return "", nil, err
+	}
+	return output, dirLock, nil
+}
+
+// resolveOutput chooses and creates the install directory for prepareOutput
+func resolveOutput(output string, system bool) (string, error) {
+	if system && !install.Writable(output) {
+		offerPkexec(output)
+		return "", exitcode.Wrap(exitcode.Permission, fmt.Errorf("%s is not writable; --system installs must run as root (or as administrator on Windows)", output))
+	}
 
 	// If using default output path, try to find a writable directory in PATH
 	defaultPath := getDefaultInstallPath()
-	if output == defaultPath || output == "/usr/local/bin" {
-		if writableDir, err := install.FindWritableInstallPath(); err == nil {
+	if !system && (output == defaultPath || output == "/usr/local/bin") {
+		if reason := systemInstallReason(); reason != "" && output == install.SystemInstallPath() {
+			fmt.Print(reason)
+			if !install.IsPathInEnv(output) {
+				fmt.Print(i18n.T("Note: %s is not in PATH; installed tools will not be found by name\n", output))
+			}
+		} else if writableDir, err := install.FindWritableInstallPath(); err == nil {
 			if writableDir != output {
-				fmt.Printf("Using writable directory: %s\n", writableDir)
+				fmt.Print(i18n.T("Using writable directory: %s\n", writableDir))
 				output = writableDir
 			}
 		}
@@ -232,81 +678,464 @@ func runInstall(cmd *cobra.Command, args []string) error {
 
 	// Create output directory if it doesn't exist
 	if err := os.MkdirAll(output, 0755); err != nil {
-		return fmt.Errorf("failed to create output directory: %w", err)
+		return "", fmt.Errorf("failed to create output directory: %w", err)
 	}
+	return filepath.Clean(output), nil
+}
 
-	// Parse repository
-	owner, repoName, err := github.ParseRepoURL(repo)
+// lockOutput locks a resolved install directory against other installer processes.
+// A process must lock each directory once, as a second lock of the same directory
+// would wait for the first.
+func lockOutput(output string) (*lock.Lock, error) {
+	dirLock, err := lock.AcquireDir(output)
 	if err != nil {
-		return fmt.Errorf("invalid repository: %w", err)
+		return nil, err
 	}
 
-	fmt.Printf("Installing %s/%s from GitHub...\n", owner, repoName)
+	// Remove executables left behind by earlier in-use replacements
+	replace.CleanupStale(output)
+	return dirLock, nil
+}
 
-	// Get release
-	client := github.NewClient()
-	var release *github.Release
-	
-	if version == "latest" {
-		release, err = client.GetLatestRelease(owner, repoName)
-	} else {
-		release, err = client.GetRelease(owner, repoName, version)
+// parseSource expands tool aliases and resolves the source to its release provider
+func parseSource(input string) (provider.ReleaseProvider, provider.Source, error) {
+	registry, err := alias.Load()
+	if err != nil {
+		return nil, provider.Source{}, err
 	}
-	
+
+	resolved, err := registry.Resolve(input)
 	if err != nil {
-		return fmt.Errorf("failed to get release: %w", err)
+		return nil, provider.Source{}, err
 	}
+	return provider.Parse(resolved)
+}
 
-	fmt.Printf("Found release: %s\n", release.TagName)
+// resolveRelease finds the release to install for a version, version constraint or release
+// channel. A non-nil latest policy replaces the provider's own notion of "latest".
+func resolveRelease(ctx context.Context, prov provider.ReleaseProvider, src provider.Source, version, channel string, latest *provider.LatestPolicy) (*provider.Release, error) {
+	if semver.IsConstraint(version) {
+		constraint, err := semver.ParseConstraint(version)
+		if err != nil {
+			return nil, err
+		}
+
+		releases, err := prov.ListVersions(ctx, src)
+		if err != nil {
+			return nil, err
+		}
+		return provider.SelectConstraint(releases, constraint, channel)
+	}
+
+	isLatest := version == "" || version == "latest"
+	if isLatest && latest != nil && (channel == "" || channel == provider.ChannelStable) {
+		releases, err := prov.ListVersions(ctx, src)
+		if err != nil {
+			return nil, err
+		}
+		return provider.SelectLatest(releases, *latest)
+	}
+
+	if channel == "" || (channel == provider.ChannelStable && isLatest) {
+		return prov.Resolve(ctx, src, version)
+	}
+	if !isLatest {
+		release, err := prov.Resolve(ctx, src, version)
+		if err == nil && !provider.InChannel(release, channel) {
+			return nil, fmt.Errorf("%s is not a release of channel %s", release.TagName, channel)
+		}
+		return release, err
+	}
 
-	// Find asset for platform
-	asset, err := release.FindAssetForPlatform(platform)
+	releases, err := prov.ListVersions(ctx, src)
 	if err != nil {
-		return fmt.Errorf("failed to find asset: %w", err)
+		return nil, err
 	}
+	return provider.SelectChannel(releases, channel)
+}
 
-	fmt.Printf("Found asset: %s (%d bytes)\n", asset.Name, asset.Size)
+// assetScoring returns the asset scoring rules extended by the config file
+func assetScoring(cfg *config.Config) *github.Scoring {
+	scoring := github.DefaultScoring()
+	if cfg == nil {
+		return scoring
+	}
+	return scoring.Extend(cfg.Scoring.Platforms, cfg.Scoring.Adjustments)
+}
 
-	// Download asset
-	outputPath := filepath.Join(output, asset.Name)
-	downloader := download.NewChunkDownloader(asset.BrowserDownloadURL, outputPath)
-	ctx := context.Background()
-	
-	if err := downloader.Download(ctx); err != nil {
-		return fmt.Errorf("download failed: %w", err)
+// latestPolicy returns the configured latest policy for a source, or nil for the default
+func latestPolicy(cfg *config.Config, src provider.Source) (*provider.LatestPolicy, error) {
+	if cfg == nil {
+		return nil, nil
 	}
 
-	// Try to find and verify signature
-	sigAsset, err := release.FindSignatureAsset(asset.Name)
-	if err == nil {
-		fmt.Println("Found signature file, verifying...")
-		verifier := verify.NewVerifier(outputPath)
-		if err := verifier.VerifyWithURL(sigAsset.BrowserDownloadURL); err != nil {
-			fmt.Printf("Warning: signature verification failed: %v\n", err)
+	settings := cfg.LatestPolicyFor(src.String())
+	if settings.IsDefault() {
+		return nil, nil
+	}
+
+	policy, err := provider.NewLatestPolicy(settings.IncludePrerelease, settings.SkipTags, settings.PreferTags)
+	if err != nil {
+		return nil, err
+	}
+	return &policy, nil
+}
+
+// selectPlatformAsset finds the asset for a platform, falling back to a platform the
+// system can emulate (e.g. Intel builds under Rosetta 2) when there is no native build
+func selectPlatformAsset(release *provider.Release, target string, scoring *github.Scoring) (*provider.Asset, error) {
+	if target == "" {
+		target = platform.Native()
+	}
+
+	asset, err := release.FindAssetWithScoring(target, scoring)
+	_, arch := platform.Split(target)
+	if err == nil && github.AssetMatchesArch(asset.Name, arch) {
+		return asset, nil
+	}
+
+	if fallback, note, ok := platform.Fallback(target); ok {
+		fallbackAsset, fallbackErr := release.FindAssetWithScoring(fallback, scoring)
+		if fallbackErr == nil {
+			fmt.Print(i18n.T("Note: %s\n", note))
+			return fallbackAsset, nil
 		}
-	} else {
-		fmt.Println("No signature file found, skipping verification")
 	}
 
-	// Extract if it's an archive
-	extractor := extract.NewExtractor(outputPath, output)
-	if err := extractor.Extract(); err != nil {
-		fmt.Printf("Note: Not an archive or extraction failed: %v\n", err)
+	if hint := platform.FallbackHint(target); hint != "" {
+		return nil, fmt.Errorf("no asset found for platform: %s (%s)", target, hint)
+	}
+	return asset, err
+}
+
+// chooseAsset lets the user pick the asset when several score equally, or among all
+// assets with interactive set, e.g. when none matches the platform. Without a terminal
+// the automatic choice is kept.
+func chooseAsset(release *provider.Release, chosen *provider.Asset, target string, scoring *github.Scoring, interactive bool, label string) (*provider.Asset, error) {
+	if target == "" {
+		target = platform.Native()
+	}
+	ranked, rankErr := release.RankAssets(target, scoring)
+
+	var candidates []*provider.Asset
+	if interactive {
+		if chosen == nil {
+			fmt.Print(i18n.T("Note: no asset matches %s\n", target))
+		}
+		if rankErr == nil {
+			for _, r := range ranked {
+				candidates = append(candidates, r.Asset)
+			}
+		} else {
+			for i := range release.Assets {
+				candidates = append(candidates, &release.Assets[i])
+			}
+		}
 	} else {
-		// Set executable permissions for extracted files
-		installer := install.NewInstaller(output, output, "755")
-		if err := installer.InstallDirectory(); err != nil {
-			fmt.Printf("Warning: failed to set permissions: %v\n", err)
+		candidates = github.TiedAssets(ranked)
+		if len(candidates) < 2 || !containsAsset(candidates, chosen) {
+			return chosen, nil
+		}
+		if !prompt.IsInteractive() {
+			fmt.Print(i18n.T("Note: %d assets match %s equally, using %s (select one with --asset or --interactive)\n", len(candidates), target, chosen.Name))
+			return chosen, nil
+		}
+	}
+	if len(candidates) == 0 {
+		return nil, fmt.Errorf("release %s has no assets", release.TagName)
+	}
+
+	options := make([]string, len(candidates))
+	defaultIndex := 0
+	for i, asset := range candidates {
+		options[i] = fmt.Sprintf("%s (%s)", asset.Name, clean.FormatSize(asset.Size))
+		if chosen != nil && asset.Name == chosen.Name {
+			defaultIndex = i
+		}
+	}
+
+	index, err := prompt.Default().Select(fmt.Sprintf("Select an asset for %s:", label), options, defaultIndex)
+	if err != nil {
+		return nil, err
+	}
+	return candidates[index], nil
+}
+
+// containsAsset reports whether assets includes an asset with the same name as asset
+func containsAsset(assets []*provider.Asset, asset *provider.Asset) bool {
+	for _, a := range assets {
+		if asset != nil && a.Name == asset.Name {
+			return true
+		}
+	}
+	return false
+}
+
+// recordReceipt saves an install receipt in the state database
+func recordReceipt(receipt state.Receipt) error {
+	db, err := state.DefaultDB()
+	if err != nil {
+		return err
+	}
+	return db.Update(func(s *state.State) error {
+		s.Put(receipt)
+		return nil
+	})
+}
+
+// addReceiptFiles adds the files installed from a further asset of a release, and the
+// asset's download, to the receipt of the tool, so that uninstall removes them too
+func addReceiptFiles(receipt state.Receipt) error {
+	db, err := state.DefaultDB()
+	if err != nil {
+		return err
+	}
+	return db.Update(func(s *state.State) error {
+		existing := s.Get(receipt.Name)
+		if existing == nil || existing.Version != receipt.Version {
+			s.Put(receipt)
+			return nil
+		}
+		files := append(receipt.Files, filepath.Join(receipt.InstallPath, receipt.Asset))
+		for _, file := range files {
+			if !slices.Contains(existing.Files, file) {
+				existing.Files = append(existing.Files, file)
+			}
+		}
+		return nil
+	})
+}
+
+// configureProxy routes requests through the proxy of --proxy, or else of the proxy
+// environment variables, or else of the operating system unless system_proxy is false
+// in config.json, and sets up NTLM or Negotiate authentication to the proxy if
+// proxy_auth is configured
+func configureProxy(cmd *cobra.Command) error {
+	if proxy, _ := cmd.Flags().GetString("proxy"); proxy != "" {
+		u, err := sysproxy.ParseURL(proxy)
+		if err != nil {
+			return exitcode.Wrap(exitcode.Usage, err)
 		}
+		// The variables take precedence over the system proxy, and are passed on to
+		// the installer processes started by this one
+		os.Setenv("HTTPS_PROXY", u.String())
+		os.Setenv("HTTP_PROXY", u.String())
 	}
 
-	fmt.Printf("✓ Installation completed to: %s\n", output)
+	transport := http.DefaultTransport.(*http.Transport)
+	cfg, err := config.Load()
+	if err != nil || cfg.SystemProxy {
+		transport.Proxy = sysproxy.ProxyFunc(os.Getenv)
+	}
+	if err == nil && cfg.ProxyAuth != "" {
+		if err := proxyauth.Configure(transport, cfg.ProxyAuth, os.Getenv); err != nil {
+			return exitcode.Wrap(exitcode.Usage, fmt.Errorf("proxy_auth: %w", err))
+		}
+	}
+	return nil
+}
+
+// configureTLS makes every connection trust the extra root certificates and present
+// the client certificate of the flags, or else of config.json. --insecure turns off
+// certificate verification for this command only; it is not a config.json setting.
+func configureTLS(cmd *cobra.Command) error {
+	var opts tlsconfig.Options
+	if cfg, err := config.Load(); err == nil {
+		opts = tlsconfig.Options{CACert: cfg.CACert, ClientCert: cfg.ClientCert, ClientKey: cfg.ClientKey}
+	}
+	if caCert, _ := cmd.Flags().GetString("ca-cert"); caCert != "" {
+		opts.CACert = caCert
+	}
+	if clientCert, _ := cmd.Flags().GetString("client-cert"); clientCert != "" {
+		opts.ClientCert, opts.ClientKey = clientCert, ""
+	}
+	if clientKey, _ := cmd.Flags().GetString("client-key"); clientKey != "" {
+		opts.ClientKey = clientKey
+	}
+	opts.Insecure, _ = cmd.Flags().GetBool("insecure")
+
+	tlsConfig, err := tlsconfig.Config(opts)
+	if err != nil {
+		return exitcode.Wrap(exitcode.Usage, err)
+	}
+	if tlsConfig != nil {
+		http.DefaultTransport.(*http.Transport).TLSClientConfig = tlsConfig
+	}
 	return nil
 }
 
+// configureCredentials sets up the credential helpers of config.json. GitHub clients
+// without --token, GITHUB_TOKEN or GH_TOKEN ask the helper of their host for a token; a failing helper is
+// reported and the request continues unauthenticated.
+func configureCredentials() {
+	cfg, err := config.Load()
+	if err != nil || len(cfg.CredentialHelpers) == 0 {
+		return
+	}
+	credential.Configure(cfg.CredentialHelpers)
+	warned := make(map[string]bool)
+	github.TokenHelper = func(host string) string {
+		cred, err := credential.Default.Get(context.Background(), host)
+		if err != nil && !warned[host] {
+			warned[host] = true
+			fmt.Fprint(os.Stderr, i18n.T("Warning: %v\n", err))
+		}
+		if cred == nil {
+			return ""
+		}
+		return cred.Password
+	}
+}
+
+// configureAPICache keeps GitHub API responses in the cache directory, so repeated
+// lookups are revalidated with their ETag and mostly answered with 304 Not Modified
+func configureAPICache() {
+	cacheDir, err := config.CacheDir()
+	if err != nil {
+		return
+	}
+	github.DefaultCacheDir = filepath.Join(cacheDir, "api")
+}
+
+// cacheServer is the reachable team cache server, or "" to use GitHub directly
+var cacheServer string
+
+// configureCacheServer points GitHub API requests at the cache server of
+// PYHUB_CACHE_SERVER or config.json, and makes it the first download mirror (see
+// downloadMirrors). An unreachable server is reported and skipped.
+func configureCacheServer() {
+	server := os.Getenv("PYHUB_CACHE_SERVER")
+	if server == "" {
+		cfg, err := config.Load()
+		if err != nil || cfg.CacheServer == "" {
+			return
+		}
+		server = cfg.CacheServer
+	}
+	if err := cacheproxy.Ping(&http.Client{Timeout: 2 * time.Second}, server); err != nil {
+		fmt.Fprint(os.Stderr, i18n.T("Warning: cache server unavailable, using GitHub directly: %v\n", err))
+		return
+	}
+	cacheServer = server
+	github.DefaultReleaseBaseURL = cacheproxy.APIURL(server)
+}
+
+// lowMemoryLimit is the soft memory limit of the Go runtime in low-memory mode
+const lowMemoryLimit = 32 << 20
+
+// configureLowMemory enables the low-memory mode of --low-memory or PYHUB_LOW_MEMORY:
+// downloads are streamed one at a time without parallel chunks, and the garbage
+// collector keeps the heap near lowMemoryLimit. Verification and extraction already
+// read the downloaded file in fixed-size blocks. The variable is set so that child
+// processes, e.g. of agent and serve, run in the same mode.
+func configureLowMemory(cmd *cobra.Command) {
+	lowMemory, _ := cmd.Flags().GetBool("low-memory")
+	if value := os.Getenv("PYHUB_LOW_MEMORY"); value != "" && value != "0" && value != "false" {
+		lowMemory = true
+	}
+	if !lowMemory {
+		return
+	}
+	download.Configure(download.Options{LowMemory: true})
+	debug.SetMemoryLimit(lowMemoryLimit)
+	os.Setenv("PYHUB_LOW_MEMORY", "1")
+}
+
+// configureTempDir sets the root of temporary files from PYHUB_TEMP_DIR or config.json
+// and removes the temporary files of earlier runs that crashed or were killed
+func configureTempDir() {
+	dir := os.Getenv("PYHUB_TEMP_DIR")
+	if dir == "" {
+		if cfg, err := config.Load(); err == nil {
+			dir = cfg.TempDir
+		}
+	}
+	tempdir.Configure(dir)
+	tempdir.RecoverStale()
+}
+
+// configureRetry sets the retry policy of network requests from config.json, with the
+// attempts of --retries
+func configureRetry(cmd *cobra.Command) error {
+	policy := retry.Current()
+	if cfg, err := config.Load(); err == nil {
+		baseDelay, _ := time.ParseDuration(cfg.Retry.BaseDelay)
+		maxDelay, _ := time.ParseDuration(cfg.Retry.MaxDelay)
+		policy = retry.Policy{
+			Attempts:  cfg.Retry.Attempts,
+			BaseDelay: baseDelay,
+			MaxDelay:  maxDelay,
+			Statuses:  cfg.Retry.StatusCodes,
+		}
+	}
+	if cmd.Flags().Changed("retries") {
+		retries, _ := cmd.Flags().GetInt("retries")
+		if retries < 0 {
+			return exitcode.Wrap(exitcode.Usage, fmt.Errorf("--retries cannot be negative"))
+		}
+		policy.Attempts = retries + 1
+	}
+	retry.Configure(policy)
+	return nil
+}
+
+// profiler records the phases of a command run with --profile or --profile-trace
+var profiler *profile.Profiler
+
+// finishProfile reports the phases recorded by the profiler, if any, on stderr so the
+// report does not mix with output, and writes the trace file
+func finishProfile(cmd *cobra.Command) {
+	if profiler == nil {
+		return
+	}
+	fmt.Fprintln(os.Stderr)
+	profiler.Report(os.Stderr)
+	if path, _ := cmd.Flags().GetString("profile-trace"); path != "" {
+		if err := profiler.WriteTrace(path); err != nil {
+			fmt.Fprint(os.Stderr, i18n.T("Warning: %v\n", err))
+		}
+	}
+}
+
+// notifyUpdate prints a notice if a newer pyhub-installer release exists. The check is
+// opt-in (update_check in config.json), cached for a day, and never fails a command.
+func notifyUpdate() {
+	cfg, err := config.Load()
+	if err != nil || !cfg.UpdateCheck {
+		return
+	}
+	cacheDir, err := config.CacheDir()
+	if err != nil {
+		return
+	}
+
+	client := github.NewClient(github.WithTimeout(5 * time.Second))
+	checker := update.NewChecker(filepath.Join(cacheDir, "update-check.json"), func() (string, error) {
+		release, err := client.GetLatestRelease(update.Owner, update.Repo)
+		if err != nil {
+			return "", err
+		}
+		return release.TagName, nil
+	})
+	if latest, err := checker.Check(version); err == nil && latest != "" {
+		fmt.Fprintln(os.Stderr, update.Notice(version, latest))
+	}
+}
+
 func main() {
-	if err := rootCmd.Execute(); err != nil {
+	// Set once before any request is made
+	useragent.SetVersion(version)
+
+	// Unknown commands run the plugin of the same name, like git's external commands
+	if p := findPlugin(os.Args[1:]); p != nil {
+		os.Exit(runPlugin(p, os.Args[2:]))
+	}
+
+	ctx, stop := shutdownContext()
+	defer stop()
+	defer tempdir.Cleanup()
+	if err := rootCmd.ExecuteContext(ctx); err != nil {
 		fmt.Fprintln(os.Stderr, err)
-		os.Exit(1)
+		os.Exit(exitcode.Usage)
 	}
 }
\ No newline at end of file
@@ -0,0 +1,204 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/pyhub-kr/pyhub-installer/internal/doctor"
+	"github.com/pyhub-kr/pyhub-installer/internal/exitcode"
+	"github.com/pyhub-kr/pyhub-installer/internal/github"
+	"github.com/pyhub-kr/pyhub-installer/internal/i18n"
+	"github.com/pyhub-kr/pyhub-installer/internal/platform"
+	"github.com/pyhub-kr/pyhub-installer/internal/provider"
+	"github.com/pyhub-kr/pyhub-installer/internal/toolset"
+	"github.com/pyhub-kr/pyhub-installer/internal/ui"
+	"github.com/pyhub-kr/pyhub-installer/pkg/config"
+	"github.com/spf13/cobra"
+)
+
+var manifestCmd = &cobra.Command{
+	Use:   "manifest",
+	Short: "Work with pyhub-tools.yaml project manifests",
+}
+
+var manifestValidateCmd = &cobra.Command{
+	Use:   "validate [FILE]",
+	Short: "Check that every tool of a project manifest can be installed",
+	Long: `Check a project manifest without downloading anything: its schema, and for
+every tool that the source exists, the version resolves to a release, and exactly
+one asset is selected for each platform. Run it in CI to catch broken entries
+before they break an install.
+
+Examples:
+  pyhub-installer manifest validate
+  pyhub-installer manifest validate ci/pyhub-tools.yaml --platform linux-amd64,darwin-arm64`,
+	Args: cobra.MaximumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		if err := runManifestValidate(cmd, args); err != nil {
+			fmt.Fprint(os.Stderr, i18n.T("Error: %v\n", err))
+			os.Exit(exitcode.Code(err))
+		}
+	},
+}
+
+func init() {
+	manifestValidateCmd.Flags().StringSliceP("platform", "p", nil, "Platforms to check asset selection for (default: current)")
+
+	manifestCmd.AddCommand(manifestValidateCmd)
+	rootCmd.AddCommand(manifestCmd)
+}
+
+// runManifestValidate implements the manifest validate command
+func runManifestValidate(cmd *cobra.Command, args []string) error {
+	platforms, _ := cmd.Flags().GetStringSlice("platform")
+	path := toolset.DefaultFile
+	if len(args) > 0 {
+		path = args[0]
+	}
+	if len(platforms) == 0 {
+		platforms = []string{platform.Native()}
+	}
+
+	file, err := toolset.Load(path)
+	if err != nil {
+		return err
+	}
+	cfg, err := config.Load()
+	if err != nil {
+		return err
+	}
+
+	ctx := cmd.Context()
+	failed := 0
+	for _, tool := range file.Tools {
+		r := validateTool(ctx, cfg, tool, platforms)
+		fmt.Printf("%s %s: %s\n", statusSymbol(r.Status), r.Name, r.Detail)
+		if r.Fix != "" {
+			fmt.Print(i18n.T("  Fix: %s\n", r.Fix))
+		}
+		if r.Status == doctor.Failed {
+			failed++
+		}
+	}
+
+	fmt.Println()
+	if failed > 0 {
+		return fmt.Errorf("%d of %d tools in %s cannot be installed", failed, len(file.Tools), path)
+	}
+	fmt.Print(i18n.T("%s %s is valid (%d tools)\n", ui.Success("✓"), path, len(file.Tools)))
+	return nil
+}
+
+// validateTool resolves a manifest tool and its assets without downloading. Sources of
+// a configured source chain are tried in order, like an install does.
+func validateTool(ctx context.Context, cfg *config.Config, tool toolset.Tool, platforms []string) doctor.Result {
+	result := doctor.Result{Name: fmt.Sprintf("%s@%s", tool.Source, tool.Version)}
+
+	chain := sourceChain(cfg, tool.Source)
+	if chain == nil {
+		chain = []string{tool.Source}
+	}
+
+	var failures []string
+	for _, input := range chain {
+		release, err := resolveManifestRelease(ctx, cfg, input, tool.Version)
+		if err != nil {
+			if len(chain) == 1 {
+				failures = append(failures, err.Error())
+			} else {
+				failures = append(failures, fmt.Sprintf("%s: %v", input, err))
+			}
+			continue
+		}
+
+		status, detail, fix := checkAssets(cfg, release, tool.Asset, platforms)
+		result.Status, result.Fix = status, fix
+		result.Detail = fmt.Sprintf("%s: %s", release.TagName, detail)
+		if input != tool.Source {
+			result.Detail = fmt.Sprintf("%s from %s: %s", release.TagName, input, detail)
+		}
+		if rule := findLifecycleRule(cfg, tool.Deprecations, []string{input, tool.Source}, release.TagName); rule != nil && result.Status == doctor.OK {
+			result.Status = doctor.Warning
+			result.Detail += fmt.Sprintf(" (%s)", rule.Describe())
+			result.Fix = "pin a version that is not deprecated, yanked or EOL"
+		}
+		if len(failures) > 0 && result.Status == doctor.OK {
+			result.Status = doctor.Warning
+			result.Detail += fmt.Sprintf(" (fallback; %s)", strings.Join(failures, "; "))
+			result.Fix = "fix the failing sources of the chain, or remove them"
+		}
+		return result
+	}
+
+	result.Status = doctor.Failed
+	result.Detail = strings.Join(failures, "; ")
+	result.Fix = "check that the source exists, is reachable, and publishes a release matching the version"
+	return result
+}
+
+// resolveManifestRelease resolves the release of a source and lists its assets
+func resolveManifestRelease(ctx context.Context, cfg *config.Config, input, version string) (*provider.Release, error) {
+	prov, src, err := parseSource(input)
+	if err != nil {
+		return nil, err
+	}
+	latest, err := latestPolicy(cfg, src)
+	if err != nil {
+		return nil, err
+	}
+	release, err := resolveRelease(ctx, prov, src, version, "", latest)
+	if err != nil {
+		return nil, err
+	}
+	if release.Assets, err = prov.Assets(ctx, src, release); err != nil {
+		return nil, fmt.Errorf("failed to list assets: %w", err)
+	}
+	return release, nil
+}
+
+// checkAssets reports the asset an install would select, per platform unless an asset
+// pattern selects it, and whether the selection is missing or ambiguous
+func checkAssets(cfg *config.Config, release *provider.Release, pattern string, platforms []string) (doctor.Status, string, string) {
+	if len(release.Assets) == 1 && github.IsSourceArchive(&release.Assets[0]) {
+		return doctor.OK, "source archive (no release assets)", ""
+	}
+	if pattern != "" {
+		asset, err := release.FindAssetByPattern(pattern)
+		if err != nil {
+			return doctor.Failed, err.Error(), "change the asset pattern so it matches exactly one asset"
+		}
+		return doctor.OK, asset.Name, ""
+	}
+
+	scoring := assetScoring(cfg)
+	status := doctor.OK
+	var details []string
+	fix := ""
+	for _, target := range platforms {
+		asset, err := selectPlatformAsset(release, target, scoring)
+		if err != nil {
+			status = doctor.Failed
+			details = append(details, fmt.Sprintf("%s: %v", target, err))
+			fix = "add an asset pattern, or remove the platform from the check"
+			continue
+		}
+
+		ranked, _ := release.RankAssets(target, scoring)
+		if tied := github.TiedAssets(ranked); len(tied) > 1 && containsAsset(tied, asset) {
+			names := make([]string, len(tied))
+			for i, a := range tied {
+				names[i] = a.Name
+			}
+			if status == doctor.OK {
+				status = doctor.Warning
+				fix = "add an asset pattern selecting one of the equally matching assets"
+			}
+			details = append(details, fmt.Sprintf("%s: ambiguous, %s match equally", target, strings.Join(names, ", ")))
+			continue
+		}
+		details = append(details, fmt.Sprintf("%s (%s)", asset.Name, target))
+	}
+	return status, strings.Join(details, ", "), fix
+}
@@ -0,0 +1,113 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"text/tabwriter"
+
+	"github.com/pyhub-kr/pyhub-installer/internal/exitcode"
+	"github.com/pyhub-kr/pyhub-installer/internal/i18n"
+	"github.com/pyhub-kr/pyhub-installer/internal/platform"
+	"github.com/pyhub-kr/pyhub-installer/internal/plugin"
+	"github.com/pyhub-kr/pyhub-installer/pkg/config"
+	"github.com/spf13/cobra"
+)
+
+var pluginsCmd = &cobra.Command{
+	Use:   "plugins",
+	Short: "List external commands provided by plugins on PATH",
+	Long: `List plugins: executables on PATH named pyhub-installer-<name>, which add the
+command <name>. "pyhub-installer <name> ARGS..." runs the plugin with ARGS and writes a
+JSON description of the installer (version, executable, platform, config, data, cache
+and install directories) to its standard input. Built-in commands take precedence
+over plugins of the same name.
+
+Examples:
+  pyhub-installer plugins
+  pyhub-installer org-sync --team platform   # runs pyhub-installer-org-sync`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		if err := runPlugins(cmd, args); err != nil {
+			fmt.Fprint(os.Stderr, i18n.T("Error: %v\n", err))
+			os.Exit(exitcode.Code(err))
+		}
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(pluginsCmd)
+}
+
+// runPlugins implements the plugins command
+func runPlugins(cmd *cobra.Command, args []string) error {
+	plugins := plugin.List()
+	if len(plugins) == 0 {
+		fmt.Println(i18n.T("No plugins found; add executables named pyhub-installer-<name> to PATH"))
+		return nil
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "COMMAND\tPATH")
+	for _, p := range plugins {
+		note := ""
+		if _, _, err := rootCmd.Find([]string{p.Name}); err == nil {
+			note = " (shadowed by the built-in command)"
+		}
+		fmt.Fprintf(w, "%s\t%s%s\n", p.Name, p.Path, note)
+	}
+	return w.Flush()
+}
+
+// findPlugin returns the plugin to run for the command line, or nil when it names a
+// built-in command, starts with a flag, or no plugin provides the command
+func findPlugin(args []string) *plugin.Plugin {
+	if len(args) == 0 || len(args[0]) == 0 || args[0][0] == '-' {
+		return nil
+	}
+	rootCmd.InitDefaultHelpCmd()
+	rootCmd.InitDefaultCompletionCmd()
+	if found, _, err := rootCmd.Find(args); err == nil && found != rootCmd {
+		return nil
+	}
+	p, err := plugin.Find(args[0])
+	if err != nil {
+		return nil
+	}
+	return p
+}
+
+// runPlugin runs a plugin with the arguments following its command name and returns
+// its exit code
+func runPlugin(p *plugin.Plugin, args []string) int {
+	lang, _ := i18n.Detect("", os.Getenv)
+	i18n.SetLanguage(lang)
+
+	executable, _ := os.Executable()
+	configPath, _ := config.ConfigPath()
+	dataDir, _ := config.DataDir()
+	cacheDir, _ := config.CacheDir()
+	pluginCtx := plugin.Context{
+		Version:    version,
+		Executable: executable,
+		Args:       args,
+		Platform:   platform.Native(),
+		Language:   lang,
+		ConfigPath: configPath,
+		DataDir:    dataDir,
+		CacheDir:   cacheDir,
+		InstallDir: getDefaultInstallPath(),
+	}
+	if pluginCtx.Args == nil {
+		pluginCtx.Args = []string{}
+	}
+
+	// The plugin receives Ctrl+C itself; wait for it to exit
+	signal.Ignore(os.Interrupt)
+	code, err := p.Run(context.Background(), pluginCtx, os.Stdout, os.Stderr)
+	if err != nil {
+		fmt.Fprint(os.Stderr, i18n.T("Error: %v\n", err))
+	}
+	return code
+}
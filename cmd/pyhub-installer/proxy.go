@@ -0,0 +1,111 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/pyhub-kr/pyhub-installer/internal/cacheproxy"
+	"github.com/pyhub-kr/pyhub-installer/internal/exitcode"
+	"github.com/pyhub-kr/pyhub-installer/internal/github"
+	"github.com/pyhub-kr/pyhub-installer/internal/i18n"
+	"github.com/pyhub-kr/pyhub-installer/pkg/config"
+	"github.com/spf13/cobra"
+)
+
+var proxyCmd = &cobra.Command{
+	Use:   "proxy",
+	Short: "Share a cache of GitHub releases on the local network",
+}
+
+var proxyServeCmd = &cobra.Command{
+	Use:   "serve",
+	Short: "Serve a shared cache of GitHub release metadata and assets",
+	Long: `Serve a caching proxy for GitHub release metadata and assets, so machines on a LAN
+or in a build farm download each release asset once:
+
+  GET /api/...       GitHub API for releases, tags and assets, cached for --ttl and
+                     then revalidated
+  GET /download/...  Release downloads from github.com, cached until evicted
+  GET /v1/status     Health check
+
+Point clients at the server with "cache_server" in config.json or PYHUB_CACHE_SERVER,
+e.g. http://cache.lan:7879. Clients fall back to GitHub when it is unreachable, and
+check downloads against the release checksums as for any mirror. Requests carrying
+their own credentials are passed through uncached. Anonymous API requests are made
+anonymously too, unless --share-token lends them the server's GITHUB_TOKEN (or
+GH_TOKEN, --token) for its rate limit; private repositories are then hidden from them.
+
+Examples:
+  pyhub-installer proxy serve
+  GITHUB_TOKEN=ghp_... pyhub-installer proxy serve --share-token --addr :8080 --cache-dir /srv/pyhub-cache`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		if err := runProxyServe(cmd, args); err != nil {
+			fmt.Fprint(os.Stderr, i18n.T("Error: %v\n", err))
+			os.Exit(exitcode.Code(err))
+		}
+	},
+}
+
+func init() {
+	proxyServeCmd.Flags().String("addr", ":7879", "Address to listen on")
+	proxyServeCmd.Flags().String("cache-dir", "", "Cache directory (default: proxy/ in the installer cache)")
+	proxyServeCmd.Flags().Duration("ttl", cacheproxy.DefaultMetadataTTL, "How long API responses are served before they are revalidated")
+	proxyServeCmd.Flags().Bool("share-token", false, "Make API requests of anonymous clients with the server's GitHub token, for public repositories only")
+
+	proxyCmd.AddCommand(proxyServeCmd)
+	rootCmd.AddCommand(proxyCmd)
+}
+
+// runProxyServe implements the proxy serve command
+func runProxyServe(cmd *cobra.Command, args []string) error {
+	addr, _ := cmd.Flags().GetString("addr")
+	dir, _ := cmd.Flags().GetString("cache-dir")
+	ttl, _ := cmd.Flags().GetDuration("ttl")
+	shareToken, _ := cmd.Flags().GetBool("share-token")
+
+	if ttl <= 0 {
+		return exitcode.Wrap(exitcode.Usage, fmt.Errorf("--ttl must be positive"))
+	}
+	if shareToken && github.ConfiguredToken() == "" {
+		return exitcode.Wrap(exitcode.Usage, fmt.Errorf("--share-token needs GITHUB_TOKEN, GH_TOKEN or --token"))
+	}
+	if dir == "" {
+		cacheDir, err := config.CacheDir()
+		if err != nil {
+			return err
+		}
+		dir = filepath.Join(cacheDir, "proxy")
+	}
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return fmt.Errorf("failed to create cache directory: %w", err)
+	}
+
+	ctx := cmd.Context()
+
+	server := cacheproxy.NewServer(dir)
+	if shareToken {
+		server.Token = github.ConfiguredToken()
+	}
+	server.MetadataTTL = ttl
+	server.Version = version
+
+	httpServer := &http.Server{Addr: addr, Handler: server.Handler(), ReadHeaderTimeout: 10 * time.Second}
+	go func() {
+		<-ctx.Done()
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		httpServer.Shutdown(shutdownCtx)
+	}()
+
+	fmt.Print(i18n.T("Serving the release cache on %s (cache: %s)\n", addr, dir))
+	if err := httpServer.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		return fmt.Errorf("failed to serve: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,131 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"text/tabwriter"
+	"time"
+
+	"github.com/pyhub-kr/pyhub-installer/internal/exitcode"
+	"github.com/pyhub-kr/pyhub-installer/internal/i18n"
+	"github.com/spf13/cobra"
+)
+
+var releasesCmd = &cobra.Command{
+	Use:   "releases [SOURCE]",
+	Short: "List available releases of a tool",
+	Long: `List the releases a source publishes, newest first, with their publication
+dates, pre-release flags and number of assets.
+
+Examples:
+  pyhub-installer releases cli/cli
+  pyhub-installer releases github:cli/cli --limit 5
+  pyhub-installer releases cli/cli --json
+  pyhub-installer releases rg`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		if err := runReleases(cmd, args); err != nil {
+			fmt.Fprint(os.Stderr, i18n.T("Error: %v\n", err))
+			os.Exit(exitcode.Code(err))
+		}
+	},
+}
+
+// releaseInfo is the JSON representation of a listed release
+type releaseInfo struct {
+	Tag         string    `json:"tag"`
+	Name        string    `json:"name"`
+	PublishedAt time.Time `json:"published_at"`
+	Prerelease  bool      `json:"prerelease"`
+	Draft       bool      `json:"draft"`
+	Assets      int       `json:"assets"`
+}
+
+func init() {
+	releasesCmd.Flags().IntP("limit", "n", 20, "Maximum number of releases to show (0 for all)")
+	releasesCmd.Flags().Bool("json", false, "Output as JSON")
+
+	rootCmd.AddCommand(releasesCmd)
+}
+
+// runReleases implements the releases command
+func runReleases(cmd *cobra.Command, args []string) error {
+	limit, _ := cmd.Flags().GetInt("limit")
+	jsonOutput, _ := cmd.Flags().GetBool("json")
+
+	if limit < 0 {
+		return fmt.Errorf("--limit must not be negative")
+	}
+
+	prov, src, err := parseSource(args[0])
+	if err != nil {
+		return err
+	}
+
+	ctx := cmd.Context()
+	releases, err := prov.ListVersions(ctx, src)
+	if err != nil {
+		return fmt.Errorf("failed to list releases: %w", err)
+	}
+	total := len(releases)
+	if limit > 0 && total > limit {
+		releases = releases[:limit]
+	}
+
+	infos := make([]releaseInfo, 0, len(releases))
+	for i := range releases {
+		assets, err := prov.Assets(ctx, src, &releases[i])
+		if err != nil {
+			return fmt.Errorf("failed to list assets of %s: %w", releases[i].TagName, err)
+		}
+		infos = append(infos, releaseInfo{
+			Tag:         releases[i].TagName,
+			Name:        releases[i].Name,
+			PublishedAt: releases[i].PublishedAt,
+			Prerelease:  releases[i].Prerelease,
+			Draft:       releases[i].Draft,
+			Assets:      len(assets),
+		})
+	}
+
+	if jsonOutput {
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(infos)
+	}
+
+	if len(infos) == 0 {
+		fmt.Print(i18n.T("No releases found for %s\n", src))
+		return nil
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "TAG\tPUBLISHED\tTYPE\tASSETS")
+	for _, info := range infos {
+		published := "-"
+		if !info.PublishedAt.IsZero() {
+			published = info.PublishedAt.Local().Format("2006-01-02")
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%d\n", info.Tag, published, releaseType(info), info.Assets)
+	}
+	if err := w.Flush(); err != nil {
+		return err
+	}
+	if len(infos) < total {
+		fmt.Print(i18n.T("Showing %d of %d releases; use --limit 0 to show all\n", len(infos), total))
+	}
+	return nil
+}
+
+// releaseType labels a release as stable, pre-release or draft
+func releaseType(info releaseInfo) string {
+	switch {
+	case info.Draft:
+		return "draft"
+	case info.Prerelease:
+		return "pre-release"
+	default:
+		return "stable"
+	}
+}
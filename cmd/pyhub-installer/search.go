@@ -0,0 +1,171 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+	"text/tabwriter"
+
+	"github.com/pyhub-kr/pyhub-installer/internal/audit"
+	"github.com/pyhub-kr/pyhub-installer/internal/exitcode"
+	"github.com/pyhub-kr/pyhub-installer/internal/github"
+	"github.com/pyhub-kr/pyhub-installer/internal/i18n"
+	"github.com/spf13/cobra"
+)
+
+var searchCmd = &cobra.Command{
+	Use:   "search QUERY...",
+	Short: "Search GitHub for tools with binary releases",
+	Long: `Search GitHub repositories, most stars first, and show those whose latest
+release has assets to install, with their stars and latest tag. The query may use
+GitHub's search qualifiers, e.g. language:go or topic:cli.
+
+--quiet prints just the sources, one per line, for install to read with "-".
+
+Examples:
+  pyhub-installer search ripgrep
+  pyhub-installer search json language:go --limit 5
+  pyhub-installer search ripgrep --quiet --limit 1 | pyhub-installer install -`,
+	Args: cobra.MinimumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		if err := runSearch(cmd, args); err != nil {
+			fmt.Fprint(os.Stderr, i18n.T("Error: %v\n", err))
+			os.Exit(exitcode.Code(err))
+		}
+	},
+}
+
+// searchResult is the JSON representation of a found repository
+type searchResult struct {
+	Source      string `json:"source"`
+	Description string `json:"description,omitempty"`
+	Stars       int    `json:"stars"`
+	Latest      string `json:"latest"`
+	Assets      int    `json:"assets"`
+	URL         string `json:"url"`
+}
+
+// searchCandidates is how many repositories are searched per result shown, as many
+// repositories publish no binary releases
+const searchCandidates = 3
+
+func init() {
+	searchCmd.Flags().IntP("limit", "n", 10, "Maximum number of repositories to show")
+	searchCmd.Flags().BoolP("quiet", "q", false, "Print only the sources, one per line")
+	searchCmd.Flags().Bool("json", false, "Output as JSON")
+
+	rootCmd.AddCommand(searchCmd)
+}
+
+// runSearch implements the search command
+func runSearch(cmd *cobra.Command, args []string) error {
+	limit, _ := cmd.Flags().GetInt("limit")
+	quiet, _ := cmd.Flags().GetBool("quiet")
+	jsonOutput, _ := cmd.Flags().GetBool("json")
+
+	if limit < 1 {
+		return exitcode.Wrap(exitcode.Usage, fmt.Errorf("--limit must be at least 1"))
+	}
+	if quiet && jsonOutput {
+		return exitcode.Wrap(exitcode.Usage, fmt.Errorf("--quiet cannot be combined with --json"))
+	}
+
+	client := github.NewClient()
+	query := strings.Join(args, " ")
+	repos, err := client.SearchRepositories(query, limit*searchCandidates)
+	if err != nil {
+		return err
+	}
+	results := releasedRepositories(client, repos, limit)
+
+	switch {
+	case jsonOutput:
+		if results == nil {
+			results = []searchResult{}
+		}
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(results)
+	case quiet:
+		for _, result := range results {
+			fmt.Println(result.Source)
+		}
+		return nil
+	}
+
+	if len(results) == 0 {
+		fmt.Print(i18n.T("No repositories with binary releases found for %q\n", query))
+		return nil
+	}
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "SOURCE\tSTARS\tLATEST\tDESCRIPTION")
+	for _, result := range results {
+		fmt.Fprintf(w, "%s\t%d\t%s\t%s\n", result.Source, result.Stars, result.Latest, truncate(result.Description, 60))
+	}
+	return w.Flush()
+}
+
+// releasedRepositories looks up the latest release of each repository, a few at a
+// time, and returns up to limit of those with installable assets, in search order.
+// Archived repositories and repositories without releases are left out.
+func releasedRepositories(client *github.Client, repos []github.Repository, limit int) []searchResult {
+	found := make([]*searchResult, len(repos))
+	var wg sync.WaitGroup
+	sem := make(chan struct{}, 4)
+	for i, repo := range repos {
+		if repo.Archived {
+			continue
+		}
+		owner, name, ok := strings.Cut(repo.FullName, "/")
+		if !ok {
+			continue
+		}
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(i int, repo github.Repository) {
+			defer wg.Done()
+			defer func() { <-sem }()
+			release, err := client.GetLatestRelease(owner, name)
+			if err != nil {
+				return
+			}
+			assets := 0
+			for _, asset := range release.Assets {
+				if !audit.IsMetadata(asset.Name) {
+					assets++
+				}
+			}
+			if assets == 0 {
+				return
+			}
+			found[i] = &searchResult{
+				Source:      "github:" + repo.FullName,
+				Description: repo.Description,
+				Stars:       repo.Stars,
+				Latest:      release.TagName,
+				Assets:      assets,
+				URL:         repo.HTMLURL,
+			}
+		}(i, repo)
+	}
+	wg.Wait()
+
+	var results []searchResult
+	for _, result := range found {
+		if result != nil && len(results) < limit {
+			results = append(results, *result)
+		}
+	}
+	return results
+}
+
+// truncate shortens s to at most n runes, marking the cut with an ellipsis
+func truncate(s string, n int) string {
+	runes := []rune(s)
+	if len(runes) <= n {
+		return s
+	}
+	return string(runes[:n-1]) + "…"
+}
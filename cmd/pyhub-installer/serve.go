@@ -0,0 +1,151 @@
+package main
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"os"
+	"os/exec"
+	"time"
+
+	"github.com/pyhub-kr/pyhub-installer/internal/exitcode"
+	"github.com/pyhub-kr/pyhub-installer/internal/i18n"
+	"github.com/pyhub-kr/pyhub-installer/internal/serve"
+	"github.com/pyhub-kr/pyhub-installer/internal/state"
+	"github.com/spf13/cobra"
+)
+
+var serveCmd = &cobra.Command{
+	Use:   "serve",
+	Short: "Serve a local REST API for installs",
+	Long: `Serve a REST API so orchestration agents and GUIs can drive installations:
+
+  GET  /v1/status         Installer version and number of running jobs
+  GET  /v1/tools          Installed tools
+  POST /v1/install        Start an install job: {"targets": ["rg"], "version": "", "output": ""}
+  POST /v1/upgrade        Start an upgrade job: {"tools": ["rg"]} (empty for all tools)
+  GET  /v1/jobs           All jobs
+  GET  /v1/jobs/{id}      Status and exit code of a job
+  GET  /v1/jobs/{id}/log  Output of a job, streamed until it finishes
+
+Every request needs "Authorization: Bearer <token>". The API listens on localhost by
+default and generates a token at startup unless --api-token (or PYHUB_INSTALLER_TOKEN) is
+set; a token is required on other addresses. Requests with an Origin header, and POSTs
+that are not application/json, are rejected, so web pages cannot use the API.`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		if err := runServe(cmd, args); err != nil {
+			fmt.Fprint(os.Stderr, i18n.T("Error: %v\n", err))
+			os.Exit(exitcode.Code(err))
+		}
+	},
+}
+
+func init() {
+	serveCmd.Flags().String("addr", "127.0.0.1:7878", "Address to listen on")
+	serveCmd.Flags().String("api-token", os.Getenv("PYHUB_INSTALLER_TOKEN"), "Bearer token clients must send (the global --token is the GitHub token)")
+
+	rootCmd.AddCommand(serveCmd)
+}
+
+// runServe implements the serve command
+func runServe(cmd *cobra.Command, args []string) error {
+	addr, _ := cmd.Flags().GetString("addr")
+	token, _ := cmd.Flags().GetString("api-token")
+
+	host, _, err := net.SplitHostPort(addr)
+	if err != nil {
+		return exitcode.Wrap(exitcode.Usage, fmt.Errorf("invalid --addr: %w", err))
+	}
+	local := serve.IsLoopback(host)
+	if token == "" && !local {
+		return exitcode.Wrap(exitcode.Usage, fmt.Errorf("refusing to serve on %s without --api-token", addr))
+	}
+	generated := token == ""
+	if generated {
+		if token, err = newToken(); err != nil {
+			return err
+		}
+	}
+
+	// Jobs run in child processes, which get the GitHub token through the environment
+	if githubToken, _ := cmd.Root().PersistentFlags().GetString("token"); githubToken != "" {
+		os.Setenv("GITHUB_TOKEN", githubToken)
+	}
+
+	ctx := cmd.Context()
+
+	server := serve.NewServer(ctx, runSelf, installedReceipts)
+	server.Token = token
+	server.Local = local
+	server.Version = version
+
+	httpServer := &http.Server{Addr: addr, Handler: server.Handler(), ReadHeaderTimeout: 10 * time.Second}
+	go func() {
+		<-ctx.Done()
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		httpServer.Shutdown(shutdownCtx)
+	}()
+
+	fmt.Print(i18n.T("Serving the installer API on http://%s\n", addr))
+	if generated {
+		fmt.Print(i18n.T("Token: %s\n", token))
+	}
+	if err := httpServer.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		return fmt.Errorf("failed to serve: %w", err)
+	}
+	return nil
+}
+
+// newToken returns a random bearer token for a server started without --api-token
+func newToken() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("failed to generate a token: %w", err)
+	}
+	return hex.EncodeToString(b), nil
+}
+
+// runSelf runs an installer command in a child process, so jobs have their own output
+// and cannot affect the server
+func runSelf(ctx context.Context, args []string, out io.Writer) int {
+	executable, err := os.Executable()
+	if err != nil {
+		fmt.Fprintf(out, "failed to locate the installer executable: %v\n", err)
+		return exitcode.General
+	}
+
+	// Global flags go first, as job arguments end with "--" and targets
+	cmdArgs := append([]string{"--no-color", "--no-progress", "--no-update-check", "--non-interactive"}, args...)
+	child := exec.CommandContext(ctx, executable, cmdArgs...)
+	child.Stdout = out
+	child.Stderr = out
+	if err := child.Run(); err != nil {
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) && exitErr.ExitCode() > 0 {
+			return exitErr.ExitCode()
+		}
+		fmt.Fprintf(out, "%v\n", err)
+		return exitcode.General
+	}
+	return exitcode.OK
+}
+
+// installedReceipts returns the receipts of the installed tools
+func installedReceipts() ([]state.Receipt, error) {
+	db, err := state.DefaultDB()
+	if err != nil {
+		return nil, err
+	}
+	s, err := db.Load()
+	if err != nil {
+		return nil, err
+	}
+	return s.Receipts, nil
+}
@@ -0,0 +1,36 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/pyhub-kr/pyhub-installer/internal/i18n"
+)
+
+// shutdownContext returns the context shared by all commands. The first SIGINT or
+// SIGTERM cancels it, so that downloads and extractions stop, partial installs are
+// rolled back and interrupted downloads are kept for resuming; a second one exits
+// immediately.
+func shutdownContext() (context.Context, context.CancelFunc) {
+	ctx, cancel := context.WithCancel(context.Background())
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, os.Interrupt, syscall.SIGTERM)
+	go func() {
+		select {
+		case <-signals:
+		case <-ctx.Done():
+			return
+		}
+		// Restore the default handling, so that the next signal terminates
+		signal.Stop(signals)
+		fmt.Fprint(os.Stderr, i18n.T("\nInterrupted; cleaning up (press Ctrl+C again to quit immediately)\n"))
+		cancel()
+	}()
+	return ctx, func() {
+		signal.Stop(signals)
+		cancel()
+	}
+}
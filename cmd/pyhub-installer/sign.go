@@ -0,0 +1,93 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/pyhub-kr/pyhub-installer/internal/exitcode"
+	"github.com/pyhub-kr/pyhub-installer/internal/i18n"
+	"github.com/pyhub-kr/pyhub-installer/internal/sign"
+	"github.com/pyhub-kr/pyhub-installer/internal/ui"
+	"github.com/spf13/cobra"
+)
+
+var signCmd = &cobra.Command{
+	Use:   "sign FILE...",
+	Short: "Write checksum files and signatures for release assets",
+	Long: `Prepare release assets for the installer: --sha256 writes FILE.sha256 for each
+file and a SHA256SUMS listing all of them, --minisign-key writes minisign signatures
+(FILE.minisig) with the minisign tool, and --gpg writes ASCII-armored detached
+signatures (FILE.sig) with gpg. With --sha256, SHA256SUMS is signed as well. Upload
+the written files next to the assets.
+
+Examples:
+  pyhub-installer sign dist/* --sha256
+  pyhub-installer sign dist/*.tar.gz dist/*.zip --sha256 --gpg 0xABCD1234 -o dist`,
+	Args: cobra.MinimumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		if err := runSign(cmd, args); err != nil {
+			fmt.Fprint(os.Stderr, i18n.T("Error: %v\n", err))
+			os.Exit(exitcode.Code(err))
+		}
+	},
+}
+
+func init() {
+	signCmd.Flags().Bool("sha256", false, "Write FILE.sha256 files and SHA256SUMS")
+	signCmd.Flags().String("minisign-key", "", "Sign with this minisign secret key file")
+	signCmd.Flags().String("gpg", "", "Sign with this GnuPG key ID")
+	signCmd.Flags().StringP("output", "o", ".", "Directory for the written files")
+
+	rootCmd.AddCommand(signCmd)
+}
+
+// runSign implements the sign command
+func runSign(cmd *cobra.Command, args []string) error {
+	sha256, _ := cmd.Flags().GetBool("sha256")
+	minisignKey, _ := cmd.Flags().GetString("minisign-key")
+	gpgKey, _ := cmd.Flags().GetString("gpg")
+	output, _ := cmd.Flags().GetString("output")
+
+	if !sha256 && minisignKey == "" && gpgKey == "" {
+		return exitcode.Wrap(exitcode.Usage, fmt.Errorf("nothing to do; pass --sha256, --minisign-key or --gpg"))
+	}
+	for _, file := range args {
+		if info, err := os.Stat(file); err != nil || info.IsDir() {
+			return exitcode.Wrap(exitcode.NotFound, fmt.Errorf("%s is not a file", file))
+		}
+	}
+	if err := os.MkdirAll(output, 0755); err != nil {
+		return fmt.Errorf("failed to create output directory: %w", err)
+	}
+
+	files := args
+	if sha256 {
+		written, err := sign.WriteChecksums(args, output)
+		for _, path := range written {
+			fmt.Printf("%s %s\n", ui.Success("✓"), path)
+		}
+		if err != nil {
+			return err
+		}
+		files = append(files[:len(files):len(files)], filepath.Join(output, sign.SumsFile))
+	}
+
+	for _, file := range files {
+		if minisignKey != "" {
+			path, err := sign.Minisign(file, minisignKey, output)
+			if err != nil {
+				return err
+			}
+			fmt.Printf("%s %s\n", ui.Success("✓"), path)
+		}
+		if gpgKey != "" {
+			path, err := sign.GPG(file, gpgKey, output)
+			if err != nil {
+				return err
+			}
+			fmt.Printf("%s %s\n", ui.Success("✓"), path)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,204 @@
+package main
+
+import (
+	"fmt"
+	"maps"
+	"os"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/pyhub-kr/pyhub-installer/internal/exitcode"
+	"github.com/pyhub-kr/pyhub-installer/internal/i18n"
+	"github.com/pyhub-kr/pyhub-installer/internal/install"
+	"github.com/pyhub-kr/pyhub-installer/internal/lock"
+	"github.com/pyhub-kr/pyhub-installer/internal/platform"
+	"github.com/pyhub-kr/pyhub-installer/internal/profile"
+	"github.com/pyhub-kr/pyhub-installer/internal/toolset"
+	"github.com/pyhub-kr/pyhub-installer/internal/ui"
+	"github.com/pyhub-kr/pyhub-installer/pkg/config"
+	"github.com/spf13/cobra"
+)
+
+var syncCmd = &cobra.Command{
+	Use:   "sync",
+	Short: "Install the tools declared in pyhub-tools.yaml",
+	Long: `Install or update every tool declared in a project manifest so the installed
+versions match it. Tools whose resolved release is already installed are skipped.
+
+If a lockfile (pyhub-tools.lock, written by freeze) is next to the manifest, tools
+are installed at their locked versions, and on the locked platform with the locked
+assets, which must match the locked checksums.
+
+Example pyhub-tools.yaml:
+  output: ./bin
+  tools:
+    - source: rg
+      version: "^14"
+    - source: github:cli/cli
+      version: v2.62.0
+      asset: "gh_*_linux_amd64.tar.gz"
+      output: ~/.local/bin
+
+Examples:
+  pyhub-installer sync
+  pyhub-installer sync --file ci/pyhub-tools.yaml`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		if err := runSync(cmd, args); err != nil {
+			fmt.Fprint(os.Stderr, i18n.T("Error: %v\n", err))
+			os.Exit(exitcode.Code(err))
+		}
+	},
+}
+
+func init() {
+	syncCmd.Flags().StringP("file", "f", toolset.DefaultFile, "Project manifest to install from")
+	syncCmd.Flags().StringP("platform", "p", "", "Target platform (auto-detect if not specified)")
+	syncCmd.Flags().IntP("jobs", "j", 4, "Maximum number of tools resolved and downloaded concurrently")
+	syncCmd.Flags().Bool("system", false, "Install tools without an output to the system directory ("+install.SystemInstallPath()+")")
+	syncCmd.Flags().Bool("refuse-deprecated", false, "Fail tools whose version is marked deprecated, yanked or EOL instead of warning")
+
+	rootCmd.AddCommand(syncCmd)
+}
+
+// runSync implements the sync command
+func runSync(cmd *cobra.Command, args []string) error {
+	defer finishProfile(cmd)
+	path, _ := cmd.Flags().GetString("file")
+	target, _ := cmd.Flags().GetString("platform")
+	jobs, _ := cmd.Flags().GetInt("jobs")
+	refuseDeprecated, _ := cmd.Flags().GetBool("refuse-deprecated")
+	system, _ := cmd.Flags().GetBool("system")
+
+	file, err := toolset.Load(path)
+	if err != nil {
+		return err
+	}
+	if len(file.Tools) == 0 {
+		fmt.Print(i18n.T("No tools declared in %s\n", path))
+		return nil
+	}
+
+	lockfile, err := toolset.LoadLock(toolset.LockPath(path))
+	if err != nil {
+		return err
+	}
+
+	cfg, err := config.Load()
+	if err != nil {
+		return err
+	}
+
+	lockPlatform := target
+	if lockPlatform == "" {
+		lockPlatform = platform.Native()
+	}
+	targets := make([]*installJob, 0, len(file.Tools))
+	for _, tool := range file.Tools {
+		output := file.OutputFor(tool)
+		if output == "" && system {
+			output = install.SystemInstallPath()
+		} else if output == "" {
+			output = getDefaultInstallPath()
+		}
+		job := &installJob{
+			Input:        tool.Source,
+			Version:      tool.Version,
+			Output:       output,
+			AssetPattern: tool.Asset,
+			Deprecations: tool.Deprecations,
+		}
+		if locked := lockfile.Find(tool.Source); locked != nil {
+			if !toolset.Allows(tool.Version, locked.Version) {
+				fmt.Print(i18n.T("Warning: %s is locked to %s, which %s no longer allows; ignoring the lock\n", tool.Source, locked.Version, tool.Version))
+			} else {
+				job.Version = locked.Version
+				if lockfile.Platform == lockPlatform && locked.Asset != "" {
+					job.AssetPattern = globEscape(locked.Asset)
+					job.SHA256 = locked.SHA256
+				}
+			}
+		}
+		targets = append(targets, job)
+	}
+
+	// Lock every install directory up front, in a fixed order, so the tools of all
+	// directories are resolved and downloaded by one concurrent pipeline
+	locks, err := prepareOutputs(targets, system)
+	for _, dirLock := range locks {
+		defer dirLock.Release()
+	}
+	if err != nil {
+		return err
+	}
+
+	opts := installOptions{
+		Platform:         target,
+		SkipCurrent:      true,
+		RefuseDeprecated: refuseDeprecated,
+		Jobs:             jobs,
+		Config:           cfg,
+	}
+	if err := installJobs(profile.WithProfiler(cmd.Context(), profiler), targets, opts); err != nil {
+		return err
+	}
+
+	fmt.Print(i18n.T("%s %d tools match %s\n", ui.Success("✓"), len(file.Tools), path))
+	return nil
+}
+
+// globEscape quotes the glob metacharacters of an asset name, so that it only matches itself
+func globEscape(name string) string {
+	var b strings.Builder
+	for _, r := range name {
+		if strings.ContainsRune(`*?[\`, r) {
+			b.WriteByte('\\')
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
+
+// prepareOutputs creates and locks the install directories of jobs, replacing each
+// job's directory by the one prepared. Every directory is resolved before any is
+// locked, as different requested directories can resolve to the same one, e.g. the
+// default directory to ~/.local/bin. The returned locks must be released even if an
+// error is returned.
+func prepareOutputs(jobs []*installJob, system bool) ([]*lock.Lock, error) {
+	dirs := make(map[string]string)
+	for _, j := range jobs {
+		dirs[j.Output] = ""
+	}
+	requested := make([]string, 0, len(dirs))
+	for dir := range dirs {
+		requested = append(requested, dir)
+	}
+	sort.Strings(requested)
+
+	resolved := make(map[string]bool)
+	for _, dir := range requested {
+		output, err := resolveOutput(dir, system && dir == install.SystemInstallPath())
+		if err != nil {
+			return nil, err
+		}
+		dirs[dir] = output
+		resolved[output] = true
+	}
+
+	// Lock in a fixed order, so processes syncing overlapping directories cannot
+	// wait for each other
+	var locks []*lock.Lock
+	for _, output := range slices.Sorted(maps.Keys(resolved)) {
+		dirLock, err := lockOutput(output)
+		if err != nil {
+			return locks, err
+		}
+		locks = append(locks, dirLock)
+	}
+
+	for _, j := range jobs {
+		j.Output = dirs[j.Output]
+	}
+	return locks, nil
+}
@@ -0,0 +1,161 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/pyhub-kr/pyhub-installer/internal/exitcode"
+	"github.com/pyhub-kr/pyhub-installer/internal/hooks"
+	"github.com/pyhub-kr/pyhub-installer/internal/i18n"
+	"github.com/pyhub-kr/pyhub-installer/internal/lock"
+	"github.com/pyhub-kr/pyhub-installer/internal/state"
+	"github.com/pyhub-kr/pyhub-installer/internal/ui"
+	"github.com/pyhub-kr/pyhub-installer/pkg/config"
+	"github.com/spf13/cobra"
+)
+
+var uninstallCmd = &cobra.Command{
+	Use:   "uninstall TOOL...",
+	Short: "Remove installed tools",
+	Long: `Remove installed tools by their installed name or alias: the files recorded
+when they were installed, every version kept in the store with its links and shims,
+and the aliases created for them. The tools are then removed from the installed
+tools, and post-uninstall hooks run.
+
+Examples:
+  pyhub-installer uninstall rg
+  pyhub-installer uninstall gh fd`,
+	Args: cobra.MinimumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		if err := runUninstall(cmd, args); err != nil {
+			fmt.Fprint(os.Stderr, i18n.T("Error: %v\n", err))
+			os.Exit(exitcode.Code(err))
+		}
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(uninstallCmd)
+}
+
+// runUninstall implements the uninstall command
+func runUninstall(cmd *cobra.Command, args []string) error {
+	cfg, err := config.Load()
+	if err != nil {
+		return err
+	}
+	storeDir, err := config.StoreDir()
+	if err != nil {
+		return err
+	}
+	db, err := state.DefaultDB()
+	if err != nil {
+		return err
+	}
+
+	installed, err := db.Load()
+	if err != nil {
+		return err
+	}
+	var receipts []state.Receipt
+	for _, tool := range args {
+		receipt := findInstalled(installed, tool)
+		if receipt == nil {
+			return exitcode.Wrap(exitcode.NotFound, fmt.Errorf("%s is not installed", tool))
+		}
+		receipts = append(receipts, *receipt)
+	}
+
+	// Files are removed before the database is locked, as installs lock their
+	// directory first and the database second
+	var removed []state.Receipt
+	var failed error
+	for _, receipt := range receipts {
+		if err := removeInstalledFiles(receipt, storeDir); err != nil {
+			failed = err
+			break
+		}
+		removed = append(removed, receipt)
+	}
+	err = db.Update(func(s *state.State) error {
+		for _, receipt := range removed {
+			if err := removeAliasFiles(s.RemoveAliasesOf(receipt.Name)); err != nil {
+				return err
+			}
+			s.Remove(receipt.Name)
+			fmt.Print(i18n.T("%s Uninstalled %s %s\n", ui.Success("✓"), receipt.Name, receipt.Version))
+		}
+		return nil
+	})
+	if err == nil {
+		err = failed
+	}
+
+	runner := hooks.NewRunner(cfg.Hooks)
+	for _, receipt := range removed {
+		hookContext := hooks.Context{
+			Tool:        receipt.Name,
+			Source:      receipt.Source,
+			Version:     receipt.Version,
+			Asset:       receipt.Asset,
+			URL:         receipt.URL,
+			InstallPath: receipt.InstallPath,
+		}
+		if err := runner.Run(cmd.Context(), hooks.PostUninstall, hookContext); err != nil {
+			fmt.Print(i18n.T("Warning: %v\n", err))
+		}
+	}
+	return err
+}
+
+// removeInstalledFiles deletes what an install of a tool created: its recorded files,
+// the download kept in the install directory, and its versions in the store. Install
+// directories are locked while their files are removed, and directories left empty
+// are removed too.
+func removeInstalledFiles(receipt state.Receipt, storeDir string) error {
+	if receipt.InstallPath != "" {
+		if _, err := os.Stat(receipt.InstallPath); err == nil {
+			dirLock, err := lock.AcquireDir(receipt.InstallPath)
+			if err != nil {
+				return err
+			}
+			defer dirLock.Release()
+		}
+	}
+
+	files := receipt.Files
+	if len(files) == 0 && !underDir(receipt.InstallPath, storeDir) {
+		fmt.Print(i18n.T("Note: the files of %s were not recorded by the installer version that installed it; check %s for leftovers\n", receipt.Name, receipt.InstallPath))
+	}
+	if receipt.Asset != "" && receipt.InstallPath != "" {
+		files = append(files, filepath.Join(receipt.InstallPath, receipt.Asset))
+	}
+	for _, file := range files {
+		if underDir(file, storeDir) {
+			continue // Removed with the tool's store directory
+		}
+		if err := os.Remove(file); err != nil && !os.IsNotExist(err) {
+			return fmt.Errorf("failed to remove %s: %w", file, err)
+		}
+		removeEmptyParents(filepath.Dir(file), receipt.InstallPath)
+	}
+
+	if receipt.Name != "" {
+		if err := os.RemoveAll(filepath.Join(storeDir, receipt.Name)); err != nil {
+			return fmt.Errorf("failed to remove %s from the store: %w", receipt.Name, err)
+		}
+	}
+	return nil
+}
+
+// removeEmptyParents removes dir and its parents while they are empty, up to but not
+// including root
+func removeEmptyParents(dir, root string) {
+	for underDir(dir, root) && filepath.Clean(dir) != filepath.Clean(root) {
+		if os.Remove(dir) != nil {
+			return
+		}
+		dir = filepath.Dir(dir)
+	}
+}
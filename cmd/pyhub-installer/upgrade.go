@@ -0,0 +1,143 @@
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/pyhub-kr/pyhub-installer/internal/agent"
+	"github.com/pyhub-kr/pyhub-installer/internal/exitcode"
+	"github.com/pyhub-kr/pyhub-installer/internal/i18n"
+	"github.com/pyhub-kr/pyhub-installer/internal/profile"
+	"github.com/pyhub-kr/pyhub-installer/internal/ui"
+	"github.com/pyhub-kr/pyhub-installer/pkg/config"
+	"github.com/spf13/cobra"
+)
+
+var upgradeCmd = &cobra.Command{
+	Use:   "upgrade [TOOL...]",
+	Short: "Upgrade installed tools to their latest releases",
+	Long: `Check installed tools for newer releases and install the outdated ones again,
+into the directory they were installed to. Tools are named by their installed name
+or alias; --all upgrades every installed tool.
+
+Update policies in config.json apply as for the agent: a tool under "repos" with
+"update": "pinned" is never upgraded, "minor" and "patch" stay within the installed
+major or minor version.
+
+Examples:
+  pyhub-installer upgrade rg
+  pyhub-installer upgrade --all
+  pyhub-installer upgrade --all --dry-run`,
+	Run: func(cmd *cobra.Command, args []string) {
+		if err := runUpgrade(cmd, args); err != nil {
+			fmt.Fprint(os.Stderr, i18n.T("Error: %v\n", err))
+			os.Exit(exitcode.Code(err))
+		}
+	},
+}
+
+func init() {
+	upgradeCmd.Flags().Bool("all", false, "Upgrade every installed tool")
+	upgradeCmd.Flags().Bool("dry-run", false, "List outdated tools without upgrading them")
+	upgradeCmd.Flags().IntP("jobs", "j", 4, "Number of tools resolved and downloaded concurrently")
+
+	rootCmd.AddCommand(upgradeCmd)
+}
+
+// runUpgrade implements the upgrade command
+func runUpgrade(cmd *cobra.Command, args []string) error {
+	defer finishProfile(cmd)
+	all, _ := cmd.Flags().GetBool("all")
+	dryRun, _ := cmd.Flags().GetBool("dry-run")
+	jobs, _ := cmd.Flags().GetInt("jobs")
+
+	if all == (len(args) > 0) {
+		return exitcode.Wrap(exitcode.Usage, fmt.Errorf("name the tools to upgrade or pass --all"))
+	}
+
+	cfg, err := config.Load()
+	if err != nil {
+		return err
+	}
+	installed := loadState()
+	if installed == nil || len(installed.Receipts) == 0 {
+		fmt.Println(i18n.T("No installed tools to update"))
+		return nil
+	}
+
+	receipts := installed.Receipts
+	if !all {
+		receipts = nil
+		for _, tool := range args {
+			receipt := findInstalled(installed, tool)
+			if receipt == nil {
+				return exitcode.Wrap(exitcode.NotFound, fmt.Errorf("%s is not installed", tool))
+			}
+			receipts = append(receipts, *receipt)
+		}
+	}
+
+	ctx := profile.WithProfiler(cmd.Context(), profiler)
+	results := agent.Check(ctx, receipts, cfg.UpdatePolicyFor, releaseResolver(cfg))
+
+	failed := 0
+	var targets []*installJob
+	for _, result := range results {
+		switch result.Status {
+		case agent.Available:
+			if dryRun {
+				fmt.Print(i18n.T("%s %s: %s -> %s available\n", ui.Warn("!"), result.Tool, result.From, result.To))
+				break
+			}
+			fmt.Print(i18n.T("Upgrading %s: %s -> %s\n", result.Tool, result.From, result.To))
+			job, err := upgradeJob(cfg, result)
+			if err != nil {
+				return err
+			}
+			targets = append(targets, job)
+		case agent.Failed:
+			failed++
+			fmt.Print(i18n.T("%s %s: %v\n", ui.Failure("✗"), result.Tool, result.Err))
+		case agent.UpToDate:
+			fmt.Print(i18n.T("%s %s %s is up to date\n", ui.Success("✓"), result.Tool, result.From))
+		case agent.Skipped:
+			fmt.Print(i18n.T("%s %s %s is pinned or has no source; skipped\n", ui.Warn("!"), result.Tool, result.From))
+		}
+	}
+
+	if len(targets) > 0 {
+		locks, err := prepareOutputs(targets, false)
+		for _, dirLock := range locks {
+			defer dirLock.Release()
+		}
+		if err != nil {
+			return err
+		}
+
+		opts := installOptions{Jobs: jobs, Config: cfg}
+		if err := installJobs(ctx, targets, opts); err != nil {
+			return err
+		}
+		fmt.Print(i18n.T("%s Upgraded %d tools\n", ui.Success("✓"), len(targets)))
+	}
+
+	if failed > 0 {
+		return exitcode.Wrap(exitcode.Partial, fmt.Errorf("%d of %d tools could not be checked for upgrades", failed, len(results)))
+	}
+	return nil
+}
+
+// upgradeJob returns the install of the release a checked tool is upgraded to, into
+// the directory, layout and release channel of its installed version, built from source
+// if it was
+func upgradeJob(cfg *config.Config, result agent.Result) (*installJob, error) {
+	output := result.InstallPath
+	if output == "" {
+		output = getDefaultInstallPath()
+	}
+	output, storeLayout, err := storeLayoutOutput(cfg, output, true)
+	if err != nil {
+		return nil, err
+	}
+	return &installJob{Input: result.Source, Version: result.To, Output: output, StoreLayout: storeLayout, Channel: result.Channel, BuildFromSource: result.Built}, nil
+}
@@ -0,0 +1,124 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/pyhub-kr/pyhub-installer/internal/exitcode"
+	"github.com/pyhub-kr/pyhub-installer/internal/i18n"
+	"github.com/pyhub-kr/pyhub-installer/internal/state"
+	"github.com/pyhub-kr/pyhub-installer/internal/store"
+	"github.com/pyhub-kr/pyhub-installer/internal/ui"
+	"github.com/pyhub-kr/pyhub-installer/pkg/config"
+	"github.com/spf13/cobra"
+)
+
+var useCmd = &cobra.Command{
+	Use:   "use TOOL [VERSION]",
+	Short: "Switch a tool to another installed version",
+	Long: `Make another installed version of a tool the active one, without downloading:
+its current link and its shims and links are repointed to the version, each replaced
+atomically. Without VERSION, the installed versions are listed. This applies to tools
+installed in versioned directories of the store, such as Scoop manifests.
+
+Examples:
+  pyhub-installer use mytool
+  pyhub-installer use mytool 1.2.0`,
+	Args: cobra.RangeArgs(1, 2),
+	Run: func(cmd *cobra.Command, args []string) {
+		if err := runUse(cmd, args); err != nil {
+			fmt.Fprint(os.Stderr, i18n.T("Error: %v\n", err))
+			os.Exit(exitcode.Code(err))
+		}
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(useCmd)
+}
+
+// runUse implements the use command
+func runUse(cmd *cobra.Command, args []string) error {
+	storeDir, err := config.StoreDir()
+	if err != nil {
+		return err
+	}
+	db, err := state.DefaultDB()
+	if err != nil {
+		return err
+	}
+
+	return db.Update(func(s *state.State) error {
+		receipt := findInstalled(s, args[0])
+		if receipt == nil {
+			return exitcode.Wrap(exitcode.NotFound, fmt.Errorf("%s is not installed", args[0]))
+		}
+		toolDir := filepath.Join(storeDir, receipt.Name)
+		versions, err := store.Versions(toolDir)
+		if err != nil || len(versions) == 0 {
+			return exitcode.Wrap(exitcode.NotFound, fmt.Errorf("%s is not installed in versioned directories of %s", receipt.Name, storeDir))
+		}
+
+		if len(args) == 1 {
+			for _, v := range versions {
+				marker := "  "
+				if v == receipt.Version {
+					marker = "* "
+				}
+				fmt.Println(marker + v)
+			}
+			return nil
+		}
+
+		version := matchVersion(versions, args[1])
+		if version == "" {
+			return exitcode.Wrap(exitcode.NotFound, fmt.Errorf("%s %s is not installed (installed: %s)", receipt.Name, args[1], strings.Join(versions, ", ")))
+		}
+		if version == receipt.Version {
+			fmt.Print(i18n.T("%s is already using %s\n", receipt.Name, version))
+			return nil
+		}
+		return switchVersion(receipt, toolDir, version)
+	})
+}
+
+// matchVersion returns the installed version named by want, with or without a leading v
+func matchVersion(versions []string, want string) string {
+	for _, v := range versions {
+		if v == want || strings.TrimPrefix(v, "v") == strings.TrimPrefix(want, "v") {
+			return v
+		}
+	}
+	return ""
+}
+
+// switchVersion repoints the links and shims of an installed tool from its version
+// directory to another one, then the current link, and updates the receipt
+func switchVersion(receipt *state.Receipt, toolDir, version string) error {
+	from := filepath.Join(toolDir, receipt.Version)
+	to := filepath.Join(toolDir, version)
+
+	files := make([]string, 0, len(receipt.Files))
+	for _, file := range receipt.Files {
+		if file == from {
+			files = append(files, to)
+			continue
+		}
+		files = append(files, file)
+		if _, err := store.Repoint(file, from, to); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+	}
+	if err := store.SetCurrent(toolDir, version); err != nil {
+		return err
+	}
+
+	// The download details describe the version installed last, not this one
+	receipt.Version = version
+	receipt.Files = files
+	receipt.Asset, receipt.URL, receipt.SHA256 = "", "", ""
+	fmt.Print(i18n.T("%s %s now uses %s\n", ui.Success("✓"), receipt.Name, version))
+	return nil
+}
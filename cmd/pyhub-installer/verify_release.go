@@ -0,0 +1,184 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/pyhub-kr/pyhub-installer/internal/audit"
+	"github.com/pyhub-kr/pyhub-installer/internal/exitcode"
+	"github.com/pyhub-kr/pyhub-installer/internal/github"
+	"github.com/pyhub-kr/pyhub-installer/internal/i18n"
+	"github.com/pyhub-kr/pyhub-installer/internal/sign"
+	"github.com/pyhub-kr/pyhub-installer/internal/tempdir"
+	"github.com/pyhub-kr/pyhub-installer/internal/ui"
+	"github.com/pyhub-kr/pyhub-installer/pkg/config"
+	"github.com/spf13/cobra"
+)
+
+var verifyReleaseCmd = &cobra.Command{
+	Use:   "verify-release OWNER/REPO@TAG",
+	Short: "Check that every asset of a release has a valid checksum or signature",
+	Long: `Download every asset of a release and check it against the checksums and
+signatures published with it: GitHub's asset digests, FILE.sha256 files and checksum
+lists such as checksums.txt or SHA256SUMS. Detached signatures (.sig, .asc, .minisig)
+are verified with gpg when --gpg is given and with minisign when --minisign-key is
+given, and reported otherwise.
+
+The command fails when an asset has no checksum, a checksum or signature does not
+match, or the installer would not find the published checksum, so it can gate a
+release before it is announced.
+
+Examples:
+  pyhub-installer verify-release pyhub-kr/pyhub-mcptools@v1.2.0
+  pyhub-installer verify-release cli/cli --gpg
+  pyhub-installer verify-release owner/tool@v2.0.0 --minisign-key minisign.pub -o dist`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		if err := runVerifyRelease(cmd, args); err != nil {
+			fmt.Fprint(os.Stderr, i18n.T("Error: %v\n", err))
+			os.Exit(exitcode.Code(err))
+		}
+	},
+}
+
+func init() {
+	verifyReleaseCmd.Flags().String("minisign-key", "", "Verify .minisig signatures with this minisign public key file")
+	verifyReleaseCmd.Flags().Bool("gpg", false, "Verify .sig and .asc signatures with gpg against your keyring")
+	verifyReleaseCmd.Flags().StringP("output", "o", "", "Keep the downloaded assets in this directory")
+
+	rootCmd.AddCommand(verifyReleaseCmd)
+}
+
+// runVerifyRelease implements the verify-release command
+func runVerifyRelease(cmd *cobra.Command, args []string) error {
+	minisignKey, _ := cmd.Flags().GetString("minisign-key")
+	useGPG, _ := cmd.Flags().GetBool("gpg")
+	output, _ := cmd.Flags().GetString("output")
+
+	cfg, err := config.Load()
+	if err != nil {
+		return err
+	}
+	ctx := cmd.Context()
+	target := parseTarget(args[0], "latest")
+	prov, src, err := parseSource(target.Input)
+	if err != nil {
+		return err
+	}
+	latest, err := latestPolicy(cfg, src)
+	if err != nil {
+		return err
+	}
+	release, err := resolveRelease(ctx, prov, src, target.Version, "", latest)
+	if err != nil {
+		return err
+	}
+	if release.Assets, err = prov.Assets(ctx, src, release); err != nil {
+		return fmt.Errorf("failed to list assets: %w", err)
+	}
+	if len(release.Assets) == 0 || (len(release.Assets) == 1 && github.IsSourceArchive(&release.Assets[0])) {
+		return exitcode.Wrap(exitcode.NotFound, fmt.Errorf("%s %s has no release assets", src, release.TagName))
+	}
+
+	dir := output
+	if dir == "" {
+		if dir, err = tempdir.MkdirTemp("verify-release-"); err != nil {
+			return err
+		}
+		defer os.RemoveAll(dir)
+	} else if err := os.MkdirAll(dir, 0755); err != nil {
+		return fmt.Errorf("failed to create output directory: %w", err)
+	}
+
+	fmt.Print(i18n.T("Downloading %d assets of %s %s...\n", len(release.Assets), src, release.TagName))
+	assets := make([]audit.Asset, 0, len(release.Assets))
+	for i := range release.Assets {
+		asset := &release.Assets[i]
+		path := filepath.Join(dir, asset.Name)
+		if err := prov.Download(ctx, asset, path); err != nil {
+			return exitcode.Wrap(exitcode.Network, fmt.Errorf("failed to download %s: %w", asset.Name, err))
+		}
+		entry := audit.Asset{Name: asset.Name, Path: path, Digest: asset.Digest}
+		if sigAsset, err := release.FindSignatureAsset(asset.Name); err == nil {
+			entry.Installer = sigAsset.Name
+		}
+		assets = append(assets, entry)
+	}
+
+	results, err := audit.Run(assets, signatureVerifier(minisignKey, useGPG))
+	if err != nil {
+		return err
+	}
+	if len(results) == 0 {
+		return exitcode.Wrap(exitcode.NotFound, fmt.Errorf("%s %s only has checksum and signature files", src, release.TagName))
+	}
+
+	fmt.Println()
+	problems := 0
+	for _, result := range results {
+		status := result.Status()
+		fmt.Printf("%s %s\n", auditSymbol(status), result.Asset)
+		for _, check := range result.Checksums {
+			printAuditCheck(i18n.T("checksum"), check)
+		}
+		for _, check := range result.Signatures {
+			printAuditCheck(i18n.T("signature"), check)
+		}
+		for _, gap := range result.Gaps {
+			fmt.Print(i18n.T("  Gap: %s\n", gap))
+		}
+		if status != audit.OK || len(result.Gaps) > 0 {
+			problems++
+		}
+	}
+
+	fmt.Println()
+	if problems > 0 {
+		return exitcode.Wrap(exitcode.Verification, fmt.Errorf("%d of %d assets cannot be verified", problems, len(results)))
+	}
+	fmt.Print(i18n.T("%s All %d assets match their checksums\n", ui.Success("✓"), len(results)))
+	return nil
+}
+
+// signatureVerifier checks minisign signatures with a public key and GnuPG signatures
+// with the user's keyring, reporting the others unverified
+func signatureVerifier(minisignKey string, useGPG bool) audit.SignatureVerifier {
+	return func(file, signature string) (audit.Status, error) {
+		switch {
+		case strings.HasSuffix(signature, ".minisig") && minisignKey != "":
+			if err := sign.VerifyMinisign(file, signature, minisignKey); err != nil {
+				return audit.Failed, err
+			}
+		case !strings.HasSuffix(signature, ".minisig") && useGPG:
+			if err := sign.VerifyGPG(file, signature); err != nil {
+				return audit.Failed, err
+			}
+		default:
+			return audit.Unverified, nil
+		}
+		return audit.OK, nil
+	}
+}
+
+// printAuditCheck prints one checksum or signature check of an asset
+func printAuditCheck(kind string, check audit.Check) {
+	fmt.Printf("  %s %s: %s", kind, check.Source, check.Status)
+	if check.Detail != "" {
+		fmt.Printf(" (%s)", check.Detail)
+	}
+	fmt.Println()
+}
+
+// auditSymbol returns the colored marker of an asset's status
+func auditSymbol(status audit.Status) string {
+	switch status {
+	case audit.OK:
+		return ui.Success("✓")
+	case audit.Missing:
+		return ui.Warn("!")
+	default:
+		return ui.Failure("✗")
+	}
+}
@@ -0,0 +1,117 @@
+package agent
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/pyhub-kr/pyhub-installer/internal/semver"
+	"github.com/pyhub-kr/pyhub-installer/internal/state"
+)
+
+// Update policies, from most to least permissive. A version constraint such as
+// ">=2, <3" is accepted as a policy too.
+const (
+	Latest = "latest" // Any newer release
+	Minor  = "minor"  // Newer releases of the installed major version
+	Patch  = "patch"  // Newer releases of the installed minor version
+	Pinned = "pinned" // Never updated
+)
+
+// Constraint returns the --version value a policy allows for a tool at the installed
+// version: "latest", or a range such as "1.x". It returns "" for pinned tools.
+func Constraint(policy, installed string) (string, error) {
+	switch policy {
+	case "", Latest:
+		return "latest", nil
+	case Pinned:
+		return "", nil
+	case Minor, Patch:
+		v, err := semver.Parse(installed)
+		if err != nil {
+			return "", fmt.Errorf("%s policy needs a semantic version, installed %s: %w", policy, installed, err)
+		}
+		if policy == Minor {
+			return fmt.Sprintf("%d.x", v.Major), nil
+		}
+		return fmt.Sprintf("%d.%d.x", v.Major, v.Minor), nil
+	}
+	if _, err := semver.ParseConstraint(policy); err != nil {
+		return "", fmt.Errorf("unknown update policy %q: %w", policy, err)
+	}
+	return policy, nil
+}
+
+// Status is the outcome of checking a tool
+type Status string
+
+const (
+	UpToDate  Status = "up-to-date"
+	Available Status = "available"
+	Skipped   Status = "skipped" // Pinned, or installed without a source
+	Failed    Status = "failed"
+)
+
+// Result is the update check of one installed tool
+type Result struct {
+	Tool        string
+	Source      string
+	InstallPath string
+	From        string // Installed version
+	Channel     string // Release channel the tool follows, empty for stable releases
+	Built       bool   // Built from source, so updates are built too
+	To          string // Newest allowed version, if an update is available
+	Status      Status
+	Err         error
+}
+
+// Resolver returns the release tag a source resolves to for a --version value on a
+// release channel
+type Resolver func(ctx context.Context, source, version, channel string) (string, error)
+
+// Check looks up the newest release each installed tool may be updated to under its
+// policy. policyFor returns the policy of a source.
+func Check(ctx context.Context, receipts []state.Receipt, policyFor func(source string) string, resolve Resolver) []Result {
+	results := make([]Result, 0, len(receipts))
+	for _, receipt := range receipts {
+		result := Result{Tool: receipt.Name, Source: receipt.Source, InstallPath: receipt.InstallPath, From: receipt.Version, Channel: receipt.Channel, Built: receipt.Built}
+		if receipt.Source == "" {
+			result.Status = Skipped
+			results = append(results, result)
+			continue
+		}
+
+		version, err := Constraint(policyFor(receipt.Source), receipt.Version)
+		switch {
+		case err != nil:
+			result.Status, result.Err = Failed, err
+		case version == "":
+			result.Status = Skipped
+		default:
+			result.To, result.Err = resolve(ctx, receipt.Source, version, receipt.Channel)
+			switch {
+			case result.Err != nil:
+				result.Status = Failed
+			case Newer(result.To, receipt.Version):
+				result.Status = Available
+			default:
+				result.Status, result.To = UpToDate, ""
+			}
+		}
+		results = append(results, result)
+	}
+	return results
+}
+
+// Newer reports whether a release tag is newer than the installed version. Tags that
+// are not semantic versions are newer whenever they differ.
+func Newer(tag, installed string) bool {
+	v, err := semver.Parse(tag)
+	if err != nil {
+		return tag != installed
+	}
+	current, err := semver.Parse(installed)
+	if err != nil {
+		return tag != installed
+	}
+	return v.Compare(current) > 0
+}
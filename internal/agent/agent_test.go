@@ -0,0 +1,94 @@
+package agent
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/pyhub-kr/pyhub-installer/internal/state"
+)
+
+func TestConstraint(t *testing.T) {
+	tests := []struct {
+		policy, installed, want string
+		wantErr                 bool
+	}{
+		{"", "v1.4.2", "latest", false},
+		{Latest, "v1.4.2", "latest", false},
+		{Minor, "v1.4.2", "1.x", false},
+		{Patch, "v1.4.2", "1.4.x", false},
+		{Pinned, "v1.4.2", "", false},
+		{">=2, <3", "v1.4.2", ">=2, <3", false},
+		{Minor, "nightly", "", true},
+		{"newest", "v1.4.2", "", true},
+	}
+	for _, tt := range tests {
+		got, err := Constraint(tt.policy, tt.installed)
+		if (err != nil) != tt.wantErr || got != tt.want {
+			t.Errorf("Constraint(%q, %q) = %q, %v", tt.policy, tt.installed, got, err)
+		}
+	}
+}
+
+func TestCheck(t *testing.T) {
+	receipts := []state.Receipt{
+		{Name: "rg", Source: "github:BurntSushi/ripgrep", Version: "14.0.0", InstallPath: "/usr/local/bin"},
+		{Name: "gh", Source: "github:cli/cli", Version: "v2.40.0"},
+		{Name: "fd", Source: "github:sharkdp/fd", Version: "v9.0.0", Channel: "beta"},
+		{Name: "jq", Source: "github:jqlang/jq", Version: "jq-1.7", Built: true},
+		{Name: "broken", Source: "github:owner/broken", Version: "v1.0.0"},
+		{Name: "local"},
+	}
+	policies := map[string]string{"github:cli/cli": Pinned, "github:BurntSushi/ripgrep": Minor}
+	var versions []string
+	channels := map[string]string{}
+	resolve := func(ctx context.Context, source, version, channel string) (string, error) {
+		versions = append(versions, source+"@"+version)
+		channels[source] = channel
+		switch source {
+		case "github:BurntSushi/ripgrep":
+			return "14.1.1", nil
+		case "github:sharkdp/fd":
+			return "v9.0.0", nil
+		case "github:jqlang/jq":
+			return "jq-1.7.1", nil
+		}
+		return "", errors.New("not found")
+	}
+
+	results := Check(context.Background(), receipts, func(source string) string { return policies[source] }, resolve)
+	want := []struct {
+		status Status
+		to     string
+	}{
+		{Available, "14.1.1"},
+		{Skipped, ""},
+		{UpToDate, ""},
+		{Available, "jq-1.7.1"},
+		{Failed, ""},
+		{Skipped, ""},
+	}
+	for i, w := range want {
+		if results[i].Status != w.status || results[i].To != w.to {
+			t.Errorf("%s: got %s %q, want %s %q", results[i].Tool, results[i].Status, results[i].To, w.status, w.to)
+		}
+	}
+	if versions[0] != "github:BurntSushi/ripgrep@14.x" {
+		t.Errorf("Expected the minor policy to resolve 14.x, got %q", versions)
+	}
+	if channels["github:sharkdp/fd"] != "beta" || results[2].Channel != "beta" {
+		t.Errorf("Expected the recorded channel to be followed, got %q", channels)
+	}
+	if !results[3].Built || results[0].Built {
+		t.Error("Expected tools built from source to be reported as such")
+	}
+}
+
+func TestNewer(t *testing.T) {
+	if !Newer("v1.10.0", "v1.9.0") || Newer("v1.9.0", "v1.10.0") || Newer("v1.9.0", "1.9.0") {
+		t.Error("Newer() compares semantic versions")
+	}
+	if !Newer("nightly-2", "nightly-1") || Newer("nightly", "nightly") {
+		t.Error("Newer() treats other differing tags as newer")
+	}
+}
@@ -0,0 +1,143 @@
+package agent
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// ServiceName names the systemd units and the Windows scheduled task
+const ServiceName = "pyhub-installer-agent"
+
+// launchdLabel names the launchd agent
+const launchdLabel = "kr.pyhub.installer.agent"
+
+// File is a file written to install a schedule
+type File struct {
+	Path    string
+	Content string
+}
+
+// Schedule describes how to run "agent --once" periodically with the service manager
+// of a platform: files to write and commands to run to enable or disable it
+type Schedule struct {
+	Files   []File
+	Enable  [][]string
+	Disable [][]string
+}
+
+// NewSchedule returns the schedule running executable every interval for a user: a
+// systemd user timer on Linux, a launchd agent on macOS and a scheduled task on Windows
+func NewSchedule(goos, executable, home string, interval time.Duration) (*Schedule, error) {
+	if interval < time.Minute {
+		return nil, fmt.Errorf("interval must be at least a minute")
+	}
+	switch goos {
+	case "linux":
+		return systemdSchedule(executable, home, interval), nil
+	case "darwin":
+		return launchdSchedule(executable, home, interval), nil
+	case "windows":
+		return taskSchedule(executable, interval)
+	}
+	return nil, fmt.Errorf("scheduling is not supported on %s; run \"agent\" from your service manager instead", goos)
+}
+
+// systemdSchedule returns a oneshot service and a timer in the user's systemd directory
+func systemdSchedule(executable, home string, interval time.Duration) *Schedule {
+	dir := filepath.Join(home, ".config", "systemd", "user")
+	service := fmt.Sprintf(`[Unit]
+Description=Update tools installed by pyhub-installer
+
+[Service]
+Type=oneshot
+ExecStart="%s" agent --once
+`, executable)
+	timer := fmt.Sprintf(`[Unit]
+Description=Update tools installed by pyhub-installer periodically
+
+[Timer]
+OnBootSec=5min
+OnUnitActiveSec=%ds
+RandomizedDelaySec=5min
+
+[Install]
+WantedBy=timers.target
+`, int(interval.Seconds()))
+	return &Schedule{
+		Files: []File{
+			{Path: filepath.Join(dir, ServiceName+".service"), Content: service},
+			{Path: filepath.Join(dir, ServiceName+".timer"), Content: timer},
+		},
+		Enable: [][]string{
+			{"systemctl", "--user", "daemon-reload"},
+			{"systemctl", "--user", "enable", "--now", ServiceName + ".timer"},
+		},
+		Disable: [][]string{
+			{"systemctl", "--user", "disable", "--now", ServiceName + ".timer"},
+		},
+	}
+}
+
+// launchdSchedule returns a launch agent running at login and every interval
+func launchdSchedule(executable, home string, interval time.Duration) *Schedule {
+	path := filepath.Join(home, "Library", "LaunchAgents", launchdLabel+".plist")
+	plist := fmt.Sprintf(`<?xml version="1.0" encoding="UTF-8"?>
+<!DOCTYPE plist PUBLIC "-//Apple//DTD PLIST 1.0//EN" "http://www.apple.com/DTDs/PropertyList-1.0.dtd">
+<plist version="1.0">
+<dict>
+  <key>Label</key>
+  <string>%s</string>
+  <key>ProgramArguments</key>
+  <array>
+    <string>%s</string>
+    <string>agent</string>
+    <string>--once</string>
+  </array>
+  <key>StartInterval</key>
+  <integer>%d</integer>
+  <key>RunAtLoad</key>
+  <true/>
+</dict>
+</plist>
+`, launchdLabel, xmlEscape(executable), int(interval.Seconds()))
+	return &Schedule{
+		Files:   []File{{Path: path, Content: plist}},
+		Enable:  [][]string{{"launchctl", "load", "-w", path}},
+		Disable: [][]string{{"launchctl", "unload", "-w", path}},
+	}
+}
+
+// taskSchedule returns a Windows scheduled task, repeating in whole days, hours or
+// minutes as schtasks requires
+func taskSchedule(executable string, interval time.Duration) (*Schedule, error) {
+	var schedule string
+	var modifier int
+	switch {
+	case interval%(24*time.Hour) == 0:
+		schedule, modifier = "DAILY", int(interval/(24*time.Hour))
+	case interval%time.Hour == 0:
+		schedule, modifier = "HOURLY", int(interval/time.Hour)
+	case interval%time.Minute == 0 && interval < 24*time.Hour:
+		schedule, modifier = "MINUTE", int(interval/time.Minute)
+	default:
+		return nil, fmt.Errorf("interval %s cannot be scheduled on Windows; use whole minutes below a day, hours or days", interval)
+	}
+	if schedule == "HOURLY" && modifier > 23 {
+		return nil, fmt.Errorf("interval %s cannot be scheduled on Windows; use whole days", interval)
+	}
+	return &Schedule{
+		Enable: [][]string{{
+			"schtasks", "/Create", "/F", "/TN", ServiceName,
+			"/SC", schedule, "/MO", fmt.Sprint(modifier),
+			"/TR", fmt.Sprintf(`"%s" agent --once`, executable),
+		}},
+		Disable: [][]string{{"schtasks", "/Delete", "/F", "/TN", ServiceName}},
+	}, nil
+}
+
+// xmlEscape escapes text for a plist string
+func xmlEscape(s string) string {
+	return strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;").Replace(s)
+}
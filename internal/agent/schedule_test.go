@@ -0,0 +1,52 @@
+package agent
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestNewSchedule(t *testing.T) {
+	linux, err := NewSchedule("linux", "/usr/local/bin/pyhub-installer", "/home/user", 6*time.Hour)
+	if err != nil {
+		t.Fatalf("NewSchedule(linux) error = %v", err)
+	}
+	if len(linux.Files) != 2 || linux.Files[1].Path != filepath.Join("/home/user", ".config", "systemd", "user", "pyhub-installer-agent.timer") {
+		t.Fatalf("Unexpected systemd files: %+v", linux.Files)
+	}
+	if !strings.Contains(linux.Files[0].Content, `ExecStart="/usr/local/bin/pyhub-installer" agent --once`) ||
+		!strings.Contains(linux.Files[1].Content, "OnUnitActiveSec=21600s") {
+		t.Errorf("Unexpected systemd units:\n%s\n%s", linux.Files[0].Content, linux.Files[1].Content)
+	}
+
+	darwin, err := NewSchedule("darwin", "/opt/bin/pyhub-installer", "/Users/user", 24*time.Hour)
+	if err != nil {
+		t.Fatalf("NewSchedule(darwin) error = %v", err)
+	}
+	if len(darwin.Files) != 1 || !strings.Contains(darwin.Files[0].Content, "<integer>86400</integer>") {
+		t.Errorf("Unexpected launchd agent: %+v", darwin.Files)
+	}
+
+	tests := map[time.Duration]string{
+		48 * time.Hour:   "DAILY 2",
+		6 * time.Hour:    "HOURLY 6",
+		90 * time.Minute: "MINUTE 90",
+	}
+	for interval, want := range tests {
+		windows, err := NewSchedule("windows", `C:\Tools\pyhub-installer.exe`, `C:\Users\user`, interval)
+		if err != nil {
+			t.Fatalf("NewSchedule(windows, %s) error = %v", interval, err)
+		}
+		create := strings.Join(windows.Enable[0], " ")
+		if !strings.Contains(create, "/SC "+strings.Replace(want, " ", " /MO ", 1)) {
+			t.Errorf("NewSchedule(windows, %s) = %s, want %s", interval, create, want)
+		}
+	}
+	if _, err := NewSchedule("windows", "pyhub-installer.exe", "", 25*time.Hour); err == nil {
+		t.Error("Expected error for an interval schtasks cannot express")
+	}
+	if _, err := NewSchedule("linux", "pyhub-installer", "/home/user", time.Second); err == nil {
+		t.Error("Expected error for an interval below a minute")
+	}
+}
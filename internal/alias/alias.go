@@ -0,0 +1,135 @@
+package alias
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/pyhub-kr/pyhub-installer/pkg/config"
+)
+
+// Defaults maps well-known tool names to their release sources
+var Defaults = map[string]string{
+	"bat":       "github:sharkdp/bat",
+	"delta":     "github:dandavison/delta",
+	"dust":      "github:bootandy/dust",
+	"eza":       "github:eza-community/eza",
+	"fd":        "github:sharkdp/fd",
+	"fzf":       "github:junegunn/fzf",
+	"gh":        "github:cli/cli",
+	"gum":       "github:charmbracelet/gum",
+	"hyperfine": "github:sharkdp/hyperfine",
+	"jq":        "github:jqlang/jq",
+	"just":      "github:casey/just",
+	"k9s":       "github:derailed/k9s",
+	"lazygit":   "github:jesseduffield/lazygit",
+	"rg":        "github:BurntSushi/ripgrep",
+	"ruff":      "github:astral-sh/ruff",
+	"sd":        "github:chmln/sd",
+	"starship":  "github:starship/starship",
+	"task":      "github:go-task/task",
+	"uv":        "github:astral-sh/uv",
+	"xh":        "github:ducaale/xh",
+	"yq":        "github:mikefarah/yq",
+	"zoxide":    "github:ajeetdsouza/zoxide",
+}
+
+// BrewNames maps Homebrew formula and cask names to aliases where they differ
+var BrewNames = map[string]string{
+	"git-delta": "delta",
+	"go-task":   "task",
+	"ripgrep":   "rg",
+}
+
+// Registry resolves short names to release sources
+type Registry struct {
+	aliases map[string]string
+	path    string // User alias file, for error messages
+}
+
+// NewRegistry creates a registry of the default aliases overridden by user aliases
+func NewRegistry(user map[string]string) *Registry {
+	aliases := make(map[string]string, len(Defaults)+len(user))
+	for name, source := range Defaults {
+		aliases[name] = source
+	}
+	for name, source := range user {
+		aliases[strings.ToLower(name)] = source
+	}
+	return &Registry{aliases: aliases}
+}
+
+// Load creates a registry including the aliases from the user alias file, if any
+func Load() (*Registry, error) {
+	path, err := config.AliasesPath()
+	if err != nil {
+		return NewRegistry(nil), nil
+	}
+
+	var user map[string]string
+	data, err := os.ReadFile(path)
+	if err != nil && !os.IsNotExist(err) {
+		return nil, fmt.Errorf("failed to read alias file: %w", err)
+	}
+	if err == nil {
+		if err := json.Unmarshal(data, &user); err != nil {
+			return nil, fmt.Errorf("failed to parse alias file %s: %w", path, err)
+		}
+	}
+
+	registry := NewRegistry(user)
+	registry.path = path
+	return registry, nil
+}
+
+// IsAlias reports whether input is a bare name rather than a repository or URL
+func IsAlias(input string) bool {
+	return input != "" && !strings.ContainsAny(input, "/:\\")
+}
+
+// Lookup returns the source for a name
+func (r *Registry) Lookup(name string) (string, bool) {
+	source, ok := r.aliases[strings.ToLower(name)]
+	return source, ok
+}
+
+// LookupBrew returns the source for a Homebrew formula or cask name (without its tap),
+// trying the alias of the same name first
+func (r *Registry) LookupBrew(name string) (string, bool) {
+	if source, ok := r.Lookup(name); ok {
+		return source, true
+	}
+	if alias, ok := BrewNames[strings.ToLower(name)]; ok {
+		return r.Lookup(alias)
+	}
+	return "", false
+}
+
+// Resolve expands input if it is an alias and returns other inputs unchanged
+func (r *Registry) Resolve(input string) (string, error) {
+	if !IsAlias(input) {
+		return input, nil
+	}
+
+	source, ok := r.Lookup(input)
+	if !ok {
+		hint := "use OWNER/REPO"
+		if r.path != "" {
+			hint += " or add it to " + r.path
+		}
+		return "", fmt.Errorf("unknown tool alias: %s (%s)", input, hint)
+	}
+	return source, nil
+}
+
+// Names returns all alias names in sorted order
+func (r *Registry) Names() []string {
+	names := make([]string, 0, len(r.aliases))
+	for name := range r.aliases {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
@@ -0,0 +1,136 @@
+package alias
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestIsAlias(t *testing.T) {
+	tests := []struct {
+		input string
+		want  bool
+	}{
+		{"rg", true},
+		{"ripgrep", true},
+		{"BurntSushi/ripgrep", false},
+		{"github:cli/cli", false},
+		{"https://github.com/cli/cli", false},
+		{"", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			if got := IsAlias(tt.input); got != tt.want {
+				t.Errorf("IsAlias(%q) = %v, want %v", tt.input, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestResolve(t *testing.T) {
+	registry := NewRegistry(map[string]string{
+		"mytool": "github:myorg/mytool",
+		"RG":     "github:example/ripgrep-fork", // User aliases override defaults
+	})
+
+	tests := []struct {
+		input   string
+		want    string
+		wantErr bool
+	}{
+		{"gh", "github:cli/cli", false},
+		{"mytool", "github:myorg/mytool", false},
+		{"rg", "github:example/ripgrep-fork", false},
+		{"Gh", "github:cli/cli", false},
+		{"owner/repo", "owner/repo", false},
+		{"github:owner/repo", "github:owner/repo", false},
+		{"no-such-tool", "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			got, err := registry.Resolve(tt.input)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Resolve(%q) error = %v, wantErr %v", tt.input, err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("Resolve(%q) = %s, want %s", tt.input, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLoadUserAliases(t *testing.T) {
+	configDir := t.TempDir()
+	t.Setenv("XDG_CONFIG_HOME", configDir)
+	t.Setenv("APPDATA", configDir)
+	t.Setenv("HOME", configDir)
+
+	registry, err := Load()
+	if err != nil {
+		t.Fatalf("Load() without alias file error = %v", err)
+	}
+	if _, ok := registry.Lookup("rg"); !ok {
+		t.Error("Expected default aliases without alias file")
+	}
+
+	userConfigDir, err := os.UserConfigDir()
+	if err != nil {
+		t.Skip("No user config directory")
+	}
+	aliasFile := filepath.Join(userConfigDir, "pyhub-installer", "aliases.json")
+	if err := os.MkdirAll(filepath.Dir(aliasFile), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(aliasFile, []byte(`{"internal": "github:myorg/internal-tool"}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	registry, err = Load()
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if source, _ := registry.Lookup("internal"); source != "github:myorg/internal-tool" {
+		t.Errorf("Expected user alias, got %q", source)
+	}
+
+	if err := os.WriteFile(aliasFile, []byte(`not json`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Load(); err == nil {
+		t.Error("Expected error for malformed alias file")
+	}
+}
+
+func TestDefaultsAreSources(t *testing.T) {
+	for name, source := range Defaults {
+		if IsAlias(source) {
+			t.Errorf("Default alias %s must map to a repository, got %s", name, source)
+		}
+	}
+}
+
+func TestLookupBrew(t *testing.T) {
+	registry := NewRegistry(map[string]string{"ripgrep": "github:example/ripgrep-fork"})
+
+	tests := map[string]string{
+		"jq":        "github:jqlang/jq",
+		"git-delta": "github:dandavison/delta",
+		"go-task":   "github:go-task/task",
+		"ripgrep":   "github:example/ripgrep-fork", // A user alias of the formula name wins
+	}
+	for name, want := range tests {
+		if got, ok := registry.LookupBrew(name); !ok || got != want {
+			t.Errorf("LookupBrew(%q) = %q, %v, want %q", name, got, ok, want)
+		}
+	}
+	if _, ok := registry.LookupBrew("visual-studio-code"); ok {
+		t.Error("Expected no source for an unknown cask")
+	}
+	for name, alias := range BrewNames {
+		if _, ok := Defaults[alias]; !ok {
+			t.Errorf("Brew name %s maps to unknown alias %s", name, alias)
+		}
+	}
+}
@@ -0,0 +1,246 @@
+package audit
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/pyhub-kr/pyhub-installer/internal/verify"
+)
+
+// Status is the outcome of a check
+type Status string
+
+const (
+	OK         Status = "ok"
+	Failed     Status = "failed"     // Checksum mismatch or invalid signature
+	Missing    Status = "missing"    // Nothing to check the asset against
+	Unverified Status = "unverified" // Signature present but not checked, e.g. without a key
+)
+
+// Asset is a downloaded release asset
+type Asset struct {
+	Name   string
+	Path   string // Downloaded file
+	Digest string // "sha256:<hex>" published by the host, if any
+	// Installer is the checksum or signature file the installer picks for the asset,
+	// "" if it finds none
+	Installer string
+}
+
+// Check is the result of checking an asset against one checksum or signature
+type Check struct {
+	Source string // File or digest checked against
+	Status Status
+	Detail string
+}
+
+// Result describes the checksums and signatures covering a release asset
+type Result struct {
+	Asset      string
+	SHA256     string
+	Checksums  []Check
+	Signatures []Check
+	Gaps       []string // Problems an install of the asset would run into
+}
+
+// Status summarizes the result: failed if any check failed, missing if no checksum
+// matched, otherwise ok
+func (r *Result) Status() Status {
+	for _, checks := range [][]Check{r.Checksums, r.Signatures} {
+		for _, check := range checks {
+			if check.Status == Failed {
+				return Failed
+			}
+		}
+	}
+	for _, check := range r.Checksums {
+		if check.Status == OK {
+			return OK
+		}
+	}
+	return Missing
+}
+
+// SignatureVerifier checks a detached signature of a file. It returns Unverified when
+// the signature cannot be checked, e.g. without a public key.
+type SignatureVerifier func(file, signature string) (Status, error)
+
+// sumsFiles are checksum files listing several assets
+var sumsFiles = []string{"checksums.txt", "checksums", "sha256sums", "sha256sums.txt"}
+
+// signatureExts are the extensions of detached signatures
+var signatureExts = []string{".sig", ".asc", ".minisig"}
+
+// checksumExts are the extensions of per-asset checksum files
+var checksumExts = []string{".sha256", ".sha256sum"}
+
+// IsSumsFile reports whether an asset lists the checksums of several assets, including
+// GoReleaser's "<project>_<version>_checksums.txt"
+func IsSumsFile(name string) bool {
+	lower := strings.ToLower(name)
+	for _, sums := range sumsFiles {
+		if lower == sums || strings.HasSuffix(lower, "_"+sums) || strings.HasSuffix(lower, "-"+sums) {
+			return true
+		}
+	}
+	return false
+}
+
+// IsSignature reports whether an asset is a detached signature
+func IsSignature(name string) bool {
+	return hasExt(name, signatureExts)
+}
+
+// IsMetadata reports whether an asset is a checksum or signature file rather than
+// something to install
+func IsMetadata(name string) bool {
+	return IsSumsFile(name) || IsSignature(name) || hasExt(name, checksumExts)
+}
+
+// hasExt reports whether a name ends with one of the extensions, ignoring case
+func hasExt(name string, exts []string) bool {
+	lower := strings.ToLower(name)
+	for _, ext := range exts {
+		if strings.HasSuffix(lower, ext) {
+			return true
+		}
+	}
+	return false
+}
+
+// Run checks every installable asset against the checksum files, host digests and
+// signatures among the assets. verifySignature may be nil to only report signatures.
+func Run(assets []Asset, verifySignature SignatureVerifier) ([]Result, error) {
+	byName := make(map[string]*Asset, len(assets))
+	for i := range assets {
+		byName[strings.ToLower(assets[i].Name)] = &assets[i]
+	}
+	contents := make(map[string]string)
+	read := func(asset *Asset) (string, error) {
+		if content, ok := contents[asset.Name]; ok {
+			return content, nil
+		}
+		data, err := os.ReadFile(asset.Path)
+		if err != nil {
+			return "", fmt.Errorf("failed to read %s: %w", asset.Name, err)
+		}
+		contents[asset.Name] = string(data)
+		return string(data), nil
+	}
+
+	var sums []*Asset
+	for i := range assets {
+		if IsSumsFile(assets[i].Name) {
+			sums = append(sums, &assets[i])
+		}
+	}
+
+	var results []Result
+	for i := range assets {
+		asset := &assets[i]
+		if IsMetadata(asset.Name) {
+			continue
+		}
+		sum, err := verify.NewVerifier(asset.Path).GetSHA256()
+		if err != nil {
+			return nil, err
+		}
+		result := Result{Asset: asset.Name, SHA256: sum}
+
+		if expected, ok := strings.CutPrefix(asset.Digest, "sha256:"); ok {
+			result.Checksums = append(result.Checksums, compare("digest", expected, sum))
+		}
+		base := strings.TrimSuffix(asset.Name, filepath.Ext(asset.Name))
+		var signed []string // Files whose signatures cover the asset
+		seen := make(map[*Asset]bool)
+		for _, name := range []string{asset.Name + ".sha256", asset.Name + ".sha256sum", base + ".sha256", base + ".sha256sum"} {
+			file := byName[strings.ToLower(name)]
+			if file == nil || seen[file] {
+				continue
+			}
+			seen[file] = true
+			content, err := read(file)
+			if err != nil {
+				return nil, err
+			}
+			result.Checksums = append(result.Checksums, listed(file.Name, content, asset.Name, sum))
+			signed = append(signed, file.Name)
+		}
+		for _, file := range sums {
+			content, err := read(file)
+			if err != nil {
+				return nil, err
+			}
+			if check := listed(file.Name, content, asset.Name, sum); check.Status != Missing {
+				result.Checksums = append(result.Checksums, check)
+				signed = append(signed, file.Name)
+			}
+		}
+
+		for _, name := range append([]string{asset.Name}, signed...) {
+			for _, ext := range signatureExts {
+				sig := byName[strings.ToLower(name+ext)]
+				if sig == nil {
+					continue
+				}
+				target := asset
+				if name != asset.Name {
+					target = byName[strings.ToLower(name)]
+				}
+				result.Signatures = append(result.Signatures, checkSignature(verifySignature, target.Path, sig))
+			}
+		}
+
+		result.Gaps = gaps(asset, &result)
+		results = append(results, result)
+	}
+	sort.Slice(results, func(i, j int) bool { return results[i].Asset < results[j].Asset })
+	return results, nil
+}
+
+// compare checks an expected hash against the asset's
+func compare(source, expected, actual string) Check {
+	if !strings.EqualFold(expected, actual) {
+		return Check{Source: source, Status: Failed, Detail: "expected " + expected}
+	}
+	return Check{Source: source, Status: OK}
+}
+
+// listed checks the hash a checksum file lists for an asset, Missing if it lists none
+func listed(file, content, name, actual string) Check {
+	expected, err := verify.ChecksumFor(content, name)
+	if err != nil {
+		return Check{Source: file, Status: Missing, Detail: err.Error()}
+	}
+	return compare(file, expected, actual)
+}
+
+// checkSignature verifies a detached signature, or reports it unverified without a verifier
+func checkSignature(verifySignature SignatureVerifier, file string, sig *Asset) Check {
+	if verifySignature == nil {
+		return Check{Source: sig.Name, Status: Unverified}
+	}
+	status, err := verifySignature(file, sig.Path)
+	if err != nil {
+		return Check{Source: sig.Name, Status: Failed, Detail: err.Error()}
+	}
+	return Check{Source: sig.Name, Status: status}
+}
+
+// gaps lists what keeps the installer from verifying an asset
+func gaps(asset *Asset, result *Result) []string {
+	var gaps []string
+	if len(result.Checksums) == 0 {
+		gaps = append(gaps, "no checksum published")
+	}
+	switch {
+	case asset.Installer == "" && asset.Digest == "" && len(result.Checksums) > 0:
+		gaps = append(gaps, "the installer finds none of the checksum files; publish <asset>.sha256 or checksums.txt")
+	case IsSignature(asset.Installer):
+		gaps = append(gaps, fmt.Sprintf("the installer picks %s, which it cannot verify; publish %s.sha256", asset.Installer, asset.Name))
+	}
+	return gaps
+}
@@ -0,0 +1,135 @@
+package audit
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// writeAssets writes release assets with the given contents into a directory
+func writeAssets(t *testing.T, files map[string]string) []Asset {
+	t.Helper()
+	dir := t.TempDir()
+	var assets []Asset
+	for name, content := range files {
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+		assets = append(assets, Asset{Name: name, Path: path})
+	}
+	return assets
+}
+
+func hash(content string) string {
+	sum := sha256.Sum256([]byte(content))
+	return hex.EncodeToString(sum[:])
+}
+
+func TestRun(t *testing.T) {
+	assets := writeAssets(t, map[string]string{
+		"tool_linux_amd64.tar.gz":       "linux",
+		"tool_darwin_arm64.tar.gz":      "darwin",
+		"tool_windows_amd64.zip":        "windows",
+		"tool_freebsd_amd64.tar.gz":     "freebsd",
+		"tool_1.0_checksums.txt":        hash("linux") + "  tool_linux_amd64.tar.gz\n" + hash("tampered") + "  tool_darwin_arm64.tar.gz\n",
+		"tool_1.0_checksums.txt.sig":    "-----BEGIN PGP SIGNATURE-----",
+		"tool_windows_amd64.zip.sha256": hash("windows") + "  tool_windows_amd64.zip\n",
+	})
+	for i := range assets {
+		if assets[i].Name == "tool_windows_amd64.zip" {
+			assets[i].Installer = "tool_windows_amd64.zip.sha256"
+		}
+	}
+
+	results, err := Run(assets, nil)
+	if err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+	if len(results) != 4 {
+		t.Fatalf("Run() checked %d assets, want 4: %+v", len(results), results)
+	}
+	byAsset := make(map[string]Result)
+	for _, result := range results {
+		byAsset[result.Asset] = result
+	}
+
+	linux := byAsset["tool_linux_amd64.tar.gz"]
+	if linux.Status() != OK || len(linux.Checksums) != 1 || linux.Checksums[0].Source != "tool_1.0_checksums.txt" {
+		t.Errorf("Unexpected linux result: %+v", linux)
+	}
+	if len(linux.Signatures) != 1 || linux.Signatures[0].Status != Unverified {
+		t.Errorf("Expected the signature of the checksum file to cover the asset: %+v", linux.Signatures)
+	}
+	if len(linux.Gaps) != 1 || !strings.Contains(linux.Gaps[0], "finds none") {
+		t.Errorf("Expected a gap for a checksum file the installer does not find: %q", linux.Gaps)
+	}
+	if darwin := byAsset["tool_darwin_arm64.tar.gz"]; darwin.Status() != Failed {
+		t.Errorf("Expected a checksum mismatch: %+v", darwin)
+	}
+	if freebsd := byAsset["tool_freebsd_amd64.tar.gz"]; freebsd.Status() != Missing || len(freebsd.Gaps) != 1 {
+		t.Errorf("Expected a missing checksum: %+v", freebsd)
+	}
+	if windows := byAsset["tool_windows_amd64.zip"]; windows.Status() != OK || len(windows.Gaps) != 0 {
+		t.Errorf("Unexpected windows result: %+v", windows)
+	}
+}
+
+func TestRunDigestAndSignatures(t *testing.T) {
+	assets := writeAssets(t, map[string]string{
+		"tool.zip":         "zip",
+		"tool.zip.sig":     "-----BEGIN PGP SIGNATURE-----",
+		"tool.zip.minisig": "untrusted comment",
+	})
+	for i := range assets {
+		if assets[i].Name == "tool.zip" {
+			assets[i].Digest = "sha256:" + hash("zip")
+			assets[i].Installer = "tool.zip.sig"
+		}
+	}
+
+	results, err := Run(assets, func(file, signature string) (Status, error) {
+		if strings.HasSuffix(signature, ".minisig") {
+			return Failed, errors.New("signature verification failed")
+		}
+		return OK, nil
+	})
+	if err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+	if len(results) != 1 {
+		t.Fatalf("Run() = %+v", results)
+	}
+	result := results[0]
+	if len(result.Checksums) != 1 || result.Checksums[0].Source != "digest" || result.Checksums[0].Status != OK {
+		t.Errorf("Expected the digest to be checked: %+v", result.Checksums)
+	}
+	if len(result.Signatures) != 2 || result.Status() != Failed {
+		t.Errorf("Expected the invalid minisign signature to fail the asset: %+v", result)
+	}
+	if len(result.Gaps) != 1 || !strings.Contains(result.Gaps[0], "cannot verify") {
+		t.Errorf("Expected a gap for a signature picked by the installer: %q", result.Gaps)
+	}
+}
+
+func TestIsMetadata(t *testing.T) {
+	tests := map[string]bool{
+		"checksums.txt":             true,
+		"SHA256SUMS":                true,
+		"tool_1.0_checksums.txt":    true,
+		"tool.tar.gz.sha256":        true,
+		"tool.zip.minisig":          true,
+		"SHA256SUMS.asc":            true,
+		"tool_linux_amd64.tar.gz":   false,
+		"checksums-tool_1.0.tar.gz": false,
+	}
+	for name, want := range tests {
+		if got := IsMetadata(name); got != want {
+			t.Errorf("IsMetadata(%q) = %v, want %v", name, got, want)
+		}
+	}
+}
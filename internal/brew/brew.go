@@ -0,0 +1,277 @@
+package brew
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"path"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/pyhub-kr/pyhub-installer/internal/retry"
+)
+
+// DefaultBaseURL is the Homebrew JSON API
+const DefaultBaseURL = "https://formulae.brew.sh/api"
+
+// BottleToken authorizes anonymous bottle downloads from the GitHub container registry
+const BottleToken = "QQ=="
+
+// bottleHost serves Homebrew bottles
+const bottleHost = "ghcr.io"
+
+// macOSReleases lists the macOS bottle tags, oldest first
+var macOSReleases = []string{"catalina", "big_sur", "monterey", "ventura", "sonoma", "sequoia", "tahoe"}
+
+// ErrNotFound matches API errors for missing formulae and casks
+var ErrNotFound = errors.New("not found")
+
+// Client fetches formula and cask descriptions from the Homebrew API
+type Client struct {
+	BaseURL    string
+	HTTPClient *http.Client
+}
+
+// NewClient creates a client for the public Homebrew API
+func NewClient() *Client {
+	return &Client{
+		BaseURL:    DefaultBaseURL,
+		HTTPClient: &http.Client{Timeout: 30 * time.Second},
+	}
+}
+
+// Formula describes a Homebrew formula and its prebuilt bottles
+type Formula struct {
+	Name     string `json:"name"`
+	Revision int    `json:"revision"`
+	Versions struct {
+		Stable string `json:"stable"`
+	} `json:"versions"`
+	Bottle struct {
+		Stable struct {
+			Files map[string]BottleFile `json:"files"`
+		} `json:"stable"`
+	} `json:"bottle"`
+}
+
+// BottleFile is the bottle of a formula for one OS release and architecture
+type BottleFile struct {
+	URL    string `json:"url"`
+	SHA256 string `json:"sha256"`
+}
+
+// Cask describes a Homebrew cask
+type Cask struct {
+	Token      string                   `json:"token"`
+	Version    string                   `json:"version"`
+	URL        string                   `json:"url"`
+	SHA256     string                   `json:"sha256"` // "no_check" when the download is unversioned
+	Artifacts  []map[string]interface{} `json:"artifacts"`
+	Variations map[string]struct {
+		URL    string `json:"url"`
+		SHA256 string `json:"sha256"`
+	} `json:"variations"`
+}
+
+// Download is a platform-specific file described by a formula or cask
+type Download struct {
+	Platform string // e.g. darwin-arm64; "darwin-universal" when a cask names no architecture
+	URL      string
+	SHA256   string
+}
+
+// GetFormula fetches a formula by name
+func (c *Client) GetFormula(name string) (*Formula, error) {
+	var formula Formula
+	if err := c.getJSON("formula/"+url.PathEscape(name)+".json", &formula); err != nil {
+		return nil, fmt.Errorf("failed to fetch formula %s: %w", name, err)
+	}
+	return &formula, nil
+}
+
+// GetCask fetches a cask by token
+func (c *Client) GetCask(token string) (*Cask, error) {
+	var cask Cask
+	if err := c.getJSON("cask/"+url.PathEscape(token)+".json", &cask); err != nil {
+		return nil, fmt.Errorf("failed to fetch cask %s: %w", token, err)
+	}
+	return &cask, nil
+}
+
+// getJSON decodes an API document
+func (c *Client) getJSON(endpoint string, v interface{}) error {
+	client := c.HTTPClient
+	if client == nil {
+		client = http.DefaultClient
+	}
+
+	resp, err := retry.Do(context.Background(), func() (*http.Response, error) { return client.Get(c.BaseURL + "/" + endpoint) })
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return fmt.Errorf("%w in Homebrew", ErrNotFound)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("Homebrew API error: %d", resp.StatusCode)
+	}
+	if err := json.NewDecoder(resp.Body).Decode(v); err != nil {
+		return fmt.Errorf("failed to decode response: %w", err)
+	}
+	return nil
+}
+
+// Version returns the formula version as Homebrew prints it, including the revision
+func (f *Formula) Version() string {
+	if f.Revision > 0 {
+		return fmt.Sprintf("%s_%d", f.Versions.Stable, f.Revision)
+	}
+	return f.Versions.Stable
+}
+
+// Downloads returns one bottle per platform. Of several macOS bottles for an
+// architecture the one for the oldest macOS release is used, as it also runs on newer ones.
+func (f *Formula) Downloads() []Download {
+	best := make(map[string]string) // platform -> bottle tag
+	for tag := range f.Bottle.Stable.Files {
+		if tag == "all" {
+			continue
+		}
+		platform := bottlePlatform(tag)
+		if current, exists := best[platform]; !exists || macOSRank(tag) < macOSRank(current) ||
+			(macOSRank(tag) == macOSRank(current) && tag < current) {
+			best[platform] = tag
+		}
+	}
+
+	// Bottles tagged "all" run everywhere
+	if _, ok := f.Bottle.Stable.Files["all"]; ok {
+		for _, platform := range []string{"darwin-amd64", "darwin-arm64", "linux-amd64", "linux-arm64"} {
+			if _, exists := best[platform]; !exists {
+				best[platform] = "all"
+			}
+		}
+	}
+
+	var downloads []Download
+	for _, platform := range sortedKeys(best) {
+		file := f.Bottle.Stable.Files[best[platform]]
+		downloads = append(downloads, Download{Platform: platform, URL: file.URL, SHA256: file.SHA256})
+	}
+	return downloads
+}
+
+// bottlePlatform maps a bottle tag (arm64_sonoma, ventura, x86_64_linux) to a platform
+func bottlePlatform(tag string) string {
+	switch tag {
+	case "x86_64_linux":
+		return "linux-amd64"
+	case "arm64_linux":
+		return "linux-arm64"
+	}
+	if strings.HasPrefix(tag, "arm64_") {
+		return "darwin-arm64"
+	}
+	return "darwin-amd64"
+}
+
+// macOSRank orders bottle tags by macOS release; unknown releases sort last
+func macOSRank(tag string) int {
+	tag = strings.TrimPrefix(tag, "arm64_")
+	for i, release := range macOSReleases {
+		if release == tag {
+			return i
+		}
+	}
+	return len(macOSReleases)
+}
+
+// Downloads returns the cask's archive for each architecture it ships
+func (c *Cask) Downloads() []Download {
+	seen := make(map[string]bool)
+	var downloads []Download
+
+	add := func(rawURL, sha256, variation string) {
+		if rawURL == "" || seen[rawURL] {
+			return
+		}
+		seen[rawURL] = true
+		arch := urlArch(rawURL)
+		if arch == "" && variation != "" {
+			arch = "amd64"
+			if strings.HasPrefix(variation, "arm64_") {
+				arch = "arm64"
+			}
+		}
+		if arch == "" {
+			arch = "universal"
+		}
+		if sha256 == "no_check" {
+			sha256 = ""
+		}
+		downloads = append(downloads, Download{Platform: "darwin-" + arch, URL: rawURL, SHA256: sha256})
+	}
+
+	add(c.URL, c.SHA256, "")
+	for _, variation := range sortedKeys(c.Variations) {
+		v := c.Variations[variation]
+		add(v.URL, v.SHA256, variation)
+	}
+	return downloads
+}
+
+// Binaries returns the archive paths of the cask's binary stanzas. Binaries inside
+// app bundles are skipped, as apps are not installed.
+func (c *Cask) Binaries() []string {
+	var binaries []string
+	for _, artifact := range c.Artifacts {
+		entries, ok := artifact["binary"].([]interface{})
+		if !ok {
+			continue
+		}
+		for _, entry := range entries {
+			source, ok := entry.(string)
+			if !ok || strings.Contains(source, "$APPDIR") || strings.Contains(source, "appdir") {
+				continue
+			}
+			binaries = append(binaries, strings.TrimPrefix(source, "./"))
+		}
+	}
+	return binaries
+}
+
+// urlArch detects the architecture named in a download URL
+func urlArch(rawURL string) string {
+	name := strings.ToLower(path.Base(rawURL))
+	switch {
+	case strings.Contains(name, "arm64"), strings.Contains(name, "aarch64"):
+		return "arm64"
+	case strings.Contains(name, "x86_64"), strings.Contains(name, "amd64"), strings.Contains(name, "x64"), strings.Contains(name, "intel"):
+		return "amd64"
+	case strings.Contains(name, "universal"):
+		return "universal"
+	}
+	return ""
+}
+
+// IsBottleURL reports whether a URL needs the bottle registry token
+func IsBottleURL(rawURL string) bool {
+	u, err := url.Parse(rawURL)
+	return err == nil && u.Host == bottleHost
+}
+
+// sortedKeys returns the keys of a map in sorted order
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,131 @@
+package brew
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+)
+
+const formulaJSON = `{
+  "name": "jq",
+  "revision": 1,
+  "versions": {"stable": "1.7.1"},
+  "bottle": {"stable": {"files": {
+    "arm64_sequoia": {"url": "https://ghcr.io/v2/homebrew/core/jq/blobs/sha256:a1", "sha256": "a1"},
+    "arm64_ventura": {"url": "https://ghcr.io/v2/homebrew/core/jq/blobs/sha256:a2", "sha256": "a2"},
+    "sonoma":        {"url": "https://ghcr.io/v2/homebrew/core/jq/blobs/sha256:b1", "sha256": "b1"},
+    "x86_64_linux":  {"url": "https://ghcr.io/v2/homebrew/core/jq/blobs/sha256:c1", "sha256": "c1"}
+  }}}
+}`
+
+const caskJSON = `{
+  "token": "ngrok",
+  "version": "3.5.0",
+  "url": "https://bin.example.com/ngrok-v3-stable-darwin-arm64.zip",
+  "sha256": "d1",
+  "artifacts": [
+    {"binary": ["ngrok", {"target": "ngrok"}]},
+    {"binary": ["$APPDIR/Tool.app/Contents/MacOS/tool"]},
+    {"uninstall": [{"delete": "/tmp/x"}]}
+  ],
+  "variations": {
+    "sonoma": {"url": "https://bin.example.com/ngrok-v3-stable-darwin-amd64.zip", "sha256": "d2"}
+  }
+}`
+
+func TestGetFormula(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/formula/jq.json":
+			w.Write([]byte(formulaJSON))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	client := &Client{BaseURL: server.URL}
+	formula, err := client.GetFormula("jq")
+	if err != nil {
+		t.Fatalf("GetFormula() error = %v", err)
+	}
+	if formula.Version() != "1.7.1_1" {
+		t.Errorf("Expected version 1.7.1_1, got %s", formula.Version())
+	}
+
+	expected := []Download{
+		{Platform: "darwin-amd64", URL: "https://ghcr.io/v2/homebrew/core/jq/blobs/sha256:b1", SHA256: "b1"},
+		{Platform: "darwin-arm64", URL: "https://ghcr.io/v2/homebrew/core/jq/blobs/sha256:a2", SHA256: "a2"},
+		{Platform: "linux-amd64", URL: "https://ghcr.io/v2/homebrew/core/jq/blobs/sha256:c1", SHA256: "c1"},
+	}
+	if got := formula.Downloads(); !reflect.DeepEqual(got, expected) {
+		t.Errorf("Expected oldest macOS bottle per platform %v, got %v", expected, got)
+	}
+
+	if _, err := client.GetFormula("missing"); err == nil {
+		t.Error("Expected error for missing formula")
+	}
+}
+
+func TestFormulaAllBottle(t *testing.T) {
+	var formula Formula
+	formula.Bottle.Stable.Files = map[string]BottleFile{
+		"all":          {URL: "https://ghcr.io/all", SHA256: "e1"},
+		"x86_64_linux": {URL: "https://ghcr.io/linux", SHA256: "e2"},
+	}
+
+	downloads := formula.Downloads()
+	if len(downloads) != 4 {
+		t.Fatalf("Expected a download for every platform, got %v", downloads)
+	}
+	for _, d := range downloads {
+		want := "https://ghcr.io/all"
+		if d.Platform == "linux-amd64" {
+			want = "https://ghcr.io/linux"
+		}
+		if d.URL != want {
+			t.Errorf("Expected %s for %s, got %s", want, d.Platform, d.URL)
+		}
+	}
+}
+
+func TestGetCask(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(caskJSON))
+	}))
+	defer server.Close()
+
+	cask, err := (&Client{BaseURL: server.URL}).GetCask("ngrok")
+	if err != nil {
+		t.Fatalf("GetCask() error = %v", err)
+	}
+
+	expected := []Download{
+		{Platform: "darwin-arm64", URL: "https://bin.example.com/ngrok-v3-stable-darwin-arm64.zip", SHA256: "d1"},
+		{Platform: "darwin-amd64", URL: "https://bin.example.com/ngrok-v3-stable-darwin-amd64.zip", SHA256: "d2"},
+	}
+	if got := cask.Downloads(); !reflect.DeepEqual(got, expected) {
+		t.Errorf("Expected %v, got %v", expected, got)
+	}
+	if got := cask.Binaries(); !reflect.DeepEqual(got, []string{"ngrok"}) {
+		t.Errorf("Expected [ngrok], got %v", got)
+	}
+}
+
+func TestCaskWithoutChecksum(t *testing.T) {
+	cask := Cask{URL: "https://example.com/tool.zip", SHA256: "no_check"}
+	downloads := cask.Downloads()
+	if len(downloads) != 1 || downloads[0].SHA256 != "" || downloads[0].Platform != "darwin-universal" {
+		t.Errorf("Expected one universal download without checksum, got %v", downloads)
+	}
+}
+
+func TestIsBottleURL(t *testing.T) {
+	if !IsBottleURL("https://ghcr.io/v2/homebrew/core/jq/blobs/sha256:abc") {
+		t.Error("Expected ghcr.io URL to be a bottle")
+	}
+	if IsBottleURL("https://github.com/jqlang/jq/releases/download/jq-1.7.1/jq-macos-arm64") {
+		t.Error("Expected GitHub URL not to be a bottle")
+	}
+}
@@ -0,0 +1,76 @@
+package brew
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// Brewfile entry kinds that install packages
+const (
+	KindFormula = "brew"
+	KindCask    = "cask"
+)
+
+// Entry is a formula or cask listed in a Brewfile
+type Entry struct {
+	Kind string // KindFormula or KindCask
+	Name string // As written, possibly tap-qualified (e.g. go-task/tap/go-task)
+	Line int
+}
+
+// ShortName returns the entry's name without its tap, e.g. go-task for go-task/tap/go-task
+func (e Entry) ShortName() string {
+	return e.Name[strings.LastIndex(e.Name, "/")+1:]
+}
+
+// LoadBrewfile reads the formulae and casks of a Brewfile
+func LoadBrewfile(path string) ([]Entry, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read Brewfile: %w", err)
+	}
+	entries, err := ParseBrewfile(data)
+	if err != nil {
+		return nil, fmt.Errorf("invalid Brewfile %s: %w", path, err)
+	}
+	return entries, nil
+}
+
+// ParseBrewfile reads the brew and cask lines of a Brewfile. Other directives (tap,
+// mas, vscode, ...) and options after the name are ignored; Brewfiles are Ruby, so
+// only the common one-entry-per-line form is understood.
+func ParseBrewfile(data []byte) ([]Entry, error) {
+	var entries []Entry
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for n := 1; scanner.Scan(); n++ {
+		line := strings.TrimSpace(scanner.Text())
+		if i := strings.Index(line, "#"); i >= 0 && !strings.ContainsAny(line[:i], `"'`) {
+			line = strings.TrimSpace(line[:i])
+		}
+		kind, rest, ok := strings.Cut(line, " ")
+		if !ok || (kind != KindFormula && kind != KindCask) {
+			continue
+		}
+		name, err := quoted(strings.TrimSpace(rest))
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", n, err)
+		}
+		entries = append(entries, Entry{Kind: kind, Name: name, Line: n})
+	}
+	return entries, scanner.Err()
+}
+
+// quoted returns the leading Ruby string literal of s
+func quoted(s string) (string, error) {
+	if s == "" || (s[0] != '"' && s[0] != '\'') {
+		return "", fmt.Errorf("expected a quoted name: %s", s)
+	}
+	end := strings.IndexByte(s[1:], s[0])
+	if end < 1 {
+		return "", fmt.Errorf("unterminated or empty name: %s", s)
+	}
+	return s[1 : end+1], nil
+}
@@ -0,0 +1,41 @@
+package brew
+
+import "testing"
+
+func TestParseBrewfile(t *testing.T) {
+	entries, err := ParseBrewfile([]byte(`# Development tools
+tap "go-task/tap"
+brew "ripgrep"
+brew 'jq' # JSON
+brew "go-task/tap/go-task", args: ["HEAD"]
+cask "visual-studio-code"
+mas "Xcode", id: 497799835
+vscode "golang.go"
+`))
+	if err != nil {
+		t.Fatalf("ParseBrewfile() error = %v", err)
+	}
+	want := []Entry{
+		{Kind: KindFormula, Name: "ripgrep", Line: 3},
+		{Kind: KindFormula, Name: "jq", Line: 4},
+		{Kind: KindFormula, Name: "go-task/tap/go-task", Line: 5},
+		{Kind: KindCask, Name: "visual-studio-code", Line: 6},
+	}
+	if len(entries) != len(want) {
+		t.Fatalf("ParseBrewfile() = %+v", entries)
+	}
+	for i := range want {
+		if entries[i] != want[i] {
+			t.Errorf("entries[%d] = %+v, want %+v", i, entries[i], want[i])
+		}
+	}
+	if name := entries[2].ShortName(); name != "go-task" {
+		t.Errorf("ShortName() = %s", name)
+	}
+
+	for _, invalid := range []string{`brew ripgrep`, `brew "ripgrep`, `cask ""`} {
+		if _, err := ParseBrewfile([]byte(invalid)); err == nil {
+			t.Errorf("Expected error for %q", invalid)
+		}
+	}
+}
@@ -0,0 +1,418 @@
+package cacheproxy
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pyhub-kr/pyhub-installer/internal/useragent"
+)
+
+// Paths under which the server answers for the GitHub API and github.com downloads.
+// Clients use <server>/api as their API URL and <server>/download/{path} as a mirror.
+const (
+	APIPath      = "/api/"
+	DownloadPath = "/download/"
+	StatusPath   = "/v1/status"
+)
+
+// releasePath matches the API endpoints of releases, tags, release assets and tag
+// archives, the only ones served. Others, e.g. /user, could expose account data.
+var releasePath = regexp.MustCompile(`^repos/([^/]+)/([^/]+)/(releases(/latest|/tags/.+|/[0-9]+(/assets)?|/assets/[0-9]+)?|tags|tarball/.+)$`)
+
+// DefaultMetadataTTL is how long API responses are served from the cache before they
+// are revalidated
+const DefaultMetadataTTL = 5 * time.Minute
+
+// maxErrorBody limits how much of an upstream error response is relayed
+const maxErrorBody = 1 << 20
+
+// Server is a shared HTTP cache for GitHub release metadata and assets. API responses
+// are cached for MetadataTTL and then revalidated with their ETag; release downloads
+// never change and are cached until evicted. Requests carrying their own credentials
+// are passed through uncached, and anonymous clients only get what GitHub serves
+// anonymously, so private responses are never shared.
+type Server struct {
+	Dir         string        // Cache directory
+	Token       string        // Token for upstream API requests of anonymous clients, only for public repositories
+	MetadataTTL time.Duration // Defaults to DefaultMetadataTTL
+	Version     string        // Reported by /v1/status
+	APIURL      string        // Upstream API, default https://api.github.com
+	DownloadURL string        // Upstream downloads, default https://github.com
+	HTTPClient  *http.Client  // Client for upstream requests
+
+	mu         sync.Mutex
+	inflight   map[string]*fetch
+	visibility map[string]visibility // By owner/repo, checked when Token is set
+}
+
+// visibility records whether a repository is private, as checked with the token
+type visibility struct {
+	private bool
+	checked time.Time
+}
+
+// fetch is an upstream request shared by concurrent requests for the same entry
+type fetch struct {
+	done chan struct{}
+	err  error
+}
+
+// meta is stored next to a cached response body
+type meta struct {
+	URL         string    `json:"url"`
+	ContentType string    `json:"content_type,omitempty"`
+	Link        string    `json:"link,omitempty"` // API pagination
+	ETag        string    `json:"etag,omitempty"`
+	Fetched     time.Time `json:"fetched"`
+}
+
+// upstreamError relays a non-200 upstream response, which is not cached
+type upstreamError struct {
+	status      int
+	contentType string
+	body        []byte
+}
+
+func (e *upstreamError) Error() string {
+	return fmt.Sprintf("upstream returned %d", e.status)
+}
+
+// NewServer creates a server caching into dir
+func NewServer(dir string) *Server {
+	return &Server{
+		Dir:         dir,
+		MetadataTTL: DefaultMetadataTTL,
+		APIURL:      "https://api.github.com",
+		DownloadURL: "https://github.com",
+		HTTPClient:  &http.Client{},
+		inflight:    make(map[string]*fetch),
+		visibility:  make(map[string]visibility),
+	}
+}
+
+// StatusResponse is the body of GET /v1/status
+type StatusResponse struct {
+	Version string `json:"version"`
+}
+
+// Handler returns the HTTP handler of the cache
+func (s *Server) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET "+StatusPath, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(StatusResponse{Version: s.Version})
+	})
+	mux.HandleFunc("GET "+APIPath+"{path...}", s.handleAPI)
+	mux.HandleFunc("GET "+DownloadPath+"{path...}", s.handleDownload)
+	return mux
+}
+
+func (s *Server) handleAPI(w http.ResponseWriter, r *http.Request) {
+	match := releasePath.FindStringSubmatch(r.PathValue("path"))
+	if match == nil {
+		http.Error(w, "only release, tag and asset endpoints are served", http.StatusNotFound)
+		return
+	}
+	// With the token, GitHub would also answer for private repositories
+	if s.Token != "" && r.Header.Get("Authorization") == "" {
+		private, err := s.private(match[1], match[2])
+		if err != nil {
+			writeUpstreamError(w, err)
+			return
+		}
+		if private {
+			http.Error(w, "Not Found", http.StatusNotFound)
+			return
+		}
+	}
+
+	upstream := s.APIURL + "/" + r.PathValue("path")
+	if r.URL.RawQuery != "" {
+		upstream += "?" + r.URL.RawQuery
+	}
+	s.serve(w, r, "api", upstream, s.MetadataTTL)
+}
+
+func (s *Server) handleDownload(w http.ResponseWriter, r *http.Request) {
+	path := r.PathValue("path")
+	// Only release assets and source archives, not arbitrary github.com pages
+	if !strings.Contains(path, "/releases/download/") && !strings.Contains(path, "/archive/") {
+		http.Error(w, "not a release download", http.StatusNotFound)
+		return
+	}
+	s.serve(w, r, "download", s.DownloadURL+"/"+path, 0)
+}
+
+// serve answers a request from the cache, fetching the upstream URL first if the entry
+// is missing or older than ttl (0 for never)
+func (s *Server) serve(w http.ResponseWriter, r *http.Request, kind, upstream string, ttl time.Duration) {
+	if auth := r.Header.Get("Authorization"); auth != "" {
+		s.passThrough(w, r, upstream, auth)
+		return
+	}
+
+	sum := sha256.Sum256([]byte(upstream))
+	key := filepath.Join(kind, hex.EncodeToString(sum[:]))
+	if err := s.ensure(key, upstream, kind == "api", ttl); err != nil {
+		writeUpstreamError(w, err)
+		return
+	}
+
+	m, err := s.readMeta(key)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	body, err := os.Open(s.path(key) + ".body")
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	defer body.Close()
+
+	if m.ContentType != "" {
+		w.Header().Set("Content-Type", m.ContentType)
+	}
+	if m.Link != "" {
+		w.Header().Set("Link", s.rewriteLink(m.Link, r))
+	}
+	w.Header().Set("X-Cache-Fetched", m.Fetched.UTC().Format(time.RFC3339))
+	http.ServeContent(w, r, "", m.Fetched, body)
+}
+
+// ensure makes sure a fresh entry is cached, fetching it once for concurrent requests
+func (s *Server) ensure(key, upstream string, api bool, ttl time.Duration) error {
+	if m, err := s.readMeta(key); err == nil && (ttl == 0 || time.Since(m.Fetched) < ttl) {
+		return nil
+	}
+
+	s.mu.Lock()
+	if f, ok := s.inflight[key]; ok {
+		s.mu.Unlock()
+		<-f.done
+		return f.err
+	}
+	f := &fetch{done: make(chan struct{})}
+	s.inflight[key] = f
+	s.mu.Unlock()
+
+	f.err = s.fetch(key, upstream, api)
+	s.mu.Lock()
+	delete(s.inflight, key)
+	s.mu.Unlock()
+	close(f.done)
+	return f.err
+}
+
+// fetch downloads an upstream URL into the cache, with the server token for API
+// requests. A stale entry with an ETag is revalidated, which GitHub does not count
+// against the rate limit.
+func (s *Server) fetch(key, upstream string, api bool) error {
+	req, err := http.NewRequest("GET", upstream, nil)
+	if err != nil {
+		return err
+	}
+	useragent.Apply(req)
+	if s.Token != "" && api {
+		req.Header.Set("Authorization", "Bearer "+s.Token)
+	}
+	old, oldErr := s.readMeta(key)
+	if oldErr == nil && old.ETag != "" {
+		req.Header.Set("If-None-Match", old.ETag)
+	}
+
+	resp, err := s.HTTPClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotModified && oldErr == nil {
+		old.Fetched = time.Now()
+		return s.writeMeta(key, old)
+	}
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, maxErrorBody))
+		return &upstreamError{status: resp.StatusCode, contentType: resp.Header.Get("Content-Type"), body: body}
+	}
+
+	path := s.path(key)
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := io.Copy(tmp, resp.Body); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	if err := os.Rename(tmp.Name(), path+".body"); err != nil {
+		return err
+	}
+	return s.writeMeta(key, &meta{
+		URL:         upstream,
+		ContentType: resp.Header.Get("Content-Type"),
+		Link:        resp.Header.Get("Link"),
+		ETag:        resp.Header.Get("ETag"),
+		Fetched:     time.Now(),
+	})
+}
+
+// private reports whether a repository is private, checking with the token at most
+// once per MetadataTTL
+func (s *Server) private(owner, repo string) (bool, error) {
+	name := strings.ToLower(owner + "/" + repo)
+	s.mu.Lock()
+	v, ok := s.visibility[name]
+	s.mu.Unlock()
+	if ok && time.Since(v.checked) < s.MetadataTTL {
+		return v.private, nil
+	}
+
+	req, err := http.NewRequest("GET", s.APIURL+"/repos/"+owner+"/"+repo, nil)
+	if err != nil {
+		return false, err
+	}
+	useragent.Apply(req)
+	req.Header.Set("Authorization", "Bearer "+s.Token)
+	resp, err := s.HTTPClient.Do(req)
+	if err != nil {
+		return false, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, maxErrorBody))
+		return false, &upstreamError{status: resp.StatusCode, contentType: resp.Header.Get("Content-Type"), body: body}
+	}
+	var repository struct {
+		Private bool `json:"private"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&repository); err != nil {
+		return false, fmt.Errorf("failed to decode repository: %w", err)
+	}
+
+	s.mu.Lock()
+	s.visibility[name] = visibility{private: repository.Private, checked: time.Now()}
+	s.mu.Unlock()
+	return repository.Private, nil
+}
+
+// passThrough relays a request with the client's own credentials without caching
+func (s *Server) passThrough(w http.ResponseWriter, r *http.Request, upstream, auth string) {
+	req, err := http.NewRequestWithContext(r.Context(), "GET", upstream, nil)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	req.Header.Set("Authorization", auth)
+	for _, header := range []string{"Accept", "Range", "If-None-Match", "User-Agent"} {
+		if value := r.Header.Get(header); value != "" {
+			req.Header.Set(header, value)
+		}
+	}
+	resp, err := s.HTTPClient.Do(req)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadGateway)
+		return
+	}
+	defer resp.Body.Close()
+
+	for _, header := range []string{"Content-Type", "Content-Length", "Content-Range", "Accept-Ranges", "ETag"} {
+		if value := resp.Header.Get(header); value != "" {
+			w.Header().Set(header, value)
+		}
+	}
+	if link := resp.Header.Get("Link"); link != "" {
+		w.Header().Set("Link", s.rewriteLink(link, r))
+	}
+	w.WriteHeader(resp.StatusCode)
+	io.Copy(w, resp.Body)
+}
+
+// rewriteLink points the API pagination links of a response back at the cache
+func (s *Server) rewriteLink(link string, r *http.Request) string {
+	scheme := "http"
+	if r.TLS != nil {
+		scheme = "https"
+	}
+	return strings.ReplaceAll(link, s.APIURL+"/", scheme+"://"+r.Host+APIPath)
+}
+
+// writeUpstreamError relays an upstream error response, or reports a failed request
+func writeUpstreamError(w http.ResponseWriter, err error) {
+	if upstream, ok := err.(*upstreamError); ok {
+		if upstream.contentType != "" {
+			w.Header().Set("Content-Type", upstream.contentType)
+		}
+		w.WriteHeader(upstream.status)
+		w.Write(upstream.body)
+		return
+	}
+	http.Error(w, err.Error(), http.StatusBadGateway)
+}
+
+// path returns the cache path of an entry, without extension
+func (s *Server) path(key string) string {
+	return filepath.Join(s.Dir, key)
+}
+
+// readMeta reads the metadata of a cached entry
+func (s *Server) readMeta(key string) (*meta, error) {
+	data, err := os.ReadFile(s.path(key) + ".json")
+	if err != nil {
+		return nil, err
+	}
+	var m meta
+	if err := json.Unmarshal(data, &m); err != nil {
+		return nil, err
+	}
+	return &m, nil
+}
+
+// writeMeta writes the metadata of a cached entry, after its body
+func (s *Server) writeMeta(key string, m *meta) error {
+	data, err := json.Marshal(m)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(s.path(key)+".json", data, 0644)
+}
+
+// APIURL returns the GitHub API URL clients of a cache server use
+func APIURL(server string) string {
+	return strings.TrimRight(server, "/") + strings.TrimSuffix(APIPath, "/")
+}
+
+// MirrorTemplate returns the download mirror template clients of a cache server use
+func MirrorTemplate(server string) string {
+	return strings.TrimRight(server, "/") + DownloadPath + "{path}"
+}
+
+// Ping checks that a cache server is reachable
+func Ping(client *http.Client, server string) error {
+	resp, err := client.Get(strings.TrimRight(server, "/") + StatusPath)
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("cache server %s returned %d", server, resp.StatusCode)
+	}
+	return nil
+}
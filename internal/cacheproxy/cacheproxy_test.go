@@ -0,0 +1,216 @@
+package cacheproxy
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// newTestCache starts a cache in front of a fake GitHub serving the API and downloads
+func newTestCache(t *testing.T) (*Server, *httptest.Server, *atomic.Int32, *[]string) {
+	t.Helper()
+	var requests atomic.Int32
+	var mu sync.Mutex
+	var auths []string
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		mu.Lock()
+		auths = append(auths, r.Header.Get("Authorization"))
+		mu.Unlock()
+		switch {
+		case r.URL.Path == "/repos/owner/tool/releases":
+			if r.Header.Get("If-None-Match") == `"v1"` {
+				w.WriteHeader(http.StatusNotModified)
+				return
+			}
+			w.Header().Set("Content-Type", "application/json")
+			w.Header().Set("ETag", `"v1"`)
+			w.Header().Set("Link", `<`+"http://"+r.Host+`/repos/owner/tool/releases?page=2>; rel="next"`)
+			io.WriteString(w, `[{"tag_name":"v1.0.0"}]`)
+		case r.URL.Path == "/repos/owner/tool":
+			io.WriteString(w, `{"private":false}`)
+		case r.URL.Path == "/repos/owner/secret":
+			io.WriteString(w, `{"private":true}`)
+		case r.URL.Path == "/repos/owner/secret/releases":
+			io.WriteString(w, `[{"tag_name":"v0.1.0"}]`)
+		case r.URL.Path == "/owner/tool/releases/download/v1.0.0/tool.tar.gz":
+			io.WriteString(w, "archive contents")
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	t.Cleanup(upstream.Close)
+
+	s := NewServer(t.TempDir())
+	s.APIURL = upstream.URL
+	s.DownloadURL = upstream.URL
+	server := httptest.NewServer(s.Handler())
+	t.Cleanup(server.Close)
+	return s, server, &requests, &auths
+}
+
+func get(t *testing.T, url string, headers map[string]string) (*http.Response, string) {
+	t.Helper()
+	req, err := http.NewRequest("GET", url, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for key, value := range headers {
+		req.Header.Set(key, value)
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return resp, string(body)
+}
+
+func TestAPICache(t *testing.T) {
+	s, server, requests, auths := newTestCache(t)
+
+	for i := 0; i < 2; i++ {
+		resp, body := get(t, server.URL+"/api/repos/owner/tool/releases", nil)
+		if resp.StatusCode != http.StatusOK || body != `[{"tag_name":"v1.0.0"}]` {
+			t.Fatalf("GET = %d %s", resp.StatusCode, body)
+		}
+		if !strings.Contains(resp.Header.Get("Link"), server.URL+"/api/repos/owner/tool/releases?page=2") {
+			t.Errorf("Expected pagination links to point at the cache: %s", resp.Header.Get("Link"))
+		}
+	}
+	if requests.Load() != 1 {
+		t.Errorf("Expected one upstream request, got %d", requests.Load())
+	}
+	if (*auths)[0] != "" {
+		t.Errorf("Expected an anonymous upstream request without a shared token, got %q", (*auths)[0])
+	}
+
+	// A stale entry is revalidated with its ETag
+	s.MetadataTTL = time.Nanosecond
+	resp, body := get(t, server.URL+"/api/repos/owner/tool/releases", nil)
+	if resp.StatusCode != http.StatusOK || body != `[{"tag_name":"v1.0.0"}]` || requests.Load() != 2 {
+		t.Errorf("Revalidation = %d %s after %d requests", resp.StatusCode, body, requests.Load())
+	}
+
+	resp, _ = get(t, server.URL+"/api/repos/owner/missing/releases", nil)
+	if resp.StatusCode != http.StatusNotFound {
+		t.Errorf("Expected the upstream 404 to be relayed, got %d", resp.StatusCode)
+	}
+}
+
+func TestSharedToken(t *testing.T) {
+	s, server, requests, auths := newTestCache(t)
+	s.Token = "server-token"
+
+	for i := 0; i < 2; i++ {
+		resp, _ := get(t, server.URL+"/api/repos/owner/tool/releases", nil)
+		if resp.StatusCode != http.StatusOK {
+			t.Fatalf("GET = %d", resp.StatusCode)
+		}
+	}
+	// One visibility check and one release list, both cached
+	if requests.Load() != 2 {
+		t.Errorf("Expected two upstream requests, got %d", requests.Load())
+	}
+	for _, auth := range *auths {
+		if auth != "Bearer server-token" {
+			t.Errorf("Expected the server token upstream, got %q", auth)
+		}
+	}
+
+	resp, body := get(t, server.URL+"/api/repos/owner/secret/releases", nil)
+	if resp.StatusCode != http.StatusNotFound || strings.Contains(body, "v0.1.0") {
+		t.Errorf("Expected private repositories to be hidden from anonymous clients, got %d %s", resp.StatusCode, body)
+	}
+	if requests.Load() != 3 {
+		t.Errorf("Expected only the visibility check of the private repository, got %d upstream requests", requests.Load())
+	}
+}
+
+func TestAPIAllowlist(t *testing.T) {
+	_, server, requests, _ := newTestCache(t)
+
+	for _, path := range []string{"user", "repos/owner/tool/actions/runs", "search/repositories?q=tool"} {
+		for _, headers := range []map[string]string{nil, {"Authorization": "Bearer client-token"}} {
+			resp, _ := get(t, server.URL+"/api/"+path, headers)
+			if resp.StatusCode != http.StatusNotFound {
+				t.Errorf("GET /api/%s = %d, want 404", path, resp.StatusCode)
+			}
+		}
+	}
+	if requests.Load() != 0 {
+		t.Errorf("Expected no upstream requests, got %d", requests.Load())
+	}
+}
+
+func TestDownloadCache(t *testing.T) {
+	_, server, requests, auths := newTestCache(t)
+	url := server.URL + "/download/owner/tool/releases/download/v1.0.0/tool.tar.gz"
+
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, body := get(t, url, nil); body != "archive contents" {
+				t.Errorf("GET = %q", body)
+			}
+		}()
+	}
+	wg.Wait()
+	if requests.Load() != 1 {
+		t.Errorf("Expected concurrent requests to share one upstream download, got %d", requests.Load())
+	}
+	if (*auths)[0] != "" {
+		t.Errorf("The server token must not be sent with downloads, got %q", (*auths)[0])
+	}
+
+	resp, body := get(t, url, map[string]string{"Range": "bytes=8-"})
+	if resp.StatusCode != http.StatusPartialContent || body != "contents" {
+		t.Errorf("Range request = %d %q", resp.StatusCode, body)
+	}
+
+	resp, _ = get(t, server.URL+"/download/owner/tool/settings", nil)
+	if resp.StatusCode != http.StatusNotFound {
+		t.Errorf("Expected non-release paths to be refused, got %d", resp.StatusCode)
+	}
+}
+
+func TestPassThrough(t *testing.T) {
+	_, server, requests, auths := newTestCache(t)
+
+	for i := 0; i < 2; i++ {
+		resp, _ := get(t, server.URL+"/api/repos/owner/tool/releases", map[string]string{"Authorization": "Bearer client-token"})
+		if resp.StatusCode != http.StatusOK {
+			t.Fatalf("GET = %d", resp.StatusCode)
+		}
+	}
+	if requests.Load() != 2 {
+		t.Errorf("Expected authenticated requests to bypass the cache, got %d upstream requests", requests.Load())
+	}
+	if (*auths)[0] != "Bearer client-token" {
+		t.Errorf("Expected the client's credentials upstream, got %q", (*auths)[0])
+	}
+}
+
+func TestClientURLs(t *testing.T) {
+	_, server, _, _ := newTestCache(t)
+	if err := Ping(http.DefaultClient, server.URL+"/"); err != nil {
+		t.Errorf("Ping() error = %v", err)
+	}
+	if got := APIURL("http://cache.lan:7879/"); got != "http://cache.lan:7879/api" {
+		t.Errorf("APIURL() = %s", got)
+	}
+	if got := MirrorTemplate("http://cache.lan:7879"); got != "http://cache.lan:7879/download/{path}" {
+		t.Errorf("MirrorTemplate() = %s", got)
+	}
+}
@@ -0,0 +1,315 @@
+package clean
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/pyhub-kr/pyhub-installer/internal/store"
+)
+
+// CurrentLink is the name of the link in a tool's store directory that points to the active version
+const CurrentLink = store.CurrentLink
+
+// Cleaner removes stale installer-managed files
+type Cleaner struct {
+	StoreDir     string   // Versioned installs: <store>/<tool>/<version>
+	BackupDir    string   // Files kept after being replaced
+	CacheDir     string   // Downloaded artifacts and API responses
+	BinDirs      []string // Directories holding links to installed executables
+	ManagedRoots []string // Link targets under these roots are considered ours
+	MaxAge       time.Duration
+	MaxCacheSize int64 // Total cache size in bytes evicted down to, oldest first; 0 for no limit
+	DryRun       bool
+}
+
+// Result summarizes what was (or would be) removed
+type Result struct {
+	Removed        []string
+	ReclaimedBytes int64
+}
+
+// NewCleaner creates a new cleaner
+func NewCleaner(storeDir, backupDir, cacheDir string, maxAge time.Duration) *Cleaner {
+	return &Cleaner{
+		StoreDir:     storeDir,
+		BackupDir:    backupDir,
+		CacheDir:     cacheDir,
+		ManagedRoots: []string{storeDir},
+		MaxAge:       maxAge,
+	}
+}
+
+// Clean removes superseded versions, orphaned links, stale backups and old cache entries
+func (c *Cleaner) Clean() (*Result, error) {
+	result := &Result{}
+
+	if err := c.cleanVersions(result); err != nil {
+		return result, fmt.Errorf("failed to clean versions: %w", err)
+	}
+	if err := c.cleanSymlinks(result); err != nil {
+		return result, fmt.Errorf("failed to clean symlinks: %w", err)
+	}
+	if err := c.cleanOlderThan(c.BackupDir, result); err != nil {
+		return result, fmt.Errorf("failed to clean backups: %w", err)
+	}
+	if err := c.cleanCache(result); err != nil {
+		return result, fmt.Errorf("failed to clean cache: %w", err)
+	}
+
+	return result, nil
+}
+
+// cleanVersions removes every version directory except the active one for each tool
+func (c *Cleaner) cleanVersions(result *Result) error {
+	tools, err := readDirIfExists(c.StoreDir)
+	if err != nil {
+		return err
+	}
+
+	for _, tool := range tools {
+		if !tool.IsDir() {
+			continue
+		}
+		toolDir := filepath.Join(c.StoreDir, tool.Name())
+
+		versions, err := os.ReadDir(toolDir)
+		if err != nil {
+			return err
+		}
+
+		current := currentVersion(toolDir, versions)
+		for _, version := range versions {
+			if !version.IsDir() || version.Name() == current {
+				continue
+			}
+			if err := c.remove(filepath.Join(toolDir, version.Name()), result); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// currentVersion returns the version the current link points to, or the newest version
+func currentVersion(toolDir string, versions []os.DirEntry) string {
+	if current := store.Current(toolDir); current != "" {
+		return current
+	}
+
+	// Without a current link, keep the most recently installed version
+	var newest string
+	var newestTime time.Time
+	for _, version := range versions {
+		if !version.IsDir() {
+			continue
+		}
+		info, err := version.Info()
+		if err != nil {
+			continue
+		}
+		if newest == "" || info.ModTime().After(newestTime) {
+			newest = version.Name()
+			newestTime = info.ModTime()
+		}
+	}
+	return newest
+}
+
+// cleanSymlinks removes links in bin directories whose managed target no longer exists
+func (c *Cleaner) cleanSymlinks(result *Result) error {
+	for _, binDir := range c.BinDirs {
+		entries, err := readDirIfExists(binDir)
+		if err != nil {
+			return err
+		}
+
+		for _, entry := range entries {
+			if entry.Type()&os.ModeSymlink == 0 {
+				continue
+			}
+			linkPath := filepath.Join(binDir, entry.Name())
+
+			target, err := os.Readlink(linkPath)
+			if err != nil {
+				continue
+			}
+			if !filepath.IsAbs(target) {
+				target = filepath.Join(binDir, target)
+			}
+			if !c.isManaged(target) {
+				continue
+			}
+			if _, err := os.Stat(target); os.IsNotExist(err) {
+				if err := c.remove(linkPath, result); err != nil {
+					return err
+				}
+			}
+		}
+	}
+
+	return nil
+}
+
+// isManaged checks if a path lies under one of the managed roots
+func (c *Cleaner) isManaged(path string) bool {
+	path = filepath.Clean(path)
+	for _, root := range c.ManagedRoots {
+		if root == "" {
+			continue
+		}
+		root = filepath.Clean(root)
+		if path == root || strings.HasPrefix(path, root+string(os.PathSeparator)) {
+			return true
+		}
+	}
+	return false
+}
+
+// cleanOlderThan removes top-level entries of dir last modified before MaxAge
+func (c *Cleaner) cleanOlderThan(dir string, result *Result) error {
+	entries, err := readDirIfExists(dir)
+	if err != nil {
+		return err
+	}
+
+	cutoff := time.Now().Add(-c.MaxAge)
+	for _, entry := range entries {
+		info, err := entry.Info()
+		if err != nil {
+			continue
+		}
+		if info.ModTime().Before(cutoff) {
+			if err := c.remove(filepath.Join(dir, entry.Name()), result); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// PruneCache removes cache files last modified before MaxAge, then the least recently
+// modified files until the cache fits MaxCacheSize
+func (c *Cleaner) PruneCache() (*Result, error) {
+	result := &Result{}
+	if err := c.cleanCache(result); err != nil {
+		return result, fmt.Errorf("failed to prune cache: %w", err)
+	}
+	return result, nil
+}
+
+// cleanCache removes cache files at any depth that are older than MaxAge or exceed
+// MaxCacheSize
+func (c *Cleaner) cleanCache(result *Result) error {
+	if c.CacheDir == "" {
+		return nil
+	}
+
+	cutoff := time.Now().Add(-c.MaxAge)
+	var kept []cacheFile
+	var total int64
+	err := filepath.Walk(c.CacheDir, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			if os.IsNotExist(err) {
+				return nil
+			}
+			return err
+		}
+		if info.IsDir() {
+			return nil
+		}
+		if info.ModTime().Before(cutoff) {
+			return c.remove(path, result)
+		}
+		kept = append(kept, cacheFile{path, info})
+		total += info.Size()
+		return nil
+	})
+	if err != nil || c.MaxCacheSize <= 0 || total <= c.MaxCacheSize {
+		return err
+	}
+
+	sort.SliceStable(kept, func(i, j int) bool {
+		return kept[i].info.ModTime().Before(kept[j].info.ModTime())
+	})
+	for _, file := range kept {
+		if total <= c.MaxCacheSize {
+			break
+		}
+		if err := c.remove(file.path, result); err != nil {
+			return err
+		}
+		total -= file.info.Size()
+	}
+	return nil
+}
+
+// cacheFile is a cache entry kept by the age pass of cleanCache
+type cacheFile struct {
+	path string
+	info os.FileInfo
+}
+
+// remove deletes a path (unless dry-run) and records the reclaimed space
+func (c *Cleaner) remove(path string, result *Result) error {
+	size, err := DirSize(path)
+	if err != nil {
+		return err
+	}
+
+	if !c.DryRun {
+		if err := os.RemoveAll(path); err != nil {
+			return err
+		}
+	}
+
+	result.Removed = append(result.Removed, path)
+	result.ReclaimedBytes += size
+	return nil
+}
+
+// readDirIfExists lists a directory, treating a missing directory as empty
+func readDirIfExists(dir string) ([]os.DirEntry, error) {
+	if dir == "" {
+		return nil, nil
+	}
+	entries, err := os.ReadDir(dir)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	return entries, err
+}
+
+// DirSize returns the total size of regular files under path (links are not followed)
+func DirSize(path string) (int64, error) {
+	var size int64
+	err := filepath.Walk(path, func(_ string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if info.Mode().IsRegular() {
+			size += info.Size()
+		}
+		return nil
+	})
+	return size, err
+}
+
+// FormatSize formats a byte count for humans (e.g. "1.5 MB")
+func FormatSize(bytes int64) string {
+	const unit = 1024
+	if bytes < unit {
+		return fmt.Sprintf("%d B", bytes)
+	}
+	div, exp := int64(unit), 0
+	for n := bytes / unit; n >= unit; n /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %cB", float64(bytes)/float64(div), "KMGTPE"[exp])
+}
@@ -0,0 +1,269 @@
+package clean
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+	"time"
+)
+
+func writeFile(t *testing.T, path string, size int, modTime time.Time) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, make([]byte, size), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chtimes(path, modTime, modTime); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestNewCleaner(t *testing.T) {
+	cleaner := NewCleaner("/store", "/backups", "/cache", time.Hour)
+
+	if cleaner.StoreDir != "/store" {
+		t.Errorf("Expected StoreDir to be /store, got %s", cleaner.StoreDir)
+	}
+	if cleaner.MaxAge != time.Hour {
+		t.Errorf("Expected MaxAge to be 1h, got %v", cleaner.MaxAge)
+	}
+	if len(cleaner.ManagedRoots) != 1 || cleaner.ManagedRoots[0] != "/store" {
+		t.Errorf("Expected store to be a managed root, got %v", cleaner.ManagedRoots)
+	}
+}
+
+func TestCleanVersions(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("Skipping symlink test on Windows")
+	}
+
+	tempDir := t.TempDir()
+	storeDir := filepath.Join(tempDir, "store")
+	now := time.Now()
+
+	writeFile(t, filepath.Join(storeDir, "tool", "v1.0.0", "tool"), 100, now)
+	writeFile(t, filepath.Join(storeDir, "tool", "v1.1.0", "tool"), 200, now)
+	writeFile(t, filepath.Join(storeDir, "tool", "v2.0.0", "tool"), 300, now)
+
+	// v1.1.0 is active even though it is not the newest
+	if err := os.Symlink(filepath.Join(storeDir, "tool", "v1.1.0"), filepath.Join(storeDir, "tool", CurrentLink)); err != nil {
+		t.Fatal(err)
+	}
+
+	cleaner := NewCleaner(storeDir, "", "", time.Hour)
+	result, err := cleaner.Clean()
+	if err != nil {
+		t.Fatalf("Clean failed: %v", err)
+	}
+
+	if len(result.Removed) != 2 {
+		t.Errorf("Expected 2 removed versions, got %v", result.Removed)
+	}
+	if result.ReclaimedBytes != 400 {
+		t.Errorf("Expected 400 reclaimed bytes, got %d", result.ReclaimedBytes)
+	}
+	if _, err := os.Stat(filepath.Join(storeDir, "tool", "v1.1.0", "tool")); err != nil {
+		t.Errorf("Active version should be kept: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(storeDir, "tool", "v2.0.0")); !os.IsNotExist(err) {
+		t.Error("Superseded version v2.0.0 should be removed")
+	}
+}
+
+func TestCleanVersionsWithoutCurrentLink(t *testing.T) {
+	tempDir := t.TempDir()
+	storeDir := filepath.Join(tempDir, "store")
+	old := time.Now().Add(-48 * time.Hour)
+
+	writeFile(t, filepath.Join(storeDir, "tool", "v1.0.0", "tool"), 100, old)
+	writeFile(t, filepath.Join(storeDir, "tool", "v2.0.0", "tool"), 100, time.Now())
+	if err := os.Chtimes(filepath.Join(storeDir, "tool", "v1.0.0"), old, old); err != nil {
+		t.Fatal(err)
+	}
+
+	cleaner := NewCleaner(storeDir, "", "", time.Hour)
+	if _, err := cleaner.Clean(); err != nil {
+		t.Fatalf("Clean failed: %v", err)
+	}
+
+	if _, err := os.Stat(filepath.Join(storeDir, "tool", "v2.0.0")); err != nil {
+		t.Errorf("Newest version should be kept: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(storeDir, "tool", "v1.0.0")); !os.IsNotExist(err) {
+		t.Error("Older version should be removed")
+	}
+}
+
+func TestCleanOrphanedSymlinks(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("Skipping symlink test on Windows")
+	}
+
+	tempDir := t.TempDir()
+	storeDir := filepath.Join(tempDir, "store")
+	binDir := filepath.Join(tempDir, "bin")
+	if err := os.MkdirAll(binDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	writeFile(t, filepath.Join(storeDir, "alive", "v1", "alive"), 10, time.Now())
+
+	links := map[string]string{
+		"alive":    filepath.Join(storeDir, "alive", "v1", "alive"), // Valid managed link
+		"orphaned": filepath.Join(storeDir, "gone", "v1", "gone"),   // Dangling managed link
+		"foreign":  filepath.Join(tempDir, "elsewhere", "tool"),     // Dangling but not ours
+	}
+	for name, target := range links {
+		if err := os.Symlink(target, filepath.Join(binDir, name)); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	cleaner := NewCleaner(storeDir, "", "", time.Hour)
+	cleaner.BinDirs = []string{binDir}
+	result, err := cleaner.Clean()
+	if err != nil {
+		t.Fatalf("Clean failed: %v", err)
+	}
+
+	if len(result.Removed) != 1 || result.Removed[0] != filepath.Join(binDir, "orphaned") {
+		t.Errorf("Expected only the orphaned link to be removed, got %v", result.Removed)
+	}
+	for _, name := range []string{"alive", "foreign"} {
+		if _, err := os.Lstat(filepath.Join(binDir, name)); err != nil {
+			t.Errorf("Link %s should be kept: %v", name, err)
+		}
+	}
+}
+
+func TestCleanBackupsAndCache(t *testing.T) {
+	tempDir := t.TempDir()
+	backupDir := filepath.Join(tempDir, "backups")
+	cacheDir := filepath.Join(tempDir, "cache")
+	old := time.Now().Add(-72 * time.Hour)
+
+	writeFile(t, filepath.Join(backupDir, "tool.old"), 50, old)
+	writeFile(t, filepath.Join(backupDir, "tool.recent"), 50, time.Now())
+	writeFile(t, filepath.Join(cacheDir, "downloads", "old.tar.gz"), 70, old)
+	writeFile(t, filepath.Join(cacheDir, "downloads", "new.tar.gz"), 70, time.Now())
+
+	cleaner := NewCleaner("", backupDir, cacheDir, 24*time.Hour)
+	result, err := cleaner.Clean()
+	if err != nil {
+		t.Fatalf("Clean failed: %v", err)
+	}
+
+	if len(result.Removed) != 2 {
+		t.Errorf("Expected 2 removed entries, got %v", result.Removed)
+	}
+	if result.ReclaimedBytes != 120 {
+		t.Errorf("Expected 120 reclaimed bytes, got %d", result.ReclaimedBytes)
+	}
+	if _, err := os.Stat(filepath.Join(cacheDir, "downloads", "new.tar.gz")); err != nil {
+		t.Errorf("Recent cache entry should be kept: %v", err)
+	}
+}
+
+func TestCleanDryRun(t *testing.T) {
+	tempDir := t.TempDir()
+	backupDir := filepath.Join(tempDir, "backups")
+	writeFile(t, filepath.Join(backupDir, "tool.old"), 50, time.Now().Add(-72*time.Hour))
+
+	cleaner := NewCleaner("", backupDir, "", time.Hour)
+	cleaner.DryRun = true
+	result, err := cleaner.Clean()
+	if err != nil {
+		t.Fatalf("Clean failed: %v", err)
+	}
+
+	if len(result.Removed) != 1 || result.ReclaimedBytes != 50 {
+		t.Errorf("Expected dry-run to report 1 entry of 50 bytes, got %v (%d bytes)", result.Removed, result.ReclaimedBytes)
+	}
+	if _, err := os.Stat(filepath.Join(backupDir, "tool.old")); err != nil {
+		t.Errorf("Dry-run should not remove files: %v", err)
+	}
+}
+
+func TestCleanMissingDirectories(t *testing.T) {
+	cleaner := NewCleaner("/non/existent/store", "/non/existent/backups", "/non/existent/cache", time.Hour)
+	cleaner.BinDirs = []string{"/non/existent/bin"}
+
+	result, err := cleaner.Clean()
+	if err != nil {
+		t.Fatalf("Clean should ignore missing directories: %v", err)
+	}
+	if len(result.Removed) != 0 {
+		t.Errorf("Expected nothing removed, got %v", result.Removed)
+	}
+}
+
+func TestFormatSize(t *testing.T) {
+	tests := []struct {
+		bytes int64
+		want  string
+	}{
+		{0, "0 B"},
+		{512, "512 B"},
+		{1024, "1.0 KB"},
+		{1536, "1.5 KB"},
+		{5 * 1024 * 1024, "5.0 MB"},
+		{3 * 1024 * 1024 * 1024, "3.0 GB"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.want, func(t *testing.T) {
+			if got := FormatSize(tt.bytes); got != tt.want {
+				t.Errorf("FormatSize(%d) = %s, want %s", tt.bytes, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPruneCacheBySize(t *testing.T) {
+	cacheDir := t.TempDir()
+	now := time.Now()
+	writeFile(t, filepath.Join(cacheDir, "oldest.zip"), 100, now.Add(-3*time.Hour))
+	writeFile(t, filepath.Join(cacheDir, "downloads", "older.zip"), 100, now.Add(-2*time.Hour))
+	writeFile(t, filepath.Join(cacheDir, "newest.zip"), 100, now)
+
+	cleaner := NewCleaner("", "", cacheDir, 24*time.Hour)
+	cleaner.MaxCacheSize = 150
+	result, err := cleaner.PruneCache()
+	if err != nil {
+		t.Fatalf("PruneCache failed: %v", err)
+	}
+
+	if len(result.Removed) != 2 || result.ReclaimedBytes != 200 {
+		t.Errorf("Expected the 2 oldest entries removed, got %v (%d bytes)", result.Removed, result.ReclaimedBytes)
+	}
+	if _, err := os.Stat(filepath.Join(cacheDir, "newest.zip")); err != nil {
+		t.Errorf("Newest cache entry should be kept: %v", err)
+	}
+}
+
+func TestPruneCacheByAgeAndSize(t *testing.T) {
+	cacheDir := t.TempDir()
+	now := time.Now()
+	writeFile(t, filepath.Join(cacheDir, "expired.zip"), 100, now.Add(-72*time.Hour))
+	writeFile(t, filepath.Join(cacheDir, "older.zip"), 100, now.Add(-time.Hour))
+	writeFile(t, filepath.Join(cacheDir, "newest.zip"), 100, now)
+
+	cleaner := NewCleaner("", "", cacheDir, 24*time.Hour)
+	cleaner.MaxCacheSize = 200
+	cleaner.DryRun = true
+	result, err := cleaner.PruneCache()
+	if err != nil {
+		t.Fatalf("PruneCache failed: %v", err)
+	}
+
+	// The expired entry alone brings the cache within the size limit
+	if len(result.Removed) != 1 || filepath.Base(result.Removed[0]) != "expired.zip" {
+		t.Errorf("Expected only the expired entry removed, got %v", result.Removed)
+	}
+	if _, err := os.Stat(filepath.Join(cacheDir, "expired.zip")); err != nil {
+		t.Errorf("Dry-run should not remove files: %v", err)
+	}
+}
@@ -0,0 +1,74 @@
+package clean
+
+import (
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/pyhub-kr/pyhub-installer/internal/store"
+)
+
+// VersionUsage is the disk usage of one installed version
+type VersionUsage struct {
+	Version string
+	Current bool
+	Size    int64
+}
+
+// ToolUsage is the disk usage of a tool's versions in the store
+type ToolUsage struct {
+	Name     string
+	Versions []VersionUsage // Oldest first
+	Size     int64
+}
+
+// Superseded returns the size of the versions clean would remove
+func (t ToolUsage) Superseded() int64 {
+	var size int64
+	for _, v := range t.Versions {
+		if !v.Current {
+			size += v.Size
+		}
+	}
+	return size
+}
+
+// StoreUsage returns the disk usage of each tool in the store, largest first. The
+// current version is the one clean keeps.
+func StoreUsage(storeDir string) ([]ToolUsage, error) {
+	tools, err := readDirIfExists(storeDir)
+	if err != nil {
+		return nil, err
+	}
+
+	var usage []ToolUsage
+	for _, tool := range tools {
+		if !tool.IsDir() {
+			continue
+		}
+		toolDir := filepath.Join(storeDir, tool.Name())
+		entries, err := os.ReadDir(toolDir)
+		if err != nil {
+			return nil, err
+		}
+		versions, err := store.Versions(toolDir)
+		if err != nil {
+			return nil, err
+		}
+
+		current := currentVersion(toolDir, entries)
+		t := ToolUsage{Name: tool.Name()}
+		for _, version := range versions {
+			size, err := DirSize(filepath.Join(toolDir, version))
+			if err != nil {
+				return nil, err
+			}
+			t.Versions = append(t.Versions, VersionUsage{Version: version, Current: version == current, Size: size})
+			t.Size += size
+		}
+		usage = append(usage, t)
+	}
+
+	sort.SliceStable(usage, func(i, j int) bool { return usage[i].Size > usage[j].Size })
+	return usage, nil
+}
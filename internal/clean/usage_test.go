@@ -0,0 +1,50 @@
+package clean
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/pyhub-kr/pyhub-installer/internal/store"
+)
+
+func TestStoreUsage(t *testing.T) {
+	storeDir := filepath.Join(t.TempDir(), "store")
+	now := time.Now()
+
+	writeFile(t, filepath.Join(storeDir, "small", "1.0.0", "small"), 50, now)
+	writeFile(t, filepath.Join(storeDir, "big", "v1.0.0", "big"), 100, now)
+	writeFile(t, filepath.Join(storeDir, "big", "v1.1.0", "big"), 200, now)
+	writeFile(t, filepath.Join(storeDir, "big", "v1.1.0", "doc", "README"), 10, now)
+	if err := store.SetCurrent(filepath.Join(storeDir, "big"), "v1.1.0"); err != nil {
+		t.Fatal(err)
+	}
+
+	usage, err := StoreUsage(storeDir)
+	if err != nil {
+		t.Fatalf("StoreUsage() error = %v", err)
+	}
+	if len(usage) != 2 || usage[0].Name != "big" || usage[1].Name != "small" {
+		t.Fatalf("Expected tools largest first, got %+v", usage)
+	}
+
+	big := usage[0]
+	if big.Size != 310 || len(big.Versions) != 2 {
+		t.Fatalf("Unexpected usage of big: %+v", big)
+	}
+	if v := big.Versions[1]; v.Version != "v1.1.0" || !v.Current || v.Size != 210 {
+		t.Errorf("Unexpected current version: %+v", v)
+	}
+	if got := big.Superseded(); got != 100 {
+		t.Errorf("Superseded() = %d, want 100", got)
+	}
+
+	// Without a current link the only version is the one clean keeps
+	if got := usage[1].Superseded(); got != 0 {
+		t.Errorf("Superseded() = %d, want 0", got)
+	}
+
+	if usage, err := StoreUsage(filepath.Join(storeDir, "missing")); err != nil || len(usage) != 0 {
+		t.Errorf("StoreUsage() of a missing store = %v, %v", usage, err)
+	}
+}
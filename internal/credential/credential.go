@@ -0,0 +1,156 @@
+package credential
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"fmt"
+	"os"
+	"os/exec"
+	"runtime"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Timeout limits how long a helper may run, e.g. waiting for a secret manager unlock
+const Timeout = 2 * time.Minute
+
+// Credential is what a helper returns for a host
+type Credential struct {
+	Username string
+	Password string // Password or token
+}
+
+// Authorization returns the HTTP Authorization header value: basic authentication
+// with a username, otherwise the password as a bearer token
+func (c *Credential) Authorization() string {
+	if c.Username != "" {
+		return "Basic " + base64.StdEncoding.EncodeToString([]byte(c.Username+":"+c.Password))
+	}
+	return "Bearer " + c.Password
+}
+
+// Default holds the helpers configured for the process, none until Configure is called
+var Default = New(nil)
+
+// Configure sets the helpers of the process from commands keyed by host
+func Configure(commands map[string]string) {
+	Default = New(commands)
+}
+
+// Helpers obtain credentials per host by running configured shell commands, like git
+// credential helpers. Results are cached for the process.
+type Helpers struct {
+	commands map[string]string // Keyed by host, "*" for any host
+	run      func(ctx context.Context, command, host string) ([]byte, error)
+
+	mu    sync.Mutex
+	cache map[string]result
+}
+
+// result is a cached helper outcome; failures are cached too so a broken helper is
+// reported once rather than on every request
+type result struct {
+	cred *Credential
+	err  error
+}
+
+// New creates helpers from commands keyed by host, or "*" for every other host
+func New(commands map[string]string) *Helpers {
+	lowered := make(map[string]string, len(commands))
+	for host, command := range commands {
+		lowered[strings.ToLower(host)] = command
+	}
+	return &Helpers{commands: lowered, run: runHelper, cache: make(map[string]result)}
+}
+
+// Command returns the helper command configured for a host, or ""
+func (h *Helpers) Command(host string) string {
+	if h == nil {
+		return ""
+	}
+	if command, ok := h.commands[strings.ToLower(host)]; ok {
+		return command
+	}
+	return h.commands["*"]
+}
+
+// Get runs the helper of a host and returns its credential, or nil if no helper is
+// configured for the host
+func (h *Helpers) Get(ctx context.Context, host string) (*Credential, error) {
+	host = strings.ToLower(host)
+	command := h.Command(host)
+	if command == "" {
+		return nil, nil
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	r, ok := h.cache[host]
+	if !ok {
+		r.cred, r.err = h.fill(ctx, command, host)
+		h.cache[host] = r
+	}
+	return r.cred, r.err
+}
+
+// fill runs a helper command and parses its credential
+func (h *Helpers) fill(ctx context.Context, command, host string) (*Credential, error) {
+	ctx, cancel := context.WithTimeout(ctx, Timeout)
+	defer cancel()
+	output, err := h.run(ctx, command, host)
+	if err != nil {
+		return nil, fmt.Errorf("credential helper for %s failed: %w", host, err)
+	}
+	cred := Parse(output)
+	if cred.Password == "" {
+		return nil, fmt.Errorf("credential helper for %s returned no password or token", host)
+	}
+	return cred, nil
+}
+
+// Parse reads a helper's output: git credential "key=value" lines (username, password,
+// or token), or otherwise the whole output as the token
+func Parse(output []byte) *Credential {
+	cred := &Credential{}
+	keyed := false
+	for _, line := range strings.Split(string(output), "\n") {
+		key, value, ok := strings.Cut(strings.TrimRight(line, "\r"), "=")
+		if !ok {
+			continue
+		}
+		switch key {
+		case "username":
+			cred.Username, keyed = value, true
+		case "password", "token":
+			cred.Password, keyed = value, true
+		}
+	}
+	if !keyed {
+		cred.Password = strings.TrimSpace(string(output))
+	}
+	return cred
+}
+
+// runHelper runs a helper command with the platform shell. The request is written to
+// its standard input in the git credential format, and the host is also set in
+// PYHUB_CREDENTIAL_HOST for commands that ignore their input.
+func runHelper(ctx context.Context, command, host string) ([]byte, error) {
+	var cmd *exec.Cmd
+	if runtime.GOOS == "windows" {
+		cmd = exec.CommandContext(ctx, "cmd", "/C", command)
+	} else {
+		cmd = exec.CommandContext(ctx, "sh", "-c", command)
+	}
+	cmd.Stdin = strings.NewReader(fmt.Sprintf("protocol=https\nhost=%s\n\n", host))
+	cmd.Env = append(os.Environ(), "PYHUB_CREDENTIAL_HOST="+host)
+	cmd.Stderr = os.Stderr // Helpers may ask to unlock a vault
+
+	var stdout bytes.Buffer
+	cmd.Stdout = &stdout
+	if err := cmd.Run(); err != nil {
+		return nil, err
+	}
+	return stdout.Bytes(), nil
+}
@@ -0,0 +1,99 @@
+package credential
+
+import (
+	"context"
+	"errors"
+	"runtime"
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		output   string
+		username string
+		password string
+	}{
+		{"ghp_abc123\n", "", "ghp_abc123"},
+		{"  ghp_abc123  \r\n", "", "ghp_abc123"},
+		{"protocol=https\nhost=github.com\nusername=octocat\npassword=ghp_abc123\n", "octocat", "ghp_abc123"},
+		{"token=ghp_abc123\r\n", "", "ghp_abc123"},
+		{"", "", ""},
+	}
+	for _, tt := range tests {
+		cred := Parse([]byte(tt.output))
+		if cred.Username != tt.username || cred.Password != tt.password {
+			t.Errorf("Parse(%q) = %+v, want %q/%q", tt.output, cred, tt.username, tt.password)
+		}
+	}
+}
+
+func TestGet(t *testing.T) {
+	helpers := New(map[string]string{
+		"GitHub.com": "github-helper",
+		"*":          "default-helper",
+	})
+	calls := map[string]int{}
+	helpers.run = func(ctx context.Context, command, host string) ([]byte, error) {
+		calls[command+" "+host]++
+		switch command {
+		case "github-helper":
+			return []byte("ghp_github\n"), nil
+		case "default-helper":
+			if host == "broken.example.com" {
+				return nil, errors.New("exit status 1")
+			}
+			return []byte("password=ghe_token\n"), nil
+		}
+		return nil, errors.New("unexpected command")
+	}
+
+	for i := 0; i < 2; i++ {
+		cred, err := helpers.Get(context.Background(), "github.com")
+		if err != nil || cred == nil || cred.Password != "ghp_github" {
+			t.Fatalf("Get(github.com) = %+v, %v", cred, err)
+		}
+	}
+	if calls["github-helper github.com"] != 1 {
+		t.Errorf("Expected the helper to run once, ran %d times", calls["github-helper github.com"])
+	}
+
+	cred, err := helpers.Get(context.Background(), "GHE.example.com")
+	if err != nil || cred.Password != "ghe_token" {
+		t.Errorf("Get() with the * helper = %+v, %v", cred, err)
+	}
+	for i := 0; i < 2; i++ {
+		if _, err := helpers.Get(context.Background(), "broken.example.com"); err == nil {
+			t.Error("Expected error from a failing helper")
+		}
+	}
+	if calls["default-helper broken.example.com"] != 1 {
+		t.Errorf("Expected the failure to be cached, helper ran %d times", calls["default-helper broken.example.com"])
+	}
+
+	none := New(map[string]string{"github.com": "github-helper"})
+	if cred, err := none.Get(context.Background(), "gitlab.com"); cred != nil || err != nil {
+		t.Errorf("Get() without a helper = %+v, %v, want nil", cred, err)
+	}
+}
+
+func TestRunHelper(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("sh helper")
+	}
+	output, err := runHelper(context.Background(), `read -r protocol; read -r host; echo "$host $PYHUB_CREDENTIAL_HOST"`, "github.com")
+	if err != nil {
+		t.Fatalf("runHelper() error = %v", err)
+	}
+	if string(output) != "host=github.com github.com\n" {
+		t.Errorf("runHelper() = %q", output)
+	}
+}
+
+func TestAuthorization(t *testing.T) {
+	if got := (&Credential{Password: "ghp_abc"}).Authorization(); got != "Bearer ghp_abc" {
+		t.Errorf("Authorization() = %s", got)
+	}
+	if got := (&Credential{Username: "user", Password: "secret"}).Authorization(); got != "Basic dXNlcjpzZWNyZXQ=" {
+		t.Errorf("Authorization() = %s", got)
+	}
+}
@@ -0,0 +1,205 @@
+package doctor
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/pyhub-kr/pyhub-installer/internal/github"
+	"github.com/pyhub-kr/pyhub-installer/internal/install"
+	"github.com/pyhub-kr/pyhub-installer/internal/sysproxy"
+)
+
+// Status is the outcome of a check
+type Status int
+
+const (
+	OK Status = iota
+	Warning
+	Failed
+)
+
+// Symbol returns the marker printed before a result
+func (s Status) Symbol() string {
+	switch s {
+	case OK:
+		return "✓"
+	case Warning:
+		return "!"
+	default:
+		return "✗"
+	}
+}
+
+// Result is the outcome of one check and, if it did not pass, how to fix it
+type Result struct {
+	Name   string
+	Status Status
+	Detail string
+	Fix    string
+}
+
+// Endpoints are the hosts the installer downloads from
+var Endpoints = []string{"https://github.com", "https://api.github.com"}
+
+// CheckInstallDir reports the directory tools are installed to and whether it is in PATH
+func CheckInstallDir(candidates []install.PathCandidate, goos string) []Result {
+	for _, c := range candidates {
+		if !c.Chosen {
+			continue
+		}
+		results := []Result{{Name: "Install directory", Status: OK, Detail: c.Path + " is writable"}}
+		if c.InPath {
+			results = append(results, Result{Name: "PATH", Status: OK, Detail: c.Path + " is in PATH"})
+		} else {
+			results = append(results, Result{
+				Name:   "PATH",
+				Status: Warning,
+				Detail: c.Path + " is not in PATH; installed tools will not be found by name",
+				Fix:    pathFix(goos, c.Path),
+			})
+		}
+		return results
+	}
+
+	return []Result{{
+		Name:   "Install directory",
+		Status: Failed,
+		Detail: "no writable directory found",
+		Fix:    "Pass --output to install somewhere you can write, or create ~/.local/bin and add it to PATH",
+	}}
+}
+
+// pathFix returns the command that adds dir to PATH on an OS
+func pathFix(goos, dir string) string {
+	if goos == "windows" {
+		return fmt.Sprintf(`Run: setx PATH "%%PATH%%;%s" and open a new terminal`, dir)
+	}
+	return fmt.Sprintf(`Add to your shell profile (e.g. ~/.profile): export PATH="%s:$PATH"`, dir)
+}
+
+// CheckReachable reports whether an endpoint answers HTTP requests
+func CheckReachable(client *http.Client, endpoint string) Result {
+	name := "Network " + strings.TrimPrefix(endpoint, "https://")
+	resp, err := client.Head(endpoint)
+	if err != nil {
+		return Result{
+			Name:   name,
+			Status: Failed,
+			Detail: err.Error(),
+			Fix:    "Check your network, firewall and proxy settings; if GitHub is blocked, configure mirrors in config.json",
+		}
+	}
+	resp.Body.Close()
+	return Result{Name: name, Status: OK, Detail: fmt.Sprintf("reachable (HTTP %d)", resp.StatusCode)}
+}
+
+// CheckProxy reports the proxy settings taken from the environment, or else the
+// detected system proxy (nil if none)
+func CheckProxy(getenv func(string) string, system *sysproxy.Settings) Result {
+	lookup := func(names ...string) string {
+		for _, name := range names {
+			if value := getenv(name); value != "" {
+				return value
+			}
+		}
+		return ""
+	}
+
+	proxy := lookup("HTTPS_PROXY", "https_proxy", "HTTP_PROXY", "http_proxy")
+	if proxy == "" && system != nil {
+		detail := fmt.Sprintf("using system proxy from %s (HTTP: %s, HTTPS: %s)", system.Source, orNone(system.HTTP), orNone(system.HTTPS))
+		if len(system.Bypass) > 0 {
+			detail += " (bypass: " + strings.Join(system.Bypass, ", ") + ")"
+		}
+		return Result{Name: "Proxy", Status: OK, Detail: detail}
+	}
+	if proxy == "" {
+		return Result{Name: "Proxy", Status: OK, Detail: "no proxy configured"}
+	}
+
+	u, err := url.Parse(proxy)
+	if err != nil || u.Host == "" {
+		if err == nil {
+			err = errors.New("missing host")
+		}
+		return Result{
+			Name:   "Proxy",
+			Status: Failed,
+			Detail: fmt.Sprintf("invalid proxy URL %q: %v", proxy, err),
+			Fix:    "Set HTTPS_PROXY to a URL such as http://proxy.example.com:8080",
+		}
+	}
+
+	detail := "using " + u.Redacted()
+	if noProxy := lookup("NO_PROXY", "no_proxy"); noProxy != "" {
+		detail += " (NO_PROXY: " + noProxy + ")"
+	}
+	return Result{Name: "Proxy", Status: OK, Detail: detail}
+}
+
+// orNone returns value, or "none" if it is empty
+func orNone(value string) string {
+	if value == "" {
+		return "none"
+	}
+	return value
+}
+
+// CheckToken reports whether a GitHub token is set and accepted by the API
+func CheckToken(client *github.Client) Result {
+	if client.Token == "" {
+		return Result{
+			Name:   "GitHub token",
+			Status: Warning,
+			Detail: "no token is set (GITHUB_TOKEN, GH_TOKEN or --token); anonymous API requests are limited to 60 per hour",
+			Fix:    "Create a personal access token at https://github.com/settings/tokens and set GITHUB_TOKEN, or configure a credential helper for github.com",
+		}
+	}
+
+	limit, err := client.GetRateLimit()
+	if errors.Is(err, github.ErrUnauthorized) {
+		return Result{
+			Name:   "GitHub token",
+			Status: Failed,
+			Detail: "the token was rejected by GitHub",
+			Fix:    "The token is invalid or expired; create a new one and update GITHUB_TOKEN, GH_TOKEN or --token",
+		}
+	}
+	if err != nil {
+		return Result{Name: "GitHub token", Status: Warning, Detail: "could not verify the token: " + err.Error()}
+	}
+	return Result{
+		Name:   "GitHub token",
+		Status: OK,
+		Detail: fmt.Sprintf("valid, %d of %d API requests left", limit.Remaining, limit.Limit),
+	}
+}
+
+// CheckSymlinks reports whether the user can create symbolic links in dir, which
+// Windows only allows in Developer Mode or with administrator rights
+func CheckSymlinks(dir string) Result {
+	tmpDir, err := os.MkdirTemp(dir, "pyhub-doctor-*")
+	if err != nil {
+		return Result{Name: "Symbolic links", Status: Warning, Detail: "could not test: " + err.Error()}
+	}
+	defer os.RemoveAll(tmpDir)
+
+	target := filepath.Join(tmpDir, "target")
+	if err := os.WriteFile(target, nil, 0644); err != nil {
+		return Result{Name: "Symbolic links", Status: Warning, Detail: "could not test: " + err.Error()}
+	}
+	if err := os.Symlink(target, filepath.Join(tmpDir, "link")); err != nil {
+		return Result{
+			Name:   "Symbolic links",
+			Status: Warning,
+			Detail: "cannot create symbolic links; installs that link executables will fail",
+			Fix:    "Enable Developer Mode (Settings > System > For developers) or run as administrator",
+		}
+	}
+	return Result{Name: "Symbolic links", Status: OK, Detail: "supported"}
+}
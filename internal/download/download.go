@@ -2,23 +2,33 @@ package download
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
+	"net/url"
 	"os"
 	"path/filepath"
+	"strings"
 	"sync"
 	"time"
 
-	"github.com/schollz/progressbar/v3"
+	"github.com/pyhub-kr/pyhub-installer/internal/i18n"
+	"github.com/pyhub-kr/pyhub-installer/internal/retry"
+	"github.com/pyhub-kr/pyhub-installer/internal/tempdir"
+	"github.com/pyhub-kr/pyhub-installer/internal/ui"
+	"github.com/pyhub-kr/pyhub-installer/internal/useragent"
 )
 
 // ChunkDownloader handles parallel chunk downloads
 type ChunkDownloader struct {
-	URL         string
-	Filename    string
-	ChunkSize   int64
-	Parallelism int
+	URL           string
+	Filename      string
+	ChunkSize     int64
+	Parallelism   int
+	Headers       map[string]string // Extra request headers, e.g. authorization
+	Mirrors       []string          // Other URLs of the same file, tried in order when URL fails
+	MirrorHeaders map[string]string // Extra request headers also sent to mirrors on the host of URL
 }
 
 // Chunk represents a download chunk
@@ -38,10 +48,102 @@ func NewChunkDownloader(url, filename string) *ChunkDownloader {
 	}
 }
 
-// Download downloads a file with parallel chunks
+// Options controls how downloads use memory and connections
+type Options struct {
+	LowMemory bool // One request at a time, no parallel chunks, small copy buffers
+}
+
+// lowMemoryBufferSize is the copy buffer of downloads in low-memory mode
+const lowMemoryBufferSize = 8 * 1024
+
+var (
+	options Options
+
+	// lowMemoryLimiter serializes the requests of all downloads in low-memory mode
+	lowMemoryLimiter = NewLimiter(1)
+)
+
+// Configure sets the download mode. In low-memory mode downloads are streamed to
+// disk in a single request each, one download at a time, so memory use stays bounded
+// on small machines.
+func Configure(opts Options) {
+	options = opts
+}
+
+// LowMemory reports whether downloads run in low-memory mode
+func LowMemory() bool {
+	return options.LowMemory
+}
+
+// Download downloads a file with parallel chunks. An interrupted download is kept and
+// resumed by the next download of the same URL. When the URL fails, the mirrors are
+// tried in order. Mirrors on the host of the URL get MirrorHeaders instead of Headers,
+// which may hold credentials meant for the URL only; other mirrors get neither.
 func (cd *ChunkDownloader) Download(ctx context.Context) error {
+	err := cd.download(ctx)
+	failed := cd.URL
+	for _, mirrorURL := range cd.Mirrors {
+		if err == nil || ctx.Err() != nil {
+			break
+		}
+		fmt.Print(i18n.T("Warning: %s failed: %v; trying mirror %s\n", hostOf(failed), err, hostOf(mirrorURL)))
+		failed = mirrorURL
+		mirror := *cd
+		mirror.URL, mirror.Headers, mirror.Mirrors = mirrorURL, nil, nil
+		if sameHost(mirrorURL, cd.URL) {
+			mirror.Headers = cd.MirrorHeaders
+		}
+		err = mirror.download(ctx)
+	}
+	return err
+}
+
+// sameHost reports whether two URLs point at the same host and port
+func sameHost(a, b string) bool {
+	ua, err := url.Parse(a)
+	if err != nil {
+		return false
+	}
+	ub, err := url.Parse(b)
+	if err != nil {
+		return false
+	}
+	return ua.Host != "" && strings.EqualFold(ua.Host, ub.Host)
+}
+
+// hostOf returns the host of a URL for messages
+func hostOf(rawURL string) string {
+	if u, err := url.Parse(rawURL); err == nil && u.Host != "" {
+		return u.Host
+	}
+	return rawURL
+}
+
+// download downloads the file from URL
+func (cd *ChunkDownloader) download(ctx context.Context) error {
+	if options.LowMemory {
+		return cd.downloadSingle(ctx)
+	}
+	if offset, _ := cd.resumeOffset(); offset > 0 {
+		return cd.downloadSingle(ctx)
+	}
+
 	// Get file size
-	resp, err := http.Head(cd.URL)
+	if _, err := url.Parse(cd.URL); err != nil {
+		return fmt.Errorf("failed to get file info: %w", err)
+	}
+	release, err := acquire(ctx)
+	if err != nil {
+		return err
+	}
+	resp, err := retry.Do(ctx, func() (*http.Response, error) {
+		req, err := cd.newRequest(ctx, "HEAD")
+		if err != nil {
+			return nil, err
+		}
+		return http.DefaultClient.Do(req)
+	})
+	release()
 	if err != nil {
 		return fmt.Errorf("failed to get file info: %w", err)
 	}
@@ -62,10 +164,7 @@ func (cd *ChunkDownloader) Download(ctx context.Context) error {
 	chunks := cd.createChunks(contentLength)
 	
 	// Create progress bar
-	bar := progressbar.DefaultBytes(
-		contentLength,
-		fmt.Sprintf("Downloading %s", filepath.Base(cd.Filename)),
-	)
+	bar := cd.progressBar(ctx, contentLength)
 
 	// Create temporary files for each chunk
 	tempFiles := make([]*os.File, len(chunks))
@@ -78,16 +177,20 @@ func (cd *ChunkDownloader) Download(ctx context.Context) error {
 		}
 	}()
 
-	// Download chunks in parallel
+	// Download chunks in parallel, at most Parallelism at a time
 	var wg sync.WaitGroup
+	done := make([]bool, len(chunks))
 	errChan := make(chan error, len(chunks))
+	parallel := make(chan struct{}, max(cd.Parallelism, 1))
 
 	for i, chunk := range chunks {
 		wg.Add(1)
 		go func(idx int, c Chunk) {
 			defer wg.Done()
-			
-			tempFile, err := os.CreateTemp("", fmt.Sprintf("chunk_%d_*", idx))
+			parallel <- struct{}{}
+			defer func() { <-parallel }()
+
+			tempFile, err := tempdir.CreateTemp(fmt.Sprintf("chunk_%d_*", idx))
 			if err != nil {
 				errChan <- err
 				return
@@ -96,15 +199,21 @@ func (cd *ChunkDownloader) Download(ctx context.Context) error {
 
 			if err := cd.downloadChunk(ctx, c, tempFile, bar); err != nil {
 				errChan <- err
+				return
 			}
+			done[idx] = true
 		}(i, chunk)
 	}
 
 	wg.Wait()
 	close(errChan)
 
-	// Check for errors
+	// Check for errors, keeping what was downloaded when interrupted
 	if err := <-errChan; err != nil {
+		if ctx.Err() != nil {
+			cd.savePrefix(tempFiles, done, validator(resp))
+			return ctx.Err()
+		}
 		return fmt.Errorf("chunk download failed: %w", err)
 	}
 
@@ -132,80 +241,273 @@ func (cd *ChunkDownloader) createChunks(contentLength int64) []Chunk {
 	return chunks
 }
 
-// downloadChunk downloads a single chunk
-func (cd *ChunkDownloader) downloadChunk(ctx context.Context, chunk Chunk, file *os.File, bar *progressbar.ProgressBar) error {
-	req, err := http.NewRequestWithContext(ctx, "GET", cd.URL, nil)
-	if err != nil {
-		return err
+// ErrNotFound matches the errors of files the server does not have
+var ErrNotFound = errors.New("not found")
+
+// errInterrupted marks a response that broke off before its end, e.g. because the
+// connection was reset or timed out
+var errInterrupted = errors.New("download interrupted")
+
+// errTemporary marks a range request that is worth repeating: a network error or a
+// status the retry policy retries
+var errTemporary = errors.New("temporary failure")
+
+// downloadChunk downloads a single chunk. Failed requests are repeated with the retry
+// policy's backoff, and when the connection breaks off mid-chunk, the rest of the chunk
+// is requested. This is the only retry layer of a chunk, so it takes at most the
+// policy's attempts.
+func (cd *ChunkDownloader) downloadChunk(ctx context.Context, chunk Chunk, file *os.File, bar io.Writer) error {
+	policy := retry.Current()
+	start := chunk.Start
+	for attempt := 1; ; attempt++ {
+		n, err := cd.downloadRange(ctx, policy, start, chunk.End, file, bar)
+		if err == nil || ctx.Err() != nil || attempt >= policy.Attempts {
+			return err
+		}
+		if !errors.Is(err, errInterrupted) && !errors.Is(err, errTemporary) {
+			return err
+		}
+		start += n
+		if err := policy.Wait(ctx, attempt, err.Error()); err != nil {
+			return err
+		}
 	}
+}
 
-	// Set range header
-	req.Header.Set("Range", fmt.Sprintf("bytes=%d-%d", chunk.Start, chunk.End))
-
+// downloadRange appends the bytes from start to end (inclusive) to file with a single
+// request, returning how many were written
+func (cd *ChunkDownloader) downloadRange(ctx context.Context, policy retry.Policy, start, end int64, file *os.File, bar io.Writer) (int64, error) {
 	client := &http.Client{
 		Timeout: 30 * time.Second,
 	}
 
+	release, err := acquire(ctx)
+	if err != nil {
+		return 0, err
+	}
+	defer release()
+
+	req, err := cd.newRequest(ctx, "GET")
+	if err != nil {
+		return 0, err
+	}
+	req.Header.Set("Range", fmt.Sprintf("bytes=%d-%d", start, end))
 	resp, err := client.Do(req)
 	if err != nil {
-		return err
+		return 0, fmt.Errorf("%w: %w", errTemporary, err)
 	}
 	defer resp.Body.Close()
 
+	if policy.Retryable(resp.StatusCode) {
+		return 0, fmt.Errorf("%w: HTTP %d", errTemporary, resp.StatusCode)
+	}
 	if resp.StatusCode != http.StatusPartialContent {
-		return fmt.Errorf("server doesn't support range requests: %d", resp.StatusCode)
+		return 0, fmt.Errorf("server doesn't support range requests: %d", resp.StatusCode)
 	}
 
 	// Copy with progress
-	_, err = io.Copy(io.MultiWriter(file, bar), resp.Body)
-	return err
+	n, err := copyData(io.MultiWriter(file, bar), resp.Body)
+	if err != nil {
+		return n, fmt.Errorf("%w: %w", errInterrupted, err)
+	}
+	return n, nil
 }
 
-// downloadSingle downloads file in a single request (fallback)
+// downloadSingle downloads file in a single request (fallback), resuming a partial
+// download of the same URL
 func (cd *ChunkDownloader) downloadSingle(ctx context.Context) error {
-	req, err := http.NewRequestWithContext(ctx, "GET", cd.URL, nil)
-	if err != nil {
+	if _, err := url.Parse(cd.URL); err != nil {
 		return err
 	}
+	offset, ifRange := cd.resumeOffset()
 
 	client := &http.Client{
 		Timeout: 10 * time.Minute,
 	}
 
-	resp, err := client.Do(req)
+	release, err := acquire(ctx)
+	if err != nil {
+		return err
+	}
+	defer release()
+
+	resp, err := retry.Do(ctx, func() (*http.Response, error) {
+		req, err := cd.newRequest(ctx, "GET")
+		if err != nil {
+			return nil, err
+		}
+		if offset > 0 {
+			req.Header.Set("Range", fmt.Sprintf("bytes=%d-", offset))
+			req.Header.Set("If-Range", ifRange)
+		}
+		return client.Do(req)
+	})
 	if err != nil {
 		return err
 	}
 	defer resp.Body.Close()
 
-	if resp.StatusCode != http.StatusOK {
+	flags := os.O_WRONLY | os.O_CREATE | os.O_TRUNC
+	switch {
+	case resp.StatusCode == http.StatusPartialContent && offset > 0:
+		flags = os.O_WRONLY | os.O_APPEND
+		fmt.Print(i18n.T("Resuming download of %s at %d bytes\n", filepath.Base(cd.Filename), offset))
+	case resp.StatusCode == http.StatusOK:
+		// Not resumable, or the file changed since
+	case resp.StatusCode == http.StatusNotFound || resp.StatusCode == http.StatusGone:
+		cd.removePartial()
+		return fmt.Errorf("download failed: %d (%w)", resp.StatusCode, ErrNotFound)
+	default:
+		cd.removePartial()
 		return fmt.Errorf("download failed: %d", resp.StatusCode)
 	}
 
-	// Create output file
-	out, err := os.Create(cd.Filename)
+	// Download into the partial file, moved into place when complete
+	out, err := os.OpenFile(cd.partial(), flags, 0644)
 	if err != nil {
 		return err
 	}
 	defer out.Close()
+	resumable := flags&os.O_APPEND != 0
+	if flags&os.O_TRUNC != 0 {
+		os.Remove(cd.partial() + ".info")
+		cd.savePartial(validator(resp))
+		resumable = validator(resp) != ""
+	}
 
 	// Create progress bar
-	var bar *progressbar.ProgressBar
-	if resp.ContentLength > 0 {
-		bar = progressbar.DefaultBytes(
-			resp.ContentLength,
-			fmt.Sprintf("Downloading %s", filepath.Base(cd.Filename)),
-		)
-	} else {
-		bar = progressbar.DefaultBytes(
-			-1,
-			fmt.Sprintf("Downloading %s", filepath.Base(cd.Filename)),
-		)
+	size := resp.ContentLength
+	if size <= 0 {
+		size = -1
 	}
+	bar := cd.progressBar(ctx, size)
 
 	// Copy with progress
-	_, err = io.Copy(io.MultiWriter(out, bar), resp.Body)
-	return err
+	if _, err := copyData(io.MultiWriter(out, bar), resp.Body); err != nil {
+		if !resumable {
+			out.Close()
+			cd.removePartial()
+		}
+		return err
+	}
+	if err := out.Close(); err != nil {
+		return err
+	}
+	return cd.finishPartial()
+}
+
+// copyData copies a response body, through a small buffer in low-memory mode
+func copyData(dst io.Writer, src io.Reader) (int64, error) {
+	if options.LowMemory {
+		return io.CopyBuffer(dst, src, make([]byte, lowMemoryBufferSize))
+	}
+	return io.Copy(dst, src)
+}
+
+// progressKey is the context key of a shared progress bar
+type progressKey struct{}
+
+// WithProgress makes downloads started with the returned context report to a shared
+// progress writer instead of creating their own, e.g. when several run concurrently
+func WithProgress(ctx context.Context, bar io.Writer) context.Context {
+	return context.WithValue(ctx, progressKey{}, bar)
+}
+
+// ProgressGroup shows the progress of several downloads in one display
+type ProgressGroup interface {
+	// Add returns the progress writer of one download; size is -1 if unknown
+	Add(description string, size int64) io.Writer
+}
+
+// progressGroupKey is the context key of a progress group
+type progressGroupKey struct{}
+
+// WithProgressGroup makes downloads started with the returned context report to their
+// own entry of a shared display, e.g. one line per file when several run concurrently
+func WithProgressGroup(ctx context.Context, group ProgressGroup) context.Context {
+	return context.WithValue(ctx, progressGroupKey{}, group)
+}
+
+// progressBar returns the shared progress writer from ctx or a new one for this file
+func (cd *ChunkDownloader) progressBar(ctx context.Context, size int64) io.Writer {
+	if bar, ok := ctx.Value(progressKey{}).(io.Writer); ok {
+		return bar
+	}
+	if group, ok := ctx.Value(progressGroupKey{}).(ProgressGroup); ok {
+		return group.Add(filepath.Base(cd.Filename), size)
+	}
+	return ui.NewProgress(size, fmt.Sprintf("Downloading %s", filepath.Base(cd.Filename)))
+}
+
+// Limiter bounds the number of concurrent HTTP requests of all downloads sharing it,
+// so that concurrent installs do not open a connection per chunk at once
+type Limiter struct {
+	slots chan struct{}
+}
+
+// NewLimiter creates a limiter allowing n concurrent requests
+func NewLimiter(n int) *Limiter {
+	return &Limiter{slots: make(chan struct{}, max(n, 1))}
+}
+
+// limiterKey is the context key of a shared request limiter
+type limiterKey struct{}
+
+// WithLimiter makes downloads started with the returned context share a request limit
+func WithLimiter(ctx context.Context, limiter *Limiter) context.Context {
+	return context.WithValue(ctx, limiterKey{}, limiter)
+}
+
+// acquire waits for a request slot of the limiter in ctx, if any, and returns the
+// function releasing it. In low-memory mode all requests share a single slot.
+func acquire(ctx context.Context) (func(), error) {
+	limiter, ok := ctx.Value(limiterKey{}).(*Limiter)
+	if options.LowMemory {
+		limiter, ok = lowMemoryLimiter, true
+	}
+	if !ok {
+		return func() {}, nil
+	}
+	select {
+	case limiter.slots <- struct{}{}:
+		return func() { <-limiter.slots }, nil
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+// ParseHeaders parses header options of the form "Name: value". Names are made
+// canonical, and a repeated name keeps its last value. Values may contain colons.
+func ParseHeaders(values []string) (map[string]string, error) {
+	headers := make(map[string]string, len(values))
+	for _, value := range values {
+		name, v, ok := strings.Cut(value, ":")
+		name = strings.TrimSpace(name)
+		if !ok || name == "" || strings.ContainsAny(name, " \t") {
+			return nil, fmt.Errorf("invalid header %q, expected 'Name: value'", value)
+		}
+		headers[http.CanonicalHeaderKey(name)] = strings.TrimSpace(v)
+	}
+	return headers, nil
+}
+
+// newRequest creates a request for the URL with the configured extra headers. Retried
+// requests are created anew for each attempt.
+func (cd *ChunkDownloader) newRequest(ctx context.Context, method string) (*http.Request, error) {
+	req, err := http.NewRequestWithContext(ctx, method, cd.URL, nil)
+	if err != nil {
+		return nil, err
+	}
+	cd.setHeaders(req)
+	return req, nil
+}
+
+// setHeaders applies the configured extra headers to a request
+func (cd *ChunkDownloader) setHeaders(req *http.Request) {
+	useragent.Apply(req)
+	for key, value := range cd.Headers {
+		req.Header.Set(key, value)
+	}
 }
 
 // mergeChunks merges temporary chunk files into final file
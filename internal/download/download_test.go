@@ -1,14 +1,22 @@
 package download
 
 import (
+	"bytes"
 	"context"
 	"fmt"
+	"io"
+	"maps"
 	"net/http"
 	"net/http/httptest"
 	"os"
 	"path/filepath"
+	"strings"
+	"sync"
 	"testing"
 	"time"
+
+	"github.com/pyhub-kr/pyhub-installer/internal/retry"
+	"github.com/schollz/progressbar/v3"
 )
 
 func TestNewChunkDownloader(t *testing.T) {
@@ -174,6 +182,162 @@ func TestDownloadWithChunks(t *testing.T) {
 	}
 }
 
+func TestDownloadChunkResumesAfterInterruption(t *testing.T) {
+	retry.Configure(retry.Policy{Attempts: 3, BaseDelay: time.Millisecond, MaxDelay: time.Millisecond})
+	defer retry.Configure(retry.DefaultPolicy())
+
+	content := make([]byte, 1024)
+	for i := range content {
+		content[i] = byte(i % 251)
+	}
+	var mu sync.Mutex
+	var ranges []string
+	interrupted := false
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		rangeHeader := r.Header.Get("Range")
+		if rangeHeader == "" {
+			w.Header().Set("Accept-Ranges", "bytes")
+			w.Header().Set("Content-Length", fmt.Sprintf("%d", len(content)))
+			return
+		}
+		var start, end int64
+		fmt.Sscanf(rangeHeader, "bytes=%d-%d", &start, &end)
+		mu.Lock()
+		ranges = append(ranges, rangeHeader)
+		cut := start == 256 && !interrupted
+		interrupted = interrupted || cut
+		mu.Unlock()
+
+		w.Header().Set("Content-Range", fmt.Sprintf("bytes %d-%d/%d", start, end, len(content)))
+		w.Header().Set("Content-Length", fmt.Sprintf("%d", end-start+1))
+		w.WriteHeader(http.StatusPartialContent)
+		if cut {
+			// Break the connection after half of the chunk
+			w.Write(content[start : start+128])
+			w.(http.Flusher).Flush()
+			panic(http.ErrAbortHandler)
+		}
+		w.Write(content[start : end+1])
+	}))
+	defer server.Close()
+
+	outputFile := filepath.Join(t.TempDir(), "output.bin")
+	cd := NewChunkDownloader(server.URL, outputFile)
+	cd.ChunkSize = 256
+	if err := cd.Download(context.Background()); err != nil {
+		t.Fatalf("Download failed: %v", err)
+	}
+	if downloaded, _ := os.ReadFile(outputFile); !bytes.Equal(downloaded, content) {
+		t.Errorf("Expected the interrupted chunk to be completed, got %d bytes", len(downloaded))
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	resumed := false
+	for _, r := range ranges {
+		resumed = resumed || (r != "bytes=256-511" && strings.HasSuffix(r, "-511"))
+	}
+	if !resumed {
+		t.Errorf("Expected the rest of the chunk to be requested, got %v", ranges)
+	}
+}
+
+func TestDownloadChunkRetryLimit(t *testing.T) {
+	retry.Configure(retry.Policy{Attempts: 3, BaseDelay: time.Millisecond, MaxDelay: time.Millisecond, Statuses: []int{http.StatusServiceUnavailable}})
+	defer retry.Configure(retry.DefaultPolicy())
+
+	var mu sync.Mutex
+	rangeRequests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Range") == "" {
+			w.Header().Set("Accept-Ranges", "bytes")
+			w.Header().Set("Content-Length", "512")
+			return
+		}
+		mu.Lock()
+		rangeRequests++
+		mu.Unlock()
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	cd := NewChunkDownloader(server.URL, filepath.Join(t.TempDir(), "output.bin"))
+	cd.ChunkSize = 512
+	if err := cd.Download(context.Background()); err == nil {
+		t.Fatal("Expected the download to fail")
+	}
+	mu.Lock()
+	defer mu.Unlock()
+	if rangeRequests != 3 {
+		t.Errorf("Expected the chunk to be tried 3 times, got %d", rangeRequests)
+	}
+}
+
+func TestDownloadFromMirror(t *testing.T) {
+	retry.Configure(retry.Policy{Attempts: 1})
+	defer retry.Configure(retry.DefaultPolicy())
+
+	var authorized []string // Requests that got the headers
+	mirrorHeaders := map[string]string{} // Requests with the mirror headers
+	var mu sync.Mutex
+	handler := func(status int) http.HandlerFunc {
+		return func(w http.ResponseWriter, r *http.Request) {
+			mu.Lock()
+			if r.Header.Get("Authorization") != "" {
+				authorized = append(authorized, r.Host+r.URL.Path)
+			}
+			if r.Header.Get("X-Api-Key") == "key" {
+				mirrorHeaders[r.Host+r.URL.Path] = r.Header.Get("X-Api-Key")
+			}
+			mu.Unlock()
+			if status != http.StatusOK {
+				w.WriteHeader(status)
+				return
+			}
+			w.Write([]byte("mirrored content"))
+		}
+	}
+	// The primary server also hosts a mirror at /mirror/file
+	primary := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/mirror/file" {
+			handler(http.StatusOK)(w, r)
+			return
+		}
+		handler(http.StatusServiceUnavailable)(w, r)
+	}))
+	defer primary.Close()
+	broken := httptest.NewServer(handler(http.StatusNotFound))
+	defer broken.Close()
+	host := func(server *httptest.Server) string { return strings.TrimPrefix(server.URL, "http://") }
+
+	outputFile := filepath.Join(t.TempDir(), "output.txt")
+	cd := NewChunkDownloader(primary.URL+"/file", outputFile)
+	cd.Headers = map[string]string{"Authorization": "token secret"}
+	cd.Mirrors = []string{broken.URL + "/file", primary.URL + "/mirror/file"}
+	cd.MirrorHeaders = map[string]string{"X-Api-Key": "key"}
+	if err := cd.Download(context.Background()); err != nil {
+		t.Fatalf("Download failed: %v", err)
+	}
+	if content, _ := os.ReadFile(outputFile); string(content) != "mirrored content" {
+		t.Errorf("Expected the content of the working mirror, got %q", content)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	for _, request := range authorized {
+		if request != host(primary)+"/file" {
+			t.Errorf("Expected the headers to be sent to the URL only, got %q", authorized)
+			break
+		}
+	}
+	if _, ok := mirrorHeaders[host(primary)+"/mirror/file"]; !ok {
+		t.Error("Expected the mirror headers to be sent to the mirror on the host of the URL")
+	}
+	if _, ok := mirrorHeaders[host(broken)+"/file"]; ok {
+		t.Error("Expected the mirror headers not to be sent to other hosts")
+	}
+}
+
 func TestDownloadWithTimeout(t *testing.T) {
 	// Create a slow server
 	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
@@ -268,4 +432,321 @@ func TestMergeChunks(t *testing.T) {
 	if string(merged) != expected {
 		t.Errorf("Expected %s, got %s", expected, merged)
 	}
-}
\ No newline at end of file
+}
+
+func TestDownloadWithHeaders(t *testing.T) {
+	content := []byte("private asset")
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer secret" || r.Header.Get("Accept") != "application/octet-stream" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.Write(content)
+	}))
+	defer server.Close()
+
+	outputFile := filepath.Join(t.TempDir(), "asset.bin")
+	cd := NewChunkDownloader(server.URL, outputFile)
+	cd.Headers = map[string]string{
+		"Authorization": "Bearer secret",
+		"Accept":        "application/octet-stream",
+	}
+
+	if err := cd.Download(context.Background()); err != nil {
+		t.Fatalf("Download failed: %v", err)
+	}
+
+	downloaded, err := os.ReadFile(outputFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(downloaded) != string(content) {
+		t.Errorf("Expected content %s, got %s", content, downloaded)
+	}
+}
+
+func TestDownloadRetriesWithNewRequests(t *testing.T) {
+	retry.Configure(retry.Policy{Attempts: 3, BaseDelay: time.Millisecond, MaxDelay: time.Millisecond, Statuses: []int{http.StatusServiceUnavailable}})
+	defer retry.Configure(retry.DefaultPolicy())
+
+	content := []byte("retried asset")
+	var mu sync.Mutex
+	failures := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer secret" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		mu.Lock()
+		defer mu.Unlock()
+		if failures < 2 {
+			failures++
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.Write(content)
+	}))
+	defer server.Close()
+
+	outputFile := filepath.Join(t.TempDir(), "asset.bin")
+	cd := NewChunkDownloader(server.URL, outputFile)
+	cd.Headers = map[string]string{"Authorization": "Bearer secret"}
+	if err := cd.downloadSingle(context.Background()); err != nil {
+		t.Fatalf("Download failed: %v", err)
+	}
+	if downloaded, _ := os.ReadFile(outputFile); string(downloaded) != string(content) {
+		t.Errorf("Expected content %s, got %s", content, downloaded)
+	}
+}
+
+func TestDownloadWithSharedProgress(t *testing.T) {
+	content := []byte("shared progress content")
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write(content)
+	}))
+	defer server.Close()
+
+	bar := progressbar.DefaultBytesSilent(1000, "all downloads")
+	ctx := WithProgress(context.Background(), bar)
+
+	tempDir := t.TempDir()
+	for _, name := range []string{"a.bin", "b.bin"} {
+		if err := NewChunkDownloader(server.URL, filepath.Join(tempDir, name)).Download(ctx); err != nil {
+			t.Fatalf("Download failed: %v", err)
+		}
+	}
+
+	if got := bar.State().CurrentNum; got != int64(2*len(content)) {
+		t.Errorf("Expected shared bar to count %d bytes, got %d", 2*len(content), got)
+	}
+}
+
+// recordingGroup is a progress group counting the bytes reported per file
+type recordingGroup struct {
+	mu    sync.Mutex
+	bytes map[string]int
+}
+
+func (g *recordingGroup) Add(description string, size int64) io.Writer {
+	return writerFunc(func(p []byte) (int, error) {
+		g.mu.Lock()
+		defer g.mu.Unlock()
+		g.bytes[description] += len(p)
+		return len(p), nil
+	})
+}
+
+type writerFunc func(p []byte) (int, error)
+
+func (f writerFunc) Write(p []byte) (int, error) { return f(p) }
+
+func TestDownloadWithLimiterAndProgressGroup(t *testing.T) {
+	content := make([]byte, 2048)
+	var mu sync.Mutex
+	active, peak := 0, 0
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		active++
+		peak = max(peak, active)
+		mu.Unlock()
+		defer func() {
+			mu.Lock()
+			active--
+			mu.Unlock()
+		}()
+		time.Sleep(5 * time.Millisecond)
+
+		w.Header().Set("Accept-Ranges", "bytes")
+		var start, end int64
+		if _, err := fmt.Sscanf(r.Header.Get("Range"), "bytes=%d-%d", &start, &end); err != nil {
+			w.Header().Set("Content-Length", fmt.Sprintf("%d", len(content)))
+			if r.Method != "HEAD" {
+				w.Write(content)
+			}
+			return
+		}
+		w.Header().Set("Content-Length", fmt.Sprintf("%d", end-start+1))
+		w.WriteHeader(http.StatusPartialContent)
+		w.Write(content[start : end+1])
+	}))
+	defer server.Close()
+
+	group := &recordingGroup{bytes: make(map[string]int)}
+	ctx := WithLimiter(WithProgressGroup(context.Background(), group), NewLimiter(2))
+
+	tempDir := t.TempDir()
+	var wg sync.WaitGroup
+	for _, name := range []string{"a.bin", "b.bin", "c.bin"} {
+		wg.Add(1)
+		go func(name string) {
+			defer wg.Done()
+			cd := NewChunkDownloader(server.URL, filepath.Join(tempDir, name))
+			cd.ChunkSize = 256
+			if err := cd.Download(ctx); err != nil {
+				t.Errorf("Download %s failed: %v", name, err)
+			}
+		}(name)
+	}
+	wg.Wait()
+
+	if peak > 2 {
+		t.Errorf("Expected at most 2 concurrent requests, got %d", peak)
+	}
+	for _, name := range []string{"a.bin", "b.bin", "c.bin"} {
+		if group.bytes[name] != len(content) {
+			t.Errorf("Expected %d bytes reported for %s, got %d", len(content), name, group.bytes[name])
+		}
+	}
+}
+
+func TestDownloadLowMemory(t *testing.T) {
+	content := make([]byte, 64*1024)
+	for i := range content {
+		content[i] = byte(i % 251)
+	}
+
+	var mu sync.Mutex
+	var methods []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		methods = append(methods, r.Method+" "+r.Header.Get("Range"))
+		mu.Unlock()
+		w.Header().Set("Accept-Ranges", "bytes")
+		w.Header().Set("Content-Length", fmt.Sprintf("%d", len(content)))
+		w.Write(content)
+	}))
+	defer server.Close()
+
+	Configure(Options{LowMemory: true})
+	defer Configure(Options{})
+	if !LowMemory() {
+		t.Fatal("Expected low-memory mode to be enabled")
+	}
+
+	outputFile := filepath.Join(t.TempDir(), "output.bin")
+	cd := NewChunkDownloader(server.URL, outputFile)
+	cd.ChunkSize = 1024
+	if err := cd.Download(WithProgress(context.Background(), io.Discard)); err != nil {
+		t.Fatalf("Download failed: %v", err)
+	}
+
+	downloaded, err := os.ReadFile(outputFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(downloaded) != string(content) {
+		t.Errorf("Downloaded %d bytes that differ from the content", len(downloaded))
+	}
+	if len(methods) != 1 || methods[0] != "GET " {
+		t.Errorf("Expected a single GET without ranges, got %q", methods)
+	}
+}
+
+func TestDownloadResume(t *testing.T) {
+	content := make([]byte, 16*1024)
+	for i := range content {
+		content[i] = byte(i % 251)
+	}
+	etag := `"v1"`
+
+	var mu sync.Mutex
+	var ranges []string
+	interrupt := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		ranges = append(ranges, r.Header.Get("Range"))
+		first := len(ranges) == 1
+		mu.Unlock()
+		w.Header().Set("ETag", etag)
+		if first {
+			// Send half of the file, then stall until the client gives up
+			w.Header().Set("Content-Length", fmt.Sprintf("%d", len(content)))
+			w.Write(content[:len(content)/2])
+			w.(http.Flusher).Flush()
+			close(interrupt)
+			<-r.Context().Done()
+			return
+		}
+		http.ServeContent(w, r, "tool.tar.gz", time.Time{}, bytes.NewReader(content))
+	}))
+	defer server.Close()
+
+	Configure(Options{LowMemory: true})
+	defer Configure(Options{})
+
+	outputFile := filepath.Join(t.TempDir(), "tool.tar.gz")
+	cd := NewChunkDownloader(server.URL, outputFile)
+	ctx, cancel := context.WithCancel(WithProgress(context.Background(), io.Discard))
+	go func() {
+		<-interrupt
+		time.Sleep(50 * time.Millisecond)
+		cancel()
+	}()
+	if err := cd.Download(ctx); err == nil {
+		t.Fatal("Expected the interrupted download to fail")
+	}
+	if _, err := os.Stat(outputFile); !os.IsNotExist(err) {
+		t.Errorf("Expected no file at the destination after an interrupted download: %v", err)
+	}
+	if info, err := os.Stat(outputFile + ".part"); err != nil || info.Size() != int64(len(content)/2) {
+		t.Fatalf("Expected the partial download to be kept: %v", err)
+	}
+
+	if err := cd.Download(WithProgress(context.Background(), io.Discard)); err != nil {
+		t.Fatalf("Download failed: %v", err)
+	}
+	downloaded, err := os.ReadFile(outputFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(downloaded, content) {
+		t.Errorf("Resumed download differs from the content (%d bytes)", len(downloaded))
+	}
+	if want := fmt.Sprintf("bytes=%d-", len(content)/2); len(ranges) != 2 || ranges[1] != want {
+		t.Errorf("Expected the download to resume with %s, got %q", want, ranges)
+	}
+	if _, err := os.Stat(outputFile + ".part"); !os.IsNotExist(err) {
+		t.Error("Expected the partial download to be removed once complete")
+	}
+
+	// A changed file is downloaded again from the start
+	os.WriteFile(outputFile+".part", content[:100], 0644)
+	os.WriteFile(outputFile+".part.info", []byte(server.URL+"\n\"v0\"\n"), 0644)
+	if err := cd.Download(WithProgress(context.Background(), io.Discard)); err != nil {
+		t.Fatalf("Download failed: %v", err)
+	}
+	if downloaded, _ := os.ReadFile(outputFile); !bytes.Equal(downloaded, content) {
+		t.Errorf("Expected the changed file to be downloaded in full, got %d bytes", len(downloaded))
+	}
+}
+
+func TestParseHeaders(t *testing.T) {
+	tests := []struct {
+		name    string
+		values  []string
+		want    map[string]string
+		wantErr bool
+	}{
+		{"none", nil, map[string]string{}, false},
+		{"simple", []string{"X-JFrog-Art-Api: secret"}, map[string]string{"X-Jfrog-Art-Api": "secret"}, false},
+		{"colons in value", []string{"Authorization: Basic dXNlcjpwYXNz", "X-Url: https://example.com:8443/a"},
+			map[string]string{"Authorization": "Basic dXNlcjpwYXNz", "X-Url": "https://example.com:8443/a"}, false},
+		{"whitespace", []string{"  x-api-key \t:   key  "}, map[string]string{"X-Api-Key": "key"}, false},
+		{"empty value", []string{"X-Empty:"}, map[string]string{"X-Empty": ""}, false},
+		{"duplicate keeps last", []string{"X-Key: one", "x-key: two"}, map[string]string{"X-Key": "two"}, false},
+		{"no colon", []string{"X-Key"}, nil, true},
+		{"empty name", []string{": value"}, nil, true},
+		{"space in name", []string{"X Key: value"}, nil, true},
+	}
+	for _, tt := range tests {
+		got, err := ParseHeaders(tt.values)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("%s: ParseHeaders() error = %v, wantErr %v", tt.name, err, tt.wantErr)
+			continue
+		}
+		if !tt.wantErr && !maps.Equal(got, tt.want) {
+			t.Errorf("%s: ParseHeaders() = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
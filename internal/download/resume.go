@@ -0,0 +1,97 @@
+package download
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+)
+
+// Interrupted downloads are kept as FILE.part next to the destination, with the URL and
+// the validator (ETag or Last-Modified) of the response in FILE.part.info. The next
+// download of the same URL asks only for the rest, sending the validator as If-Range so
+// that a changed file is downloaded again from the start.
+
+// partialSuffix is appended to the destination of an unfinished download
+const partialSuffix = ".part"
+
+// partial returns the path of the unfinished download
+func (cd *ChunkDownloader) partial() string {
+	return cd.Filename + partialSuffix
+}
+
+// validator returns the value a response can be revalidated with for If-Range
+func validator(resp *http.Response) string {
+	if etag := resp.Header.Get("ETag"); etag != "" && !strings.HasPrefix(etag, "W/") {
+		return etag
+	}
+	return resp.Header.Get("Last-Modified")
+}
+
+// resumeOffset returns the size of a partial download of the same URL that can be
+// resumed and its validator, or 0 if the download has to start over
+func (cd *ChunkDownloader) resumeOffset() (int64, string) {
+	info, err := os.Stat(cd.partial())
+	if err != nil || info.Size() == 0 {
+		return 0, ""
+	}
+	data, err := os.ReadFile(cd.partial() + ".info")
+	if err != nil {
+		return 0, ""
+	}
+	url, value, _ := strings.Cut(string(data), "\n")
+	value = strings.TrimSpace(value)
+	if url != cd.URL || value == "" {
+		return 0, ""
+	}
+	return info.Size(), value
+}
+
+// savePartial records how an interrupted download can be resumed
+func (cd *ChunkDownloader) savePartial(value string) {
+	if value == "" {
+		return
+	}
+	os.WriteFile(cd.partial()+".info", []byte(cd.URL+"\n"+value+"\n"), 0644)
+}
+
+// removePartial removes an unfinished download and its resume information
+func (cd *ChunkDownloader) removePartial() {
+	os.Remove(cd.partial())
+	os.Remove(cd.partial() + ".info")
+}
+
+// finishPartial moves a completed download into place
+func (cd *ChunkDownloader) finishPartial() error {
+	if err := os.Rename(cd.partial(), cd.Filename); err != nil {
+		return fmt.Errorf("failed to move download into place: %w", err)
+	}
+	os.Remove(cd.partial() + ".info")
+	return nil
+}
+
+// savePrefix keeps the leading completed chunks of an interrupted parallel download as
+// a partial download, so that it can be resumed in a single request
+func (cd *ChunkDownloader) savePrefix(tempFiles []*os.File, done []bool, value string) {
+	if value == "" || len(done) == 0 || !done[0] {
+		return
+	}
+	out, err := os.Create(cd.partial())
+	if err != nil {
+		return
+	}
+	defer out.Close()
+	for i, tempFile := range tempFiles {
+		if !done[i] || tempFile == nil {
+			break
+		}
+		if _, err := tempFile.Seek(0, 0); err != nil {
+			break
+		}
+		if _, err := io.Copy(out, tempFile); err != nil {
+			break
+		}
+	}
+	cd.savePartial(value)
+}
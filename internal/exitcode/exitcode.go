@@ -0,0 +1,77 @@
+package exitcode
+
+import (
+	"context"
+	"errors"
+	"io/fs"
+	"net"
+	"net/url"
+)
+
+// Exit codes of the installer, documented in the README. Scripts may rely on them, so
+// existing values must not change.
+const (
+	OK           = 0 // Success
+	General      = 1 // Any failure without a more specific code
+	Usage        = 2 // Invalid command line
+	Network      = 3 // Download or API request failed
+	Verification = 4 // Checksum or signature mismatch
+	Permission   = 5 // Permission denied writing files or directories
+	NotFound     = 6 // Release, tag or matching asset not found
+	Extraction   = 7 // Archive could not be extracted
+	Partial      = 8 // Some targets of a batch failed, others succeeded
+
+	Interrupted = 130 // Stopped by SIGINT or SIGTERM, as shells report 128+SIGINT
+)
+
+// Error carries the exit code of a failure
+type Error struct {
+	Code int
+	Err  error
+}
+
+func (e *Error) Error() string {
+	return e.Err.Error()
+}
+
+// Unwrap returns the underlying error
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+// Wrap marks err with an exit code unless it already has a more specific one
+func Wrap(code int, err error) error {
+	if err == nil {
+		return nil
+	}
+	if existing := Code(err); existing != General {
+		return err
+	}
+	return &Error{Code: code, Err: err}
+}
+
+// Code returns the exit code for err: the code it was marked with, otherwise one
+// derived from the kind of error, falling back to General
+func Code(err error) int {
+	if err == nil {
+		return OK
+	}
+
+	var coded *Error
+	if errors.As(err, &coded) {
+		return coded.Code
+	}
+
+	// net.Error is not matched, as syscall errors such as ENOENT implement it too
+	var opErr *net.OpError
+	var urlErr *url.Error
+	switch {
+	case errors.Is(err, context.Canceled):
+		return Interrupted
+	case errors.Is(err, fs.ErrPermission):
+		return Permission
+	case errors.As(err, &opErr), errors.As(err, &urlErr), errors.Is(err, context.DeadlineExceeded):
+		return Network
+	}
+	return General
+}
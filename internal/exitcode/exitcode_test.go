@@ -0,0 +1,54 @@
+package exitcode
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"testing"
+)
+
+func TestCode(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want int
+	}{
+		{"Nil", nil, OK},
+		{"Plain", errors.New("boom"), General},
+		{"Marked", Wrap(Verification, errors.New("checksum mismatch")), Verification},
+		{"Marked and wrapped", fmt.Errorf("install failed: %w", Wrap(NotFound, errors.New("no asset"))), NotFound},
+		{"Permission", fmt.Errorf("failed to create directory: %w", os.ErrPermission), Permission},
+		{"Missing file", func() error { _, err := os.Open("/nonexistent/file"); return err }(), General},
+		{"Network", fmt.Errorf("download failed: %w", &url.Error{Op: "Get", URL: "https://example.com", Err: errors.New("refused")}), Network},
+		{"Interrupted", fmt.Errorf("download failed: %w", &url.Error{Op: "Get", URL: "https://example.com", Err: context.Canceled}), Interrupted},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Code(tt.err); got != tt.want {
+				t.Errorf("Code() = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestWrapKeepsSpecificCode(t *testing.T) {
+	err := Wrap(Network, fmt.Errorf("failed to write file: %w", os.ErrPermission))
+	if Code(err) != Permission {
+		t.Errorf("Expected the permission error to keep its code, got %d", Code(err))
+	}
+
+	err = Wrap(Network, errors.New("download failed: 500"))
+	if Code(err) != Network {
+		t.Errorf("Expected Network, got %d", Code(err))
+	}
+	if err.Error() != "download failed: 500" {
+		t.Errorf("Wrap should not change the message, got %q", err.Error())
+	}
+
+	if Wrap(Network, nil) != nil {
+		t.Error("Wrap(nil) should be nil")
+	}
+}
@@ -0,0 +1,125 @@
+package export
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/pyhub-kr/pyhub-installer/internal/state"
+)
+
+// Formats are the supported export formats
+var Formats = []string{"scoop", "brew"}
+
+// scoopManifest is the subset of a Scoop manifest generated from a receipt
+type scoopManifest struct {
+	Version  string      `json:"version"`
+	Homepage string      `json:"homepage,omitempty"`
+	URL      string      `json:"url"`
+	Hash     string      `json:"hash"`
+	Bin      interface{} `json:"bin"`
+}
+
+// Manifest generates a package manifest in a format from an install receipt. bins are
+// the executables to expose, as paths inside the archive; by default the tool name.
+func Manifest(format string, receipt state.Receipt, bins []string) ([]byte, error) {
+	if receipt.URL == "" {
+		return nil, fmt.Errorf("receipt of %s has no download URL", receipt.Name)
+	}
+	if receipt.SHA256 == "" {
+		return nil, fmt.Errorf("receipt of %s has no SHA256; reinstall it to record one", receipt.Name)
+	}
+	if len(bins) == 0 {
+		bins = []string{receipt.Name}
+	}
+
+	switch format {
+	case "scoop":
+		return Scoop(receipt, bins)
+	case "brew":
+		return []byte(Brew(receipt, bins)), nil
+	}
+	return nil, fmt.Errorf("unknown export format %q (supported: %s)", format, strings.Join(Formats, ", "))
+}
+
+// Scoop generates a Scoop manifest. Bins without an extension get .exe.
+func Scoop(receipt state.Receipt, bins []string) ([]byte, error) {
+	paths := make([]string, len(bins))
+	for i, bin := range bins {
+		if !strings.Contains(bin[strings.LastIndexAny(bin, `/\`)+1:], ".") {
+			bin += ".exe"
+		}
+		paths[i] = strings.ReplaceAll(bin, "/", `\`)
+	}
+
+	manifest := scoopManifest{
+		Version:  version(receipt),
+		Homepage: Homepage(receipt.Source),
+		URL:      receipt.URL,
+		Hash:     receipt.SHA256,
+		Bin:      paths,
+	}
+	if len(paths) == 1 {
+		manifest.Bin = paths[0]
+	}
+
+	var buf bytes.Buffer
+	encoder := json.NewEncoder(&buf)
+	encoder.SetEscapeHTML(false)
+	encoder.SetIndent("", "    ")
+	if err := encoder.Encode(manifest); err != nil {
+		return nil, fmt.Errorf("failed to encode manifest: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+// Brew generates a Homebrew formula
+func Brew(receipt state.Receipt, bins []string) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "class %s < Formula\n", ClassName(receipt.Name))
+	fmt.Fprintf(&b, "  desc \"%s\"\n", receipt.Name)
+	if homepage := Homepage(receipt.Source); homepage != "" {
+		fmt.Fprintf(&b, "  homepage \"%s\"\n", homepage)
+	}
+	fmt.Fprintf(&b, "  url \"%s\"\n", receipt.URL)
+	fmt.Fprintf(&b, "  version \"%s\"\n", version(receipt))
+	fmt.Fprintf(&b, "  sha256 \"%s\"\n", receipt.SHA256)
+	b.WriteString("\n  def install\n")
+	for _, bin := range bins {
+		fmt.Fprintf(&b, "    bin.install \"%s\"\n", bin)
+	}
+	b.WriteString("  end\nend\n")
+	return b.String()
+}
+
+// Homepage returns the project page of a GitHub source, or "" for other sources
+func Homepage(source string) string {
+	repo, ok := strings.CutPrefix(source, "github:")
+	if !ok {
+		return ""
+	}
+	if i := strings.Index(repo, "github.com/"); i >= 0 {
+		repo = repo[i+len("github.com/"):]
+	}
+	parts := strings.Split(strings.Trim(repo, "/"), "/")
+	if len(parts) < 2 {
+		return ""
+	}
+	return "https://github.com/" + parts[0] + "/" + parts[1]
+}
+
+// ClassName converts a tool name to a Homebrew formula class name, e.g.
+// "pyhub-mcptools" to "PyhubMcptools"
+func ClassName(name string) string {
+	var b strings.Builder
+	for _, part := range strings.FieldsFunc(name, func(r rune) bool { return r == '-' || r == '_' || r == '.' }) {
+		b.WriteString(strings.ToUpper(part[:1]) + part[1:])
+	}
+	return b.String()
+}
+
+// version returns the receipt's version without a leading v, as package managers expect
+func version(receipt state.Receipt) string {
+	return strings.TrimPrefix(receipt.Version, "v")
+}
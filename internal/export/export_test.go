@@ -0,0 +1,98 @@
+package export
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/pyhub-kr/pyhub-installer/internal/state"
+)
+
+var receipt = state.Receipt{
+	Name:    "pyhub-mcptools",
+	Source:  "github:pyhub-kr/pyhub-mcptools",
+	Version: "v1.2.0",
+	URL:     "https://github.com/pyhub-kr/pyhub-mcptools/releases/download/v1.2.0/pyhub-mcptools-windows-amd64.zip",
+	SHA256:  "0123456789abcdef0123456789abcdef0123456789abcdef0123456789abcdef",
+}
+
+func TestScoop(t *testing.T) {
+	data, err := Manifest("scoop", receipt, nil)
+	if err != nil {
+		t.Fatalf("Manifest() error = %v", err)
+	}
+
+	var manifest map[string]interface{}
+	if err := json.Unmarshal(data, &manifest); err != nil {
+		t.Fatalf("Invalid JSON: %v\n%s", err, data)
+	}
+	want := map[string]interface{}{
+		"version":  "1.2.0",
+		"homepage": "https://github.com/pyhub-kr/pyhub-mcptools",
+		"url":      receipt.URL,
+		"hash":     receipt.SHA256,
+		"bin":      "pyhub-mcptools.exe",
+	}
+	for key, value := range want {
+		if manifest[key] != value {
+			t.Errorf("%s = %v, want %v", key, manifest[key], value)
+		}
+	}
+
+	data, err = Manifest("scoop", receipt, []string{"bin/tool", "tool.cmd"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(data), `"bin\\tool.exe"`) || !strings.Contains(string(data), `"tool.cmd"`) {
+		t.Errorf("Unexpected bin entries:\n%s", data)
+	}
+}
+
+func TestBrew(t *testing.T) {
+	data, err := Manifest("brew", receipt, []string{"pyhub-mcptools"})
+	if err != nil {
+		t.Fatalf("Manifest() error = %v", err)
+	}
+
+	want := `class PyhubMcptools < Formula
+  desc "pyhub-mcptools"
+  homepage "https://github.com/pyhub-kr/pyhub-mcptools"
+  url "` + receipt.URL + `"
+  version "1.2.0"
+  sha256 "` + receipt.SHA256 + `"
+
+  def install
+    bin.install "pyhub-mcptools"
+  end
+end
+`
+	if string(data) != want {
+		t.Errorf("Unexpected formula:\n%s", data)
+	}
+}
+
+func TestManifestErrors(t *testing.T) {
+	if _, err := Manifest("nix", receipt, nil); err == nil {
+		t.Error("Expected error for an unknown format")
+	}
+
+	noHash := receipt
+	noHash.SHA256 = ""
+	if _, err := Manifest("scoop", noHash, nil); err == nil {
+		t.Error("Expected error for a receipt without SHA256")
+	}
+}
+
+func TestHomepage(t *testing.T) {
+	tests := map[string]string{
+		"github:cli/cli":                    "https://github.com/cli/cli",
+		"github:https://github.com/cli/cli": "https://github.com/cli/cli",
+		"scoop:jq":                          "",
+		"url:https://example.com/tool.zip":  "",
+	}
+	for source, want := range tests {
+		if got := Homepage(source); got != want {
+			t.Errorf("Homepage(%q) = %q, want %q", source, got, want)
+		}
+	}
+}
@@ -4,11 +4,15 @@ import (
 	"archive/tar"
 	"archive/zip"
 	"compress/gzip"
+	"context"
 	"fmt"
 	"io"
 	"os"
 	"path/filepath"
+	"runtime"
 	"strings"
+
+	"github.com/pyhub-kr/pyhub-installer/internal/replace"
 )
 
 // Extractor handles archive extraction
@@ -17,6 +21,14 @@ type Extractor struct {
 	DestPath    string
 	flatten     bool
 	autoFlatten bool
+
+	windowsNames bool
+	renamed      []Rename
+	renamedSeen  map[string]bool
+
+	ctx     context.Context
+	created []string // Paths created by the running extraction, for rollback
+	written []string // Files written by the extraction, see Files
 }
 
 // NewExtractor creates a new extractor
@@ -26,6 +38,7 @@ func NewExtractor(archivePath, destPath string) *Extractor {
 		DestPath:    destPath,
 		flatten:     false,
 		autoFlatten: false,
+		windowsNames: runtime.GOOS == "windows",
 	}
 }
 
@@ -39,28 +52,61 @@ func (e *Extractor) SetAutoFlatten(autoFlatten bool) {
 	e.autoFlatten = autoFlatten
 }
 
+// SetWindowsNames enables or disables renaming entries that are invalid on Windows,
+// which is enabled by default on Windows
+func (e *Extractor) SetWindowsNames(windowsNames bool) {
+	e.windowsNames = windowsNames
+}
+
+// Renamed returns the archive paths renamed during extraction to be valid on Windows
+func (e *Extractor) Renamed() []Rename {
+	return e.renamed
+}
+
+// Files returns the files written by the last extraction, e.g. to remove them when
+// the tool is uninstalled
+func (e *Extractor) Files() []string {
+	return e.written
+}
+
 // Extract extracts archive based on file extension
 func (e *Extractor) Extract() error {
+	return e.ExtractContext(context.Background())
+}
+
+// ExtractContext extracts archive based on file extension, stopping when ctx is
+// canceled. If extraction fails or is canceled, the files and directories it created
+// are removed again.
+func (e *Extractor) ExtractContext(ctx context.Context) error {
+	e.ctx = ctx
+	e.created, e.written = nil, nil
+
 	// Create destination directory
-	if err := os.MkdirAll(e.DestPath, 0755); err != nil {
+	if err := e.mkdirAll(e.DestPath, 0755); err != nil {
 		return fmt.Errorf("failed to create destination directory: %w", err)
 	}
 
+	var err error
 	ext := strings.ToLower(filepath.Ext(e.ArchivePath))
 	
 	switch ext {
 	case ".zip":
-		return e.extractZip()
+		err = e.extractZip()
 	case ".gz":
 		if strings.HasSuffix(strings.ToLower(e.ArchivePath), ".tar.gz") {
-			return e.extractTarGz()
+			err = e.extractTarGz()
+		} else {
+			err = e.extractGzip()
 		}
-		return e.extractGzip()
 	case ".tar":
-		return e.extractTar()
+		err = e.extractTar()
 	default:
-		return fmt.Errorf("unsupported archive format: %s", ext)
+		err = fmt.Errorf("unsupported archive format: %s", ext)
+	}
+	if err != nil {
+		e.rollback()
 	}
+	return err
 }
 
 // extractZip extracts ZIP archives
@@ -85,11 +131,15 @@ func (e *Extractor) extractZip() error {
 	}
 
 	for _, file := range reader.File {
+		if err := e.context().Err(); err != nil {
+			return err
+		}
 		if err := e.extractZipFile(file, shouldFlatten); err != nil {
 			return fmt.Errorf("failed to extract %s: %w", file.Name, err)
 		}
 	}
 
+	e.reportRenamed()
 	fmt.Println("✓ ZIP extraction completed")
 	return nil
 }
@@ -104,6 +154,7 @@ func (e *Extractor) extractZipFile(file *zip.File, shouldFlatten bool) error {
 			return nil // Skip the top-level directory itself
 		}
 	}
+	fileName = e.sanitize(fileName)
 	
 	// Security check: prevent zip slip
 	destPath := filepath.Join(e.DestPath, fileName)
@@ -112,11 +163,11 @@ func (e *Extractor) extractZipFile(file *zip.File, shouldFlatten bool) error {
 	}
 
 	if file.FileInfo().IsDir() {
-		return os.MkdirAll(destPath, file.FileInfo().Mode())
+		return e.mkdirAll(destPath, file.FileInfo().Mode())
 	}
 
 	// Create directory for file
-	if err := os.MkdirAll(filepath.Dir(destPath), 0755); err != nil {
+	if err := e.mkdirAll(filepath.Dir(destPath), 0755); err != nil {
 		return err
 	}
 
@@ -127,13 +178,18 @@ func (e *Extractor) extractZipFile(file *zip.File, shouldFlatten bool) error {
 	}
 	defer reader.Close()
 
+	if err := replace.Prepare(destPath); err != nil {
+		return err
+	}
+
+	e.track(destPath)
 	writer, err := os.OpenFile(destPath, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, file.FileInfo().Mode())
 	if err != nil {
 		return err
 	}
 	defer writer.Close()
 
-	_, err = io.Copy(writer, reader)
+	_, err = io.Copy(writer, e.reader(reader))
 	return err
 }
 
@@ -240,6 +296,9 @@ func (e *Extractor) extractTarWithFlatten(file *os.File) error {
 // extractTarReaderWithFlatten extracts from tar reader with optional flattening
 func (e *Extractor) extractTarReaderWithFlatten(tarReader *tar.Reader, shouldFlatten bool) error {
 	for {
+		if err := e.context().Err(); err != nil {
+			return err
+		}
 		header, err := tarReader.Next()
 		if err == io.EOF {
 			break
@@ -253,6 +312,7 @@ func (e *Extractor) extractTarReaderWithFlatten(tarReader *tar.Reader, shouldFla
 		}
 	}
 
+	e.reportRenamed()
 	fmt.Println("✓ TAR extraction completed")
 	return nil
 }
@@ -267,6 +327,7 @@ func (e *Extractor) extractTarFile(header *tar.Header, reader *tar.Reader, shoul
 			return nil // Skip the top-level directory itself
 		}
 	}
+	fileName = e.sanitize(fileName)
 	
 	// Security check: prevent tar slip
 	destPath := filepath.Join(e.DestPath, fileName)
@@ -276,21 +337,25 @@ func (e *Extractor) extractTarFile(header *tar.Header, reader *tar.Reader, shoul
 
 	switch header.Typeflag {
 	case tar.TypeDir:
-		return os.MkdirAll(destPath, os.FileMode(header.Mode))
+		return e.mkdirAll(destPath, os.FileMode(header.Mode))
 	case tar.TypeReg:
 		// Create directory for file
-		if err := os.MkdirAll(filepath.Dir(destPath), 0755); err != nil {
+		if err := e.mkdirAll(filepath.Dir(destPath), 0755); err != nil {
 			return err
 		}
 
-		// Extract file
+		// Extract file, moving a running executable out of the way first
+		if err := replace.Prepare(destPath); err != nil {
+			return err
+		}
+		e.track(destPath)
 		writer, err := os.OpenFile(destPath, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, os.FileMode(header.Mode))
 		if err != nil {
 			return err
 		}
 		defer writer.Close()
 
-		_, err = io.Copy(writer, reader)
+		_, err = io.Copy(writer, e.reader(reader))
 		return err
 	default:
 		// Skip unsupported file types (symlinks, etc.)
@@ -314,8 +379,14 @@ func (e *Extractor) extractGzip() error {
 
 	// Determine output filename
 	outputName := strings.TrimSuffix(filepath.Base(e.ArchivePath), ".gz")
+	outputName = e.sanitize(outputName)
 	outputPath := filepath.Join(e.DestPath, outputName)
 
+	if err := replace.Prepare(outputPath); err != nil {
+		return err
+	}
+
+	e.track(outputPath)
 	writer, err := os.Create(outputPath)
 	if err != nil {
 		return fmt.Errorf("failed to create output file: %w", err)
@@ -324,11 +395,12 @@ func (e *Extractor) extractGzip() error {
 
 	fmt.Printf("Extracting GZIP file to %s...\n", outputPath)
 
-	_, err = io.Copy(writer, gzReader)
+	_, err = io.Copy(writer, e.reader(gzReader))
 	if err != nil {
 		return fmt.Errorf("failed to extract GZIP: %w", err)
 	}
 
+	e.reportRenamed()
 	fmt.Println("✓ GZIP extraction completed")
 	return nil
 }
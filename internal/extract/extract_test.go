@@ -5,6 +5,8 @@ import (
 	"archive/zip"
 	"bytes"
 	"compress/gzip"
+	"context"
+	"errors"
 	"io"
 	"os"
 	"path/filepath"
@@ -464,6 +466,11 @@ func TestExtractZipWithFlatten(t *testing.T) {
 		if _, err := os.Stat(filepath.Join(extractPath, "config", "settings.json")); err != nil {
 			t.Error("Expected config/settings.json to exist")
 		}
+
+		written := extractor.Files()
+		if len(written) != 3 || written[2] != filepath.Join(extractPath, "config", "settings.json") {
+			t.Errorf("Expected the 3 written files, got %v", written)
+		}
 	})
 
 	t.Run("WithAutoFlatten", func(t *testing.T) {
@@ -563,4 +570,53 @@ func TestExtractTarGzWithFlatten(t *testing.T) {
 			t.Error("Expected config/settings.json to exist")
 		}
 	})
-}
\ No newline at end of file
+}
+func TestExtractRollback(t *testing.T) {
+	tempDir := t.TempDir()
+	destDir := filepath.Join(tempDir, "out")
+	if err := os.MkdirAll(destDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(destDir, "existing.txt"), []byte("kept"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	// The last entry escapes the destination, failing the extraction halfway
+	zipFile := filepath.Join(tempDir, "test.zip")
+	file, err := os.Create(zipFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	w := zip.NewWriter(file)
+	for _, name := range []string{"bin/tool", "docs/readme.txt", "../escape.txt"} {
+		f, err := w.Create(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		f.Write([]byte(name))
+	}
+	w.Close()
+	file.Close()
+
+	if err := NewExtractor(zipFile, destDir).Extract(); err == nil {
+		t.Fatal("Expected the extraction to fail")
+	}
+	entries, err := os.ReadDir(destDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 1 || entries[0].Name() != "existing.txt" {
+		t.Errorf("Expected only the existing file after rollback, got %v", entries)
+	}
+
+	// A canceled extraction stops and leaves nothing behind
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	newDir := filepath.Join(tempDir, "new")
+	if err := NewExtractor(zipFile, newDir).ExtractContext(ctx); !errors.Is(err, context.Canceled) {
+		t.Fatalf("ExtractContext() error = %v, want context.Canceled", err)
+	}
+	if _, err := os.Stat(newDir); !os.IsNotExist(err) {
+		t.Errorf("Expected the destination created by the extraction to be removed: %v", err)
+	}
+}
@@ -0,0 +1,68 @@
+package extract
+
+import (
+	"context"
+	"io"
+	"os"
+	"path/filepath"
+)
+
+// contextReader fails reads once its context is canceled, so that copying a large
+// entry stops promptly
+type contextReader struct {
+	ctx context.Context
+	r   io.Reader
+}
+
+func (r *contextReader) Read(p []byte) (int, error) {
+	if err := r.ctx.Err(); err != nil {
+		return 0, err
+	}
+	return r.r.Read(p)
+}
+
+// reader wraps an entry reader to honor the extraction context
+func (e *Extractor) reader(r io.Reader) io.Reader {
+	return &contextReader{ctx: e.context(), r: r}
+}
+
+// context returns the context of the running extraction
+func (e *Extractor) context() context.Context {
+	if e.ctx == nil {
+		return context.Background()
+	}
+	return e.ctx
+}
+
+// track records a file about to be written, and as created by the extraction if it
+// does not exist yet
+func (e *Extractor) track(path string) {
+	if _, err := os.Lstat(path); os.IsNotExist(err) {
+		e.created = append(e.created, path)
+	}
+	e.written = append(e.written, path)
+}
+
+// mkdirAll creates a directory and its missing parents, recording the ones it creates
+func (e *Extractor) mkdirAll(dir string, mode os.FileMode) error {
+	var missing []string
+	for d := dir; ; d = filepath.Dir(d) {
+		if _, err := os.Lstat(d); err == nil || filepath.Dir(d) == d {
+			break
+		}
+		missing = append(missing, d)
+	}
+	for i := len(missing) - 1; i >= 0; i-- {
+		e.created = append(e.created, missing[i])
+	}
+	return os.MkdirAll(dir, mode)
+}
+
+// rollback removes the files and directories created by a failed extraction, newest
+// first. Files that existed before were overwritten and are left as they are.
+func (e *Extractor) rollback() {
+	for i := len(e.created) - 1; i >= 0; i-- {
+		os.Remove(e.created[i])
+	}
+	e.created, e.written = nil, nil
+}
@@ -0,0 +1,103 @@
+package extract
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Rename records an archive path changed to be valid on Windows
+type Rename struct {
+	From string
+	To   string
+}
+
+// reservedNames are device names Windows refuses as file names, with any extension
+var reservedNames = map[string]bool{
+	"CON": true, "PRN": true, "AUX": true, "NUL": true,
+	"COM1": true, "COM2": true, "COM3": true, "COM4": true, "COM5": true,
+	"COM6": true, "COM7": true, "COM8": true, "COM9": true,
+	"LPT1": true, "LPT2": true, "LPT3": true, "LPT4": true, "LPT5": true,
+	"LPT6": true, "LPT7": true, "LPT8": true, "LPT9": true,
+}
+
+// WindowsName returns a slash-separated archive path with every element made valid on
+// Windows: characters Windows rejects become "_", trailing dots and spaces are removed
+// and reserved device names get a "_" suffix, e.g. "docs/aux.txt" becomes
+// "docs/aux_.txt". It reports whether anything changed.
+func WindowsName(name string) (string, bool) {
+	parts := strings.Split(name, "/")
+	changed := false
+	for i, part := range parts {
+		if part == "" || part == "." || part == ".." {
+			continue
+		}
+		if clean := windowsElement(part); clean != part {
+			parts[i] = clean
+			changed = true
+		}
+	}
+	return strings.Join(parts, "/"), changed
+}
+
+// windowsElement makes a single path element valid on Windows
+func windowsElement(element string) string {
+	element = strings.Map(func(r rune) rune {
+		if r < 32 || strings.ContainsRune(`<>:"|?*\`, r) {
+			return '_'
+		}
+		return r
+	}, element)
+
+	element = strings.TrimRight(element, ". ")
+	if element == "" {
+		return "_"
+	}
+
+	base, ext, _ := strings.Cut(element, ".")
+	if reservedNames[strings.ToUpper(strings.TrimRight(base, " "))] {
+		element = base + "_"
+		if ext != "" {
+			element += "." + ext
+		}
+	}
+	return element
+}
+
+// sanitize returns the name to extract an entry to, recording the first renamed
+// element of its path so that a renamed directory is reported once
+func (e *Extractor) sanitize(name string) string {
+	if !e.windowsNames {
+		return name
+	}
+	clean, changed := WindowsName(name)
+	if !changed {
+		return name
+	}
+
+	from, to := strings.Split(name, "/"), strings.Split(clean, "/")
+	for i := range from {
+		if from[i] != to[i] {
+			rename := Rename{From: strings.Join(from[:i+1], "/"), To: strings.Join(to[:i+1], "/")}
+			if !e.renamedSeen[rename.From] {
+				if e.renamedSeen == nil {
+					e.renamedSeen = make(map[string]bool)
+				}
+				e.renamedSeen[rename.From] = true
+				e.renamed = append(e.renamed, rename)
+			}
+			break
+		}
+	}
+	return clean
+}
+
+// reportRenamed lists the paths renamed during extraction
+func (e *Extractor) reportRenamed() {
+	if len(e.renamed) == 0 {
+		return
+	}
+	fmt.Printf("Warning: renamed %d paths that are invalid on Windows:\n", len(e.renamed))
+	for _, rename := range e.renamed {
+		fmt.Printf("  %s -> %s\n", rename.From, rename.To)
+	}
+}
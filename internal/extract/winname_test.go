@@ -0,0 +1,80 @@
+package extract
+
+import (
+	"archive/zip"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestWindowsName(t *testing.T) {
+	tests := []struct {
+		name    string
+		want    string
+		changed bool
+	}{
+		{"bin/tool.exe", "bin/tool.exe", false},
+		{"docs/CON", "docs/CON_", true},
+		{"docs/aux.txt", "docs/aux_.txt", true},
+		{"nul/readme", "nul_/readme", true},
+		{"LPT1.tar.gz", "LPT1_.tar.gz", true},
+		{"console.txt", "console.txt", false},
+		{"notes. ", "notes", true},
+		{"dir./file", "dir/file", true},
+		{`what?<is>:"this"|*.md`, "what__is___this___.md", true},
+		{"...", "_", true},
+		{"dir/", "dir/", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, changed := WindowsName(tt.name)
+			if got != tt.want || changed != tt.changed {
+				t.Errorf("WindowsName(%q) = %q, %v, want %q, %v", tt.name, got, changed, tt.want, tt.changed)
+			}
+		})
+	}
+}
+
+func TestExtractWindowsNames(t *testing.T) {
+	tmpDir := t.TempDir()
+	archivePath := filepath.Join(tmpDir, "test.zip")
+	file, err := os.Create(archivePath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	w := zip.NewWriter(file)
+	for _, name := range []string{"aux/one.txt", "aux/two.txt", "tool/CON.md", "tool/ok.txt"} {
+		f, err := w.Create(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		f.Write([]byte(name))
+	}
+	w.Close()
+	file.Close()
+
+	destDir := filepath.Join(tmpDir, "out")
+	extractor := NewExtractor(archivePath, destDir)
+	extractor.SetWindowsNames(true)
+	if err := extractor.Extract(); err != nil {
+		t.Fatalf("Extract() error = %v", err)
+	}
+
+	for _, path := range []string{"aux_/one.txt", "aux_/two.txt", "tool/CON_.md", "tool/ok.txt"} {
+		if _, err := os.Stat(filepath.Join(destDir, filepath.FromSlash(path))); err != nil {
+			t.Errorf("Expected %s to be extracted: %v", path, err)
+		}
+	}
+
+	want := []Rename{{From: "aux", To: "aux_"}, {From: "tool/CON.md", To: "tool/CON_.md"}}
+	renamed := extractor.Renamed()
+	if len(renamed) != len(want) {
+		t.Fatalf("Renamed() = %v, want %v", renamed, want)
+	}
+	for i := range want {
+		if renamed[i] != want[i] {
+			t.Errorf("Renamed()[%d] = %v, want %v", i, renamed[i], want[i])
+		}
+	}
+}
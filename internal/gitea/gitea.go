@@ -0,0 +1,166 @@
+package gitea
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/pyhub-kr/pyhub-installer/internal/retry"
+	"github.com/pyhub-kr/pyhub-installer/internal/useragent"
+)
+
+// pageSize is the number of releases requested per page; servers may cap it lower
+const pageSize = 50
+
+// ErrNotFound matches API errors for missing repositories and releases
+var ErrNotFound = errors.New("not found")
+
+// Client fetches releases from the API of a Gitea or Forgejo server
+type Client struct {
+	BaseURL    string // Server URL, e.g. https://git.example.com
+	Token      string // Access token, if set
+	HTTPClient *http.Client
+}
+
+// NewClient creates a client for a server, reading the token from GITEA_TOKEN or
+// FORGEJO_TOKEN
+func NewClient(baseURL string) *Client {
+	token := os.Getenv("GITEA_TOKEN")
+	if token == "" {
+		token = os.Getenv("FORGEJO_TOKEN")
+	}
+	return &Client{
+		BaseURL:    strings.TrimRight(baseURL, "/"),
+		Token:      token,
+		HTTPClient: &http.Client{Timeout: 30 * time.Second},
+	}
+}
+
+// Release is a release of a repository
+type Release struct {
+	TagName     string    `json:"tag_name"`
+	Name        string    `json:"name"`
+	Body        string    `json:"body"`
+	Draft       bool      `json:"draft"`
+	Prerelease  bool      `json:"prerelease"`
+	PublishedAt time.Time `json:"published_at"`
+	HTMLURL     string    `json:"html_url"`
+	Assets      []Asset   `json:"assets"`
+}
+
+// Asset is a file attached to a release
+type Asset struct {
+	Name               string `json:"name"`
+	Size               int64  `json:"size"`
+	BrowserDownloadURL string `json:"browser_download_url"`
+}
+
+// ParseRepoURL splits a repository URL such as https://git.example.com/owner/repo into
+// the server URL and the repository. Servers may be hosted under a path.
+func ParseRepoURL(input string) (baseURL, owner, repo string, err error) {
+	u, err := url.Parse(strings.TrimSpace(input))
+	if err != nil || (u.Scheme != "https" && u.Scheme != "http") || u.Host == "" {
+		return "", "", "", fmt.Errorf("invalid repository URL: %s (expected: https://host/owner/repo)", input)
+	}
+	parts := strings.Split(strings.Trim(u.Path, "/"), "/")
+	if len(parts) < 2 || parts[len(parts)-2] == "" {
+		return "", "", "", fmt.Errorf("invalid repository URL: %s (expected: https://host/owner/repo)", input)
+	}
+	owner = parts[len(parts)-2]
+	repo = strings.TrimSuffix(parts[len(parts)-1], ".git")
+
+	u.Path = strings.Join(parts[:len(parts)-2], "/")
+	if u.Path != "" {
+		u.Path = "/" + u.Path
+	}
+	u.RawQuery, u.Fragment = "", ""
+	return u.String(), owner, repo, nil
+}
+
+// GetLatestRelease gets the newest published release that is not a pre-release
+func (c *Client) GetLatestRelease(owner, repo string) (*Release, error) {
+	var release Release
+	if err := c.getJSON(fmt.Sprintf("/repos/%s/%s/releases/latest", owner, repo), &release); err != nil {
+		return nil, fmt.Errorf("failed to fetch release: %w", err)
+	}
+	return &release, nil
+}
+
+// GetRelease gets the release of a tag
+func (c *Client) GetRelease(owner, repo, tag string) (*Release, error) {
+	var release Release
+	if err := c.getJSON(fmt.Sprintf("/repos/%s/%s/releases/tags/%s", owner, repo, url.PathEscape(tag)), &release); err != nil {
+		return nil, fmt.Errorf("failed to fetch release %s: %w", tag, err)
+	}
+	return &release, nil
+}
+
+// ListReleases lists the releases of a repository, newest first
+func (c *Client) ListReleases(owner, repo string) ([]Release, error) {
+	var releases []Release
+	for page := 1; ; page++ {
+		var items []Release
+		endpoint := fmt.Sprintf("/repos/%s/%s/releases?limit=%d&page=%d", owner, repo, pageSize, page)
+		if err := c.getJSON(endpoint, &items); err != nil {
+			return nil, fmt.Errorf("failed to fetch releases: %w", err)
+		}
+		releases = append(releases, items...)
+		if len(items) == 0 || len(items) < pageSize {
+			return releases, nil
+		}
+	}
+}
+
+// AuthHeaders returns the headers that authenticate a request with the client's token,
+// or nil without a token
+func (c *Client) AuthHeaders() map[string]string {
+	if c.Token == "" {
+		return nil
+	}
+	return map[string]string{"Authorization": "token " + c.Token}
+}
+
+// getJSON decodes a document of the server's API
+func (c *Client) getJSON(endpoint string, v interface{}) error {
+	client := c.HTTPClient
+	if client == nil {
+		client = http.DefaultClient
+	}
+
+	resp, err := retry.Do(context.Background(), func() (*http.Response, error) {
+		req, err := http.NewRequest("GET", c.BaseURL+"/api/v1"+endpoint, nil)
+		if err != nil {
+			return nil, err
+		}
+		useragent.Apply(req)
+		for key, value := range c.AuthHeaders() {
+			req.Header.Set(key, value)
+		}
+		req.Header.Set("Accept", "application/json")
+		return client.Do(req)
+	})
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+	case http.StatusNotFound:
+		return fmt.Errorf("Gitea API error: %d (%w)", resp.StatusCode, ErrNotFound)
+	case http.StatusUnauthorized, http.StatusForbidden:
+		return fmt.Errorf("Gitea API error: %d (check GITEA_TOKEN)", resp.StatusCode)
+	default:
+		return fmt.Errorf("Gitea API error: %d", resp.StatusCode)
+	}
+	if err := json.NewDecoder(resp.Body).Decode(v); err != nil {
+		return fmt.Errorf("failed to decode response: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,112 @@
+package gitea
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestParseRepoURL(t *testing.T) {
+	tests := []struct {
+		input, baseURL, owner, repo string
+	}{
+		{"https://git.example.com/owner/repo", "https://git.example.com", "owner", "repo"},
+		{"https://git.example.com/owner/repo.git/", "https://git.example.com", "owner", "repo"},
+		{"https://example.com/gitea/owner/repo", "https://example.com/gitea", "owner", "repo"},
+		{"http://localhost:3000/owner/repo", "http://localhost:3000", "owner", "repo"},
+	}
+	for _, tt := range tests {
+		baseURL, owner, repo, err := ParseRepoURL(tt.input)
+		if err != nil {
+			t.Errorf("ParseRepoURL(%q) error = %v", tt.input, err)
+			continue
+		}
+		if baseURL != tt.baseURL || owner != tt.owner || repo != tt.repo {
+			t.Errorf("ParseRepoURL(%q) = %q, %q, %q; want %q, %q, %q", tt.input, baseURL, owner, repo, tt.baseURL, tt.owner, tt.repo)
+		}
+	}
+
+	for _, input := range []string{"owner/repo", "https://git.example.com/repo", "ftp://git.example.com/owner/repo"} {
+		if _, _, _, err := ParseRepoURL(input); err == nil {
+			t.Errorf("Expected error for %q", input)
+		}
+	}
+}
+
+func TestGetRelease(t *testing.T) {
+	var authorization string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		authorization = r.Header.Get("Authorization")
+		switch r.URL.Path {
+		case "/api/v1/repos/owner/tool/releases/latest", "/api/v1/repos/owner/tool/releases/tags/v1.0.0":
+			w.Write([]byte(`{"tag_name": "v1.0.0", "body": "Notes", "assets": [
+				{"name": "tool_linux_amd64.tar.gz", "size": 42, "browser_download_url": "https://git.example.com/attachments/1"}
+			]}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	client := &Client{BaseURL: server.URL, Token: "secret"}
+	release, err := client.GetLatestRelease("owner", "tool")
+	if err != nil {
+		t.Fatalf("GetLatestRelease() error = %v", err)
+	}
+	if release.TagName != "v1.0.0" || release.Body != "Notes" || len(release.Assets) != 1 || release.Assets[0].Size != 42 {
+		t.Errorf("Unexpected release: %+v", release)
+	}
+	if authorization != "token secret" {
+		t.Errorf("Expected token authorization, got %q", authorization)
+	}
+
+	if _, err := client.GetRelease("owner", "tool", "v1.0.0"); err != nil {
+		t.Errorf("GetRelease() error = %v", err)
+	}
+	if _, err := client.GetRelease("owner", "tool", "v9.9.9"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Expected ErrNotFound, got %v", err)
+	}
+}
+
+func TestListReleasesPages(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		page := r.URL.Query().Get("page")
+		if page == "1" {
+			w.Write([]byte("["))
+			for i := 0; i < pageSize; i++ {
+				if i > 0 {
+					w.Write([]byte(","))
+				}
+				fmt.Fprintf(w, `{"tag_name": "v1.%d.0"}`, pageSize-i)
+			}
+			w.Write([]byte("]"))
+			return
+		}
+		if page == "2" {
+			w.Write([]byte(`[{"tag_name": "v1.0.0"}]`))
+			return
+		}
+		w.Write([]byte(`[]`))
+	}))
+	defer server.Close()
+
+	client := &Client{BaseURL: server.URL}
+	releases, err := client.ListReleases("owner", "tool")
+	if err != nil {
+		t.Fatalf("ListReleases() error = %v", err)
+	}
+	if len(releases) != pageSize+1 || releases[pageSize].TagName != "v1.0.0" {
+		t.Errorf("Expected %d releases ending with v1.0.0, got %d", pageSize+1, len(releases))
+	}
+}
+
+func TestAuthHeaders(t *testing.T) {
+	if headers := (&Client{}).AuthHeaders(); headers != nil {
+		t.Errorf("Expected no headers without a token, got %v", headers)
+	}
+	if headers := (&Client{Token: "secret"}).AuthHeaders(); headers["Authorization"] != "token secret" {
+		t.Errorf("Expected token header, got %v", headers)
+	}
+}
@@ -0,0 +1,118 @@
+package github
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+// WorkflowRun represents a GitHub Actions workflow run
+type WorkflowRun struct {
+	ID         int64     `json:"id"`
+	Name       string    `json:"name"`
+	HeadBranch string    `json:"head_branch"`
+	HeadSHA    string    `json:"head_sha"`
+	Conclusion string    `json:"conclusion"`
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+// Artifact represents a build artifact uploaded by a workflow run
+type Artifact struct {
+	ID                 int64  `json:"id"`
+	Name               string `json:"name"`
+	SizeInBytes        int64  `json:"size_in_bytes"`
+	ArchiveDownloadURL string `json:"archive_download_url"`
+	Expired            bool   `json:"expired"`
+}
+
+// GetWorkflowRun gets a workflow run by ID
+func (c *Client) GetWorkflowRun(owner, repo string, runID int64) (*WorkflowRun, error) {
+	var run WorkflowRun
+	endpoint := fmt.Sprintf("%s/repos/%s/%s/actions/runs/%d", c.BaseURL, owner, repo, runID)
+	if err := c.getJSON(endpoint, &run); err != nil {
+		return nil, fmt.Errorf("failed to fetch workflow run %d: %w", runID, err)
+	}
+	return &run, nil
+}
+
+// LatestSuccessfulRun finds the newest successful workflow run on a branch. An empty
+// branch matches every branch; workflow (a file name such as build.yml or an ID)
+// limits the search to one workflow.
+func (c *Client) LatestSuccessfulRun(owner, repo, branch, workflow string) (*WorkflowRun, error) {
+	endpoint := fmt.Sprintf("%s/repos/%s/%s/actions/runs", c.BaseURL, owner, repo)
+	if workflow != "" {
+		endpoint = fmt.Sprintf("%s/repos/%s/%s/actions/workflows/%s/runs", c.BaseURL, owner, repo, url.PathEscape(workflow))
+	}
+	query := url.Values{"status": {"success"}, "per_page": {"1"}}
+	if branch != "" {
+		query.Set("branch", branch)
+	}
+
+	var page struct {
+		WorkflowRuns []WorkflowRun `json:"workflow_runs"`
+	}
+	if err := c.getJSON(endpoint+"?"+query.Encode(), &page); err != nil {
+		return nil, fmt.Errorf("failed to fetch workflow runs: %w", err)
+	}
+	if len(page.WorkflowRuns) == 0 {
+		if branch != "" {
+			return nil, fmt.Errorf("no successful workflow run found on branch %s", branch)
+		}
+		return nil, fmt.Errorf("no successful workflow run found")
+	}
+	return &page.WorkflowRuns[0], nil
+}
+
+// ListArtifacts lists the artifacts of a workflow run
+func (c *Client) ListArtifacts(owner, repo string, runID int64) ([]Artifact, error) {
+	var page struct {
+		Artifacts []Artifact `json:"artifacts"`
+	}
+	endpoint := fmt.Sprintf("%s/repos/%s/%s/actions/runs/%d/artifacts?per_page=%d", c.BaseURL, owner, repo, runID, pageSize)
+	if err := c.getJSON(endpoint, &page); err != nil {
+		return nil, fmt.Errorf("failed to fetch artifacts: %w", err)
+	}
+	return page.Artifacts, nil
+}
+
+// RunRelease describes the unexpired artifacts of a workflow run as a release. Artifacts
+// are served as zip archives through the API, which requires a token.
+func RunRelease(run *WorkflowRun, artifacts []Artifact) *Release {
+	release := &Release{
+		TagName:     fmt.Sprintf("run-%d", run.ID),
+		Name:        run.Name,
+		Prerelease:  true,
+		PublishedAt: run.CreatedAt,
+	}
+	for _, artifact := range artifacts {
+		if artifact.Expired {
+			continue
+		}
+		release.Assets = append(release.Assets, Asset{
+			Name:               artifact.Name + ".zip",
+			URL:                artifact.ArchiveDownloadURL,
+			BrowserDownloadURL: artifact.ArchiveDownloadURL,
+			Size:               artifact.SizeInBytes,
+		})
+	}
+	return release
+}
+
+// getJSON fetches an API document and decodes it into v
+func (c *Client) getJSON(url string, v interface{}) error {
+	resp, err := c.get(url)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return apiError(resp.StatusCode)
+	}
+	if err := json.NewDecoder(resp.Body).Decode(v); err != nil {
+		return fmt.Errorf("failed to decode response: %w", err)
+	}
+	return nil
+}
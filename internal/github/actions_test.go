@@ -0,0 +1,74 @@
+package github
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestLatestSuccessfulRun(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/repos/owner/repo/actions/workflows/build.yml/runs" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		query := r.URL.Query()
+		if query.Get("status") != "success" || query.Get("branch") != "main" {
+			t.Errorf("Unexpected query: %s", r.URL.RawQuery)
+		}
+		w.Write([]byte(`{"workflow_runs": [{"id": 42, "name": "build", "head_branch": "main"}]}`))
+	}))
+	defer server.Close()
+
+	client := &Client{BaseURL: server.URL}
+	run, err := client.LatestSuccessfulRun("owner", "repo", "main", "build.yml")
+	if err != nil {
+		t.Fatalf("LatestSuccessfulRun() error = %v", err)
+	}
+	if run.ID != 42 || run.HeadBranch != "main" {
+		t.Errorf("Expected run 42 on main, got %+v", run)
+	}
+
+	if _, err := client.LatestSuccessfulRun("owner", "repo", "main", ""); err == nil {
+		t.Error("Expected error for unknown endpoint")
+	}
+}
+
+func TestRunRelease(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/repos/owner/repo/actions/runs/7":
+			w.Write([]byte(`{"id": 7, "name": "nightly"}`))
+		case "/repos/owner/repo/actions/runs/7/artifacts":
+			w.Write([]byte(`{"artifacts": [
+				{"id": 1, "name": "tool-linux-amd64", "size_in_bytes": 100, "archive_download_url": "https://api.github.com/zip/1"},
+				{"id": 2, "name": "tool-old", "expired": true}
+			]}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	client := &Client{BaseURL: server.URL}
+	run, err := client.GetWorkflowRun("owner", "repo", 7)
+	if err != nil {
+		t.Fatalf("GetWorkflowRun() error = %v", err)
+	}
+	artifacts, err := client.ListArtifacts("owner", "repo", run.ID)
+	if err != nil {
+		t.Fatalf("ListArtifacts() error = %v", err)
+	}
+
+	release := RunRelease(run, artifacts)
+	if release.TagName != "run-7" || !release.Prerelease {
+		t.Errorf("Expected prerelease run-7, got %s", release.TagName)
+	}
+	if len(release.Assets) != 1 {
+		t.Fatalf("Expected expired artifacts to be skipped, got %d assets", len(release.Assets))
+	}
+	asset := release.Assets[0]
+	if asset.Name != "tool-linux-amd64.zip" || asset.URL != "https://api.github.com/zip/1" || asset.Size != 100 {
+		t.Errorf("Unexpected asset: %+v", asset)
+	}
+}
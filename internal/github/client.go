@@ -0,0 +1,179 @@
+package github
+
+import (
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"time"
+)
+
+// DefaultTimeout bounds a whole API request, including reading the response
+const DefaultTimeout = 30 * time.Second
+
+// defaultHTTPClient serves clients created without NewClient
+var defaultHTTPClient = newHTTPClient()
+
+// ErrNotFound matches API errors for missing repositories, releases and tags
+var ErrNotFound = errors.New("not found")
+
+// ErrUnauthorized matches API errors caused by an invalid or expired token
+var ErrUnauthorized = errors.New("bad credentials")
+
+// apiError describes an unexpected API response status
+func apiError(status int) error {
+	switch status {
+	case http.StatusNotFound:
+		return fmt.Errorf("GitHub API error: %d (%w)", status, ErrNotFound)
+	case http.StatusUnauthorized:
+		return fmt.Errorf("GitHub API error: %d (%w)", status, ErrUnauthorized)
+	}
+	return fmt.Errorf("GitHub API error: %d", status)
+}
+
+// DefaultBaseURL is the API endpoint of clients created without WithBaseURL
+var DefaultBaseURL = "https://api.github.com"
+
+// DefaultReleaseBaseURL, if set, is the endpoint of release, tag and asset lookups of
+// clients created without WithBaseURL, e.g. a team cache server that only serves those
+var DefaultReleaseBaseURL string
+
+// DefaultToken, if set, is the token of clients created without WithToken, e.g. from
+// --token. It takes precedence over the environment.
+var DefaultToken string
+
+// TokenHelper, if set, supplies the token of clients created without a token from
+// DefaultToken, the environment or WithToken, e.g. from a credential helper. It
+// receives the host of the client.
+var TokenHelper func(host string) string
+
+// ConfiguredToken returns the token clients use by default: DefaultToken, GITHUB_TOKEN
+// or GH_TOKEN (the variable of the gh CLI), whichever is set first
+func ConfiguredToken() string {
+	for _, token := range []string{DefaultToken, os.Getenv("GITHUB_TOKEN"), os.Getenv("GH_TOKEN")} {
+		if token != "" {
+			return token
+		}
+	}
+	return ""
+}
+
+// Host returns the host the client's token belongs to: github.com for the public API,
+// otherwise the host of the API URL, e.g. a GitHub Enterprise server
+func (c *Client) Host() string {
+	u, err := url.Parse(c.BaseURL)
+	if err != nil {
+		return ""
+	}
+	if u.Hostname() == "api.github.com" {
+		return "github.com"
+	}
+	return u.Hostname()
+}
+
+// Option configures a Client created by NewClient
+type Option func(*Client)
+
+// WithBaseURL points the client at another API endpoint, e.g. GitHub Enterprise or a test server
+func WithBaseURL(baseURL string) Option {
+	return func(c *Client) {
+		c.BaseURL = baseURL
+		c.ReleaseBaseURL = ""
+	}
+}
+
+// releaseBaseURL returns the endpoint of release, tag and asset lookups
+func (c *Client) releaseBaseURL() string {
+	if c.ReleaseBaseURL != "" {
+		return c.ReleaseBaseURL
+	}
+	return c.BaseURL
+}
+
+// WithToken sets the API token, overriding GITHUB_TOKEN
+func WithToken(token string) Option {
+	return func(c *Client) {
+		c.Token = token
+	}
+}
+
+// WithHTTPClient replaces the HTTP client used for API requests
+func WithHTTPClient(client *http.Client) Option {
+	return func(c *Client) {
+		c.HTTPClient = client
+	}
+}
+
+// WithTimeout sets the time limit of each API request; zero means no limit
+func WithTimeout(timeout time.Duration) Option {
+	return func(c *Client) {
+		if c.HTTPClient == nil {
+			c.HTTPClient = newHTTPClient()
+		}
+		c.HTTPClient.Timeout = timeout
+	}
+}
+
+// WithCacheDir keeps API responses in dir and revalidates them with their ETag; an
+// empty dir disables caching
+func WithCacheDir(dir string) Option {
+	return func(c *Client) {
+		c.CacheDir = dir
+	}
+}
+
+// WithProxy sends API requests through a proxy instead of the one from the environment
+// (HTTPS_PROXY, NO_PROXY). It has no effect on custom non-*http.Transport transports.
+func WithProxy(proxy *url.URL) Option {
+	return func(c *Client) {
+		if t := c.transport(); t != nil {
+			t.Proxy = http.ProxyURL(proxy)
+		}
+	}
+}
+
+// WithTLSConfig sets the TLS settings of API requests, e.g. a custom root CA pool.
+// It has no effect on custom non-*http.Transport transports.
+func WithTLSConfig(config *tls.Config) Option {
+	return func(c *Client) {
+		if t := c.transport(); t != nil {
+			t.TLSClientConfig = config
+		}
+	}
+}
+
+// newHTTPClient creates an HTTP client with its own transport and the default timeout
+func newHTTPClient() *http.Client {
+	return &http.Client{
+		Transport: http.DefaultTransport.(*http.Transport).Clone(),
+		Timeout:   DefaultTimeout,
+	}
+}
+
+// httpClient returns the client for API requests
+func (c *Client) httpClient() *http.Client {
+	if c.HTTPClient != nil {
+		return c.HTTPClient
+	}
+	return defaultHTTPClient
+}
+
+// transport returns the transport of the client's HTTP client for configuration,
+// installing a copy of the default transport if it has none
+func (c *Client) transport() *http.Transport {
+	if c.HTTPClient == nil {
+		c.HTTPClient = newHTTPClient()
+	}
+	switch t := c.HTTPClient.Transport.(type) {
+	case *http.Transport:
+		return t
+	case nil:
+		transport := http.DefaultTransport.(*http.Transport).Clone()
+		c.HTTPClient.Transport = transport
+		return transport
+	default:
+		return nil
+	}
+}
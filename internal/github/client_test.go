@@ -0,0 +1,186 @@
+package github
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+)
+
+const latestReleaseJSON = `{"tag_name": "v1.0.0"}`
+
+func TestNewClientOptions(t *testing.T) {
+	t.Setenv("GITHUB_TOKEN", "from-env")
+
+	c := NewClient()
+	if c.Token != "from-env" || c.HTTPClient == nil || c.HTTPClient.Timeout != DefaultTimeout {
+		t.Errorf("Expected defaults with token from environment, got %+v", c)
+	}
+
+	custom := &http.Client{}
+	c = NewClient(WithBaseURL("https://ghe.example.com/api/v3"), WithToken("explicit"), WithHTTPClient(custom), WithTimeout(5*time.Second))
+	if c.BaseURL != "https://ghe.example.com/api/v3" {
+		t.Errorf("Expected custom base URL, got %s", c.BaseURL)
+	}
+	if c.Token != "explicit" {
+		t.Errorf("Expected explicit token, got %s", c.Token)
+	}
+	if c.HTTPClient != custom || custom.Timeout != 5*time.Second {
+		t.Errorf("Expected injected client with 5s timeout, got %v", c.HTTPClient.Timeout)
+	}
+}
+
+func TestConfiguredToken(t *testing.T) {
+	original := DefaultToken
+	defer func() { DefaultToken = original }()
+	DefaultToken = ""
+	t.Setenv("GITHUB_TOKEN", "")
+	t.Setenv("GH_TOKEN", "from-gh")
+
+	if token := ConfiguredToken(); token != "from-gh" {
+		t.Errorf("Expected GH_TOKEN without GITHUB_TOKEN, got %q", token)
+	}
+	t.Setenv("GITHUB_TOKEN", "from-github")
+	if token := ConfiguredToken(); token != "from-github" {
+		t.Errorf("Expected GITHUB_TOKEN to win over GH_TOKEN, got %q", token)
+	}
+	DefaultToken = "from-flag"
+	if c := NewClient(); c.Token != "from-flag" {
+		t.Errorf("Expected DefaultToken to win over the environment, got %q", c.Token)
+	}
+}
+
+func TestNewClientTokenHelper(t *testing.T) {
+	original := TokenHelper
+	defer func() { TokenHelper = original }()
+	t.Setenv("GITHUB_TOKEN", "")
+	t.Setenv("GH_TOKEN", "")
+
+	var hosts []string
+	TokenHelper = func(host string) string {
+		hosts = append(hosts, host)
+		return "from-helper-" + host
+	}
+	if c := NewClient(); c.Token != "from-helper-github.com" {
+		t.Errorf("Expected token from helper, got %q", c.Token)
+	}
+	if c := NewClient(WithBaseURL("https://ghe.example.com/api/v3")); c.Token != "from-helper-ghe.example.com" {
+		t.Errorf("Expected token for the enterprise host, got %q", c.Token)
+	}
+	if c := NewClient(WithToken("explicit")); c.Token != "explicit" {
+		t.Errorf("Expected explicit token to win, got %q", c.Token)
+	}
+	if len(hosts) != 2 {
+		t.Errorf("Expected the helper to run only without a token, got %v", hosts)
+	}
+}
+
+func TestClientUsesInjectedHTTPClient(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(latestReleaseJSON))
+	}))
+	defer server.Close()
+
+	requests := 0
+	transport := roundTripFunc(func(r *http.Request) (*http.Response, error) {
+		requests++
+		return http.DefaultTransport.RoundTrip(r)
+	})
+
+	c := NewClient(WithBaseURL(server.URL), WithToken(""), WithHTTPClient(&http.Client{Transport: transport}))
+	if _, err := c.GetLatestRelease("owner", "repo"); err != nil {
+		t.Fatalf("GetLatestRelease() error = %v", err)
+	}
+	if requests != 1 {
+		t.Errorf("Expected 1 request through the injected client, got %d", requests)
+	}
+}
+
+func TestClientTimeout(t *testing.T) {
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-release
+	}))
+	defer server.Close()
+	defer close(release)
+
+	c := NewClient(WithBaseURL(server.URL), WithToken(""), WithTimeout(50*time.Millisecond))
+	if _, err := c.GetLatestRelease("owner", "repo"); err == nil {
+		t.Error("Expected timeout error")
+	}
+}
+
+func TestClientProxy(t *testing.T) {
+	var proxied string
+	proxy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		proxied = r.URL.String()
+		w.Write([]byte(latestReleaseJSON))
+	}))
+	defer proxy.Close()
+
+	proxyURL, _ := url.Parse(proxy.URL)
+	c := NewClient(WithBaseURL("http://api.github.invalid"), WithToken(""), WithProxy(proxyURL))
+	if _, err := c.GetLatestRelease("owner", "repo"); err != nil {
+		t.Fatalf("GetLatestRelease() error = %v", err)
+	}
+	if proxied != "http://api.github.invalid/repos/owner/repo/releases/latest" {
+		t.Errorf("Expected request through proxy, got %q", proxied)
+	}
+}
+
+func TestClientTLSConfig(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(latestReleaseJSON))
+	}))
+	defer server.Close()
+
+	// The test server's certificate is not trusted by default
+	c := NewClient(WithBaseURL(server.URL), WithToken(""))
+	if _, err := c.GetLatestRelease("owner", "repo"); err == nil {
+		t.Error("Expected certificate error without custom roots")
+	}
+
+	roots := x509.NewCertPool()
+	roots.AddCert(server.Certificate())
+	c = NewClient(WithBaseURL(server.URL), WithToken(""), WithTLSConfig(&tls.Config{RootCAs: roots}))
+	if _, err := c.GetLatestRelease("owner", "repo"); err != nil {
+		t.Errorf("GetLatestRelease() error = %v", err)
+	}
+}
+
+func TestReleaseBaseURL(t *testing.T) {
+	var cached, direct int
+	cache := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		cached++
+		w.Write([]byte(latestReleaseJSON))
+	}))
+	defer cache.Close()
+	api := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		direct++
+		w.Write([]byte(`{"items":[]}`))
+	}))
+	defer api.Close()
+
+	c := NewClient(WithBaseURL(api.URL), WithToken(""))
+	c.ReleaseBaseURL = cache.URL
+	c.CacheDir = ""
+	if _, err := c.GetLatestRelease("owner", "repo"); err != nil {
+		t.Fatalf("GetLatestRelease() error = %v", err)
+	}
+	if _, err := c.SearchRepositories("tool", 1); err != nil {
+		t.Fatalf("SearchRepositories() error = %v", err)
+	}
+	if cached != 1 || direct != 1 {
+		t.Errorf("Expected the release lookup at ReleaseBaseURL and the search at BaseURL, got %d and %d", cached, direct)
+	}
+}
+
+// roundTripFunc adapts a function to http.RoundTripper
+type roundTripFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripFunc) RoundTrip(r *http.Request) (*http.Response, error) {
+	return f(r)
+}
@@ -0,0 +1,125 @@
+package github
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// DefaultCacheDir, if set, is where clients created by NewClient keep API responses
+// to revalidate them with their ETag, e.g. "api" in the installer cache directory
+var DefaultCacheDir string
+
+// maxCachedBody bounds the size of a cached API response
+const maxCachedBody = 8 << 20
+
+// cachedResponse is an API response stored for conditional requests
+type cachedResponse struct {
+	URL  string `json:"url"`
+	ETag string `json:"etag"`
+	Link string `json:"link,omitempty"` // Pagination
+	Body []byte `json:"body"`
+}
+
+// cachePath returns the cache file of a URL. Responses depend on the credentials, so
+// the token is part of the key.
+func (c *Client) cachePath(url string) string {
+	sum := sha256.Sum256([]byte(c.Token + "\n" + url))
+	return filepath.Join(c.CacheDir, hex.EncodeToString(sum[:])+".json")
+}
+
+// cachedFor returns the cached response of a URL, or nil
+func (c *Client) cachedFor(url string) *cachedResponse {
+	if c.CacheDir == "" {
+		return nil
+	}
+	data, err := os.ReadFile(c.cachePath(url))
+	if err != nil {
+		return nil
+	}
+	var cached cachedResponse
+	if json.Unmarshal(data, &cached) != nil || cached.URL != url || cached.ETag == "" {
+		return nil
+	}
+	return &cached
+}
+
+// revalidated turns the response to a conditional request into the response to use:
+// the cached body for 304 Not Modified, or the new body, stored if it has an ETag.
+// Failures to cache are ignored.
+func (c *Client) revalidated(url string, cached *cachedResponse, resp *http.Response) (*http.Response, error) {
+	if c.CacheDir == "" {
+		return resp, nil
+	}
+	if resp.StatusCode == http.StatusNotModified && cached != nil {
+		resp.Body.Close()
+		// Keep recently used entries when the cache is pruned by age
+		now := time.Now()
+		os.Chtimes(c.cachePath(url), now, now)
+		return cachedHTTPResponse(resp, cached), nil
+	}
+	etag := resp.Header.Get("ETag")
+	if resp.StatusCode != http.StatusOK || etag == "" {
+		return resp, nil
+	}
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxCachedBody+1))
+	resp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+	if len(body) <= maxCachedBody {
+		c.storeResponse(&cachedResponse{URL: url, ETag: etag, Link: resp.Header.Get("Link"), Body: body})
+	}
+	return resp, nil
+}
+
+// storeResponse writes a response to the cache, replacing the old entry atomically
+func (c *Client) storeResponse(cached *cachedResponse) {
+	data, err := json.Marshal(cached)
+	if err != nil || os.MkdirAll(c.CacheDir, 0755) != nil {
+		return
+	}
+	path := c.cachePath(cached.URL)
+	tmp, err := os.CreateTemp(c.CacheDir, filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return
+	}
+	_, err = tmp.Write(data)
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil {
+		err = os.Rename(tmp.Name(), path)
+	}
+	if err != nil {
+		os.Remove(tmp.Name())
+	}
+}
+
+// cachedHTTPResponse serves a cached body as the 200 response it was
+func cachedHTTPResponse(notModified *http.Response, cached *cachedResponse) *http.Response {
+	header := notModified.Header.Clone()
+	header.Set("ETag", cached.ETag)
+	if cached.Link != "" {
+		header.Set("Link", cached.Link)
+	}
+	return &http.Response{
+		Status:        "200 OK",
+		StatusCode:    http.StatusOK,
+		Proto:         notModified.Proto,
+		ProtoMajor:    notModified.ProtoMajor,
+		ProtoMinor:    notModified.ProtoMinor,
+		Header:        header,
+		Body:          io.NopCloser(bytes.NewReader(cached.Body)),
+		ContentLength: int64(len(cached.Body)),
+		Request:       notModified.Request,
+	}
+}
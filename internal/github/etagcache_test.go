@@ -0,0 +1,98 @@
+package github
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+)
+
+func TestETagCache(t *testing.T) {
+	var requests, notModified int
+	var authorization string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		authorization = r.Header.Get("Authorization")
+		etag := `"v1-` + authorization + `"`
+		if r.Header.Get("If-None-Match") == etag {
+			notModified++
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		w.Header().Set("ETag", etag)
+		switch r.URL.Path {
+		case "/repos/owner/repo/releases/latest":
+			w.Write([]byte(`{"tag_name": "v1.0.0"}`))
+		case "/repos/owner/repo/tags":
+			if r.URL.Query().Get("page") == "" {
+				w.Header().Set("Link", `<`+"http://"+r.Host+`/repos/owner/repo/tags?page=2>; rel="next"`)
+				w.Write([]byte(`[{"name": "v1.1.0"}]`))
+				return
+			}
+			w.Write([]byte(`[{"name": "v1.0.0"}]`))
+		}
+	}))
+	defer server.Close()
+
+	dir := t.TempDir()
+	client := NewClient(WithBaseURL(server.URL), WithToken(""), WithCacheDir(dir))
+	for i := 0; i < 2; i++ {
+		release, err := client.GetLatestRelease("owner", "repo")
+		if err != nil {
+			t.Fatalf("GetLatestRelease() error = %v", err)
+		}
+		if release.TagName != "v1.0.0" {
+			t.Errorf("Expected v1.0.0 from request %d, got %s", i+1, release.TagName)
+		}
+	}
+	if requests != 2 || notModified != 1 {
+		t.Errorf("Expected the second request to be revalidated, got %d requests, %d not modified", requests, notModified)
+	}
+
+	// Pagination links are kept with cached pages
+	for i := 0; i < 2; i++ {
+		tags, err := client.ListTags("owner", "repo")
+		if err != nil {
+			t.Fatalf("ListTags() error = %v", err)
+		}
+		if len(tags) != 2 {
+			t.Errorf("Expected 2 tags over 2 pages from listing %d, got %v", i+1, tags)
+		}
+	}
+	if notModified != 3 {
+		t.Errorf("Expected both pages to be revalidated, got %d not modified", notModified)
+	}
+
+	// Responses are cached per token
+	other := NewClient(WithBaseURL(server.URL), WithToken("secret"), WithCacheDir(dir))
+	notModified = 0
+	if _, err := other.GetLatestRelease("owner", "repo"); err != nil {
+		t.Fatalf("GetLatestRelease() error = %v", err)
+	}
+	if notModified != 0 || authorization != "Bearer secret" {
+		t.Errorf("Expected an unconditional request with the other token, got %d not modified", notModified)
+	}
+
+	entries, err := os.ReadDir(dir)
+	if err != nil || len(entries) != 4 {
+		t.Errorf("Expected 4 cache entries, got %d (%v)", len(entries), err)
+	}
+}
+
+func TestETagCacheDisabled(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("If-None-Match") != "" {
+			t.Error("Expected no conditional request without a cache directory")
+		}
+		w.Header().Set("ETag", `"v1"`)
+		w.Write([]byte(`{"tag_name": "v1.0.0"}`))
+	}))
+	defer server.Close()
+
+	client := &Client{BaseURL: server.URL}
+	for i := 0; i < 2; i++ {
+		if _, err := client.GetLatestRelease("owner", "repo"); err != nil {
+			t.Fatalf("GetLatestRelease() error = %v", err)
+		}
+	}
+}
@@ -0,0 +1,79 @@
+package github
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// pageSize is the largest page the GitHub API serves
+const pageSize = 100
+
+// maxPages bounds how many pages a listing follows
+const maxPages = 10
+
+// getPages fetches a paginated list, following Link rel="next" headers
+func getPages[T any](c *Client, url string) ([]T, error) {
+	var items []T
+
+	for page := 0; url != "" && page < maxPages; page++ {
+		resp, err := c.get(url)
+		if err != nil {
+			return nil, err
+		}
+
+		if resp.StatusCode != http.StatusOK {
+			resp.Body.Close()
+			return nil, apiError(resp.StatusCode)
+		}
+
+		var pageItems []T
+		err = json.NewDecoder(resp.Body).Decode(&pageItems)
+		resp.Body.Close()
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode response: %w", err)
+		}
+
+		items = append(items, pageItems...)
+		url = nextPageURL(resp.Header.Get("Link"))
+	}
+
+	return items, nil
+}
+
+// nextPageURL extracts the rel="next" target from a Link header
+func nextPageURL(link string) string {
+	for _, part := range strings.Split(link, ",") {
+		sections := strings.Split(part, ";")
+		if len(sections) < 2 {
+			continue
+		}
+		for _, param := range sections[1:] {
+			if strings.TrimSpace(param) == `rel="next"` {
+				return strings.Trim(strings.TrimSpace(sections[0]), "<>")
+			}
+		}
+	}
+	return ""
+}
+
+// ListAssets lists every asset of a release, beyond the first page embedded in the release
+func (c *Client) ListAssets(owner, repo string, releaseID int64) ([]Asset, error) {
+	url := fmt.Sprintf("%s/repos/%s/%s/releases/%d/assets?per_page=%d", c.releaseBaseURL(), owner, repo, releaseID, pageSize)
+
+	assets, err := getPages[Asset](c, url)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch release assets: %w", err)
+	}
+	return assets, nil
+}
+
+// ReleaseAssets returns all assets of a release. The release payload embeds at most
+// one page of assets, so the asset endpoint is only queried when that page is full.
+func (c *Client) ReleaseAssets(owner, repo string, release *Release) ([]Asset, error) {
+	if len(release.Assets) < pageSize || release.ID == 0 {
+		return release.Assets, nil
+	}
+	return c.ListAssets(owner, repo, release.ID)
+}
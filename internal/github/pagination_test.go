@@ -0,0 +1,115 @@
+package github
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+)
+
+func TestNextPageURL(t *testing.T) {
+	tests := []struct {
+		name string
+		link string
+		want string
+	}{
+		{"empty", "", ""},
+		{
+			"next and last",
+			`<https://api.github.com/repositories/1/releases?page=2>; rel="next", <https://api.github.com/repositories/1/releases?page=5>; rel="last"`,
+			"https://api.github.com/repositories/1/releases?page=2",
+		},
+		{
+			"last page",
+			`<https://api.github.com/repositories/1/releases?page=4>; rel="prev", <https://api.github.com/repositories/1/releases?page=1>; rel="first"`,
+			"",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := nextPageURL(tt.link); got != tt.want {
+				t.Errorf("Expected %q, got %q", tt.want, got)
+			}
+		})
+	}
+}
+
+// pagedServer serves total numbered items in pages of pageSize with Link headers
+func pagedServer(path string, total int, item func(i int) any) *httptest.Server {
+	var server *httptest.Server
+	server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != path {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+
+		page, _ := strconv.Atoi(r.URL.Query().Get("page"))
+		if page == 0 {
+			page = 1
+		}
+		start := (page - 1) * pageSize
+		end := min(start+pageSize, total)
+
+		var items []any
+		for i := start; i < end; i++ {
+			items = append(items, item(i))
+		}
+		if end < total {
+			w.Header().Set("Link", fmt.Sprintf(`<%s%s?per_page=%d&page=%d>; rel="next"`, server.URL, path, pageSize, page+1))
+		}
+		json.NewEncoder(w).Encode(items)
+	}))
+	return server
+}
+
+func TestListReleasesPagination(t *testing.T) {
+	server := pagedServer("/repos/owner/repo/releases", 250, func(i int) any {
+		return Release{TagName: fmt.Sprintf("v%d.0.0", 250-i)}
+	})
+	defer server.Close()
+
+	client := &Client{BaseURL: server.URL}
+	releases, err := client.ListReleases("owner", "repo")
+	if err != nil {
+		t.Fatalf("ListReleases() error = %v", err)
+	}
+
+	if len(releases) != 250 {
+		t.Fatalf("Expected 250 releases, got %d", len(releases))
+	}
+	if releases[249].TagName != "v1.0.0" {
+		t.Errorf("Expected last release v1.0.0, got %s", releases[249].TagName)
+	}
+}
+
+func TestReleaseAssets(t *testing.T) {
+	server := pagedServer("/repos/owner/repo/releases/42/assets", 150, func(i int) any {
+		return Asset{Name: fmt.Sprintf("tool-%03d.tar.gz", i)}
+	})
+	defer server.Close()
+
+	client := &Client{BaseURL: server.URL}
+
+	// A release with fewer assets than a page is complete as is
+	small := &Release{ID: 42, Assets: []Asset{{Name: "tool.tar.gz"}}}
+	assets, err := client.ReleaseAssets("owner", "repo", small)
+	if err != nil {
+		t.Fatalf("ReleaseAssets() error = %v", err)
+	}
+	if len(assets) != 1 {
+		t.Errorf("Expected 1 asset, got %d", len(assets))
+	}
+
+	// A full page may be truncated, so all pages are fetched
+	full := &Release{ID: 42, Assets: make([]Asset, pageSize)}
+	assets, err = client.ReleaseAssets("owner", "repo", full)
+	if err != nil {
+		t.Fatalf("ReleaseAssets() error = %v", err)
+	}
+	if len(assets) != 150 {
+		t.Errorf("Expected 150 assets, got %d", len(assets))
+	}
+}
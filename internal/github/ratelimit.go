@@ -0,0 +1,174 @@
+package github
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/pyhub-kr/pyhub-installer/internal/retry"
+	"github.com/pyhub-kr/pyhub-installer/internal/useragent"
+)
+
+// DefaultRateLimitWait is the longest the client waits for a rate limit to reset
+const DefaultRateLimitWait = 60 * time.Second
+
+// LongestRateLimitWait is the longest clients wait with WaitForRateLimit; primary
+// rate limits reset within an hour
+const LongestRateLimitWait = 61 * time.Minute
+
+// WaitForRateLimit makes clients without their own RateLimitWait wait up to
+// LongestRateLimitWait for a rate limit to reset, e.g. for --wait-for-rate-limit in
+// unattended runs
+var WaitForRateLimit bool
+
+// maxRateLimitRetries bounds how often one request is retried after waiting
+const maxRateLimitRetries = 3
+
+// ErrRateLimited matches any RateLimitError
+var ErrRateLimited = errors.New("rate limited")
+
+// RateLimitError is returned when the API rate limit is exhausted and waiting is not worthwhile
+type RateLimitError struct {
+	StatusCode int
+	Reset      time.Time // Zero if the API did not say when the limit resets
+}
+
+func (e *RateLimitError) Error() string {
+	msg := fmt.Sprintf("GitHub API rate limit exceeded (HTTP %d)", e.StatusCode)
+	if !e.Reset.IsZero() {
+		msg += fmt.Sprintf("; resets at %s", e.Reset.Local().Format("15:04:05"))
+	}
+	return msg + "; set GITHUB_TOKEN to a personal access token for a higher limit, or pass --wait-for-rate-limit to wait for the reset"
+}
+
+// Unwrap lets callers match the error with errors.Is(err, ErrRateLimited)
+func (e *RateLimitError) Unwrap() error {
+	return ErrRateLimited
+}
+
+// rateLimitWait reports whether a response was rejected by the rate limiter and
+// how long to wait before retrying, based on Retry-After or X-RateLimit-Reset
+func rateLimitWait(resp *http.Response, now time.Time) (wait time.Duration, reset time.Time, limited bool) {
+	if resp.StatusCode != http.StatusForbidden && resp.StatusCode != http.StatusTooManyRequests {
+		return 0, time.Time{}, false
+	}
+
+	if retryAfter := resp.Header.Get("Retry-After"); retryAfter != "" {
+		if seconds, err := strconv.Atoi(retryAfter); err == nil {
+			wait = time.Duration(seconds) * time.Second
+			return wait, now.Add(wait), true
+		}
+		if at, err := http.ParseTime(retryAfter); err == nil {
+			return max(at.Sub(now), 0), at, true
+		}
+	}
+
+	// A 403 is only a rate limit when the remaining quota is exhausted
+	if resp.Header.Get("X-RateLimit-Remaining") != "0" {
+		return 0, time.Time{}, resp.StatusCode == http.StatusTooManyRequests
+	}
+
+	if epoch, err := strconv.ParseInt(resp.Header.Get("X-RateLimit-Reset"), 10, 64); err == nil {
+		reset = time.Unix(epoch, 0)
+		// Add a second of slack so the retry lands after the reset
+		return max(reset.Sub(now)+time.Second, 0), reset, true
+	}
+	return 0, time.Time{}, true
+}
+
+// RateLimit is the core API quota of the client's credentials
+type RateLimit struct {
+	Limit     int   `json:"limit"`
+	Remaining int   `json:"remaining"`
+	Reset     int64 `json:"reset"` // Unix time at which the quota resets
+}
+
+// GetRateLimit fetches the API quota. The request does not count against it, which
+// makes it a cheap way to check that a token is accepted.
+func (c *Client) GetRateLimit() (*RateLimit, error) {
+	var status struct {
+		Resources struct {
+			Core RateLimit `json:"core"`
+		} `json:"resources"`
+	}
+	if err := c.getJSON(c.BaseURL+"/rate_limit", &status); err != nil {
+		return nil, fmt.Errorf("failed to fetch rate limit: %w", err)
+	}
+	return &status.Resources.Core, nil
+}
+
+// get performs a GET request, waiting out short rate limits with a countdown. With a
+// cache directory, cached responses are revalidated with their ETag.
+func (c *Client) get(url string) (*http.Response, error) {
+	cached := c.cachedFor(url)
+	// Rate limits are waited out below, with a countdown
+	policy := retry.Current().Without(http.StatusTooManyRequests)
+	for attempt := 0; ; attempt++ {
+		resp, err := policy.Do(context.Background(), func() (*http.Response, error) {
+			req, err := http.NewRequest("GET", url, nil)
+			if err != nil {
+				return nil, err
+			}
+			useragent.Apply(req)
+			if c.Token != "" {
+				req.Header.Set("Authorization", "Bearer "+c.Token)
+			}
+			if cached != nil {
+				req.Header.Set("If-None-Match", cached.ETag)
+			}
+			return c.httpClient().Do(req)
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		wait, reset, limited := rateLimitWait(resp, c.now())
+		if !limited {
+			return c.revalidated(url, cached, resp)
+		}
+		resp.Body.Close()
+
+		maxWait := c.RateLimitWait
+		if maxWait == 0 {
+			maxWait = DefaultRateLimitWait
+			if WaitForRateLimit {
+				maxWait = LongestRateLimitWait
+			}
+		}
+		if wait == 0 || wait > maxWait || attempt >= maxRateLimitRetries {
+			return nil, &RateLimitError{StatusCode: resp.StatusCode, Reset: reset}
+		}
+
+		c.countdown(wait)
+	}
+}
+
+// countdown sleeps for d while showing the remaining time on stderr
+func (c *Client) countdown(d time.Duration) {
+	for remaining := d.Round(time.Second); remaining > 0; remaining -= time.Second {
+		fmt.Fprintf(os.Stderr, "\rGitHub API rate limit reached; retrying in %s...   ", remaining)
+		c.sleep(time.Second)
+	}
+	fmt.Fprintf(os.Stderr, "\r%60s\r", "")
+}
+
+// now returns the current time, overridable in tests
+func (c *Client) now() time.Time {
+	if c.clock != nil {
+		return c.clock()
+	}
+	return time.Now()
+}
+
+// sleep pauses for d, overridable in tests
+func (c *Client) sleep(d time.Duration) {
+	if c.sleeper != nil {
+		c.sleeper(d)
+		return
+	}
+	time.Sleep(d)
+}
@@ -0,0 +1,172 @@
+package github
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestRateLimitWait(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+
+	tests := []struct {
+		name        string
+		status      int
+		headers     map[string]string
+		wantWait    time.Duration
+		wantLimited bool
+	}{
+		{"ok response", http.StatusOK, nil, 0, false},
+		{"plain forbidden", http.StatusForbidden, map[string]string{"X-RateLimit-Remaining": "10"}, 0, false},
+		{"retry after seconds", http.StatusTooManyRequests, map[string]string{"Retry-After": "30"}, 30 * time.Second, true},
+		{"secondary limit", http.StatusForbidden, map[string]string{"Retry-After": "5"}, 5 * time.Second, true},
+		{"primary limit", http.StatusForbidden, map[string]string{
+			"X-RateLimit-Remaining": "0",
+			"X-RateLimit-Reset":     strconv.FormatInt(now.Add(20*time.Second).Unix(), 10),
+		}, 21 * time.Second, true},
+		{"too many requests without headers", http.StatusTooManyRequests, nil, 0, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp := &http.Response{StatusCode: tt.status, Header: http.Header{}}
+			for k, v := range tt.headers {
+				resp.Header.Set(k, v)
+			}
+
+			wait, _, limited := rateLimitWait(resp, now)
+			if limited != tt.wantLimited {
+				t.Errorf("Expected limited %v, got %v", tt.wantLimited, limited)
+			}
+			if wait != tt.wantWait {
+				t.Errorf("Expected wait %v, got %v", tt.wantWait, wait)
+			}
+		})
+	}
+}
+
+func TestRateLimitRetry(t *testing.T) {
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		if requests == 1 {
+			w.Header().Set("Retry-After", "3")
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		w.Write([]byte(`{"tag_name": "v1.0.0"}`))
+	}))
+	defer server.Close()
+
+	var slept time.Duration
+	client := &Client{BaseURL: server.URL, sleeper: func(d time.Duration) { slept += d }}
+
+	release, err := client.GetLatestRelease("owner", "repo")
+	if err != nil {
+		t.Fatalf("GetLatestRelease() error = %v", err)
+	}
+	if release.TagName != "v1.0.0" {
+		t.Errorf("Expected v1.0.0, got %s", release.TagName)
+	}
+	if requests != 2 {
+		t.Errorf("Expected 2 requests, got %d", requests)
+	}
+	if slept != 3*time.Second {
+		t.Errorf("Expected to wait 3s, waited %v", slept)
+	}
+}
+
+func TestRateLimitTooLong(t *testing.T) {
+	reset := time.Now().Add(time.Hour)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-RateLimit-Remaining", "0")
+		w.Header().Set("X-RateLimit-Reset", strconv.FormatInt(reset.Unix(), 10))
+		w.WriteHeader(http.StatusForbidden)
+	}))
+	defer server.Close()
+
+	client := &Client{BaseURL: server.URL, sleeper: func(time.Duration) {
+		t.Error("Client should not wait for a reset beyond RateLimitWait")
+	}}
+
+	_, err := client.GetRelease("owner", "repo", "v1.0.0")
+	if !errors.Is(err, ErrRateLimited) {
+		t.Fatalf("Expected rate limit error, got %v", err)
+	}
+	if !strings.Contains(err.Error(), "GITHUB_TOKEN") {
+		t.Errorf("Expected error to suggest GITHUB_TOKEN, got %v", err)
+	}
+}
+
+func TestWaitForRateLimit(t *testing.T) {
+	original := WaitForRateLimit
+	defer func() { WaitForRateLimit = original }()
+	WaitForRateLimit = true
+
+	now := time.Now()
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		if requests == 1 {
+			w.Header().Set("X-RateLimit-Remaining", "0")
+			w.Header().Set("X-RateLimit-Reset", strconv.FormatInt(now.Add(30*time.Minute).Unix(), 10))
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+		w.Write([]byte(`{"tag_name": "v1.0.0"}`))
+	}))
+	defer server.Close()
+
+	var slept time.Duration
+	client := &Client{BaseURL: server.URL, clock: func() time.Time { return now }, sleeper: func(d time.Duration) { slept += d }}
+	if _, err := client.GetLatestRelease("owner", "repo"); err != nil {
+		t.Fatalf("GetLatestRelease() error = %v", err)
+	}
+	if slept < 29*time.Minute || slept > 31*time.Minute {
+		t.Errorf("Expected to wait about 30m for the reset, waited %v", slept)
+	}
+}
+
+func TestTokenHeader(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if got := r.Header.Get("Authorization"); got != "Bearer secret" {
+			t.Errorf("Expected bearer token, got %q", got)
+		}
+		w.Write([]byte(`[]`))
+	}))
+	defer server.Close()
+
+	client := &Client{BaseURL: server.URL, Token: "secret"}
+	if _, err := client.ListReleases("owner", "repo"); err != nil {
+		t.Fatalf("ListReleases() error = %v", err)
+	}
+}
+
+func TestGetRateLimit(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer secret" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		w.Write([]byte(`{"resources": {"core": {"limit": 5000, "remaining": 4990, "reset": 1700000000}}}`))
+	}))
+	defer server.Close()
+
+	client := &Client{BaseURL: server.URL, Token: "secret"}
+	limit, err := client.GetRateLimit()
+	if err != nil {
+		t.Fatalf("GetRateLimit() error = %v", err)
+	}
+	if limit.Limit != 5000 || limit.Remaining != 4990 {
+		t.Errorf("Unexpected rate limit: %+v", limit)
+	}
+
+	client.Token = "expired"
+	if _, err := client.GetRateLimit(); !errors.Is(err, ErrUnauthorized) {
+		t.Errorf("Expected ErrUnauthorized, got %v", err)
+	}
+}
@@ -1,52 +1,91 @@
 package github
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"net/http"
+	"net/url"
+	"path"
 	"path/filepath"
+	"regexp"
 	"runtime"
+	"sort"
 	"strings"
+	"time"
+
+	"github.com/pyhub-kr/pyhub-installer/internal/retry"
+	"github.com/pyhub-kr/pyhub-installer/internal/useragent"
 )
 
 // Release represents a GitHub release
 type Release struct {
-	TagName string  `json:"tag_name"`
-	Name    string  `json:"name"`
-	Assets  []Asset `json:"assets"`
+	ID          int64     `json:"id"`
+	TagName     string    `json:"tag_name"`
+	Name        string    `json:"name"`
+	Prerelease  bool      `json:"prerelease"`
+	Draft       bool      `json:"draft"`
+	PublishedAt time.Time `json:"published_at"`
+	Body        string    `json:"body"`     // Release notes, in Markdown
+	HTMLURL     string    `json:"html_url"` // Web page of the release
+	Assets      []Asset   `json:"assets"`
 }
 
 // Asset represents a release asset
 type Asset struct {
 	Name               string `json:"name"`
+	URL                string `json:"url"` // API endpoint, downloadable with a token
 	BrowserDownloadURL string `json:"browser_download_url"`
 	Size               int64  `json:"size"`
+	Digest             string `json:"digest"` // "sha256:<hex>", published by GitHub for newer uploads
+
+	Binaries []string `json:"-"` // Archive paths (slash-separated globs) to install instead of the whole archive
 }
 
 // Client handles GitHub API interactions
 type Client struct {
-	BaseURL string
+	BaseURL        string
+	ReleaseBaseURL string        // Endpoint of release, tag and asset lookups, if not BaseURL
+	Token          string        // API token sent as a bearer token, if set
+	RateLimitWait  time.Duration // Longest rate-limit reset to wait for (default DefaultRateLimitWait)
+	HTTPClient     *http.Client  // Client for API requests (default: DefaultTimeout, proxy from environment)
+	CacheDir       string        // Directory of API responses revalidated with their ETag; empty disables caching
+
+	clock   func() time.Time
+	sleeper func(time.Duration)
 }
 
-// NewClient creates a new GitHub client
-func NewClient() *Client {
-	return &Client{
-		BaseURL: "https://api.github.com",
+// NewClient creates a new GitHub client with the token of ConfiguredToken.
+// Options are applied in order on top of the defaults.
+func NewClient(opts ...Option) *Client {
+	c := &Client{
+		BaseURL:        DefaultBaseURL,
+		ReleaseBaseURL: DefaultReleaseBaseURL,
+		Token:          ConfiguredToken(),
+		HTTPClient:     newHTTPClient(),
+		CacheDir:       DefaultCacheDir,
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	if c.Token == "" && TokenHelper != nil {
+		c.Token = TokenHelper(c.Host())
 	}
+	return c
 }
 
 // GetLatestRelease gets the latest release for a repository
 func (c *Client) GetLatestRelease(owner, repo string) (*Release, error) {
-	url := fmt.Sprintf("%s/repos/%s/%s/releases/latest", c.BaseURL, owner, repo)
+	url := fmt.Sprintf("%s/repos/%s/%s/releases/latest", c.releaseBaseURL(), owner, repo)
 	
-	resp, err := http.Get(url)
+	resp, err := c.get(url)
 	if err != nil {
 		return nil, fmt.Errorf("failed to fetch release: %w", err)
 	}
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("GitHub API error: %d", resp.StatusCode)
+		return nil, apiError(resp.StatusCode)
 	}
 
 	var release Release
@@ -59,16 +98,16 @@ func (c *Client) GetLatestRelease(owner, repo string) (*Release, error) {
 
 // GetRelease gets a specific release by tag
 func (c *Client) GetRelease(owner, repo, tag string) (*Release, error) {
-	url := fmt.Sprintf("%s/repos/%s/%s/releases/tags/%s", c.BaseURL, owner, repo, tag)
+	url := fmt.Sprintf("%s/repos/%s/%s/releases/tags/%s", c.releaseBaseURL(), owner, repo, tag)
 	
-	resp, err := http.Get(url)
+	resp, err := c.get(url)
 	if err != nil {
 		return nil, fmt.Errorf("failed to fetch release: %w", err)
 	}
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("GitHub API error: %d", resp.StatusCode)
+		return nil, apiError(resp.StatusCode)
 	}
 
 	var release Release
@@ -79,70 +118,419 @@ func (c *Client) GetRelease(owner, repo, tag string) (*Release, error) {
 	return &release, nil
 }
 
+// ListReleases lists the releases of a repository, newest first
+func (c *Client) ListReleases(owner, repo string) ([]Release, error) {
+	url := fmt.Sprintf("%s/repos/%s/%s/releases?per_page=%d", c.releaseBaseURL(), owner, repo, pageSize)
+
+	releases, err := getPages[Release](c, url)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch releases: %w", err)
+	}
+	return releases, nil
+}
+
+// FindDraftRelease finds an unpublished release by tag. Drafts are not served by the
+// tag endpoint and only appear in listings for tokens with push access.
+func (c *Client) FindDraftRelease(owner, repo, tag string) (*Release, error) {
+	if c.Token == "" {
+		return nil, fmt.Errorf("draft releases require GITHUB_TOKEN with push access to %s/%s", owner, repo)
+	}
+
+	releases, err := c.ListReleases(owner, repo)
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range releases {
+		if releases[i].Draft && releases[i].TagName == tag {
+			return &releases[i], nil
+		}
+	}
+	return nil, fmt.Errorf("no draft release found for tag: %s", tag)
+}
+
+// AssetHeaders returns the headers for downloading an asset through its API URL
+func (c *Client) AssetHeaders() map[string]string {
+	headers := map[string]string{"Accept": "application/octet-stream"}
+	if c.Token != "" {
+		headers["Authorization"] = "Bearer " + c.Token
+	}
+	return headers
+}
+
+// AssetDownloadURL resolves where an asset can be downloaded with the client's token.
+// The API endpoint usually redirects to pre-signed storage that needs no further
+// authentication; otherwise the API URL is returned with the headers it requires.
+func (c *Client) AssetDownloadURL(asset *Asset) (string, map[string]string, error) {
+	if _, err := url.Parse(asset.URL); err != nil {
+		return "", nil, err
+	}
+	headers := c.AssetHeaders()
+
+	client := *c.httpClient()
+	client.CheckRedirect = func(req *http.Request, via []*http.Request) error {
+		return http.ErrUseLastResponse
+	}
+	resp, err := retry.Do(context.Background(), func() (*http.Response, error) {
+		req, err := http.NewRequest("GET", asset.URL, nil)
+		if err != nil {
+			return nil, err
+		}
+		useragent.Apply(req)
+		for key, value := range headers {
+			req.Header.Set(key, value)
+		}
+		return client.Do(req)
+	})
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to resolve asset URL: %w", err)
+	}
+	resp.Body.Close()
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+		return asset.URL, headers, nil
+	case http.StatusFound, http.StatusMovedPermanently, http.StatusTemporaryRedirect, http.StatusSeeOther:
+		location := resp.Header.Get("Location")
+		if location == "" {
+			return "", nil, fmt.Errorf("asset redirect without location: %s", asset.Name)
+		}
+		return location, nil, nil
+	case http.StatusNotFound:
+		return "", nil, fmt.Errorf("asset not found: %s (check that GITHUB_TOKEN can read the repository)", asset.Name)
+	default:
+		return "", nil, fmt.Errorf("GitHub API error: %d", resp.StatusCode)
+	}
+}
+
+// Scoring holds the rules used to rank assets for a platform
+type Scoring struct {
+	Platforms   map[string][]string // Keywords identifying each platform in asset names
+	Adjustments map[string]int      // Added when an asset name contains the key; keys starting with "." must end the name
+}
+
+// DefaultScoring returns the built-in platform keywords and score adjustments
+func DefaultScoring() *Scoring {
+	return &Scoring{
+		Platforms: map[string][]string{
+			"windows-amd64": {"windows", "win", "win64", "amd64", "x86_64", "x64"},
+			"windows-386":   {"windows", "win", "win32", "386", "i386", "i686"},
+			"windows-arm64": {"windows", "win", "arm64", "aarch64"},
+			"darwin-amd64":  {"darwin", "macos", "osx", "amd64", "x86_64", "x64"},
+			"darwin-arm64":  {"darwin", "macos", "osx", "arm64", "aarch64"},
+			"linux-amd64":   {"linux", "amd64", "x86_64", "x64"},
+			"linux-386":     {"linux", "386", "i386", "i686"},
+			"linux-arm64":   {"linux", "arm64", "aarch64"},
+			"linux-arm":     {"linux", "arm", "armv7", "armhf"}, // ARM version unknown: prefer the common ARMv7 builds
+			"linux-armv7":   {"linux", "arm", "armv7", "armhf"},
+			"linux-armv6":   {"linux", "arm", "armv6", "armel"},
+			"linux-riscv64": {"linux", "riscv64"},
+			"linux-ppc64le": {"linux", "ppc64le", "powerpc64le"},
+			"linux-s390x":   {"linux", "s390x"},
+			"linux-loong64": {"linux", "loong64", "loongarch64"},
+			"freebsd-amd64": {"freebsd", "amd64", "x86_64", "x64"},
+			"freebsd-386":   {"freebsd", "386", "i386", "i686"},
+			"freebsd-arm64": {"freebsd", "arm64", "aarch64"},
+			"openbsd-amd64": {"openbsd", "amd64", "x86_64", "x64"},
+			"openbsd-arm64": {"openbsd", "arm64", "aarch64"},
+			"netbsd-amd64":  {"netbsd", "amd64", "x86_64", "x64"},
+		},
+		Adjustments: map[string]int{
+			// Bonus for common archive formats
+			".zip":    1,
+			".tar.gz": 1,
+			// Penalty for source code archives
+			"source": -10,
+			"src":    -10,
+		},
+	}
+}
+
+// Extend returns a copy of the scoring with extra platform keywords appended and
+// adjustments added or overridden
+func (s *Scoring) Extend(platforms map[string][]string, adjustments map[string]int) *Scoring {
+	extended := &Scoring{
+		Platforms:   make(map[string][]string, len(s.Platforms)+len(platforms)),
+		Adjustments: make(map[string]int, len(s.Adjustments)+len(adjustments)),
+	}
+	for platform, keywords := range s.Platforms {
+		extended.Platforms[platform] = append([]string(nil), keywords...)
+	}
+	for platform, keywords := range platforms {
+		extended.Platforms[platform] = append(extended.Platforms[platform], keywords...)
+	}
+	for key, value := range s.Adjustments {
+		extended.Adjustments[key] = value
+	}
+	for key, value := range adjustments {
+		extended.Adjustments[strings.ToLower(key)] = value
+	}
+	return extended
+}
+
 // FindAssetForPlatform finds the best asset for current platform
 func (r *Release) FindAssetForPlatform(platform string) (*Asset, error) {
-	if platform == "" {
-		platform = fmt.Sprintf("%s-%s", runtime.GOOS, runtime.GOARCH)
+	return r.FindAssetWithScoring(platform, DefaultScoring())
+}
+
+// FindAssetWithScoring finds the best asset for a platform using custom scoring rules
+func (r *Release) FindAssetWithScoring(platform string, scoring *Scoring) (*Asset, error) {
+	ranked, err := r.RankAssets(platform, scoring)
+	if err != nil {
+		return nil, err
 	}
 
-	// Platform mappings
-	platformMap := map[string][]string{
-		"windows-amd64": {"windows", "win64", "amd64", "x86_64"},
-		"windows-386":   {"windows", "win32", "386", "i386"},
-		"darwin-amd64":  {"darwin", "macos", "osx", "amd64", "x86_64"},
-		"darwin-arm64":  {"darwin", "macos", "osx", "arm64", "aarch64"},
-		"linux-amd64":   {"linux", "amd64", "x86_64"},
-		"linux-386":     {"linux", "386", "i386"},
-		"linux-arm64":   {"linux", "arm64", "aarch64"},
-		"linux-arm":     {"linux", "arm", "armv7"},
+	if len(ranked) == 0 || ranked[0].Score <= 0 {
+		if platform == "" {
+			platform = fmt.Sprintf("%s-%s", runtime.GOOS, runtime.GOARCH)
+		}
+		return nil, fmt.Errorf("no asset found for platform: %s", platform)
 	}
 
-	keywords := platformMap[platform]
+	return ranked[0].Asset, nil
+}
+
+// ScoredAsset is an asset with its platform match score
+type ScoredAsset struct {
+	Asset       *Asset
+	Score       int
+	Runnable    bool           // The asset's architecture can run on the platform's (see AssetMatchesArch)
+	Keywords    []string       // Platform keywords found in the asset name
+	Adjustments map[string]int // Score adjustments that apply to the asset name
+}
+
+// RankAssets scores every asset for a platform, best first. Among equal scores,
+// assets that can run on the platform's architecture come first; otherwise assets
+// keep their release order.
+func (r *Release) RankAssets(platform string, scoring *Scoring) ([]ScoredAsset, error) {
+	if platform == "" {
+		platform = fmt.Sprintf("%s-%s", runtime.GOOS, runtime.GOARCH)
+	}
+
+	keywords := scoring.Platforms[platform]
 	if len(keywords) == 0 {
 		return nil, fmt.Errorf("unsupported platform: %s", platform)
 	}
 
-	// Score assets based on platform keywords
-	var bestAsset *Asset
-	bestScore := 0
+	_, arch, _ := strings.Cut(platform, "-")
+	ranked := make([]ScoredAsset, 0, len(r.Assets))
+	for i := range r.Assets {
+		name := r.Assets[i].Name
+		matched, adjustments := scoring.Match(name, keywords)
+		scored := ScoredAsset{Asset: &r.Assets[i], Score: len(matched), Runnable: AssetMatchesArch(name, arch), Keywords: matched, Adjustments: adjustments}
+		for _, adjustment := range adjustments {
+			scored.Score += adjustment
+		}
+		ranked = append(ranked, scored)
+	}
+	sort.SliceStable(ranked, func(i, j int) bool {
+		if ranked[i].Score != ranked[j].Score {
+			return ranked[i].Score > ranked[j].Score
+		}
+		return ranked[i].Runnable && !ranked[j].Runnable
+	})
+	return ranked, nil
+}
 
-	for _, asset := range r.Assets {
-		score := r.scorePlatformMatch(asset.Name, keywords)
-		if score > bestScore {
-			bestScore = score
-			bestAsset = &asset
+// TiedAssets returns the best-scoring assets when more than one share the top score.
+// Assets that cannot run on the platform do not tie with ones that can.
+func TiedAssets(ranked []ScoredAsset) []*Asset {
+	tiesTop := func(scored ScoredAsset) bool {
+		return scored.Score == ranked[0].Score && scored.Runnable == ranked[0].Runnable
+	}
+	if len(ranked) < 2 || ranked[0].Score <= 0 || !tiesTop(ranked[1]) {
+		return nil
+	}
+
+	var tied []*Asset
+	for _, scored := range ranked {
+		if !tiesTop(scored) {
+			break
 		}
+		tied = append(tied, scored.Asset)
 	}
+	return tied
+}
 
-	if bestAsset == nil {
-		return nil, fmt.Errorf("no asset found for platform: %s", platform)
+// FindAssetByPattern selects the single asset whose name matches a glob or, failing
+// that, a regular expression. Platform heuristics are not applied.
+func (r *Release) FindAssetByPattern(pattern string) (*Asset, error) {
+	var matches []*Asset
+
+	var globErr error
+	for i := range r.Assets {
+		ok, err := path.Match(pattern, r.Assets[i].Name)
+		if err != nil {
+			globErr = err
+			break
+		}
+		if ok {
+			matches = append(matches, &r.Assets[i])
+		}
+	}
+
+	// Fall back to a regular expression when the glob matches nothing
+	if len(matches) == 0 {
+		re, err := regexp.Compile(pattern)
+		if err != nil && globErr != nil {
+			return nil, fmt.Errorf("invalid asset pattern %q: %w", pattern, err)
+		}
+		for i := range r.Assets {
+			if re != nil && re.MatchString(r.Assets[i].Name) {
+				matches = append(matches, &r.Assets[i])
+			}
+		}
+	}
+
+	switch len(matches) {
+	case 0:
+		return nil, fmt.Errorf("no asset matches pattern %q (available: %s)", pattern, strings.Join(r.assetNames(), ", "))
+	case 1:
+		return matches[0], nil
 	}
 
-	return bestAsset, nil
+	var names []string
+	for _, asset := range matches {
+		names = append(names, asset.Name)
+	}
+	return nil, fmt.Errorf("pattern %q matches multiple assets: %s (use a more specific pattern, e.g. the full asset name)", pattern, strings.Join(names, ", "))
+}
+
+// ValidateAssetPattern checks that a pattern is a valid glob or regular expression, so
+// that a mistyped --asset fails before any release is fetched
+func ValidateAssetPattern(pattern string) error {
+	if _, err := path.Match(pattern, ""); err == nil {
+		return nil
+	}
+	if _, err := regexp.Compile(pattern); err != nil {
+		return fmt.Errorf("invalid asset pattern %q: %w", pattern, err)
+	}
+	return nil
 }
 
-// scorePlatformMatch scores how well an asset name matches platform keywords
-func (r *Release) scorePlatformMatch(assetName string, keywords []string) int {
+// assetNames returns the names of all release assets
+func (r *Release) assetNames() []string {
+	names := make([]string, 0, len(r.Assets))
+	for _, asset := range r.Assets {
+		names = append(names, asset.Name)
+	}
+	return names
+}
+
+// Score rates how well an asset name matches platform keywords
+func (s *Scoring) Score(assetName string, keywords []string) int {
+	matched, adjustments := s.Match(assetName, keywords)
+	score := len(matched)
+	for _, adjustment := range adjustments {
+		score += adjustment
+	}
+	return score
+}
+
+// Match returns the platform keywords an asset name contains and the adjustments that
+// apply to it, which together make up its score
+func (s *Scoring) Match(assetName string, keywords []string) ([]string, map[string]int) {
 	name := strings.ToLower(assetName)
-	score := 0
 
+	var matched []string
 	for _, keyword := range keywords {
-		if strings.Contains(name, strings.ToLower(keyword)) {
-			score++
+		if containsKeyword(name, strings.ToLower(keyword)) {
+			matched = append(matched, keyword)
 		}
 	}
 
-	// Bonus for common archive formats
-	if strings.HasSuffix(name, ".zip") || strings.HasSuffix(name, ".tar.gz") {
-		score++
+	adjustments := make(map[string]int)
+	for key, adjustment := range s.Adjustments {
+		if strings.HasPrefix(key, ".") {
+			if strings.HasSuffix(name, key) {
+				adjustments[key] = adjustment
+			}
+		} else if strings.Contains(name, key) {
+			adjustments[key] = adjustment
+		}
 	}
 
-	// Penalty for source code archives
-	if strings.Contains(name, "source") || strings.Contains(name, "src") {
-		score -= 10
+	return matched, adjustments
+}
+
+// containsKeyword reports whether a name contains a platform keyword. Keywords starting
+// with a letter must start a word, so that "win" (win-arm64) is not found in "darwin".
+func containsKeyword(name, keyword string) bool {
+	if keyword == "" || !isLetter(keyword[0]) {
+		return strings.Contains(name, keyword)
+	}
+	for i := 0; ; {
+		j := strings.Index(name[i:], keyword)
+		if j < 0 {
+			return false
+		}
+		if i+j == 0 || !isLetter(name[i+j-1]) {
+			return true
+		}
+		i += j + 1
 	}
+}
 
-	return score
+// isLetter reports whether an ASCII byte is a lowercase letter
+func isLetter(c byte) bool {
+	return c >= 'a' && c <= 'z'
+}
+
+// archAliases lists the spellings of each architecture found in asset names. "arm" is
+// 32-bit ARM of unknown version; armv7 and armv6 are the versions.
+var archAliases = map[string][]string{
+	"amd64":   {"amd64", "x86_64", "x64"},
+	"386":     {"386", "i386", "i686", "x86_32"},
+	"arm64":   {"arm64", "aarch64"},
+	"arm":     {"arm"},
+	"armv7":   {"armv7", "armhf"},
+	"armv6":   {"armv6", "armel"},
+	"riscv64": {"riscv64"},
+	"ppc64le": {"ppc64le", "powerpc64le"},
+	"ppc64":   {"ppc64"}, // Big-endian; "ppc64le" is longer and wins for little-endian builds
+	"s390x":   {"s390x"},
+	"loong64": {"loong64", "loongarch64"},
+}
+
+// archRuns lists the other architectures whose builds run on an architecture
+var archRuns = map[string][]string{
+	"arm":   {"armv7", "armv6"}, // The version of the CPU is unknown
+	"armv7": {"arm", "armv6"},
+	"armv6": {"arm"},
+}
+
+// AssetMatchesArch reports whether an asset can run on an architecture: its name
+// mentions that architecture or one it can run, marks a universal build, or names no
+// architecture at all
+func AssetMatchesArch(assetName, arch string) bool {
+	name := strings.ToLower(assetName)
+	if strings.Contains(name, "universal") {
+		return true
+	}
+	named := assetArch(name)
+	if named == "" || named == arch {
+		return true
+	}
+	for _, runs := range archRuns[arch] {
+		if named == runs {
+			return true
+		}
+	}
+	return false
+}
+
+// assetArch returns the architecture an asset name mentions, by its longest alias so
+// that "arm64" is not taken for "arm", or "" if it mentions none
+func assetArch(name string) string {
+	arch, longest := "", 0
+	for candidate, aliases := range archAliases {
+		for _, alias := range aliases {
+			if len(alias) > longest && strings.Contains(name, alias) {
+				arch, longest = candidate, len(alias)
+			}
+		}
+	}
+	return arch
 }
 
 // FindSignatureAsset finds signature file for an asset
@@ -195,4 +583,27 @@ func ParseRepoURL(input string) (owner, repo string, err error) {
 	}
 
 	return parts[0], parts[1], nil
-}
\ No newline at end of file
+}
+
+// NotesSummary returns the first maxLines non-blank lines of the release notes, with
+// Markdown heading markers and HTML comments removed, and whether lines were left out
+func (r *Release) NotesSummary(maxLines int) ([]string, bool) {
+	var lines []string
+	inComment := false
+	for _, line := range strings.Split(r.Body, "\n") {
+		line = strings.TrimSpace(line)
+		if inComment || strings.HasPrefix(line, "<!--") {
+			inComment = !strings.Contains(line, "-->")
+			continue
+		}
+		line = strings.TrimSpace(strings.TrimLeft(line, "#"))
+		if line == "" {
+			continue
+		}
+		if len(lines) == maxLines {
+			return lines, true
+		}
+		lines = append(lines, line)
+	}
+	return lines, false
+}
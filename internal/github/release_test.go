@@ -5,7 +5,9 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"runtime"
+	"strings"
 	"testing"
+	"time"
 )
 
 func TestNewClient(t *testing.T) {
@@ -234,7 +236,7 @@ func TestFindAssetForPlatform(t *testing.T) {
 		},
 		{
 			name:     "Unsupported platform",
-			platform: "freebsd-amd64",
+			platform: "plan9-amd64",
 			wantErr:  true,
 		},
 	}
@@ -263,8 +265,6 @@ func TestFindAssetForPlatform(t *testing.T) {
 }
 
 func TestScorePlatformMatch(t *testing.T) {
-	release := &Release{}
-	
 	tests := []struct {
 		name      string
 		assetName string
@@ -299,7 +299,7 @@ func TestScorePlatformMatch(t *testing.T) {
 	
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			score := release.scorePlatformMatch(tt.assetName, tt.keywords)
+			score := DefaultScoring().Score(tt.assetName, tt.keywords)
 			if score != tt.wantScore {
 				t.Errorf("Expected score %d, got %d", tt.wantScore, score)
 			}
@@ -444,4 +444,458 @@ func substringIndex(s, substr string) int {
 		}
 	}
 	return -1
-}
\ No newline at end of file
+}
+
+func TestListReleases(t *testing.T) {
+	releases := []Release{
+		{TagName: "v2.0.0", Name: "Release 2.0.0"},
+		{TagName: "v1.0.0", Name: "Release 1.0.0"},
+	}
+	
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/repos/owner/repo/releases" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(releases)
+	}))
+	defer server.Close()
+	
+	client := &Client{BaseURL: server.URL}
+	
+	got, err := client.ListReleases("owner", "repo")
+	if err != nil {
+		t.Fatalf("ListReleases() error = %v", err)
+	}
+	
+	if len(got) != 2 || got[0].TagName != "v2.0.0" {
+		t.Errorf("Expected releases [v2.0.0 v1.0.0], got %+v", got)
+	}
+	
+	// Test 404 response
+	_, err = client.ListReleases("invalid", "repo")
+	if err == nil {
+		t.Error("Expected error for 404 response, got nil")
+	}
+}
+
+func TestListReleasesMetadata(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("per_page") != "100" {
+			t.Errorf("Expected per_page=100, got %s", r.URL.RawQuery)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`[{"tag_name": "v1.0.0-rc.1", "prerelease": true, "published_at": "2024-03-01T12:00:00Z", "assets": [{"name": "a"}, {"name": "b"}]}]`))
+	}))
+	defer server.Close()
+
+	client := &Client{BaseURL: server.URL}
+	got, err := client.ListReleases("owner", "repo")
+	if err != nil {
+		t.Fatalf("ListReleases() error = %v", err)
+	}
+
+	if len(got) != 1 {
+		t.Fatalf("Expected 1 release, got %d", len(got))
+	}
+	if !got[0].Prerelease {
+		t.Error("Expected release to be marked as pre-release")
+	}
+	if want := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC); !got[0].PublishedAt.Equal(want) {
+		t.Errorf("Expected PublishedAt %v, got %v", want, got[0].PublishedAt)
+	}
+	if len(got[0].Assets) != 2 {
+		t.Errorf("Expected 2 assets, got %d", len(got[0].Assets))
+	}
+}
+
+func TestFindAssetByPattern(t *testing.T) {
+	release := &Release{
+		Assets: []Asset{
+			{Name: "tool_1.0.0_linux_amd64.deb"},
+			{Name: "tool_1.0.0_linux_amd64.tar.gz"},
+			{Name: "tool_1.0.0_linux_arm64.tar.gz"},
+			{Name: "checksums.txt"},
+		},
+	}
+
+	tests := []struct {
+		name    string
+		pattern string
+		want    string
+		wantErr bool
+	}{
+		{"glob", "*linux_amd64.tar.gz", "tool_1.0.0_linux_amd64.tar.gz", false},
+		{"exact name", "checksums.txt", "checksums.txt", false},
+		{"regex", `amd64\.deb$`, "tool_1.0.0_linux_amd64.deb", false},
+		{"regex with groups", `linux_(arm64|aarch64)`, "tool_1.0.0_linux_arm64.tar.gz", false},
+		{"multiple matches", "*.tar.gz", "", true},
+		{"no match", "*windows*", "", true},
+		{"invalid pattern", "[", "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			asset, err := release.FindAssetByPattern(tt.pattern)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("FindAssetByPattern(%q) error = %v, wantErr %v", tt.pattern, err, tt.wantErr)
+			}
+			if !tt.wantErr && asset.Name != tt.want {
+				t.Errorf("Expected %s, got %s", tt.want, asset.Name)
+			}
+		})
+	}
+}
+
+func TestValidateAssetPattern(t *testing.T) {
+	for _, pattern := range []string{"*linux_amd64.tar.gz", `amd64\.deb$`, "tool[0-9].zip", `linux_(arm64|aarch64)`} {
+		if err := ValidateAssetPattern(pattern); err != nil {
+			t.Errorf("ValidateAssetPattern(%q) error = %v", pattern, err)
+		}
+	}
+	for _, pattern := range []string{"[", "tool[-"} {
+		if err := ValidateAssetPattern(pattern); err == nil {
+			t.Errorf("Expected error for %q", pattern)
+		}
+	}
+}
+
+func TestAssetMatchesArch(t *testing.T) {
+	tests := []struct {
+		name string
+		arch string
+		want bool
+	}{
+		{"tool_darwin_arm64.tar.gz", "arm64", true},
+		{"tool-aarch64-apple-darwin.tar.gz", "arm64", true},
+		{"tool_darwin_amd64.tar.gz", "arm64", false},
+		{"tool-x86_64-apple-darwin.tar.gz", "arm64", false},
+		{"tool_darwin_universal.tar.gz", "arm64", true},
+		{"tool_Darwin_all.tar.gz", "arm64", true},
+		{"tool-macos.zip", "arm64", true},
+		{"tool_linux_x86_64.tar.gz", "amd64", true},
+		{"tool_linux_arm64.tar.gz", "amd64", false},
+		{"tool_linux_arm64.tar.gz", "arm", false},
+		{"tool_linux_arm.tar.gz", "arm", true},
+		{"tool_linux_armv7.tar.gz", "arm", true},
+		{"tool_linux_armhf.deb", "armv7", true},
+		{"tool_linux_armv6.tar.gz", "armv7", true},
+		{"tool_linux_armv7.tar.gz", "armv6", false},
+		{"tool_linux_armel.tar.gz", "armv6", true},
+		{"tool_linux_riscv64.tar.gz", "riscv64", true},
+		{"tool_linux_riscv64.tar.gz", "amd64", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := AssetMatchesArch(tt.name, tt.arch); got != tt.want {
+				t.Errorf("AssetMatchesArch(%s, %s) = %v, want %v", tt.name, tt.arch, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFindAssetForWindowsARM64(t *testing.T) {
+	release := &Release{
+		Assets: []Asset{
+			{Name: "app-windows-amd64.zip"},
+			{Name: "app-windows-arm64.zip"},
+			{Name: "app-linux-arm64.tar.gz"},
+		},
+	}
+
+	asset, err := release.FindAssetForPlatform("windows-arm64")
+	if err != nil {
+		t.Fatalf("FindAssetForPlatform() error = %v", err)
+	}
+	if asset.Name != "app-windows-arm64.zip" {
+		t.Errorf("Expected app-windows-arm64.zip, got %s", asset.Name)
+	}
+
+	// Without a native build the x64 asset is the closest match, but it is not arm64
+	release.Assets = release.Assets[:1]
+	asset, err = release.FindAssetForPlatform("windows-arm64")
+	if err != nil {
+		t.Fatalf("FindAssetForPlatform() error = %v", err)
+	}
+	if AssetMatchesArch(asset.Name, "arm64") {
+		t.Errorf("Expected %s not to match arm64", asset.Name)
+	}
+}
+
+func TestAssetDownloadURL(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer secret" || r.Header.Get("Accept") != "application/octet-stream" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		switch r.URL.Path {
+		case "/assets/redirect":
+			http.Redirect(w, r, "https://storage.example.com/signed?token=abc", http.StatusFound)
+		case "/assets/direct":
+			w.Write([]byte("content"))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	client := &Client{BaseURL: server.URL, Token: "secret"}
+
+	url, headers, err := client.AssetDownloadURL(&Asset{Name: "a", URL: server.URL + "/assets/redirect"})
+	if err != nil {
+		t.Fatalf("AssetDownloadURL() error = %v", err)
+	}
+	if url != "https://storage.example.com/signed?token=abc" || headers != nil {
+		t.Errorf("Expected signed URL without headers, got %s %v", url, headers)
+	}
+
+	url, headers, err = client.AssetDownloadURL(&Asset{Name: "b", URL: server.URL + "/assets/direct"})
+	if err != nil {
+		t.Fatalf("AssetDownloadURL() error = %v", err)
+	}
+	if url != server.URL+"/assets/direct" || headers["Authorization"] != "Bearer secret" {
+		t.Errorf("Expected API URL with authorization, got %s %v", url, headers)
+	}
+
+	if _, _, err := client.AssetDownloadURL(&Asset{Name: "c", URL: server.URL + "/assets/missing"}); err == nil {
+		t.Error("Expected error for missing asset")
+	}
+}
+
+func TestScoringExtend(t *testing.T) {
+	release := &Release{
+		Assets: []Asset{
+			{Name: "tool-linux-amd64.tar.gz"},
+			{Name: "tool-linux-amd64-static.tar.gz"},
+			{Name: "tool-illumos-amd64.tar.gz"},
+		},
+	}
+
+	scoring := DefaultScoring().Extend(
+		map[string][]string{"illumos-amd64": {"illumos", "amd64"}},
+		map[string]int{"Static": 2},
+	)
+
+	asset, err := release.FindAssetWithScoring("linux-amd64", scoring)
+	if err != nil {
+		t.Fatalf("FindAssetWithScoring() error = %v", err)
+	}
+	if asset.Name != "tool-linux-amd64-static.tar.gz" {
+		t.Errorf("Expected static build to win, got %s", asset.Name)
+	}
+
+	release.Assets = release.Assets[2:]
+	asset, err = release.FindAssetWithScoring("illumos-amd64", scoring)
+	if err != nil {
+		t.Fatalf("FindAssetWithScoring() error = %v", err)
+	}
+	if asset.Name != "tool-illumos-amd64.tar.gz" {
+		t.Errorf("Expected custom platform asset, got %s", asset.Name)
+	}
+
+	// Extending must not change the defaults
+	if _, err := release.FindAssetForPlatform("illumos-amd64"); err == nil {
+		t.Error("Expected default scoring to leave illumos-amd64 unsupported")
+	}
+	if _, ok := DefaultScoring().Adjustments["static"]; ok {
+		t.Error("Expected default adjustments to be unchanged")
+	}
+}
+
+func TestFindAssetForMorePlatforms(t *testing.T) {
+	release := &Release{
+		Assets: []Asset{
+			{Name: "tool-darwin-arm64.tar.gz"},
+			{Name: "tool-win-arm64.zip"},
+			{Name: "tool-win-x64.zip"},
+			{Name: "tool-linux-x64.tar.gz"},
+			{Name: "tool_Linux_armv6.tar.gz"},
+			{Name: "tool_Linux_armhf.tar.gz"},
+			{Name: "tool_Linux_aarch64.tar.gz"},
+			{Name: "tool_Linux_riscv64.tar.gz"},
+			{Name: "tool_Linux_ppc64le.tar.gz"},
+			{Name: "tool_Linux_s390x.tar.gz"},
+			{Name: "tool_Freebsd_x86_64.tar.gz"},
+			{Name: "tool_Freebsd_arm64.tar.gz"},
+		},
+	}
+
+	tests := map[string]string{
+		"windows-arm64": "tool-win-arm64.zip",
+		"windows-amd64": "tool-win-x64.zip",
+		"linux-amd64":   "tool-linux-x64.tar.gz",
+		"linux-armv6":   "tool_Linux_armv6.tar.gz",
+		"linux-armv7":   "tool_Linux_armhf.tar.gz",
+		"linux-arm64":   "tool_Linux_aarch64.tar.gz",
+		"linux-riscv64": "tool_Linux_riscv64.tar.gz",
+		"linux-ppc64le": "tool_Linux_ppc64le.tar.gz",
+		"linux-s390x":   "tool_Linux_s390x.tar.gz",
+		"freebsd-amd64": "tool_Freebsd_x86_64.tar.gz",
+		"freebsd-arm64": "tool_Freebsd_arm64.tar.gz",
+		"darwin-arm64":  "tool-darwin-arm64.tar.gz",
+	}
+	for platform, want := range tests {
+		asset, err := release.FindAssetForPlatform(platform)
+		if err != nil {
+			t.Errorf("FindAssetForPlatform(%s) error = %v", platform, err)
+			continue
+		}
+		if asset.Name != want {
+			t.Errorf("FindAssetForPlatform(%s) = %s, want %s", platform, asset.Name, want)
+		}
+	}
+}
+
+func TestContainsKeyword(t *testing.T) {
+	tests := []struct {
+		name, keyword string
+		want          bool
+	}{
+		{"tool-win-arm64.zip", "win", true},
+		{"tool-windows-amd64.zip", "win", true},
+		{"tool-darwin-arm64.tar.gz", "win", false},
+		{"tool_linux_i386.tar.gz", "386", true},
+		{"tool_macosx.zip", "osx", false},
+		{"tool_macosx.zip", "macos", true},
+	}
+	for _, tt := range tests {
+		if got := containsKeyword(tt.name, tt.keyword); got != tt.want {
+			t.Errorf("containsKeyword(%q, %q) = %v, want %v", tt.name, tt.keyword, got, tt.want)
+		}
+	}
+}
+
+func TestRankAssets(t *testing.T) {
+	release := &Release{
+		Assets: []Asset{
+			{Name: "tool_linux_amd64.deb"},
+			{Name: "tool_linux_amd64.tar.gz"},
+			{Name: "tool_linux_x86_64.zip"},
+			{Name: "tool_darwin_arm64.tar.gz"},
+		},
+	}
+
+	ranked, err := release.RankAssets("linux-amd64", DefaultScoring())
+	if err != nil {
+		t.Fatalf("RankAssets() error = %v", err)
+	}
+	if len(ranked) != 4 {
+		t.Fatalf("Expected all 4 assets ranked, got %d", len(ranked))
+	}
+	if ranked[0].Asset.Name != "tool_linux_amd64.tar.gz" || ranked[1].Asset.Name != "tool_linux_x86_64.zip" {
+		t.Errorf("Expected archives first in release order, got %s, %s", ranked[0].Asset.Name, ranked[1].Asset.Name)
+	}
+
+	tied := TiedAssets(ranked)
+	if len(tied) != 2 {
+		t.Errorf("Expected 2 tied assets, got %d", len(tied))
+	}
+
+	if _, err := release.RankAssets("plan9-amd64", DefaultScoring()); err == nil {
+		t.Error("Expected error for unsupported platform")
+	}
+}
+
+func TestRankAssetsMatches(t *testing.T) {
+	release := &Release{
+		Assets: []Asset{
+			{Name: "tool-src-linux-x86_64.tar.gz"},
+			{Name: "tool-linux-amd64.zip"},
+		},
+	}
+
+	ranked, err := release.RankAssets("linux-amd64", DefaultScoring())
+	if err != nil {
+		t.Fatalf("RankAssets() error = %v", err)
+	}
+	best, source := ranked[0], ranked[1]
+	if best.Asset.Name != "tool-linux-amd64.zip" || strings.Join(best.Keywords, ",") != "linux,amd64" || best.Adjustments[".zip"] != 1 {
+		t.Errorf("Expected the zip to match linux, amd64 and .zip, got %+v", best)
+	}
+	if strings.Join(source.Keywords, ",") != "linux,x86_64" || source.Adjustments["src"] != -10 || source.Adjustments[".tar.gz"] != 1 || source.Score != -7 {
+		t.Errorf("Expected the source archive to be penalized, got %+v", source)
+	}
+}
+
+func TestRankAssetsARMAndRISCV(t *testing.T) {
+	release := &Release{
+		Assets: []Asset{
+			{Name: "tool_linux_arm64.tar.gz"},
+			{Name: "tool_linux_armv6.tar.gz"},
+			{Name: "tool_linux_armhf.tar.gz"},
+			{Name: "tool_linux_riscv64.tar.gz"},
+		},
+	}
+	tests := map[string]string{
+		"linux-arm":     "tool_linux_armhf.tar.gz",
+		"linux-armv7":   "tool_linux_armhf.tar.gz",
+		"linux-armv6":   "tool_linux_armv6.tar.gz",
+		"linux-riscv64": "tool_linux_riscv64.tar.gz",
+		"linux-arm64":   "tool_linux_arm64.tar.gz",
+	}
+	for platform, want := range tests {
+		asset, err := release.FindAssetForPlatform(platform)
+		if err != nil {
+			t.Errorf("%s: FindAssetForPlatform() error = %v", platform, err)
+			continue
+		}
+		if asset.Name != want {
+			t.Errorf("%s: got %s, want %s", platform, asset.Name, want)
+		}
+	}
+
+	// An ARMv7 system runs ARMv6 builds; the arm64 build scores the same but cannot run
+	release.Assets = []Asset{{Name: "tool_linux_arm64.tar.gz"}, {Name: "tool_linux_armv6.tar.gz"}}
+	ranked, err := release.RankAssets("linux-armv7", DefaultScoring())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ranked[0].Asset.Name != "tool_linux_armv6.tar.gz" || !ranked[0].Runnable {
+		t.Errorf("Expected the runnable armv6 build first, got %+v", ranked[0])
+	}
+	if tied := TiedAssets(ranked); tied != nil {
+		t.Errorf("Expected no tie with a build that cannot run, got %v", tied)
+	}
+}
+
+func TestTiedAssetsUnambiguous(t *testing.T) {
+	ranked := []ScoredAsset{
+		{Asset: &Asset{Name: "a"}, Score: 3},
+		{Asset: &Asset{Name: "b"}, Score: 2},
+	}
+	if tied := TiedAssets(ranked); tied != nil {
+		t.Errorf("Expected no tie, got %v", tied)
+	}
+
+	zero := []ScoredAsset{
+		{Asset: &Asset{Name: "a"}, Score: 0},
+		{Asset: &Asset{Name: "b"}, Score: 0},
+	}
+	if tied := TiedAssets(zero); tied != nil {
+		t.Errorf("Expected no tie among non-matching assets, got %v", tied)
+	}
+}
+
+func TestNotesSummary(t *testing.T) {
+	release := &Release{Body: "<!-- generated\nby a bot -->\n## What's Changed\r\n\n* Faster downloads\n* Fix Windows paths\n\n**Full Changelog**: v1...v2"}
+
+	lines, truncated := release.NotesSummary(2)
+	if len(lines) != 2 || lines[0] != "What's Changed" || lines[1] != "* Faster downloads" {
+		t.Errorf("Unexpected summary: %q", lines)
+	}
+	if !truncated {
+		t.Error("Expected summary to be truncated")
+	}
+
+	lines, truncated = release.NotesSummary(10)
+	if len(lines) != 4 || truncated {
+		t.Errorf("Expected all 4 lines untruncated, got %q (truncated=%v)", lines, truncated)
+	}
+
+	if lines, truncated := (&Release{}).NotesSummary(5); len(lines) != 0 || truncated {
+		t.Errorf("Expected empty summary without notes, got %q", lines)
+	}
+}
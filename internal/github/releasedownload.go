@@ -0,0 +1,48 @@
+package github
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+// ReleaseDownloadURL resolves a release download URL of github.com, such as
+// https://github.com/owner/repo/releases/download/v1.0.0/tool.tar.gz, for the client's
+// token. Those URLs return 404 for private repositories, so with a token the asset is
+// looked up in its release and fetched through the API. Other URLs, and all URLs
+// without a token, are returned unchanged.
+func (c *Client) ReleaseDownloadURL(rawURL string) (string, map[string]string, error) {
+	owner, repo, tag, name, ok := parseReleaseDownloadURL(rawURL)
+	if !ok || c.Token == "" {
+		return rawURL, nil, nil
+	}
+
+	release, err := c.GetRelease(owner, repo, tag)
+	if err != nil {
+		return "", nil, err
+	}
+	assets, err := c.ReleaseAssets(owner, repo, release)
+	if err != nil {
+		return "", nil, err
+	}
+	for i := range assets {
+		if assets[i].Name == name {
+			return c.AssetDownloadURL(&assets[i])
+		}
+	}
+	return "", nil, fmt.Errorf("asset not found: %s in %s/%s %s", name, owner, repo, tag)
+}
+
+// parseReleaseDownloadURL splits a github.com release download URL into its repository,
+// tag and asset name
+func parseReleaseDownloadURL(rawURL string) (owner, repo, tag, name string, ok bool) {
+	u, err := url.Parse(rawURL)
+	if err != nil || u.Host != "github.com" {
+		return "", "", "", "", false
+	}
+	parts := strings.Split(strings.Trim(u.Path, "/"), "/")
+	if len(parts) != 6 || parts[2] != "releases" || parts[3] != "download" {
+		return "", "", "", "", false
+	}
+	return parts[0], parts[1], parts[4], parts[5], true
+}
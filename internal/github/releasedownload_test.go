@@ -0,0 +1,56 @@
+package github
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestReleaseDownloadURL(t *testing.T) {
+	var server *httptest.Server
+	server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/repos/owner/private/releases/tags/v1.0.0":
+			w.Write([]byte(`{"tag_name": "v1.0.0", "assets": [
+				{"name": "tool.tar.gz", "url": "` + server.URL + `/repos/owner/private/releases/assets/7"}
+			]}`))
+		case "/repos/owner/private/releases/assets/7":
+			if r.Header.Get("Accept") != "application/octet-stream" {
+				w.WriteHeader(http.StatusNotFound)
+				return
+			}
+			http.Redirect(w, r, "https://storage.example.com/signed", http.StatusFound)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	client := &Client{BaseURL: server.URL, Token: "secret"}
+	url, _, err := client.ReleaseDownloadURL("https://github.com/owner/private/releases/download/v1.0.0/tool.tar.gz")
+	if err != nil {
+		t.Fatalf("ReleaseDownloadURL() error = %v", err)
+	}
+	if url != "https://storage.example.com/signed" {
+		t.Errorf("Expected the asset to be fetched through the API, got %s", url)
+	}
+
+	if _, _, err := client.ReleaseDownloadURL("https://github.com/owner/private/releases/download/v1.0.0/other.zip"); err == nil {
+		t.Error("Expected error for an asset missing from the release")
+	}
+
+	for _, rawURL := range []string{
+		"https://example.com/owner/private/releases/download/v1.0.0/tool.tar.gz",
+		"https://github.com/owner/private/archive/refs/tags/v1.0.0.tar.gz",
+	} {
+		if url, headers, err := client.ReleaseDownloadURL(rawURL); url != rawURL || headers != nil || err != nil {
+			t.Errorf("Expected %s unchanged, got %s %v %v", rawURL, url, headers, err)
+		}
+	}
+
+	anonymous := &Client{BaseURL: server.URL}
+	rawURL := "https://github.com/owner/private/releases/download/v1.0.0/tool.tar.gz"
+	if url, _, _ := anonymous.ReleaseDownloadURL(rawURL); url != rawURL {
+		t.Errorf("Expected the URL unchanged without a token, got %s", url)
+	}
+}
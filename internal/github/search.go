@@ -0,0 +1,41 @@
+package github
+
+import (
+	"fmt"
+	"net/url"
+)
+
+// maxSearchResults is the largest page the search API returns
+const maxSearchResults = 100
+
+// Repository represents a repository found by a search
+type Repository struct {
+	FullName    string `json:"full_name"`
+	Description string `json:"description"`
+	Stars       int    `json:"stargazers_count"`
+	HTMLURL     string `json:"html_url"`
+	Archived    bool   `json:"archived"`
+}
+
+// SearchRepositories searches repositories by keywords, most stars first, returning
+// at most limit repositories (up to 100). The query may use GitHub's search
+// qualifiers, e.g. "ripgrep language:rust".
+func (c *Client) SearchRepositories(query string, limit int) ([]Repository, error) {
+	if limit < 1 || limit > maxSearchResults {
+		limit = maxSearchResults
+	}
+	params := url.Values{
+		"q":        {query},
+		"sort":     {"stars"},
+		"order":    {"desc"},
+		"per_page": {fmt.Sprint(limit)},
+	}
+
+	var page struct {
+		Items []Repository `json:"items"`
+	}
+	if err := c.getJSON(c.BaseURL+"/search/repositories?"+params.Encode(), &page); err != nil {
+		return nil, fmt.Errorf("failed to search repositories: %w", err)
+	}
+	return page.Items, nil
+}
@@ -0,0 +1,50 @@
+package github
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestSearchRepositories(t *testing.T) {
+	var query, sort, perPage string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/search/repositories" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		query = r.URL.Query().Get("q")
+		sort = r.URL.Query().Get("sort")
+		perPage = r.URL.Query().Get("per_page")
+		w.Write([]byte(`{"total_count": 2, "items": [
+			{"full_name": "BurntSushi/ripgrep", "description": "grep, but faster", "stargazers_count": 50000},
+			{"full_name": "old/ripgrep-fork", "stargazers_count": 3, "archived": true}
+		]}`))
+	}))
+	defer server.Close()
+
+	client := &Client{BaseURL: server.URL}
+	repos, err := client.SearchRepositories("ripgrep language:rust", 5)
+	if err != nil {
+		t.Fatalf("SearchRepositories() error = %v", err)
+	}
+	if query != "ripgrep language:rust" || sort != "stars" || perPage != "5" {
+		t.Errorf("Unexpected query q=%q sort=%q per_page=%q", query, sort, perPage)
+	}
+	if len(repos) != 2 {
+		t.Fatalf("Expected 2 repositories, got %d", len(repos))
+	}
+	if repos[0].FullName != "BurntSushi/ripgrep" || repos[0].Stars != 50000 || repos[0].Description != "grep, but faster" {
+		t.Errorf("Unexpected first repository: %+v", repos[0])
+	}
+	if !repos[1].Archived {
+		t.Error("Expected second repository to be archived")
+	}
+
+	if _, err := client.SearchRepositories("ripgrep", 500); err != nil {
+		t.Fatalf("SearchRepositories() error = %v", err)
+	}
+	if perPage != "100" {
+		t.Errorf("Expected limit capped at 100, got per_page=%s", perPage)
+	}
+}
@@ -0,0 +1,52 @@
+package github
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+// Tag represents a git tag of a repository
+type Tag struct {
+	Name string `json:"name"`
+}
+
+// sourceArchivePath marks the URLs of auto-generated tag archives
+const sourceArchivePath = "/archive/refs/tags/"
+
+// ListTags lists the tags of a repository
+func (c *Client) ListTags(owner, repo string) ([]Tag, error) {
+	url := fmt.Sprintf("%s/repos/%s/%s/tags?per_page=%d", c.releaseBaseURL(), owner, repo, pageSize)
+
+	tags, err := getPages[Tag](c, url)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch tags: %w", err)
+	}
+	return tags, nil
+}
+
+// SourceArchive returns the source archive GitHub generates for a tag. The API URL
+// serves the same archive to token holders, so private repositories work too.
+func (c *Client) SourceArchive(owner, repo, tag string) Asset {
+	escaped := url.PathEscape(tag)
+	return Asset{
+		Name:               fmt.Sprintf("%s-%s.tar.gz", repo, strings.TrimPrefix(tag, "v")),
+		URL:                fmt.Sprintf("%s/repos/%s/%s/tarball/%s", c.releaseBaseURL(), owner, repo, escaped),
+		BrowserDownloadURL: fmt.Sprintf("https://github.com/%s/%s%s%s.tar.gz", owner, repo, sourceArchivePath, escaped),
+	}
+}
+
+// TagRelease describes a tag without a release as a release whose only asset is the
+// tag's source archive
+func (c *Client) TagRelease(owner, repo, tag string) *Release {
+	return &Release{
+		TagName: tag,
+		Name:    tag,
+		Assets:  []Asset{c.SourceArchive(owner, repo, tag)},
+	}
+}
+
+// IsSourceArchive reports whether an asset is an auto-generated tag archive
+func IsSourceArchive(asset *Asset) bool {
+	return strings.Contains(asset.BrowserDownloadURL, sourceArchivePath)
+}
@@ -0,0 +1,61 @@
+package github
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestListTags(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/repos/owner/repo/tags":
+			w.Write([]byte(`[{"name": "v1.1.0"}, {"name": "v1.0.0"}]`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	client := &Client{BaseURL: server.URL}
+	tags, err := client.ListTags("owner", "repo")
+	if err != nil {
+		t.Fatalf("ListTags() error = %v", err)
+	}
+	if len(tags) != 2 || tags[0].Name != "v1.1.0" {
+		t.Errorf("Expected 2 tags starting with v1.1.0, got %v", tags)
+	}
+
+	if _, err := client.ListTags("owner", "missing"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Expected ErrNotFound, got %v", err)
+	}
+}
+
+func TestSourceArchive(t *testing.T) {
+	client := &Client{BaseURL: "https://api.github.com"}
+	asset := client.SourceArchive("owner", "tool", "v1.2.3")
+
+	if asset.Name != "tool-1.2.3.tar.gz" {
+		t.Errorf("Expected tool-1.2.3.tar.gz, got %s", asset.Name)
+	}
+	if asset.BrowserDownloadURL != "https://github.com/owner/tool/archive/refs/tags/v1.2.3.tar.gz" {
+		t.Errorf("Unexpected download URL: %s", asset.BrowserDownloadURL)
+	}
+	if asset.URL != "https://api.github.com/repos/owner/tool/tarball/v1.2.3" {
+		t.Errorf("Unexpected API URL: %s", asset.URL)
+	}
+	if !IsSourceArchive(&asset) {
+		t.Error("Expected source archive to be recognized")
+	}
+
+	uploaded := Asset{BrowserDownloadURL: "https://github.com/owner/tool/releases/download/v1.2.3/tool.tar.gz"}
+	if IsSourceArchive(&uploaded) {
+		t.Error("Expected uploaded asset not to be a source archive")
+	}
+
+	release := client.TagRelease("owner", "tool", "v1.2.3")
+	if release.TagName != "v1.2.3" || len(release.Assets) != 1 || release.Assets[0].BrowserDownloadURL != asset.BrowserDownloadURL {
+		t.Errorf("Expected tag release with the source archive, got %+v", release)
+	}
+}
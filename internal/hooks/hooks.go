@@ -0,0 +1,96 @@
+package hooks
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"runtime"
+)
+
+// Event is a point in the install pipeline at which hooks run
+type Event string
+
+// Hook events. A failing pre-download or post-verify hook aborts the operation, so
+// these can enforce policies such as virus scanning; later hooks only warn.
+const (
+	PreDownload   Event = "pre-download"
+	PostVerify    Event = "post-verify"
+	PostInstall   Event = "post-install"
+	PostUninstall Event = "post-uninstall"
+)
+
+// Blocking reports whether a failing hook of the event aborts the operation
+func (e Event) Blocking() bool {
+	return e == PreDownload || e == PostVerify
+}
+
+// Context describes the operation a hook runs for. It is passed to hook commands
+// as PYHUB_* environment variables; empty fields are left out.
+type Context struct {
+	Tool        string // Tool name, e.g. ripgrep
+	Source      string // Source, e.g. github:BurntSushi/ripgrep
+	Version     string // Release tag
+	Asset       string // Asset name
+	URL         string // Download URL
+	File        string // Downloaded file
+	InstallPath string // Install directory
+}
+
+// Env returns the environment variables describing an event
+func (c Context) Env(event Event) []string {
+	env := []string{"PYHUB_HOOK_EVENT=" + string(event)}
+	for _, v := range []struct{ name, value string }{
+		{"PYHUB_TOOL", c.Tool},
+		{"PYHUB_SOURCE", c.Source},
+		{"PYHUB_VERSION", c.Version},
+		{"PYHUB_ASSET", c.Asset},
+		{"PYHUB_URL", c.URL},
+		{"PYHUB_FILE", c.File},
+		{"PYHUB_INSTALL_PATH", c.InstallPath},
+	} {
+		if v.value != "" {
+			env = append(env, v.name+"="+v.value)
+		}
+	}
+	return env
+}
+
+// Runner runs the commands configured for each event
+type Runner struct {
+	hooks  map[string][]string
+	Stdout io.Writer
+	Stderr io.Writer
+}
+
+// NewRunner creates a runner for hook commands keyed by event name
+func NewRunner(hooks map[string][]string) *Runner {
+	return &Runner{hooks: hooks, Stdout: os.Stdout, Stderr: os.Stderr}
+}
+
+// Run runs the commands of an event in order, stopping at the first failure. A nil
+// runner runs nothing.
+func (r *Runner) Run(ctx context.Context, event Event, hc Context) error {
+	if r == nil {
+		return nil
+	}
+	for _, command := range r.hooks[string(event)] {
+		cmd := shellCommand(ctx, command)
+		cmd.Env = append(os.Environ(), hc.Env(event)...)
+		cmd.Stdout = r.Stdout
+		cmd.Stderr = r.Stderr
+		if err := cmd.Run(); err != nil {
+			return fmt.Errorf("%s hook %q failed: %w", event, command, err)
+		}
+	}
+	return nil
+}
+
+// shellCommand runs a command line with the platform shell
+func shellCommand(ctx context.Context, command string) *exec.Cmd {
+	if runtime.GOOS == "windows" {
+		return exec.CommandContext(ctx, "cmd", "/C", command)
+	}
+	return exec.CommandContext(ctx, "sh", "-c", command)
+}
@@ -0,0 +1,65 @@
+package hooks
+
+import (
+	"bytes"
+	"context"
+	"runtime"
+	"strings"
+	"testing"
+)
+
+func TestContextEnv(t *testing.T) {
+	env := Context{Tool: "rg", Version: "14.1.0", File: "/tmp/rg.tar.gz"}.Env(PostVerify)
+
+	want := []string{"PYHUB_HOOK_EVENT=post-verify", "PYHUB_TOOL=rg", "PYHUB_VERSION=14.1.0", "PYHUB_FILE=/tmp/rg.tar.gz"}
+	if strings.Join(env, ",") != strings.Join(want, ",") {
+		t.Errorf("Env() = %v, want %v", env, want)
+	}
+}
+
+func TestRunnerRun(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("hook commands in this test use sh syntax")
+	}
+
+	var out bytes.Buffer
+	runner := NewRunner(map[string][]string{
+		"pre-download": {`echo "$PYHUB_HOOK_EVENT $PYHUB_TOOL"`, "echo second"},
+		"post-verify":  {"exit 3", "echo unreachable"},
+	})
+	runner.Stdout = &out
+
+	ctx := context.Background()
+	if err := runner.Run(ctx, PreDownload, Context{Tool: "rg"}); err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+	if out.String() != "pre-download rg\nsecond\n" {
+		t.Errorf("Unexpected hook output %q", out.String())
+	}
+
+	out.Reset()
+	if err := runner.Run(ctx, PostVerify, Context{}); err == nil {
+		t.Error("Expected an error from a failing hook")
+	}
+	if out.Len() != 0 {
+		t.Errorf("Expected hooks after a failure to be skipped, got %q", out.String())
+	}
+
+	if err := runner.Run(ctx, PostInstall, Context{}); err != nil {
+		t.Errorf("Expected no error for an event without hooks, got %v", err)
+	}
+
+	var none *Runner
+	if err := none.Run(ctx, PreDownload, Context{}); err != nil {
+		t.Errorf("Expected a nil runner to run nothing, got %v", err)
+	}
+}
+
+func TestBlocking(t *testing.T) {
+	if !PreDownload.Blocking() || !PostVerify.Blocking() {
+		t.Error("Expected pre-download and post-verify to block")
+	}
+	if PostInstall.Blocking() || PostUninstall.Blocking() {
+		t.Error("Expected post-install and post-uninstall not to block")
+	}
+}
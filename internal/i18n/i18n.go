@@ -0,0 +1,62 @@
+package i18n
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Supported languages
+const (
+	English = "en"
+	Korean  = "ko"
+)
+
+// catalogs maps a language to its translations, keyed by the English format string.
+// English needs no catalog: untranslated messages are printed as written.
+var catalogs = map[string]map[string]string{
+	Korean: korean,
+}
+
+// current is the language messages are translated to
+var current = English
+
+// Detect returns the language to use: lang if given, otherwise the first locale set in
+// LC_ALL, LC_MESSAGES or LANG (e.g. ko_KR.UTF-8) or the Windows display language,
+// defaulting to English
+func Detect(lang string, getenv func(string) string) (string, error) {
+	if lang != "" {
+		lang = strings.ToLower(lang)
+		if lang != English && catalogs[lang] == nil {
+			return "", fmt.Errorf("unsupported language %q (supported: en, ko)", lang)
+		}
+		return lang, nil
+	}
+
+	locale := systemLocale()
+	for _, name := range []string{"LC_ALL", "LC_MESSAGES", "LANG"} {
+		if value := getenv(name); value != "" {
+			locale = value
+			break
+		}
+	}
+	if strings.HasPrefix(strings.ToLower(locale), Korean) {
+		return Korean, nil
+	}
+	return English, nil
+}
+
+// SetLanguage selects the language of translated messages
+func SetLanguage(lang string) {
+	current = lang
+}
+
+// T translates a format string to the current language and formats it with args
+func T(format string, args ...interface{}) string {
+	if translated, ok := catalogs[current][format]; ok {
+		format = translated
+	}
+	if len(args) == 0 {
+		return format
+	}
+	return fmt.Sprintf(format, args...)
+}
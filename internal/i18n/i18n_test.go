@@ -0,0 +1,73 @@
+package i18n
+
+import (
+	"regexp"
+	"strings"
+	"testing"
+)
+
+func TestDetect(t *testing.T) {
+	tests := []struct {
+		name    string
+		lang    string
+		env     map[string]string
+		want    string
+		wantErr bool
+	}{
+		{"Default", "", nil, English, false},
+		{"Korean LANG", "", map[string]string{"LANG": "ko_KR.UTF-8"}, Korean, false},
+		{"LC_ALL wins", "", map[string]string{"LC_ALL": "en_US.UTF-8", "LANG": "ko_KR.UTF-8"}, English, false},
+		{"Flag wins", "ko", map[string]string{"LANG": "en_US.UTF-8"}, Korean, false},
+		{"Flag case", "EN", nil, English, false},
+		{"Unsupported", "fr", nil, "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Detect(tt.lang, func(name string) string { return tt.env[name] })
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Detect() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("Detect() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestT(t *testing.T) {
+	defer SetLanguage(English)
+
+	if got := T("Installing %s...\n", "rg"); got != "Installing rg...\n" {
+		t.Errorf("Unexpected English message: %q", got)
+	}
+	if got := T("100% done"); got != "100% done" {
+		t.Errorf("Messages without arguments must not be formatted, got %q", got)
+	}
+
+	SetLanguage(Korean)
+	if got := T("Installing %s...\n", "rg"); got != "rg 설치 중...\n" {
+		t.Errorf("Unexpected Korean message: %q", got)
+	}
+	if got := T("Untranslated %s", "message"); got != "Untranslated message" {
+		t.Errorf("Untranslated messages should fall back to English, got %q", got)
+	}
+}
+
+// TestCatalogVerbs checks that translations keep the formatting verbs of the original,
+// in order, so arguments are not misplaced
+func TestCatalogVerbs(t *testing.T) {
+	verbs := regexp.MustCompile(`%[-+# 0-9.]*[a-zA-Z%]`)
+	for lang, catalog := range catalogs {
+		for key, translated := range catalog {
+			want := strings.Join(verbs.FindAllString(key, -1), " ")
+			got := strings.Join(verbs.FindAllString(translated, -1), " ")
+			if got != want {
+				t.Errorf("%s: %q has verbs %q, want %q", lang, translated, got, want)
+			}
+			if strings.HasSuffix(key, "\n") != strings.HasSuffix(translated, "\n") {
+				t.Errorf("%s: %q must keep the trailing newline of %q", lang, translated, key)
+			}
+		}
+	}
+}
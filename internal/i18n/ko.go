@@ -0,0 +1,166 @@
+package i18n
+
+// korean is the Korean message catalog
+var korean = map[string]string{
+	// Common
+	"Error: %v\n":   "오류: %v\n",
+	"Note: %s\n":    "참고: %s\n",
+	"Warning: %v\n": "경고: %v\n",
+
+	// install
+	"Installing %s...\n":                                                 "%s 설치 중...\n",
+	"Found release: %s\n":                                                "릴리스 찾음: %s\n",
+	"Found asset: %s (%d bytes)\n":                                       "파일 찾음: %s (%d 바이트)\n",
+	"Found asset: %s %s (%d bytes)\n":                                    "파일 찾음: %s %s (%d 바이트)\n",
+	"%s %s %s is already installed\n":                                    "%s %s %s 은(는) 이미 설치되어 있습니다\n",
+	"Served by %s\n":                                                     "설치 소스: %s\n",
+	"Summary:":                                                           "요약:",
+	"  %s %s %s (already installed)\n":                                   "  %s %s %s (이미 설치됨)\n",
+	"%s Installation completed to: %s\n":                                 "%s 설치 완료: %s\n",
+	"Using writable directory: %s\n":                                     "쓰기 가능한 디렉터리 사용: %s\n",
+	"Running as root; installing system-wide to %s\n":                    "root 권한으로 실행 중입니다. 시스템 전체 경로 %s 에 설치합니다\n",
+	"Running as administrator; installing system-wide to %s\n":           "관리자 권한으로 실행 중입니다. 시스템 전체 경로 %s 에 설치합니다\n",
+	"Installing the Windows builds into %s\n":                            "Windows 빌드를 %s 에 설치하는 중...\n",
+	"Running in a %s container; installing to %s\n":                      "%s 컨테이너에서 실행 중입니다. %s 에 설치합니다\n",
+	"Warning: %s: %v; trying %s\n":                                       "경고: %s: %v; %s 시도 중\n",
+	"Warning: download from %s failed: %v; trying %s\n":                  "경고: %s 에서 다운로드 실패: %v; %s 시도 중\n",
+	"Warning: %s %s is %s\n":                                             "경고: %s %s 은(는) %s 상태입니다\n",
+	"Note: %s %s has no release assets, installing its source archive\n": "참고: %s %s 에 릴리스 파일이 없어 소스 아카이브를 설치합니다\n",
+	"Note: %d assets match %s equally, using %s (select one with --asset or --interactive)\n": "참고: 파일 %d개가 %s 에 똑같이 맞아 %s 을(를) 사용합니다 (--asset 또는 --interactive로 선택)\n",
+	"Found signature file, verifying...":                                                      "서명 파일을 찾았습니다. 검증 중...",
+	"Skipping %s %s: no known GitHub repository\n":                                            "%s %s 건너뜀: 알려진 GitHub 저장소가 없습니다\n",
+	"Would install %d tools; %d entries skipped\n":                                            "도구 %d개를 설치하고 항목 %d개를 건너뜁니다\n",
+	"Note: %d Brewfile entries were skipped; add aliases for them to import them\n":           "참고: Brewfile 항목 %d개를 건너뛰었습니다. 가져오려면 별칭을 추가하세요\n",
+	"Created %s running %s\n":                                                                 "%s 생성됨: %s 실행\n",
+	"Removed alias %s\n":                                                                      "별칭 %s 제거됨\n",
+	"No aliases; create one with: pyhub-installer alias NAME TOOL [-- ARGS...]":               "별칭이 없습니다. 다음 명령으로 만드세요: pyhub-installer alias NAME TOOL [-- ARGS...]",
+	"%s is already using %s\n":                                                                "%s은(는) 이미 %s을(를) 사용 중입니다\n",
+	"%s %s now uses %s\n":                                                                     "%s %s이(가) 이제 %s을(를) 사용합니다\n",
+	"Downloading %d assets of %s %s...\n":                                                     "에셋 %d개 다운로드 중: %s %s...\n",
+	"checksum":                                                                                "체크섬",
+	"signature":                                                                               "서명",
+	"  Gap: %s\n":                                                                             "  누락: %s\n",
+	"%s All %d assets match their checksums\n":                                                "%s 에셋 %d개 모두 체크섬이 일치합니다\n",
+	"Warning: cache server unavailable, using GitHub directly: %v\n":                          "경고: 캐시 서버를 사용할 수 없어 GitHub에 직접 연결합니다: %v\n",
+	"Serving the release cache on %s (cache: %s)\n":                                           "%s에서 릴리스 캐시를 제공합니다 (캐시: %s)\n",
+	"No installed tools to update":                                                            "업데이트할 설치된 도구가 없습니다",
+	"Next check at %s\n":                                                                      "다음 확인: %s\n",
+	"%s %s: %s -> %s available\n":                                                             "%s %s: %s -> %s 업데이트 가능\n",
+	"Updating %s: %s -> %s\n":                                                                 "%s 업데이트 중: %s -> %s\n",
+	"%s %s: update to %s failed\n":                                                            "%s %s: %s(으)로 업데이트 실패\n",
+	"%s %s updated to %s\n":                                                                   "%s %s이(가) %s(으)로 업데이트되었습니다\n",
+	"Wrote %s\n":                                                                              "%s 작성됨\n",
+	"%s The agent runs every %s\n":                                                            "%s 에이전트가 %s마다 실행됩니다\n",
+	"%s The agent schedule was removed\n":                                                     "%s 에이전트 일정이 제거되었습니다\n",
+	"%d tools updated":                                                                        "도구 %d개 업데이트됨",
+	"%d tool updates available":                                                               "도구 업데이트 %d개 사용 가능",
+	"%s %s and %s have the same files\n":                                                      "%s %s와 %s의 파일이 같습니다\n",
+	"%d added, %d removed, %d changed\n":                                                      "%d개 추가, %d개 삭제, %d개 변경\n",
+	"Total managed by the installer: %s\n":                                                    "설치 프로그램이 관리하는 전체 용량: %s\n",
+	"\"pyhub-installer clean\" would reclaim %s:\n":                                           "\"pyhub-installer clean\"으로 %s 확보 가능:\n",
+	"  %s in superseded versions\n":                                                           "  이전 버전 %s\n",
+	"  %s in backups older than %d days\n":                                                    "  %s: %d일 지난 백업\n",
+	"  %s in the cache (also removed by \"pyhub-installer cache prune\")\n":                   "  캐시 %s (\"pyhub-installer cache prune\"으로도 정리됨)\n",
+	"%s is not writable. Install system-wide with pkexec (asks for an administrator password)?": "%s에 쓸 수 없습니다. pkexec로 시스템 전체에 설치할까요? (관리자 비밀번호를 묻습니다)",
+	"Elevation with pkexec was not authorized":                                                  "pkexec 권한 상승이 승인되지 않았습니다",
+	"%s Installed to: %s\n": "%s 설치 위치: %s\n",
+	"%s Linked %s\n":        "%s 링크 생성: %s\n",
+	"Warning: %s exists and is not managed by the store; not linked\n":      "경고: %s이(가) 이미 있고 스토어가 관리하지 않으므로 링크하지 않습니다\n",
+	"\nInterrupted; cleaning up (press Ctrl+C again to quit immediately)\n": "\n중단되었습니다. 정리하는 중입니다 (즉시 종료하려면 Ctrl+C를 한 번 더 누르세요)\n",
+	"Asset scores are not available: %v\n":                                  "에셋 점수를 계산할 수 없습니다: %v\n",
+	"Asset scores for %s:\n":                                                "%s 플랫폼의 에셋 점수:\n",
+	"Reason: %s\n":                                                          "선택 이유: %s\n",
+	"keywords: %s":                                                          "키워드: %s",
+	"no platform keywords":                                                  "플랫폼 키워드 없음",
+	"built for another architecture than %s":                                "%s이(가) 아닌 다른 아키텍처용 빌드",
+	"it matches the asset pattern %q; platform scores were not used":        "에셋 패턴 %q와 일치합니다. 플랫폼 점수는 사용되지 않았습니다",
+	"the release has no assets besides its source archive":                  "릴리스에 소스 아카이브 외의 에셋이 없습니다",
+	"it was selected from the list":                                         "목록에서 선택되었습니다",
+	"it has the best score":                                                 "가장 높은 점수입니다",
+	"%d assets share the top score %d and the first in release order was used; select one with --asset or --interactive": "%d개의 에셋이 최고 점수 %d로 같아 릴리스 순서상 첫 번째 에셋을 사용했습니다. --asset 또는 --interactive로 선택하세요",
+	"it has the top score %d and, unlike the assets with the same score, runs on %s":                                     "최고 점수 %d이며, 같은 점수의 다른 에셋과 달리 %s에서 실행됩니다",
+	"it has the top score %d for %s":                 "최고 점수 %d (%s 기준)",
+	"Upgrading %s: %s -> %s\n":                       "%s 업그레이드 중: %s -> %s\n",
+	"%s %s %s is up to date\n":                       "%s %s %s은(는) 최신 버전입니다\n",
+	"%s %s %s is pinned or has no source; skipped\n": "%s %s %s은(는) 고정되었거나 소스가 없어 건너뜁니다\n",
+	"%s Upgraded %d tools\n":                         "%s 도구 %d개를 업그레이드했습니다\n",
+	"%s Uninstalled %s %s\n":                         "%s %s %s 제거됨\n",
+	"Note: the files of %s were not recorded by the installer version that installed it; check %s for leftovers\n": "참고: %s의 파일은 설치 당시의 설치 프로그램 버전이 기록하지 않았습니다. %s에 남은 파일이 있는지 확인하세요\n",
+	"No tools installed": "설치된 도구가 없습니다",
+	"No repositories with binary releases found for %q\n": "%q에 대해 바이너리 릴리스가 있는 저장소를 찾지 못했습니다\n",
+	"URL: %s\n":                       "URL: %s\n",
+	"Release notes:":                  "릴리스 노트:",
+	"  ... (show all with --notes)\n": "  ... (전체 내용은 --notes로 확인)\n",
+	"<- selected for %s":              "<- %s용으로 선택됨",
+	"Showing %d of %d releases; use --limit 0 to show all\n":                                 "릴리스 %d개 표시 (전체 %d개); 모두 보려면 --limit 0 사용\n",
+	"Note: no asset matches %s\n":                                                            "참고: %s 에 맞는 파일이 없습니다\n",
+	"Building %s from source for %s...\n":                                                    "%s 을(를) %s 용으로 소스에서 빌드하는 중...\n",
+	"Note: no asset of %s %s matches the platform, building it from source\n":                "참고: %s %s 에 플랫폼에 맞는 파일이 없어 소스에서 빌드합니다\n",
+	"no asset matches the platform, so the source archive is built with --build-from-source": "플랫폼에 맞는 파일이 없어 --build-from-source 로 소스 아카이브를 빌드합니다",
+	"%s WARNING: --insecure disables TLS certificate verification. Anyone on the network path can replace downloads, checksums and signatures without notice.\n": "%s 경고: --insecure 는 TLS 인증서 검증을 끕니다. 네트워크 경로상의 누구든 다운로드, 체크섬, 서명을 알리지 않고 바꿔치기할 수 있습니다.\n",
+	"Note: %s is in use; the old version will be removed once it exits\n":                                                                                        "참고: %s 파일이 사용 중이므로 이전 버전은 프로그램이 종료된 후 삭제됩니다\n",
+	"Request failed (%s); retrying in %s (attempt %d of %d)\n":                                                                                                   "요청 실패 (%s); %s 후 다시 시도합니다 (%d/%d회)\n",
+	"Warning: %s failed: %v; trying mirror %s\n":                                                                                                                 "경고: %s 실패: %v; 미러 %s 시도 중\n",
+	"Resuming download of %s at %d bytes\n":                                                                                                                      "%s 다운로드를 %d바이트부터 이어받습니다\n",
+	"Note: no checksum known for %s, downloading from GitHub instead of a mirror\n":                                                                              "참고: %s의 체크섬을 알 수 없어 미러 대신 GitHub에서 다운로드합니다\n",
+	"Warning: mirror %s failed: %v\n":                                                                                                                            "경고: 미러 %s 실패: %v\n",
+	"Waiting for another pyhub-installer process to release %s...\n":                                                                                             "다른 pyhub-installer 프로세스가 %s을(를) 해제하기를 기다리는 중...\n",
+	"--insecure cannot be combined with --verify or --signature, as signatures fetched without TLS verification prove nothing":                                   "--insecure는 --verify 또는 --signature와 함께 사용할 수 없습니다. TLS 검증 없이 받은 서명은 아무것도 증명하지 못합니다",
+	"Verifying locked checksum...":                                                                                                                               "잠금 파일의 체크섬 검증 중...",
+	"Warning: %s is locked to %s, which %s no longer allows; ignoring the lock\n":                                                                                "경고: %s은(는) %s(으)로 잠겨 있지만 %s에 더 이상 맞지 않아 잠금을 무시합니다\n",
+	"Froze %d tools to %s and %s\n":                                                                                                                              "도구 %d개를 %s 및 %s에 고정했습니다\n",
+	"Verifying published checksum...":                                                                                                                            "게시된 체크섬 검증 중...",
+	"No signature file found, skipping verification":                                                                                                             "서명 파일이 없어 검증을 건너뜁니다",
+	"Warning: signature verification failed: %v\n":                                                                                                               "경고: 서명 검증 실패: %v\n",
+	"Note: Not an archive or extraction failed: %v\n":                                                                                                            "참고: 아카이브가 아니거나 압축 해제에 실패했습니다: %v\n",
+	"Warning: failed to set permissions: %v\n":                                                                                                                   "경고: 권한 설정 실패: %v\n",
+	"Warning: failed to record installation: %v\n":                                                                                                               "경고: 설치 기록 실패: %v\n",
+	"Warning: failed to read installed tools: %v\n":                                                                                                              "경고: 설치된 도구 목록을 읽지 못했습니다: %v\n",
+
+	// download
+	"Downloading %s...\n":                                      "%s 다운로드 중...\n",
+	"%s Downloaded to: %s\n":                                   "%s 다운로드 완료: %s\n",
+	"Verifying signature...":                                   "서명 검증 중...",
+	"Extracting archive...":                                    "압축 해제 중...",
+	"Removing archive: %s\n":                                   "아카이브 삭제: %s\n",
+	"Warning: failed to remove archive: %v\n":                  "경고: 아카이브 삭제 실패: %v\n",
+	"Permission denied for %s, using writable directory: %s\n": "%s 에 쓸 권한이 없어 쓰기 가능한 디렉터리를 사용합니다: %s\n",
+
+	// sync
+	"No tools declared in %s\n": "%s 에 선언된 도구가 없습니다\n",
+	"%s %d tools match %s\n":    "%s 도구 %d개가 %s 와 일치합니다\n",
+
+	// serve
+	"Serving the installer API on http://%s\n": "설치 API 제공 중: http://%s\n",
+	"Token: %s\n": "토큰: %s\n",
+
+	// manifest validate
+	"%s %s is valid (%d tools)\n": "%s %s 이(가) 올바릅니다 (도구 %d개)\n",
+
+	// releases
+	"No releases found for %s\n": "%s 의 릴리스가 없습니다\n",
+
+	// clean
+	"✓ Nothing to clean": "✓ 정리할 항목이 없습니다",
+	"✓ Nothing to prune": "✓ 제거할 캐시 항목이 없습니다",
+	"Would reclaim %s\n": "확보 예정: %s\n",
+	"✓ Reclaimed %s\n":   "✓ 확보한 용량: %s\n",
+
+	// doctor
+	"  Fix: %s\n": "  해결 방법: %s\n",
+	"%s No problems found that prevent installs\n":                      "%s 설치를 막는 문제가 없습니다\n",
+	"Install directory candidates, in the order they are tried:":        "설치 디렉터리 후보 (시도 순서):",
+	"No usable directory found; pass --output to choose one explicitly": "사용 가능한 디렉터리가 없습니다. --output으로 직접 지정하세요",
+	"Selected: %s\n": "선택됨: %s\n",
+	"Note: %s is not in PATH; installed tools will not be found by name\n": "참고: %s 이(가) PATH에 없어 설치한 도구를 이름으로 실행할 수 없습니다\n",
+
+	// info
+	"Name: %s\n":                         "이름: %s\n",
+	"Installed: no":                      "설치됨: 아니요",
+	"Installed: %s in %s (up to date)\n": "설치됨: %s (%s, 최신)\n",
+	"Installed: %s in %s\n":              "설치됨: %s (%s)\n",
+	"Assets (%d):\n":                     "에셋 (%d개):\n",
+
+	// plugins
+	"No plugins found; add executables named pyhub-installer-<name> to PATH": "플러그인이 없습니다. pyhub-installer-<이름> 실행 파일을 PATH에 추가하세요",
+}
@@ -0,0 +1,8 @@
+//go:build !windows
+
+package i18n
+
+// systemLocale returns "", as the locale variables are authoritative outside Windows
+func systemLocale() string {
+	return ""
+}
@@ -0,0 +1,15 @@
+//go:build windows
+
+package i18n
+
+import "golang.org/x/sys/windows"
+
+// systemLocale returns the user's preferred display language, e.g. ko-KR, as Windows
+// does not set LANG
+func systemLocale() string {
+	languages, err := windows.GetUserPreferredUILanguages(windows.MUI_LANGUAGE_NAME)
+	if err != nil || len(languages) == 0 {
+		return ""
+	}
+	return languages[0]
+}
@@ -0,0 +1,33 @@
+package install
+
+import (
+	"path/filepath"
+	"strings"
+)
+
+// usersSID is the well-known SID of the built-in Users group, independent of the UI language
+const usersSID = "S-1-5-32-545"
+
+// aclGrant returns the icacls grant giving Users read and execute access.
+// Directories grant it with object and container inheritance so new files pick it up.
+func aclGrant(isDir bool) string {
+	if isDir {
+		return "*" + usersSID + ":(OI)(CI)(RX)"
+	}
+	return "*" + usersSID + ":(RX)"
+}
+
+// isUnderAny checks if path is one of the roots or inside one (case-insensitive, as on Windows)
+func isUnderAny(path string, roots []string) bool {
+	path = strings.ToLower(filepath.Clean(path))
+	for _, root := range roots {
+		if root == "" {
+			continue
+		}
+		root = strings.ToLower(filepath.Clean(root))
+		if path == root || strings.HasPrefix(path, root+string(filepath.Separator)) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,8 @@
+//go:build !windows
+
+package install
+
+// setACL is a no-op outside Windows; Unix permissions are set with chmod
+func setACL(path string, isDir bool) error {
+	return nil
+}
@@ -0,0 +1,39 @@
+//go:build windows
+
+package install
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+
+	"golang.org/x/sys/windows"
+)
+
+// protectedRoots returns directories whose ACLs are locked down by Windows
+func protectedRoots() []string {
+	return []string{
+		os.Getenv("ProgramFiles"),
+		os.Getenv("ProgramFiles(x86)"),
+		os.Getenv("ProgramW6432"),
+		os.Getenv("ProgramData"),
+		os.Getenv("SystemRoot"),
+	}
+}
+
+// setACL gives Users read/execute access to a file or directory installed into a protected location.
+// chmod is a no-op on Windows, so without this an elevated install can leave files unusable by
+// non-admin users when inheritance was broken on the parent directory.
+func setACL(path string, isDir bool) error {
+	if !isUnderAny(path, protectedRoots()) || !windows.GetCurrentProcessToken().IsElevated() {
+		return nil
+	}
+
+	// Re-enable inheritance from the parent, then add the explicit grant
+	cmd := exec.Command("icacls", path, "/inheritance:e", "/grant", aclGrant(isDir), "/Q")
+	if output, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("icacls failed for %s: %v: %s", path, err, strings.TrimSpace(string(output)))
+	}
+	return nil
+}
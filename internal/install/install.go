@@ -7,6 +7,9 @@ import (
 	"runtime"
 	"strconv"
 	"strings"
+
+	"github.com/pyhub-kr/pyhub-installer/internal/replace"
+	"github.com/pyhub-kr/pyhub-installer/internal/wsl"
 )
 
 // Installer handles file installation and permissions
@@ -38,11 +41,13 @@ func (i *Installer) Install() error {
 		return fmt.Errorf("failed to copy file: %w", err)
 	}
 
-	// Set permissions (Unix only)
+	// Set permissions (Unix mode bits, ACLs for protected Windows directories)
 	if runtime.GOOS != "windows" {
 		if err := i.setPermissions(); err != nil {
 			return fmt.Errorf("failed to set permissions: %w", err)
 		}
+	} else if err := setACL(i.DestPath, false); err != nil {
+		return fmt.Errorf("failed to set permissions: %w", err)
 	}
 
 	fmt.Printf("✓ Installed to: %s\n", i.DestPath)
@@ -82,6 +87,19 @@ func (i *Installer) copyFile() error {
 	}
 	defer source.Close()
 
+	// Installing a file in place only sets its permissions; creating the destination
+	// would truncate the source
+	if srcInfo, err := source.Stat(); err == nil {
+		if destInfo, err := os.Stat(i.DestPath); err == nil && os.SameFile(srcInfo, destInfo) {
+			return nil
+		}
+	}
+
+	// Move a running executable out of the way so it can be replaced
+	if err := replace.Prepare(i.DestPath); err != nil {
+		return err
+	}
+
 	dest, err := os.Create(i.DestPath)
 	if err != nil {
 		return err
@@ -305,6 +323,11 @@ func (s *DirectoryInstallStrategy) Install(sourceDir string, programName string)
 		return fmt.Errorf("failed to create installation directory: %w", err)
 	}
 	
+	// Grant Users access on protected Windows directories; copied files inherit it
+	if err := setACL(s.InstallPath, true); err != nil {
+		return fmt.Errorf("failed to set permissions: %w", err)
+	}
+	
 	// Copy all files from source to install path
 	err := filepath.Walk(sourceDir, func(path string, info os.FileInfo, err error) error {
 		if err != nil {
@@ -384,6 +407,11 @@ func (s *SingleFileInstallStrategy) Install(sourceFile string, programName strin
 		return fmt.Errorf("failed to create installation directory: %w", err)
 	}
 	
+	// Grant Users access on protected Windows directories; copied files inherit it
+	if err := setACL(s.InstallPath, true); err != nil {
+		return fmt.Errorf("failed to set permissions: %w", err)
+	}
+	
 	// Determine destination file name
 	fileName := filepath.Base(sourceFile)
 	destFile := filepath.Join(s.InstallPath, fileName)
@@ -427,6 +455,11 @@ func copyFileWithPermissions(src, dst string, mode os.FileMode) error {
 	}
 	defer input.Close()
 	
+	// Move a running executable out of the way so it can be replaced
+	if err := replace.Prepare(dst); err != nil {
+		return err
+	}
+	
 	output, err := os.Create(dst)
 	if err != nil {
 		return err
@@ -441,6 +474,114 @@ func copyFileWithPermissions(src, dst string, mode os.FileMode) error {
 	return os.Chmod(dst, mode)
 }
 
+// PathCandidate describes a directory considered by FindWritableInstallPath
+type PathCandidate struct {
+	Path             string
+	InPath           bool   // Listed in the PATH environment variable
+	Writable         bool   // Writable, or can be created by the current user
+	LanguageSpecific bool   // Belongs to a language toolchain (tried last)
+	Fallback         bool   // Well-known directory tried when no PATH entry is usable
+	SkipReason       string // Why the directory is never used (empty if eligible)
+	Chosen           bool   // The directory FindWritableInstallPath would return
+}
+
+// ExplainInstallPath lists every candidate directory in the order FindWritableInstallPath tries them
+func ExplainInstallPath() []PathCandidate {
+	var candidates []PathCandidate
+	seen := make(map[string]bool)
+	chosen := false
+
+	consider := func(c PathCandidate) {
+		if seen[c.Path] {
+			return
+		}
+		seen[c.Path] = true
+		if !chosen && c.SkipReason == "" && c.Writable {
+			c.Chosen = true
+			chosen = true
+		}
+		candidates = append(candidates, c)
+	}
+
+	// PATH directories in priority order
+	for _, dir := range getPathDirectories() {
+		c := PathCandidate{
+			Path:             dir,
+			InPath:           true,
+			Writable:         isDirectoryWritable(dir),
+			LanguageSpecific: isLanguageSpecificPath(dir),
+		}
+		if isIDESpecificPath(strings.ToLower(filepath.ToSlash(dir))) {
+			c.SkipReason = "IDE-specific directory"
+		}
+		consider(c)
+	}
+
+	// PATH directories that are filtered out up front
+	for _, dir := range filepath.SplitList(os.Getenv("PATH")) {
+		if dir == "" {
+			continue
+		}
+		dir = filepath.Clean(dir)
+		if isProblematicPath(dir) {
+			consider(PathCandidate{Path: dir, InPath: true, SkipReason: "system directory (typically read-only)"})
+		} else if isWindowsDrivePath(dir) {
+			consider(PathCandidate{Path: dir, InPath: true, SkipReason: "Windows drive mounted by WSL"})
+		}
+	}
+
+	// Well-known directories, created on demand
+	for _, dir := range getFallbackDirectories() {
+		consider(PathCandidate{
+			Path:     dir,
+			InPath:   IsPathInEnv(dir),
+			Writable: isDirectoryCreatable(dir),
+			Fallback: true,
+		})
+	}
+
+	return candidates
+}
+
+// FindWritableInstallPath finds a writable directory for executables, preferring directories in PATH
+func FindWritableInstallPath() (string, error) {
+	// Candidates after the chosen one are tried in case it cannot be created after all
+	var lastErr error
+	for _, c := range ExplainInstallPath() {
+		if c.SkipReason != "" || !c.Writable {
+			continue
+		}
+		if err := os.MkdirAll(c.Path, 0755); err != nil {
+			lastErr = fmt.Errorf("failed to create install directory %s: %w", c.Path, err)
+			continue
+		}
+		return c.Path, nil
+	}
+
+	if lastErr != nil {
+		return "", lastErr
+	}
+	return "", fmt.Errorf("no writable installation directory found")
+}
+
+// SystemInstallPath returns the system-wide directory for executables: /usr/local/bin,
+// or the installer's directory under Program Files on Windows
+func SystemInstallPath() string {
+	if runtime.GOOS == "windows" {
+		programFiles := os.Getenv("ProgramFiles")
+		if programFiles == "" {
+			programFiles = `C:\Program Files`
+		}
+		return filepath.Join(programFiles, "pyhub-installer")
+	}
+	return "/usr/local/bin"
+}
+
+// Writable reports whether a directory is writable, or can be created, by the current user
+func Writable(dir string) bool {
+	return isDirectoryCreatable(dir)
+}
+
 // getPathDirectories returns directories from PATH environment variable in priority order
 func getPathDirectories() []string {
 	pathEnv := os.Getenv("PATH")
@@ -471,7 +612,7 @@ func getPathDirectories() []string {
 		dir = filepath.Clean(dir)
 		
 		// Skip problematic directories
-		if isProblematicPath(dir) {
+		if isProblematicPath(dir) || isWindowsDrivePath(dir) {
 			continue
 		}
 		
@@ -568,6 +709,26 @@ func isDirectoryWritable(dir string) bool {
 	return true
 }
 
+// isDirectoryCreatable checks if a directory is writable or could be created by the current user
+func isDirectoryCreatable(dir string) bool {
+	for {
+		if _, err := os.Stat(dir); err == nil {
+			return isDirectoryWritable(dir)
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return false
+		}
+		dir = parent
+	}
+}
+
+// isWindowsDrivePath reports whether a PATH entry is a Windows directory added to PATH
+// by WSL, which is no place for Linux binaries
+func isWindowsDrivePath(dir string) bool {
+	return wsl.IsWindowsPath(dir) && wsl.Detect()
+}
+
 // isProblematicPath checks if a path should be skipped
 func isProblematicPath(dir string) bool {
 	// Skip empty or current directory
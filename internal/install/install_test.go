@@ -130,6 +130,29 @@ func TestInstallDirectory(t *testing.T) {
 	}
 }
 
+func TestInstallDirectoryInPlace(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "bin", "tool")
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte("binary"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	// Extracted files are installed onto themselves to set their permissions
+	if err := NewInstaller(dir, dir, "755").InstallDirectory(); err != nil {
+		t.Fatalf("InstallDirectory failed: %v", err)
+	}
+	content, err := os.ReadFile(path)
+	if err != nil || string(content) != "binary" {
+		t.Errorf("Expected the file to be kept, got %q, %v", content, err)
+	}
+	if info, err := os.Stat(path); runtime.GOOS != "windows" && (err != nil || info.Mode().Perm() != 0755) {
+		t.Errorf("Expected mode 0755, got %v, %v", info.Mode(), err)
+	}
+}
+
 func TestParseChmod(t *testing.T) {
 	installer := &Installer{}
 	
@@ -689,4 +712,115 @@ func TestWindowsAppDataPaths(t *testing.T) {
 	}
 	
 	t.Logf("Windows fallback paths: %v", fallbacks)
-}
\ No newline at end of file
+}
+
+// TestExplainInstallPath tests that candidates are reported with the chosen directory
+func TestExplainInstallPath(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("Skipping Unix PATH layout test on Windows")
+	}
+
+	originalPath := os.Getenv("PATH")
+	defer os.Setenv("PATH", originalPath)
+
+	tempDir := t.TempDir()
+	missingDir := filepath.Join(tempDir, "missing")
+	writableDir := filepath.Join(tempDir, "bin")
+	if err := os.MkdirAll(writableDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	os.Setenv("PATH", strings.Join([]string{missingDir, writableDir, "/usr/sbin"}, ":"))
+
+	candidates := ExplainInstallPath()
+
+	var chosen []string
+	var skippedSbin bool
+	for _, c := range candidates {
+		if c.Chosen {
+			chosen = append(chosen, c.Path)
+		}
+		if c.Path == "/usr/sbin" && c.SkipReason != "" {
+			skippedSbin = true
+		}
+		if c.Path == missingDir && c.Writable {
+			t.Errorf("Missing PATH directory should not be writable")
+		}
+	}
+
+	if len(chosen) != 1 || chosen[0] != writableDir {
+		t.Errorf("Expected %s to be the only chosen directory, got %v", writableDir, chosen)
+	}
+	if !skippedSbin {
+		t.Error("Expected /usr/sbin to be reported as skipped")
+	}
+
+	path, err := FindWritableInstallPath()
+	if err != nil {
+		t.Fatalf("FindWritableInstallPath failed: %v", err)
+	}
+	if path != writableDir {
+		t.Errorf("FindWritableInstallPath = %s, want %s", path, writableDir)
+	}
+}
+
+// TestIsDirectoryCreatable tests detection of directories that can be created
+func TestIsDirectoryCreatable(t *testing.T) {
+	tempDir := t.TempDir()
+
+	if !isDirectoryCreatable(filepath.Join(tempDir, "a", "b")) {
+		t.Error("Nested directory under temp dir should be creatable")
+	}
+	if _, err := os.Stat(filepath.Join(tempDir, "a")); !os.IsNotExist(err) {
+		t.Error("isDirectoryCreatable should not create directories")
+	}
+}
+
+// TestSystemInstallPath tests the system-wide directory used by --system
+func TestSystemInstallPath(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Setenv("ProgramFiles", `D:\Programs`)
+		if got := SystemInstallPath(); got != `D:\Programs\pyhub-installer` {
+			t.Errorf("SystemInstallPath() = %s", got)
+		}
+		return
+	}
+	if got := SystemInstallPath(); got != "/usr/local/bin" {
+		t.Errorf("SystemInstallPath() = %s, want /usr/local/bin", got)
+	}
+}
+
+// TestACLGrant tests the icacls grant strings for files and directories
+func TestACLGrant(t *testing.T) {
+	if got := aclGrant(false); got != "*S-1-5-32-545:(RX)" {
+		t.Errorf("aclGrant(false) = %s", got)
+	}
+	if got := aclGrant(true); got != "*S-1-5-32-545:(OI)(CI)(RX)" {
+		t.Errorf("aclGrant(true) = %s", got)
+	}
+}
+
+// TestIsUnderAny tests protected-root matching
+func TestIsUnderAny(t *testing.T) {
+	root := string(filepath.Separator) + "Program Files"
+	roots := []string{"", root}
+
+	tests := []struct {
+		path     string
+		expected bool
+	}{
+		{root, true},
+		{filepath.Join(root, "tool", "tool.exe"), true},
+		{strings.ToUpper(filepath.Join(root, "tool")), true},
+		{root + " (x86)", false},
+		{filepath.Join(string(filepath.Separator)+"Users", "tool"), false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			if got := isUnderAny(tt.path, roots); got != tt.expected {
+				t.Errorf("isUnderAny(%s) = %v, want %v", tt.path, got, tt.expected)
+			}
+		})
+	}
+}
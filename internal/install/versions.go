@@ -0,0 +1,83 @@
+package install
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"time"
+
+	"github.com/pyhub-kr/pyhub-installer/internal/shim"
+)
+
+// VersionedName returns the name an executable is installed under to keep versions
+// side by side: the version, without a leading v, follows the name and precedes the
+// extension, e.g. terraform-1.8.0 or terraform-1.8.0.exe
+func VersionedName(name, version string) string {
+	version = strings.ReplaceAll(strings.TrimPrefix(version, "v"), "/", "-")
+	stem, ext := splitExecutable(name)
+	return stem + "-" + version + ext
+}
+
+// splitExecutable splits the extension that marks an executable, such as .exe, off a
+// file name; other extensions belong to the name
+func splitExecutable(name string) (stem, ext string) {
+	ext = filepath.Ext(name)
+	if runtime.GOOS != "windows" && !strings.EqualFold(ext, ".exe") {
+		return name, ""
+	}
+	return strings.TrimSuffix(name, ext), ext
+}
+
+// LinkDefault points the unversioned name of an executable in dir at one of its
+// versioned copies there: a relative symbolic link, or a shim on Windows. Links and
+// shims to other versions are replaced; any other file of that name is left alone and
+// reported as an error. It returns the path of the link.
+func LinkDefault(dir, name, versioned string) (string, error) {
+	base, _ := splitExecutable(name)
+	if runtime.GOOS == "windows" {
+		path := filepath.Join(dir, base+".cmd")
+		if !isVersionLink(path, base) {
+			return "", fmt.Errorf("%s exists and is not a link to a version; not linked", path)
+		}
+		return shim.Create(dir, base, filepath.Join(dir, versioned), nil)
+	}
+
+	path := filepath.Join(dir, name)
+	if !isVersionLink(path, base) {
+		return "", fmt.Errorf("%s exists and is not a link to a version; not linked", path)
+	}
+	tmp := fmt.Sprintf("%s.pyhub-new-%d", path, time.Now().UnixNano())
+	if err := os.Symlink(versioned, tmp); err != nil {
+		return "", fmt.Errorf("failed to link %s: %w", name, err)
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		os.Remove(tmp)
+		return "", fmt.Errorf("failed to link %s: %w", name, err)
+	}
+	return path, nil
+}
+
+// isVersionLink reports whether path is missing, or a link or shim to a versioned copy
+// of base
+func isVersionLink(path, base string) bool {
+	info, err := os.Lstat(path)
+	if os.IsNotExist(err) {
+		return true
+	}
+	if err != nil {
+		return false
+	}
+	prefix := base + "-"
+	if info.Mode()&os.ModeSymlink != 0 {
+		target, err := os.Readlink(path)
+		return err == nil && strings.HasPrefix(filepath.Base(target), prefix)
+	}
+	// Shims are small scripts naming their target
+	if !info.Mode().IsRegular() || info.Size() > 4096 {
+		return false
+	}
+	data, err := os.ReadFile(path)
+	return err == nil && strings.Contains(string(data), filepath.Join(filepath.Dir(path), prefix))
+}
@@ -0,0 +1,51 @@
+package install
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+)
+
+func TestVersionedName(t *testing.T) {
+	tests := []struct {
+		name, version, want string
+	}{
+		{"terraform", "v1.8.0", "terraform-1.8.0"},
+		{"terraform.exe", "1.8.0", "terraform-1.8.0.exe"},
+		{"tool", "cli/v2", "tool-cli-v2"},
+	}
+	for _, tt := range tests {
+		if got := VersionedName(tt.name, tt.version); got != tt.want {
+			t.Errorf("VersionedName(%q, %q) = %q, want %q", tt.name, tt.version, got, tt.want)
+		}
+	}
+}
+
+func TestLinkDefault(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("Windows uses shims")
+	}
+	dir := t.TempDir()
+	for _, name := range []string{"tool-1.0.0", "tool-2.0.0", "other"} {
+		os.WriteFile(filepath.Join(dir, name), []byte("#!/bin/sh\n"), 0755)
+	}
+
+	for _, version := range []string{"tool-1.0.0", "tool-2.0.0"} {
+		link, err := LinkDefault(dir, "tool", version)
+		if err != nil {
+			t.Fatalf("LinkDefault(%s) error = %v", version, err)
+		}
+		if target, _ := os.Readlink(link); target != version {
+			t.Errorf("Expected %s to point to %s, got %s", link, version, target)
+		}
+	}
+
+	// Files that are not links to a version are kept
+	if _, err := LinkDefault(dir, "other", "other-1.0.0"); err == nil {
+		t.Error("Expected an error for a file that is not a version link")
+	}
+	if info, err := os.Lstat(filepath.Join(dir, "other")); err != nil || !info.Mode().IsRegular() {
+		t.Errorf("Expected the file to be kept: %v", err)
+	}
+}
@@ -0,0 +1,75 @@
+package lifecycle
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/pyhub-kr/pyhub-installer/internal/semver"
+)
+
+// Statuses of versions that should no longer be installed
+const (
+	Deprecated = "deprecated" // Still works, but will stop being supported
+	Yanked     = "yanked"     // Withdrawn, e.g. for a serious bug or a bad build
+	EOL        = "eol"        // No longer maintained or patched
+)
+
+// Statuses are the supported version statuses
+var Statuses = []string{Deprecated, Yanked, EOL}
+
+// Rule marks the versions of a source with a status
+type Rule struct {
+	Versions string `json:"versions" yaml:"versions"` // Exact tag or semver constraint, e.g. "v1.4.2" or "<2"
+	Status   string `json:"status" yaml:"status"`
+	Message  string `json:"message,omitempty" yaml:"message"` // Why, or what to use instead
+}
+
+// Validate checks that the rule has a known status and a valid version selector
+func (r Rule) Validate() error {
+	if strings.TrimSpace(r.Versions) == "" {
+		return fmt.Errorf("versions is required")
+	}
+	if !slices.Contains(Statuses, r.Status) {
+		return fmt.Errorf("unknown status %q (supported: %s)", r.Status, strings.Join(Statuses, ", "))
+	}
+	if semver.IsConstraint(r.Versions) {
+		if _, err := semver.ParseConstraint(r.Versions); err != nil {
+			return fmt.Errorf("invalid versions %q: %w", r.Versions, err)
+		}
+	}
+	return nil
+}
+
+// Matches reports whether a release tag is selected by the rule. Exact versions match
+// with or without a leading v.
+func (r Rule) Matches(tag string) bool {
+	if !semver.IsConstraint(r.Versions) {
+		return strings.TrimPrefix(r.Versions, "v") == strings.TrimPrefix(tag, "v")
+	}
+	constraint, err := semver.ParseConstraint(r.Versions)
+	if err != nil {
+		return false
+	}
+	v, err := semver.Parse(tag)
+	return err == nil && constraint.Check(v)
+}
+
+// Find returns the first rule matching a release tag, or nil
+func Find(rules []Rule, tag string) *Rule {
+	for i := range rules {
+		if rules[i].Matches(tag) {
+			return &rules[i]
+		}
+	}
+	return nil
+}
+
+// Describe returns the status of the rule with its message, e.g.
+// "yanked: corrupt Windows build"
+func (r Rule) Describe() string {
+	if r.Message == "" {
+		return r.Status
+	}
+	return r.Status + ": " + r.Message
+}
@@ -0,0 +1,75 @@
+package lifecycle
+
+import "testing"
+
+func TestMatches(t *testing.T) {
+	tests := []struct {
+		versions string
+		tag      string
+		want     bool
+	}{
+		{"v1.4.2", "v1.4.2", true},
+		{"1.4.2", "v1.4.2", true},
+		{"v1.4.2", "1.4.2", true},
+		{"v1.4.2", "v1.4.3", false},
+		{"<2", "v1.9.0", true},
+		{"<2", "v2.0.0", false},
+		{">=1.2, <1.4", "v1.3.5", true},
+		{"1.x", "v1.0.1", true},
+		{"<2", "nightly", false},
+	}
+	for _, tt := range tests {
+		rule := Rule{Versions: tt.versions, Status: Deprecated}
+		if got := rule.Matches(tt.tag); got != tt.want {
+			t.Errorf("Rule{%q}.Matches(%q) = %v, want %v", tt.versions, tt.tag, got, tt.want)
+		}
+	}
+}
+
+func TestFind(t *testing.T) {
+	rules := []Rule{
+		{Versions: "v1.4.2", Status: Yanked, Message: "corrupt Windows build"},
+		{Versions: "<2", Status: EOL},
+	}
+	if rule := Find(rules, "v1.4.2"); rule == nil || rule.Status != Yanked {
+		t.Errorf("Find(v1.4.2) = %+v, want the yanked rule", rule)
+	}
+	if rule := Find(rules, "v1.5.0"); rule == nil || rule.Status != EOL {
+		t.Errorf("Find(v1.5.0) = %+v, want the eol rule", rule)
+	}
+	if rule := Find(rules, "v2.1.0"); rule != nil {
+		t.Errorf("Find(v2.1.0) = %+v, want nil", rule)
+	}
+}
+
+func TestValidate(t *testing.T) {
+	valid := []Rule{
+		{Versions: "v1.0.0", Status: Deprecated},
+		{Versions: "<2", Status: EOL, Message: "upgrade to v2"},
+	}
+	for _, rule := range valid {
+		if err := rule.Validate(); err != nil {
+			t.Errorf("Validate(%+v) error = %v", rule, err)
+		}
+	}
+
+	invalid := []Rule{
+		{Versions: "", Status: Deprecated},
+		{Versions: "v1.0.0", Status: "retired"},
+		{Versions: ">=x", Status: Yanked},
+	}
+	for _, rule := range invalid {
+		if err := rule.Validate(); err == nil {
+			t.Errorf("Validate(%+v) succeeded, want error", rule)
+		}
+	}
+}
+
+func TestDescribe(t *testing.T) {
+	if got := (Rule{Status: EOL}).Describe(); got != "eol" {
+		t.Errorf("Describe() = %q", got)
+	}
+	if got := (Rule{Status: Yanked, Message: "bad build"}).Describe(); got != "yanked: bad build" {
+		t.Errorf("Describe() = %q", got)
+	}
+}
@@ -0,0 +1,95 @@
+package lock
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/pyhub-kr/pyhub-installer/internal/i18n"
+	"github.com/pyhub-kr/pyhub-installer/pkg/config"
+)
+
+// Lock is an advisory lock held on a lock file
+type Lock struct {
+	Path string
+	file *os.File
+}
+
+// Acquire blocks until the lock file at path is held by this process
+func Acquire(path string) (*Lock, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return nil, fmt.Errorf("failed to create lock directory: %w", err)
+	}
+
+	file, err := os.OpenFile(path, os.O_RDWR|os.O_CREATE, 0644)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open lock file: %w", err)
+	}
+
+	// Try without blocking first so we can tell the user why we are waiting
+	acquired, err := tryLockFile(file)
+	if err != nil {
+		file.Close()
+		return nil, fmt.Errorf("failed to lock %s: %w", path, err)
+	}
+	if !acquired {
+		fmt.Print(i18n.T("Waiting for another pyhub-installer process to release %s...\n", path))
+		if err := lockFile(file); err != nil {
+			file.Close()
+			return nil, fmt.Errorf("failed to lock %s: %w", path, err)
+		}
+	}
+
+	return &Lock{Path: path, file: file}, nil
+}
+
+// TryAcquire takes the lock file at path if no other process holds it, without waiting.
+// It returns a nil lock if the file is locked.
+func TryAcquire(path string) (*Lock, error) {
+	file, err := os.OpenFile(path, os.O_RDWR|os.O_CREATE, 0644)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open lock file: %w", err)
+	}
+	acquired, err := tryLockFile(file)
+	if err != nil || !acquired {
+		file.Close()
+		if err != nil {
+			return nil, fmt.Errorf("failed to lock %s: %w", path, err)
+		}
+		return nil, nil
+	}
+	return &Lock{Path: path, file: file}, nil
+}
+
+// AcquireDir locks a destination directory without writing into it
+func AcquireDir(dir string) (*Lock, error) {
+	dataDir, err := config.DataDir()
+	if err != nil {
+		return nil, err
+	}
+
+	absDir, err := filepath.Abs(dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve %s: %w", dir, err)
+	}
+
+	sum := sha256.Sum256([]byte(filepath.Clean(absDir)))
+	name := hex.EncodeToString(sum[:8]) + ".lock"
+	return Acquire(filepath.Join(dataDir, "locks", name))
+}
+
+// Release releases the lock
+func (l *Lock) Release() error {
+	if l == nil || l.file == nil {
+		return nil
+	}
+	defer func() { l.file = nil }()
+
+	if err := unlockFile(l.file); err != nil {
+		l.file.Close()
+		return fmt.Errorf("failed to unlock %s: %w", l.Path, err)
+	}
+	return l.file.Close()
+}
@@ -0,0 +1,118 @@
+package lock
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestAcquireAndRelease(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "sub", "test.lock")
+
+	l, err := Acquire(path)
+	if err != nil {
+		t.Fatalf("Acquire failed: %v", err)
+	}
+
+	if _, err := os.Stat(path); err != nil {
+		t.Errorf("Lock file should exist: %v", err)
+	}
+
+	// A second handle must not be able to take the lock while it is held
+	file, err := os.OpenFile(path, os.O_RDWR, 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer file.Close()
+
+	acquired, err := tryLockFile(file)
+	if err != nil {
+		t.Fatalf("tryLockFile failed: %v", err)
+	}
+	if acquired {
+		t.Fatal("Lock should not be acquirable while held")
+	}
+
+	if err := l.Release(); err != nil {
+		t.Fatalf("Release failed: %v", err)
+	}
+
+	acquired, err = tryLockFile(file)
+	if err != nil {
+		t.Fatalf("tryLockFile failed: %v", err)
+	}
+	if !acquired {
+		t.Error("Lock should be acquirable after release")
+	}
+	unlockFile(file)
+}
+
+func TestAcquireWaitsForRelease(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "test.lock")
+
+	first, err := Acquire(path)
+	if err != nil {
+		t.Fatalf("Acquire failed: %v", err)
+	}
+
+	acquired := make(chan *Lock)
+	go func() {
+		second, err := Acquire(path)
+		if err != nil {
+			t.Errorf("Second Acquire failed: %v", err)
+		}
+		acquired <- second
+	}()
+
+	select {
+	case <-acquired:
+		t.Fatal("Second Acquire should block while the lock is held")
+	case <-time.After(100 * time.Millisecond):
+	}
+
+	first.Release()
+
+	select {
+	case second := <-acquired:
+		second.Release()
+	case <-time.After(5 * time.Second):
+		t.Fatal("Second Acquire did not succeed after release")
+	}
+}
+
+func TestReleaseTwice(t *testing.T) {
+	l, err := Acquire(filepath.Join(t.TempDir(), "test.lock"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := l.Release(); err != nil {
+		t.Fatalf("Release failed: %v", err)
+	}
+	if err := l.Release(); err != nil {
+		t.Errorf("Second Release should be a no-op, got %v", err)
+	}
+}
+
+func TestTryAcquire(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "test.lock")
+
+	l, err := TryAcquire(path)
+	if err != nil || l == nil {
+		t.Fatalf("TryAcquire() = %v, %v, want the lock", l, err)
+	}
+	other, err := TryAcquire(path)
+	if err != nil || other != nil {
+		t.Fatalf("TryAcquire() = %v, %v while held, want nil", other, err)
+	}
+
+	if err := l.Release(); err != nil {
+		t.Fatal(err)
+	}
+	other, err = TryAcquire(path)
+	if err != nil || other == nil {
+		t.Fatalf("TryAcquire() = %v, %v after release, want the lock", other, err)
+	}
+	other.Release()
+}
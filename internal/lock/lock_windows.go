@@ -0,0 +1,36 @@
+//go:build windows
+
+package lock
+
+import (
+	"errors"
+	"os"
+
+	"golang.org/x/sys/windows"
+)
+
+// tryLockFile takes an exclusive lock without blocking
+func tryLockFile(file *os.File) (bool, error) {
+	err := lockFileEx(file, windows.LOCKFILE_EXCLUSIVE_LOCK|windows.LOCKFILE_FAIL_IMMEDIATELY)
+	if errors.Is(err, windows.ERROR_LOCK_VIOLATION) {
+		return false, nil
+	}
+	return err == nil, err
+}
+
+// lockFile takes an exclusive lock, blocking until it is available
+func lockFile(file *os.File) error {
+	return lockFileEx(file, windows.LOCKFILE_EXCLUSIVE_LOCK)
+}
+
+// unlockFile releases the lock
+func unlockFile(file *os.File) error {
+	overlapped := new(windows.Overlapped)
+	return windows.UnlockFileEx(windows.Handle(file.Fd()), 0, 1, 0, overlapped)
+}
+
+// lockFileEx locks the first byte of the file, which is enough for an advisory lock
+func lockFileEx(file *os.File, flags uint32) error {
+	overlapped := new(windows.Overlapped)
+	return windows.LockFileEx(windows.Handle(file.Fd()), flags, 0, 1, 0, overlapped)
+}
@@ -0,0 +1,64 @@
+package notify
+
+import (
+	"fmt"
+	"os/exec"
+	"runtime"
+	"strings"
+)
+
+// AppName is shown as the sender of notifications where the platform allows
+const AppName = "pyhub-installer"
+
+// powershellAppID is the application Windows shows toasts from; toasts of unregistered
+// applications are dropped
+const powershellAppID = `{1AC14E77-02E7-4E5D-B744-2EB1AE5198B7}\WindowsPowerShell\v1.0\powershell.exe`
+
+// run runs a notification command
+var run = func(name string, args ...string) error {
+	if output, err := exec.Command(name, args...).CombinedOutput(); err != nil {
+		return fmt.Errorf("%s failed: %w: %s", name, err, strings.TrimSpace(string(output)))
+	}
+	return nil
+}
+
+// Send shows a desktop notification: with notify-send on Linux, Notification Center
+// on macOS and a toast on Windows
+func Send(title, message string) error {
+	name, args, err := command(runtime.GOOS, title, message)
+	if err != nil {
+		return err
+	}
+	return run(name, args...)
+}
+
+// command returns the command showing a notification on a platform
+func command(goos, title, message string) (string, []string, error) {
+	switch goos {
+	case "linux", "freebsd", "openbsd", "netbsd":
+		return "notify-send", []string{"--app-name=" + AppName, title, message}, nil
+	case "darwin":
+		script := fmt.Sprintf("display notification %s with title %s", appleScriptString(message), appleScriptString(title))
+		return "osascript", []string{"-e", script}, nil
+	case "windows":
+		script := fmt.Sprintf(`[Windows.UI.Notifications.ToastNotificationManager, Windows.UI.Notifications, ContentType = WindowsRuntime] > $null
+$template = [Windows.UI.Notifications.ToastNotificationManager]::GetTemplateContent([Windows.UI.Notifications.ToastTemplateType]::ToastText02)
+$text = $template.GetElementsByTagName('text')
+$text.Item(0).AppendChild($template.CreateTextNode(%s)) > $null
+$text.Item(1).AppendChild($template.CreateTextNode(%s)) > $null
+[Windows.UI.Notifications.ToastNotificationManager]::CreateToastNotifier(%s).Show([Windows.UI.Notifications.ToastNotification]::new($template))`,
+			powershellString(title), powershellString(message), powershellString(powershellAppID))
+		return "powershell", []string{"-NoProfile", "-NonInteractive", "-Command", script}, nil
+	}
+	return "", nil, fmt.Errorf("desktop notifications are not supported on %s", goos)
+}
+
+// appleScriptString quotes text as an AppleScript string literal
+func appleScriptString(s string) string {
+	return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(s) + `"`
+}
+
+// powershellString quotes text as a single-quoted PowerShell string, which expands nothing
+func powershellString(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", "''") + "'"
+}
@@ -0,0 +1,30 @@
+package notify
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestCommand(t *testing.T) {
+	name, args, err := command("linux", "Updates", "rg 14.1.1")
+	if err != nil || name != "notify-send" || strings.Join(args, "|") != "--app-name=pyhub-installer|Updates|rg 14.1.1" {
+		t.Errorf("command(linux) = %s %q, %v", name, args, err)
+	}
+
+	name, args, err = command("darwin", `Say "hi"`, `C:\path`)
+	if err != nil || name != "osascript" || args[1] != `display notification "C:\\path" with title "Say \"hi\""` {
+		t.Errorf("command(darwin) = %s %q, %v", name, args, err)
+	}
+
+	name, args, err = command("windows", "Updates", "it's $HOME")
+	if err != nil || name != "powershell" {
+		t.Fatalf("command(windows) = %s, %v", name, err)
+	}
+	if !strings.Contains(args[len(args)-1], `CreateTextNode('it''s $HOME')`) {
+		t.Errorf("Expected the message as a literal PowerShell string:\n%s", args[len(args)-1])
+	}
+
+	if _, _, err := command("plan9", "Updates", "rg"); err == nil {
+		t.Error("Expected error on an unsupported platform")
+	}
+}
@@ -0,0 +1,49 @@
+package platform
+
+import (
+	"os"
+	"strings"
+)
+
+// Files and environment variables that reveal a container runtime
+var (
+	dockerEnvPath    = "/.dockerenv"
+	containerEnvPath = "/run/.containerenv" // Podman
+	cgroupPath       = "/proc/1/cgroup"
+)
+
+// cgroupRuntimes maps markers in the init process's cgroup paths to container runtimes
+var cgroupRuntimes = []struct{ marker, runtime string }{
+	{"kubepods", "kubernetes"},
+	{"libpod", "podman"},
+	{"docker", "docker"},
+	{"containerd", "containerd"},
+	{"lxc", "lxc"},
+}
+
+// Container returns the container runtime the installer runs in, e.g. "docker",
+// "podman" or "kubernetes", or "" outside a container
+func Container() string {
+	// Podman and systemd-nspawn set $container for the init process and its children
+	if name := os.Getenv("container"); name != "" {
+		return name
+	}
+	if _, err := os.Stat(containerEnvPath); err == nil {
+		return "podman"
+	}
+	if _, err := os.Stat(dockerEnvPath); err == nil {
+		return "docker"
+	}
+	if os.Getenv("KUBERNETES_SERVICE_HOST") != "" {
+		return "kubernetes"
+	}
+	if data, err := os.ReadFile(cgroupPath); err == nil {
+		cgroups := string(data)
+		for _, r := range cgroupRuntimes {
+			if strings.Contains(cgroups, r.marker) {
+				return r.runtime
+			}
+		}
+	}
+	return ""
+}
@@ -0,0 +1,50 @@
+package platform
+
+import (
+	"slices"
+	"sort"
+	"strings"
+)
+
+// pkexecEnv lists the variables passed on to commands elevated with pkexec, which
+// starts them with a minimal environment. Variables starting with PYHUB_ are passed too.
+var pkexecEnv = []string{
+	"GITHUB_TOKEN", "GH_TOKEN", "GITEA_TOKEN", "FORGEJO_TOKEN",
+	"HTTP_PROXY", "HTTPS_PROXY", "NO_PROXY", "http_proxy", "https_proxy", "no_proxy",
+	"LANG", "LC_ALL", "LC_MESSAGES", "NO_COLOR", "TERM",
+}
+
+// PkexecAvailable reports whether a command can be elevated with pkexec: on Linux in a
+// graphical session, where polkit asks for the password in a dialog, with pkexec on
+// PATH. lookPath is exec.LookPath.
+func PkexecAvailable(goos string, getenv func(string) string, lookPath func(string) (string, error)) bool {
+	if goos != "linux" {
+		return false
+	}
+	if getenv("DISPLAY") == "" && getenv("WAYLAND_DISPLAY") == "" {
+		return false
+	}
+	_, err := lookPath("pkexec")
+	return err == nil
+}
+
+// PkexecCommand returns the command running executable with args as root through
+// pkexec, passing on the variables of environ ("KEY=value") the installer uses
+func PkexecCommand(executable string, args, environ []string) []string {
+	var env []string
+	for _, kv := range environ {
+		key, _, ok := strings.Cut(kv, "=")
+		if !ok {
+			continue
+		}
+		if strings.HasPrefix(key, "PYHUB_") || slices.Contains(pkexecEnv, key) {
+			env = append(env, kv)
+		}
+	}
+	sort.Strings(env)
+
+	command := []string{"pkexec", "/usr/bin/env"}
+	command = append(command, env...)
+	command = append(command, executable)
+	return append(command, args...)
+}
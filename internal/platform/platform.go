@@ -0,0 +1,116 @@
+package platform
+
+import (
+	"fmt"
+	"os"
+	"runtime"
+	"strconv"
+	"strings"
+)
+
+// windows11Build is the first Windows build number that can emulate x64 on ARM64
+const windows11Build = 22000
+
+// rosettaRuntimePath is installed by Rosetta 2 on Apple Silicon Macs
+var rosettaRuntimePath = "/Library/Apple/usr/libexec/oah/libRosettaRuntime"
+
+// translated reports whether the process is an Intel build running under Rosetta 2
+var translated = processTranslated
+
+// cpuinfoPath describes the CPU on Linux, including the version of ARM processors
+var cpuinfoPath = "/proc/cpuinfo"
+
+// Current returns the platform of the running binary, e.g. "darwin-arm64"
+func Current() string {
+	return fmt.Sprintf("%s-%s", runtime.GOOS, runtime.GOARCH)
+}
+
+// Native returns the current platform with the version of 32-bit ARM processors, e.g.
+// "linux-armv6" rather than "linux-arm", so that builds for that version are preferred.
+// An Intel build of the installer running under Rosetta 2 reports the Apple Silicon
+// platform, so that native builds are installed.
+func Native() string {
+	return native(runtime.GOOS, runtime.GOARCH)
+}
+
+// native returns the platform of goos and goarch, refined by the CPU version on ARM Linux
+// and by Rosetta 2 translation on macOS
+func native(goos, goarch string) string {
+	if goos == "darwin" && goarch == "amd64" && translated() {
+		return "darwin-arm64"
+	}
+	if goos == "linux" && goarch == "arm" {
+		switch version := armVersion(); {
+		case version == 6:
+			return "linux-armv6"
+		case version >= 7:
+			return "linux-armv7"
+		}
+	}
+	return goos + "-" + goarch
+}
+
+// armVersion returns the ARM architecture version from /proc/cpuinfo, or 0 if unknown.
+// 64-bit processors running a 32-bit system report 8 and run ARMv7 builds.
+func armVersion() int {
+	data, err := os.ReadFile(cpuinfoPath)
+	if err != nil {
+		return 0
+	}
+	for _, line := range strings.Split(string(data), "\n") {
+		key, value, ok := strings.Cut(line, ":")
+		if !ok || strings.TrimSpace(key) != "CPU architecture" {
+			continue
+		}
+		version, err := strconv.Atoi(strings.TrimSpace(value))
+		if err != nil {
+			return 0
+		}
+		return version
+	}
+	return 0
+}
+
+// Split separates a platform string into operating system and architecture
+func Split(platform string) (goos, goarch string) {
+	goos, goarch, _ = strings.Cut(platform, "-")
+	return goos, goarch
+}
+
+// RosettaAvailable reports whether Rosetta 2 can run x86_64 binaries on this Mac
+func RosettaAvailable() bool {
+	if runtime.GOOS != "darwin" {
+		return false
+	}
+	_, err := os.Stat(rosettaRuntimePath)
+	return err == nil
+}
+
+// Fallback returns a platform whose binaries can run on platform through emulation,
+// with a note explaining the choice. ok is false if there is none.
+func Fallback(platform string) (fallback, note string, ok bool) {
+	switch platform {
+	case "darwin-arm64":
+		if RosettaAvailable() {
+			return "darwin-amd64", "no Apple Silicon build available; using the Intel build under Rosetta 2", true
+		}
+	case "windows-arm64":
+		// Windows 11 emulates x64; earlier ARM releases only emulate x86
+		if runtime.GOOS == "windows" && windowsBuild() < windows11Build {
+			return "windows-386", "no ARM64 build available; using the x86 build under emulation", true
+		}
+		return "windows-amd64", "no ARM64 build available; using the x64 build under Windows 11 emulation", true
+	}
+	return "", "", false
+}
+
+// FallbackHint suggests how to make a fallback platform usable when Fallback finds none
+func FallbackHint(platform string) string {
+	switch platform {
+	case "darwin-arm64":
+		if runtime.GOOS == "darwin" && !RosettaAvailable() {
+			return "install Rosetta 2 with 'softwareupdate --install-rosetta' to run Intel builds"
+		}
+	}
+	return ""
+}
@@ -0,0 +1,15 @@
+//go:build !windows
+
+package platform
+
+import "os"
+
+// windowsBuild returns 0 when not running on Windows
+func windowsBuild() uint32 {
+	return 0
+}
+
+// IsElevated reports whether the installer runs as root
+func IsElevated() bool {
+	return os.Geteuid() == 0
+}
@@ -0,0 +1,218 @@
+package platform
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+)
+
+func TestCurrent(t *testing.T) {
+	expected := runtime.GOOS + "-" + runtime.GOARCH
+	if got := Current(); got != expected {
+		t.Errorf("Expected %s, got %s", expected, got)
+	}
+}
+
+func TestSplit(t *testing.T) {
+	goos, goarch := Split("darwin-arm64")
+	if goos != "darwin" || goarch != "arm64" {
+		t.Errorf("Expected darwin arm64, got %s %s", goos, goarch)
+	}
+
+	goos, goarch = Split("linux")
+	if goos != "linux" || goarch != "" {
+		t.Errorf("Expected linux with empty arch, got %s %s", goos, goarch)
+	}
+}
+
+func TestRosettaFallback(t *testing.T) {
+	original := rosettaRuntimePath
+	defer func() { rosettaRuntimePath = original }()
+
+	runtimePath := filepath.Join(t.TempDir(), "libRosettaRuntime")
+	rosettaRuntimePath = runtimePath
+
+	if _, _, ok := Fallback("darwin-arm64"); ok {
+		t.Error("Expected no fallback without Rosetta")
+	}
+
+	if err := os.WriteFile(runtimePath, nil, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	fallback, note, ok := Fallback("darwin-arm64")
+	if runtime.GOOS != "darwin" {
+		if ok {
+			t.Error("Rosetta should only be detected on macOS")
+		}
+		return
+	}
+	if !ok || fallback != "darwin-amd64" || note == "" {
+		t.Errorf("Expected darwin-amd64 fallback with note, got %q %q %v", fallback, note, ok)
+	}
+}
+
+func TestWindowsARM64Fallback(t *testing.T) {
+	fallback, note, ok := Fallback("windows-arm64")
+	if !ok || note == "" {
+		t.Fatalf("Expected a fallback for windows-arm64, got %q %q %v", fallback, note, ok)
+	}
+
+	expected := "windows-amd64"
+	if runtime.GOOS == "windows" && windowsBuild() < windows11Build {
+		expected = "windows-386"
+	}
+	if fallback != expected {
+		t.Errorf("Expected %s, got %s", expected, fallback)
+	}
+}
+
+func TestNoFallback(t *testing.T) {
+	for _, p := range []string{"linux-amd64", "linux-arm64", "darwin-amd64"} {
+		if _, _, ok := Fallback(p); ok {
+			t.Errorf("Expected no fallback for %s", p)
+		}
+	}
+}
+
+func TestContainer(t *testing.T) {
+	originals := []string{dockerEnvPath, containerEnvPath, cgroupPath}
+	defer func() { dockerEnvPath, containerEnvPath, cgroupPath = originals[0], originals[1], originals[2] }()
+	t.Setenv("container", "")
+	t.Setenv("KUBERNETES_SERVICE_HOST", "")
+
+	dir := t.TempDir()
+	dockerEnvPath = filepath.Join(dir, ".dockerenv")
+	containerEnvPath = filepath.Join(dir, ".containerenv")
+	cgroupPath = filepath.Join(dir, "cgroup")
+
+	if err := os.WriteFile(cgroupPath, []byte("0::/\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if got := Container(); got != "" {
+		t.Errorf("Container() = %q outside a container", got)
+	}
+
+	if err := os.WriteFile(cgroupPath, []byte("12:cpu:/kubepods/besteffort/pod1234\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if got := Container(); got != "kubernetes" {
+		t.Errorf("Container() = %q, want kubernetes from cgroups", got)
+	}
+
+	if err := os.WriteFile(dockerEnvPath, nil, 0644); err != nil {
+		t.Fatal(err)
+	}
+	if got := Container(); got != "docker" {
+		t.Errorf("Container() = %q, want docker", got)
+	}
+
+	if err := os.WriteFile(containerEnvPath, nil, 0644); err != nil {
+		t.Fatal(err)
+	}
+	if got := Container(); got != "podman" {
+		t.Errorf("Container() = %q, want podman", got)
+	}
+
+	t.Setenv("container", "systemd-nspawn")
+	if got := Container(); got != "systemd-nspawn" {
+		t.Errorf("Container() = %q, want the $container value", got)
+	}
+}
+
+func TestIsElevated(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("elevation is read from the process token on Windows")
+	}
+	if got, want := IsElevated(), os.Geteuid() == 0; got != want {
+		t.Errorf("IsElevated() = %v, want %v", got, want)
+	}
+}
+
+func TestNative(t *testing.T) {
+	original := cpuinfoPath
+	defer func() { cpuinfoPath = original }()
+	cpuinfoPath = filepath.Join(t.TempDir(), "cpuinfo")
+
+	if got := native("linux", "arm"); got != "linux-arm" {
+		t.Errorf("native() = %s without cpuinfo, want linux-arm", got)
+	}
+
+	tests := map[string]string{
+		"processor\t: 0\nmodel name\t: ARMv6-compatible processor rev 7 (v6l)\nCPU architecture: 6\n": "linux-armv6",
+		"processor\t: 0\nmodel name\t: ARMv7 Processor rev 4 (v7l)\nCPU architecture: 7\n":            "linux-armv7",
+		"processor\t: 0\nCPU architecture: 8\n":                                                       "linux-armv7",
+	}
+	for cpuinfo, want := range tests {
+		if err := os.WriteFile(cpuinfoPath, []byte(cpuinfo), 0644); err != nil {
+			t.Fatal(err)
+		}
+		if got := native("linux", "arm"); got != want {
+			t.Errorf("native() = %s, want %s for cpuinfo %q", got, want, cpuinfo)
+		}
+	}
+
+	if got := native("linux", "riscv64"); got != "linux-riscv64" {
+		t.Errorf("native() = %s, want linux-riscv64", got)
+	}
+	if got := native("darwin", "arm64"); got != "darwin-arm64" {
+		t.Errorf("native() = %s, want darwin-arm64", got)
+	}
+}
+
+func TestNativeUnderRosetta(t *testing.T) {
+	original := translated
+	defer func() { translated = original }()
+
+	translated = func() bool { return false }
+	if got := native("darwin", "amd64"); got != "darwin-amd64" {
+		t.Errorf("native() = %s on an Intel Mac, want darwin-amd64", got)
+	}
+
+	translated = func() bool { return true }
+	if got := native("darwin", "amd64"); got != "darwin-arm64" {
+		t.Errorf("native() = %s under Rosetta 2, want darwin-arm64", got)
+	}
+}
+
+func TestPkexecAvailable(t *testing.T) {
+	env := func(vars map[string]string) func(string) string {
+		return func(key string) string { return vars[key] }
+	}
+	found := func(string) (string, error) { return "/usr/bin/pkexec", nil }
+	missing := func(name string) (string, error) { return "", os.ErrNotExist }
+
+	tests := []struct {
+		name     string
+		goos     string
+		vars     map[string]string
+		lookPath func(string) (string, error)
+		want     bool
+	}{
+		{"X11 session", "linux", map[string]string{"DISPLAY": ":0"}, found, true},
+		{"Wayland session", "linux", map[string]string{"WAYLAND_DISPLAY": "wayland-0"}, found, true},
+		{"no graphical session", "linux", nil, found, false},
+		{"pkexec not installed", "linux", map[string]string{"DISPLAY": ":0"}, missing, false},
+		{"macOS", "darwin", map[string]string{"DISPLAY": ":0"}, found, false},
+	}
+	for _, tt := range tests {
+		if got := PkexecAvailable(tt.goos, env(tt.vars), tt.lookPath); got != tt.want {
+			t.Errorf("%s: PkexecAvailable() = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestPkexecCommand(t *testing.T) {
+	environ := []string{"HOME=/home/user", "PYHUB_CACHE_SERVER=http://cache.lan:7879", "GITHUB_TOKEN=ghp_x", "PATH=/usr/bin", "MALFORMED"}
+	got := PkexecCommand("/usr/local/bin/pyhub-installer", []string{"install", "--system", "cli/cli"}, environ)
+	want := []string{"pkexec", "/usr/bin/env", "GITHUB_TOKEN=ghp_x", "PYHUB_CACHE_SERVER=http://cache.lan:7879", "/usr/local/bin/pyhub-installer", "install", "--system", "cli/cli"}
+	if len(got) != len(want) {
+		t.Fatalf("PkexecCommand() = %q, want %q", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("PkexecCommand()[%d] = %q, want %q", i, got[i], want[i])
+		}
+	}
+}
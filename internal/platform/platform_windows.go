@@ -0,0 +1,16 @@
+//go:build windows
+
+package platform
+
+import "golang.org/x/sys/windows"
+
+// windowsBuild returns the build number of the running Windows version
+func windowsBuild() uint32 {
+	return windows.RtlGetVersion().BuildNumber
+}
+
+// IsElevated reports whether the installer runs with administrator rights, e.g. from
+// an elevated prompt
+func IsElevated() bool {
+	return windows.GetCurrentProcessToken().IsElevated()
+}
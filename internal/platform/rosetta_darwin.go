@@ -0,0 +1,12 @@
+//go:build darwin
+
+package platform
+
+import "golang.org/x/sys/unix"
+
+// processTranslated reports whether the installer runs under Rosetta 2, i.e. an Intel
+// build on an Apple Silicon Mac. Intel Macs don't have the sysctl.
+func processTranslated() bool {
+	translated, err := unix.SysctlUint32("sysctl.proc_translated")
+	return err == nil && translated == 1
+}
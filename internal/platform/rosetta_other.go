@@ -0,0 +1,8 @@
+//go:build !darwin
+
+package platform
+
+// processTranslated returns false when not running on macOS
+func processTranslated() bool {
+	return false
+}
@@ -0,0 +1,137 @@
+package plugin
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"slices"
+	"sort"
+	"strings"
+)
+
+// Prefix names plugin executables: "pyhub-installer-<name>" adds the command <name>
+const Prefix = "pyhub-installer-"
+
+// Plugin is an external command found on PATH
+type Plugin struct {
+	Name string // Command name, without the prefix
+	Path string
+}
+
+// Context describes the installer to a plugin. It is written to the plugin's standard
+// input as JSON, so plugins can reuse the installer's settings without parsing them.
+type Context struct {
+	Version    string   `json:"version"`    // Installer version
+	Executable string   `json:"executable"` // Installer binary, for running installer commands
+	Args       []string `json:"args"`       // Arguments after the command name
+	Platform   string   `json:"platform"`   // e.g. linux-amd64
+	Language   string   `json:"language"`
+	ConfigPath string   `json:"config_path"`
+	DataDir    string   `json:"data_dir"`
+	CacheDir   string   `json:"cache_dir"`
+	InstallDir string   `json:"install_dir"` // Default install directory
+}
+
+// Find returns the plugin providing a command, searching PATH
+func Find(name string) (*Plugin, error) {
+	if name == "" || strings.ContainsAny(name, `/\`) {
+		return nil, fmt.Errorf("invalid plugin name %q", name)
+	}
+	path, err := exec.LookPath(Prefix + name)
+	if err != nil {
+		return nil, err
+	}
+	return &Plugin{Name: name, Path: path}, nil
+}
+
+// List returns the plugins on PATH, sorted by name. A plugin in an earlier PATH
+// directory shadows one of the same name in a later directory, as for Find.
+func List() []Plugin {
+	seen := make(map[string]bool)
+	var plugins []Plugin
+	for _, dir := range filepath.SplitList(os.Getenv("PATH")) {
+		entries, err := os.ReadDir(dir)
+		if err != nil {
+			continue
+		}
+		for _, entry := range entries {
+			name, ok := commandName(entry.Name())
+			if !ok || seen[name] || entry.IsDir() {
+				continue
+			}
+			path := filepath.Join(dir, entry.Name())
+			if !isExecutable(path) {
+				continue
+			}
+			seen[name] = true
+			plugins = append(plugins, Plugin{Name: name, Path: path})
+		}
+	}
+	sort.Slice(plugins, func(i, j int) bool { return plugins[i].Name < plugins[j].Name })
+	return plugins
+}
+
+// commandName returns the command a plugin file provides, e.g. "org-sync" for
+// "pyhub-installer-org-sync" or, on Windows, "pyhub-installer-org-sync.exe"
+func commandName(file string) (string, bool) {
+	name, ok := strings.CutPrefix(file, Prefix)
+	if !ok {
+		return "", false
+	}
+	if runtime.GOOS == "windows" {
+		ext := strings.ToLower(filepath.Ext(name))
+		if !isWindowsExecutableExt(ext) {
+			return "", false
+		}
+		name = strings.TrimSuffix(name, filepath.Ext(name))
+	}
+	return name, name != ""
+}
+
+// isWindowsExecutableExt reports whether an extension is in PATHEXT
+func isWindowsExecutableExt(ext string) bool {
+	pathext := os.Getenv("PATHEXT")
+	if pathext == "" {
+		pathext = ".COM;.EXE;.BAT;.CMD"
+	}
+	return slices.Contains(strings.Split(strings.ToLower(pathext), ";"), ext)
+}
+
+// isExecutable reports whether a file can be run; on Windows the extension decides
+func isExecutable(path string) bool {
+	info, err := os.Stat(path)
+	if err != nil || info.IsDir() {
+		return false
+	}
+	return runtime.GOOS == "windows" || info.Mode()&0111 != 0
+}
+
+// Run runs a plugin with the context on standard input and the given output streams,
+// and returns its exit code
+func (p *Plugin) Run(ctx context.Context, pluginCtx Context, stdout, stderr io.Writer) (int, error) {
+	input, err := json.Marshal(pluginCtx)
+	if err != nil {
+		return 1, fmt.Errorf("failed to encode plugin context: %w", err)
+	}
+
+	cmd := exec.CommandContext(ctx, p.Path, pluginCtx.Args...)
+	cmd.Stdin = bytes.NewReader(append(input, '\n'))
+	cmd.Stdout = stdout
+	cmd.Stderr = stderr
+	cmd.Env = append(os.Environ(), "PYHUB_INSTALLER="+pluginCtx.Executable)
+	if err := cmd.Run(); err != nil {
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) && exitErr.ExitCode() > 0 {
+			return exitErr.ExitCode(), nil
+		}
+		return 1, fmt.Errorf("failed to run plugin %s: %w", p.Name, err)
+	}
+	return 0, nil
+}
@@ -0,0 +1,119 @@
+package plugin
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+)
+
+// writePlugin creates an executable shell script plugin in dir
+func writePlugin(t *testing.T, dir, name, script string) string {
+	t.Helper()
+	path := filepath.Join(dir, Prefix+name)
+	if err := os.WriteFile(path, []byte("#!/bin/sh\n"+script), 0755); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestFindAndList(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("shell script plugins")
+	}
+	first, second := t.TempDir(), t.TempDir()
+	writePlugin(t, first, "org-sync", "exit 0\n")
+	writePlugin(t, second, "org-sync", "exit 1\n")
+	writePlugin(t, second, "audit", "exit 0\n")
+	if err := os.WriteFile(filepath.Join(second, Prefix+"notes"), []byte("not executable"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("PATH", first+string(os.PathListSeparator)+second)
+
+	p, err := Find("org-sync")
+	if err != nil {
+		t.Fatalf("Find() error = %v", err)
+	}
+	if p.Path != filepath.Join(first, Prefix+"org-sync") {
+		t.Errorf("Find() = %s, want the plugin of the first PATH directory", p.Path)
+	}
+	if _, err := Find("missing"); err == nil {
+		t.Error("Expected error for a missing plugin")
+	}
+	if _, err := Find("../org-sync"); err == nil {
+		t.Error("Expected error for a name with a path separator")
+	}
+
+	plugins := List()
+	if len(plugins) != 2 || plugins[0].Name != "audit" || plugins[1].Name != "org-sync" {
+		t.Fatalf("List() = %+v", plugins)
+	}
+	if plugins[1].Path != p.Path {
+		t.Errorf("List() found %s, want the shadowing plugin %s", plugins[1].Path, p.Path)
+	}
+}
+
+func TestRun(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("shell script plugins")
+	}
+	dir := t.TempDir()
+	writePlugin(t, dir, "echo", `read -r context
+echo "$context"
+echo "args: $*"
+echo "self: $PYHUB_INSTALLER" >&2
+exit 3
+`)
+	p := &Plugin{Name: "echo", Path: filepath.Join(dir, Prefix+"echo")}
+
+	var stdout, stderr bytes.Buffer
+	pluginCtx := Context{Version: "v1.2.3", Executable: "/usr/local/bin/pyhub-installer", Args: []string{"one", "two"}, Platform: "linux-amd64"}
+	code, err := p.Run(context.Background(), pluginCtx, &stdout, &stderr)
+	if err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+	if code != 3 {
+		t.Errorf("Run() = %d, want the plugin's exit code 3", code)
+	}
+
+	lines := strings.Split(strings.TrimSpace(stdout.String()), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("Unexpected output:\n%s", stdout.String())
+	}
+	var got Context
+	if err := json.Unmarshal([]byte(lines[0]), &got); err != nil {
+		t.Fatalf("Plugin did not receive JSON context: %v\n%s", err, lines[0])
+	}
+	if got.Version != "v1.2.3" || got.Platform != "linux-amd64" || len(got.Args) != 2 {
+		t.Errorf("Unexpected context: %+v", got)
+	}
+	if lines[1] != "args: one two" {
+		t.Errorf("Unexpected arguments line: %s", lines[1])
+	}
+	if strings.TrimSpace(stderr.String()) != "self: /usr/local/bin/pyhub-installer" {
+		t.Errorf("Unexpected stderr: %s", stderr.String())
+	}
+}
+
+func TestCommandName(t *testing.T) {
+	name, ok := commandName(Prefix + "org-sync")
+	if runtime.GOOS == "windows" {
+		if ok {
+			t.Error("Expected files without an executable extension to be ignored on Windows")
+		}
+		name, ok = commandName(Prefix + "org-sync.exe")
+	}
+	if !ok || name != "org-sync" {
+		t.Errorf("commandName() = %q, %v", name, ok)
+	}
+	if _, ok := commandName("other-tool"); ok {
+		t.Error("Expected files without the prefix to be ignored")
+	}
+	if _, ok := commandName(Prefix); ok {
+		t.Error("Expected the bare prefix to be ignored")
+	}
+}
@@ -0,0 +1,202 @@
+package profile
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"text/tabwriter"
+	"time"
+)
+
+// Phases of the install pipeline, in order
+const (
+	Resolve  = "resolve"
+	Download = "download"
+	Verify   = "verify"
+	Extract  = "extract"
+	Install  = "install"
+)
+
+// Phases lists the phases in pipeline order
+var Phases = []string{Resolve, Download, Verify, Extract, Install}
+
+// Span is the time one tool spent in one phase
+type Span struct {
+	Phase    string
+	Name     string
+	Start    time.Time
+	Duration time.Duration
+}
+
+// Profiler records the spans of a command. A nil profiler records nothing.
+type Profiler struct {
+	mu    sync.Mutex
+	start time.Time
+	spans []Span
+}
+
+// New creates a profiler whose total time starts now
+func New() *Profiler {
+	return &Profiler{start: time.Now()}
+}
+
+// Start starts a span of name in phase and returns the function that ends it
+func (p *Profiler) Start(phase, name string) func() {
+	if p == nil {
+		return func() {}
+	}
+	start := time.Now()
+	return func() {
+		p.mu.Lock()
+		defer p.mu.Unlock()
+		p.spans = append(p.spans, Span{Phase: phase, Name: name, Start: start, Duration: time.Since(start)})
+	}
+}
+
+// Spans returns the recorded spans in the order they ended
+func (p *Profiler) Spans() []Span {
+	if p == nil {
+		return nil
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return append([]Span{}, p.spans...)
+}
+
+type contextKey struct{}
+
+// WithProfiler returns a context whose spans are recorded by p
+func WithProfiler(ctx context.Context, p *Profiler) context.Context {
+	return context.WithValue(ctx, contextKey{}, p)
+}
+
+// Start starts a span with the profiler of ctx, if any, and returns the function
+// that ends it
+func Start(ctx context.Context, phase, name string) func() {
+	p, _ := ctx.Value(contextKey{}).(*Profiler)
+	return p.Start(phase, name)
+}
+
+// Report writes the time spent per phase. Spans of concurrent tools overlap, so a
+// phase's time is the wall-clock time any tool spent in it, followed by each tool's time.
+func (p *Profiler) Report(w io.Writer) {
+	if p == nil {
+		return
+	}
+	total := time.Since(p.start)
+	spans := p.Spans()
+
+	fmt.Fprintf(w, "Profile (%s total):\n", formatDuration(total))
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	for _, phase := range Phases {
+		var inPhase []Span
+		for _, s := range spans {
+			if s.Phase == phase {
+				inPhase = append(inPhase, s)
+			}
+		}
+		if len(inPhase) == 0 {
+			continue
+		}
+
+		wall := wallTime(inPhase)
+		share := 0.0
+		if total > 0 {
+			share = 100 * float64(wall) / float64(total)
+		}
+		detail := ""
+		if len(inPhase) > 1 {
+			parts := make([]string, len(inPhase))
+			for i, s := range inPhase {
+				parts[i] = s.Name + " " + formatDuration(s.Duration)
+			}
+			detail = strings.Join(parts, ", ")
+		}
+		line := fmt.Sprintf("  %s\t%s\t%3.0f%%", phase, formatDuration(wall), share)
+		if detail != "" {
+			line += "\t" + detail
+		}
+		fmt.Fprintln(tw, line)
+	}
+	tw.Flush()
+}
+
+// wallTime returns the time covered by at least one span
+func wallTime(spans []Span) time.Duration {
+	sorted := append([]Span{}, spans...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].Start.Before(sorted[j].Start) })
+
+	var wall time.Duration
+	var end time.Time
+	for _, s := range sorted {
+		spanEnd := s.Start.Add(s.Duration)
+		switch {
+		case !spanEnd.After(end):
+		case s.Start.After(end):
+			wall += s.Duration
+		default:
+			wall += spanEnd.Sub(end)
+		}
+		if spanEnd.After(end) {
+			end = spanEnd
+		}
+	}
+	return wall
+}
+
+// formatDuration rounds a duration for display
+func formatDuration(d time.Duration) string {
+	switch {
+	case d >= time.Second:
+		return d.Round(10 * time.Millisecond).String()
+	case d >= time.Millisecond:
+		return d.Round(time.Millisecond).String()
+	}
+	return d.Round(time.Microsecond).String()
+}
+
+// traceEvent is a complete event of the Chrome trace event format
+type traceEvent struct {
+	Name      string `json:"name"`
+	Category  string `json:"cat"`
+	Phase     string `json:"ph"`
+	Timestamp int64  `json:"ts"`  // Microseconds since the profile started
+	Duration  int64  `json:"dur"` // Microseconds
+	PID       int    `json:"pid"`
+	TID       int    `json:"tid"`
+}
+
+// WriteTrace writes the spans as a Chrome trace event file, with a row per tool, for
+// chrome://tracing or https://ui.perfetto.dev
+func (p *Profiler) WriteTrace(path string) error {
+	rows := map[string]int{}
+	events := []traceEvent{}
+	for _, s := range p.Spans() {
+		if _, ok := rows[s.Name]; !ok {
+			rows[s.Name] = len(rows) + 1
+		}
+		events = append(events, traceEvent{
+			Name:      s.Phase + " " + s.Name,
+			Category:  s.Phase,
+			Phase:     "X",
+			Timestamp: s.Start.Sub(p.start).Microseconds(),
+			Duration:  s.Duration.Microseconds(),
+			PID:       1,
+			TID:       rows[s.Name],
+		})
+	}
+
+	data, err := json.MarshalIndent(map[string]interface{}{"traceEvents": events, "displayTimeUnit": "ms"}, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode trace: %w", err)
+	}
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		return fmt.Errorf("failed to write trace: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,96 @@
+package profile
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestWallTime(t *testing.T) {
+	base := time.Now()
+	spans := []Span{
+		{Start: base, Duration: 2 * time.Second},
+		{Start: base.Add(time.Second), Duration: 2 * time.Second},              // Overlaps the first
+		{Start: base.Add(1500 * time.Millisecond), Duration: time.Second / 10}, // Inside the second
+		{Start: base.Add(5 * time.Second), Duration: time.Second},              // After a gap
+	}
+	if got := wallTime(spans); got != 4*time.Second {
+		t.Errorf("wallTime() = %v, want 4s", got)
+	}
+}
+
+func TestProfilerContext(t *testing.T) {
+	p := New()
+	ctx := WithProfiler(context.Background(), p)
+
+	end := Start(ctx, Resolve, "rg")
+	end()
+	Start(ctx, Download, "rg")()
+	Start(context.Background(), Verify, "rg")() // No profiler, not recorded
+
+	spans := p.Spans()
+	if len(spans) != 2 || spans[0].Phase != Resolve || spans[1].Phase != Download || spans[0].Name != "rg" {
+		t.Errorf("Unexpected spans: %+v", spans)
+	}
+}
+
+func TestNilProfiler(t *testing.T) {
+	var p *Profiler
+	p.Start(Download, "rg")()
+	p.Report(&bytes.Buffer{})
+	if spans := p.Spans(); spans != nil {
+		t.Errorf("Expected no spans, got %v", spans)
+	}
+}
+
+func TestReport(t *testing.T) {
+	p := New()
+	base := time.Now()
+	p.spans = []Span{
+		{Phase: Download, Name: "rg", Start: base, Duration: 300 * time.Millisecond},
+		{Phase: Download, Name: "fd", Start: base, Duration: 200 * time.Millisecond},
+		{Phase: Extract, Name: "rg", Start: base, Duration: 40 * time.Millisecond},
+	}
+
+	var out bytes.Buffer
+	p.Report(&out)
+	report := out.String()
+	for _, want := range []string{"Profile (", "download  300ms", "rg 300ms, fd 200ms", "extract   40ms"} {
+		if !strings.Contains(report, want) {
+			t.Errorf("Expected %q in report:\n%s", want, report)
+		}
+	}
+	if strings.Contains(report, "resolve") {
+		t.Errorf("Phases without spans should be omitted:\n%s", report)
+	}
+}
+
+func TestWriteTrace(t *testing.T) {
+	p := New()
+	p.Start(Download, "rg")()
+	p.Start(Download, "fd")()
+
+	path := filepath.Join(t.TempDir(), "trace.json")
+	if err := p.WriteTrace(path); err != nil {
+		t.Fatalf("WriteTrace() error = %v", err)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var trace struct {
+		TraceEvents []traceEvent `json:"traceEvents"`
+	}
+	if err := json.Unmarshal(data, &trace); err != nil {
+		t.Fatalf("Invalid trace JSON: %v", err)
+	}
+	if len(trace.TraceEvents) != 2 || trace.TraceEvents[0].Phase != "X" || trace.TraceEvents[0].TID == trace.TraceEvents[1].TID {
+		t.Errorf("Unexpected trace events: %+v", trace.TraceEvents)
+	}
+}
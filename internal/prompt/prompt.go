@@ -0,0 +1,158 @@
+package prompt
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+
+	"golang.org/x/term"
+)
+
+// ErrNoInput is returned when the input ends before an answer is given
+var ErrNoInput = errors.New("no input available for prompt")
+
+// ErrNonInteractive is returned when a question needs an answer but prompting is disabled
+var ErrNonInteractive = errors.New("an answer is required but prompting is disabled (pass --yes to accept the defaults)")
+
+// maxAttempts is how often an invalid answer is asked again
+const maxAttempts = 3
+
+// mu serializes prompts so concurrent callers never interleave questions
+var mu sync.Mutex
+
+// Prompter asks questions on a pair of streams
+type Prompter struct {
+	In             *bufio.Reader
+	Out            io.Writer
+	AssumeYes      bool // Answer confirmations with yes and selections with their default
+	NonInteractive bool // Fail with ErrNonInteractive instead of asking
+}
+
+// New creates a prompter reading answers from in and writing questions to out
+func New(in io.Reader, out io.Writer) *Prompter {
+	return &Prompter{In: bufio.NewReader(in), Out: out}
+}
+
+// stdPrompter is shared so buffered input is not lost between prompts
+var stdPrompter = New(os.Stdin, os.Stdout)
+
+// Default returns the prompter on standard input and output
+func Default() *Prompter {
+	return stdPrompter
+}
+
+// Options controls how the default prompter answers questions
+type Options struct {
+	AssumeYes      bool // --yes
+	NonInteractive bool // --non-interactive
+}
+
+// Configure sets how the default prompter answers questions. Call it once at startup.
+func Configure(opts Options) {
+	mu.Lock()
+	defer mu.Unlock()
+	stdPrompter.AssumeYes = opts.AssumeYes
+	stdPrompter.NonInteractive = opts.NonInteractive
+}
+
+// IsInteractive reports whether standard input and output are both terminals and
+// prompting is not disabled, so optional questions can be asked
+func IsInteractive() bool {
+	if stdPrompter.AssumeYes || stdPrompter.NonInteractive {
+		return false
+	}
+	return term.IsTerminal(int(os.Stdin.Fd())) && term.IsTerminal(int(os.Stdout.Fd()))
+}
+
+// Confirm asks a yes/no question. An empty answer picks defaultYes. With AssumeYes the
+// answer is yes without asking.
+func (p *Prompter) Confirm(question string, defaultYes bool) (bool, error) {
+	mu.Lock()
+	defer mu.Unlock()
+
+	if p.AssumeYes {
+		fmt.Fprintf(p.Out, "%s yes (--yes)\n", question)
+		return true, nil
+	}
+	if p.NonInteractive {
+		return false, fmt.Errorf("%s %w", question, ErrNonInteractive)
+	}
+
+	choices := "[y/N]"
+	if defaultYes {
+		choices = "[Y/n]"
+	}
+	for attempt := 0; attempt < maxAttempts; attempt++ {
+		fmt.Fprintf(p.Out, "%s %s: ", question, choices)
+
+		line, err := p.In.ReadString('\n')
+		answer := strings.ToLower(strings.TrimSpace(line))
+		if err != nil && answer == "" {
+			fmt.Fprintln(p.Out)
+			return false, ErrNoInput
+		}
+
+		switch answer {
+		case "":
+			return defaultYes, nil
+		case "y", "yes":
+			return true, nil
+		case "n", "no":
+			return false, nil
+		}
+		fmt.Fprintln(p.Out, "Please answer yes or no")
+	}
+	return false, fmt.Errorf("no valid answer after %d attempts", maxAttempts)
+}
+
+// Select asks the user to pick one of options by number and returns its index.
+// An empty answer picks defaultIndex, as does AssumeYes without asking.
+func (p *Prompter) Select(question string, options []string, defaultIndex int) (int, error) {
+	if len(options) == 0 {
+		return 0, fmt.Errorf("nothing to select")
+	}
+	if defaultIndex < 0 || defaultIndex >= len(options) {
+		defaultIndex = 0
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+
+	if p.AssumeYes {
+		fmt.Fprintf(p.Out, "%s %s (--yes)\n", question, options[defaultIndex])
+		return defaultIndex, nil
+	}
+	if p.NonInteractive {
+		return 0, fmt.Errorf("%s %w", question, ErrNonInteractive)
+	}
+
+	fmt.Fprintln(p.Out, question)
+	for i, option := range options {
+		fmt.Fprintf(p.Out, "  %d) %s\n", i+1, option)
+	}
+
+	for attempt := 0; attempt < maxAttempts; attempt++ {
+		fmt.Fprintf(p.Out, "Select [1-%d] (default %d): ", len(options), defaultIndex+1)
+
+		line, err := p.In.ReadString('\n')
+		answer := strings.TrimSpace(line)
+		if err != nil && answer == "" {
+			fmt.Fprintln(p.Out)
+			return 0, ErrNoInput
+		}
+
+		if answer == "" {
+			return defaultIndex, nil
+		}
+		if n, err := strconv.Atoi(answer); err == nil && n >= 1 && n <= len(options) {
+			return n - 1, nil
+		}
+		fmt.Fprintf(p.Out, "Please enter a number between 1 and %d\n", len(options))
+	}
+	return 0, fmt.Errorf("no valid selection after %d attempts", maxAttempts)
+}
@@ -0,0 +1,117 @@
+package prompt
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestSelect(t *testing.T) {
+	options := []string{"tool.tar.gz", "tool.deb", "tool.rpm"}
+
+	tests := []struct {
+		name    string
+		input   string
+		want    int
+		wantErr bool
+	}{
+		{"explicit choice", "2\n", 1, false},
+		{"default on empty answer", "\n", 2, false},
+		{"retry after invalid answer", "abc\n9\n3\n", 2, false},
+		{"answer without newline", "1", 0, false},
+		{"too many invalid answers", "x\ny\nz\n", 0, true},
+		{"no input", "", 0, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var out bytes.Buffer
+			p := New(strings.NewReader(tt.input), &out)
+
+			got, err := p.Select("Choose an asset:", options, 2)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Select() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && got != tt.want {
+				t.Errorf("Expected %d, got %d", tt.want, got)
+			}
+			if !strings.Contains(out.String(), "2) tool.deb") {
+				t.Errorf("Expected numbered options in output, got %q", out.String())
+			}
+		})
+	}
+}
+
+func TestSelectNoInput(t *testing.T) {
+	p := New(strings.NewReader(""), &bytes.Buffer{})
+	if _, err := p.Select("Choose:", []string{"a"}, 0); !errors.Is(err, ErrNoInput) {
+		t.Errorf("Expected ErrNoInput, got %v", err)
+	}
+}
+
+func TestSelectEmptyOptions(t *testing.T) {
+	p := New(strings.NewReader("1\n"), &bytes.Buffer{})
+	if _, err := p.Select("Choose:", nil, 0); err == nil {
+		t.Error("Expected error for empty options")
+	}
+}
+
+func TestConfirm(t *testing.T) {
+	tests := []struct {
+		name       string
+		input      string
+		defaultYes bool
+		want       bool
+		wantErr    bool
+	}{
+		{"yes", "y\n", false, true, false},
+		{"no", "NO\n", true, false, false},
+		{"default yes", "\n", true, true, false},
+		{"default no", "\n", false, false, false},
+		{"retry after invalid answer", "maybe\nyes\n", false, true, false},
+		{"too many invalid answers", "a\nb\nc\n", false, false, true},
+		{"no input", "", true, false, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := New(strings.NewReader(tt.input), &bytes.Buffer{})
+			got, err := p.Confirm("Overwrite tool?", tt.defaultYes)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Confirm() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("Expected %v, got %v", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestAssumeYes(t *testing.T) {
+	var out bytes.Buffer
+	p := New(strings.NewReader(""), &out)
+	p.AssumeYes = true
+
+	if ok, err := p.Confirm("Overwrite tool?", false); err != nil || !ok {
+		t.Errorf("Expected yes without input, got %v, %v", ok, err)
+	}
+	if index, err := p.Select("Choose:", []string{"a", "b"}, 1); err != nil || index != 1 {
+		t.Errorf("Expected the default selection without input, got %d, %v", index, err)
+	}
+	if !strings.Contains(out.String(), "Choose: b (--yes)") {
+		t.Errorf("Expected the automatic answer in output, got %q", out.String())
+	}
+}
+
+func TestNonInteractive(t *testing.T) {
+	p := New(strings.NewReader("y\n"), &bytes.Buffer{})
+	p.NonInteractive = true
+
+	if _, err := p.Confirm("Overwrite tool?", true); !errors.Is(err, ErrNonInteractive) {
+		t.Errorf("Expected ErrNonInteractive from Confirm, got %v", err)
+	}
+	if _, err := p.Select("Choose:", []string{"a"}, 0); !errors.Is(err, ErrNonInteractive) {
+		t.Errorf("Expected ErrNonInteractive from Select, got %v", err)
+	}
+}
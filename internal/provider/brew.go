@@ -0,0 +1,164 @@
+package provider
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"path"
+	"strings"
+
+	"github.com/pyhub-kr/pyhub-installer/internal/brew"
+	"github.com/pyhub-kr/pyhub-installer/internal/download"
+)
+
+func init() {
+	Register("brew", func() ReleaseProvider {
+		return NewBrewProvider(brew.NewClient())
+	})
+}
+
+// caskPrefix selects a cask instead of a formula, e.g. "brew:cask/ngrok"
+const caskPrefix = "cask/"
+
+// formulaBinaries matches the executables of a bottle (<name>/<version>/bin/*)
+const formulaBinaries = "*/*/bin/*"
+
+// BrewProvider installs the prebuilt bottles of Homebrew formulae and the binaries of
+// Homebrew casks without Homebrew itself
+type BrewProvider struct {
+	Client *brew.Client
+}
+
+// NewBrewProvider creates a provider backed by a Homebrew API client
+func NewBrewProvider(client *brew.Client) *BrewProvider {
+	return &BrewProvider{Client: client}
+}
+
+// Resolve returns the current version of the formula or cask. Homebrew only describes
+// the current version, so older versions cannot be installed.
+func (p *BrewProvider) Resolve(ctx context.Context, src Source, version string) (*Release, error) {
+	release, err := p.release(src)
+	if err != nil {
+		return nil, err
+	}
+	if version != "" && version != "latest" && strings.TrimPrefix(version, "v") != release.TagName {
+		return nil, notFound{fmt.Errorf("Homebrew only provides the current version of %s (%s)", src.Path, release.TagName)}
+	}
+	return release, nil
+}
+
+// ListVersions returns the current version
+func (p *BrewProvider) ListVersions(ctx context.Context, src Source) ([]Release, error) {
+	release, err := p.release(src)
+	if err != nil {
+		return nil, err
+	}
+	return []Release{*release}, nil
+}
+
+// Assets returns the platform downloads of the release
+func (p *BrewProvider) Assets(ctx context.Context, src Source, release *Release) ([]Asset, error) {
+	return release.Assets, nil
+}
+
+// AssetRequest adds the anonymous registry token that bottle downloads require
+func (p *BrewProvider) AssetRequest(ctx context.Context, asset *Asset) (string, map[string]string, error) {
+	if brew.IsBottleURL(asset.BrowserDownloadURL) {
+		return asset.BrowserDownloadURL, map[string]string{"Authorization": "Bearer " + brew.BottleToken}, nil
+	}
+	return asset.BrowserDownloadURL, nil, nil
+}
+
+// Download fetches an asset with the parallel chunk downloader
+func (p *BrewProvider) Download(ctx context.Context, asset *Asset, dest string) error {
+	assetURL, headers, err := p.AssetRequest(ctx, asset)
+	if err != nil {
+		return err
+	}
+
+	downloader := download.NewChunkDownloader(assetURL, dest)
+	downloader.Headers = headers
+	return downloader.Download(ctx)
+}
+
+// release describes the formula or cask of a source as a release with one asset per platform
+func (p *BrewProvider) release(src Source) (*Release, error) {
+	name := strings.Trim(src.Path, "/")
+
+	if token, ok := strings.CutPrefix(name, caskPrefix); ok {
+		cask, err := p.Client.GetCask(token)
+		if err != nil {
+			return nil, brewError(err)
+		}
+		binaries := cask.Binaries()
+		if len(binaries) == 0 {
+			return nil, fmt.Errorf("cask %s has no binary outside an app bundle", token)
+		}
+		release := brewRelease(token, cask.Version, cask.Downloads(), binaries)
+		if len(release.Assets) == 0 {
+			return nil, fmt.Errorf("cask %s ships no zip or tar.gz archive (dmg and pkg are not supported)", token)
+		}
+		return release, nil
+	}
+
+	formula, err := p.Client.GetFormula(name)
+	if err != nil {
+		return nil, brewError(err)
+	}
+	release := brewRelease(formula.Name, formula.Version(), formula.Downloads(), []string{formulaBinaries})
+	if len(release.Assets) == 0 {
+		return nil, fmt.Errorf("formula %s has no bottles", name)
+	}
+	return release, nil
+}
+
+// brewError makes the errors of missing formulae and casks match ErrNotFound
+func brewError(err error) error {
+	if errors.Is(err, brew.ErrNotFound) {
+		return notFound{err}
+	}
+	return err
+}
+
+// brewRelease builds a release whose asset names carry the platform, so that the
+// usual platform detection picks the right download
+func brewRelease(name, version string, downloads []brew.Download, binaries []string) *Release {
+	release := &Release{TagName: version, Name: name + " " + version}
+	for _, d := range downloads {
+		ext := archiveExt(d.URL)
+		if ext == "" {
+			continue
+		}
+		asset := Asset{
+			Name:               fmt.Sprintf("%s-%s-%s%s", name, version, d.Platform, ext),
+			BrowserDownloadURL: d.URL,
+			Binaries:           binaries,
+		}
+		if d.SHA256 != "" {
+			asset.Digest = "sha256:" + d.SHA256
+		}
+		release.Assets = append(release.Assets, asset)
+	}
+	return release
+}
+
+// archiveExt returns the extension to name a download by, or "" for unsupported formats.
+// Bottle URLs are registry blobs without an extension.
+func archiveExt(rawURL string) string {
+	if brew.IsBottleURL(rawURL) {
+		return ".bottle.tar.gz"
+	}
+	name := rawURL
+	if u, err := url.Parse(rawURL); err == nil {
+		name = u.Path
+	}
+	name = strings.ToLower(path.Base(name))
+	switch {
+	case strings.HasSuffix(name, ".tar.gz"), strings.HasSuffix(name, ".tgz"):
+		return ".tar.gz"
+	case strings.HasSuffix(name, ".zip"):
+		return ".zip"
+	}
+	return ""
+}
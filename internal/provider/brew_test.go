@@ -0,0 +1,92 @@
+package provider
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/pyhub-kr/pyhub-installer/internal/brew"
+)
+
+func TestBrewProvider(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/formula/jq.json":
+			w.Write([]byte(`{"name": "jq", "versions": {"stable": "1.7.1"}, "bottle": {"stable": {"files": {
+				"arm64_sonoma": {"url": "https://ghcr.io/v2/homebrew/core/jq/blobs/sha256:aa", "sha256": "aa"},
+				"x86_64_linux": {"url": "https://ghcr.io/v2/homebrew/core/jq/blobs/sha256:bb", "sha256": "bb"}
+			}}}}`))
+		case "/cask/tool.json":
+			w.Write([]byte(`{"token": "tool", "version": "2.0", "url": "https://example.com/tool.dmg", "sha256": "cc",
+				"artifacts": [{"binary": ["tool"]}]}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	p := NewBrewProvider(&brew.Client{BaseURL: server.URL})
+	ctx := context.Background()
+	src := Source{Scheme: "brew", Path: "jq"}
+
+	release, err := p.Resolve(ctx, src, "latest")
+	if err != nil {
+		t.Fatalf("Resolve() error = %v", err)
+	}
+	if release.TagName != "1.7.1" || len(release.Assets) != 2 {
+		t.Fatalf("Expected 1.7.1 with 2 bottles, got %+v", release)
+	}
+
+	asset, err := release.FindAssetForPlatform("linux-amd64")
+	if err != nil {
+		t.Fatalf("FindAssetForPlatform() error = %v", err)
+	}
+	if asset.Name != "jq-1.7.1-linux-amd64.bottle.tar.gz" || asset.Digest != "sha256:bb" {
+		t.Errorf("Unexpected asset %s (%s)", asset.Name, asset.Digest)
+	}
+	if len(asset.Binaries) != 1 || asset.Binaries[0] != formulaBinaries {
+		t.Errorf("Expected bottle binaries, got %v", asset.Binaries)
+	}
+
+	url, headers, err := AssetRequest(ctx, p, asset)
+	if err != nil || url != asset.BrowserDownloadURL || headers["Authorization"] != "Bearer "+brew.BottleToken {
+		t.Errorf("Expected registry token for bottle download, got %s %v %v", url, headers, err)
+	}
+
+	if _, err := p.Resolve(ctx, src, "v1.7.1"); err != nil {
+		t.Errorf("Expected current version to resolve, got %v", err)
+	}
+	if _, err := p.Resolve(ctx, src, "1.6"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Expected ErrNotFound for an old version, got %v", err)
+	}
+	if _, err := p.Resolve(ctx, Source{Scheme: "brew", Path: "missing"}, "latest"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Expected ErrNotFound for a missing formula, got %v", err)
+	}
+	if _, err := p.Resolve(ctx, Source{Scheme: "brew", Path: "cask/missing"}, "latest"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Expected ErrNotFound for a missing cask, got %v", err)
+	}
+
+	if _, err := p.Resolve(ctx, Source{Scheme: "brew", Path: "cask/tool"}, "latest"); err == nil {
+		t.Error("Expected error for a cask without zip or tar.gz archives")
+	}
+}
+
+func TestArchiveExt(t *testing.T) {
+	tests := []struct {
+		url      string
+		expected string
+	}{
+		{"https://ghcr.io/v2/homebrew/core/jq/blobs/sha256:aa", ".bottle.tar.gz"},
+		{"https://example.com/tool.zip?download=1", ".zip"},
+		{"https://example.com/tool.tgz", ".tar.gz"},
+		{"https://example.com/tool.dmg", ""},
+	}
+
+	for _, tt := range tests {
+		if got := archiveExt(tt.url); got != tt.expected {
+			t.Errorf("archiveExt(%s): expected %q, got %q", tt.url, tt.expected, got)
+		}
+	}
+}
@@ -0,0 +1,87 @@
+package provider
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+// Release channels
+const (
+	ChannelStable  = "stable"
+	ChannelBeta    = "beta"
+	ChannelRC      = "rc"
+	ChannelNightly = "nightly"
+	ChannelPre     = "pre" // Any pre-release (--pre)
+)
+
+// channelKeywords maps channels to the tag markers of their pre-releases
+var channelKeywords = map[string][]string{
+	ChannelStable:  nil,
+	ChannelBeta:    {"beta"},
+	ChannelRC:      {"rc"},
+	ChannelNightly: {"nightly"},
+	ChannelPre:     nil,
+}
+
+// ValidateChannel checks if a channel name is supported
+func ValidateChannel(channel string) error {
+	if _, ok := channelKeywords[channel]; !ok {
+		return fmt.Errorf("unknown channel: %s (expected stable, beta, rc or nightly)", channel)
+	}
+	return nil
+}
+
+// InChannel reports whether a release is published on a channel.
+// Stable releases belong to every channel, so a pre-release channel moves on to a
+// stable release once it is newer than the last matching pre-release.
+func InChannel(release *Release, channel string) bool {
+	if release.Draft {
+		return false
+	}
+	if !release.Prerelease {
+		return true
+	}
+	if channel == ChannelPre {
+		return true
+	}
+
+	for _, token := range tagTokens(release.TagName) {
+		for _, keyword := range channelKeywords[channel] {
+			if token == keyword {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// SelectChannel returns the newest release on a channel from releases listed newest first
+func SelectChannel(releases []Release, channel string) (*Release, error) {
+	if err := ValidateChannel(channel); err != nil {
+		return nil, err
+	}
+
+	for i := range releases {
+		if InChannel(&releases[i], channel) {
+			return &releases[i], nil
+		}
+	}
+	return nil, fmt.Errorf("no release found on channel: %s", channel)
+}
+
+// tagTokens splits a tag into lowercase words without trailing numbers ("v1.0.0-rc.2" -> v, rc)
+func tagTokens(tag string) []string {
+	words := strings.FieldsFunc(strings.ToLower(tag), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+
+	var tokens []string
+	for _, word := range words {
+		word = strings.TrimRightFunc(word, unicode.IsDigit)
+		if word != "" {
+			tokens = append(tokens, word)
+		}
+	}
+	return tokens
+}
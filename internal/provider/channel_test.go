@@ -0,0 +1,88 @@
+package provider
+
+import "testing"
+
+func TestSelectChannel(t *testing.T) {
+	releases := []Release{
+		{TagName: "v2.1.0-nightly.20250101", Prerelease: true},
+		{TagName: "v2.1.0-draft", Draft: true},
+		{TagName: "v2.0.0-rc2", Prerelease: true},
+		{TagName: "v2.0.0-beta.1", Prerelease: true},
+		{TagName: "v1.9.0"},
+		{TagName: "v1.9.0-beta.3", Prerelease: true},
+	}
+
+	tests := []struct {
+		channel string
+		want    string
+		wantErr bool
+	}{
+		{ChannelStable, "v1.9.0", false},
+		{ChannelBeta, "v2.0.0-beta.1", false},
+		{ChannelRC, "v2.0.0-rc2", false},
+		{ChannelNightly, "v2.1.0-nightly.20250101", false},
+		{ChannelPre, "v2.1.0-nightly.20250101", false},
+		{"alpha", "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.channel, func(t *testing.T) {
+			release, err := SelectChannel(releases, tt.channel)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("SelectChannel() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && release.TagName != tt.want {
+				t.Errorf("Expected %s, got %s", tt.want, release.TagName)
+			}
+		})
+	}
+}
+
+func TestSelectChannelPrefersNewerStable(t *testing.T) {
+	releases := []Release{
+		{TagName: "v3.0.0"},
+		{TagName: "v3.0.0-beta.2", Prerelease: true},
+	}
+
+	release, err := SelectChannel(releases, ChannelBeta)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if release.TagName != "v3.0.0" {
+		t.Errorf("Beta channel should move to newer stable release, got %s", release.TagName)
+	}
+}
+
+func TestSelectChannelNoMatch(t *testing.T) {
+	releases := []Release{{TagName: "v1.0.0-beta", Prerelease: true}}
+
+	if _, err := SelectChannel(releases, ChannelStable); err == nil {
+		t.Error("Expected error when no stable release exists")
+	}
+}
+
+func TestTagTokens(t *testing.T) {
+	tests := []struct {
+		tag  string
+		want []string
+	}{
+		{"v1.0.0-rc.2", []string{"v", "rc"}},
+		{"v2.0.0-beta1", []string{"v", "beta"}},
+		{"nightly-2025-01-01", []string{"nightly"}},
+		{"release-arch", []string{"release", "arch"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.tag, func(t *testing.T) {
+			got := tagTokens(tt.tag)
+			if len(got) != len(tt.want) {
+				t.Fatalf("tagTokens(%s) = %v, want %v", tt.tag, got, tt.want)
+			}
+			for i := range got {
+				if got[i] != tt.want[i] {
+					t.Errorf("tagTokens(%s) = %v, want %v", tt.tag, got, tt.want)
+				}
+			}
+		})
+	}
+}
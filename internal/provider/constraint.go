@@ -0,0 +1,39 @@
+package provider
+
+import (
+	"fmt"
+
+	"github.com/pyhub-kr/pyhub-installer/internal/semver"
+)
+
+// SelectConstraint returns the highest release whose tag satisfies a version constraint.
+// Pre-releases are only considered when the constraint names one or when they are on
+// the requested channel; tags that are not semantic versions are ignored.
+func SelectConstraint(releases []Release, c *semver.Constraint, channel string) (*Release, error) {
+	var best *Release
+	var bestVersion *semver.Version
+
+	for i := range releases {
+		release := &releases[i]
+		if release.Draft {
+			continue
+		}
+		if release.Prerelease && !c.AllowsPrerelease() &&
+			(channel == "" || channel == ChannelStable || !InChannel(release, channel)) {
+			continue
+		}
+
+		v, err := semver.Parse(release.TagName)
+		if err != nil || !c.Check(v) {
+			continue
+		}
+		if bestVersion == nil || v.Compare(bestVersion) > 0 {
+			best, bestVersion = release, v
+		}
+	}
+
+	if best == nil {
+		return nil, fmt.Errorf("no release matches version constraint: %s", c)
+	}
+	return best, nil
+}
@@ -0,0 +1,56 @@
+package provider
+
+import (
+	"testing"
+
+	"github.com/pyhub-kr/pyhub-installer/internal/semver"
+)
+
+func TestSelectConstraint(t *testing.T) {
+	releases := []Release{
+		{TagName: "v3.0.0-rc.1", Prerelease: true},
+		{TagName: "v2.1.0"},
+		{TagName: "nightly-build", Prerelease: true},
+		{TagName: "v1.10.0"},
+		{TagName: "v2.0.0"},
+		{TagName: "v2.2.0", Draft: true},
+		{TagName: "v1.4.2"},
+		{TagName: "1.3.0"},
+	}
+
+	tests := []struct {
+		constraint string
+		channel    string
+		want       string
+		wantErr    bool
+	}{
+		{"^1.4", "", "v1.10.0", false},
+		{"~1.4", "", "v1.4.2", false},
+		{">=2,<3", "", "v2.1.0", false},
+		{"2.x", "", "v2.1.0", false},
+		{"<1.4", "", "1.3.0", false},
+		{">=2", "", "v2.1.0", false},
+		{">=2", ChannelRC, "v3.0.0-rc.1", false},
+		{">=2", ChannelPre, "v3.0.0-rc.1", false},
+		{">=2", ChannelBeta, "v2.1.0", false},
+		{">=3.0.0-rc.1", "", "v3.0.0-rc.1", false},
+		{"^4", "", "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.constraint+"_"+tt.channel, func(t *testing.T) {
+			c, err := semver.ParseConstraint(tt.constraint)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			release, err := SelectConstraint(releases, c, tt.channel)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("SelectConstraint() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && release.TagName != tt.want {
+				t.Errorf("Expected %s, got %s", tt.want, release.TagName)
+			}
+		})
+	}
+}
@@ -0,0 +1,177 @@
+package provider
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+	"sync"
+
+	"github.com/pyhub-kr/pyhub-installer/internal/credential"
+	"github.com/pyhub-kr/pyhub-installer/internal/download"
+	"github.com/pyhub-kr/pyhub-installer/internal/gitea"
+)
+
+func init() {
+	// Forgejo is a fork of Gitea with the same releases API
+	for _, scheme := range []string{"gitea", "forgejo"} {
+		Register(scheme, func() ReleaseProvider {
+			return &GiteaProvider{}
+		})
+	}
+}
+
+// GiteaProvider serves releases from self-hosted Gitea and Forgejo servers, for sources
+// such as "gitea:https://git.example.com/owner/repo". Requests are authenticated with
+// GITEA_TOKEN (or FORGEJO_TOKEN), or else the credential helper of the server's host.
+type GiteaProvider struct {
+	mu      sync.Mutex
+	clients map[string]*gitea.Client // By server URL
+}
+
+// Resolve returns the release for a tag, or the latest release
+func (p *GiteaProvider) Resolve(ctx context.Context, src Source, version string) (*Release, error) {
+	client, owner, repo, err := p.client(ctx, src)
+	if err != nil {
+		return nil, err
+	}
+
+	var release *gitea.Release
+	if version == "" || version == "latest" {
+		release, err = client.GetLatestRelease(owner, repo)
+		if errors.Is(err, gitea.ErrNotFound) {
+			err = fmt.Errorf("%s has no published releases: %w", src, err)
+		}
+	} else {
+		release, err = client.GetRelease(owner, repo, version)
+	}
+	if err != nil {
+		return nil, giteaError(err)
+	}
+	return giteaRelease(release), nil
+}
+
+// ListVersions returns the published releases, newest first
+func (p *GiteaProvider) ListVersions(ctx context.Context, src Source) ([]Release, error) {
+	client, owner, repo, err := p.client(ctx, src)
+	if err != nil {
+		return nil, err
+	}
+	releases, err := client.ListReleases(owner, repo)
+	if err != nil {
+		return nil, giteaError(err)
+	}
+
+	var result []Release
+	for i := range releases {
+		if !releases[i].Draft {
+			result = append(result, *giteaRelease(&releases[i]))
+		}
+	}
+	return result, nil
+}
+
+// Assets returns the files attached to the release
+func (p *GiteaProvider) Assets(ctx context.Context, src Source, release *Release) ([]Asset, error) {
+	return release.Assets, nil
+}
+
+// SourceArchive returns the tarball the server generates for the release's tag
+func (p *GiteaProvider) SourceArchive(ctx context.Context, src Source, release *Release) (*Asset, error) {
+	client, owner, repo, err := p.client(ctx, src)
+	if err != nil {
+		return nil, err
+	}
+	return &Asset{
+		Name:               fmt.Sprintf("%s-%s.tar.gz", repo, strings.TrimPrefix(release.TagName, "v")),
+		BrowserDownloadURL: fmt.Sprintf("%s/%s/%s/archive/%s.tar.gz", client.BaseURL, owner, repo, url.PathEscape(release.TagName)),
+	}, nil
+}
+
+// AssetRequest adds the token of the asset's server, so that attachments of private
+// repositories, including signature files, can be fetched
+func (p *GiteaProvider) AssetRequest(ctx context.Context, asset *Asset) (string, map[string]string, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	for baseURL, client := range p.clients {
+		if strings.HasPrefix(asset.BrowserDownloadURL, baseURL+"/") {
+			return asset.BrowserDownloadURL, client.AuthHeaders(), nil
+		}
+	}
+	return asset.BrowserDownloadURL, nil, nil
+}
+
+// Download fetches an asset with the parallel chunk downloader
+func (p *GiteaProvider) Download(ctx context.Context, asset *Asset, dest string) error {
+	assetURL, headers, err := p.AssetRequest(ctx, asset)
+	if err != nil {
+		return err
+	}
+
+	downloader := download.NewChunkDownloader(assetURL, dest)
+	downloader.Headers = headers
+	return downloader.Download(ctx)
+}
+
+// client returns the API client of the source's server, created on first use, and the
+// repository the source names
+func (p *GiteaProvider) client(ctx context.Context, src Source) (*gitea.Client, string, string, error) {
+	baseURL, owner, repo, err := gitea.ParseRepoURL(src.Path)
+	if err != nil {
+		return nil, "", "", err
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if client, ok := p.clients[baseURL]; ok {
+		return client, owner, repo, nil
+	}
+
+	client := gitea.NewClient(baseURL)
+	if client.Token == "" {
+		if u, err := url.Parse(baseURL); err == nil {
+			cred, err := credential.Default.Get(ctx, u.Hostname())
+			if err != nil {
+				return nil, "", "", err
+			}
+			if cred != nil {
+				client.Token = cred.Password
+			}
+		}
+	}
+	if p.clients == nil {
+		p.clients = make(map[string]*gitea.Client)
+	}
+	p.clients[baseURL] = client
+	return client, owner, repo, nil
+}
+
+// giteaError makes the errors of missing repositories and releases match ErrNotFound
+func giteaError(err error) error {
+	if errors.Is(err, gitea.ErrNotFound) {
+		return notFound{err}
+	}
+	return err
+}
+
+// giteaRelease describes a Gitea release in the common release shape
+func giteaRelease(r *gitea.Release) *Release {
+	release := &Release{
+		TagName:     r.TagName,
+		Name:        r.Name,
+		Body:        r.Body,
+		Draft:       r.Draft,
+		Prerelease:  r.Prerelease,
+		PublishedAt: r.PublishedAt,
+		HTMLURL:     r.HTMLURL,
+	}
+	for _, asset := range r.Assets {
+		release.Assets = append(release.Assets, Asset{
+			Name:               asset.Name,
+			Size:               asset.Size,
+			BrowserDownloadURL: asset.BrowserDownloadURL,
+		})
+	}
+	return release
+}
@@ -0,0 +1,78 @@
+package provider
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestGiteaProvider(t *testing.T) {
+	t.Setenv("GITEA_TOKEN", "secret")
+
+	var server *httptest.Server
+	server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		asset := server.URL + "/owner/tool/releases/download/v1.1.0/tool_linux_amd64.tar.gz"
+		switch r.URL.Path {
+		case "/api/v1/repos/owner/tool/releases/latest":
+			w.Write([]byte(`{"tag_name": "v1.1.0", "assets": [
+				{"name": "tool_linux_amd64.tar.gz", "size": 10, "browser_download_url": "` + asset + `"},
+				{"name": "tool_linux_amd64.tar.gz.sig", "size": 1, "browser_download_url": "` + asset + `.sig"}
+			]}`))
+		case "/api/v1/repos/owner/tool/releases":
+			w.Write([]byte(`[{"tag_name": "v1.2.0", "draft": true}, {"tag_name": "v1.1.0"}, {"tag_name": "v1.0.0"}]`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	p, src, err := Parse("gitea:" + server.URL + "/owner/tool")
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+	if src.Name() != "tool" {
+		t.Errorf("Expected tool name, got %s", src.Name())
+	}
+	ctx := context.Background()
+
+	release, err := p.Resolve(ctx, src, "latest")
+	if err != nil {
+		t.Fatalf("Resolve() error = %v", err)
+	}
+	if release.TagName != "v1.1.0" || len(release.Assets) != 2 {
+		t.Fatalf("Expected v1.1.0 with 2 assets, got %+v", release)
+	}
+	if sig, err := release.FindSignatureAsset("tool_linux_amd64.tar.gz"); err != nil || sig.Name != "tool_linux_amd64.tar.gz.sig" {
+		t.Errorf("Expected signature asset, got %v, %v", sig, err)
+	}
+
+	url, headers, err := AssetRequest(ctx, p, &release.Assets[0])
+	if err != nil || url != release.Assets[0].BrowserDownloadURL || headers["Authorization"] != "token secret" {
+		t.Errorf("Expected token for asset download, got %s %v %v", url, headers, err)
+	}
+	if _, headers, _ := AssetRequest(ctx, p, &Asset{BrowserDownloadURL: "https://elsewhere.example.com/file"}); headers != nil {
+		t.Errorf("Expected no token for another host, got %v", headers)
+	}
+
+	archive, err := p.(SourceArchiver).SourceArchive(ctx, src, release)
+	if err != nil || archive.Name != "tool-1.1.0.tar.gz" || archive.BrowserDownloadURL != server.URL+"/owner/tool/archive/v1.1.0.tar.gz" {
+		t.Errorf("Expected the tag's source archive, got %v, %v", archive, err)
+	}
+
+	versions, err := p.ListVersions(ctx, src)
+	if err != nil {
+		t.Fatalf("ListVersions() error = %v", err)
+	}
+	if len(versions) != 2 || versions[0].TagName != "v1.1.0" {
+		t.Errorf("Expected 2 published releases, got %+v", versions)
+	}
+
+	if _, err := p.Resolve(ctx, src, "v9.0.0"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Expected ErrNotFound for missing release, got %v", err)
+	}
+	if _, err := p.Resolve(ctx, Source{Scheme: "gitea", Path: "owner/tool"}, "latest"); err == nil {
+		t.Error("Expected error for a source without server URL")
+	}
+}
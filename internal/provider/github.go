@@ -0,0 +1,262 @@
+package provider
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/pyhub-kr/pyhub-installer/internal/download"
+	"github.com/pyhub-kr/pyhub-installer/internal/github"
+	"github.com/pyhub-kr/pyhub-installer/internal/i18n"
+	"github.com/pyhub-kr/pyhub-installer/internal/semver"
+	"github.com/pyhub-kr/pyhub-installer/internal/verify"
+)
+
+func init() {
+	Register("github", func() ReleaseProvider {
+		return NewGitHubProvider(github.NewClient())
+	})
+}
+
+// GitHubProvider serves releases from the GitHub releases API
+type GitHubProvider struct {
+	Client        *github.Client
+	IncludeDrafts bool         // Resolve draft releases by tag (requires a token)
+	Run           *RunSelector // Install workflow run artifacts instead of releases
+	Mirrors       []string     // Mirror URL templates for anonymous asset downloads, tried in order
+}
+
+// NewGitHubProvider creates a provider backed by a GitHub client
+func NewGitHubProvider(client *github.Client) *GitHubProvider {
+	return &GitHubProvider{Client: client}
+}
+
+// Resolve returns the release for a tag, or the latest release
+func (p *GitHubProvider) Resolve(ctx context.Context, src Source, version string) (*Release, error) {
+	owner, repo, err := github.ParseRepoURL(src.Path)
+	if err != nil {
+		return nil, err
+	}
+
+	if p.Run != nil {
+		return p.resolveRun(owner, repo)
+	}
+
+	if version == "" || version == "latest" {
+		release, err := p.Client.GetLatestRelease(owner, repo)
+		if errors.Is(err, github.ErrNotFound) {
+			if tags, tagErr := p.tagReleases(owner, repo); tagErr == nil && len(tags) > 0 {
+				return newestStable(tags), nil
+			}
+		}
+		return release, err
+	}
+
+	release, err := p.Client.GetRelease(owner, repo, version)
+	if err != nil && p.IncludeDrafts {
+		if draft, draftErr := p.Client.FindDraftRelease(owner, repo, version); draftErr == nil {
+			return draft, nil
+		}
+	}
+	if errors.Is(err, github.ErrNotFound) {
+		if tags, tagErr := p.tagReleases(owner, repo); tagErr == nil {
+			for i := range tags {
+				if tags[i].TagName == version {
+					return &tags[i], nil
+				}
+			}
+		}
+	}
+	return release, err
+}
+
+// tagReleases lists the tags of a repository as releases of their source archives,
+// newest first. Repositories without releases are installed from these.
+func (p *GitHubProvider) tagReleases(owner, repo string) ([]Release, error) {
+	tags, err := p.Client.ListTags(owner, repo)
+	if err != nil {
+		return nil, err
+	}
+
+	releases := make([]Release, len(tags))
+	for i, tag := range tags {
+		releases[i] = *p.Client.TagRelease(owner, repo, tag.Name)
+	}
+	sortNewestFirst(releases)
+	return releases, nil
+}
+
+// SetWorkflowRun makes the provider resolve workflow run artifacts, which needs an API token
+func (p *GitHubProvider) SetWorkflowRun(run RunSelector) error {
+	if p.Client.Token == "" {
+		return fmt.Errorf("workflow artifacts require GITHUB_TOKEN, even for public repositories")
+	}
+	p.Run = &run
+	return nil
+}
+
+// resolveRun returns the artifacts of the selected workflow run as a release
+func (p *GitHubProvider) resolveRun(owner, repo string) (*Release, error) {
+	var run *github.WorkflowRun
+	var err error
+	if p.Run.ID != 0 {
+		run, err = p.Client.GetWorkflowRun(owner, repo, p.Run.ID)
+	} else {
+		run, err = p.Client.LatestSuccessfulRun(owner, repo, p.Run.Branch, p.Run.Workflow)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	artifacts, err := p.Client.ListArtifacts(owner, repo, run.ID)
+	if err != nil {
+		return nil, err
+	}
+	release := github.RunRelease(run, artifacts)
+	if len(release.Assets) == 0 {
+		return nil, fmt.Errorf("workflow run %d has no unexpired artifacts", run.ID)
+	}
+	return release, nil
+}
+
+// SetIncludeDrafts enables resolving draft releases, which needs an API token
+func (p *GitHubProvider) SetIncludeDrafts(include bool) error {
+	if include && p.Client.Token == "" {
+		return fmt.Errorf("--include-drafts requires GITHUB_TOKEN with push access to the repository")
+	}
+	p.IncludeDrafts = include
+	return nil
+}
+
+// SetMirrors sets the mirror URL templates used for anonymous asset downloads
+func (p *GitHubProvider) SetMirrors(mirrors []string) {
+	p.Mirrors = mirrors
+}
+
+// ListVersions returns releases, newest first
+func (p *GitHubProvider) ListVersions(ctx context.Context, src Source) ([]Release, error) {
+	owner, repo, err := github.ParseRepoURL(src.Path)
+	if err != nil {
+		return nil, err
+	}
+	releases, err := p.Client.ListReleases(owner, repo)
+	if err != nil || len(releases) > 0 {
+		return releases, err
+	}
+	return p.tagReleases(owner, repo)
+}
+
+// Assets returns the assets of the release, paging beyond those embedded in it
+func (p *GitHubProvider) Assets(ctx context.Context, src Source, release *Release) ([]Asset, error) {
+	owner, repo, err := github.ParseRepoURL(src.Path)
+	if err != nil {
+		return nil, err
+	}
+	assets, err := p.Client.ReleaseAssets(owner, repo, release)
+	if err != nil || len(assets) > 0 {
+		return assets, err
+	}
+	// A release without uploads still has the source archive of its tag
+	return []Asset{p.Client.SourceArchive(owner, repo, release.TagName)}, nil
+}
+
+// SourceArchive returns the source archive of the release's tag
+func (p *GitHubProvider) SourceArchive(ctx context.Context, src Source, release *Release) (*Asset, error) {
+	owner, repo, err := github.ParseRepoURL(src.Path)
+	if err != nil {
+		return nil, err
+	}
+	archive := p.Client.SourceArchive(owner, repo, release.TagName)
+	return &archive, nil
+}
+
+// AssetRequest returns the URL and headers for downloading an asset. With a token,
+// assets are fetched through the API so private repositories and drafts work.
+func (p *GitHubProvider) AssetRequest(ctx context.Context, asset *Asset) (string, map[string]string, error) {
+	if p.Client.Token == "" || asset.URL == "" {
+		return asset.BrowserDownloadURL, nil, nil
+	}
+	return p.Client.AssetDownloadURL(asset)
+}
+
+// Download fetches an asset with the parallel chunk downloader
+func (p *GitHubProvider) Download(ctx context.Context, asset *Asset, dest string) error {
+	url, headers, err := p.AssetRequest(ctx, asset)
+	if err != nil {
+		return err
+	}
+
+	// Authenticated downloads go to GitHub directly
+	if p.Client.Token == "" && p.downloadFromMirrors(ctx, asset, url, dest) {
+		return nil
+	}
+
+	downloader := download.NewChunkDownloader(url, dest)
+	downloader.Headers = headers
+	return downloader.Download(ctx)
+}
+
+// downloadFromMirrors tries the configured mirrors in order and reports whether one
+// succeeded. Mirrors are untrusted: a mirrored file is only kept if it matches the
+// checksum of the original release asset.
+func (p *GitHubProvider) downloadFromMirrors(ctx context.Context, asset *Asset, original, dest string) bool {
+	if len(p.Mirrors) == 0 {
+		return false
+	}
+	expected, ok := strings.CutPrefix(asset.Digest, "sha256:")
+	if !ok {
+		fmt.Print(i18n.T("Note: no checksum known for %s, downloading from GitHub instead of a mirror\n", asset.Name))
+		return false
+	}
+
+	for _, template := range p.Mirrors {
+		mirrorURL, ok := MirrorURL(template, original)
+		if !ok {
+			return false
+		}
+
+		err := download.NewChunkDownloader(mirrorURL, dest).Download(ctx)
+		if err == nil {
+			err = verify.NewVerifier(dest).VerifyWithString(expected)
+		}
+		if err == nil {
+			return true
+		}
+		os.Remove(dest)
+		fmt.Print(i18n.T("Warning: mirror %s failed: %v\n", MirrorHost(mirrorURL), err))
+	}
+	return false
+}
+
+// sortNewestFirst orders releases by descending semantic version. Tags that are not
+// semantic versions keep their order after the others.
+func sortNewestFirst(releases []Release) {
+	versions := make(map[string]*semver.Version, len(releases))
+	for _, release := range releases {
+		if v, err := semver.Parse(release.TagName); err == nil {
+			versions[release.TagName] = v
+		}
+	}
+
+	sort.SliceStable(releases, func(i, j int) bool {
+		vi, vj := versions[releases[i].TagName], versions[releases[j].TagName]
+		if vi == nil || vj == nil {
+			return vi != nil && vj == nil
+		}
+		return vi.Compare(vj) > 0
+	})
+}
+
+// newestStable returns the first release that is not a pre-release version, or the
+// first release if all are
+func newestStable(releases []Release) *Release {
+	for i := range releases {
+		if v, err := semver.Parse(releases[i].TagName); err == nil && v.Prerelease == "" {
+			return &releases[i]
+		}
+	}
+	return &releases[0]
+}
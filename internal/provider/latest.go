@@ -0,0 +1,71 @@
+package provider
+
+import (
+	"fmt"
+	"regexp"
+)
+
+// LatestPolicy decides which listed release counts as "latest"
+type LatestPolicy struct {
+	IncludePrerelease bool
+	Skip              []*regexp.Regexp // Releases whose tag or name match are never chosen
+	Prefer            *regexp.Regexp   // Tags preferred over newer non-matching releases
+}
+
+// NewLatestPolicy compiles a latest policy from its patterns
+func NewLatestPolicy(includePrerelease bool, skip []string, prefer string) (LatestPolicy, error) {
+	policy := LatestPolicy{IncludePrerelease: includePrerelease}
+
+	for _, pattern := range skip {
+		re, err := regexp.Compile(pattern)
+		if err != nil {
+			return policy, fmt.Errorf("invalid skip pattern %q: %w", pattern, err)
+		}
+		policy.Skip = append(policy.Skip, re)
+	}
+
+	if prefer != "" {
+		re, err := regexp.Compile(prefer)
+		if err != nil {
+			return policy, fmt.Errorf("invalid prefer pattern %q: %w", prefer, err)
+		}
+		policy.Prefer = re
+	}
+	return policy, nil
+}
+
+// skipped reports whether the policy excludes a release
+func (p LatestPolicy) skipped(release *Release) bool {
+	if release.Draft || (release.Prerelease && !p.IncludePrerelease) {
+		return true
+	}
+	for _, re := range p.Skip {
+		if re.MatchString(release.TagName) || re.MatchString(release.Name) {
+			return true
+		}
+	}
+	return false
+}
+
+// SelectLatest returns the newest release allowed by the policy from releases listed
+// newest first, preferring releases whose tag matches the policy's Prefer pattern
+func SelectLatest(releases []Release, policy LatestPolicy) (*Release, error) {
+	var fallback *Release
+	for i := range releases {
+		release := &releases[i]
+		if policy.skipped(release) {
+			continue
+		}
+		if policy.Prefer == nil || policy.Prefer.MatchString(release.TagName) {
+			return release, nil
+		}
+		if fallback == nil {
+			fallback = release
+		}
+	}
+
+	if fallback == nil {
+		return nil, fmt.Errorf("no release satisfies the latest policy")
+	}
+	return fallback, nil
+}
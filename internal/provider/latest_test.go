@@ -0,0 +1,55 @@
+package provider
+
+import "testing"
+
+func TestSelectLatest(t *testing.T) {
+	releases := []Release{
+		{TagName: "v3.0.0-beta.1", Prerelease: true},
+		{TagName: "v2.5.0", Name: "v2.5.0 (yanked)"},
+		{TagName: "nightly-2024-05-01"},
+		{TagName: "v2.4.0"},
+		{TagName: "v2.3.0"},
+	}
+
+	tests := []struct {
+		name              string
+		includePrerelease bool
+		skip              []string
+		prefer            string
+		want              string
+		wantErr           bool
+	}{
+		{"default", false, nil, "", "v2.5.0", false},
+		{"include pre-releases", true, nil, "", "v3.0.0-beta.1", false},
+		{"skip yanked", false, []string{`(?i)yanked`}, "", "nightly-2024-05-01", false},
+		{"prefer semver tags", false, []string{`(?i)yanked`}, `^v\d+\.\d+\.\d+$`, "v2.4.0", false},
+		{"prefer falls back to newest", false, nil, `^release-`, "v2.5.0", false},
+		{"everything skipped", false, []string{`.`}, "", "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			policy, err := NewLatestPolicy(tt.includePrerelease, tt.skip, tt.prefer)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			release, err := SelectLatest(releases, policy)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("SelectLatest() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && release.TagName != tt.want {
+				t.Errorf("Expected %s, got %s", tt.want, release.TagName)
+			}
+		})
+	}
+}
+
+func TestNewLatestPolicyInvalid(t *testing.T) {
+	if _, err := NewLatestPolicy(false, []string{"("}, ""); err == nil {
+		t.Error("Expected error for invalid skip pattern")
+	}
+	if _, err := NewLatestPolicy(false, nil, "["); err == nil {
+		t.Error("Expected error for invalid prefer pattern")
+	}
+}
@@ -0,0 +1,34 @@
+package provider
+
+import (
+	"net/url"
+	"strings"
+)
+
+// MirrorProvider is implemented by providers that can fetch assets through mirrors
+type MirrorProvider interface {
+	SetMirrors(mirrors []string)
+}
+
+// githubDownloadHost is the host whose download URLs mirrors may rewrite
+const githubDownloadHost = "github.com"
+
+// MirrorURL rewrites a GitHub download URL through a mirror template. {url} is replaced
+// by the original URL and {path} by its path without the leading slash
+// (owner/repo/releases/download/tag/name). URLs of other hosts are not rewritten.
+func MirrorURL(template, original string) (string, bool) {
+	u, err := url.Parse(original)
+	if err != nil || u.Host != githubDownloadHost {
+		return "", false
+	}
+	path := strings.TrimPrefix(u.EscapedPath(), "/")
+	return strings.NewReplacer("{url}", original, "{path}", path).Replace(template), true
+}
+
+// MirrorHost returns the host of a mirror URL for messages
+func MirrorHost(mirrorURL string) string {
+	if u, err := url.Parse(mirrorURL); err == nil && u.Host != "" {
+		return u.Host
+	}
+	return mirrorURL
+}
@@ -0,0 +1,97 @@
+package provider
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/pyhub-kr/pyhub-installer/internal/github"
+)
+
+func TestMirrorURL(t *testing.T) {
+	original := "https://github.com/cli/cli/releases/download/v2.40.0/gh_2.40.0_linux_amd64.tar.gz"
+
+	tests := []struct {
+		name     string
+		template string
+		original string
+		expected string
+		ok       bool
+	}{
+		{"url prefix", "https://mirror.example.com/{url}", original, "https://mirror.example.com/" + original, true},
+		{"path", "https://mirror.example.com/gh/{path}", original, "https://mirror.example.com/gh/cli/cli/releases/download/v2.40.0/gh_2.40.0_linux_amd64.tar.gz", true},
+		{"other host", "https://mirror.example.com/{url}", "https://example.com/tool.tar.gz", "", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := MirrorURL(tt.template, tt.original)
+			if ok != tt.ok {
+				t.Fatalf("Expected ok %v, got %v", tt.ok, ok)
+			}
+			if got != tt.expected {
+				t.Errorf("Expected %s, got %s", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestDownloadFromMirrors(t *testing.T) {
+	content := []byte("mirrored binary")
+	sum := sha256.Sum256(content)
+	digest := "sha256:" + hex.EncodeToString(sum[:])
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/good/o/r/releases/download/v1/tool":
+			w.Write(content)
+		case "/tampered/o/r/releases/download/v1/tool":
+			w.Write([]byte("tampered binary"))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	original := "https://github.com/o/r/releases/download/v1/tool"
+	ctx := context.Background()
+
+	tests := []struct {
+		name    string
+		mirrors []string
+		digest  string
+		ok      bool
+	}{
+		{"verified mirror", []string{server.URL + "/good/{path}"}, digest, true},
+		{"falls through to next mirror", []string{server.URL + "/missing/{path}", server.URL + "/good/{path}"}, digest, true},
+		{"checksum mismatch", []string{server.URL + "/tampered/{path}"}, digest, false},
+		{"no checksum", []string{server.URL + "/good/{path}"}, "", false},
+		{"no mirrors", nil, digest, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := NewGitHubProvider(&github.Client{})
+			p.SetMirrors(tt.mirrors)
+			asset := &Asset{Name: "tool", BrowserDownloadURL: original, Digest: tt.digest}
+			dest := filepath.Join(t.TempDir(), "tool")
+
+			if ok := p.downloadFromMirrors(ctx, asset, original, dest); ok != tt.ok {
+				t.Fatalf("Expected %v, got %v", tt.ok, ok)
+			}
+
+			got, err := os.ReadFile(dest)
+			if tt.ok && string(got) != string(content) {
+				t.Errorf("Expected mirrored content, got %q", got)
+			}
+			if !tt.ok && err == nil {
+				t.Errorf("Expected no file after failed mirror download, got %q", got)
+			}
+		})
+	}
+}
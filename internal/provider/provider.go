@@ -0,0 +1,194 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"path"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/pyhub-kr/pyhub-installer/internal/github"
+	"github.com/pyhub-kr/pyhub-installer/internal/urltemplate"
+)
+
+// Release and Asset are shared by all providers. The GitHub release shape is used as the
+// common model so platform selection and signature lookup work for every source.
+type (
+	Release = github.Release
+	Asset   = github.Asset
+)
+
+// ErrNotFound matches the errors of every provider for missing projects and releases.
+// GitHub's error is shared; other providers mark theirs with notFound.
+var ErrNotFound = github.ErrNotFound
+
+// notFound marks an error of a provider as ErrNotFound, keeping its message
+type notFound struct{ error }
+
+func (e notFound) Is(target error) bool { return target == ErrNotFound }
+func (e notFound) Unwrap() error        { return e.error }
+
+// DefaultScheme is used for sources given without a scheme (e.g. "owner/repo")
+const DefaultScheme = "github"
+
+// Source identifies a project at a provider, e.g. "github:owner/repo"
+type Source struct {
+	Scheme string // Registry key of the provider
+	Path   string // Provider-specific location, e.g. "owner/repo"
+}
+
+// String formats the source as scheme:path
+func (s Source) String() string {
+	return s.Scheme + ":" + s.Path
+}
+
+// Name returns the tool name, the last element of the path. A ".json" extension is
+// dropped, as manifest files are named after their tool, and URL templates are cut at
+// their first placeholder.
+func (s Source) Name() string {
+	if s.Scheme == "url" {
+		return urltemplate.Name(s.Path)
+	}
+	name := path.Base(strings.Trim(strings.ReplaceAll(s.Path, `\`, "/"), "/"))
+	return strings.TrimSuffix(name, ".json")
+}
+
+// ReleaseProvider resolves releases and downloads their assets
+type ReleaseProvider interface {
+	// Resolve returns the release for version, or the newest release for "latest"
+	Resolve(ctx context.Context, src Source, version string) (*Release, error)
+	// ListVersions returns the available releases, newest first
+	ListVersions(ctx context.Context, src Source) ([]Release, error)
+	// Assets returns every downloadable asset of a release
+	Assets(ctx context.Context, src Source, release *Release) ([]Asset, error)
+	// Download fetches an asset to the destination file
+	Download(ctx context.Context, asset *Asset, dest string) error
+}
+
+// DraftProvider is implemented by providers that can resolve unpublished releases
+type DraftProvider interface {
+	SetIncludeDrafts(include bool) error
+}
+
+// RunSelector picks a CI workflow run: by ID, or the latest successful run of an
+// optional branch and workflow
+type RunSelector struct {
+	ID       int64
+	Branch   string
+	Workflow string
+}
+
+// RunProvider is implemented by providers that can install CI build artifacts
+type RunProvider interface {
+	SetWorkflowRun(run RunSelector) error
+}
+
+// AssetRequester is implemented by providers whose asset URLs need resolving or
+// request headers, so that files other than the main asset (e.g. signatures) can be fetched
+type AssetRequester interface {
+	AssetRequest(ctx context.Context, asset *Asset) (url string, headers map[string]string, err error)
+}
+
+// AssetInstaller is implemented by providers that lay out an install themselves instead
+// of having the asset extracted into the install directory. Install returns the files
+// and directories it created, so they can be removed on uninstall.
+type AssetInstaller interface {
+	Install(ctx context.Context, release *Release, asset *Asset, archivePath, binDir string) ([]string, error)
+}
+
+// SourceArchiver is implemented by providers that serve the source code of a release as
+// a tarball, so that it can be built when no asset matches the platform
+type SourceArchiver interface {
+	SourceArchive(ctx context.Context, src Source, release *Release) (*Asset, error)
+}
+
+// AssetRequest returns how to fetch an asset from a provider, defaulting to its public URL
+func AssetRequest(ctx context.Context, prov ReleaseProvider, asset *Asset) (string, map[string]string, error) {
+	if requester, ok := prov.(AssetRequester); ok {
+		return requester.AssetRequest(ctx, asset)
+	}
+	return asset.BrowserDownloadURL, nil, nil
+}
+
+// Factory creates a provider for a registered scheme
+type Factory func() ReleaseProvider
+
+var (
+	registryMu sync.RWMutex
+	registry   = make(map[string]Factory)
+)
+
+// Register makes a provider available under a scheme
+func Register(scheme string, factory Factory) {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+	registry[scheme] = factory
+}
+
+// Get returns a provider for a scheme
+func Get(scheme string) (ReleaseProvider, error) {
+	registryMu.RLock()
+	factory, ok := registry[scheme]
+	registryMu.RUnlock()
+
+	if !ok {
+		return nil, fmt.Errorf("unknown source type: %s (supported: %s)", scheme, strings.Join(Schemes(), ", "))
+	}
+	return factory(), nil
+}
+
+// Schemes returns the registered schemes in sorted order
+func Schemes() []string {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+
+	schemes := make([]string, 0, len(registry))
+	for scheme := range registry {
+		schemes = append(schemes, scheme)
+	}
+	sort.Strings(schemes)
+	return schemes
+}
+
+// ParseSource splits "scheme:path" into a source; inputs without a registered scheme use the default
+func ParseSource(input string) (Source, error) {
+	input = strings.TrimSpace(input)
+	if input == "" {
+		return Source{}, fmt.Errorf("empty source")
+	}
+
+	if scheme, rest, ok := strings.Cut(input, ":"); ok {
+		registryMu.RLock()
+		_, registered := registry[scheme]
+		registryMu.RUnlock()
+		if registered {
+			return Source{Scheme: scheme, Path: rest}, nil
+		}
+	}
+
+	// GitHub URLs are recognized before falling back to the default scheme
+	if strings.Contains(input, "github.com/") {
+		return Source{Scheme: "github", Path: input}, nil
+	}
+	// Other download URLs are installed as they are
+	if strings.HasPrefix(input, "https://") || strings.HasPrefix(input, "http://") {
+		return Source{Scheme: "url", Path: input}, nil
+	}
+
+	return Source{Scheme: DefaultScheme, Path: input}, nil
+}
+
+// Parse resolves an install target to its provider and source
+func Parse(input string) (ReleaseProvider, Source, error) {
+	src, err := ParseSource(input)
+	if err != nil {
+		return nil, Source{}, err
+	}
+
+	p, err := Get(src.Scheme)
+	if err != nil {
+		return nil, Source{}, err
+	}
+	return p, src, nil
+}
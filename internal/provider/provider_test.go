@@ -0,0 +1,419 @@
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/pyhub-kr/pyhub-installer/internal/github"
+)
+
+// fakeProvider is a minimal provider used to test the registry
+type fakeProvider struct{}
+
+func (fakeProvider) Resolve(ctx context.Context, src Source, version string) (*Release, error) {
+	return &Release{TagName: version}, nil
+}
+
+func (fakeProvider) ListVersions(ctx context.Context, src Source) ([]Release, error) {
+	return []Release{{TagName: "v1"}}, nil
+}
+
+func (fakeProvider) Assets(ctx context.Context, src Source, release *Release) ([]Asset, error) {
+	return nil, nil
+}
+
+func (fakeProvider) Download(ctx context.Context, asset *Asset, dest string) error {
+	return nil
+}
+
+func TestRegistry(t *testing.T) {
+	Register("fake", func() ReleaseProvider { return fakeProvider{} })
+
+	p, err := Get("fake")
+	if err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+	if _, ok := p.(fakeProvider); !ok {
+		t.Errorf("Expected fakeProvider, got %T", p)
+	}
+
+	if _, err := Get("missing"); err == nil {
+		t.Error("Expected error for unknown scheme")
+	}
+
+	found := false
+	for _, scheme := range Schemes() {
+		if scheme == "fake" {
+			found = true
+		}
+	}
+	if !found {
+		t.Errorf("Expected fake in schemes, got %v", Schemes())
+	}
+}
+
+func TestParseSource(t *testing.T) {
+	Register("fake", func() ReleaseProvider { return fakeProvider{} })
+
+	tests := []struct {
+		name       string
+		input      string
+		wantScheme string
+		wantPath   string
+		wantName   string
+		wantErr    bool
+	}{
+		{
+			name:       "GitHub prefix",
+			input:      "github:owner/repo",
+			wantScheme: "github",
+			wantPath:   "owner/repo",
+			wantName:   "repo",
+		},
+		{
+			name:       "Default scheme",
+			input:      "owner/repo",
+			wantScheme: "github",
+			wantPath:   "owner/repo",
+			wantName:   "repo",
+		},
+		{
+			name:       "GitHub URL",
+			input:      "https://github.com/owner/repo/",
+			wantScheme: "github",
+			wantPath:   "https://github.com/owner/repo/",
+			wantName:   "repo",
+		},
+		{
+			name:       "Registered scheme",
+			input:      "fake:some/where/tool",
+			wantScheme: "fake",
+			wantPath:   "some/where/tool",
+			wantName:   "tool",
+		},
+		{
+			name:       "Manifest file",
+			input:      `fake:C:\apps\manifests\tool.json`,
+			wantScheme: "fake",
+			wantPath:   `C:\apps\manifests\tool.json`,
+			wantName:   "tool",
+		},
+		{
+			name:       "URL template",
+			input:      "url:https://github.com/owner/repo/releases/download/v{version}/tool-{os}.zip",
+			wantScheme: "url",
+			wantPath:   "https://github.com/owner/repo/releases/download/v{version}/tool-{os}.zip",
+			wantName:   "tool",
+		},
+		{
+			name:       "Download URL",
+			input:      "https://example.com/downloads/tool-1.2.0-linux-amd64.tar.gz",
+			wantScheme: "url",
+			wantPath:   "https://example.com/downloads/tool-1.2.0-linux-amd64.tar.gz",
+			wantName:   "tool",
+		},
+		{
+			name:    "Empty input",
+			input:   "",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			src, err := ParseSource(tt.input)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseSource() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if src.Scheme != tt.wantScheme || src.Path != tt.wantPath {
+				t.Errorf("Expected %s:%s, got %s", tt.wantScheme, tt.wantPath, src)
+			}
+			if src.Name() != tt.wantName {
+				t.Errorf("Expected name %s, got %s", tt.wantName, src.Name())
+			}
+		})
+	}
+}
+
+func TestGitHubProvider(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/repos/owner/repo/releases/latest":
+			json.NewEncoder(w).Encode(Release{TagName: "v2.0.0", Assets: []Asset{{Name: "tool-linux-amd64.tar.gz"}}})
+		case "/repos/owner/repo/releases/tags/v1.0.0":
+			json.NewEncoder(w).Encode(Release{TagName: "v1.0.0"})
+		case "/repos/owner/repo/releases":
+			json.NewEncoder(w).Encode([]Release{{TagName: "v2.0.0"}, {TagName: "v1.0.0"}})
+		case "/download/tool":
+			w.Write([]byte("binary"))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	p := NewGitHubProvider(&github.Client{BaseURL: server.URL})
+	src := Source{Scheme: "github", Path: "owner/repo"}
+	ctx := context.Background()
+
+	latest, err := p.Resolve(ctx, src, "latest")
+	if err != nil || latest.TagName != "v2.0.0" {
+		t.Fatalf("Resolve(latest) = %v, %v", latest, err)
+	}
+
+	tagged, err := p.Resolve(ctx, src, "v1.0.0")
+	if err != nil || tagged.TagName != "v1.0.0" {
+		t.Fatalf("Resolve(v1.0.0) = %v, %v", tagged, err)
+	}
+
+	versions, err := p.ListVersions(ctx, src)
+	if err != nil || len(versions) != 2 || versions[0].TagName != "v2.0.0" {
+		t.Errorf("ListVersions() = %v, %v", versions, err)
+	}
+
+	assets, err := p.Assets(ctx, src, latest)
+	if err != nil || len(assets) != 1 {
+		t.Errorf("Assets() = %v, %v", assets, err)
+	}
+
+	dest := filepath.Join(t.TempDir(), "tool")
+	if err := p.Download(ctx, &Asset{Name: "tool", BrowserDownloadURL: server.URL + "/download/tool"}, dest); err != nil {
+		t.Fatalf("Download() error = %v", err)
+	}
+	if content, _ := os.ReadFile(dest); string(content) != "binary" {
+		t.Errorf("Expected downloaded content, got %q", content)
+	}
+
+	if _, err := p.Resolve(ctx, Source{Scheme: "github", Path: "invalid"}, "latest"); err == nil {
+		t.Error("Expected error for invalid repository path")
+	}
+}
+
+func TestGitHubProviderDrafts(t *testing.T) {
+	var server *httptest.Server
+	server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/repos/owner/repo/releases":
+			if r.Header.Get("Authorization") == "" {
+				json.NewEncoder(w).Encode([]Release{})
+				return
+			}
+			json.NewEncoder(w).Encode([]Release{{
+				TagName: "v3.0.0",
+				Draft:   true,
+				Assets:  []Asset{{Name: "tool", URL: server.URL + "/api/assets/1"}},
+			}})
+		case "/api/assets/1":
+			if r.Header.Get("Accept") != "application/octet-stream" || r.Header.Get("Authorization") != "Bearer secret" {
+				w.WriteHeader(http.StatusNotFound)
+				return
+			}
+			w.Write([]byte("draft binary"))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	src := Source{Scheme: "github", Path: "owner/repo"}
+	ctx := context.Background()
+
+	// Drafts need a token
+	anonymous := NewGitHubProvider(&github.Client{BaseURL: server.URL})
+	if err := anonymous.SetIncludeDrafts(true); err == nil {
+		t.Error("Expected error when including drafts without a token")
+	}
+
+	p := NewGitHubProvider(&github.Client{BaseURL: server.URL, Token: "secret"})
+	if _, err := p.Resolve(ctx, src, "v3.0.0"); err == nil {
+		t.Error("Drafts should not resolve unless included")
+	}
+
+	if err := p.SetIncludeDrafts(true); err != nil {
+		t.Fatalf("SetIncludeDrafts() error = %v", err)
+	}
+	release, err := p.Resolve(ctx, src, "v3.0.0")
+	if err != nil {
+		t.Fatalf("Resolve(draft) error = %v", err)
+	}
+	if !release.Draft {
+		t.Error("Expected a draft release")
+	}
+
+	dest := filepath.Join(t.TempDir(), "tool")
+	if err := p.Download(ctx, &release.Assets[0], dest); err != nil {
+		t.Fatalf("Download() error = %v", err)
+	}
+	if content, _ := os.ReadFile(dest); string(content) != "draft binary" {
+		t.Errorf("Expected draft asset content, got %q", content)
+	}
+}
+
+func TestGitHubProviderPrivateAssets(t *testing.T) {
+	var server *httptest.Server
+	server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/assets/1":
+			if r.Header.Get("Authorization") != "Bearer secret" {
+				w.WriteHeader(http.StatusNotFound)
+				return
+			}
+			http.Redirect(w, r, server.URL+"/storage/tool?signature=xyz", http.StatusFound)
+		case "/storage/tool":
+			w.Write([]byte("private binary"))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	asset := &Asset{
+		Name:               "tool",
+		URL:                server.URL + "/api/assets/1",
+		BrowserDownloadURL: server.URL + "/private/tool", // Not reachable for private repositories
+	}
+	ctx := context.Background()
+
+	p := NewGitHubProvider(&github.Client{BaseURL: server.URL, Token: "secret"})
+	url, headers, err := AssetRequest(ctx, p, asset)
+	if err != nil {
+		t.Fatalf("AssetRequest() error = %v", err)
+	}
+	if url != server.URL+"/storage/tool?signature=xyz" || len(headers) != 0 {
+		t.Errorf("Expected signed storage URL, got %s %v", url, headers)
+	}
+
+	dest := filepath.Join(t.TempDir(), "tool")
+	if err := p.Download(ctx, asset, dest); err != nil {
+		t.Fatalf("Download() error = %v", err)
+	}
+	if content, _ := os.ReadFile(dest); string(content) != "private binary" {
+		t.Errorf("Expected private asset content, got %q", content)
+	}
+
+	// Without a token the public URL is used
+	anonymous := NewGitHubProvider(&github.Client{BaseURL: server.URL})
+	if url, _, _ := AssetRequest(ctx, anonymous, asset); url != asset.BrowserDownloadURL {
+		t.Errorf("Expected browser download URL, got %s", url)
+	}
+	if url, _, _ := AssetRequest(ctx, fakeProvider{}, asset); url != asset.BrowserDownloadURL {
+		t.Errorf("Expected browser download URL for providers without AssetRequest, got %s", url)
+	}
+}
+
+func TestGitHubProviderTagsWithoutReleases(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/repos/owner/scripts/tags":
+			w.Write([]byte(`[{"name": "v2.0.0-rc.1"}, {"name": "v1.10.0"}, {"name": "nightly"}, {"name": "v1.9.0"}]`))
+		case "/repos/owner/scripts/releases":
+			w.Write([]byte(`[]`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	p := NewGitHubProvider(&github.Client{BaseURL: server.URL})
+	ctx := context.Background()
+	src := Source{Scheme: "github", Path: "owner/scripts"}
+
+	latest, err := p.Resolve(ctx, src, "latest")
+	if err != nil {
+		t.Fatalf("Resolve(latest) error = %v", err)
+	}
+	if latest.TagName != "v1.10.0" {
+		t.Errorf("Expected newest stable tag v1.10.0, got %s", latest.TagName)
+	}
+	if len(latest.Assets) != 1 || !github.IsSourceArchive(&latest.Assets[0]) {
+		t.Errorf("Expected the source archive as only asset, got %v", latest.Assets)
+	}
+
+	tagged, err := p.Resolve(ctx, src, "v1.9.0")
+	if err != nil || tagged.TagName != "v1.9.0" {
+		t.Errorf("Expected tag v1.9.0, got %v, %v", tagged, err)
+	}
+
+	archive, err := p.SourceArchive(ctx, src, tagged)
+	if err != nil || archive.Name != "scripts-1.9.0.tar.gz" || !github.IsSourceArchive(archive) {
+		t.Errorf("Expected the source archive of v1.9.0, got %v, %v", archive, err)
+	}
+
+	if _, err := p.Resolve(ctx, src, "v3.0.0"); !errors.Is(err, github.ErrNotFound) {
+		t.Errorf("Expected not found for missing tag, got %v", err)
+	}
+
+	versions, err := p.ListVersions(ctx, src)
+	if err != nil {
+		t.Fatalf("ListVersions() error = %v", err)
+	}
+	var order []string
+	for _, v := range versions {
+		order = append(order, v.TagName)
+	}
+	if strings.Join(order, ",") != "v2.0.0-rc.1,v1.10.0,v1.9.0,nightly" {
+		t.Errorf("Expected tags newest first, got %v", order)
+	}
+
+	// A release without uploaded assets falls back to its source archive
+	assets, err := p.Assets(ctx, src, &Release{TagName: "v1.9.0"})
+	if err != nil || len(assets) != 1 || assets[0].Name != "scripts-1.9.0.tar.gz" {
+		t.Errorf("Expected source archive asset, got %v, %v", assets, err)
+	}
+}
+
+func TestGitHubProviderWorkflowRun(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/repos/owner/repo/actions/runs":
+			w.Write([]byte(`{"workflow_runs": [{"id": 9, "head_branch": "main"}]}`))
+		case "/repos/owner/repo/actions/runs/9/artifacts":
+			w.Write([]byte(`{"artifacts": [{"name": "tool-linux-amd64", "archive_download_url": "https://api.github.com/zip/9"}]}`))
+		case "/repos/owner/repo/actions/runs/10/artifacts":
+			w.Write([]byte(`{"artifacts": [{"name": "tool-linux-amd64", "expired": true}]}`))
+		case "/repos/owner/repo/actions/runs/10":
+			w.Write([]byte(`{"id": 10}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	src := Source{Scheme: "github", Path: "owner/repo"}
+	ctx := context.Background()
+
+	anonymous := NewGitHubProvider(&github.Client{BaseURL: server.URL})
+	if err := anonymous.SetWorkflowRun(RunSelector{Branch: "main"}); err == nil {
+		t.Error("Expected error for workflow artifacts without a token")
+	}
+
+	p := NewGitHubProvider(&github.Client{BaseURL: server.URL, Token: "secret"})
+	if err := p.SetWorkflowRun(RunSelector{Branch: "main"}); err != nil {
+		t.Fatalf("SetWorkflowRun() error = %v", err)
+	}
+	release, err := p.Resolve(ctx, src, "latest")
+	if err != nil {
+		t.Fatalf("Resolve(run) error = %v", err)
+	}
+	if release.TagName != "run-9" || len(release.Assets) != 1 {
+		t.Errorf("Expected run-9 with one artifact, got %+v", release)
+	}
+
+	if err := p.SetWorkflowRun(RunSelector{ID: 10}); err != nil {
+		t.Fatalf("SetWorkflowRun() error = %v", err)
+	}
+	if _, err := p.Resolve(ctx, src, "latest"); err == nil {
+		t.Error("Expected error for a run whose artifacts expired")
+	}
+}
@@ -0,0 +1,225 @@
+package provider
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/pyhub-kr/pyhub-installer/internal/download"
+	"github.com/pyhub-kr/pyhub-installer/internal/extract"
+	"github.com/pyhub-kr/pyhub-installer/internal/scoop"
+	"github.com/pyhub-kr/pyhub-installer/internal/shim"
+	"github.com/pyhub-kr/pyhub-installer/internal/store"
+	"github.com/pyhub-kr/pyhub-installer/pkg/config"
+)
+
+func init() {
+	Register("scoop", func() ReleaseProvider {
+		return &ScoopProvider{}
+	})
+}
+
+// unsupportedScoopFormats are downloads that need tools Scoop bundles (7-Zip, msiexec)
+var unsupportedScoopFormats = []string{".7z", ".msi", ".rar", ".xz", ".bz2", ".zst"}
+
+// ScoopProvider installs apps described by Scoop manifests. Apps are unpacked into the
+// versioned store and their executables are exposed through shims.
+type ScoopProvider struct {
+	StoreDir string // Root of app directories (default: config.StoreDir())
+
+	name     string
+	packages map[string]scoop.Package // By asset name
+}
+
+// Resolve loads the manifest. A manifest describes one version only.
+func (p *ScoopProvider) Resolve(ctx context.Context, src Source, version string) (*Release, error) {
+	release, err := p.release(src)
+	if err != nil {
+		return nil, err
+	}
+	if version != "" && version != "latest" && strings.TrimPrefix(version, "v") != release.TagName {
+		return nil, notFound{fmt.Errorf("manifest %s describes version %s only", src.Path, release.TagName)}
+	}
+	return release, nil
+}
+
+// ListVersions returns the version of the manifest
+func (p *ScoopProvider) ListVersions(ctx context.Context, src Source) ([]Release, error) {
+	release, err := p.release(src)
+	if err != nil {
+		return nil, err
+	}
+	return []Release{*release}, nil
+}
+
+// Assets returns the downloads of the manifest, one per architecture
+func (p *ScoopProvider) Assets(ctx context.Context, src Source, release *Release) ([]Asset, error) {
+	return release.Assets, nil
+}
+
+// Download fetches an asset with the parallel chunk downloader
+func (p *ScoopProvider) Download(ctx context.Context, asset *Asset, dest string) error {
+	return download.NewChunkDownloader(asset.BrowserDownloadURL, dest).Download(ctx)
+}
+
+// Install unpacks the download into <store>/<app>/<version>, creates shims for the
+// manifest's bin entries in binDir and Start menu shortcuts for its shortcuts
+func (p *ScoopProvider) Install(ctx context.Context, release *Release, asset *Asset, archivePath, binDir string) ([]string, error) {
+	pkg, ok := p.packages[asset.Name]
+	if !ok {
+		return nil, fmt.Errorf("asset %s is not from a Scoop manifest", asset.Name)
+	}
+
+	storeDir := p.StoreDir
+	if storeDir == "" {
+		var err error
+		if storeDir, err = config.StoreDir(); err != nil {
+			return nil, err
+		}
+	}
+	appDir := filepath.Join(storeDir, p.name, release.TagName)
+	if err := unpackScoop(ctx, archivePath, asset.Name, pkg, appDir); err != nil {
+		return nil, err
+	}
+	if err := store.SetCurrent(filepath.Dir(appDir), release.TagName); err != nil {
+		return nil, err
+	}
+	fmt.Printf("✓ Installed to: %s\n", appDir)
+	files := []string{appDir}
+
+	for _, bin := range pkg.Bin {
+		target := filepath.Join(appDir, manifestPath(bin.Path))
+		if _, err := os.Stat(target); err != nil {
+			return files, fmt.Errorf("bin %s not found in %s", bin.Path, asset.Name)
+		}
+		shimPath, err := shim.Create(binDir, bin.Name(), target, bin.Args)
+		if err != nil {
+			return files, err
+		}
+		fmt.Printf("✓ Created shim: %s\n", shimPath)
+		files = append(files, shimPath)
+	}
+
+	for _, shortcut := range pkg.Shortcuts {
+		link, err := shim.CreateShortcut(shortcut.Name, filepath.Join(appDir, manifestPath(shortcut.Target)))
+		if err != nil {
+			fmt.Printf("Warning: %v\n", err)
+			continue
+		}
+		fmt.Printf("✓ Created shortcut: %s\n", link)
+		files = append(files, link)
+	}
+	return files, nil
+}
+
+// release loads the manifest of a source as a release with one asset per architecture
+func (p *ScoopProvider) release(src Source) (*Release, error) {
+	location, err := scoop.ManifestURL(src.Path)
+	if err != nil {
+		return nil, err
+	}
+	manifest, err := scoop.Load(location)
+	if errors.Is(err, scoop.ErrNotFound) {
+		return nil, notFound{err}
+	}
+	if err != nil {
+		return nil, err
+	}
+	packages, err := manifest.Packages()
+	if err != nil {
+		return nil, fmt.Errorf("invalid manifest %s: %w", location, err)
+	}
+
+	p.name = src.Name()
+	p.packages = make(map[string]scoop.Package)
+	release := &Release{TagName: manifest.Version, Name: p.name + " " + manifest.Version}
+	for _, pkg := range packages {
+		ext := scoopExt(pkg.Filename)
+		if ext == "" {
+			continue
+		}
+		platform := "windows"
+		if pkg.Arch != "" {
+			platform += "-" + pkg.Arch
+		}
+		asset := Asset{
+			Name:               fmt.Sprintf("%s-%s-%s%s", p.name, manifest.Version, platform, ext),
+			BrowserDownloadURL: pkg.URL,
+		}
+		if pkg.SHA256 != "" {
+			asset.Digest = "sha256:" + pkg.SHA256
+		}
+		p.packages[asset.Name] = pkg
+		release.Assets = append(release.Assets, asset)
+	}
+
+	if len(release.Assets) == 0 {
+		return nil, fmt.Errorf("manifest %s only has downloads in unsupported formats (%s)", location, strings.Join(unsupportedScoopFormats, ", "))
+	}
+	return release, nil
+}
+
+// scoopExt returns the extension to name a download by, or "" for unsupported formats
+func scoopExt(filename string) string {
+	name := strings.ToLower(filename)
+	if strings.HasSuffix(name, ".tar.gz") || strings.HasSuffix(name, ".tgz") {
+		return ".tar.gz"
+	}
+	ext := path.Ext(name)
+	for _, unsupported := range unsupportedScoopFormats {
+		if ext == unsupported {
+			return ""
+		}
+	}
+	return ext
+}
+
+// unpackScoop replaces appDir with the content of the download: archives are extracted
+// (from extract_dir if set), other files such as single executables are copied
+func unpackScoop(ctx context.Context, archivePath, assetName string, pkg scoop.Package, appDir string) error {
+	if err := os.RemoveAll(appDir); err != nil {
+		return fmt.Errorf("failed to remove previous install: %w", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(appDir), 0755); err != nil {
+		return fmt.Errorf("failed to create app directory: %w", err)
+	}
+
+	switch scoopExt(assetName) {
+	case ".zip", ".tar.gz", ".tar":
+	default:
+		if err := os.MkdirAll(appDir, 0755); err != nil {
+			return fmt.Errorf("failed to create app directory: %w", err)
+		}
+		return copyExecutable(archivePath, filepath.Join(appDir, pkg.Filename))
+	}
+
+	tmpDir := appDir + ".tmp"
+	os.RemoveAll(tmpDir)
+	defer os.RemoveAll(tmpDir)
+
+	if err := extract.NewExtractor(archivePath, tmpDir).ExtractContext(ctx); err != nil {
+		return fmt.Errorf("failed to extract %s: %w", assetName, err)
+	}
+	if err := os.Rename(filepath.Join(tmpDir, manifestPath(pkg.ExtractDir)), appDir); err != nil {
+		return fmt.Errorf("failed to move extracted files: %w", err)
+	}
+	return nil
+}
+
+// manifestPath converts a manifest path, which uses backslashes, to a local path
+func manifestPath(p string) string {
+	return filepath.FromSlash(strings.ReplaceAll(p, `\`, "/"))
+}
+
+// copyExecutable copies a downloaded file and makes it executable
+func copyExecutable(src, dst string) error {
+	data, err := os.ReadFile(src)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(dst, data, 0755)
+}
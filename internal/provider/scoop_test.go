@@ -0,0 +1,113 @@
+package provider
+
+import (
+	"archive/zip"
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/pyhub-kr/pyhub-installer/internal/store"
+)
+
+func TestScoopProviderInstall(t *testing.T) {
+	dir := t.TempDir()
+
+	manifest := filepath.Join(dir, "tool.json")
+	err := os.WriteFile(manifest, []byte(`{
+		"version": "1.2.0",
+		"architecture": {
+			"64bit": {"url": "https://example.com/tool-x64.zip", "hash": "`+zeroHash+`", "extract_dir": "tool-1.2.0"},
+			"arm64": {"url": "https://example.com/tool-arm64.7z"}
+		},
+		"bin": [["bin\\tool.exe", "tl", "--quiet"]]
+	}`), 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	p := &ScoopProvider{StoreDir: filepath.Join(dir, "store")}
+	ctx := context.Background()
+	release, err := p.Resolve(ctx, Source{Scheme: "scoop", Path: manifest}, "latest")
+	if err != nil {
+		t.Fatalf("Resolve() error = %v", err)
+	}
+	if release.TagName != "1.2.0" || len(release.Assets) != 1 {
+		t.Fatalf("Expected 1.2.0 with only the zip download, got %+v", release)
+	}
+	asset := &release.Assets[0]
+	if asset.Name != "tool-1.2.0-windows-amd64.zip" || asset.Digest != "sha256:"+zeroHash {
+		t.Errorf("Unexpected asset %s (%s)", asset.Name, asset.Digest)
+	}
+
+	archive := filepath.Join(dir, asset.Name)
+	writeZip(t, archive, map[string]string{"tool-1.2.0/bin/tool.exe": "binary"})
+
+	binDir := filepath.Join(dir, "bin")
+	files, err := p.Install(ctx, release, asset, archive, binDir)
+	if err != nil {
+		t.Fatalf("Install() error = %v", err)
+	}
+
+	appDir := filepath.Join(dir, "store", "tool", "1.2.0")
+	if content, err := os.ReadFile(filepath.Join(appDir, "bin", "tool.exe")); err != nil || string(content) != "binary" {
+		t.Errorf("Expected app extracted from extract_dir, got %q, %v", content, err)
+	}
+	if current := store.Current(filepath.Dir(appDir)); current != "1.2.0" {
+		t.Errorf("Expected the installed version to become current, got %q", current)
+	}
+
+	shimName := "tl"
+	if runtime.GOOS == "windows" {
+		shimName = "tl.cmd"
+	}
+	expected := []string{appDir, filepath.Join(binDir, shimName)}
+	if len(files) != len(expected) || files[0] != expected[0] || files[1] != expected[1] {
+		t.Errorf("Expected created files %v, got %v", expected, files)
+	}
+
+	if _, err := p.Resolve(ctx, Source{Scheme: "scoop", Path: manifest}, "1.1.0"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Expected ErrNotFound for a version the manifest does not describe, got %v", err)
+	}
+}
+
+func TestScoopProviderNotFound(t *testing.T) {
+	server := httptest.NewServer(http.NotFoundHandler())
+	defer server.Close()
+
+	ctx := context.Background()
+	for _, path := range []string{server.URL + "/bucket/missing.json", filepath.Join(t.TempDir(), "missing.json")} {
+		if _, err := (&ScoopProvider{}).Resolve(ctx, Source{Scheme: "scoop", Path: path}, "latest"); !errors.Is(err, ErrNotFound) {
+			t.Errorf("Expected ErrNotFound for %s, got %v", path, err)
+		}
+	}
+}
+
+const zeroHash = "0000000000000000000000000000000000000000000000000000000000000000"
+
+// writeZip creates a zip archive with the given files
+func writeZip(t *testing.T, path string, files map[string]string) {
+	t.Helper()
+
+	f, err := os.Create(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+
+	w := zip.NewWriter(f)
+	for name, content := range files {
+		fw, err := w.Create(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		fw.Write([]byte(content))
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+}
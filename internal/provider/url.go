@@ -0,0 +1,115 @@
+package provider
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"path"
+	"sort"
+	"strings"
+
+	"github.com/pyhub-kr/pyhub-installer/internal/credential"
+	"github.com/pyhub-kr/pyhub-installer/internal/download"
+	"github.com/pyhub-kr/pyhub-installer/internal/github"
+	"github.com/pyhub-kr/pyhub-installer/internal/platform"
+	"github.com/pyhub-kr/pyhub-installer/internal/urltemplate"
+)
+
+func init() {
+	Register("url", func() ReleaseProvider {
+		return &URLProvider{}
+	})
+}
+
+// URLProvider installs files from vendors without a release API, described by a URL
+// template such as "url:https://example.com/tool-{version}-{os}-{arch}.tar.gz"
+type URLProvider struct{}
+
+// Resolve returns the release for version. The vendor has no release listing, so a
+// template using {version} needs an explicit version.
+func (p *URLProvider) Resolve(ctx context.Context, src Source, version string) (*Release, error) {
+	if err := urltemplate.Validate(src.Path); err != nil {
+		return nil, err
+	}
+	if version == "" || version == "latest" {
+		if urltemplate.Uses(src.Path, urltemplate.Version) {
+			return nil, fmt.Errorf("%s needs an explicit version, e.g. %s@1.0.0", src, src)
+		}
+		// A plain URL usually names its version, e.g. tool-1.2.0.tar.gz
+		version = urltemplate.VersionOf(src.Path)
+		if version == "" {
+			version = "latest"
+		}
+	}
+	return &Release{TagName: version, Name: src.Name() + " " + version}, nil
+}
+
+// ListVersions is not supported, as the vendor publishes no list of versions
+func (p *URLProvider) ListVersions(ctx context.Context, src Source) ([]Release, error) {
+	return nil, fmt.Errorf("versions of %s cannot be listed; install an exact version instead", src)
+}
+
+// Assets expands the template for every known platform. The asset names carry the
+// platform, so that the usual platform detection picks the right download.
+func (p *URLProvider) Assets(ctx context.Context, src Source, release *Release) ([]Asset, error) {
+	platforms := make([]string, 0, len(github.DefaultScoring().Platforms))
+	for target := range github.DefaultScoring().Platforms {
+		platforms = append(platforms, target)
+	}
+	sort.Strings(platforms)
+
+	var version string
+	if release.TagName != "latest" {
+		version = release.TagName
+	}
+
+	assets := make([]Asset, 0, len(platforms))
+	for _, target := range platforms {
+		goos, goarch := platform.Split(target)
+		assetURL, err := urltemplate.Expand(src.Path, urltemplate.Values{OS: goos, Arch: goarch, Version: version})
+		if err != nil {
+			return nil, err
+		}
+		assets = append(assets, Asset{
+			Name:               fmt.Sprintf("%s-%s-%s%s", src.Name(), release.TagName, target, fileExt(assetURL)),
+			BrowserDownloadURL: assetURL,
+		})
+	}
+	return assets, nil
+}
+
+// Download fetches an asset with the parallel chunk downloader, authenticating with the
+// credential helper configured for the vendor's host, if any. The vendor has no release
+// listing, so a missing file means the version or platform does not exist.
+func (p *URLProvider) Download(ctx context.Context, asset *Asset, dest string) error {
+	cd := download.NewChunkDownloader(asset.BrowserDownloadURL, dest)
+	if u, err := url.Parse(asset.BrowserDownloadURL); err == nil {
+		cred, err := credential.Default.Get(ctx, u.Hostname())
+		if err != nil {
+			return err
+		}
+		if cred != nil {
+			cd.Headers = map[string]string{"Authorization": cred.Authorization()}
+		}
+	}
+	if err := cd.Download(ctx); err != nil {
+		if errors.Is(err, download.ErrNotFound) {
+			return notFound{err}
+		}
+		return err
+	}
+	return nil
+}
+
+// fileExt returns the extension of the file a URL points to, keeping compound
+// archive extensions such as .tar.gz
+func fileExt(rawURL string) string {
+	name := path.Base(strings.SplitN(rawURL, "?", 2)[0])
+	for _, ext := range []string{".tar.gz", ".tar.xz", ".tar.bz2"} {
+		if strings.HasSuffix(strings.ToLower(name), ext) {
+			return name[len(name)-len(ext):]
+		}
+	}
+	return path.Ext(name)
+}
@@ -0,0 +1,114 @@
+package provider
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+
+	"github.com/pyhub-kr/pyhub-installer/internal/github"
+)
+
+func TestURLProvider(t *testing.T) {
+	p := &URLProvider{}
+	ctx := context.Background()
+	src := Source{Scheme: "url", Path: "https://example.com/dl/{version}/tool-{version}-{os}-{arch}.tar.gz"}
+
+	if _, err := p.Resolve(ctx, src, "latest"); err == nil {
+		t.Error("Expected an error resolving latest for a template using {version}")
+	}
+
+	release, err := p.Resolve(ctx, src, "1.4.0")
+	if err != nil {
+		t.Fatalf("Resolve() error = %v", err)
+	}
+	if release.TagName != "1.4.0" {
+		t.Errorf("Expected tag 1.4.0, got %s", release.TagName)
+	}
+
+	release.Assets, err = p.Assets(ctx, src, release)
+	if err != nil {
+		t.Fatalf("Assets() error = %v", err)
+	}
+
+	asset, err := release.FindAssetWithScoring("darwin-arm64", github.DefaultScoring())
+	if err != nil {
+		t.Fatalf("FindAssetWithScoring() error = %v", err)
+	}
+	if asset.Name != "tool-1.4.0-darwin-arm64.tar.gz" {
+		t.Errorf("Unexpected asset name %s", asset.Name)
+	}
+	if want := "https://example.com/dl/1.4.0/tool-1.4.0-darwin-arm64.tar.gz"; asset.BrowserDownloadURL != want {
+		t.Errorf("Expected URL %s, got %s", want, asset.BrowserDownloadURL)
+	}
+}
+
+func TestURLProviderNotFound(t *testing.T) {
+	server := httptest.NewServer(http.NotFoundHandler())
+	defer server.Close()
+
+	asset := &Asset{Name: "tool-9.9.9-linux-amd64.tar.gz", BrowserDownloadURL: server.URL + "/tool-9.9.9-linux-amd64.tar.gz"}
+	err := (&URLProvider{}).Download(context.Background(), asset, filepath.Join(t.TempDir(), asset.Name))
+	if !errors.Is(err, ErrNotFound) {
+		t.Errorf("Expected ErrNotFound for a missing file, got %v", err)
+	}
+}
+
+func TestURLProviderWithoutVersion(t *testing.T) {
+	p := &URLProvider{}
+	ctx := context.Background()
+	src := Source{Scheme: "url", Path: "https://example.com/tool-{os}-{arch}.exe"}
+
+	release, err := p.Resolve(ctx, src, "")
+	if err != nil {
+		t.Fatalf("Resolve() error = %v", err)
+	}
+	release.Assets, err = p.Assets(ctx, src, release)
+	if err != nil {
+		t.Fatalf("Assets() error = %v", err)
+	}
+
+	asset, err := release.FindAssetWithScoring("windows-amd64", github.DefaultScoring())
+	if err != nil {
+		t.Fatalf("FindAssetWithScoring() error = %v", err)
+	}
+	if asset.BrowserDownloadURL != "https://example.com/tool-windows-amd64.exe" {
+		t.Errorf("Unexpected URL %s", asset.BrowserDownloadURL)
+	}
+
+	if _, err := p.Resolve(ctx, Source{Scheme: "url", Path: "https://example.com/tool-{platform}"}, "1.0"); err == nil {
+		t.Error("Expected an error for an unknown placeholder")
+	}
+}
+
+func TestURLProviderPlainURL(t *testing.T) {
+	p := &URLProvider{}
+	ctx := context.Background()
+	src := Source{Scheme: "url", Path: "https://example.com/downloads/tool-1.2.0-linux-amd64.tar.gz"}
+
+	release, err := p.Resolve(ctx, src, "")
+	if err != nil {
+		t.Fatalf("Resolve() error = %v", err)
+	}
+	if release.TagName != "1.2.0" {
+		t.Errorf("Expected the version of the file name, got %s", release.TagName)
+	}
+	release.Assets, err = p.Assets(ctx, src, release)
+	if err != nil {
+		t.Fatalf("Assets() error = %v", err)
+	}
+	asset, err := release.FindAssetWithScoring("linux-amd64", github.DefaultScoring())
+	if err != nil {
+		t.Fatalf("FindAssetWithScoring() error = %v", err)
+	}
+	if asset.BrowserDownloadURL != src.Path {
+		t.Errorf("Unexpected URL %s", asset.BrowserDownloadURL)
+	}
+
+	release, err = p.Resolve(ctx, src, "1.2.0-custom")
+	if err != nil || release.TagName != "1.2.0-custom" {
+		t.Errorf("Expected an explicit version to be kept, got %v, %v", release, err)
+	}
+}
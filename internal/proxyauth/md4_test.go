@@ -0,0 +1,23 @@
+package proxyauth
+
+import (
+	"encoding/hex"
+	"strings"
+	"testing"
+)
+
+func TestMD4(t *testing.T) {
+	// Test suite of RFC 1320
+	tests := map[string]string{
+		"":                              "31d6cfe0d16ae931b73c59d7e0c089c0",
+		"a":                             "bde52cb31de33e46245e05fbdbd6fb24",
+		"abc":                           "a448017aaf21d8525fc10ae87aa6729d",
+		"message digest":                "d9130a8164549fe818874806e1c7014b",
+		strings.Repeat("1234567890", 8): "e33b4ddc9c38f2199c3e7b164fcc0536",
+	}
+	for input, want := range tests {
+		if got := hex.EncodeToString(md4([]byte(input))); got != want {
+			t.Errorf("md4(%q) = %s, want %s", input, got, want)
+		}
+	}
+}
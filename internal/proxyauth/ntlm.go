@@ -0,0 +1,220 @@
+package proxyauth
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/md5"
+	"crypto/rand"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+	"unicode/utf16"
+)
+
+// ntlmSignature starts every NTLM message
+var ntlmSignature = []byte("NTLMSSP\x00")
+
+// NTLM negotiate flags (MS-NLMP 2.2.2.5)
+const (
+	ntlmNegotiateUnicode          = 0x00000001
+	ntlmRequestTarget             = 0x00000004
+	ntlmNegotiateNTLM             = 0x00000200
+	ntlmNegotiateAlwaysSign       = 0x00008000
+	ntlmNegotiateExtendedSecurity = 0x00080000
+	ntlmNegotiateTargetInfo       = 0x00800000
+	ntlmNegotiate128              = 0x20000000
+	ntlmNegotiate56               = 0x80000000
+
+	ntlmFlags = ntlmNegotiateUnicode | ntlmRequestTarget | ntlmNegotiateNTLM | ntlmNegotiateAlwaysSign |
+		ntlmNegotiateExtendedSecurity | ntlmNegotiateTargetInfo | ntlmNegotiate128 | ntlmNegotiate56
+)
+
+// avTimestamp is the MsvAvTimestamp attribute of the target info
+const avTimestamp = 7
+
+// ntlmAuth authenticates with NTLMv2 and explicit credentials
+type ntlmAuth struct {
+	scheme   string // "NTLM", or "Negotiate" when sent as a raw NTLM token
+	user     string
+	domain   string
+	password string
+
+	// now and clientChallenge are replaced in tests
+	now             func() time.Time
+	clientChallenge func() ([]byte, error)
+}
+
+// newNTLM creates an NTLM authenticator for a user given as DOMAIN\user, user@domain
+// or user
+func newNTLM(scheme, user, password string) *ntlmAuth {
+	domain := ""
+	if d, u, ok := strings.Cut(user, `\`); ok {
+		domain, user = d, u
+	} else if u, d, ok := strings.Cut(user, "@"); ok {
+		domain, user = d, u
+	}
+	return &ntlmAuth{
+		scheme:   scheme,
+		user:     user,
+		domain:   domain,
+		password: password,
+		now:      time.Now,
+		clientChallenge: func() ([]byte, error) {
+			challenge := make([]byte, 8)
+			_, err := rand.Read(challenge)
+			return challenge, err
+		},
+	}
+}
+
+// Scheme returns the Proxy-Authorization scheme
+func (a *ntlmAuth) Scheme() string {
+	return a.scheme
+}
+
+// Next returns the negotiate message without a challenge, and the authenticate message
+// answering the proxy's challenge message
+func (a *ntlmAuth) Next(challenge []byte) ([]byte, error) {
+	if challenge == nil {
+		return negotiateMessage(), nil
+	}
+	return a.authenticateMessage(challenge)
+}
+
+// negotiateMessage returns the NTLM NEGOTIATE_MESSAGE, without domain or workstation
+func negotiateMessage() []byte {
+	msg := append([]byte{}, ntlmSignature...)
+	msg = binary.LittleEndian.AppendUint32(msg, 1)
+	msg = binary.LittleEndian.AppendUint32(msg, ntlmFlags)
+	return append(msg, make([]byte, 16)...) // Empty domain and workstation fields
+}
+
+// challengeMessage holds the parts of an NTLM CHALLENGE_MESSAGE used to answer it
+type challengeMessage struct {
+	flags      uint32
+	challenge  []byte
+	targetInfo []byte
+}
+
+// parseChallenge parses an NTLM CHALLENGE_MESSAGE
+func parseChallenge(msg []byte) (*challengeMessage, error) {
+	if len(msg) < 48 || !bytes.Equal(msg[:8], ntlmSignature) || binary.LittleEndian.Uint32(msg[8:]) != 2 {
+		return nil, errors.New("invalid NTLM challenge message")
+	}
+	c := &challengeMessage{
+		flags:     binary.LittleEndian.Uint32(msg[20:]),
+		challenge: msg[24:32],
+	}
+
+	length := int(binary.LittleEndian.Uint16(msg[40:]))
+	offset := int(binary.LittleEndian.Uint32(msg[44:]))
+	if offset+length > len(msg) {
+		return nil, errors.New("invalid NTLM challenge message: target info out of range")
+	}
+	c.targetInfo = msg[offset : offset+length]
+	return c, nil
+}
+
+// timestamp returns the MsvAvTimestamp of target info, or nil
+func timestamp(targetInfo []byte) []byte {
+	for len(targetInfo) >= 4 {
+		id := binary.LittleEndian.Uint16(targetInfo)
+		length := int(binary.LittleEndian.Uint16(targetInfo[2:]))
+		if id == 0 || 4+length > len(targetInfo) {
+			return nil
+		}
+		if id == avTimestamp && length == 8 {
+			return targetInfo[4:12]
+		}
+		targetInfo = targetInfo[4+length:]
+	}
+	return nil
+}
+
+// authenticateMessage answers a challenge with an NTLM AUTHENTICATE_MESSAGE holding
+// NTLMv2 responses
+func (a *ntlmAuth) authenticateMessage(msg []byte) ([]byte, error) {
+	challenge, err := parseChallenge(msg)
+	if err != nil {
+		return nil, err
+	}
+	clientChallenge, err := a.clientChallenge()
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate client challenge: %w", err)
+	}
+
+	ts := timestamp(challenge.targetInfo)
+	lmResponse := make([]byte, 24) // Zero when the server sends a timestamp
+	if ts == nil {
+		ts = fileTime(a.now())
+		lmResponse = lmV2Response(a.user, a.domain, a.password, challenge.challenge, clientChallenge)
+	}
+	ntResponse := ntV2Response(a.user, a.domain, a.password, challenge.challenge, clientChallenge, ts, challenge.targetInfo)
+
+	flags := ntlmFlags & challenge.flags
+	fields := [][]byte{lmResponse, ntResponse, utf16le(a.domain), utf16le(a.user), nil, nil}
+
+	header := append([]byte{}, ntlmSignature...)
+	header = binary.LittleEndian.AppendUint32(header, 3)
+	offset := uint32(8 + 4 + 8*len(fields) + 4)
+	var payload []byte
+	for _, field := range fields {
+		header = binary.LittleEndian.AppendUint16(header, uint16(len(field)))
+		header = binary.LittleEndian.AppendUint16(header, uint16(len(field)))
+		header = binary.LittleEndian.AppendUint32(header, offset+uint32(len(payload)))
+		payload = append(payload, field...)
+	}
+	header = binary.LittleEndian.AppendUint32(header, flags)
+	return append(header, payload...), nil
+}
+
+// ntOWFv2 returns the NTLMv2 hash of a user's password
+func ntOWFv2(user, domain, password string) []byte {
+	return hmacMD5(md4(utf16le(password)), utf16le(strings.ToUpper(user)+domain))
+}
+
+// lmV2Response returns the LMv2 response to a server challenge
+func lmV2Response(user, domain, password string, serverChallenge, clientChallenge []byte) []byte {
+	proof := hmacMD5(ntOWFv2(user, domain, password), serverChallenge, clientChallenge)
+	return append(proof, clientChallenge...)
+}
+
+// ntV2Response returns the NTLMv2 response to a server challenge
+func ntV2Response(user, domain, password string, serverChallenge, clientChallenge, time, targetInfo []byte) []byte {
+	var temp []byte
+	temp = append(temp, 1, 1, 0, 0, 0, 0, 0, 0)
+	temp = append(temp, time...)
+	temp = append(temp, clientChallenge...)
+	temp = append(temp, 0, 0, 0, 0)
+	temp = append(temp, targetInfo...)
+	temp = append(temp, 0, 0, 0, 0)
+
+	proof := hmacMD5(ntOWFv2(user, domain, password), serverChallenge, temp)
+	return append(proof, temp...)
+}
+
+// hmacMD5 returns the HMAC-MD5 of the concatenated data
+func hmacMD5(key []byte, data ...[]byte) []byte {
+	mac := hmac.New(md5.New, key)
+	for _, d := range data {
+		mac.Write(d)
+	}
+	return mac.Sum(nil)
+}
+
+// utf16le encodes s as UTF-16 little endian, the NTLM string encoding
+func utf16le(s string) []byte {
+	var b []byte
+	for _, r := range utf16.Encode([]rune(s)) {
+		b = binary.LittleEndian.AppendUint16(b, r)
+	}
+	return b
+}
+
+// fileTime encodes t as a Windows FILETIME: 100ns intervals since 1601
+func fileTime(t time.Time) []byte {
+	const epochDelta = 11644473600 // Seconds from 1601 to 1970
+	return binary.LittleEndian.AppendUint64(nil, uint64(t.Unix()+epochDelta)*10000000+uint64(t.Nanosecond()/100))
+}
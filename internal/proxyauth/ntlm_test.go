@@ -0,0 +1,107 @@
+package proxyauth
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/hex"
+	"testing"
+	"time"
+)
+
+// Values of the NTLMv2 example of MS-NLMP 4.2.4
+var (
+	testServerChallenge = []byte{0x01, 0x23, 0x45, 0x67, 0x89, 0xab, 0xcd, 0xef}
+	testClientChallenge = bytes.Repeat([]byte{0xaa}, 8)
+	testTargetInfo      = append(append(append(
+		[]byte{0x02, 0x00, 0x0c, 0x00}, utf16le("Domain")...),
+		append([]byte{0x01, 0x00, 0x0c, 0x00}, utf16le("Server")...)...),
+		0x00, 0x00, 0x00, 0x00)
+)
+
+func TestNTOWFv2(t *testing.T) {
+	if got := hex.EncodeToString(ntOWFv2("User", "Domain", "Password")); got != "0c868a403bfd7a93a3001ef22ef02e3f" {
+		t.Errorf("ntOWFv2() = %s", got)
+	}
+}
+
+func TestLMv2Response(t *testing.T) {
+	got := hex.EncodeToString(lmV2Response("User", "Domain", "Password", testServerChallenge, testClientChallenge))
+	if got != "86c35097ac9cec102554764a57cccc19aaaaaaaaaaaaaaaa" {
+		t.Errorf("lmV2Response() = %s", got)
+	}
+}
+
+func TestNTv2Response(t *testing.T) {
+	response := ntV2Response("User", "Domain", "Password", testServerChallenge, testClientChallenge, make([]byte, 8), testTargetInfo)
+	if got := hex.EncodeToString(response[:16]); got != "68cd0ab851e51c96aabc927bebef6a1c" {
+		t.Errorf("NTProofStr = %s", got)
+	}
+}
+
+// challenge builds a CHALLENGE_MESSAGE with target info
+func challenge(targetInfo []byte) []byte {
+	msg := append([]byte{}, ntlmSignature...)
+	msg = binary.LittleEndian.AppendUint32(msg, 2)
+	msg = append(msg, 0, 0, 0, 0, 48, 0, 0, 0) // Empty target name
+	msg = binary.LittleEndian.AppendUint32(msg, ntlmFlags)
+	msg = append(msg, testServerChallenge...)
+	msg = append(msg, make([]byte, 8)...)
+	msg = binary.LittleEndian.AppendUint16(msg, uint16(len(targetInfo)))
+	msg = binary.LittleEndian.AppendUint16(msg, uint16(len(targetInfo)))
+	msg = binary.LittleEndian.AppendUint32(msg, 48)
+	return append(msg, targetInfo...)
+}
+
+func TestNTLMHandshake(t *testing.T) {
+	auth := newNTLM("NTLM", `Domain\User`, "Password")
+	auth.now = func() time.Time { return time.Date(1601, 1, 1, 0, 0, 0, 0, time.UTC) }
+	auth.clientChallenge = func() ([]byte, error) { return testClientChallenge, nil }
+
+	negotiate, err := auth.Next(nil)
+	if err != nil || !bytes.HasPrefix(negotiate, ntlmSignature) || negotiate[8] != 1 {
+		t.Fatalf("Unexpected negotiate message %x (%v)", negotiate, err)
+	}
+
+	msg, err := auth.Next(challenge(testTargetInfo))
+	if err != nil {
+		t.Fatalf("Next(challenge) error = %v", err)
+	}
+	if msg[8] != 3 {
+		t.Fatalf("Expected an authenticate message, got type %d", msg[8])
+	}
+
+	field := func(index int) []byte {
+		length := binary.LittleEndian.Uint16(msg[12+8*index:])
+		offset := binary.LittleEndian.Uint32(msg[16+8*index:])
+		return msg[offset : offset+uint32(length)]
+	}
+	if got := hex.EncodeToString(field(0)); got != "86c35097ac9cec102554764a57cccc19aaaaaaaaaaaaaaaa" {
+		t.Errorf("LM response = %s", got)
+	}
+	if got := hex.EncodeToString(field(1)[:16]); got != "68cd0ab851e51c96aabc927bebef6a1c" {
+		t.Errorf("NT proof = %s", got)
+	}
+	if !bytes.Equal(field(2), utf16le("Domain")) || !bytes.Equal(field(3), utf16le("User")) {
+		t.Errorf("Unexpected domain %x or user %x", field(2), field(3))
+	}
+}
+
+func TestNewNTLMUserFormats(t *testing.T) {
+	tests := map[string][2]string{
+		`CORP\alice`:     {"CORP", "alice"},
+		"alice@corp.com": {"corp.com", "alice"},
+		"alice":          {"", "alice"},
+	}
+	for input, want := range tests {
+		auth := newNTLM("NTLM", input, "secret")
+		if auth.domain != want[0] || auth.user != want[1] {
+			t.Errorf("newNTLM(%q) = domain %q user %q, want %v", input, auth.domain, auth.user, want)
+		}
+	}
+}
+
+func TestParseChallengeInvalid(t *testing.T) {
+	if _, err := parseChallenge([]byte("NTLMSSP\x00short")); err == nil {
+		t.Error("Expected error for a truncated challenge")
+	}
+}
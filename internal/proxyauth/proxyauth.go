@@ -0,0 +1,216 @@
+package proxyauth
+
+import (
+	"bufio"
+	"context"
+	"encoding/base64"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+	"time"
+)
+
+// Schemes are the supported proxy authentication schemes
+var Schemes = []string{"ntlm", "negotiate"}
+
+// Environment variables holding explicit proxy credentials
+const (
+	UserEnv     = "PYHUB_PROXY_USER"
+	PasswordEnv = "PYHUB_PROXY_PASSWORD"
+)
+
+// maxRounds limits the CONNECT requests of one handshake
+const maxRounds = 3
+
+// handshakeTimeout bounds a handshake when the dial context has no deadline
+const handshakeTimeout = 30 * time.Second
+
+// Authenticator produces the tokens of a connection-based proxy authentication
+// handshake, where every step must be sent on the same connection
+type Authenticator interface {
+	// Scheme is the Proxy-Authorization scheme, e.g. "NTLM"
+	Scheme() string
+	// Next returns the token answering the proxy's challenge; a nil challenge starts
+	// the handshake
+	Next(challenge []byte) ([]byte, error)
+}
+
+// New creates an authenticator for a proxy. Credentials are taken from the proxy URL or
+// PYHUB_PROXY_USER and PYHUB_PROXY_PASSWORD; without them the logged-on Windows user
+// authenticates.
+func New(scheme string, proxy *url.URL, getenv func(string) string) (Authenticator, error) {
+	user, password := getenv(UserEnv), getenv(PasswordEnv)
+	if proxy.User != nil {
+		user = proxy.User.Username()
+		password, _ = proxy.User.Password()
+	}
+
+	switch strings.ToLower(scheme) {
+	case "ntlm":
+		if user != "" {
+			return newNTLM("NTLM", user, password), nil
+		}
+		return newSSPI("NTLM", proxy.Hostname())
+	case "negotiate":
+		if user != "" {
+			// Negotiate accepts a raw NTLM token where Kerberos is not available
+			return newNTLM("Negotiate", user, password), nil
+		}
+		return newSSPI("Negotiate", proxy.Hostname())
+	}
+	return nil, fmt.Errorf("unknown proxy authentication scheme %q (supported: %s)", scheme, strings.Join(Schemes, ", "))
+}
+
+// Dialer opens connections tunneled through an authenticating proxy with HTTP CONNECT.
+// Targets without a proxy are dialed directly.
+type Dialer struct {
+	Proxy   func(*http.Request) (*url.URL, error) // Proxy for a target, as http.Transport.Proxy
+	NewAuth func(proxy *url.URL) (Authenticator, error)
+	Dial    func(ctx context.Context, network, addr string) (net.Conn, error)
+}
+
+// Configure makes a transport tunnel every proxied connection through its proxy with a
+// connection-based authentication scheme
+func Configure(transport *http.Transport, scheme string, getenv func(string) string) error {
+	if !slices.Contains(Schemes, strings.ToLower(scheme)) {
+		return fmt.Errorf("unknown proxy authentication scheme %q (supported: %s)", scheme, strings.Join(Schemes, ", "))
+	}
+
+	proxy := transport.Proxy
+	if proxy == nil {
+		proxy = http.ProxyFromEnvironment
+	}
+	dialer := &Dialer{
+		Proxy: proxy,
+		NewAuth: func(proxy *url.URL) (Authenticator, error) {
+			return New(scheme, proxy, getenv)
+		},
+		Dial: (&net.Dialer{Timeout: 30 * time.Second, KeepAlive: 30 * time.Second}).DialContext,
+	}
+	transport.Proxy = nil
+	transport.DialContext = dialer.DialContext
+	return nil
+}
+
+// DialContext connects to addr, through its proxy if it has one
+func (d *Dialer) DialContext(ctx context.Context, network, addr string) (net.Conn, error) {
+	// Only the port tells whether the connection is for HTTPS
+	scheme := "http"
+	if _, port, _ := net.SplitHostPort(addr); port == "443" {
+		scheme = "https"
+	}
+	proxy, err := d.Proxy(&http.Request{URL: &url.URL{Scheme: scheme, Host: addr}})
+	if err != nil {
+		return nil, err
+	}
+	if proxy == nil {
+		return d.Dial(ctx, network, addr)
+	}
+
+	if proxy.Scheme != "http" {
+		return nil, fmt.Errorf("%s proxy authentication needs an http:// proxy, not %s", proxy.Scheme, proxy.Redacted())
+	}
+	proxyAddr := proxy.Host
+	if proxy.Port() == "" {
+		proxyAddr = net.JoinHostPort(proxy.Hostname(), "80")
+	}
+	conn, err := d.Dial(ctx, network, proxyAddr)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to proxy %s: %w", proxy.Redacted(), err)
+	}
+
+	deadline, ok := ctx.Deadline()
+	if !ok {
+		deadline = time.Now().Add(handshakeTimeout)
+	}
+	conn.SetDeadline(deadline)
+	tunnel, err := d.connect(conn, proxy, addr)
+	if err != nil {
+		conn.Close()
+		return nil, err
+	}
+	conn.SetDeadline(time.Time{})
+	return tunnel, nil
+}
+
+// connect opens a tunnel to addr on a proxy connection, authenticating on the way
+func (d *Dialer) connect(conn net.Conn, proxy *url.URL, addr string) (net.Conn, error) {
+	auth, err := d.NewAuth(proxy)
+	if err != nil {
+		return nil, err
+	}
+	token, err := auth.Next(nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to start %s proxy authentication: %w", auth.Scheme(), err)
+	}
+
+	reader := bufio.NewReader(conn)
+	for round := 0; round < maxRounds; round++ {
+		req := &http.Request{
+			Method: http.MethodConnect,
+			URL:    &url.URL{Opaque: addr},
+			Host:   addr,
+			Header: http.Header{},
+		}
+		req.Header.Set("Proxy-Authorization", auth.Scheme()+" "+base64.StdEncoding.EncodeToString(token))
+		req.Header.Set("Proxy-Connection", "Keep-Alive")
+		if err := req.Write(conn); err != nil {
+			return nil, fmt.Errorf("failed to send CONNECT to proxy: %w", err)
+		}
+
+		resp, err := http.ReadResponse(reader, req)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read proxy response: %w", err)
+		}
+		if resp.StatusCode == http.StatusOK {
+			return &bufferedConn{Conn: conn, reader: reader}, nil
+		}
+		io.Copy(io.Discard, resp.Body)
+		resp.Body.Close()
+
+		if resp.StatusCode != http.StatusProxyAuthRequired {
+			return nil, fmt.Errorf("proxy refused CONNECT to %s: %s", addr, resp.Status)
+		}
+		challenge := findChallenge(resp.Header, auth.Scheme())
+		if challenge == nil {
+			return nil, fmt.Errorf("proxy rejected %s authentication to %s", auth.Scheme(), addr)
+		}
+		if resp.Close {
+			return nil, fmt.Errorf("proxy closed the connection during %s authentication", auth.Scheme())
+		}
+		if token, err = auth.Next(challenge); err != nil {
+			return nil, fmt.Errorf("failed to answer %s proxy challenge: %w", auth.Scheme(), err)
+		}
+	}
+	return nil, fmt.Errorf("%s proxy authentication did not complete after %d attempts", auth.Scheme(), maxRounds)
+}
+
+// findChallenge returns the decoded token of a Proxy-Authenticate header of scheme, or
+// nil if there is none
+func findChallenge(header http.Header, scheme string) []byte {
+	for _, value := range header.Values("Proxy-Authenticate") {
+		name, token, _ := strings.Cut(strings.TrimSpace(value), " ")
+		if !strings.EqualFold(name, scheme) || token == "" {
+			continue
+		}
+		if decoded, err := base64.StdEncoding.DecodeString(strings.TrimSpace(token)); err == nil {
+			return decoded
+		}
+	}
+	return nil
+}
+
+// bufferedConn reads the bytes buffered while reading the proxy's response first
+type bufferedConn struct {
+	net.Conn
+	reader *bufio.Reader
+}
+
+// Read reads from the buffer, then the connection
+func (c *bufferedConn) Read(p []byte) (int, error) {
+	return c.reader.Read(p)
+}
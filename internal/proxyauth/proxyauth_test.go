@@ -0,0 +1,146 @@
+package proxyauth
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/base64"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"testing"
+)
+
+// fakeProxy accepts one connection, requires an NTLM handshake for CONNECT and then
+// echoes the tunneled bytes
+func fakeProxy(t *testing.T, accept bool) (*url.URL, <-chan string) {
+	t.Helper()
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { listener.Close() })
+
+	targets := make(chan string, 1)
+	go func() {
+		conn, err := listener.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		reader := bufio.NewReader(conn)
+
+		for round := 0; ; round++ {
+			req, err := http.ReadRequest(reader)
+			if err != nil {
+				return
+			}
+			scheme, token, _ := strings.Cut(req.Header.Get("Proxy-Authorization"), " ")
+			msg, _ := base64.StdEncoding.DecodeString(token)
+			if scheme != "NTLM" || !bytes.HasPrefix(msg, ntlmSignature) {
+				io.WriteString(conn, "HTTP/1.1 407 Proxy Authentication Required\r\nContent-Length: 0\r\n\r\n")
+				return
+			}
+
+			switch msg[8] {
+			case 1:
+				io.WriteString(conn, "HTTP/1.1 407 Proxy Authentication Required\r\n"+
+					"Proxy-Authenticate: NTLM "+base64.StdEncoding.EncodeToString(challenge(testTargetInfo))+"\r\n"+
+					"Content-Length: 4\r\n\r\ndeny")
+			case 3:
+				if !accept {
+					io.WriteString(conn, "HTTP/1.1 407 Proxy Authentication Required\r\nProxy-Authenticate: NTLM\r\nContent-Length: 0\r\n\r\n")
+					return
+				}
+				targets <- req.Host
+				io.WriteString(conn, "HTTP/1.1 200 Connection established\r\n\r\n")
+				io.Copy(conn, reader)
+				return
+			}
+		}
+	}()
+
+	return &url.URL{Scheme: "http", User: url.UserPassword(`CORP\alice`, "secret"), Host: listener.Addr().String()}, targets
+}
+
+func newTestDialer(proxy *url.URL) *Dialer {
+	return &Dialer{
+		Proxy: http.ProxyURL(proxy),
+		NewAuth: func(proxy *url.URL) (Authenticator, error) {
+			return New("ntlm", proxy, func(string) string { return "" })
+		},
+		Dial: (&net.Dialer{}).DialContext,
+	}
+}
+
+func TestDialerNTLM(t *testing.T) {
+	proxy, targets := fakeProxy(t, true)
+
+	conn, err := newTestDialer(proxy).DialContext(context.Background(), "tcp", "github.com:443")
+	if err != nil {
+		t.Fatalf("DialContext() error = %v", err)
+	}
+	defer conn.Close()
+
+	if target := <-targets; target != "github.com:443" {
+		t.Errorf("Expected CONNECT to github.com:443, got %s", target)
+	}
+	io.WriteString(conn, "ping")
+	buf := make([]byte, 4)
+	if _, err := io.ReadFull(conn, buf); err != nil || string(buf) != "ping" {
+		t.Errorf("Expected the tunnel to echo, got %q (%v)", buf, err)
+	}
+}
+
+func TestDialerRejected(t *testing.T) {
+	proxy, _ := fakeProxy(t, false)
+
+	_, err := newTestDialer(proxy).DialContext(context.Background(), "tcp", "github.com:443")
+	if err == nil || !strings.Contains(err.Error(), "rejected NTLM") {
+		t.Errorf("Expected rejected credentials error, got %v", err)
+	}
+}
+
+func TestNewCredentials(t *testing.T) {
+	getenv := func(name string) string {
+		return map[string]string{UserEnv: `CORP\bob`, PasswordEnv: "env-secret"}[name]
+	}
+
+	auth, err := New("Negotiate", &url.URL{Host: "proxy:8080"}, getenv)
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	ntlm, ok := auth.(*ntlmAuth)
+	if !ok || ntlm.Scheme() != "Negotiate" || ntlm.user != "bob" || ntlm.password != "env-secret" {
+		t.Errorf("Expected NTLM under Negotiate with environment credentials, got %+v", auth)
+	}
+
+	auth, err = New("ntlm", &url.URL{User: url.UserPassword("alice", "url-secret"), Host: "proxy:8080"}, getenv)
+	if err != nil || auth.(*ntlmAuth).password != "url-secret" {
+		t.Errorf("Expected credentials of the proxy URL to take precedence, got %+v (%v)", auth, err)
+	}
+
+	if _, err := New("digest", &url.URL{Host: "proxy:8080"}, getenv); err == nil {
+		t.Error("Expected error for an unknown scheme")
+	}
+}
+
+func TestConfigureUnknownScheme(t *testing.T) {
+	transport := &http.Transport{Proxy: http.ProxyFromEnvironment}
+	err := Configure(transport, "digest", func(string) string { return "" })
+	if err == nil || !strings.Contains(err.Error(), "digest") {
+		t.Errorf("Expected error for an unknown scheme, got %v", err)
+	}
+	if transport.Proxy == nil || transport.DialContext != nil {
+		t.Error("Expected the transport to be left unchanged")
+	}
+
+	if err := Configure(transport, "NTLM", func(string) string { return "" }); err != nil {
+		t.Errorf("Configure() error = %v", err)
+	}
+	if transport.Proxy != nil || transport.DialContext == nil {
+		t.Error("Expected connections to be tunneled through the dialer")
+	}
+}
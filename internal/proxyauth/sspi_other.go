@@ -0,0 +1,10 @@
+//go:build !windows
+
+package proxyauth
+
+import "fmt"
+
+// newSSPI fails outside Windows, where the credentials of the logged-on user cannot be used
+func newSSPI(scheme, host string) (Authenticator, error) {
+	return nil, fmt.Errorf("%s proxy authentication needs %s and %s outside Windows", scheme, UserEnv, PasswordEnv)
+}
@@ -0,0 +1,141 @@
+//go:build windows
+
+package proxyauth
+
+import (
+	"fmt"
+	"unsafe"
+
+	"golang.org/x/sys/windows"
+)
+
+// SSPI constants (sspi.h)
+const (
+	secpkgCredOutbound      = 2
+	secbufferVersion        = 0
+	secbufferToken          = 2
+	securityNativeDrep      = 0x10
+	iscReqAllocateMemory    = 0x100
+	iscReqConnection        = 0x800
+	secEOK                  = 0
+	secIContinueNeeded      = 0x00090312
+	secICompleteNeeded      = 0x00090313
+	secICompleteAndContinue = 0x00090314
+)
+
+var (
+	secur32                        = windows.NewLazySystemDLL("secur32.dll")
+	procAcquireCredentialsHandleW  = secur32.NewProc("AcquireCredentialsHandleW")
+	procInitializeSecurityContextW = secur32.NewProc("InitializeSecurityContextW")
+	procFreeCredentialsHandle      = secur32.NewProc("FreeCredentialsHandle")
+	procDeleteSecurityContext      = secur32.NewProc("DeleteSecurityContext")
+	procFreeContextBuffer          = secur32.NewProc("FreeContextBuffer")
+)
+
+// secHandle is CredHandle and CtxtHandle
+type secHandle struct {
+	lower, upper uintptr
+}
+
+// secBuffer is SecBuffer
+type secBuffer struct {
+	size       uint32
+	bufferType uint32
+	buffer     *byte
+}
+
+// secBufferDesc is SecBufferDesc
+type secBufferDesc struct {
+	version uint32
+	count   uint32
+	buffers *secBuffer
+}
+
+// sspiAuth authenticates as the logged-on user through the Windows SSPI, so NTLM and
+// Kerberos work without a password
+type sspiAuth struct {
+	scheme  string
+	target  *uint16 // Service principal name of the proxy, HTTP/host
+	cred    secHandle
+	ctx     secHandle
+	started bool
+}
+
+// newSSPI acquires the credentials of the logged-on user for a security package
+func newSSPI(scheme, host string) (Authenticator, error) {
+	if err := procAcquireCredentialsHandleW.Find(); err != nil {
+		return nil, fmt.Errorf("SSPI is not available: %w", err)
+	}
+	pkg, err := windows.UTF16PtrFromString(scheme)
+	if err != nil {
+		return nil, err
+	}
+	target, err := windows.UTF16PtrFromString("HTTP/" + host)
+	if err != nil {
+		return nil, err
+	}
+
+	a := &sspiAuth{scheme: scheme, target: target}
+	var expiry int64
+	status, _, _ := procAcquireCredentialsHandleW.Call(
+		0, uintptr(unsafe.Pointer(pkg)), secpkgCredOutbound, 0, 0, 0, 0,
+		uintptr(unsafe.Pointer(&a.cred)), uintptr(unsafe.Pointer(&expiry)))
+	if status != secEOK {
+		return nil, fmt.Errorf("failed to acquire %s credentials of the current user: SSPI status 0x%x", scheme, status)
+	}
+	return a, nil
+}
+
+// Scheme returns the Proxy-Authorization scheme
+func (a *sspiAuth) Scheme() string {
+	return a.scheme
+}
+
+// Next passes the proxy's challenge to the security package and returns its answer
+func (a *sspiAuth) Next(challenge []byte) ([]byte, error) {
+	var input *secBufferDesc
+	if challenge != nil {
+		in := secBuffer{size: uint32(len(challenge)), bufferType: secbufferToken, buffer: &challenge[0]}
+		input = &secBufferDesc{version: secbufferVersion, count: 1, buffers: &in}
+	}
+	out := secBuffer{bufferType: secbufferToken}
+	output := secBufferDesc{version: secbufferVersion, count: 1, buffers: &out}
+
+	var ctx uintptr
+	if a.started {
+		ctx = uintptr(unsafe.Pointer(&a.ctx))
+	}
+	var attributes uint32
+	var expiry int64
+	status, _, _ := procInitializeSecurityContextW.Call(
+		uintptr(unsafe.Pointer(&a.cred)), ctx, uintptr(unsafe.Pointer(a.target)),
+		iscReqAllocateMemory|iscReqConnection, 0, securityNativeDrep,
+		uintptr(unsafe.Pointer(input)), 0, uintptr(unsafe.Pointer(&a.ctx)),
+		uintptr(unsafe.Pointer(&output)), uintptr(unsafe.Pointer(&attributes)), uintptr(unsafe.Pointer(&expiry)))
+	a.started = true
+	if out.buffer != nil {
+		defer procFreeContextBuffer.Call(uintptr(unsafe.Pointer(out.buffer)))
+	}
+
+	switch status {
+	case secEOK, secIContinueNeeded, secICompleteNeeded, secICompleteAndContinue:
+	default:
+		a.close()
+		return nil, fmt.Errorf("SSPI status 0x%x", status)
+	}
+	if status == secEOK {
+		defer a.close()
+	}
+	if out.buffer == nil || out.size == 0 {
+		return []byte{}, nil
+	}
+	return append([]byte{}, unsafe.Slice(out.buffer, out.size)...), nil
+}
+
+// close releases the security context and credentials
+func (a *sspiAuth) close() {
+	if a.started {
+		procDeleteSecurityContext.Call(uintptr(unsafe.Pointer(&a.ctx)))
+	}
+	procFreeCredentialsHandle.Call(uintptr(unsafe.Pointer(&a.cred)))
+}
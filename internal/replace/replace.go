@@ -0,0 +1,62 @@
+package replace
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/pyhub-kr/pyhub-installer/internal/i18n"
+)
+
+// staleMarker is inserted into the names of files moved aside while in use
+const staleMarker = ".pyhub-old-"
+
+// Prepare makes it possible to write a new file at path when the existing file is in use.
+// A running executable cannot be overwritten (Windows, and ETXTBSY on Linux) but it can be
+// renamed, so the old file is moved aside and deleted now or as soon as it is released.
+func Prepare(path string) error {
+	file, err := os.OpenFile(path, os.O_WRONLY, 0)
+	if err == nil {
+		file.Close()
+		return nil
+	}
+	if os.IsNotExist(err) || !isInUse(err) {
+		// Let the actual write report any other problem
+		return nil
+	}
+
+	return moveAside(path)
+}
+
+// moveAside renames path out of the way and removes or schedules removal of the old file
+func moveAside(path string) error {
+	old := fmt.Sprintf("%s%s%d", path, staleMarker, time.Now().UnixNano())
+	if err := os.Rename(path, old); err != nil {
+		return fmt.Errorf("failed to move in-use file %s aside: %w", path, err)
+	}
+
+	// Unix can remove a running executable; Windows has to wait until it exits
+	if err := os.Remove(old); err != nil {
+		scheduleDelete(old)
+		fmt.Print(i18n.T("Note: %s is in use; the old version will be removed once it exits\n", filepath.Base(path)))
+	}
+	return nil
+}
+
+// CleanupStale removes files left behind by earlier in-use replacements in dir
+func CleanupStale(dir string) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return
+	}
+
+	for _, entry := range entries {
+		if entry.IsDir() || !strings.Contains(entry.Name(), staleMarker) {
+			continue
+		}
+		// Still-running files fail to delete; they are retried on the next run
+		os.Remove(filepath.Join(dir, entry.Name()))
+	}
+}
@@ -0,0 +1,102 @@
+package replace
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestPrepareMissingFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "missing")
+	if err := Prepare(path); err != nil {
+		t.Errorf("Prepare should ignore missing files, got %v", err)
+	}
+}
+
+func TestPrepareWritableFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "tool")
+	if err := os.WriteFile(path, []byte("old"), 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := Prepare(path); err != nil {
+		t.Fatalf("Prepare failed: %v", err)
+	}
+
+	// File is not in use, so it must be left in place
+	content, err := os.ReadFile(path)
+	if err != nil || string(content) != "old" {
+		t.Errorf("Writable file should be untouched, got %q (%v)", content, err)
+	}
+}
+
+func TestPrepareRunningExecutable(t *testing.T) {
+	if runtime.GOOS != "linux" {
+		t.Skip("Running-executable replacement is exercised on Linux")
+	}
+
+	sleepPath, err := exec.LookPath("sleep")
+	if err != nil {
+		t.Skip("sleep not available")
+	}
+	original, err := os.ReadFile(sleepPath)
+	if err != nil {
+		t.Skip("cannot read sleep binary")
+	}
+
+	dir := t.TempDir()
+	path := filepath.Join(dir, "tool")
+	if err := os.WriteFile(path, original, 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	cmd := exec.Command(path, "5")
+	if err := cmd.Start(); err != nil {
+		t.Skipf("cannot run copied binary: %v", err)
+	}
+	defer cmd.Process.Kill()
+	time.Sleep(100 * time.Millisecond)
+
+	if _, err := os.OpenFile(path, os.O_WRONLY|os.O_TRUNC, 0); err == nil {
+		t.Skip("filesystem allows writing running executables")
+	}
+
+	if err := Prepare(path); err != nil {
+		t.Fatalf("Prepare failed: %v", err)
+	}
+	if err := os.WriteFile(path, []byte("new"), 0755); err != nil {
+		t.Fatalf("Writing replacement failed: %v", err)
+	}
+
+	content, _ := os.ReadFile(path)
+	if string(content) != "new" {
+		t.Errorf("Expected replacement content, got %q", content)
+	}
+}
+
+func TestCleanupStale(t *testing.T) {
+	dir := t.TempDir()
+	stale := filepath.Join(dir, "tool.exe"+staleMarker+"12345")
+	keep := filepath.Join(dir, "tool.exe")
+	for _, path := range []string{stale, keep} {
+		if err := os.WriteFile(path, []byte("x"), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	CleanupStale(dir)
+
+	entries, _ := os.ReadDir(dir)
+	for _, entry := range entries {
+		if strings.Contains(entry.Name(), staleMarker) {
+			t.Errorf("Stale file should be removed: %s", entry.Name())
+		}
+	}
+	if _, err := os.Stat(keep); err != nil {
+		t.Errorf("Regular file should be kept: %v", err)
+	}
+}
@@ -0,0 +1,16 @@
+//go:build !windows
+
+package replace
+
+import (
+	"errors"
+	"syscall"
+)
+
+// isInUse checks if an open error means the file is a running executable
+func isInUse(err error) bool {
+	return errors.Is(err, syscall.ETXTBSY)
+}
+
+// scheduleDelete is not needed on Unix, where in-use files can be removed
+func scheduleDelete(path string) {}
@@ -0,0 +1,24 @@
+//go:build windows
+
+package replace
+
+import (
+	"errors"
+
+	"golang.org/x/sys/windows"
+)
+
+// isInUse checks if an open error means the file is locked by a running process
+func isInUse(err error) bool {
+	return errors.Is(err, windows.ERROR_SHARING_VIOLATION) || errors.Is(err, windows.ERROR_ACCESS_DENIED)
+}
+
+// scheduleDelete asks Windows to delete the file at the next reboot.
+// This requires elevation; without it CleanupStale removes the file on a later run.
+func scheduleDelete(path string) {
+	from, err := windows.UTF16PtrFromString(path)
+	if err != nil {
+		return
+	}
+	windows.MoveFileEx(from, nil, windows.MOVEFILE_DELAY_UNTIL_REBOOT)
+}
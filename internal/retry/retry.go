@@ -0,0 +1,179 @@
+package retry
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand/v2"
+	"net/http"
+	"os"
+	"slices"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/pyhub-kr/pyhub-installer/internal/i18n"
+)
+
+// Policy decides how often and how long apart failed requests are tried again.
+// Requests fail with a network error or a response status listed in Statuses.
+type Policy struct {
+	Attempts  int           // Total tries of a request; 1 disables retrying
+	BaseDelay time.Duration // Delay before the first retry, doubled before each further one
+	MaxDelay  time.Duration // Longest delay between tries, including Retry-After
+	Statuses  []int         // Response statuses that are retried
+}
+
+// DefaultPolicy returns the policy used unless one is configured
+func DefaultPolicy() Policy {
+	return Policy{
+		Attempts:  3,
+		BaseDelay: 500 * time.Millisecond,
+		MaxDelay:  10 * time.Second,
+		Statuses: []int{
+			http.StatusRequestTimeout,
+			http.StatusTooManyRequests,
+			http.StatusInternalServerError,
+			http.StatusBadGateway,
+			http.StatusServiceUnavailable,
+			http.StatusGatewayTimeout,
+		},
+	}
+}
+
+var (
+	mu      sync.Mutex
+	current = DefaultPolicy()
+)
+
+// Configure sets the policy of Do for all requests of the process
+func Configure(p Policy) {
+	mu.Lock()
+	defer mu.Unlock()
+	current = p
+}
+
+// Current returns the configured policy
+func Current() Policy {
+	mu.Lock()
+	defer mu.Unlock()
+	return current
+}
+
+// Do sends a request with the configured policy, see Policy.Do
+func Do(ctx context.Context, send func() (*http.Response, error)) (*http.Response, error) {
+	return Current().Do(ctx, send)
+}
+
+// Do calls send until it returns a response that is not retried, or the attempts are
+// used up, waiting between tries. send must create a new request each time. The last
+// response or error is returned, so callers report failures as before.
+func (p Policy) Do(ctx context.Context, send func() (*http.Response, error)) (*http.Response, error) {
+	for attempt := 1; ; attempt++ {
+		resp, err := send()
+		if attempt >= p.Attempts || ctx.Err() != nil {
+			return resp, err
+		}
+
+		var reason string
+		delay := jitter(p.Delay(attempt))
+		switch {
+		case err != nil:
+			if errors.Is(err, context.Canceled) {
+				return resp, err
+			}
+			reason = err.Error()
+		case p.Retryable(resp.StatusCode):
+			if wait, ok := retryAfter(resp); ok {
+				if wait > p.MaxDelay {
+					// The server asks for more patience than allowed
+					return resp, nil
+				}
+				delay = wait
+			}
+			resp.Body.Close()
+			reason = fmt.Sprintf("HTTP %d", resp.StatusCode)
+		default:
+			return resp, nil
+		}
+
+		if err := p.wait(ctx, delay, attempt, reason); err != nil {
+			return nil, err
+		}
+	}
+}
+
+// Wait reports why an attempt (1 for the first) failed and waits its jittered backoff
+// delay, for callers that retry more than sending a request, e.g. reading a response
+func (p Policy) Wait(ctx context.Context, attempt int, reason string) error {
+	return p.wait(ctx, jitter(p.Delay(attempt)), attempt, reason)
+}
+
+// wait reports a failed attempt and waits delay before the next
+func (p Policy) wait(ctx context.Context, delay time.Duration, attempt int, reason string) error {
+	fmt.Fprint(os.Stderr, i18n.T("Request failed (%s); retrying in %s (attempt %d of %d)\n", reason, delay.Round(time.Millisecond), attempt+1, p.Attempts))
+	return sleep(ctx, delay)
+}
+
+// Delay returns how long to wait after a failed attempt (1 for the first)
+func (p Policy) Delay(attempt int) time.Duration {
+	delay := p.BaseDelay
+	for i := 1; i < attempt && delay < p.MaxDelay; i++ {
+		delay *= 2
+	}
+	return min(delay, p.MaxDelay)
+}
+
+// jitter randomizes a backoff delay between half and all of it, so that clients
+// failing together don't retry in lockstep; replaced in tests
+var jitter = func(d time.Duration) time.Duration {
+	if d <= 0 {
+		return d
+	}
+	return d/2 + rand.N(d/2+1)
+}
+
+// Without returns the policy without retrying the given statuses, e.g. for clients
+// that handle rate limits themselves
+func (p Policy) Without(statuses ...int) Policy {
+	kept := make([]int, 0, len(p.Statuses))
+	for _, s := range p.Statuses {
+		if !slices.Contains(statuses, s) {
+			kept = append(kept, s)
+		}
+	}
+	p.Statuses = kept
+	return p
+}
+
+// Retryable reports whether a response status is retried
+func (p Policy) Retryable(status int) bool {
+	return slices.Contains(p.Statuses, status)
+}
+
+// retryAfter returns the delay a response asks for with Retry-After
+func retryAfter(resp *http.Response) (time.Duration, bool) {
+	value := resp.Header.Get("Retry-After")
+	if value == "" {
+		return 0, false
+	}
+	if seconds, err := strconv.Atoi(value); err == nil {
+		return time.Duration(seconds) * time.Second, true
+	}
+	if at, err := http.ParseTime(value); err == nil {
+		return max(time.Until(at), 0), true
+	}
+	return 0, false
+}
+
+// sleep waits for d unless ctx is canceled first; replaced in tests
+var sleep = func(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
@@ -0,0 +1,155 @@
+package retry
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// noSleep records the delays, without jitter, instead of waiting
+func noSleep(t *testing.T) *[]time.Duration {
+	t.Helper()
+	var delays []time.Duration
+	saved, savedJitter := sleep, jitter
+	sleep = func(ctx context.Context, d time.Duration) error {
+		delays = append(delays, d)
+		return ctx.Err()
+	}
+	jitter = func(d time.Duration) time.Duration { return d }
+	t.Cleanup(func() { sleep, jitter = saved, savedJitter })
+	return &delays
+}
+
+func TestDo(t *testing.T) {
+	delays := noSleep(t)
+	var requests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if requests.Add(1) < 3 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.Write([]byte("ok"))
+	}))
+	defer server.Close()
+
+	p := Policy{Attempts: 4, BaseDelay: time.Second, MaxDelay: time.Minute, Statuses: []int{http.StatusServiceUnavailable}}
+	resp, err := p.Do(context.Background(), func() (*http.Response, error) { return http.Get(server.URL) })
+	if err != nil {
+		t.Fatalf("Do() error = %v", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK || requests.Load() != 3 {
+		t.Errorf("Expected success on the third try, got %d after %d", resp.StatusCode, requests.Load())
+	}
+	if len(*delays) != 2 || (*delays)[0] != time.Second || (*delays)[1] != 2*time.Second {
+		t.Errorf("Expected exponential delays, got %v", *delays)
+	}
+}
+
+func TestDoGivesUp(t *testing.T) {
+	noSleep(t)
+	var requests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		if r.URL.Path == "/missing" {
+			http.NotFound(w, r)
+			return
+		}
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+	defer server.Close()
+
+	p := DefaultPolicy()
+	resp, err := p.Do(context.Background(), func() (*http.Response, error) { return http.Get(server.URL) })
+	if err != nil || resp.StatusCode != http.StatusBadGateway || requests.Load() != int32(p.Attempts) {
+		t.Errorf("Expected the last response after %d tries, got %v, %v after %d", p.Attempts, resp, err, requests.Load())
+	}
+
+	requests.Store(0)
+	resp, _ = p.Do(context.Background(), func() (*http.Response, error) { return http.Get(server.URL + "/missing") })
+	if resp.StatusCode != http.StatusNotFound || requests.Load() != 1 {
+		t.Errorf("Expected statuses that are not listed to be returned at once, got %d after %d", resp.StatusCode, requests.Load())
+	}
+
+	// Network errors are retried, cancellation is not
+	failures := 0
+	_, err = p.Do(context.Background(), func() (*http.Response, error) { failures++; return nil, errors.New("connection reset") })
+	if err == nil || failures != p.Attempts {
+		t.Errorf("Expected %d tries of a failing request, got %d", p.Attempts, failures)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	failures = 0
+	p.Do(ctx, func() (*http.Response, error) { failures++; return nil, ctx.Err() })
+	if failures != 1 {
+		t.Errorf("Expected a canceled request not to be retried, got %d tries", failures)
+	}
+}
+
+func TestRetryAfter(t *testing.T) {
+	delays := noSleep(t)
+	var requests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if requests.Add(1) == 1 {
+			w.Header().Set("Retry-After", r.URL.Query().Get("after"))
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		w.Write([]byte("ok"))
+	}))
+	defer server.Close()
+
+	p := DefaultPolicy()
+	resp, _ := p.Do(context.Background(), func() (*http.Response, error) { return http.Get(server.URL + "?after=3") })
+	if resp.StatusCode != http.StatusOK || len(*delays) != 1 || (*delays)[0] != 3*time.Second {
+		t.Errorf("Expected to wait the 3s asked for, got %d after %v", resp.StatusCode, *delays)
+	}
+
+	// Longer waits than MaxDelay are left to the caller
+	requests.Store(0)
+	resp, _ = p.Do(context.Background(), func() (*http.Response, error) { return http.Get(server.URL + "?after=3600") })
+	if resp.StatusCode != http.StatusTooManyRequests || requests.Load() != 1 {
+		t.Errorf("Expected the response asking for an hour to be returned, got %d after %d", resp.StatusCode, requests.Load())
+	}
+}
+
+func TestDelay(t *testing.T) {
+	p := Policy{BaseDelay: time.Second, MaxDelay: 5 * time.Second}
+	for attempt, want := range []time.Duration{time.Second, 2 * time.Second, 4 * time.Second, 5 * time.Second, 5 * time.Second} {
+		if got := p.Delay(attempt + 1); got != want {
+			t.Errorf("Delay(%d) = %s, want %s", attempt+1, got, want)
+		}
+	}
+}
+
+func TestJitter(t *testing.T) {
+	for range 100 {
+		if d := jitter(time.Second); d < 500*time.Millisecond || d > time.Second {
+			t.Fatalf("Expected a delay between half and all of 1s, got %s", d)
+		}
+	}
+	if d := jitter(0); d != 0 {
+		t.Errorf("Expected no delay to stay none, got %s", d)
+	}
+}
+
+func TestWait(t *testing.T) {
+	delays := noSleep(t)
+	p := Policy{Attempts: 3, BaseDelay: time.Second, MaxDelay: time.Minute}
+	if err := p.Wait(context.Background(), 2, "connection reset"); err != nil {
+		t.Fatalf("Wait() error = %v", err)
+	}
+	if len(*delays) != 1 || (*delays)[0] != 2*time.Second {
+		t.Errorf("Expected the backoff delay of the second attempt, got %v", *delays)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if err := p.Wait(ctx, 1, "timeout"); !errors.Is(err, context.Canceled) {
+		t.Errorf("Expected canceled wait, got %v", err)
+	}
+}
@@ -0,0 +1,315 @@
+package scoop
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/pyhub-kr/pyhub-installer/internal/retry"
+)
+
+// DefaultBucket is used for manifests named without a bucket
+const DefaultBucket = "main"
+
+// Buckets maps the known Scoop bucket names to their GitHub repositories
+var Buckets = map[string]string{
+	"main":       "ScoopInstaller/Main",
+	"extras":     "ScoopInstaller/Extras",
+	"versions":   "ScoopInstaller/Versions",
+	"java":       "ScoopInstaller/Java",
+	"nerd-fonts": "matthewjberger/scoop-nerd-fonts",
+}
+
+// ErrNotFound matches the errors of manifests that do not exist
+var ErrNotFound = errors.New("not found")
+
+// architectures maps Scoop architecture keys to Go architectures
+var architectures = map[string]string{
+	"64bit": "amd64",
+	"32bit": "386",
+	"arm64": "arm64",
+}
+
+// StringList is a manifest value given as a string or an array of strings
+type StringList []string
+
+// UnmarshalJSON accepts a single string or an array
+func (l *StringList) UnmarshalJSON(data []byte) error {
+	var single string
+	if err := json.Unmarshal(data, &single); err == nil {
+		*l = StringList{single}
+		return nil
+	}
+	var list []string
+	if err := json.Unmarshal(data, &list); err != nil {
+		return err
+	}
+	*l = list
+	return nil
+}
+
+// Bin is an executable exposed on PATH, optionally under an alias with fixed arguments
+type Bin struct {
+	Path  string
+	Alias string
+	Args  []string
+}
+
+// Name returns the command name of the executable
+func (b Bin) Name() string {
+	if b.Alias != "" {
+		return b.Alias
+	}
+	base := path.Base(strings.ReplaceAll(b.Path, `\`, "/"))
+	return strings.TrimSuffix(base, path.Ext(base))
+}
+
+// Bins is the "bin" field: a path, or an array of paths and [path, alias, args...] arrays
+type Bins []Bin
+
+// UnmarshalJSON accepts every form of the bin field
+func (b *Bins) UnmarshalJSON(data []byte) error {
+	var single string
+	if err := json.Unmarshal(data, &single); err == nil {
+		*b = Bins{{Path: single}}
+		return nil
+	}
+
+	var entries []json.RawMessage
+	if err := json.Unmarshal(data, &entries); err != nil {
+		return err
+	}
+	*b = nil
+	for _, entry := range entries {
+		var parts StringList
+		if err := json.Unmarshal(entry, &parts); err != nil || len(parts) == 0 {
+			return fmt.Errorf("invalid bin entry: %s", entry)
+		}
+		bin := Bin{Path: parts[0]}
+		if len(parts) > 1 {
+			bin.Alias = parts[1]
+		}
+		if len(parts) > 2 {
+			bin.Args = strings.Fields(strings.Join(parts[2:], " "))
+		}
+		*b = append(*b, bin)
+	}
+	return nil
+}
+
+// Shortcut is a Start menu entry: the target inside the app and the shortcut name
+type Shortcut struct {
+	Target string
+	Name   string
+}
+
+// Shortcuts is the "shortcuts" field, an array of [target, name, ...] arrays
+type Shortcuts []Shortcut
+
+// UnmarshalJSON reads the shortcut arrays, ignoring parameters and icons
+func (s *Shortcuts) UnmarshalJSON(data []byte) error {
+	var entries [][]string
+	if err := json.Unmarshal(data, &entries); err != nil {
+		return err
+	}
+	*s = nil
+	for _, entry := range entries {
+		if len(entry) < 2 {
+			return fmt.Errorf("invalid shortcut entry: %v", entry)
+		}
+		*s = append(*s, Shortcut{Target: entry[0], Name: entry[1]})
+	}
+	return nil
+}
+
+// Spec holds the fields that may be set per architecture
+type Spec struct {
+	URL        StringList `json:"url"`
+	Hash       StringList `json:"hash"`
+	ExtractDir StringList `json:"extract_dir"`
+	Bin        Bins       `json:"bin"`
+	Shortcuts  Shortcuts  `json:"shortcuts"`
+}
+
+// Manifest is a Scoop app manifest
+type Manifest struct {
+	Spec
+	Version      string          `json:"version"`
+	Description  string          `json:"description"`
+	Homepage     string          `json:"homepage"`
+	Architecture map[string]Spec `json:"architecture"`
+}
+
+// Package is a manifest resolved for one architecture
+type Package struct {
+	Arch       string // Go architecture, or "" if the manifest names none
+	URL        string
+	Filename   string // Download name, honoring "#/name" renames
+	SHA256     string // Empty if the manifest publishes another hash type
+	ExtractDir string
+	Bin        Bins
+	Shortcuts  Shortcuts
+}
+
+// Packages returns one package per architecture of the manifest. Architecture fields
+// override the top-level ones.
+func (m *Manifest) Packages() ([]Package, error) {
+	if len(m.Architecture) == 0 {
+		pkg, err := m.resolve("", Spec{})
+		if err != nil {
+			return nil, err
+		}
+		return []Package{pkg}, nil
+	}
+
+	var packages []Package
+	for _, key := range []string{"64bit", "32bit", "arm64"} {
+		spec, ok := m.Architecture[key]
+		if !ok {
+			continue
+		}
+		pkg, err := m.resolve(architectures[key], spec)
+		if err != nil {
+			return nil, err
+		}
+		packages = append(packages, pkg)
+	}
+	return packages, nil
+}
+
+// resolve merges an architecture spec over the top-level fields
+func (m *Manifest) resolve(arch string, spec Spec) (Package, error) {
+	merged := m.Spec
+	if len(spec.URL) > 0 {
+		merged.URL, merged.Hash = spec.URL, spec.Hash
+	}
+	if len(spec.ExtractDir) > 0 {
+		merged.ExtractDir = spec.ExtractDir
+	}
+	if len(spec.Bin) > 0 {
+		merged.Bin = spec.Bin
+	}
+	if len(spec.Shortcuts) > 0 {
+		merged.Shortcuts = spec.Shortcuts
+	}
+
+	switch len(merged.URL) {
+	case 0:
+		return Package{}, fmt.Errorf("manifest has no url")
+	case 1:
+	default:
+		return Package{}, fmt.Errorf("manifests with several downloads are not supported")
+	}
+
+	pkg := Package{
+		Arch:      arch,
+		Bin:       merged.Bin,
+		Shortcuts: merged.Shortcuts,
+	}
+	pkg.URL, pkg.Filename = splitRename(merged.URL[0])
+	if len(merged.Hash) > 0 {
+		pkg.SHA256 = sha256Hash(merged.Hash[0])
+	}
+	if len(merged.ExtractDir) > 0 {
+		pkg.ExtractDir = merged.ExtractDir[0]
+	}
+	return pkg, nil
+}
+
+// splitRename separates a "#/name" rename suffix from a download URL
+func splitRename(rawURL string) (string, string) {
+	if i := strings.Index(rawURL, "#/"); i >= 0 {
+		return rawURL[:i], rawURL[i+2:]
+	}
+	name := rawURL
+	if u, err := url.Parse(rawURL); err == nil {
+		name = u.Path
+	}
+	return rawURL, path.Base(name)
+}
+
+// sha256Hash returns the hex digest of a manifest hash if it is a SHA256 hash.
+// Scoop hashes are SHA256 unless prefixed with another algorithm.
+func sha256Hash(hash string) string {
+	hash = strings.TrimPrefix(strings.ToLower(hash), "sha256:")
+	if len(hash) != 64 || strings.Contains(hash, ":") {
+		return ""
+	}
+	return hash
+}
+
+// ManifestURL returns the location of a manifest: a URL or file as given, or the raw
+// GitHub URL of "bucket/app" and "app" (in the main bucket)
+func ManifestURL(ref string) (string, error) {
+	if strings.HasPrefix(ref, "https://") || strings.HasPrefix(ref, "http://") || strings.HasSuffix(ref, ".json") {
+		return ref, nil
+	}
+
+	bucket, app, ok := strings.Cut(ref, "/")
+	if !ok {
+		bucket, app = DefaultBucket, ref
+	}
+	repo, known := Buckets[bucket]
+	if !known {
+		return "", fmt.Errorf("unknown Scoop bucket %q; give the manifest URL instead", bucket)
+	}
+	return fmt.Sprintf("https://raw.githubusercontent.com/%s/master/bucket/%s.json", repo, app), nil
+}
+
+// Load reads a manifest from a URL or local file
+func Load(location string) (*Manifest, error) {
+	data, err := read(location)
+	if err != nil {
+		return nil, err
+	}
+
+	var manifest Manifest
+	if err := json.Unmarshal(data, &manifest); err != nil {
+		return nil, fmt.Errorf("failed to parse manifest %s: %w", location, err)
+	}
+	if manifest.Version == "" {
+		return nil, fmt.Errorf("manifest %s has no version", location)
+	}
+	return &manifest, nil
+}
+
+// read returns the content of a URL or local file
+func read(location string) ([]byte, error) {
+	if !strings.HasPrefix(location, "https://") && !strings.HasPrefix(location, "http://") {
+		data, err := os.ReadFile(location)
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, fmt.Errorf("manifest %s %w", location, ErrNotFound)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read manifest: %w", err)
+		}
+		return data, nil
+	}
+
+	client := &http.Client{Timeout: 30 * time.Second}
+	resp, err := retry.Do(context.Background(), func() (*http.Response, error) { return client.Get(location) })
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch manifest: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, fmt.Errorf("failed to fetch manifest %s: HTTP %d (%w)", location, resp.StatusCode, ErrNotFound)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to fetch manifest %s: HTTP %d", location, resp.StatusCode)
+	}
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch manifest: %w", err)
+	}
+	return data, nil
+}
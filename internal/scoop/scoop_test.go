@@ -0,0 +1,148 @@
+package scoop
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+const manifestJSON = `{
+  "version": "1.2.0",
+  "description": "Example tool",
+  "bin": ["tool.exe", ["tool.exe", "tl", "--quiet"]],
+  "shortcuts": [["gui\\tool-gui.exe", "Tool"]],
+  "architecture": {
+    "64bit": {
+      "url": "https://example.com/tool-1.2.0-x64.zip",
+      "hash": "AAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAA",
+      "extract_dir": "tool-1.2.0"
+    },
+    "32bit": {
+      "url": "https://example.com/tool-1.2.0-x86.exe#/tool.exe",
+      "hash": "sha512:bbbb",
+      "bin": "tool.exe"
+    }
+  }
+}`
+
+func TestManifestPackages(t *testing.T) {
+	var manifest Manifest
+	if err := json.Unmarshal([]byte(manifestJSON), &manifest); err != nil {
+		t.Fatalf("Unmarshal() error = %v", err)
+	}
+
+	packages, err := manifest.Packages()
+	if err != nil {
+		t.Fatalf("Packages() error = %v", err)
+	}
+	if len(packages) != 2 {
+		t.Fatalf("Expected 2 packages, got %d", len(packages))
+	}
+
+	x64 := packages[0]
+	if x64.Arch != "amd64" || x64.Filename != "tool-1.2.0-x64.zip" || x64.ExtractDir != "tool-1.2.0" {
+		t.Errorf("Unexpected 64bit package: %+v", x64)
+	}
+	if x64.SHA256 != strings.Repeat("a", 64) {
+		t.Errorf("Expected lowercase SHA256, got %s", x64.SHA256)
+	}
+	expectedBins := Bins{{Path: "tool.exe"}, {Path: "tool.exe", Alias: "tl", Args: []string{"--quiet"}}}
+	if !reflect.DeepEqual(x64.Bin, expectedBins) {
+		t.Errorf("Expected top-level bins %v, got %v", expectedBins, x64.Bin)
+	}
+	if len(x64.Shortcuts) != 1 || x64.Shortcuts[0] != (Shortcut{Target: `gui\tool-gui.exe`, Name: "Tool"}) {
+		t.Errorf("Unexpected shortcuts: %v", x64.Shortcuts)
+	}
+
+	x86 := packages[1]
+	if x86.Arch != "386" || x86.URL != "https://example.com/tool-1.2.0-x86.exe" || x86.Filename != "tool.exe" {
+		t.Errorf("Expected renamed 32bit download, got %+v", x86)
+	}
+	if x86.SHA256 != "" {
+		t.Errorf("Expected no SHA256 for a sha512 hash, got %s", x86.SHA256)
+	}
+	if len(x86.Bin) != 1 || x86.Bin[0].Name() != "tool" {
+		t.Errorf("Expected architecture bin override, got %v", x86.Bin)
+	}
+}
+
+func TestManifestWithoutArchitecture(t *testing.T) {
+	var manifest Manifest
+	data := `{"version": "1.0", "url": "https://example.com/a.zip", "hash": "` + strings.Repeat("c", 64) + `", "bin": "a.exe"}`
+	if err := json.Unmarshal([]byte(data), &manifest); err != nil {
+		t.Fatalf("Unmarshal() error = %v", err)
+	}
+
+	packages, err := manifest.Packages()
+	if err != nil {
+		t.Fatalf("Packages() error = %v", err)
+	}
+	if len(packages) != 1 || packages[0].Arch != "" || packages[0].Bin[0].Path != "a.exe" {
+		t.Errorf("Expected one package for any architecture, got %+v", packages)
+	}
+
+	manifest.URL = StringList{"https://example.com/a.zip", "https://example.com/b.zip"}
+	if _, err := manifest.Packages(); err == nil {
+		t.Error("Expected error for several downloads")
+	}
+}
+
+func TestManifestURL(t *testing.T) {
+	tests := []struct {
+		ref      string
+		expected string
+		wantErr  bool
+	}{
+		{"jq", "https://raw.githubusercontent.com/ScoopInstaller/Main/master/bucket/jq.json", false},
+		{"extras/vscode", "https://raw.githubusercontent.com/ScoopInstaller/Extras/master/bucket/vscode.json", false},
+		{"https://example.com/tool.json", "https://example.com/tool.json", false},
+		{`C:\manifests\tool.json`, `C:\manifests\tool.json`, false},
+		{"unknown/tool", "", true},
+	}
+
+	for _, tt := range tests {
+		got, err := ManifestURL(tt.ref)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("ManifestURL(%s) error = %v, wantErr %v", tt.ref, err, tt.wantErr)
+		}
+		if got != tt.expected {
+			t.Errorf("ManifestURL(%s): expected %s, got %s", tt.ref, tt.expected, got)
+		}
+	}
+}
+
+func TestLoad(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/tool.json" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.Write([]byte(manifestJSON))
+	}))
+	defer server.Close()
+
+	manifest, err := Load(server.URL + "/tool.json")
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if manifest.Version != "1.2.0" {
+		t.Errorf("Expected version 1.2.0, got %s", manifest.Version)
+	}
+
+	if _, err := Load(server.URL + "/missing.json"); err == nil {
+		t.Error("Expected error for missing manifest")
+	}
+
+	path := filepath.Join(t.TempDir(), "tool.json")
+	if err := os.WriteFile(path, []byte(`{"url": "https://example.com/a.zip"}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Load(path); err == nil {
+		t.Error("Expected error for manifest without version")
+	}
+}
@@ -0,0 +1,189 @@
+package semver
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// Constraint is a set of version ranges, e.g. "^1.4" or ">=2, <3 || 4.x"
+type Constraint struct {
+	raw    string
+	ranges [][]comparator // OR of ANDed comparators
+}
+
+// comparator is a single "op version" condition
+type comparator struct {
+	op      string
+	version *Version
+	bound   bool // Derived upper bound (e.g. from ^ or ~) rather than written by the user
+}
+
+// operatorSpace matches whitespace between an operator and its version (">= 2")
+var operatorSpace = regexp.MustCompile(`([<>=!~^]+)\s+`)
+
+// IsConstraint reports whether a --version value is a range rather than an exact tag
+func IsConstraint(s string) bool {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return false
+	}
+	if strings.ContainsAny(s[:1], "<>=!~^") {
+		return true
+	}
+	return strings.Contains(s, ",") || strings.Contains(s, "||") || strings.Contains(s, "*") ||
+		strings.HasSuffix(s, ".x") || strings.Contains(s, ".x.") || strings.Contains(s, " ")
+}
+
+// ParseConstraint parses a constraint expression
+func ParseConstraint(s string) (*Constraint, error) {
+	c := &Constraint{raw: s}
+	normalized := operatorSpace.ReplaceAllString(strings.TrimSpace(s), "$1")
+
+	for _, alternative := range strings.Split(normalized, "||") {
+		var and []comparator
+		for _, term := range strings.FieldsFunc(alternative, func(r rune) bool { return r == ',' || r == ' ' }) {
+			comparators, err := parseTerm(term)
+			if err != nil {
+				return nil, fmt.Errorf("invalid version constraint %q: %w", s, err)
+			}
+			and = append(and, comparators...)
+		}
+		if len(and) == 0 {
+			return nil, fmt.Errorf("invalid version constraint %q: empty range", s)
+		}
+		c.ranges = append(c.ranges, and)
+	}
+
+	return c, nil
+}
+
+// String returns the constraint as written
+func (c *Constraint) String() string {
+	return c.raw
+}
+
+// Check reports whether a version satisfies the constraint
+func (c *Constraint) Check(v *Version) bool {
+	for _, and := range c.ranges {
+		matched := true
+		for _, cmp := range and {
+			if !cmp.check(v) {
+				matched = false
+				break
+			}
+		}
+		if matched {
+			return true
+		}
+	}
+	return false
+}
+
+// AllowsPrerelease reports whether the constraint explicitly names a pre-release.
+// Pre-releases only match such constraints unless they are requested otherwise.
+func (c *Constraint) AllowsPrerelease() bool {
+	for _, and := range c.ranges {
+		for _, cmp := range and {
+			if !cmp.bound && cmp.version.Prerelease != "" {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// check evaluates a single comparator
+func (cmp comparator) check(v *Version) bool {
+	c := v.Compare(cmp.version)
+	switch cmp.op {
+	case "=":
+		return c == 0
+	case "!=":
+		return c != 0
+	case ">":
+		return c > 0
+	case ">=":
+		return c >= 0
+	case "<":
+		return c < 0
+	case "<=":
+		return c <= 0
+	}
+	return false
+}
+
+// parseTerm expands one term (e.g. "^1.4", "~2", "1.x", ">=3") into comparators
+func parseTerm(term string) ([]comparator, error) {
+	rest := strings.TrimLeft(term, "<>=!~^")
+	op := term[:len(term)-len(rest)]
+	if op == "==" {
+		op = "="
+	}
+
+	// Wildcards: "*", "1.x", "1.4.*" keep the components before the wildcard
+	parts := strings.Split(strings.TrimPrefix(rest, "v"), ".")
+	for i, part := range parts {
+		if part != "x" && part != "X" && part != "*" {
+			continue
+		}
+		if i == 0 {
+			if op != "" && op != "=" {
+				return nil, fmt.Errorf("wildcard cannot follow %s", op)
+			}
+			return []comparator{{op: ">=", version: &Version{}}}, nil
+		}
+		v, err := Parse(strings.Join(parts[:i], "."))
+		if err != nil {
+			return nil, err
+		}
+		if op == "" || op == "=" {
+			return partialRange(v, i), nil
+		}
+		return []comparator{{op: op, version: v}}, nil
+	}
+
+	v, err := Parse(rest)
+	if err != nil {
+		return nil, err
+	}
+	// Number of components written, e.g. 2 for "1.4" or "1.4-rc.1"
+	given := len(strings.Split(strings.SplitN(strings.TrimPrefix(rest, "v"), "-", 2)[0], "."))
+
+	switch op {
+	case "", "=":
+		if given < 3 {
+			return partialRange(v, given), nil
+		}
+		return []comparator{{op: "=", version: v}}, nil
+	case "!=", ">", ">=", "<", "<=":
+		return []comparator{{op: op, version: v}}, nil
+	case "^":
+		return []comparator{{op: ">=", version: v}, {op: "<", version: caretUpper(v, given), bound: true}}, nil
+	case "~":
+		return partialRange(v, min(given, 2)), nil
+	}
+	return nil, fmt.Errorf("unknown operator: %s", op)
+}
+
+// partialRange matches every version sharing the first n components of v ("1.4" -> >=1.4.0 <1.5.0)
+func partialRange(v *Version, n int) []comparator {
+	upper := &Version{Major: v.Major + 1, Prerelease: "0"}
+	if n >= 2 {
+		upper = &Version{Major: v.Major, Minor: v.Minor + 1, Prerelease: "0"}
+	}
+	return []comparator{{op: ">=", version: v}, {op: "<", version: upper, bound: true}}
+}
+
+// caretUpper returns the exclusive upper bound of ^v: the next version changing the
+// left-most non-zero component (^1.4 -> 2.0.0, ^0.4 -> 0.5.0, ^0.0.3 -> 0.0.4)
+func caretUpper(v *Version, given int) *Version {
+	switch {
+	case v.Major > 0 || given == 1:
+		return &Version{Major: v.Major + 1, Prerelease: "0"}
+	case v.Minor > 0 || given == 2:
+		return &Version{Minor: v.Minor + 1, Prerelease: "0"}
+	default:
+		return &Version{Patch: v.Patch + 1, Prerelease: "0"}
+	}
+}
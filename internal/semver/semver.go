@@ -0,0 +1,128 @@
+package semver
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// Version is a parsed semantic version
+type Version struct {
+	Major      int
+	Minor      int
+	Patch      int
+	Prerelease string // e.g. "rc.1" (without the leading dash)
+	Original   string // The tag the version was parsed from
+}
+
+// Parse parses a version or release tag such as "v1.2.3", "1.4", "tool-2.0.0-rc.1".
+// Missing minor/patch components default to zero and build metadata is ignored.
+func Parse(tag string) (*Version, error) {
+	s := strings.TrimSpace(tag)
+
+	// Skip tag prefixes like "v" or "tool-" up to the first digit
+	start := strings.IndexFunc(s, unicode.IsDigit)
+	if start < 0 {
+		return nil, fmt.Errorf("invalid version: %s", tag)
+	}
+	s = s[start:]
+
+	// Drop build metadata
+	if i := strings.Index(s, "+"); i >= 0 {
+		s = s[:i]
+	}
+
+	v := &Version{Original: tag}
+	if i := strings.Index(s, "-"); i >= 0 {
+		v.Prerelease = s[i+1:]
+		s = s[:i]
+	}
+
+	parts := strings.Split(s, ".")
+	if len(parts) > 3 {
+		return nil, fmt.Errorf("invalid version: %s", tag)
+	}
+
+	numbers := []*int{&v.Major, &v.Minor, &v.Patch}
+	for i, part := range parts {
+		n, err := strconv.Atoi(part)
+		if err != nil || n < 0 {
+			return nil, fmt.Errorf("invalid version: %s", tag)
+		}
+		*numbers[i] = n
+	}
+
+	return v, nil
+}
+
+// String formats the version without prefix
+func (v *Version) String() string {
+	s := fmt.Sprintf("%d.%d.%d", v.Major, v.Minor, v.Patch)
+	if v.Prerelease != "" {
+		s += "-" + v.Prerelease
+	}
+	return s
+}
+
+// Compare returns -1, 0 or 1 if v is lower than, equal to or greater than o
+func (v *Version) Compare(o *Version) int {
+	if c := compareInt(v.Major, o.Major); c != 0 {
+		return c
+	}
+	if c := compareInt(v.Minor, o.Minor); c != 0 {
+		return c
+	}
+	if c := compareInt(v.Patch, o.Patch); c != 0 {
+		return c
+	}
+	return comparePrerelease(v.Prerelease, o.Prerelease)
+}
+
+// compareInt compares two integers
+func compareInt(a, b int) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	default:
+		return 0
+	}
+}
+
+// comparePrerelease compares pre-release identifiers following semver precedence rules
+func comparePrerelease(a, b string) int {
+	// A release has higher precedence than its pre-releases
+	switch {
+	case a == b:
+		return 0
+	case a == "":
+		return 1
+	case b == "":
+		return -1
+	}
+
+	aParts := strings.Split(a, ".")
+	bParts := strings.Split(b, ".")
+	for i := 0; i < len(aParts) && i < len(bParts); i++ {
+		aNum, aErr := strconv.Atoi(aParts[i])
+		bNum, bErr := strconv.Atoi(bParts[i])
+
+		var c int
+		switch {
+		case aErr == nil && bErr == nil:
+			c = compareInt(aNum, bNum)
+		case aErr == nil:
+			c = -1 // Numeric identifiers sort before alphanumeric ones
+		case bErr == nil:
+			c = 1
+		default:
+			c = strings.Compare(aParts[i], bParts[i])
+		}
+		if c != 0 {
+			return c
+		}
+	}
+	return compareInt(len(aParts), len(bParts))
+}
@@ -0,0 +1,164 @@
+package semver
+
+import "testing"
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		tag     string
+		want    string
+		wantErr bool
+	}{
+		{"v1.2.3", "1.2.3", false},
+		{"1.2.3", "1.2.3", false},
+		{"v1.4", "1.4.0", false},
+		{"2", "2.0.0", false},
+		{"v2.0.0-rc.1", "2.0.0-rc.1", false},
+		{"v1.0.0+build.5", "1.0.0", false},
+		{"jq-1.7.1", "1.7.1", false},
+		{"nightly", "", true},
+		{"v1.2.3.4", "", true},
+		{"v1.a.3", "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.tag, func(t *testing.T) {
+			v, err := Parse(tt.tag)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Parse(%s) error = %v, wantErr %v", tt.tag, err, tt.wantErr)
+			}
+			if !tt.wantErr && v.String() != tt.want {
+				t.Errorf("Parse(%s) = %s, want %s", tt.tag, v, tt.want)
+			}
+			if !tt.wantErr && v.Original != tt.tag {
+				t.Errorf("Expected Original %s, got %s", tt.tag, v.Original)
+			}
+		})
+	}
+}
+
+func TestCompare(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want int
+	}{
+		{"1.0.0", "1.0.0", 0},
+		{"1.0.0", "2.0.0", -1},
+		{"1.10.0", "1.9.0", 1},
+		{"1.0.1", "1.0.0", 1},
+		{"1.0.0-rc.1", "1.0.0", -1},
+		{"1.0.0-alpha", "1.0.0-beta", -1},
+		{"1.0.0-rc.2", "1.0.0-rc.10", -1},
+		{"1.0.0-1", "1.0.0-alpha", -1},
+		{"1.0.0-alpha", "1.0.0-alpha.1", -1},
+		{"v1.2.3", "1.2.3", 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.a+"_"+tt.b, func(t *testing.T) {
+			a, _ := Parse(tt.a)
+			b, _ := Parse(tt.b)
+			if got := a.Compare(b); got != tt.want {
+				t.Errorf("Compare(%s, %s) = %d, want %d", tt.a, tt.b, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestIsConstraint(t *testing.T) {
+	tests := []struct {
+		input string
+		want  bool
+	}{
+		{"v1.2.3", false},
+		{"latest", false},
+		{"release-2024", false},
+		{"^1.4", true},
+		{"~1.4", true},
+		{">=2,<3", true},
+		{">=2 <3", true},
+		{"1.x", true},
+		{"1.4.*", true},
+		{"1 || 2", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			if got := IsConstraint(tt.input); got != tt.want {
+				t.Errorf("IsConstraint(%s) = %v, want %v", tt.input, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestConstraintCheck(t *testing.T) {
+	tests := []struct {
+		constraint string
+		version    string
+		want       bool
+	}{
+		{"^1.4", "1.4.0", true},
+		{"^1.4", "1.9.3", true},
+		{"^1.4", "2.0.0", false},
+		{"^1.4", "1.3.9", false},
+		{"^1.4", "2.0.0-rc.1", false},
+		{"^0.4", "0.4.5", true},
+		{"^0.4", "0.5.0", false},
+		{"^0.0.3", "0.0.4", false},
+		{"~1.4", "1.4.9", true},
+		{"~1.4", "1.5.0", false},
+		{"~1", "1.9.0", true},
+		{">=2,<3", "2.5.0", true},
+		{">=2,<3", "3.0.0", false},
+		{">= 2, < 3", "2.0.0", true},
+		{">=2 <3", "1.9.9", false},
+		{"1.x", "1.99.0", true},
+		{"1.x", "2.0.0", false},
+		{"1.4.*", "1.4.7", true},
+		{"1.4.*", "1.5.0", false},
+		{"*", "0.0.1", true},
+		{"1.4", "1.4.2", true},
+		{"=1.4.2", "1.4.2", true},
+		{"=1.4.2", "1.4.3", false},
+		{"!=1.4.2", "1.4.3", true},
+		{"<1 || >=3", "3.1.0", true},
+		{"<1 || >=3", "2.0.0", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.constraint+"_"+tt.version, func(t *testing.T) {
+			c, err := ParseConstraint(tt.constraint)
+			if err != nil {
+				t.Fatalf("ParseConstraint(%s) error = %v", tt.constraint, err)
+			}
+			v, err := Parse(tt.version)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := c.Check(v); got != tt.want {
+				t.Errorf("%s.Check(%s) = %v, want %v", tt.constraint, tt.version, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseConstraintErrors(t *testing.T) {
+	for _, input := range []string{">=", "^abc", "1 ||", ">=1, <x.y"} {
+		t.Run(input, func(t *testing.T) {
+			if _, err := ParseConstraint(input); err == nil {
+				t.Errorf("Expected error for %q", input)
+			}
+		})
+	}
+}
+
+func TestAllowsPrerelease(t *testing.T) {
+	c, _ := ParseConstraint(">=2.0.0-rc.1")
+	if !c.AllowsPrerelease() {
+		t.Error("Constraint naming a pre-release should allow pre-releases")
+	}
+
+	c, _ = ParseConstraint("^2")
+	if c.AllowsPrerelease() {
+		t.Error("Plain constraint should not allow pre-releases")
+	}
+}
@@ -0,0 +1,489 @@
+package serve
+
+import (
+	"context"
+	"crypto/subtle"
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime"
+	"net"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pyhub-kr/pyhub-installer/internal/state"
+)
+
+// maxRequestBody limits the size of request bodies
+const maxRequestBody = 1 << 20
+
+// Command runs one installer command line (e.g. ["install", "--", "rg"]), writing its
+// output to out, and returns its exit code
+type Command func(ctx context.Context, args []string, out io.Writer) int
+
+// Server exposes installs, upgrades and the installed tools over a local REST API.
+// Installs run as background jobs whose output can be streamed.
+type Server struct {
+	Token    string                          // Bearer token required by every request, if set
+	Local    bool                            // Only accept requests addressed to a loopback host
+	Version  string                          // Installer version reported by /v1/status
+	Receipts func() ([]state.Receipt, error) // Installed tools
+
+	ctx  context.Context
+	run  Command
+	mu   sync.Mutex
+	jobs map[string]*Job
+	next int
+}
+
+// NewServer creates a server running job commands with run. Jobs are canceled when
+// ctx is done.
+func NewServer(ctx context.Context, run Command, receipts func() ([]state.Receipt, error)) *Server {
+	return &Server{
+		Receipts: receipts,
+		ctx:      ctx,
+		run:      run,
+		jobs:     make(map[string]*Job),
+	}
+}
+
+// Handler returns the HTTP handler of the API
+func (s *Server) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /v1/status", s.handleStatus)
+	mux.HandleFunc("GET /v1/tools", s.handleTools)
+	mux.HandleFunc("POST /v1/install", s.handleInstall)
+	mux.HandleFunc("POST /v1/upgrade", s.handleUpgrade)
+	mux.HandleFunc("GET /v1/jobs", s.handleJobs)
+	mux.HandleFunc("GET /v1/jobs/{id}", s.handleJob)
+	mux.HandleFunc("GET /v1/jobs/{id}/log", s.handleJobLog)
+	return s.authorize(mux)
+}
+
+// authorize rejects requests a web page could have sent and those without the configured
+// bearer token. Browsers add Origin to cross-site requests and cannot send JSON to another
+// site without its consent; a local server also checks Host, as DNS rebinding points
+// another name at the loopback address.
+func (s *Server) authorize(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if s.Local && !IsLoopback(hostOnly(r.Host)) {
+			writeError(w, http.StatusForbidden, fmt.Errorf("host %q is not served", r.Host))
+			return
+		}
+		if r.Header.Get("Origin") != "" {
+			writeError(w, http.StatusForbidden, fmt.Errorf("requests from web pages are not allowed"))
+			return
+		}
+		if r.Method == http.MethodPost {
+			if mediaType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type")); mediaType != "application/json" {
+				writeError(w, http.StatusUnsupportedMediaType, fmt.Errorf("request body must be application/json"))
+				return
+			}
+		}
+		if s.Token != "" {
+			token, _ := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+			if subtle.ConstantTimeCompare([]byte(token), []byte(s.Token)) != 1 {
+				writeError(w, http.StatusUnauthorized, fmt.Errorf("missing or invalid bearer token"))
+				return
+			}
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// IsLoopback reports whether a host name or IP only refers to this machine
+func IsLoopback(host string) bool {
+	if host == "localhost" {
+		return true
+	}
+	ip := net.ParseIP(host)
+	return ip != nil && ip.IsLoopback()
+}
+
+// hostOnly strips the port from a Host header
+func hostOnly(hostport string) string {
+	if host, _, err := net.SplitHostPort(hostport); err == nil {
+		return host
+	}
+	return strings.Trim(hostport, "[]")
+}
+
+// InstallRequest is the body of POST /v1/install
+type InstallRequest struct {
+	Targets  []string `json:"targets"`  // Sources or aliases, optionally with @version
+	Version  string   `json:"version"`  // Version for targets without @version
+	Output   string   `json:"output"`   // Install directory
+	Platform string   `json:"platform"` // Target platform, e.g. linux-amd64
+	Asset    string   `json:"asset"`    // Asset name pattern
+}
+
+// args returns the install command line of the request
+func (req InstallRequest) args() []string {
+	args := []string{"install"}
+	for _, flag := range []struct{ name, value string }{
+		{"--version", req.Version},
+		{"--output", req.Output},
+		{"--platform", req.Platform},
+		{"--asset", req.Asset},
+	} {
+		if flag.value != "" {
+			args = append(args, flag.name, flag.value)
+		}
+	}
+	// Targets follow "--" so they are never taken for flags
+	return append(append(args, "--"), req.Targets...)
+}
+
+// UpgradeRequest is the body of POST /v1/upgrade
+type UpgradeRequest struct {
+	Tools []string `json:"tools"` // Installed tool names; empty upgrades every tool
+}
+
+// StatusResponse is the body of GET /v1/status
+type StatusResponse struct {
+	Version string `json:"version"`
+	Running int    `json:"running"` // Number of running jobs
+}
+
+func (s *Server) handleStatus(w http.ResponseWriter, r *http.Request) {
+	running := 0
+	for _, job := range s.listJobs() {
+		if job.Status == StatusRunning {
+			running++
+		}
+	}
+	writeJSON(w, http.StatusOK, StatusResponse{Version: s.Version, Running: running})
+}
+
+func (s *Server) handleTools(w http.ResponseWriter, r *http.Request) {
+	receipts, err := s.Receipts()
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+	if receipts == nil {
+		receipts = []state.Receipt{}
+	}
+	writeJSON(w, http.StatusOK, receipts)
+}
+
+func (s *Server) handleInstall(w http.ResponseWriter, r *http.Request) {
+	var req InstallRequest
+	if err := decodeBody(w, r, &req); err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+	if len(req.Targets) == 0 {
+		writeError(w, http.StatusBadRequest, fmt.Errorf("targets is required"))
+		return
+	}
+
+	job := s.start("install", [][]string{req.args()})
+	writeJSON(w, http.StatusAccepted, job.Info())
+}
+
+func (s *Server) handleUpgrade(w http.ResponseWriter, r *http.Request) {
+	var req UpgradeRequest
+	if err := decodeBody(w, r, &req); err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	receipts, err := s.Receipts()
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+	commands, err := upgradeCommands(receipts, req.Tools)
+	if err != nil {
+		writeError(w, http.StatusNotFound, err)
+		return
+	}
+	if len(commands) == 0 {
+		writeError(w, http.StatusBadRequest, fmt.Errorf("no installed tools to upgrade"))
+		return
+	}
+
+	job := s.start("upgrade", commands)
+	writeJSON(w, http.StatusAccepted, job.Info())
+}
+
+// upgradeCommands returns install commands reinstalling the latest release of the
+// named tools (all tools if names is empty), one command per install directory
+func upgradeCommands(receipts []state.Receipt, names []string) ([][]string, error) {
+	selected := receipts
+	if len(names) > 0 {
+		selected = nil
+		for _, name := range names {
+			found := false
+			for _, receipt := range receipts {
+				if receipt.Name == name {
+					selected = append(selected, receipt)
+					found = true
+					break
+				}
+			}
+			if !found {
+				return nil, fmt.Errorf("%s is not installed", name)
+			}
+		}
+	}
+
+	sources := make(map[string][]string)
+	for _, receipt := range selected {
+		sources[receipt.InstallPath] = append(sources[receipt.InstallPath], receipt.Source)
+	}
+	dirs := make([]string, 0, len(sources))
+	for dir := range sources {
+		dirs = append(dirs, dir)
+	}
+	sort.Strings(dirs)
+
+	commands := make([][]string, 0, len(dirs))
+	for _, dir := range dirs {
+		commands = append(commands, append([]string{"install", "--output", dir, "--"}, sources[dir]...))
+	}
+	return commands, nil
+}
+
+func (s *Server) handleJobs(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, s.listJobs())
+}
+
+func (s *Server) handleJob(w http.ResponseWriter, r *http.Request) {
+	job := s.job(r.PathValue("id"))
+	if job == nil {
+		writeError(w, http.StatusNotFound, fmt.Errorf("job %s not found", r.PathValue("id")))
+		return
+	}
+	writeJSON(w, http.StatusOK, job.Info())
+}
+
+// handleJobLog streams the output of a job as plain text until the job finishes or
+// the client disconnects
+func (s *Server) handleJobLog(w http.ResponseWriter, r *http.Request) {
+	job := s.job(r.PathValue("id"))
+	if job == nil {
+		writeError(w, http.StatusNotFound, fmt.Errorf("job %s not found", r.PathValue("id")))
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	flusher, _ := w.(http.Flusher)
+
+	offset := 0
+	for {
+		data, changed, done := job.log.since(offset)
+		if len(data) > 0 {
+			if _, err := w.Write(data); err != nil {
+				return
+			}
+			if flusher != nil {
+				flusher.Flush()
+			}
+			offset += len(data)
+		}
+		if done {
+			return
+		}
+		select {
+		case <-changed:
+		case <-r.Context().Done():
+			return
+		}
+	}
+}
+
+// start creates a job and runs its commands in the background
+func (s *Server) start(kind string, commands [][]string) *Job {
+	s.mu.Lock()
+	s.next++
+	job := newJob(strconv.Itoa(s.next), kind, commands)
+	s.jobs[job.ID] = job
+	s.mu.Unlock()
+
+	go job.run(s.ctx, s.run)
+	return job
+}
+
+// job returns a job by ID, or nil
+func (s *Server) job(id string) *Job {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.jobs[id]
+}
+
+// listJobs returns all jobs, oldest first
+func (s *Server) listJobs() []JobInfo {
+	s.mu.Lock()
+	jobs := make([]*Job, 0, len(s.jobs))
+	for _, job := range s.jobs {
+		jobs = append(jobs, job)
+	}
+	s.mu.Unlock()
+
+	infos := make([]JobInfo, 0, len(jobs))
+	for _, job := range jobs {
+		infos = append(infos, job.Info())
+	}
+	sort.Slice(infos, func(i, j int) bool {
+		a, _ := strconv.Atoi(infos[i].ID)
+		b, _ := strconv.Atoi(infos[j].ID)
+		return a < b
+	})
+	return infos
+}
+
+// decodeBody parses a JSON request body; an empty body leaves v unchanged
+func decodeBody(w http.ResponseWriter, r *http.Request, v interface{}) error {
+	decoder := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxRequestBody))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(v); err != nil && err != io.EOF {
+		return fmt.Errorf("invalid request body: %w", err)
+	}
+	return nil
+}
+
+// writeJSON writes v as a JSON response
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
+
+// writeError writes an error response
+func writeError(w http.ResponseWriter, status int, err error) {
+	writeJSON(w, status, map[string]string{"error": err.Error()})
+}
+
+// Job statuses
+const (
+	StatusRunning   = "running"
+	StatusSucceeded = "succeeded"
+	StatusFailed    = "failed"
+)
+
+// Job is an install or upgrade running in the background
+type Job struct {
+	ID       string
+	Kind     string
+	Commands [][]string
+
+	log       *jobLog
+	mu        sync.Mutex
+	status    string
+	exitCode  int
+	createdAt time.Time
+	endedAt   time.Time
+}
+
+// JobInfo is the JSON representation of a job
+type JobInfo struct {
+	ID         string     `json:"id"`
+	Kind       string     `json:"kind"` // install or upgrade
+	Commands   [][]string `json:"commands"`
+	Status     string     `json:"status"`
+	ExitCode   int        `json:"exit_code"`
+	CreatedAt  time.Time  `json:"created_at"`
+	FinishedAt *time.Time `json:"finished_at,omitempty"`
+}
+
+// newJob creates a running job
+func newJob(id, kind string, commands [][]string) *Job {
+	return &Job{
+		ID:        id,
+		Kind:      kind,
+		Commands:  commands,
+		log:       newJobLog(),
+		status:    StatusRunning,
+		createdAt: time.Now(),
+	}
+}
+
+// Info returns a snapshot of the job
+func (j *Job) Info() JobInfo {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	info := JobInfo{
+		ID:        j.ID,
+		Kind:      j.Kind,
+		Commands:  j.Commands,
+		Status:    j.status,
+		ExitCode:  j.exitCode,
+		CreatedAt: j.createdAt,
+	}
+	if !j.endedAt.IsZero() {
+		endedAt := j.endedAt
+		info.FinishedAt = &endedAt
+	}
+	return info
+}
+
+// run runs the job's commands in order. All commands run; the job's exit code is
+// that of the first failing command.
+func (j *Job) run(ctx context.Context, command Command) {
+	exitCode := 0
+	for _, args := range j.Commands {
+		fmt.Fprintf(j.log, "$ pyhub-installer %s\n", strings.Join(args, " "))
+		if code := command(ctx, args, j.log); code != 0 && exitCode == 0 {
+			exitCode = code
+		}
+	}
+
+	j.mu.Lock()
+	j.exitCode = exitCode
+	j.status = StatusSucceeded
+	if exitCode != 0 {
+		j.status = StatusFailed
+	}
+	j.endedAt = time.Now()
+	j.mu.Unlock()
+
+	j.log.close()
+}
+
+// jobLog collects the output of a job and wakes up readers following it
+type jobLog struct {
+	mu      sync.Mutex
+	data    []byte
+	done    bool
+	changed chan struct{} // Closed and replaced on every write
+}
+
+// newJobLog creates an empty log
+func newJobLog() *jobLog {
+	return &jobLog{changed: make(chan struct{})}
+}
+
+// Write appends output to the log
+func (l *jobLog) Write(p []byte) (int, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.data = append(l.data, p...)
+	close(l.changed)
+	l.changed = make(chan struct{})
+	return len(p), nil
+}
+
+// close marks the log complete
+func (l *jobLog) close() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.done = true
+	close(l.changed)
+	l.changed = make(chan struct{})
+}
+
+// since returns the output after offset, a channel closed on the next change, and
+// whether the log is complete
+func (l *jobLog) since(offset int) ([]byte, <-chan struct{}, bool) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return append([]byte(nil), l.data[offset:]...), l.changed, l.done
+}
@@ -0,0 +1,269 @@
+package serve
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/pyhub-kr/pyhub-installer/internal/state"
+)
+
+// fakeCommands records the command lines run and fails those containing "broken"
+type fakeCommands struct {
+	mu    sync.Mutex
+	calls [][]string
+}
+
+func (f *fakeCommands) run(ctx context.Context, args []string, out io.Writer) int {
+	f.mu.Lock()
+	f.calls = append(f.calls, args)
+	f.mu.Unlock()
+
+	fmt.Fprintf(out, "ran %s\n", strings.Join(args, " "))
+	for _, arg := range args {
+		if arg == "broken" {
+			return 6
+		}
+	}
+	return 0
+}
+
+func newTestServer(t *testing.T, receipts []state.Receipt) (*httptest.Server, *fakeCommands) {
+	t.Helper()
+	commands := &fakeCommands{}
+	s := NewServer(context.Background(), commands.run, func() ([]state.Receipt, error) { return receipts, nil })
+	s.Token = "secret"
+	s.Local = true
+	server := httptest.NewServer(s.Handler())
+	t.Cleanup(server.Close)
+	return server, commands
+}
+
+func request(t *testing.T, method, url, body string) *http.Response {
+	t.Helper()
+	req, err := http.NewRequest(method, url, strings.NewReader(body))
+	if err != nil {
+		t.Fatal(err)
+	}
+	req.Header.Set("Authorization", "Bearer secret")
+	if method == "POST" {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { resp.Body.Close() })
+	return resp
+}
+
+func TestInstallJob(t *testing.T) {
+	server, commands := newTestServer(t, nil)
+
+	resp := request(t, "POST", server.URL+"/v1/install", `{"targets": ["rg", "broken"], "output": "/opt/bin"}`)
+	if resp.StatusCode != http.StatusAccepted {
+		t.Fatalf("Expected 202, got %d", resp.StatusCode)
+	}
+	var job JobInfo
+	if err := json.NewDecoder(resp.Body).Decode(&job); err != nil {
+		t.Fatal(err)
+	}
+
+	// The log streams until the job finishes
+	logResp := request(t, "GET", server.URL+"/v1/jobs/"+job.ID+"/log", "")
+	output, err := io.ReadAll(logResp.Body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "$ pyhub-installer install --output /opt/bin -- rg broken\nran install --output /opt/bin -- rg broken\n"
+	if string(output) != want {
+		t.Errorf("Unexpected log %q", output)
+	}
+
+	resp = request(t, "GET", server.URL+"/v1/jobs/"+job.ID, "")
+	if err := json.NewDecoder(resp.Body).Decode(&job); err != nil {
+		t.Fatal(err)
+	}
+	if job.Status != StatusFailed || job.ExitCode != 6 || job.FinishedAt == nil {
+		t.Errorf("Expected a failed job with exit code 6, got %+v", job)
+	}
+	if len(commands.calls) != 1 {
+		t.Errorf("Expected one command, got %v", commands.calls)
+	}
+}
+
+func TestUpgradeJob(t *testing.T) {
+	server, commands := newTestServer(t, []state.Receipt{
+		{Name: "rg", Source: "github:BurntSushi/ripgrep", InstallPath: "/usr/local/bin"},
+		{Name: "fd", Source: "github:sharkdp/fd", InstallPath: "/home/user/bin"},
+		{Name: "jq", Source: "github:jqlang/jq", InstallPath: "/usr/local/bin"},
+	})
+
+	if resp := request(t, "POST", server.URL+"/v1/upgrade", `{"tools": ["missing"]}`); resp.StatusCode != http.StatusNotFound {
+		t.Errorf("Expected 404 for a tool that is not installed, got %d", resp.StatusCode)
+	}
+
+	resp := request(t, "POST", server.URL+"/v1/upgrade", "")
+	var job JobInfo
+	if err := json.NewDecoder(resp.Body).Decode(&job); err != nil {
+		t.Fatal(err)
+	}
+	io.ReadAll(request(t, "GET", server.URL+"/v1/jobs/"+job.ID+"/log", "").Body)
+
+	want := [][]string{
+		{"install", "--output", "/home/user/bin", "--", "github:sharkdp/fd"},
+		{"install", "--output", "/usr/local/bin", "--", "github:BurntSushi/ripgrep", "github:jqlang/jq"},
+	}
+	if fmt.Sprint(commands.calls) != fmt.Sprint(want) {
+		t.Errorf("Expected commands %v, got %v", want, commands.calls)
+	}
+}
+
+func TestToolsAndStatus(t *testing.T) {
+	server, _ := newTestServer(t, []state.Receipt{{Name: "rg", Version: "14.1.0"}})
+
+	var receipts []state.Receipt
+	if err := json.NewDecoder(request(t, "GET", server.URL+"/v1/tools", "").Body).Decode(&receipts); err != nil {
+		t.Fatal(err)
+	}
+	if len(receipts) != 1 || receipts[0].Name != "rg" {
+		t.Errorf("Unexpected tools %+v", receipts)
+	}
+
+	var status StatusResponse
+	if err := json.NewDecoder(request(t, "GET", server.URL+"/v1/status", "").Body).Decode(&status); err != nil {
+		t.Fatal(err)
+	}
+	if status.Running != 0 {
+		t.Errorf("Expected no running jobs, got %d", status.Running)
+	}
+}
+
+func TestRequestValidation(t *testing.T) {
+	server, _ := newTestServer(t, nil)
+
+	resp, err := http.Get(server.URL + "/v1/tools")
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusUnauthorized {
+		t.Errorf("Expected 401 without a token, got %d", resp.StatusCode)
+	}
+
+	tests := map[string]string{
+		"no targets":    `{"targets": []}`,
+		"unknown field": `{"targets": ["rg"], "force": true}`,
+		"bad json":      `{`,
+	}
+	for name, body := range tests {
+		if resp := request(t, "POST", server.URL+"/v1/install", body); resp.StatusCode != http.StatusBadRequest {
+			t.Errorf("%s: expected 400, got %d", name, resp.StatusCode)
+		}
+	}
+
+	if resp := request(t, "GET", server.URL+"/v1/jobs/42", ""); resp.StatusCode != http.StatusNotFound {
+		t.Errorf("Expected 404 for an unknown job, got %d", resp.StatusCode)
+	}
+}
+
+func TestRejectsBrowserRequests(t *testing.T) {
+	server, commands := newTestServer(t, nil)
+
+	tests := []struct {
+		name        string
+		host        string
+		origin      string
+		contentType string
+		want        int
+	}{
+		{"rebound host", "attacker.example:7878", "", "application/json", http.StatusForbidden},
+		{"origin", "", "https://attacker.example", "application/json", http.StatusForbidden},
+		{"null origin", "", "null", "application/json", http.StatusForbidden},
+		{"text body", "", "", "text/plain", http.StatusUnsupportedMediaType},
+		{"form body", "", "", "application/x-www-form-urlencoded", http.StatusUnsupportedMediaType},
+		{"no content type", "", "", "", http.StatusUnsupportedMediaType},
+		{"localhost", "localhost:7878", "", "application/json; charset=utf-8", http.StatusAccepted},
+	}
+	for _, tt := range tests {
+		req, err := http.NewRequest("POST", server.URL+"/v1/install", strings.NewReader(`{"targets": ["rg"]}`))
+		if err != nil {
+			t.Fatal(err)
+		}
+		req.Header.Set("Authorization", "Bearer secret")
+		if tt.host != "" {
+			req.Host = tt.host
+		}
+		if tt.origin != "" {
+			req.Header.Set("Origin", tt.origin)
+		}
+		if tt.contentType != "" {
+			req.Header.Set("Content-Type", tt.contentType)
+		}
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp.Body.Close()
+		if resp.StatusCode != tt.want {
+			t.Errorf("%s: expected %d, got %d", tt.name, tt.want, resp.StatusCode)
+		}
+	}
+	if len(commands.calls) > 1 {
+		t.Errorf("Expected only the localhost request to start a job, got %v", commands.calls)
+	}
+}
+
+func TestIsLoopback(t *testing.T) {
+	tests := map[string]bool{
+		"localhost":        true,
+		"127.0.0.1":        true,
+		"::1":              true,
+		"0.0.0.0":          false,
+		"":                 false,
+		"127.0.0.1.nip.io": false,
+		"attacker.example": false,
+	}
+	for host, want := range tests {
+		if got := IsLoopback(host); got != want {
+			t.Errorf("IsLoopback(%q) = %v, want %v", host, got, want)
+		}
+	}
+}
+
+func TestJobLogFollows(t *testing.T) {
+	log := newJobLog()
+	data, changed, done := log.since(0)
+	if len(data) != 0 || done {
+		t.Fatal("Expected an empty, open log")
+	}
+
+	go func() {
+		log.Write([]byte("line\n"))
+		log.close()
+	}()
+
+	var buf bytes.Buffer
+	offset := 0
+	for !done {
+		select {
+		case <-changed:
+		case <-time.After(5 * time.Second):
+			t.Fatal("Timed out waiting for the log")
+		}
+		data, changed, done = log.since(offset)
+		buf.Write(data)
+		offset += len(data)
+	}
+	if buf.String() != "line\n" {
+		t.Errorf("Unexpected log %q", buf.String())
+	}
+}
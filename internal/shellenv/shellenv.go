@@ -0,0 +1,65 @@
+package shellenv
+
+import (
+	"fmt"
+	"path/filepath"
+	"runtime"
+	"strings"
+)
+
+// Shells are the supported shells
+var Shells = []string{"bash", "zsh", "fish", "powershell"}
+
+// Detect returns the shell of the user: from SHELL, PowerShell on Windows, otherwise
+// bash, whose syntax other POSIX shells share
+func Detect(getenv func(string) string) string {
+	switch name := strings.TrimSuffix(filepath.Base(getenv("SHELL")), ".exe"); name {
+	case "zsh", "fish", "bash":
+		return name
+	case "pwsh", "powershell":
+		return "powershell"
+	}
+	if runtime.GOOS == "windows" {
+		return "powershell"
+	}
+	return "bash"
+}
+
+// Script returns shell code that adds dirs to the front of PATH, in order, skipping
+// those already in it, so evaluating it again from a profile changes nothing
+func Script(shell string, dirs []string) (string, error) {
+	var b strings.Builder
+	// Prepend in reverse so the first directory ends up first
+	for i := len(dirs) - 1; i >= 0; i-- {
+		dir := dirs[i]
+		switch shell {
+		case "bash", "zsh":
+			q := posixQuote(dir)
+			fmt.Fprintf(&b, "case \":$PATH:\" in *:%s:*) ;; *) export PATH=%s\":$PATH\" ;; esac\n", q, q)
+		case "fish":
+			q := fishQuote(dir)
+			fmt.Fprintf(&b, "contains -- %s $PATH; or set -gx PATH %s $PATH\n", q, q)
+		case "powershell":
+			q := psQuote(dir)
+			fmt.Fprintf(&b, "if (($env:PATH -split [IO.Path]::PathSeparator) -notcontains %s) { Set-Item -Path Env:PATH -Value (%s + [IO.Path]::PathSeparator + $env:PATH) }\n", q, q)
+		default:
+			return "", fmt.Errorf("unsupported shell %q (supported: %s)", shell, strings.Join(Shells, ", "))
+		}
+	}
+	return b.String(), nil
+}
+
+// posixQuote single-quotes a string for POSIX shells
+func posixQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
+
+// fishQuote single-quotes a string for fish, where \ and ' are escaped inside quotes
+func fishQuote(s string) string {
+	return "'" + strings.NewReplacer(`\`, `\\`, "'", `\'`).Replace(s) + "'"
+}
+
+// psQuote single-quotes a string for PowerShell
+func psQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", "''") + "'"
+}
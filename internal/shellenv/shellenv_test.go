@@ -0,0 +1,74 @@
+package shellenv
+
+import (
+	"os"
+	"os/exec"
+	"runtime"
+	"strings"
+	"testing"
+)
+
+func TestDetect(t *testing.T) {
+	tests := map[string]string{
+		"/bin/zsh":            "zsh",
+		"/usr/local/bin/fish": "fish",
+		"/bin/bash":           "bash",
+		"/usr/bin/pwsh":       "powershell",
+	}
+	for shell, want := range tests {
+		getenv := func(string) string { return shell }
+		if got := Detect(getenv); got != want {
+			t.Errorf("Detect(%s) = %s, want %s", shell, got, want)
+		}
+	}
+
+	want := "bash"
+	if runtime.GOOS == "windows" {
+		want = "powershell"
+	}
+	if got := Detect(func(string) string { return "" }); got != want {
+		t.Errorf("Detect() without SHELL = %s, want %s", got, want)
+	}
+}
+
+func TestScript(t *testing.T) {
+	dirs := []string{"/opt/it's bin", "/home/user/.local/bin"}
+	for _, shell := range Shells {
+		script, err := Script(shell, dirs)
+		if err != nil {
+			t.Fatalf("Script(%s) error = %v", shell, err)
+		}
+		if lines := strings.Split(strings.TrimSpace(script), "\n"); len(lines) != 2 {
+			t.Errorf("Script(%s) = %q, want a line per directory", shell, script)
+		}
+	}
+	if script, _ := Script("fish", dirs); !strings.Contains(script, `'/opt/it\'s bin'`) {
+		t.Errorf("Unexpected fish quoting: %s", script)
+	}
+	if script, _ := Script("powershell", []string{`C:\it's`}); !strings.Contains(script, `'C:\it''s'`) {
+		t.Errorf("Unexpected PowerShell quoting: %s", script)
+	}
+	if _, err := Script("tcsh", dirs); err == nil {
+		t.Error("Expected error for an unsupported shell")
+	}
+}
+
+func TestScriptRunsInSh(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("POSIX shell")
+	}
+	script, err := Script("bash", []string{"/opt/it's bin", "/usr/bin"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Evaluated twice, the directories are added once, the first one first
+	cmd := exec.Command("sh", "-c", script+script+`printf %s "$PATH"`)
+	cmd.Env = append(os.Environ(), "PATH=/usr/bin:/bin")
+	output, err := cmd.Output()
+	if err != nil {
+		t.Fatalf("sh: %v", err)
+	}
+	if got := string(output); got != "/opt/it's bin:/usr/bin:/bin" {
+		t.Errorf("PATH = %q", got)
+	}
+}
@@ -0,0 +1,60 @@
+package shim
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+)
+
+// Create writes a launcher named name into dir that runs target with args followed by
+// the caller's arguments: a .cmd script on Windows and a shell script elsewhere.
+// It returns the path of the launcher.
+func Create(dir, name, target string, args []string) (string, error) {
+	return create(runtime.GOOS, dir, name, target, args)
+}
+
+// create writes the launcher for goos
+func create(goos, dir, name, target string, args []string) (string, error) {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return "", fmt.Errorf("failed to create shim directory: %w", err)
+	}
+
+	var path, content string
+	if goos == "windows" {
+		path = filepath.Join(dir, name+".cmd")
+		content = fmt.Sprintf("@\"%s\" %s%%*\r\n", target, joinArgs(args, "\"%s\" "))
+	} else {
+		path = filepath.Join(dir, name)
+		content = fmt.Sprintf("#!/bin/sh\nexec '%s' %s\"$@\"\n", shellEscape(target), joinArgs(quoteAll(args), "%s "))
+	}
+
+	if err := os.WriteFile(path, []byte(content), 0755); err != nil {
+		return "", fmt.Errorf("failed to write shim %s: %w", path, err)
+	}
+	return path, nil
+}
+
+// joinArgs formats each argument with format and concatenates them
+func joinArgs(args []string, format string) string {
+	var b strings.Builder
+	for _, arg := range args {
+		fmt.Fprintf(&b, format, arg)
+	}
+	return b.String()
+}
+
+// quoteAll quotes arguments for a POSIX shell
+func quoteAll(args []string) []string {
+	quoted := make([]string, len(args))
+	for i, arg := range args {
+		quoted[i] = "'" + shellEscape(arg) + "'"
+	}
+	return quoted
+}
+
+// shellEscape escapes single quotes inside a single-quoted shell string
+func shellEscape(s string) string {
+	return strings.ReplaceAll(s, "'", `'\''`)
+}
@@ -0,0 +1,53 @@
+package shim
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+)
+
+func TestCreateWindows(t *testing.T) {
+	dir := t.TempDir()
+
+	path, err := create("windows", dir, "tool", `C:\apps\tool\1.0\tool.exe`, []string{"--config", "x y"})
+	if err != nil {
+		t.Fatalf("create() error = %v", err)
+	}
+	if filepath.Base(path) != "tool.cmd" {
+		t.Errorf("Expected tool.cmd, got %s", path)
+	}
+
+	content, _ := os.ReadFile(path)
+	expected := "@\"C:\\apps\\tool\\1.0\\tool.exe\" \"--config\" \"x y\" %*\r\n"
+	if string(content) != expected {
+		t.Errorf("Expected %q, got %q", expected, content)
+	}
+}
+
+func TestCreateUnix(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("shell scripts are not run on Windows")
+	}
+	dir := t.TempDir()
+
+	target := filepath.Join(dir, "it's tool")
+	if err := os.WriteFile(target, []byte("#!/bin/sh\necho \"$@\"\n"), 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	path, err := create("linux", filepath.Join(dir, "bin"), "tool", target, []string{"--flag", "a b"})
+	if err != nil {
+		t.Fatalf("create() error = %v", err)
+	}
+
+	output, err := exec.Command(path, "extra").Output()
+	if err != nil {
+		t.Fatalf("running shim: %v", err)
+	}
+	if got := strings.TrimSpace(string(output)); got != "--flag a b extra" {
+		t.Errorf("Expected shim to pass arguments, got %q", got)
+	}
+}
@@ -0,0 +1,10 @@
+//go:build !windows
+
+package shim
+
+import "fmt"
+
+// CreateShortcut is only supported on Windows, where shortcuts go to the Start menu
+func CreateShortcut(name, target string) (string, error) {
+	return "", fmt.Errorf("shortcuts are only supported on Windows")
+}
@@ -0,0 +1,36 @@
+//go:build windows
+
+package shim
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/pyhub-kr/pyhub-installer/pkg/config"
+)
+
+// CreateShortcut adds a Start menu shortcut named name that opens target and returns its path
+func CreateShortcut(name, target string) (string, error) {
+	dir := filepath.Join(os.Getenv("APPDATA"), "Microsoft", "Windows", "Start Menu", "Programs", config.AppName)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return "", fmt.Errorf("failed to create Start menu folder: %w", err)
+	}
+
+	link := filepath.Join(dir, name+".lnk")
+	script := fmt.Sprintf(
+		"$s = (New-Object -ComObject WScript.Shell).CreateShortcut('%s'); $s.TargetPath = '%s'; $s.WorkingDirectory = '%s'; $s.Save()",
+		psQuote(link), psQuote(target), psQuote(filepath.Dir(target)))
+
+	if output, err := exec.Command("powershell", "-NoProfile", "-NonInteractive", "-Command", script).CombinedOutput(); err != nil {
+		return "", fmt.Errorf("failed to create shortcut %s: %w: %s", name, err, strings.TrimSpace(string(output)))
+	}
+	return link, nil
+}
+
+// psQuote escapes single quotes inside a single-quoted PowerShell string
+func psQuote(s string) string {
+	return strings.ReplaceAll(s, "'", "''")
+}
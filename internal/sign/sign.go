@@ -0,0 +1,84 @@
+package sign
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/pyhub-kr/pyhub-installer/internal/verify"
+)
+
+// SumsFile lists the checksums of all signed files, as sha256sum writes them
+const SumsFile = "SHA256SUMS"
+
+// run runs a signing tool attached to the terminal, so it can ask for a passphrase
+var run = func(name string, args ...string) error {
+	cmd := exec.Command(name, args...)
+	cmd.Stdin = os.Stdin
+	cmd.Stdout = os.Stderr
+	cmd.Stderr = os.Stderr
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("%s failed: %w", name, err)
+	}
+	return nil
+}
+
+// WriteChecksums writes <file>.sha256 for each file and SHA256SUMS listing all of
+// them into dir, the files the installer looks for next to release assets. It returns
+// the paths written.
+func WriteChecksums(files []string, dir string) ([]string, error) {
+	var written []string
+	var sums strings.Builder
+	for _, file := range files {
+		sum, err := verify.NewVerifier(file).GetSHA256()
+		if err != nil {
+			return written, err
+		}
+		line := fmt.Sprintf("%s  %s\n", sum, filepath.Base(file))
+		sums.WriteString(line)
+
+		path := filepath.Join(dir, filepath.Base(file)+".sha256")
+		if err := os.WriteFile(path, []byte(line), 0644); err != nil {
+			return written, fmt.Errorf("failed to write checksum: %w", err)
+		}
+		written = append(written, path)
+	}
+
+	path := filepath.Join(dir, SumsFile)
+	if err := os.WriteFile(path, []byte(sums.String()), 0644); err != nil {
+		return written, fmt.Errorf("failed to write checksums: %w", err)
+	}
+	return append(written, path), nil
+}
+
+// Minisign writes a detached minisign signature <file>.minisig into dir with the
+// minisign secret key file
+func Minisign(file, key, dir string) (string, error) {
+	out := filepath.Join(dir, filepath.Base(file)+".minisig")
+	if err := run("minisign", "-S", "-s", key, "-m", file, "-x", out); err != nil {
+		return "", err
+	}
+	return out, nil
+}
+
+// GPG writes an ASCII-armored detached signature <file>.sig into dir with the key of
+// keyID, under the name the installer looks for
+func GPG(file, keyID, dir string) (string, error) {
+	out := filepath.Join(dir, filepath.Base(file)+".sig")
+	if err := run("gpg", "--yes", "--armor", "--detach-sign", "--local-user", keyID, "--output", out, file); err != nil {
+		return "", err
+	}
+	return out, nil
+}
+
+// VerifyMinisign checks a minisign signature of a file with a public key file
+func VerifyMinisign(file, signature, publicKey string) error {
+	return run("minisign", "-V", "-q", "-p", publicKey, "-m", file, "-x", signature)
+}
+
+// VerifyGPG checks a detached GnuPG signature of a file against the user's keyring
+func VerifyGPG(file, signature string) error {
+	return run("gpg", "--verify", signature, file)
+}
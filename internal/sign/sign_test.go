@@ -0,0 +1,78 @@
+package sign
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/pyhub-kr/pyhub-installer/internal/verify"
+)
+
+func TestWriteChecksums(t *testing.T) {
+	src, out := t.TempDir(), t.TempDir()
+	files := []string{filepath.Join(src, "tool-linux-amd64.tar.gz"), filepath.Join(src, "tool-windows-amd64.zip")}
+	for i, file := range files {
+		if err := os.WriteFile(file, []byte(strings.Repeat("x", i+1)), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	written, err := WriteChecksums(files, out)
+	if err != nil {
+		t.Fatalf("WriteChecksums() error = %v", err)
+	}
+	if len(written) != 3 || filepath.Base(written[2]) != SumsFile {
+		t.Fatalf("WriteChecksums() wrote %v", written)
+	}
+
+	// The installer must find each file's checksum in both formats
+	sums, _ := os.ReadFile(filepath.Join(out, SumsFile))
+	for i, file := range files {
+		want, _ := verify.NewVerifier(file).GetSHA256()
+		if got, err := verify.ChecksumFor(string(sums), filepath.Base(file)); err != nil || got != want {
+			t.Errorf("SHA256SUMS entry of %s = %s, %v, want %s", file, got, err, want)
+		}
+		single, _ := os.ReadFile(written[i])
+		if err := verify.NewVerifier(file).VerifyWithString(string(single)); err != nil {
+			t.Errorf("%s does not verify: %v", written[i], err)
+		}
+	}
+}
+
+func TestSigners(t *testing.T) {
+	original := run
+	defer func() { run = original }()
+
+	var commands []string
+	run = func(name string, args ...string) error {
+		commands = append(commands, name+" "+strings.Join(args, " "))
+		return nil
+	}
+
+	out, err := Minisign("/dist/tool.zip", "/keys/minisign.key", "/out")
+	if err != nil || out != filepath.Join("/out", "tool.zip.minisig") {
+		t.Errorf("Minisign() = %s, %v", out, err)
+	}
+	out, err = GPG("/dist/tool.zip", "ABCD1234", "/out")
+	if err != nil || out != filepath.Join("/out", "tool.zip.sig") {
+		t.Errorf("GPG() = %s, %v", out, err)
+	}
+	if err := VerifyMinisign("tool.zip", "tool.zip.minisig", "minisign.pub"); err != nil {
+		t.Errorf("VerifyMinisign() error = %v", err)
+	}
+	if err := VerifyGPG("tool.zip", "tool.zip.sig"); err != nil {
+		t.Errorf("VerifyGPG() error = %v", err)
+	}
+	want := []string{
+		"minisign -S -s /keys/minisign.key -m /dist/tool.zip -x " + filepath.Join("/out", "tool.zip.minisig"),
+		"gpg --yes --armor --detach-sign --local-user ABCD1234 --output " + filepath.Join("/out", "tool.zip.sig") + " /dist/tool.zip",
+		"minisign -V -q -p minisign.pub -m tool.zip -x tool.zip.minisig",
+		"gpg --verify tool.zip.sig tool.zip",
+	}
+	for i := range want {
+		if i >= len(commands) || commands[i] != want[i] {
+			t.Errorf("Expected command %q, got %q", want[i], commands)
+		}
+	}
+}
@@ -0,0 +1,226 @@
+package state
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/pyhub-kr/pyhub-installer/internal/lock"
+	"github.com/pyhub-kr/pyhub-installer/pkg/config"
+)
+
+// Receipt records one installed tool
+type Receipt struct {
+	Name        string    `json:"name"`
+	Source      string    `json:"source"` // Provider source, e.g. github:owner/repo
+	Version     string    `json:"version"`
+	Channel     string    `json:"channel,omitempty"` // Release channel updates follow, e.g. beta; empty for stable releases
+	Asset       string    `json:"asset"`
+	Built       bool      `json:"built,omitempty"` // Built from the source archive with --build-from-source
+	URL         string    `json:"url"`
+	SHA256      string    `json:"sha256,omitempty"`
+	InstallPath string    `json:"install_path"`
+	InstalledAt time.Time `json:"installed_at"`
+	Files       []string  `json:"files,omitempty"` // Created by the install, e.g. extracted files, store directories, links and shims
+}
+
+// Alias records an extra command created by the alias command for an installed tool
+type Alias struct {
+	Name   string   `json:"name"`
+	Tool   string   `json:"tool"`   // Name of the receipt of the tool it runs
+	Target string   `json:"target"` // Executable it runs
+	Args   []string `json:"args,omitempty"`
+	Path   string   `json:"path"` // Symlink or shim created
+}
+
+// State is the content of the installed-packages database
+type State struct {
+	Receipts []Receipt `json:"receipts"`
+	Aliases  []Alias   `json:"aliases,omitempty"`
+}
+
+// DB is the installed-packages database stored as a JSON file
+type DB struct {
+	Path string
+}
+
+// NewDB creates a database handle for the given file
+func NewDB(path string) *DB {
+	return &DB{Path: path}
+}
+
+// DefaultDB returns the database in the installer data directory
+func DefaultDB() (*DB, error) {
+	dataDir, err := config.DataDir()
+	if err != nil {
+		return nil, err
+	}
+	return NewDB(filepath.Join(dataDir, "state.json")), nil
+}
+
+// Load reads the database while holding its lock
+func (db *DB) Load() (*State, error) {
+	l, err := lock.Acquire(db.lockPath())
+	if err != nil {
+		return nil, err
+	}
+	defer l.Release()
+
+	return db.read()
+}
+
+// Update applies fn to the database atomically with respect to other processes
+func (db *DB) Update(fn func(*State) error) error {
+	l, err := lock.Acquire(db.lockPath())
+	if err != nil {
+		return err
+	}
+	defer l.Release()
+
+	s, err := db.read()
+	if err != nil {
+		return err
+	}
+
+	if err := fn(s); err != nil {
+		return err
+	}
+
+	return db.write(s)
+}
+
+// lockPath returns the lock file guarding the database
+func (db *DB) lockPath() string {
+	return db.Path + ".lock"
+}
+
+// read loads the database file, treating a missing file as empty
+func (db *DB) read() (*State, error) {
+	s := &State{}
+
+	data, err := os.ReadFile(db.Path)
+	if os.IsNotExist(err) {
+		return s, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read state database: %w", err)
+	}
+
+	if err := json.Unmarshal(data, s); err != nil {
+		return nil, fmt.Errorf("failed to parse state database %s: %w", db.Path, err)
+	}
+	return s, nil
+}
+
+// write saves the database via a temporary file so readers never see partial content
+func (db *DB) write(s *State) error {
+	if err := os.MkdirAll(filepath.Dir(db.Path), 0755); err != nil {
+		return fmt.Errorf("failed to create state directory: %w", err)
+	}
+
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode state database: %w", err)
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(db.Path), ".state-*.json")
+	if err != nil {
+		return fmt.Errorf("failed to write state database: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to write state database: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to write state database: %w", err)
+	}
+
+	if err := os.Rename(tmp.Name(), db.Path); err != nil {
+		return fmt.Errorf("failed to replace state database: %w", err)
+	}
+	return nil
+}
+
+// Get returns the receipt for a tool, or nil if it is not installed
+func (s *State) Get(name string) *Receipt {
+	for i := range s.Receipts {
+		if s.Receipts[i].Name == name {
+			return &s.Receipts[i]
+		}
+	}
+	return nil
+}
+
+// Put adds or replaces the receipt for a tool
+func (s *State) Put(r Receipt) {
+	if existing := s.Get(r.Name); existing != nil {
+		*existing = r
+		return
+	}
+	s.Receipts = append(s.Receipts, r)
+	sort.Slice(s.Receipts, func(i, j int) bool { return s.Receipts[i].Name < s.Receipts[j].Name })
+}
+
+// Remove deletes the receipt for a tool and reports whether it existed
+func (s *State) Remove(name string) bool {
+	for i := range s.Receipts {
+		if s.Receipts[i].Name == name {
+			s.Receipts = append(s.Receipts[:i], s.Receipts[i+1:]...)
+			return true
+		}
+	}
+	return false
+}
+
+// GetAlias returns an alias by name, or nil
+func (s *State) GetAlias(name string) *Alias {
+	for i := range s.Aliases {
+		if s.Aliases[i].Name == name {
+			return &s.Aliases[i]
+		}
+	}
+	return nil
+}
+
+// PutAlias adds or replaces an alias
+func (s *State) PutAlias(a Alias) {
+	if existing := s.GetAlias(a.Name); existing != nil {
+		*existing = a
+		return
+	}
+	s.Aliases = append(s.Aliases, a)
+	sort.Slice(s.Aliases, func(i, j int) bool { return s.Aliases[i].Name < s.Aliases[j].Name })
+}
+
+// RemoveAlias deletes an alias and returns it, or nil if it did not exist
+func (s *State) RemoveAlias(name string) *Alias {
+	for i := range s.Aliases {
+		if s.Aliases[i].Name == name {
+			removed := s.Aliases[i]
+			s.Aliases = append(s.Aliases[:i], s.Aliases[i+1:]...)
+			return &removed
+		}
+	}
+	return nil
+}
+
+// RemoveAliasesOf deletes the aliases of a tool, e.g. when it is uninstalled, and
+// returns them so their files can be removed
+func (s *State) RemoveAliasesOf(tool string) []Alias {
+	var removed []Alias
+	kept := s.Aliases[:0]
+	for _, a := range s.Aliases {
+		if a.Tool == tool {
+			removed = append(removed, a)
+		} else {
+			kept = append(kept, a)
+		}
+	}
+	s.Aliases = kept
+	return removed
+}
@@ -0,0 +1,174 @@
+package state
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestLoadMissingDatabase(t *testing.T) {
+	db := NewDB(filepath.Join(t.TempDir(), "state.json"))
+
+	s, err := db.Load()
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	if len(s.Receipts) != 0 {
+		t.Errorf("Expected empty state, got %d receipts", len(s.Receipts))
+	}
+}
+
+func TestUpdateAndLoad(t *testing.T) {
+	db := NewDB(filepath.Join(t.TempDir(), "data", "state.json"))
+	installedAt := time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	err := db.Update(func(s *State) error {
+		s.Put(Receipt{
+			Name:        "cli",
+			Source:      "github:cli/cli",
+			Version:     "v2.40.0",
+			Asset:       "gh_2.40.0_linux_amd64.tar.gz",
+			InstallPath: "/home/user/.local/bin",
+			InstalledAt: installedAt,
+		})
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("Update failed: %v", err)
+	}
+
+	s, err := db.Load()
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+
+	r := s.Get("cli")
+	if r == nil {
+		t.Fatal("Expected receipt for cli")
+	}
+	if r.Version != "v2.40.0" || r.Source != "github:cli/cli" {
+		t.Errorf("Unexpected receipt: %+v", r)
+	}
+	if !r.InstalledAt.Equal(installedAt) {
+		t.Errorf("Expected InstalledAt %v, got %v", installedAt, r.InstalledAt)
+	}
+}
+
+func TestUpdateErrorKeepsDatabase(t *testing.T) {
+	db := NewDB(filepath.Join(t.TempDir(), "state.json"))
+
+	db.Update(func(s *State) error {
+		s.Put(Receipt{Name: "tool", Version: "v1"})
+		return nil
+	})
+
+	err := db.Update(func(s *State) error {
+		s.Put(Receipt{Name: "tool", Version: "v2"})
+		return fmt.Errorf("install failed")
+	})
+	if err == nil {
+		t.Fatal("Expected error from Update")
+	}
+
+	s, _ := db.Load()
+	if r := s.Get("tool"); r == nil || r.Version != "v1" {
+		t.Errorf("Failed update should not be saved, got %+v", r)
+	}
+}
+
+func TestConcurrentUpdates(t *testing.T) {
+	db := NewDB(filepath.Join(t.TempDir(), "state.json"))
+
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func(n int) {
+			defer wg.Done()
+			err := NewDB(db.Path).Update(func(s *State) error {
+				s.Put(Receipt{Name: fmt.Sprintf("tool-%02d", n), Version: "v1"})
+				return nil
+			})
+			if err != nil {
+				t.Errorf("Update failed: %v", err)
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	s, err := db.Load()
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	if len(s.Receipts) != 20 {
+		t.Errorf("Expected 20 receipts after concurrent updates, got %d", len(s.Receipts))
+	}
+}
+
+func TestPutReplacesAndSorts(t *testing.T) {
+	s := &State{}
+	s.Put(Receipt{Name: "zeta", Version: "v1"})
+	s.Put(Receipt{Name: "alpha", Version: "v1"})
+	s.Put(Receipt{Name: "zeta", Version: "v2"})
+
+	if len(s.Receipts) != 2 {
+		t.Fatalf("Expected 2 receipts, got %d", len(s.Receipts))
+	}
+	if s.Receipts[0].Name != "alpha" {
+		t.Errorf("Expected receipts sorted by name, got %s first", s.Receipts[0].Name)
+	}
+	if r := s.Get("zeta"); r.Version != "v2" {
+		t.Errorf("Expected zeta to be replaced with v2, got %s", r.Version)
+	}
+}
+
+func TestRemove(t *testing.T) {
+	s := &State{}
+	s.Put(Receipt{Name: "tool"})
+
+	if !s.Remove("tool") {
+		t.Error("Remove should report existing receipt")
+	}
+	if s.Remove("tool") {
+		t.Error("Remove should report missing receipt")
+	}
+}
+
+func TestCorruptDatabase(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "state.json")
+	if err := os.WriteFile(path, []byte("{not json"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := NewDB(path).Load(); err == nil {
+		t.Error("Expected error for corrupt database")
+	}
+}
+
+func TestAliases(t *testing.T) {
+	s := &State{}
+	s.PutAlias(Alias{Name: "rgs", Tool: "ripgrep", Target: "/bin/rg", Args: []string{"--smart-case"}, Path: "/bin/rgs"})
+	s.PutAlias(Alias{Name: "g", Tool: "cli", Target: "/bin/gh", Path: "/bin/g"})
+	s.PutAlias(Alias{Name: "rgh", Tool: "ripgrep", Target: "/bin/rg", Args: []string{"--hidden"}, Path: "/bin/rgh"})
+	s.PutAlias(Alias{Name: "g", Tool: "cli", Target: "/bin/gh", Args: []string{"pr"}, Path: "/bin/g"})
+
+	if len(s.Aliases) != 3 || s.Aliases[0].Name != "g" {
+		t.Fatalf("Expected 3 sorted aliases, got %+v", s.Aliases)
+	}
+	if a := s.GetAlias("g"); a == nil || len(a.Args) != 1 {
+		t.Errorf("Expected the alias to be replaced, got %+v", a)
+	}
+
+	removed := s.RemoveAliasesOf("ripgrep")
+	if len(removed) != 2 || len(s.Aliases) != 1 {
+		t.Errorf("RemoveAliasesOf() = %+v, left %+v", removed, s.Aliases)
+	}
+	if a := s.RemoveAlias("g"); a == nil || a.Path != "/bin/g" {
+		t.Errorf("RemoveAlias() = %+v", a)
+	}
+	if s.RemoveAlias("g") != nil {
+		t.Error("Expected nil removing a missing alias")
+	}
+}
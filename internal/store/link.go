@@ -0,0 +1,117 @@
+package store
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"time"
+
+	"github.com/pyhub-kr/pyhub-installer/internal/shim"
+)
+
+// windowsExecutables are the extensions of files linked on Windows
+var windowsExecutables = []string{".exe", ".bat", ".cmd", ".ps1"}
+
+// Link creates a link in binDir for each executable of a version directory: those in
+// its bin directory if it has one, otherwise those at its top level. Links are symbolic
+// links, or shims on Windows. Existing links and shims into storeDir are replaced
+// atomically; other files are left alone and returned as conflicts.
+func Link(binDir, versionDir, storeDir string) (links, conflicts []string, err error) {
+	executables, err := Executables(versionDir)
+	if err != nil {
+		return nil, nil, err
+	}
+	if err := os.MkdirAll(binDir, 0755); err != nil {
+		return nil, nil, fmt.Errorf("failed to create bin directory: %w", err)
+	}
+
+	for _, exe := range executables {
+		name := filepath.Base(exe)
+		path := filepath.Join(binDir, name)
+		if runtime.GOOS == "windows" {
+			name = strings.TrimSuffix(name, filepath.Ext(name))
+			path = filepath.Join(binDir, name+".cmd")
+		}
+		if !replaceable(path, storeDir) {
+			conflicts = append(conflicts, path)
+			continue
+		}
+
+		if runtime.GOOS == "windows" {
+			if _, err := shim.Create(binDir, name, exe, nil); err != nil {
+				return links, conflicts, err
+			}
+		} else {
+			tmp := fmt.Sprintf("%s.pyhub-new-%d", path, time.Now().UnixNano())
+			if err := os.Symlink(exe, tmp); err != nil {
+				return links, conflicts, fmt.Errorf("failed to link %s: %w", name, err)
+			}
+			if err := os.Rename(tmp, path); err != nil {
+				os.Remove(tmp)
+				return links, conflicts, fmt.Errorf("failed to link %s: %w", name, err)
+			}
+		}
+		links = append(links, path)
+	}
+	return links, conflicts, nil
+}
+
+// Executables returns the executables of bin/ in dir if it exists, otherwise those in dir
+func Executables(dir string) ([]string, error) {
+	if info, err := os.Stat(filepath.Join(dir, "bin")); err == nil && info.IsDir() {
+		dir = filepath.Join(dir, "bin")
+	}
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	var found []string
+	for _, entry := range entries {
+		if entry.IsDir() {
+			continue
+		}
+		info, err := entry.Info()
+		if err != nil {
+			return nil, err
+		}
+		if runtime.GOOS == "windows" {
+			ext := strings.ToLower(filepath.Ext(entry.Name()))
+			for _, e := range windowsExecutables {
+				if ext == e {
+					found = append(found, filepath.Join(dir, entry.Name()))
+				}
+			}
+		} else if info.Mode().IsRegular() && info.Mode()&0111 != 0 {
+			found = append(found, filepath.Join(dir, entry.Name()))
+		}
+	}
+	return found, nil
+}
+
+// replaceable reports whether path is missing, or a link or shim into storeDir
+func replaceable(path, storeDir string) bool {
+	info, err := os.Lstat(path)
+	if os.IsNotExist(err) {
+		return true
+	}
+	if err != nil {
+		return false
+	}
+	if info.Mode()&os.ModeSymlink != 0 {
+		target, err := os.Readlink(path)
+		if err != nil {
+			return false
+		}
+		rel, err := filepath.Rel(storeDir, target)
+		return err == nil && rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator))
+	}
+	// Shims are small scripts naming their target
+	if !info.Mode().IsRegular() || info.Size() > 4096 {
+		return false
+	}
+	data, err := os.ReadFile(path)
+	return err == nil && strings.Contains(string(data), storeDir)
+}
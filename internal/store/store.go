@@ -0,0 +1,123 @@
+package store
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/pyhub-kr/pyhub-installer/internal/semver"
+)
+
+// CurrentLink is the name of the link in a tool's store directory that points to the
+// active version. Where symbolic links cannot be created (Windows without Developer
+// Mode) it is a file containing the version instead.
+const CurrentLink = "current"
+
+// Versions returns the versions of a tool directory (<store>/<tool>), oldest first
+func Versions(toolDir string) ([]string, error) {
+	entries, err := os.ReadDir(toolDir)
+	if err != nil {
+		return nil, err
+	}
+	var versions []string
+	for _, entry := range entries {
+		if entry.IsDir() && entry.Name() != CurrentLink && !strings.HasPrefix(entry.Name(), ".") {
+			versions = append(versions, entry.Name())
+		}
+	}
+	sortVersions(versions)
+	return versions, nil
+}
+
+// sortVersions orders semantic versions by precedence and other names alphabetically
+// before them
+func sortVersions(versions []string) {
+	less := func(a, b string) bool {
+		va, errA := semver.Parse(a)
+		vb, errB := semver.Parse(b)
+		switch {
+		case errA == nil && errB == nil:
+			return va.Compare(vb) < 0
+		case errA != nil && errB != nil:
+			return a < b
+		}
+		return errA != nil
+	}
+	sort.Slice(versions, func(i, j int) bool { return less(versions[i], versions[j]) })
+}
+
+// Current returns the active version of a tool directory, or "" if none is set
+func Current(toolDir string) string {
+	path := filepath.Join(toolDir, CurrentLink)
+	if target, err := os.Readlink(path); err == nil {
+		return filepath.Base(target)
+	}
+	if info, err := os.Lstat(path); err == nil && info.Mode().IsRegular() {
+		if data, err := os.ReadFile(path); err == nil {
+			return strings.TrimSpace(string(data))
+		}
+	}
+	return ""
+}
+
+// SetCurrent makes version the active version of a tool directory. The link is
+// created under a temporary name and renamed over the old one, so it is replaced
+// atomically.
+func SetCurrent(toolDir, version string) error {
+	tmp := filepath.Join(toolDir, fmt.Sprintf(".%s-%d", CurrentLink, time.Now().UnixNano()))
+	if err := os.Symlink(version, tmp); err != nil {
+		if err := os.WriteFile(tmp, []byte(version+"\n"), 0644); err != nil {
+			return fmt.Errorf("failed to set current version: %w", err)
+		}
+	}
+	if err := os.Rename(tmp, filepath.Join(toolDir, CurrentLink)); err != nil {
+		os.Remove(tmp)
+		return fmt.Errorf("failed to set current version: %w", err)
+	}
+	return nil
+}
+
+// Repoint makes a link or shim that refers to a file under from refer to the same file
+// under to instead, replacing it atomically. It reports whether path was changed.
+func Repoint(path, from, to string) (bool, error) {
+	info, err := os.Lstat(path)
+	if err != nil {
+		return false, err
+	}
+	tmp := fmt.Sprintf("%s.pyhub-new-%d", path, time.Now().UnixNano())
+
+	if info.Mode()&os.ModeSymlink != 0 {
+		target, err := os.Readlink(path)
+		if err != nil {
+			return false, err
+		}
+		rel, err := filepath.Rel(from, target)
+		if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+			return false, nil
+		}
+		if err := os.Symlink(filepath.Join(to, rel), tmp); err != nil {
+			return false, fmt.Errorf("failed to repoint %s: %w", path, err)
+		}
+	} else {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return false, err
+		}
+		if !strings.Contains(string(data), from) {
+			return false, nil
+		}
+		content := strings.ReplaceAll(string(data), from, to)
+		if err := os.WriteFile(tmp, []byte(content), info.Mode().Perm()); err != nil {
+			return false, fmt.Errorf("failed to repoint %s: %w", path, err)
+		}
+	}
+
+	if err := os.Rename(tmp, path); err != nil {
+		os.Remove(tmp)
+		return false, fmt.Errorf("failed to repoint %s: %w", path, err)
+	}
+	return true, nil
+}
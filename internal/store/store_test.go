@@ -0,0 +1,148 @@
+package store
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"runtime"
+	"testing"
+)
+
+func TestVersionsAndCurrent(t *testing.T) {
+	toolDir := t.TempDir()
+	for _, v := range []string{"v1.10.0", "v1.2.0", "nightly", "v2.0.0-rc.1"} {
+		if err := os.MkdirAll(filepath.Join(toolDir, v), 0755); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if current := Current(toolDir); current != "" {
+		t.Errorf("Current() without a link = %q", current)
+	}
+	if err := SetCurrent(toolDir, "v1.2.0"); err != nil {
+		t.Fatalf("SetCurrent() error = %v", err)
+	}
+	if err := SetCurrent(toolDir, "v1.10.0"); err != nil {
+		t.Fatalf("SetCurrent() replacing the link error = %v", err)
+	}
+	if current := Current(toolDir); current != "v1.10.0" {
+		t.Errorf("Current() = %q, want v1.10.0", current)
+	}
+
+	versions, err := Versions(toolDir)
+	if err != nil {
+		t.Fatalf("Versions() error = %v", err)
+	}
+	want := []string{"nightly", "v1.2.0", "v1.10.0", "v2.0.0-rc.1"}
+	if !reflect.DeepEqual(versions, want) {
+		t.Errorf("Versions() = %v, want %v", versions, want)
+	}
+}
+
+func TestCurrentFile(t *testing.T) {
+	toolDir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(toolDir, CurrentLink), []byte("v1.0.0\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if current := Current(toolDir); current != "v1.0.0" {
+		t.Errorf("Current() = %q, want the version of the current file", current)
+	}
+}
+
+func TestRepointShim(t *testing.T) {
+	dir := t.TempDir()
+	from, to := filepath.Join(dir, "store", "tool", "v1"), filepath.Join(dir, "store", "tool", "v2")
+	shim := filepath.Join(dir, "tool")
+	if err := os.WriteFile(shim, []byte("#!/bin/sh\nexec '"+from+"/bin/tool' \"$@\"\n"), 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	changed, err := Repoint(shim, from, to)
+	if err != nil || !changed {
+		t.Fatalf("Repoint() = %v, %v", changed, err)
+	}
+	data, _ := os.ReadFile(shim)
+	if want := "#!/bin/sh\nexec '" + to + "/bin/tool' \"$@\"\n"; string(data) != want {
+		t.Errorf("Repointed shim = %q, want %q", data, want)
+	}
+	if info, _ := os.Stat(shim); runtime.GOOS != "windows" && info.Mode().Perm() != 0755 {
+		t.Errorf("Expected the shim to stay executable, got %v", info.Mode())
+	}
+
+	if changed, err := Repoint(shim, filepath.Join(dir, "other"), to); err != nil || changed {
+		t.Errorf("Repoint() of an unrelated shim = %v, %v", changed, err)
+	}
+}
+
+func TestRepointSymlink(t *testing.T) {
+	dir := t.TempDir()
+	from, to := filepath.Join(dir, "v1"), filepath.Join(dir, "v2")
+	link := filepath.Join(dir, "tool")
+	if err := os.Symlink(filepath.Join(from, "bin", "tool"), link); err != nil {
+		t.Skipf("symlinks not supported: %v", err)
+	}
+
+	changed, err := Repoint(link, from, to)
+	if err != nil || !changed {
+		t.Fatalf("Repoint() = %v, %v", changed, err)
+	}
+	if target, _ := os.Readlink(link); target != filepath.Join(to, "bin", "tool") {
+		t.Errorf("Link points to %s", target)
+	}
+}
+
+func TestLink(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("Windows links executables with shims")
+	}
+	root := t.TempDir()
+	storeDir := filepath.Join(root, "store")
+	binDir := filepath.Join(root, "bin")
+	v1 := filepath.Join(storeDir, "tool", "1.0.0")
+	for path, mode := range map[string]os.FileMode{
+		filepath.Join(v1, "bin", "tool"):        0755,
+		filepath.Join(v1, "bin", "tool-helper"): 0755,
+		filepath.Join(v1, "bin", "README"):      0644,
+		filepath.Join(v1, "LICENSE"):            0755,
+	} {
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, nil, mode); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// Another program's file of the same name is not replaced
+	if err := os.MkdirAll(binDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(binDir, "tool-helper"), []byte("#!/bin/sh\n"), 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	links, conflicts, err := Link(binDir, v1, storeDir)
+	if err != nil {
+		t.Fatalf("Link() error = %v", err)
+	}
+	if len(links) != 1 || links[0] != filepath.Join(binDir, "tool") {
+		t.Errorf("Expected only bin/tool to be linked, got %v", links)
+	}
+	if len(conflicts) != 1 || conflicts[0] != filepath.Join(binDir, "tool-helper") {
+		t.Errorf("Expected a conflict with the existing tool-helper, got %v", conflicts)
+	}
+
+	// Links of a previous version are replaced
+	v2 := filepath.Join(storeDir, "tool", "2.0.0")
+	if err := os.MkdirAll(v2, 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(v2, "tool"), nil, 0755); err != nil {
+		t.Fatal(err)
+	}
+	if _, _, err := Link(binDir, v2, storeDir); err != nil {
+		t.Fatalf("Link() error = %v", err)
+	}
+	if target, _ := os.Readlink(filepath.Join(binDir, "tool")); target != filepath.Join(v2, "tool") {
+		t.Errorf("Link points to %s", target)
+	}
+}
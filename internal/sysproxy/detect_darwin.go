@@ -0,0 +1,17 @@
+//go:build darwin
+
+package sysproxy
+
+import (
+	"fmt"
+	"os/exec"
+)
+
+// detect reads the proxy of the active network service with scutil
+func detect() (*Settings, error) {
+	output, err := exec.Command("scutil", "--proxy").Output()
+	if err != nil {
+		return nil, fmt.Errorf("failed to run scutil: %w", err)
+	}
+	return parseScutil(string(output)), nil
+}
@@ -0,0 +1,18 @@
+//go:build linux
+
+package sysproxy
+
+import (
+	"os/exec"
+)
+
+// detect reads the GNOME proxy settings, if gsettings is available
+func detect() (*Settings, error) {
+	if _, err := exec.LookPath("gsettings"); err != nil {
+		return nil, nil
+	}
+	return parseGSettings(func(schema, key string) (string, error) {
+		output, err := exec.Command("gsettings", "get", schema, key).Output()
+		return string(output), err
+	})
+}
@@ -0,0 +1,8 @@
+//go:build !windows && !darwin && !linux
+
+package sysproxy
+
+// detect finds no system proxy on other operating systems
+func detect() (*Settings, error) {
+	return nil, nil
+}
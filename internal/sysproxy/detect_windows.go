@@ -0,0 +1,86 @@
+//go:build windows
+
+package sysproxy
+
+import (
+	"fmt"
+	"unsafe"
+
+	"golang.org/x/sys/windows"
+	"golang.org/x/sys/windows/registry"
+)
+
+// internetSettingsKey holds the per-user proxy configured in Windows settings
+const internetSettingsKey = `Software\Microsoft\Windows\CurrentVersion\Internet Settings`
+
+// winhttpAccessTypeNamedProxy is WINHTTP_ACCESS_TYPE_NAMED_PROXY
+const winhttpAccessTypeNamedProxy = 3
+
+var (
+	winhttp                                 = windows.NewLazySystemDLL("winhttp.dll")
+	procWinHttpGetDefaultProxyConfiguration = winhttp.NewProc("WinHttpGetDefaultProxyConfiguration")
+	procGlobalFree                          = windows.NewLazySystemDLL("kernel32.dll").NewProc("GlobalFree")
+)
+
+// winhttpProxyInfo is WINHTTP_PROXY_INFO
+type winhttpProxyInfo struct {
+	accessType  uint32
+	proxy       *uint16
+	proxyBypass *uint16
+}
+
+// detect reads the user's Internet Settings, as browsers do, then the machine-wide
+// WinHTTP proxy (netsh winhttp set proxy)
+func detect() (*Settings, error) {
+	if settings, err := internetSettings(); err != nil || settings != nil {
+		return settings, err
+	}
+	return winHTTPSettings()
+}
+
+// internetSettings reads the proxy of the Windows Internet Settings, if enabled
+func internetSettings() (*Settings, error) {
+	key, err := registry.OpenKey(registry.CURRENT_USER, internetSettingsKey, registry.QUERY_VALUE)
+	if err != nil {
+		return nil, nil
+	}
+	defer key.Close()
+
+	if enabled, _, err := key.GetIntegerValue("ProxyEnable"); err != nil || enabled == 0 {
+		return nil, nil
+	}
+	server, _, err := key.GetStringValue("ProxyServer")
+	if err != nil {
+		return nil, nil
+	}
+	override, _, _ := key.GetStringValue("ProxyOverride")
+	return parseWindows(server, override, "Windows Internet Settings"), nil
+}
+
+// winHTTPSettings reads the WinHTTP default proxy
+func winHTTPSettings() (*Settings, error) {
+	if err := procWinHttpGetDefaultProxyConfiguration.Find(); err != nil {
+		return nil, nil
+	}
+
+	var info winhttpProxyInfo
+	if r, _, err := procWinHttpGetDefaultProxyConfiguration.Call(uintptr(unsafe.Pointer(&info))); r == 0 {
+		return nil, fmt.Errorf("failed to read WinHTTP proxy settings: %w", err)
+	}
+	defer func() {
+		for _, p := range []*uint16{info.proxy, info.proxyBypass} {
+			if p != nil {
+				procGlobalFree.Call(uintptr(unsafe.Pointer(p)))
+			}
+		}
+	}()
+
+	if info.accessType != winhttpAccessTypeNamedProxy || info.proxy == nil {
+		return nil, nil
+	}
+	bypass := ""
+	if info.proxyBypass != nil {
+		bypass = windows.UTF16PtrToString(info.proxyBypass)
+	}
+	return parseWindows(windows.UTF16PtrToString(info.proxy), bypass, "WinHTTP"), nil
+}
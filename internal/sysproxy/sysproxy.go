@@ -0,0 +1,273 @@
+package sysproxy
+
+import (
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"path"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// Settings are the proxy settings of the operating system
+type Settings struct {
+	HTTP   string   // Proxy for http:// URLs, as host:port or URL
+	HTTPS  string   // Proxy for https:// URLs, as host:port or URL
+	Bypass []string // Hosts reached directly: names, *.domain or .domain suffixes, CIDRs, <local>
+	Source string   // Where the settings were read, e.g. "scutil"
+}
+
+// Detect reads the proxy settings of the operating system: the Internet Settings or
+// WinHTTP proxy on Windows, scutil on macOS and GNOME settings on Linux. It returns
+// nil when no proxy is configured.
+func Detect() (*Settings, error) {
+	return detect()
+}
+
+// envVars are the proxy variables http.ProxyFromEnvironment reads
+var envVars = []string{"HTTPS_PROXY", "https_proxy", "HTTP_PROXY", "http_proxy"}
+
+// ProxyFunc returns a proxy function for http.Transport that uses the proxy environment
+// variables when any is set, and otherwise the settings of the operating system,
+// detected on the first request
+func ProxyFunc(getenv func(string) string) func(*http.Request) (*url.URL, error) {
+	for _, name := range envVars {
+		if getenv(name) != "" {
+			return http.ProxyFromEnvironment
+		}
+	}
+
+	var once sync.Once
+	var settings *Settings
+	return func(req *http.Request) (*url.URL, error) {
+		once.Do(func() {
+			settings, _ = Detect()
+		})
+		if settings == nil {
+			return nil, nil
+		}
+		return settings.Proxy(req)
+	}
+}
+
+// Schemes are the proxy URL schemes requests can use. socks5h resolves host names on
+// the proxy, which Go's socks5 does as well.
+var Schemes = []string{"http", "https", "socks5", "socks5h"}
+
+// ParseURL checks a proxy URL given by the user, e.g. socks5://127.0.0.1:1080
+func ParseURL(raw string) (*url.URL, error) {
+	u, err := url.Parse(raw)
+	if err != nil {
+		return nil, fmt.Errorf("invalid proxy URL %q: %w", raw, err)
+	}
+	if !slices.Contains(Schemes, u.Scheme) || u.Host == "" {
+		return nil, fmt.Errorf("invalid proxy URL %q: expected a URL such as socks5://host:port (schemes: %s)", raw, strings.Join(Schemes, ", "))
+	}
+	return u, nil
+}
+
+// Proxy returns the proxy URL for a request, or nil if it goes direct
+func (s *Settings) Proxy(req *http.Request) (*url.URL, error) {
+	proxy := s.HTTPS
+	if req.URL.Scheme == "http" {
+		proxy = s.HTTP
+	}
+	if proxy == "" || s.bypassed(req.URL.Hostname()) {
+		return nil, nil
+	}
+	if !strings.Contains(proxy, "://") {
+		proxy = "http://" + proxy
+	}
+	return url.Parse(proxy)
+}
+
+// bypassed reports whether a host is reached without the proxy. Loopback addresses
+// always are, as with the proxy environment variables.
+func (s *Settings) bypassed(host string) bool {
+	host = strings.ToLower(host)
+	ip := net.ParseIP(host)
+	if host == "localhost" || (ip != nil && ip.IsLoopback()) {
+		return true
+	}
+
+	for _, entry := range s.Bypass {
+		entry = strings.ToLower(strings.TrimSpace(entry))
+		switch {
+		case entry == "":
+		case entry == "*":
+			return true
+		case entry == "<local>":
+			if ip == nil && !strings.Contains(host, ".") {
+				return true
+			}
+		case strings.Contains(entry, "/"):
+			if _, network, err := net.ParseCIDR(completeCIDR(entry)); err == nil && ip != nil && network.Contains(ip) {
+				return true
+			}
+		case strings.HasPrefix(entry, "*."):
+			if host == entry[2:] || strings.HasSuffix(host, entry[1:]) {
+				return true
+			}
+		case strings.Contains(entry, "*"):
+			if ok, _ := path.Match(entry, host); ok {
+				return true
+			}
+		case strings.HasPrefix(entry, "."):
+			if strings.HasSuffix(host, entry) {
+				return true
+			}
+		default:
+			if host == entry || strings.HasSuffix(host, "."+entry) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// completeCIDR expands an abbreviated IPv4 CIDR such as macOS's "169.254/16"
+func completeCIDR(cidr string) string {
+	addr, bits, _ := strings.Cut(cidr, "/")
+	if strings.Contains(addr, ":") {
+		return cidr
+	}
+	for strings.Count(addr, ".") < 3 {
+		addr += ".0"
+	}
+	return addr + "/" + bits
+}
+
+// hostPort joins a proxy host and port, or returns "" without a host
+func hostPort(host string, port int) string {
+	if host == "" {
+		return ""
+	}
+	if port == 0 {
+		return host
+	}
+	return net.JoinHostPort(host, strconv.Itoa(port))
+}
+
+// parseWindows parses the ProxyServer and ProxyOverride values of the Windows Internet
+// Settings: either one proxy for all protocols ("proxy:8080") or per-protocol entries
+// ("http=proxy:8080;https=proxy:8443"), and ";"-separated bypass entries
+func parseWindows(server, override, source string) *Settings {
+	settings := &Settings{Source: source}
+	for _, part := range strings.Split(server, ";") {
+		part = strings.TrimSpace(part)
+		protocol, proxy, ok := strings.Cut(part, "=")
+		switch {
+		case !ok || strings.Contains(part, "://"):
+			if part != "" && settings.HTTP == "" && settings.HTTPS == "" {
+				settings.HTTP, settings.HTTPS = part, part
+			}
+		case strings.EqualFold(protocol, "http"):
+			settings.HTTP = proxy
+		case strings.EqualFold(protocol, "https"):
+			settings.HTTPS = proxy
+		}
+	}
+	if settings.HTTP == "" && settings.HTTPS == "" {
+		return nil
+	}
+
+	for _, entry := range strings.FieldsFunc(override, func(r rune) bool { return r == ';' || r == ' ' }) {
+		settings.Bypass = append(settings.Bypass, entry)
+	}
+	return settings
+}
+
+// parseScutil parses the output of macOS "scutil --proxy"
+func parseScutil(output string) *Settings {
+	values := map[string]string{}
+	var bypass []string
+	inExceptions := false
+	for _, line := range strings.Split(output, "\n") {
+		line = strings.TrimSpace(line)
+		key, value, ok := strings.Cut(line, " : ")
+		switch {
+		case inExceptions && line == "}":
+			inExceptions = false
+		case inExceptions && ok:
+			bypass = append(bypass, value)
+		case ok && key == "ExceptionsList":
+			inExceptions = true
+		case ok:
+			values[key] = value
+		}
+	}
+
+	settings := &Settings{Bypass: bypass, Source: "scutil"}
+	for _, protocol := range []string{"HTTP", "HTTPS"} {
+		if values[protocol+"Enable"] != "1" {
+			continue
+		}
+		port, _ := strconv.Atoi(values[protocol+"Port"])
+		proxy := hostPort(values[protocol+"Proxy"], port)
+		if protocol == "HTTP" {
+			settings.HTTP = proxy
+		} else {
+			settings.HTTPS = proxy
+		}
+	}
+	if settings.HTTP == "" && settings.HTTPS == "" {
+		return nil
+	}
+	if values["ExcludeSimpleHostnames"] == "1" {
+		settings.Bypass = append(settings.Bypass, "<local>")
+	}
+	return settings
+}
+
+// parseGSettings reads the GNOME proxy settings through get, which returns the value
+// of a gsettings key as printed by "gsettings get". Only the manual mode is supported.
+func parseGSettings(get func(schema, key string) (string, error)) (*Settings, error) {
+	mode, err := get("org.gnome.system.proxy", "mode")
+	if err != nil {
+		return nil, err
+	}
+	if gvariantString(mode) != "manual" {
+		return nil, nil
+	}
+
+	settings := &Settings{Source: "GNOME settings"}
+	for _, protocol := range []string{"http", "https"} {
+		schema := "org.gnome.system.proxy." + protocol
+		host, err := get(schema, "host")
+		if err != nil {
+			return nil, err
+		}
+		port, err := get(schema, "port")
+		if err != nil {
+			return nil, err
+		}
+		portNumber, _ := strconv.Atoi(strings.TrimSpace(port))
+		proxy := hostPort(gvariantString(host), portNumber)
+		if protocol == "http" {
+			settings.HTTP = proxy
+		} else {
+			settings.HTTPS = proxy
+		}
+	}
+	if settings.HTTP == "" && settings.HTTPS == "" {
+		return nil, nil
+	}
+
+	if ignore, err := get("org.gnome.system.proxy", "ignore-hosts"); err == nil {
+		list := strings.Trim(strings.TrimPrefix(strings.TrimSpace(ignore), "@as"), " []")
+		for _, entry := range strings.Split(list, ",") {
+			if entry = gvariantString(entry); entry != "" {
+				settings.Bypass = append(settings.Bypass, entry)
+			}
+		}
+	}
+	return settings, nil
+}
+
+// gvariantString unquotes a GVariant string such as 'manual'
+func gvariantString(value string) string {
+	return strings.Trim(strings.TrimSpace(value), `'"`)
+}
@@ -0,0 +1,161 @@
+package tempdir
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pyhub-kr/pyhub-installer/internal/lock"
+)
+
+// Temporary files of a process live in a session directory under the temp root,
+// run-*, which the process holds a lock in. The session is removed when the process
+// finishes; sessions left behind by processes that crashed or were killed are found
+// by their released lock and removed by the next run.
+
+// sessionPrefix starts the names of session directories
+const sessionPrefix = "run-"
+
+// lockName is the lock file held in a session directory while its process runs
+const lockName = ".lock"
+
+// recoveryGrace protects sessions that were just created and are not locked yet
+const recoveryGrace = time.Minute
+
+var (
+	mu      sync.Mutex
+	root    string
+	session string
+	held    *lock.Lock
+)
+
+// DefaultRoot returns the temp root used unless one is configured: a directory of the
+// current user in the system temp directory
+func DefaultRoot() string {
+	name := "pyhub-installer"
+	if runtime.GOOS != "windows" {
+		// /tmp is shared between users
+		name = fmt.Sprintf("pyhub-installer-%d", os.Getuid())
+	}
+	return filepath.Join(os.TempDir(), name)
+}
+
+// Configure sets the temp root; "" selects DefaultRoot. It must be called before
+// temporary files are created.
+func Configure(dir string) {
+	mu.Lock()
+	defer mu.Unlock()
+	root = dir
+}
+
+// Root returns the temp root
+func Root() string {
+	mu.Lock()
+	defer mu.Unlock()
+	return rootLocked()
+}
+
+func rootLocked() string {
+	if root == "" {
+		return DefaultRoot()
+	}
+	return root
+}
+
+// Dir returns the session directory of this process, creating it on first use
+func Dir() (string, error) {
+	mu.Lock()
+	defer mu.Unlock()
+	if session != "" {
+		return session, nil
+	}
+
+	r := rootLocked()
+	if err := os.MkdirAll(r, 0700); err != nil {
+		return "", fmt.Errorf("failed to create temp directory: %w", err)
+	}
+	dir, err := os.MkdirTemp(r, sessionPrefix+"*")
+	if err != nil {
+		return "", fmt.Errorf("failed to create temp directory: %w", err)
+	}
+	l, err := lock.TryAcquire(filepath.Join(dir, lockName))
+	if err != nil || l == nil {
+		os.RemoveAll(dir)
+		return "", fmt.Errorf("failed to lock temp directory %s: %v", dir, err)
+	}
+	session, held = dir, l
+	return session, nil
+}
+
+// CreateTemp creates a temporary file in the session directory, see os.CreateTemp
+func CreateTemp(pattern string) (*os.File, error) {
+	dir, err := Dir()
+	if err != nil {
+		return nil, err
+	}
+	return os.CreateTemp(dir, pattern)
+}
+
+// MkdirTemp creates a temporary directory in the session directory, see os.MkdirTemp
+func MkdirTemp(pattern string) (string, error) {
+	dir, err := Dir()
+	if err != nil {
+		return "", err
+	}
+	return os.MkdirTemp(dir, pattern)
+}
+
+// Cleanup removes the session directory with everything left in it
+func Cleanup() error {
+	mu.Lock()
+	defer mu.Unlock()
+	if session == "" {
+		return nil
+	}
+	held.Release()
+	err := os.RemoveAll(session)
+	session, held = "", nil
+	return err
+}
+
+// RecoverStale removes the session directories of processes that ended without
+// cleaning up, and returns them
+func RecoverStale() ([]string, error) {
+	r := Root()
+	entries, err := os.ReadDir(r)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	mu.Lock()
+	own := session
+	mu.Unlock()
+
+	var removed []string
+	for _, entry := range entries {
+		dir := filepath.Join(r, entry.Name())
+		if !entry.IsDir() || !strings.HasPrefix(entry.Name(), sessionPrefix) || dir == own {
+			continue
+		}
+		if info, err := entry.Info(); err != nil || time.Since(info.ModTime()) < recoveryGrace {
+			continue
+		}
+		l, err := lock.TryAcquire(filepath.Join(dir, lockName))
+		if err != nil || l == nil {
+			continue // Still in use
+		}
+		// Windows cannot remove the lock file while it is open
+		l.Release()
+		if err := os.RemoveAll(dir); err == nil {
+			removed = append(removed, dir)
+		}
+	}
+	return removed, nil
+}
@@ -0,0 +1,87 @@
+package tempdir
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/pyhub-kr/pyhub-installer/internal/lock"
+)
+
+func TestSession(t *testing.T) {
+	root := filepath.Join(t.TempDir(), "tmp")
+	Configure(root)
+	defer Configure("")
+	defer Cleanup()
+
+	file, err := CreateTemp("chunk_*")
+	if err != nil {
+		t.Fatalf("CreateTemp() error = %v", err)
+	}
+	file.Close()
+	dir, err := MkdirTemp("staging-*")
+	if err != nil {
+		t.Fatalf("MkdirTemp() error = %v", err)
+	}
+
+	session, _ := Dir()
+	if filepath.Dir(session) != root || filepath.Dir(file.Name()) != session || filepath.Dir(dir) != session {
+		t.Errorf("Expected temporary files in a session under %s, got %s and %s", root, file.Name(), dir)
+	}
+
+	if err := Cleanup(); err != nil {
+		t.Fatalf("Cleanup() error = %v", err)
+	}
+	if _, err := os.Stat(session); !os.IsNotExist(err) {
+		t.Errorf("Expected the session to be removed: %v", err)
+	}
+}
+
+func TestRecoverStale(t *testing.T) {
+	root := t.TempDir()
+	Configure(root)
+	defer Configure("")
+	defer Cleanup()
+
+	old := time.Now().Add(-time.Hour)
+	newSession := func(name string) string {
+		dir := filepath.Join(root, name)
+		if err := os.MkdirAll(dir, 0700); err != nil {
+			t.Fatal(err)
+		}
+		os.WriteFile(filepath.Join(dir, "chunk_1"), []byte("partial"), 0644)
+		os.WriteFile(filepath.Join(dir, lockName), nil, 0644)
+		os.Chtimes(dir, old, old)
+		return dir
+	}
+
+	crashed := newSession("run-crashed")
+	running := newSession("run-running")
+	l, err := lock.TryAcquire(filepath.Join(running, lockName))
+	if err != nil || l == nil {
+		t.Fatalf("TryAcquire() = %v, %v", l, err)
+	}
+	defer l.Release()
+	recent := filepath.Join(root, "run-recent")
+	os.MkdirAll(recent, 0700)
+	other := newSession("unrelated")
+	own, err := Dir()
+	if err != nil {
+		t.Fatal(err)
+	}
+	os.Chtimes(own, old, old)
+
+	removed, err := RecoverStale()
+	if err != nil {
+		t.Fatalf("RecoverStale() error = %v", err)
+	}
+	if len(removed) != 1 || removed[0] != crashed {
+		t.Errorf("RecoverStale() = %v, want [%s]", removed, crashed)
+	}
+	for _, dir := range []string{running, recent, other, own} {
+		if _, err := os.Stat(dir); err != nil {
+			t.Errorf("Expected %s to be kept: %v", dir, err)
+		}
+	}
+}
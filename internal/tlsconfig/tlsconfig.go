@@ -0,0 +1,56 @@
+package tlsconfig
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"os"
+)
+
+// Options are the certificate files of outgoing TLS connections
+type Options struct {
+	CACert     string // PEM bundle of root certificates trusted besides the system roots, e.g. of a TLS-intercepting proxy
+	ClientCert string // PEM client certificate for servers requiring mutual TLS, with its key unless ClientKey is set
+	ClientKey  string // PEM private key of ClientCert
+	Insecure   bool   // Skip verifying server certificates, for internal servers with self-signed ones
+}
+
+// Config returns the TLS settings of opts, or nil if opts sets nothing and the defaults apply
+func Config(opts Options) (*tls.Config, error) {
+	if opts.CACert == "" && opts.ClientCert == "" && opts.ClientKey == "" && !opts.Insecure {
+		return nil, nil
+	}
+	config := &tls.Config{MinVersion: tls.VersionTLS12, InsecureSkipVerify: opts.Insecure}
+
+	if opts.CACert != "" {
+		pem, err := os.ReadFile(opts.CACert)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read CA certificates: %w", err)
+		}
+		pool, err := x509.SystemCertPool()
+		if err != nil {
+			// Without readable system roots only the bundle is trusted
+			pool = x509.NewCertPool()
+		}
+		if !pool.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("no PEM certificates found in %s", opts.CACert)
+		}
+		config.RootCAs = pool
+	}
+
+	if opts.ClientKey != "" && opts.ClientCert == "" {
+		return nil, fmt.Errorf("a client key needs its client certificate")
+	}
+	if opts.ClientCert != "" {
+		key := opts.ClientKey
+		if key == "" {
+			key = opts.ClientCert
+		}
+		cert, err := tls.LoadX509KeyPair(opts.ClientCert, key)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load client certificate: %w", err)
+		}
+		config.Certificates = []tls.Certificate{cert}
+	}
+	return config, nil
+}
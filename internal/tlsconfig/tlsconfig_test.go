@@ -0,0 +1,120 @@
+package tlsconfig
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// writeClientCert writes a self-signed client certificate and its key as PEM files
+func writeClientCert(t *testing.T, dir string) (certFile, keyFile string) {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "client"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	certFile, keyFile = filepath.Join(dir, "client.pem"), filepath.Join(dir, "client.key")
+	os.WriteFile(certFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), 0600)
+	os.WriteFile(keyFile, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER}), 0600)
+	return certFile, keyFile
+}
+
+func TestConfig(t *testing.T) {
+	if config, err := Config(Options{}); config != nil || err != nil {
+		t.Errorf("Expected no settings without options, got %v, %v", config, err)
+	}
+
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if len(r.TLS.PeerCertificates) == 0 {
+			w.WriteHeader(http.StatusUnauthorized)
+		}
+	}))
+	server.TLS = &tls.Config{ClientAuth: tls.RequestClientCert}
+	server.StartTLS()
+	defer server.Close()
+
+	dir := t.TempDir()
+	caFile := filepath.Join(dir, "ca.pem")
+	os.WriteFile(caFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: server.Certificate().Raw}), 0644)
+	certFile, keyFile := writeClientCert(t, dir)
+
+	get := func(opts Options) (int, error) {
+		config, err := Config(opts)
+		if err != nil {
+			t.Fatalf("Config() error = %v", err)
+		}
+		client := &http.Client{Transport: &http.Transport{TLSClientConfig: config}}
+		resp, err := client.Get(server.URL)
+		if err != nil {
+			return 0, err
+		}
+		resp.Body.Close()
+		return resp.StatusCode, nil
+	}
+
+	if _, err := get(Options{ClientCert: certFile, ClientKey: keyFile}); err == nil {
+		t.Error("Expected the test server's certificate to be untrusted without the CA bundle")
+	}
+	if status, err := get(Options{Insecure: true}); err != nil || status != http.StatusUnauthorized {
+		t.Errorf("Expected the certificate to be accepted unverified, got %d, %v", status, err)
+	}
+	if status, err := get(Options{CACert: caFile}); err != nil || status != http.StatusUnauthorized {
+		t.Errorf("Expected a trusted connection without client certificate, got %d, %v", status, err)
+	}
+	if status, err := get(Options{CACert: caFile, ClientCert: certFile, ClientKey: keyFile}); err != nil || status != http.StatusOK {
+		t.Errorf("Expected the client certificate to be presented, got %d, %v", status, err)
+	}
+
+	// The key may be in the certificate file
+	combined := filepath.Join(dir, "combined.pem")
+	certPEM, _ := os.ReadFile(certFile)
+	keyPEM, _ := os.ReadFile(keyFile)
+	os.WriteFile(combined, append(certPEM, keyPEM...), 0600)
+	if config, err := Config(Options{ClientCert: combined}); err != nil || len(config.Certificates) != 1 {
+		t.Errorf("Expected the key from the certificate file, got %v", err)
+	}
+}
+
+func TestConfigErrors(t *testing.T) {
+	dir := t.TempDir()
+	notPEM := filepath.Join(dir, "ca.pem")
+	os.WriteFile(notPEM, []byte("not a certificate"), 0644)
+	certFile, _ := writeClientCert(t, dir)
+
+	for name, opts := range map[string]Options{
+		"missing CA file":     {CACert: filepath.Join(dir, "missing.pem")},
+		"CA file without PEM": {CACert: notPEM},
+		"key without cert":    {ClientKey: certFile},
+		"cert without key":    {ClientCert: certFile},
+	} {
+		if _, err := Config(opts); err == nil {
+			t.Errorf("%s: expected error", name)
+		}
+	}
+}
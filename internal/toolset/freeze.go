@@ -0,0 +1,66 @@
+package toolset
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/pyhub-kr/pyhub-installer/internal/state"
+)
+
+// Freeze describes installed tools as a project manifest pinning each to its installed
+// version, and a lockfile pinning the assets installed on platform. The most common
+// install directory becomes the manifest's default; paths under the home directory are
+// written with ~ so the manifest works for other users.
+func Freeze(receipts []state.Receipt, platform string) (*File, *Lock) {
+	file := &File{}
+	lock := &Lock{Platform: platform}
+
+	counts := make(map[string]int)
+	for _, r := range receipts {
+		if r.Source != "" {
+			counts[r.InstallPath]++
+		}
+	}
+	for dir, n := range counts {
+		if n > counts[file.Output] || (n == counts[file.Output] && dir < file.Output) {
+			file.Output = dir
+		}
+	}
+
+	for _, r := range receipts {
+		if r.Source == "" {
+			continue
+		}
+		tool := Tool{Source: r.Source, Version: r.Version}
+		if r.InstallPath != file.Output {
+			tool.Output = homeRelative(r.InstallPath)
+		}
+		file.Tools = append(file.Tools, tool)
+		lock.Tools = append(lock.Tools, LockedTool{
+			Source:  r.Source,
+			Version: r.Version,
+			Asset:   r.Asset,
+			URL:     r.URL,
+			SHA256:  r.SHA256,
+		})
+	}
+	file.Output = homeRelative(file.Output)
+	return file, lock
+}
+
+// homeRelative writes a path under the home directory as ~/path
+func homeRelative(path string) string {
+	home, err := os.UserHomeDir()
+	if err != nil || path == "" {
+		return path
+	}
+	rel, err := filepath.Rel(home, path)
+	if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return path
+	}
+	if rel == "." {
+		return "~"
+	}
+	return "~/" + filepath.ToSlash(rel)
+}
@@ -0,0 +1,57 @@
+package toolset
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/pyhub-kr/pyhub-installer/internal/state"
+)
+
+func TestFreeze(t *testing.T) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		t.Skip("no home directory")
+	}
+	bin := filepath.Join(home, ".local", "bin")
+	receipts := []state.Receipt{
+		{Name: "gh", Source: "github:cli/cli", Version: "v2.62.0", Asset: "gh_2.62.0_linux_amd64.tar.gz", SHA256: "aaa", InstallPath: bin},
+		{Name: "legacy", Version: "v1.0.0", InstallPath: bin},
+		{Name: "rg", Source: "github:BurntSushi/ripgrep", Version: "14.1.0", Asset: "ripgrep.tar.gz", SHA256: "bbb", InstallPath: bin},
+		{Name: "tool", Source: "url:https://example.com/tool-{version}.zip", Version: "1.0.0", Asset: "tool.zip", InstallPath: "/opt/tools"},
+	}
+
+	file, lock := Freeze(receipts, "linux-amd64")
+	if file.Output != "~/.local/bin" {
+		t.Errorf("Expected the most common directory as default output, got %s", file.Output)
+	}
+	if len(file.Tools) != 3 || len(lock.Tools) != 3 {
+		t.Fatalf("Expected receipts without a source to be skipped, got %+v", file.Tools)
+	}
+	if tool := file.Tools[0]; tool.Source != "github:cli/cli" || tool.Version != "v2.62.0" || tool.Output != "" {
+		t.Errorf("Unexpected tool: %+v", tool)
+	}
+	if tool := file.Tools[2]; tool.Output != "/opt/tools" {
+		t.Errorf("Expected own output for a tool outside the default directory, got %+v", tool)
+	}
+	if lock.Platform != "linux-amd64" || lock.Tools[1].Asset != "ripgrep.tar.gz" || lock.Tools[1].SHA256 != "bbb" {
+		t.Errorf("Unexpected lockfile: %+v", lock)
+	}
+
+	// The frozen manifest must be accepted by sync
+	data, err := file.Marshal()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(data), "\n  - source: github:cli/cli\n") {
+		t.Errorf("Expected two-space indentation:\n%s", data)
+	}
+	parsed, err := Parse(data)
+	if err != nil {
+		t.Fatalf("Parse() of the frozen manifest error = %v\n%s", err, data)
+	}
+	if len(parsed.Tools) != 3 || parsed.Tools[1].Version != "14.1.0" {
+		t.Errorf("Unexpected parsed manifest: %+v", parsed.Tools)
+	}
+}
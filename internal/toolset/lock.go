@@ -0,0 +1,91 @@
+package toolset
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/pyhub-kr/pyhub-installer/internal/semver"
+	"gopkg.in/yaml.v3"
+)
+
+// LockedTool pins a tool of a project manifest to the exact release and asset
+// installed when the lockfile was written
+type LockedTool struct {
+	Source  string `yaml:"source"`
+	Version string `yaml:"version"` // Release tag
+	Asset   string `yaml:"asset"`
+	URL     string `yaml:"url,omitempty"`
+	SHA256  string `yaml:"sha256,omitempty"`
+}
+
+// Lock is a lockfile next to a project manifest. Assets are platform specific, so they
+// are only pinned when installing for the platform the lockfile was written on.
+type Lock struct {
+	Platform string       `yaml:"platform"`
+	Tools    []LockedTool `yaml:"tools"`
+}
+
+// LockPath returns the lockfile of a manifest: pyhub-tools.lock for pyhub-tools.yaml
+func LockPath(manifest string) string {
+	return strings.TrimSuffix(manifest, filepath.Ext(manifest)) + ".lock"
+}
+
+// LoadLock reads a lockfile, returning nil without error if it does not exist
+func LoadLock(path string) (*Lock, error) {
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read lockfile: %w", err)
+	}
+
+	var lock Lock
+	if err := yaml.Unmarshal(data, &lock); err != nil {
+		return nil, fmt.Errorf("invalid lockfile %s: %w", path, err)
+	}
+	for i, tool := range lock.Tools {
+		if tool.Source == "" || tool.Version == "" {
+			return nil, fmt.Errorf("invalid lockfile %s: tools[%d]: source and version are required", path, i)
+		}
+	}
+	return &lock, nil
+}
+
+// Marshal encodes the lockfile as YAML
+func (l *Lock) Marshal() ([]byte, error) {
+	return marshal(l)
+}
+
+// Find returns the locked release of a source, or nil
+func (l *Lock) Find(source string) *LockedTool {
+	if l == nil {
+		return nil
+	}
+	for i := range l.Tools {
+		if l.Tools[i].Source == source {
+			return &l.Tools[i]
+		}
+	}
+	return nil
+}
+
+// Allows reports whether a locked release tag satisfies a manifest version: any tag
+// for "latest", a matching tag for a constraint, otherwise the same version with or
+// without a leading v. A lock entry the manifest no longer allows is stale.
+func Allows(version, tag string) bool {
+	if version == "" || version == "latest" {
+		return true
+	}
+	if !semver.IsConstraint(version) {
+		return strings.TrimPrefix(version, "v") == strings.TrimPrefix(tag, "v")
+	}
+	constraint, err := semver.ParseConstraint(version)
+	if err != nil {
+		return false
+	}
+	v, err := semver.Parse(tag)
+	return err == nil && constraint.Check(v)
+}
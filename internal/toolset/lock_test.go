@@ -0,0 +1,71 @@
+package toolset
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestLockRoundTrip(t *testing.T) {
+	dir := t.TempDir()
+	path := LockPath(filepath.Join(dir, DefaultFile))
+	if filepath.Base(path) != "pyhub-tools.lock" {
+		t.Fatalf("LockPath() = %s", path)
+	}
+
+	if lock, err := LoadLock(path); lock != nil || err != nil {
+		t.Fatalf("LoadLock() of a missing file = %v, %v", lock, err)
+	}
+
+	lock := &Lock{Platform: "linux-amd64", Tools: []LockedTool{
+		{Source: "github:BurntSushi/ripgrep", Version: "14.1.0", Asset: "ripgrep-14.1.0-x86_64-unknown-linux-musl.tar.gz", SHA256: "abc123"},
+	}}
+	data, err := lock.Marshal()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	loaded, err := LoadLock(path)
+	if err != nil {
+		t.Fatalf("LoadLock() error = %v", err)
+	}
+	if loaded.Platform != "linux-amd64" || len(loaded.Tools) != 1 {
+		t.Fatalf("Unexpected lockfile: %+v", loaded)
+	}
+	if locked := loaded.Find("github:BurntSushi/ripgrep"); locked == nil || locked.SHA256 != "abc123" {
+		t.Errorf("Find() = %+v", locked)
+	}
+	if loaded.Find("github:cli/cli") != nil {
+		t.Error("Expected nil for a source that is not locked")
+	}
+
+	if err := os.WriteFile(path, []byte("tools:\n  - source: rg\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := LoadLock(path); err == nil {
+		t.Error("Expected error for a locked tool without a version")
+	}
+}
+
+func TestAllows(t *testing.T) {
+	tests := []struct {
+		version, tag string
+		want         bool
+	}{
+		{"latest", "v2.62.0", true},
+		{"", "v2.62.0", true},
+		{"v2.62.0", "2.62.0", true},
+		{"v2.62.0", "v2.63.0", false},
+		{"^2", "v2.62.0", true},
+		{"^2", "v3.0.0", false},
+		{"^2", "nightly", false},
+	}
+	for _, tt := range tests {
+		if got := Allows(tt.version, tt.tag); got != tt.want {
+			t.Errorf("Allows(%q, %q) = %v, want %v", tt.version, tt.tag, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,146 @@
+package toolset
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/pyhub-kr/pyhub-installer/internal/lifecycle"
+	"github.com/pyhub-kr/pyhub-installer/internal/urltemplate"
+	"gopkg.in/yaml.v3"
+)
+
+// DefaultFile is the project manifest looked up by the sync command
+const DefaultFile = "pyhub-tools.yaml"
+
+// Tool is one tool declared in a project manifest
+type Tool struct {
+	Source       string           `yaml:"source,omitempty"`       // Source or alias, e.g. github:cli/cli or rg
+	URL          string           `yaml:"url,omitempty"`          // Download URL template, instead of a source
+	Version      string           `yaml:"version,omitempty"`      // Exact version, constraint, or "latest" (the default)
+	Asset        string           `yaml:"asset,omitempty"`        // Asset name pattern, overriding platform detection
+	Output       string           `yaml:"output,omitempty"`       // Install directory, overriding the file's default
+	Deprecations []lifecycle.Rule `yaml:"deprecations,omitempty"` // Versions marked deprecated, yanked or EOL
+}
+
+// File is a project manifest declaring the tools a project needs
+type File struct {
+	Output string `yaml:"output,omitempty"` // Default install directory
+	Tools  []Tool `yaml:"tools"`
+}
+
+// Load reads a project manifest. Relative install directories are resolved against the
+// manifest's directory, and a leading ~ against the home directory.
+func Load(path string) (*File, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read tool manifest: %w", err)
+	}
+
+	file, err := Parse(data)
+	if err != nil {
+		return nil, fmt.Errorf("invalid tool manifest %s: %w", path, err)
+	}
+
+	dir := filepath.Dir(path)
+	if file.Output, err = resolvePath(dir, file.Output); err != nil {
+		return nil, err
+	}
+	for i := range file.Tools {
+		if file.Tools[i].Output, err = resolvePath(dir, file.Tools[i].Output); err != nil {
+			return nil, err
+		}
+	}
+	return file, nil
+}
+
+// Parse decodes and validates a project manifest
+func Parse(data []byte) (*File, error) {
+	// Unknown keys are rejected so that typos such as "verison" are not ignored
+	var file File
+	decoder := yaml.NewDecoder(bytes.NewReader(data))
+	decoder.KnownFields(true)
+	if err := decoder.Decode(&file); err != nil && err != io.EOF {
+		return nil, err
+	}
+
+	seen := make(map[string]bool)
+	for i, tool := range file.Tools {
+		if tool.URL != "" {
+			if tool.Source != "" {
+				return nil, fmt.Errorf("tools[%d]: source and url are mutually exclusive", i)
+			}
+			if err := urltemplate.Validate(tool.URL); err != nil {
+				return nil, fmt.Errorf("tools[%d]: %w", i, err)
+			}
+			if urltemplate.Uses(tool.URL, urltemplate.Version) && (tool.Version == "" || tool.Version == "latest") {
+				return nil, fmt.Errorf("tools[%d]: url uses {version}, so an exact version is required", i)
+			}
+			tool.Source = "url:" + tool.URL
+			file.Tools[i].Source = tool.Source
+		}
+		if strings.TrimSpace(tool.Source) == "" {
+			return nil, fmt.Errorf("tools[%d]: source or url is required", i)
+		}
+		if seen[tool.Source] {
+			return nil, fmt.Errorf("tools[%d]: %s is declared twice", i, tool.Source)
+		}
+		seen[tool.Source] = true
+		for j, rule := range tool.Deprecations {
+			if err := rule.Validate(); err != nil {
+				return nil, fmt.Errorf("tools[%d].deprecations[%d]: %w", i, j, err)
+			}
+		}
+		if tool.Version == "" {
+			file.Tools[i].Version = "latest"
+		}
+	}
+	return &file, nil
+}
+
+// Marshal encodes the manifest as YAML
+func (f *File) Marshal() ([]byte, error) {
+	return marshal(f)
+}
+
+// marshal encodes YAML indented by two spaces, as manifests are usually written
+func marshal(v any) ([]byte, error) {
+	var buf bytes.Buffer
+	encoder := yaml.NewEncoder(&buf)
+	encoder.SetIndent(2)
+	if err := encoder.Encode(v); err != nil {
+		return nil, err
+	}
+	if err := encoder.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// OutputFor returns the install directory of a tool, or "" for the installer default
+func (f *File) OutputFor(tool Tool) string {
+	if tool.Output != "" {
+		return tool.Output
+	}
+	return f.Output
+}
+
+// resolvePath makes a manifest path absolute; empty paths stay empty
+func resolvePath(dir, path string) (string, error) {
+	switch {
+	case path == "":
+		return "", nil
+	case path == "~" || strings.HasPrefix(path, "~/"):
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return "", fmt.Errorf("failed to get user home directory: %w", err)
+		}
+		return filepath.Join(home, path[1:]), nil
+	case filepath.IsAbs(path):
+		return path, nil
+	}
+	return filepath.Abs(filepath.Join(dir, path))
+}
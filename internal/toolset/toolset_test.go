@@ -0,0 +1,84 @@
+package toolset
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+	file, err := Parse([]byte(`
+output: ./bin
+tools:
+  - source: rg
+  - source: github:cli/cli
+    version: "^2"
+    asset: "gh_*_linux_amd64.tar.gz"
+    output: /opt/tools
+    deprecations:
+      - versions: "<2.40"
+        status: eol
+        message: upgrade to v2.40 or later
+  - url: https://example.com/tool-{version}-{os}-{arch}.tar.gz
+    version: 1.4.0
+`))
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+	if len(file.Tools) != 3 {
+		t.Fatalf("Expected 3 tools, got %d", len(file.Tools))
+	}
+	if file.Tools[0].Version != "latest" {
+		t.Errorf("Expected default version latest, got %s", file.Tools[0].Version)
+	}
+	if file.OutputFor(file.Tools[0]) != "./bin" || file.OutputFor(file.Tools[1]) != "/opt/tools" {
+		t.Errorf("Unexpected outputs: %s, %s", file.OutputFor(file.Tools[0]), file.OutputFor(file.Tools[1]))
+	}
+	if file.Tools[2].Source != "url:https://example.com/tool-{version}-{os}-{arch}.tar.gz" {
+		t.Errorf("Expected the url to become a url: source, got %s", file.Tools[2].Source)
+	}
+	if deps := file.Tools[1].Deprecations; len(deps) != 1 || deps[0].Status != "eol" || deps[0].Versions != "<2.40" {
+		t.Errorf("Unexpected deprecations: %+v", deps)
+	}
+
+	invalid := map[string]string{
+		"missing source":      "tools:\n  - version: v1.0.0\n",
+		"source and url":      "tools:\n  - source: rg\n    url: https://example.com/rg.zip\n",
+		"url without version": "tools:\n  - url: https://example.com/tool-{version}.zip\n",
+		"unknown placeholder": "tools:\n  - url: https://example.com/tool-{ver}.zip\n    version: 1.0.0\n",
+		"duplicate":           "tools:\n  - source: rg\n  - source: rg\n",
+		"bad yaml":            "tools: [",
+		"unknown key":         "tools:\n  - source: rg\n    verison: v14\n",
+		"unknown status":      "tools:\n  - source: rg\n    deprecations:\n      - versions: v1\n        status: retired\n",
+	}
+	for name, content := range invalid {
+		if _, err := Parse([]byte(content)); err == nil {
+			t.Errorf("%s: expected error", name)
+		}
+	}
+}
+
+func TestLoadResolvesPaths(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, DefaultFile)
+	content := "output: bin\ntools:\n  - source: rg\n  - source: fd\n    output: ~/tools\n"
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	file, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if file.Output != filepath.Join(dir, "bin") {
+		t.Errorf("Expected output relative to the manifest, got %s", file.Output)
+	}
+	home, _ := os.UserHomeDir()
+	if file.Tools[1].Output != filepath.Join(home, "tools") {
+		t.Errorf("Expected ~ to expand to the home directory, got %s", file.Tools[1].Output)
+	}
+
+	if _, err := Load(filepath.Join(dir, "missing.yaml")); err == nil {
+		t.Error("Expected error for missing manifest")
+	}
+}
@@ -0,0 +1,118 @@
+package treediff
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+)
+
+// Entry describes a file of a tree
+type Entry struct {
+	Size   int64
+	Mode   fs.FileMode
+	SHA256 string // Of regular files
+	Link   string // Target of symbolic links
+}
+
+// Manifest maps slash-separated paths relative to the tree root to their entries.
+// Directories are not listed.
+type Manifest map[string]Entry
+
+// Kind is the kind of a change
+type Kind string
+
+const (
+	Added   Kind = "added"
+	Removed Kind = "removed"
+	Changed Kind = "changed"
+)
+
+// Change is a file that differs between two trees
+type Change struct {
+	Path string
+	Kind Kind
+	Old  *Entry // nil if added
+	New  *Entry // nil if removed
+}
+
+// Scan hashes every file under root; symbolic links are recorded, not followed
+func Scan(root string) (Manifest, error) {
+	manifest := make(Manifest)
+	err := filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			return nil
+		}
+		rel, err := filepath.Rel(root, path)
+		if err != nil {
+			return err
+		}
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+
+		entry := Entry{Size: info.Size(), Mode: info.Mode()}
+		switch {
+		case info.Mode()&fs.ModeSymlink != 0:
+			if entry.Link, err = os.Readlink(path); err != nil {
+				return err
+			}
+		case info.Mode().IsRegular():
+			if entry.SHA256, err = hashFile(path); err != nil {
+				return err
+			}
+		}
+		manifest[filepath.ToSlash(rel)] = entry
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to scan %s: %w", root, err)
+	}
+	return manifest, nil
+}
+
+// hashFile returns the SHA256 hash of a file
+func hashFile(path string) (string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+	hash := sha256.New()
+	if _, err := io.Copy(hash, f); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}
+
+// Compare returns the files added, removed or changed from old to new, sorted by
+// path. Files differ in content, link target or permissions.
+func Compare(old, new Manifest) []Change {
+	var changes []Change
+	for path, o := range old {
+		o := o
+		n, ok := new[path]
+		switch {
+		case !ok:
+			changes = append(changes, Change{Path: path, Kind: Removed, Old: &o})
+		case o.SHA256 != n.SHA256 || o.Link != n.Link || o.Mode != n.Mode:
+			changes = append(changes, Change{Path: path, Kind: Changed, Old: &o, New: &n})
+		}
+	}
+	for path, n := range new {
+		n := n
+		if _, ok := old[path]; !ok {
+			changes = append(changes, Change{Path: path, Kind: Added, New: &n})
+		}
+	}
+	sort.Slice(changes, func(i, j int) bool { return changes[i].Path < changes[j].Path })
+	return changes
+}
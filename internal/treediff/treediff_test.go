@@ -0,0 +1,89 @@
+package treediff
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+)
+
+// writeTree creates files with the given contents under a new directory
+func writeTree(t *testing.T, files map[string]string) string {
+	t.Helper()
+	root := t.TempDir()
+	for name, content := range files {
+		path := filepath.Join(root, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return root
+}
+
+func TestScanAndCompare(t *testing.T) {
+	oldRoot := writeTree(t, map[string]string{
+		"bin/tool":       "v1 binary",
+		"README.md":      "readme",
+		"doc/CHANGES.md": "old changes",
+	})
+	newRoot := writeTree(t, map[string]string{
+		"bin/tool":         "v2 binary",
+		"README.md":        "readme",
+		"bin/tool-helper":  "helper",
+		"complete/tool.sh": "complete",
+	})
+
+	old, err := Scan(oldRoot)
+	if err != nil {
+		t.Fatalf("Scan() error = %v", err)
+	}
+	if entry := old["bin/tool"]; entry.Size != 9 || len(entry.SHA256) != 64 {
+		t.Errorf("Unexpected entry: %+v", entry)
+	}
+	new, err := Scan(newRoot)
+	if err != nil {
+		t.Fatalf("Scan() error = %v", err)
+	}
+
+	changes := Compare(old, new)
+	want := []struct {
+		path string
+		kind Kind
+	}{
+		{"bin/tool", Changed},
+		{"bin/tool-helper", Added},
+		{"complete/tool.sh", Added},
+		{"doc/CHANGES.md", Removed},
+	}
+	if len(changes) != len(want) {
+		t.Fatalf("Compare() = %+v", changes)
+	}
+	for i, w := range want {
+		if changes[i].Path != w.path || changes[i].Kind != w.kind {
+			t.Errorf("Change %d = %s %s, want %s %s", i, changes[i].Kind, changes[i].Path, w.kind, w.path)
+		}
+	}
+	if changes[0].Old.SHA256 == changes[0].New.SHA256 {
+		t.Error("Expected different hashes for a changed file")
+	}
+}
+
+func TestScanSymlink(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("symlinks need privileges on Windows")
+	}
+	root := writeTree(t, map[string]string{"bin/tool-1.0": "binary"})
+	if err := os.Symlink("tool-1.0", filepath.Join(root, "bin", "tool")); err != nil {
+		t.Fatal(err)
+	}
+	manifest, err := Scan(root)
+	if err != nil {
+		t.Fatalf("Scan() error = %v", err)
+	}
+	if entry := manifest["bin/tool"]; entry.Link != "tool-1.0" || entry.SHA256 != "" {
+		t.Errorf("Expected the link to be recorded, not followed: %+v", entry)
+	}
+}
@@ -0,0 +1,254 @@
+package ui
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/schollz/progressbar/v3"
+	"golang.org/x/term"
+)
+
+// ciVariables are set by common CI systems
+var ciVariables = []string{"CI", "GITHUB_ACTIONS", "GITLAB_CI", "BUILDKITE", "JENKINS_URL", "TF_BUILD", "TEAMCITY_VERSION"}
+
+// Options controls how output is decorated
+type Options struct {
+	NoColor    bool // Never print ANSI color codes
+	NoProgress bool // Print percentage lines instead of progress bars
+}
+
+var (
+	colorEnabled    = false
+	progressEnabled = true
+)
+
+// Configure sets the output mode. Colors need stdout to be a terminal and no NO_COLOR
+// variable; progress bars need stderr to be a terminal outside CI.
+func Configure(opts Options) {
+	colorEnabled = !opts.NoColor && IsTerminal(os.Stdout) && os.Getenv("NO_COLOR") == ""
+	progressEnabled = !opts.NoProgress && IsTerminal(os.Stderr) && !IsCI(os.Getenv)
+}
+
+// IsTerminal reports whether f is an interactive terminal
+func IsTerminal(f *os.File) bool {
+	return term.IsTerminal(int(f.Fd()))
+}
+
+// IsCI reports whether the process runs in a CI system
+func IsCI(getenv func(string) string) bool {
+	for _, name := range ciVariables {
+		if value := getenv(name); value != "" && value != "false" && value != "0" {
+			return true
+		}
+	}
+	return false
+}
+
+// ANSI color codes
+const (
+	green  = "\033[32m"
+	yellow = "\033[33m"
+	red    = "\033[31m"
+	reset  = "\033[0m"
+)
+
+// colorize wraps s in a color code when colors are enabled
+func colorize(color, s string) string {
+	if !colorEnabled {
+		return s
+	}
+	return color + s + reset
+}
+
+// Success formats a success marker or message
+func Success(s string) string {
+	return colorize(green, s)
+}
+
+// Warn formats a warning
+func Warn(s string) string {
+	return colorize(yellow, s)
+}
+
+// Failure formats a failure marker or message
+func Failure(s string) string {
+	return colorize(red, s)
+}
+
+// NewProgress returns a writer reporting download progress: an animated progress bar
+// in a terminal, otherwise periodic percentage lines on stderr. size is -1 if unknown.
+func NewProgress(size int64, description string) io.Writer {
+	if progressEnabled {
+		return progressbar.DefaultBytes(size, description)
+	}
+	return NewLineProgress(os.Stderr, size, description)
+}
+
+// LineProgress prints a line per 10% of progress, or every 5 seconds if the size is
+// unknown, so logs are not filled with control characters
+type LineProgress struct {
+	out         io.Writer
+	size        int64
+	description string
+	interval    time.Duration
+	now         func() time.Time
+
+	mu          sync.Mutex
+	written     int64
+	lastPercent int64
+	lastPrint   time.Time
+}
+
+// NewLineProgress creates a line-based progress writer
+func NewLineProgress(out io.Writer, size int64, description string) *LineProgress {
+	return &LineProgress{
+		out:         out,
+		size:        size,
+		description: description,
+		interval:    5 * time.Second,
+		now:         time.Now,
+		lastPercent: -1,
+	}
+}
+
+// Write records n bytes of progress and prints a line when the next step is reached
+func (p *LineProgress) Write(b []byte) (int, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.written += int64(len(b))
+	if p.size > 0 {
+		percent := min(p.written*100/p.size, 100) / 10 * 10
+		if percent > p.lastPercent {
+			p.lastPercent = percent
+			fmt.Fprintf(p.out, "%s: %d%% (%s / %s)\n", p.description, percent, formatBytes(p.written), formatBytes(p.size))
+		}
+		return len(b), nil
+	}
+
+	if now := p.now(); now.Sub(p.lastPrint) >= p.interval {
+		p.lastPrint = now
+		fmt.Fprintf(p.out, "%s: %s\n", p.description, formatBytes(p.written))
+	}
+	return len(b), nil
+}
+
+// formatBytes formats a byte count with a binary unit
+func formatBytes(n int64) string {
+	const unit = 1024
+	if n < unit {
+		return fmt.Sprintf("%d B", n)
+	}
+	div, exp := int64(unit), 0
+	for m := n / unit; m >= unit; m /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %ciB", float64(n)/float64(div), "KMGTPE"[exp])
+}
+
+// MultiProgress shows the progress of several concurrent downloads in one display: a
+// line per download redrawn in place in a terminal, otherwise percentage lines
+type MultiProgress struct {
+	out      io.Writer
+	live     bool
+	interval time.Duration
+	now      func() time.Time
+
+	mu         sync.Mutex
+	bars       []*multiBar
+	drawn      int // Lines drawn by the last render
+	lastRender time.Time
+}
+
+// multiBar is one download of a MultiProgress
+type multiBar struct {
+	parent      *MultiProgress
+	description string
+	size        int64
+	written     int64
+	line        *LineProgress // Used instead of redrawing when not live
+}
+
+// NewMultiProgress creates a display on stderr
+func NewMultiProgress() *MultiProgress {
+	return newMultiProgress(os.Stderr, progressEnabled)
+}
+
+// newMultiProgress creates a display redrawing lines in place if live is set
+func newMultiProgress(out io.Writer, live bool) *MultiProgress {
+	return &MultiProgress{out: out, live: live, interval: 100 * time.Millisecond, now: time.Now}
+}
+
+// Add adds a download to the display; size is -1 if unknown
+func (m *MultiProgress) Add(description string, size int64) io.Writer {
+	bar := &multiBar{parent: m, description: description, size: size}
+	if !m.live {
+		bar.line = NewLineProgress(m.out, size, description)
+	}
+
+	m.mu.Lock()
+	m.bars = append(m.bars, bar)
+	m.mu.Unlock()
+	return bar
+}
+
+// Write records progress of the download
+func (b *multiBar) Write(p []byte) (int, error) {
+	if b.line != nil {
+		return b.line.Write(p)
+	}
+
+	m := b.parent
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	b.written += int64(len(p))
+	if now := m.now(); now.Sub(m.lastRender) >= m.interval {
+		m.lastRender = now
+		m.render()
+	}
+	return len(p), nil
+}
+
+// Finish draws the final state of every download
+func (m *MultiProgress) Finish() {
+	if !m.live {
+		return
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.render()
+}
+
+// render redraws all lines, moving the cursor up over the previous render
+func (m *MultiProgress) render() {
+	if m.drawn > 0 {
+		fmt.Fprintf(m.out, "\033[%dA", m.drawn)
+	}
+	width := 0
+	for _, bar := range m.bars {
+		width = max(width, len(bar.description))
+	}
+	for _, bar := range m.bars {
+		fmt.Fprintf(m.out, "\r\033[K%-*s %s\n", width, bar.description, bar.status())
+	}
+	m.drawn = len(m.bars)
+}
+
+// barWidth is the number of cells of a progress bar
+const barWidth = 30
+
+// status formats the bar and byte counts of a download
+func (b *multiBar) status() string {
+	if b.size <= 0 {
+		return formatBytes(b.written)
+	}
+	written := min(b.written, b.size)
+	filled := int(written * barWidth / b.size)
+	return fmt.Sprintf("[%s%s] %3d%% %s / %s", strings.Repeat("=", filled), strings.Repeat(" ", barWidth-filled),
+		written*100/b.size, formatBytes(written), formatBytes(b.size))
+}
@@ -0,0 +1,123 @@
+package ui
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestIsCI(t *testing.T) {
+	tests := []struct {
+		name string
+		env  map[string]string
+		want bool
+	}{
+		{"Local", nil, false},
+		{"Generic CI", map[string]string{"CI": "true"}, true},
+		{"GitHub Actions", map[string]string{"GITHUB_ACTIONS": "true"}, true},
+		{"Disabled", map[string]string{"CI": "false"}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := IsCI(func(name string) string { return tt.env[name] }); got != tt.want {
+				t.Errorf("IsCI() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestColorize(t *testing.T) {
+	defer func(enabled bool) { colorEnabled = enabled }(colorEnabled)
+
+	colorEnabled = false
+	if got := Success("✓"); got != "✓" {
+		t.Errorf("Expected plain text without colors, got %q", got)
+	}
+
+	colorEnabled = true
+	if got := Failure("✗"); got != red+"✗"+reset {
+		t.Errorf("Expected red text, got %q", got)
+	}
+}
+
+func TestLineProgress(t *testing.T) {
+	var out bytes.Buffer
+	p := NewLineProgress(&out, 1000, "Downloading tool")
+
+	for i := 0; i < 20; i++ {
+		p.Write(make([]byte, 50))
+	}
+
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	if len(lines) != 11 {
+		t.Fatalf("Expected a line per 10%% from 0%% to 100%%, got %d:\n%s", len(lines), out.String())
+	}
+	if lines[10] != "Downloading tool: 100% (1000 B / 1000 B)" {
+		t.Errorf("Unexpected last line: %q", lines[10])
+	}
+	if strings.ContainsAny(out.String(), "\r\033") {
+		t.Error("Line progress must not contain control characters")
+	}
+}
+
+func TestLineProgressUnknownSize(t *testing.T) {
+	var out bytes.Buffer
+	now := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	p := NewLineProgress(&out, -1, "Downloading tool")
+	p.now = func() time.Time { return now }
+
+	p.Write(make([]byte, 2048))
+	p.Write(make([]byte, 2048))
+	now = now.Add(5 * time.Second)
+	p.Write(make([]byte, 2048))
+
+	want := "Downloading tool: 2.0 KiB\nDownloading tool: 6.0 KiB\n"
+	if out.String() != want {
+		t.Errorf("Expected a line per interval, got %q", out.String())
+	}
+}
+
+func TestMultiProgressLive(t *testing.T) {
+	var out bytes.Buffer
+	m := newMultiProgress(&out, true)
+	now := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	m.now = func() time.Time { return now }
+
+	a := m.Add("a.zip", 100)
+	b := m.Add("bb.tar.gz", -1)
+	a.Write(make([]byte, 50))
+	b.Write(make([]byte, 10)) // Within the render interval: not drawn
+
+	now = now.Add(time.Second)
+	a.Write(make([]byte, 50))
+	m.Finish()
+
+	lines := strings.Split(out.String(), "\n")
+	if len(lines) != 7 {
+		t.Fatalf("Expected three renders of two lines, got %q", out.String())
+	}
+	if !strings.HasPrefix(lines[2], "\033[2A") {
+		t.Errorf("Expected the second render to move up two lines, got %q", lines[2])
+	}
+	if !strings.Contains(lines[4], "a.zip     [==============================] 100% 100 B / 100 B") {
+		t.Errorf("Unexpected line %q", lines[4])
+	}
+	if !strings.HasSuffix(lines[5], "bb.tar.gz 10 B") {
+		t.Errorf("Unexpected line %q", lines[5])
+	}
+}
+
+func TestMultiProgressLines(t *testing.T) {
+	var out bytes.Buffer
+	m := newMultiProgress(&out, false)
+
+	m.Add("a.zip", 100).Write(make([]byte, 100))
+	m.Add("b.zip", 100).Write(make([]byte, 50))
+	m.Finish()
+
+	want := "a.zip: 100% (100 B / 100 B)\nb.zip: 50% (50 B / 100 B)\n"
+	if out.String() != want {
+		t.Errorf("Expected %q, got %q", want, out.String())
+	}
+}
@@ -0,0 +1,110 @@
+package update
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/pyhub-kr/pyhub-installer/internal/semver"
+)
+
+// Owner and Repo name the repository whose releases are checked
+const (
+	Owner = "pyhub-kr"
+	Repo  = "pyhub-installer"
+)
+
+// DefaultInterval is how long a check result is reused
+const DefaultInterval = 24 * time.Hour
+
+// cacheEntry is the stored result of the last check
+type cacheEntry struct {
+	CheckedAt time.Time `json:"checked_at"`
+	Latest    string    `json:"latest"`
+}
+
+// Checker finds out whether a newer release exists, querying at most once per interval
+type Checker struct {
+	CachePath string
+	Interval  time.Duration
+	Latest    func() (string, error) // Returns the tag of the newest release
+	Now       func() time.Time
+}
+
+// NewChecker creates a checker caching its result in cachePath
+func NewChecker(cachePath string, latest func() (string, error)) *Checker {
+	return &Checker{
+		CachePath: cachePath,
+		Interval:  DefaultInterval,
+		Latest:    latest,
+		Now:       time.Now,
+	}
+}
+
+// Check returns the newest release tag if it is newer than current, or "". Development
+// builds, whose version is not a semantic version, are never reported as outdated.
+func (c *Checker) Check(current string) (string, error) {
+	currentVersion, err := semver.Parse(current)
+	if err != nil {
+		return "", nil
+	}
+
+	latest, err := c.latest()
+	if err != nil || latest == "" {
+		return "", err
+	}
+	latestVersion, err := semver.Parse(latest)
+	if err != nil {
+		return "", fmt.Errorf("invalid release tag %q: %w", latest, err)
+	}
+	if latestVersion.Compare(currentVersion) > 0 {
+		return latest, nil
+	}
+	return "", nil
+}
+
+// latest returns the cached release tag, refreshing it once the interval has passed.
+// Failed queries are recorded too, so an offline machine is not slowed down by every command.
+func (c *Checker) latest() (string, error) {
+	var entry cacheEntry
+	if data, err := os.ReadFile(c.CachePath); err == nil && json.Unmarshal(data, &entry) == nil {
+		if c.Now().Sub(entry.CheckedAt) < c.Interval {
+			return entry.Latest, nil
+		}
+	}
+
+	latest, err := c.Latest()
+	if err != nil {
+		c.save(cacheEntry{CheckedAt: c.Now(), Latest: entry.Latest})
+		return "", err
+	}
+
+	entry = cacheEntry{CheckedAt: c.Now(), Latest: latest}
+	if err := c.save(entry); err != nil {
+		return "", err
+	}
+	return latest, nil
+}
+
+// save writes the check result to the cache file
+func (c *Checker) save(entry cacheEntry) error {
+	if err := os.MkdirAll(filepath.Dir(c.CachePath), 0755); err != nil {
+		return fmt.Errorf("failed to create cache directory: %w", err)
+	}
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(c.CachePath, data, 0644); err != nil {
+		return fmt.Errorf("failed to write update check cache: %w", err)
+	}
+	return nil
+}
+
+// Notice formats the one-line message shown when a newer release exists
+func Notice(current, latest string) string {
+	return fmt.Sprintf("A new version of %s is available: %s -> %s (https://github.com/%s/%s/releases/latest)",
+		Repo, current, latest, Owner, Repo)
+}
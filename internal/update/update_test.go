@@ -0,0 +1,93 @@
+package update
+
+import (
+	"errors"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestCheck(t *testing.T) {
+	tests := []struct {
+		name    string
+		current string
+		latest  string
+		want    string
+	}{
+		{"Newer release", "v1.2.0", "v1.3.0", "v1.3.0"},
+		{"Without v prefix", "1.2.0", "v1.2.1", "v1.2.1"},
+		{"Up to date", "v1.3.0", "v1.3.0", ""},
+		{"Ahead of release", "v1.4.0", "v1.3.0", ""},
+		{"Development build", "dev", "v1.3.0", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			checker := NewChecker(filepath.Join(t.TempDir(), "update.json"), func() (string, error) {
+				return tt.latest, nil
+			})
+			got, err := checker.Check(tt.current)
+			if err != nil {
+				t.Fatalf("Check() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("Check(%s) = %q, want %q", tt.current, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCheckCachesResult(t *testing.T) {
+	calls := 0
+	latest := "v1.1.0"
+	now := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	checker := NewChecker(filepath.Join(t.TempDir(), "cache", "update.json"), func() (string, error) {
+		calls++
+		return latest, nil
+	})
+	checker.Now = func() time.Time { return now }
+
+	if got, _ := checker.Check("v1.0.0"); got != "v1.1.0" {
+		t.Fatalf("Expected v1.1.0, got %q", got)
+	}
+
+	// Within the interval the cached tag is used
+	latest = "v1.2.0"
+	now = now.Add(time.Hour)
+	if got, _ := checker.Check("v1.0.0"); got != "v1.1.0" || calls != 1 {
+		t.Errorf("Expected cached v1.1.0 after 1 call, got %q after %d calls", got, calls)
+	}
+
+	// After the interval the release is queried again
+	now = now.Add(DefaultInterval)
+	if got, _ := checker.Check("v1.0.0"); got != "v1.2.0" || calls != 2 {
+		t.Errorf("Expected refreshed v1.2.0 after 2 calls, got %q after %d calls", got, calls)
+	}
+}
+
+func TestCheckError(t *testing.T) {
+	checker := NewChecker(filepath.Join(t.TempDir(), "update.json"), func() (string, error) {
+		return "", errors.New("offline")
+	})
+	if _, err := checker.Check("v1.0.0"); err == nil {
+		t.Error("Expected error when the release cannot be fetched")
+	}
+
+	// The failure is cached, so the next command does not query again
+	checker.Latest = func() (string, error) {
+		t.Error("Expected no query within the interval after a failure")
+		return "", nil
+	}
+	if got, err := checker.Check("v1.0.0"); err != nil || got != "" {
+		t.Errorf("Expected no notice, got %q, %v", got, err)
+	}
+}
+
+func TestNotice(t *testing.T) {
+	notice := Notice("v1.0.0", "v1.1.0")
+	if !strings.Contains(notice, "v1.0.0 -> v1.1.0") {
+		t.Errorf("Unexpected notice: %s", notice)
+	}
+}
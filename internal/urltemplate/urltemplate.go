@@ -0,0 +1,172 @@
+package urltemplate
+
+import (
+	"fmt"
+	"path"
+	"regexp"
+	"strings"
+)
+
+// Placeholders that can appear in a URL template
+const (
+	OS      = "os"      // Operating system, e.g. linux
+	Arch    = "arch"    // Architecture, e.g. amd64
+	Version = "version" // Version as given, e.g. 1.2.3
+)
+
+// placeholderPattern matches {name} placeholders
+var placeholderPattern = regexp.MustCompile(`\{([^{}]*)\}`)
+
+// Values are substituted for the placeholders of a template
+type Values struct {
+	OS      string
+	Arch    string
+	Version string
+}
+
+// IsTemplate reports whether s contains placeholders
+func IsTemplate(s string) bool {
+	return placeholderPattern.MatchString(s)
+}
+
+// Uses reports whether the template contains the named placeholder
+func Uses(template, name string) bool {
+	return strings.Contains(template, "{"+name+"}")
+}
+
+// Expand substitutes the placeholders of a template. Unknown placeholders and
+// placeholders without a value are errors, so a typo never produces a wrong URL.
+func Expand(template string, values Values) (string, error) {
+	var expandErr error
+	expanded := placeholderPattern.ReplaceAllStringFunc(template, func(match string) string {
+		name := match[1 : len(match)-1]
+		var value string
+		switch name {
+		case OS:
+			value = values.OS
+		case Arch:
+			value = values.Arch
+		case Version:
+			value = values.Version
+		default:
+			if expandErr == nil {
+				expandErr = fmt.Errorf("unknown placeholder %s in URL template (supported: {os}, {arch}, {version})", match)
+			}
+			return match
+		}
+		if value == "" && expandErr == nil {
+			expandErr = fmt.Errorf("no value for %s in URL template", match)
+		}
+		return value
+	})
+	if expandErr != nil {
+		return "", expandErr
+	}
+	return expanded, nil
+}
+
+// Validate checks that a template only uses known placeholders
+func Validate(template string) error {
+	_, err := Expand(template, Values{OS: OS, Arch: Arch, Version: Version})
+	return err
+}
+
+// Name derives a tool name from a template: the file name up to its first
+// placeholder, e.g. "tool" for https://example.com/tool-{version}-{os}.tar.gz. For plain
+// URLs it is the file name without its extension, version and platform, e.g. "tool"
+// for https://example.com/tool-1.2.0-linux-amd64.tar.gz.
+func Name(template string) string {
+	name := fileName(template)
+	if i := strings.Index(name, "{"); i > 0 {
+		return strings.TrimRight(name[:i], "-_.")
+	}
+	if strings.Contains(name, "{") {
+		return name
+	}
+
+	name = trimExt(name)
+	fields := nameSeparator.Split(name, -1)
+	end := 0
+	for i, field := range fields {
+		if i > 0 && (versionPattern.MatchString(field) || isPlatformWord(field)) {
+			break
+		}
+		end += len(field)
+		if end < len(name) {
+			end++ // The separator
+		}
+	}
+	if trimmed := strings.TrimRight(name[:end], "-_."); trimmed != "" {
+		return trimmed
+	}
+	return name
+}
+
+// VersionOf returns the version a plain URL names, from its file name or else a
+// directory of its path, e.g. "1.2.0" for https://example.com/tool-1.2.0.tar.gz and
+// "v2.0" for https://example.com/v2.0/tool.zip. It returns "" if there is none.
+func VersionOf(rawURL string) string {
+	for _, field := range nameSeparator.Split(trimExt(fileName(rawURL)), -1) {
+		if versionPattern.MatchString(field) {
+			return field
+		}
+	}
+	segments := strings.Split(strings.Trim(stripQuery(rawURL), "/"), "/")
+	for i := len(segments) - 2; i >= 0; i-- {
+		if versionPattern.MatchString(segments[i]) {
+			return segments[i]
+		}
+	}
+	return ""
+}
+
+// nameSeparator splits file names into words
+var nameSeparator = regexp.MustCompile(`[-_]`)
+
+// versionPattern matches versions with at least two numbers, e.g. 1.2 or v1.2.3
+var versionPattern = regexp.MustCompile(`^v?\d+(\.\d+)+$`)
+
+// platformWords are operating systems and architectures found in file names
+var platformWords = []string{
+	"linux", "darwin", "macos", "mac", "osx", "apple", "windows", "win", "win32", "win64", "freebsd", "openbsd", "netbsd",
+	"amd64", "x86", "x64", "arm64", "aarch64", "386", "i386", "i686", "armv6", "armv7", "arm", "universal",
+	"riscv64", "ppc64le", "s390x", "loong64",
+}
+
+// isPlatformWord reports whether a word names an operating system or architecture
+func isPlatformWord(word string) bool {
+	word = strings.ToLower(word)
+	for _, platform := range platformWords {
+		if word == platform {
+			return true
+		}
+	}
+	return false
+}
+
+// archiveExts are the extensions removed from file names, compound ones first
+var archiveExts = []string{".tar.gz", ".tar.xz", ".tar.bz2", ".tgz", ".zip", ".tar", ".gz", ".exe"}
+
+// trimExt removes an archive or executable extension from a file name
+func trimExt(name string) string {
+	lower := strings.ToLower(name)
+	for _, ext := range archiveExts {
+		if strings.HasSuffix(lower, ext) && len(name) > len(ext) {
+			return name[:len(name)-len(ext)]
+		}
+	}
+	return name
+}
+
+// fileName returns the last path element of a URL
+func fileName(rawURL string) string {
+	return path.Base(strings.TrimRight(stripQuery(rawURL), "/"))
+}
+
+// stripQuery removes the query and fragment of a URL
+func stripQuery(rawURL string) string {
+	if i := strings.IndexAny(rawURL, "?#"); i >= 0 {
+		return rawURL[:i]
+	}
+	return rawURL
+}
@@ -0,0 +1,99 @@
+package urltemplate
+
+import "testing"
+
+func TestExpand(t *testing.T) {
+	values := Values{OS: "linux", Arch: "amd64", Version: "1.2.3"}
+
+	tests := []struct {
+		name     string
+		template string
+		values   Values
+		want     string
+		wantErr  bool
+	}{
+		{"All placeholders", "https://example.com/tool-{version}-{os}-{arch}.tar.gz", values, "https://example.com/tool-1.2.3-linux-amd64.tar.gz", false},
+		{"Directories", "https://example.com/{version}/{os}/{arch}/tool", values, "https://example.com/1.2.3/linux/amd64/tool", false},
+		{"Repeated", "https://example.com/v{version}/tool-{version}.zip", values, "https://example.com/v1.2.3/tool-1.2.3.zip", false},
+		{"No placeholders", "https://example.com/tool.tar.gz", values, "https://example.com/tool.tar.gz", false},
+		{"Unknown placeholder", "https://example.com/tool-{platform}.tar.gz", values, "", true},
+		{"Missing version", "https://example.com/tool-{version}.tar.gz", Values{OS: "linux", Arch: "amd64"}, "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Expand(tt.template, tt.values)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Expand() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("Expand() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestIsTemplate(t *testing.T) {
+	if !IsTemplate("https://example.com/tool-{os}") {
+		t.Error("Expected a URL with a placeholder to be a template")
+	}
+	if IsTemplate("https://example.com/tool") {
+		t.Error("Expected a plain URL not to be a template")
+	}
+	if !Uses("https://example.com/tool-{version}", Version) || Uses("https://example.com/tool-{os}", Version) {
+		t.Error("Uses() did not detect the version placeholder correctly")
+	}
+}
+
+func TestValidate(t *testing.T) {
+	if err := Validate("https://example.com/tool-{version}-{os}-{arch}.zip"); err != nil {
+		t.Errorf("Validate() error = %v", err)
+	}
+	if err := Validate("https://example.com/tool-{ver}.zip"); err == nil {
+		t.Error("Expected an error for an unknown placeholder")
+	}
+}
+
+func TestName(t *testing.T) {
+	tests := []struct {
+		template string
+		want     string
+	}{
+		{"https://example.com/tool-{version}-{os}-{arch}.tar.gz", "tool"},
+		{"https://example.com/{version}/{os}/mytool", "mytool"},
+		{"https://example.com/my_tool_{os}.zip?download=1", "my_tool"},
+		{"https://example.com/tool.tar.gz", "tool"},
+		{"https://example.com/dl/my-tool-1.2.0-linux-amd64.tar.gz", "my-tool"},
+		{"https://example.com/dl/tool_v2.1_Windows_x86_64.zip", "tool"},
+		{"https://example.com/dl/1.0/tool", "tool"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.template, func(t *testing.T) {
+			if got := Name(tt.template); got != tt.want {
+				t.Errorf("Name() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestVersionOf(t *testing.T) {
+	tests := []struct {
+		url  string
+		want string
+	}{
+		{"https://example.com/tool-1.2.0-linux-amd64.tar.gz", "1.2.0"},
+		{"https://example.com/tool_v2.1_windows.zip?download=1", "v2.1"},
+		{"https://example.com/releases/v3.0.1/tool.zip", "v3.0.1"},
+		{"https://example.com/tool-linux-amd64.tar.gz", ""},
+		{"https://example.com/tool2/tool", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.url, func(t *testing.T) {
+			if got := VersionOf(tt.url); got != tt.want {
+				t.Errorf("VersionOf() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,24 @@
+package useragent
+
+import (
+	"fmt"
+	"net/http"
+	"runtime"
+)
+
+// Default is the User-Agent of the installer's requests, so that servers and proxies can
+// tell them apart from other Go programs
+var Default = "pyhub-installer"
+
+// SetVersion makes the User-Agent identify the installer version and platform, e.g.
+// "pyhub-installer/1.4.0 (linux; amd64)". It is called once at startup, before any
+// request is made.
+func SetVersion(version string) {
+	Default = fmt.Sprintf("pyhub-installer/%s (%s; %s)", version, runtime.GOOS, runtime.GOARCH)
+}
+
+// Apply sets the User-Agent of a request. Headers set afterwards, e.g. a User-Agent
+// chosen by the user, take precedence.
+func Apply(req *http.Request) {
+	req.Header.Set("User-Agent", Default)
+}
@@ -0,0 +1,20 @@
+package useragent
+
+import (
+	"net/http"
+	"runtime"
+	"testing"
+)
+
+func TestApply(t *testing.T) {
+	saved := Default
+	defer func() { Default = saved }()
+
+	SetVersion("v1.4.0")
+	req, _ := http.NewRequest("GET", "https://example.com", nil)
+	Apply(req)
+	want := "pyhub-installer/v1.4.0 (" + runtime.GOOS + "; " + runtime.GOARCH + ")"
+	if got := req.Header.Get("User-Agent"); got != want {
+		t.Errorf("User-Agent = %q, want %q", got, want)
+	}
+}
@@ -1,19 +1,26 @@
 package verify
 
 import (
+	"context"
 	"crypto/sha256"
 	"encoding/hex"
 	"fmt"
 	"io"
 	"net/http"
+	"net/url"
 	"os"
+	"path"
 	"strings"
+
+	"github.com/pyhub-kr/pyhub-installer/internal/retry"
+	"github.com/pyhub-kr/pyhub-installer/internal/useragent"
 )
 
 // Verifier handles file signature verification
 type Verifier struct {
 	FilePath      string
-	SignatureType string // "sha256", "sha512", "gpg"
+	SignatureType string            // "sha256", "sha512", "gpg"
+	Headers       map[string]string // Extra headers for signature downloads, e.g. authorization
 }
 
 // NewVerifier creates a new verifier
@@ -24,9 +31,9 @@ func NewVerifier(filePath string) *Verifier {
 }
 
 // VerifyWithURL verifies file against signature from URL
-func (v *Verifier) VerifyWithURL(signatureURL string) error {
+func (v *Verifier) VerifyWithURL(ctx context.Context, signatureURL string) error {
 	// Download signature
-	signature, err := v.downloadSignature(signatureURL)
+	signature, err := v.downloadSignature(ctx, signatureURL)
 	if err != nil {
 		return fmt.Errorf("failed to download signature: %w", err)
 	}
@@ -60,8 +67,21 @@ func (v *Verifier) VerifyWithString(signature string) error {
 }
 
 // downloadSignature downloads signature from URL
-func (v *Verifier) downloadSignature(url string) (string, error) {
-	resp, err := http.Get(url)
+func (v *Verifier) downloadSignature(ctx context.Context, rawURL string) (string, error) {
+	if _, err := url.Parse(rawURL); err != nil {
+		return "", err
+	}
+	resp, err := retry.Do(ctx, func() (*http.Response, error) {
+		req, err := http.NewRequestWithContext(ctx, "GET", rawURL, nil)
+		if err != nil {
+			return nil, err
+		}
+		useragent.Apply(req)
+		for key, value := range v.Headers {
+			req.Header.Set(key, value)
+		}
+		return http.DefaultClient.Do(req)
+	})
 	if err != nil {
 		return "", err
 	}
@@ -156,4 +176,33 @@ func (v *Verifier) GetSHA256() (string, error) {
 	}
 
 	return hex.EncodeToString(hash.Sum(nil)), nil
-}
\ No newline at end of file
+}
+
+// FetchChecksum downloads a checksum file and returns the SHA256 hash listed for name
+func (v *Verifier) FetchChecksum(ctx context.Context, url, name string) (string, error) {
+	content, err := v.downloadSignature(ctx, url)
+	if err != nil {
+		return "", fmt.Errorf("failed to download checksum: %w", err)
+	}
+	return ChecksumFor(content, name)
+}
+
+// ChecksumFor returns the SHA256 hash for name from checksum file content, either a
+// single hash or "<hash>  <name>" lines as written by sha256sum
+func ChecksumFor(content, name string) (string, error) {
+	lines := strings.Split(strings.TrimSpace(content), "\n")
+	for _, line := range lines {
+		fields := strings.Fields(line)
+		if len(fields) == 0 || len(fields[0]) != 64 {
+			continue
+		}
+		if len(lines) == 1 && len(fields) == 1 {
+			return fields[0], nil
+		}
+		listed := strings.TrimPrefix(fields[len(fields)-1], "*")
+		if len(fields) > 1 && path.Base(listed) == name {
+			return fields[0], nil
+		}
+	}
+	return "", fmt.Errorf("no SHA256 checksum found for %s", name)
+}
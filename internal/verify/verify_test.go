@@ -1,12 +1,14 @@
 package verify
 
 import (
+	"context"
 	"crypto/sha256"
 	"encoding/hex"
 	"net/http"
 	"net/http/httptest"
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 )
 
@@ -240,19 +242,19 @@ func TestVerifyWithURL(t *testing.T) {
 	v := NewVerifier(testFile)
 	
 	// Test successful verification
-	err = v.VerifyWithURL(server.URL + "/valid.sha256")
+	err = v.VerifyWithURL(context.Background(), server.URL + "/valid.sha256")
 	if err != nil {
 		t.Errorf("VerifyWithURL failed: %v", err)
 	}
 	
 	// Test failed verification
-	err = v.VerifyWithURL(server.URL + "/invalid.sha256")
+	err = v.VerifyWithURL(context.Background(), server.URL + "/invalid.sha256")
 	if err == nil {
 		t.Error("Expected error for invalid signature, got nil")
 	}
 	
 	// Test 404 response
-	err = v.VerifyWithURL(server.URL + "/notfound.sha256")
+	err = v.VerifyWithURL(context.Background(), server.URL + "/notfound.sha256")
 	if err == nil {
 		t.Error("Expected error for 404 response, got nil")
 	}
@@ -274,7 +276,7 @@ func TestDownloadSignature(t *testing.T) {
 	v := &Verifier{}
 	
 	// Test successful download
-	sig, err := v.downloadSignature(server.URL + "/signature.txt")
+	sig, err := v.downloadSignature(context.Background(), server.URL + "/signature.txt")
 	if err != nil {
 		t.Fatalf("downloadSignature failed: %v", err)
 	}
@@ -284,7 +286,7 @@ func TestDownloadSignature(t *testing.T) {
 	}
 	
 	// Test 404
-	_, err = v.downloadSignature(server.URL + "/notfound.txt")
+	_, err = v.downloadSignature(context.Background(), server.URL + "/notfound.txt")
 	if err == nil {
 		t.Error("Expected error for 404, got nil")
 	}
@@ -327,4 +329,57 @@ func hexToUpper(s string) string {
 		}
 	}
 	return result
-}
\ No newline at end of file
+}
+
+func TestDownloadSignatureWithHeaders(t *testing.T) {
+	expectedSig := "e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855"
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer secret" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.Write([]byte(expectedSig))
+	}))
+	defer server.Close()
+
+	v := &Verifier{Headers: map[string]string{"Authorization": "Bearer secret"}}
+	sig, err := v.downloadSignature(context.Background(), server.URL)
+	if err != nil {
+		t.Fatalf("downloadSignature failed: %v", err)
+	}
+	if sig != expectedSig {
+		t.Errorf("Expected signature %s, got %s", expectedSig, sig)
+	}
+}
+
+func TestChecksumFor(t *testing.T) {
+	hashA := strings.Repeat("a", 64)
+	hashB := strings.Repeat("b", 64)
+
+	tests := []struct {
+		name     string
+		content  string
+		file     string
+		expected string
+		wantErr  bool
+	}{
+		{"single hash", hashA, "tool.tar.gz", hashA, false},
+		{"single line", hashA + "  tool.tar.gz", "tool.tar.gz", hashA, false},
+		{"checksums file", hashA + "  other.zip\n" + hashB + "  tool.tar.gz\n", "tool.tar.gz", hashB, false},
+		{"binary mode and path", hashB + " *dist/tool.tar.gz", "tool.tar.gz", hashB, false},
+		{"not listed", hashA + "  other.zip\n" + hashB + "  another.zip", "tool.tar.gz", "", true},
+		{"not a sha256", "abc123  tool.tar.gz", "tool.tar.gz", "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ChecksumFor(tt.content, tt.file)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ChecksumFor() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.expected {
+				t.Errorf("Expected %s, got %s", tt.expected, got)
+			}
+		})
+	}
+}
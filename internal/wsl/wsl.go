@@ -0,0 +1,59 @@
+package wsl
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"regexp"
+	"runtime"
+	"strings"
+)
+
+// osReleasePath holds the kernel release, which names Microsoft under WSL
+var osReleasePath = "/proc/sys/kernel/osrelease"
+
+// run runs a command and returns its standard output; replaced in tests
+var run = func(name string, args ...string) ([]byte, error) {
+	return exec.Command(name, args...).Output()
+}
+
+// driveMount matches the mount points of Windows drives, e.g. /mnt/c
+var driveMount = regexp.MustCompile(`^/mnt/[a-zA-Z](/|$)`)
+
+// Detect reports whether the installer runs under the Windows Subsystem for Linux
+func Detect() bool {
+	if runtime.GOOS != "linux" {
+		return false
+	}
+	if os.Getenv("WSL_DISTRO_NAME") != "" {
+		return true
+	}
+	data, err := os.ReadFile(osReleasePath)
+	return err == nil && strings.Contains(strings.ToLower(string(data)), "microsoft")
+}
+
+// IsWindowsPath reports whether a Linux path is on a Windows drive mounted by WSL.
+// WSL appends the Windows PATH to the Linux one, but Linux binaries installed there
+// are slow to run and cannot be run by name from Windows.
+func IsWindowsPath(dir string) bool {
+	return driveMount.MatchString(dir)
+}
+
+// WindowsInstallDir returns the Linux path of the Windows user's install directory,
+// %LOCALAPPDATA%\Programs, through Windows interop
+func WindowsInstallDir() (string, error) {
+	out, err := run("cmd.exe", "/c", "echo %LOCALAPPDATA%")
+	if err != nil {
+		return "", fmt.Errorf("failed to run cmd.exe (is Windows interop enabled?): %w", err)
+	}
+	localAppData := strings.TrimSpace(string(out))
+	if localAppData == "" || strings.Contains(localAppData, "%") {
+		return "", fmt.Errorf("failed to read %%LOCALAPPDATA%% from Windows")
+	}
+
+	out, err = run("wslpath", "-u", localAppData+`\Programs`)
+	if err != nil {
+		return "", fmt.Errorf("failed to convert %s to a WSL path: %w", localAppData, err)
+	}
+	return strings.TrimSpace(string(out)), nil
+}
@@ -0,0 +1,97 @@
+package wsl
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+)
+
+func TestDetect(t *testing.T) {
+	if runtime.GOOS != "linux" {
+		if Detect() {
+			t.Error("WSL should only be detected on Linux")
+		}
+		return
+	}
+
+	original := osReleasePath
+	defer func() { osReleasePath = original }()
+	t.Setenv("WSL_DISTRO_NAME", "")
+
+	osReleasePath = filepath.Join(t.TempDir(), "osrelease")
+	if err := os.WriteFile(osReleasePath, []byte("6.8.0-45-generic\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if Detect() {
+		t.Error("Detected WSL on a plain Linux kernel")
+	}
+
+	if err := os.WriteFile(osReleasePath, []byte("5.15.153.1-microsoft-standard-WSL2\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if !Detect() {
+		t.Error("Expected WSL from the kernel release")
+	}
+
+	osReleasePath = filepath.Join(t.TempDir(), "missing")
+	t.Setenv("WSL_DISTRO_NAME", "Ubuntu")
+	if !Detect() {
+		t.Error("Expected WSL from WSL_DISTRO_NAME")
+	}
+}
+
+func TestIsWindowsPath(t *testing.T) {
+	tests := map[string]bool{
+		"/mnt/c":                  true,
+		"/mnt/c/Windows/system32": true,
+		"/mnt/D/tools":            true,
+		"/mnt/wsl":                false,
+		"/mnt/data/bin":           false,
+		"/usr/local/bin":          false,
+		"/home/user/mnt/c/bin":    false,
+		"/mnt/c/Users/user/AppData/Local/Programs": true,
+	}
+	for dir, want := range tests {
+		if got := IsWindowsPath(dir); got != want {
+			t.Errorf("IsWindowsPath(%q) = %v, want %v", dir, got, want)
+		}
+	}
+}
+
+func TestWindowsInstallDir(t *testing.T) {
+	original := run
+	defer func() { run = original }()
+
+	var calls []string
+	run = func(name string, args ...string) ([]byte, error) {
+		calls = append(calls, name+" "+strings.Join(args, " "))
+		switch name {
+		case "cmd.exe":
+			return []byte("C:\\Users\\user\\AppData\\Local\r\n"), nil
+		case "wslpath":
+			return []byte("/mnt/c/Users/user/AppData/Local/Programs\n"), nil
+		}
+		return nil, errors.New("unexpected command")
+	}
+
+	dir, err := WindowsInstallDir()
+	if err != nil {
+		t.Fatalf("WindowsInstallDir() error = %v", err)
+	}
+	if dir != "/mnt/c/Users/user/AppData/Local/Programs" {
+		t.Errorf("WindowsInstallDir() = %s", dir)
+	}
+	if len(calls) != 2 || calls[1] != `wslpath -u C:\Users\user\AppData\Local\Programs` {
+		t.Errorf("Unexpected commands: %q", calls)
+	}
+
+	run = func(name string, args ...string) ([]byte, error) {
+		return []byte("%LOCALAPPDATA%\r\n"), nil
+	}
+	if _, err := WindowsInstallDir(); err == nil {
+		t.Error("Expected error when %LOCALAPPDATA% is not expanded")
+	}
+}
@@ -1,10 +1,23 @@
 package config
 
 import (
+	"encoding/json"
 	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
 	"runtime"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/pyhub-kr/pyhub-installer/internal/lifecycle"
+	"github.com/pyhub-kr/pyhub-installer/internal/semver"
 )
 
+// AppName names the per-user config, data and cache directories
+const AppName = "pyhub-installer"
+
 // Config holds application configuration
 type Config struct {
 	// Download settings
@@ -12,13 +25,210 @@ type Config struct {
 	Parallelism int   `json:"parallelism"`
 	Timeout     int   `json:"timeout_seconds"`
 
+	// Use the proxy settings of the operating system when no proxy environment variable is set
+	SystemProxy bool `json:"system_proxy"`
+
+	// Connection-based proxy authentication: "ntlm" or "negotiate" (Kerberos), with
+	// credentials from the proxy URL, PYHUB_PROXY_USER/PYHUB_PROXY_PASSWORD or the Windows logon
+	ProxyAuth string `json:"proxy_auth"`
+
+	// PEM bundle of extra trusted root certificates, e.g. of a TLS-intercepting proxy
+	CACert string `json:"ca_cert"`
+
+	// PEM client certificate and key for servers requiring mutual TLS; the key may be
+	// in the certificate file
+	ClientCert string `json:"client_cert"`
+	ClientKey  string `json:"client_key"`
+
+	// Maximum concurrent HTTP requests of all downloads when installing several tools
+	MaxConnections int `json:"max_connections"`
+
 	// Installation settings
 	DefaultInstallPath string `json:"default_install_path"`
 	DefaultChmod       string `json:"default_chmod"`
 
+	// Install layout: "flat" unpacks tools into the install directory; "store" keeps every
+	// version in <data>/store/<tool>/<version> and links its executables into <data>/bin,
+	// like Homebrew's Cellar
+	Layout string `json:"layout"`
+
+	// Root of temporary files such as download chunks and staged extractions (default: a
+	// pyhub-installer directory in the system temp directory)
+	TempDir string `json:"temp_dir"`
+
 	// Verification settings
 	VerifyByDefault bool `json:"verify_by_default"`
 	ExtractByDefault bool `json:"extract_by_default"`
+
+	// Cleanup settings
+	CleanMaxAgeDays int `json:"clean_max_age_days"`
+
+	// Cache eviction: files older than the age, then the oldest files until the cache
+	// fits the size (0 for no limit). Run once a day after commands unless disabled.
+	CacheMaxSizeMB  int  `json:"cache_max_size_mb"`
+	CacheMaxAgeDays int  `json:"cache_max_age_days"`
+	AutoPruneCache  bool `json:"auto_prune_cache"`
+
+	// Check once a day whether a newer pyhub-installer release exists
+	UpdateCheck bool `json:"update_check"`
+
+	// Release resolution settings
+	Latest LatestPolicy          `json:"latest"`
+	Repos  map[string]RepoConfig `json:"repos"` // Keyed by owner/repo or full source
+
+	// Asset selection settings
+	Scoring ScoringConfig `json:"scoring"`
+
+	// Mirror URL templates for GitHub release assets, tried in order before GitHub.
+	// {url} is replaced by the original download URL, {path} by its path.
+	Mirrors []string `json:"mirrors"`
+
+	// Team cache server started with "proxy serve", e.g. http://cache.lan:7879. GitHub API
+	// requests and release downloads go through it while it is reachable.
+	CacheServer string `json:"cache_server"`
+
+	// Shell commands run at points of the install pipeline, keyed by event
+	// (pre-download, post-verify, post-install, post-uninstall)
+	Hooks map[string][]string `json:"hooks"`
+
+	// Shell commands printing a token or git credential output, keyed by host ("*" for
+	// any other host), run when a host needs credentials and no token is set
+	CredentialHelpers map[string]string `json:"credential_helpers"`
+
+	// Scheduled updates of installed tools by the agent command
+	Agent AgentConfig `json:"agent"`
+
+	// Retries of failed GitHub API requests, signature fetches and downloads
+	Retry RetryConfig `json:"retry"`
+}
+
+// RetryConfig controls how failed requests are retried
+type RetryConfig struct {
+	Attempts    int    `json:"attempts"`     // Total tries of a request; 1 disables retrying
+	BaseDelay   string `json:"base_delay"`   // Delay before the first retry, doubled before each further one
+	MaxDelay    string `json:"max_delay"`    // Longest delay between tries, including Retry-After
+	StatusCodes []int  `json:"status_codes"` // Response statuses that are retried
+}
+
+// AgentConfig controls the agent command
+type AgentConfig struct {
+	Interval string `json:"interval"` // Time between update runs, e.g. "24h"
+	Update   string `json:"update"`   // Update policy of tools without their own (default latest)
+	Notify   bool   `json:"notify"`   // Show a desktop notification for available and installed updates
+}
+
+// proxyAuthSchemes are the supported proxy_auth values
+var proxyAuthSchemes = []string{"ntlm", "negotiate"}
+
+// updatePolicies are the named update policies; version constraints are accepted too
+var updatePolicies = []string{"latest", "minor", "patch", "pinned"}
+
+// Install layouts
+const (
+	LayoutFlat  = "flat"
+	LayoutStore = "store"
+)
+
+// hookEvents are the events hooks can be configured for
+var hookEvents = []string{"pre-download", "post-verify", "post-install", "post-uninstall"}
+
+// ScoringConfig extends the built-in asset scoring rules
+type ScoringConfig struct {
+	Platforms   map[string][]string `json:"platforms"`   // Extra keywords per platform, e.g. {"linux-amd64": ["musl"]}; new platforms may be added
+	Adjustments map[string]int      `json:"adjustments"` // Score added when an asset name contains the key; negative values penalize
+}
+
+// LatestPolicy controls which release "latest" resolves to
+type LatestPolicy struct {
+	IncludePrerelease bool     `json:"include_prerelease"` // Consider pre-releases
+	SkipTags          []string `json:"skip_tags"`          // Regexes of yanked/deprecated tags or release names
+	PreferTags        string   `json:"prefer_tags"`        // Regex of tags to pick over newer non-matching ones
+}
+
+// IsDefault reports whether the policy is the plain latest release of the provider
+func (p LatestPolicy) IsDefault() bool {
+	return !p.IncludePrerelease && len(p.SkipTags) == 0 && p.PreferTags == ""
+}
+
+// validate checks that the policy's patterns compile
+func (p LatestPolicy) validate() error {
+	for _, pattern := range append([]string{p.PreferTags}, p.SkipTags...) {
+		if _, err := regexp.Compile(pattern); err != nil {
+			return fmt.Errorf("invalid tag pattern %q: %w", pattern, err)
+		}
+	}
+	return nil
+}
+
+// RepoConfig holds settings for a single source
+type RepoConfig struct {
+	Latest       *LatestPolicy    `json:"latest,omitempty"`
+	Sources      []string         `json:"sources,omitempty"`      // Sources to try in order, replacing the one given
+	Deprecations []lifecycle.Rule `json:"deprecations,omitempty"` // Versions marked deprecated, yanked or EOL
+	Update       string           `json:"update,omitempty"`       // Agent update policy: latest, minor, patch, pinned or a version constraint
+}
+
+// repoKeys returns the Repos keys a source is matched by: as given (e.g.
+// github:owner/repo) and by path (owner/repo)
+func repoKeys(source string) []string {
+	keys := []string{source}
+	if _, path, ok := strings.Cut(source, ":"); ok {
+		keys = append(keys, path)
+	}
+	return keys
+}
+
+// LatestPolicyFor returns the latest policy of a source: its own policy if configured,
+// otherwise the global one
+func (c *Config) LatestPolicyFor(source string) LatestPolicy {
+	for _, key := range repoKeys(source) {
+		if repo, ok := c.Repos[key]; ok && repo.Latest != nil {
+			return *repo.Latest
+		}
+	}
+	return c.Latest
+}
+
+// SourcesFor returns the fallback chain configured for a source or tool name, or nil
+func (c *Config) SourcesFor(source string) []string {
+	for _, key := range repoKeys(source) {
+		if repo, ok := c.Repos[key]; ok && len(repo.Sources) > 0 {
+			return repo.Sources
+		}
+	}
+	return nil
+}
+
+// DeprecationsFor returns the version status rules configured for a source or tool name
+func (c *Config) DeprecationsFor(source string) []lifecycle.Rule {
+	for _, key := range repoKeys(source) {
+		if repo, ok := c.Repos[key]; ok && len(repo.Deprecations) > 0 {
+			return repo.Deprecations
+		}
+	}
+	return nil
+}
+
+// UpdatePolicyFor returns the agent update policy of a source: its own policy if
+// configured, otherwise the global one
+func (c *Config) UpdatePolicyFor(source string) string {
+	for _, key := range repoKeys(source) {
+		if repo, ok := c.Repos[key]; ok && repo.Update != "" {
+			return repo.Update
+		}
+	}
+	return c.Agent.Update
+}
+
+// validateUpdatePolicy checks that a policy is named or a version constraint
+func validateUpdatePolicy(policy string) error {
+	if policy == "" || slices.Contains(updatePolicies, policy) {
+		return nil
+	}
+	if _, err := semver.ParseConstraint(policy); err != nil {
+		return fmt.Errorf("unknown update policy %q (use %s or a version constraint)", policy, strings.Join(updatePolicies, ", "))
+	}
+	return nil
 }
 
 // DefaultConfig returns default configuration
@@ -27,9 +237,23 @@ func DefaultConfig() *Config {
 		ChunkSize:        1024 * 1024, // 1MB chunks
 		Parallelism:      4,           // 4 parallel downloads
 		Timeout:          300,         // 5 minutes
+		MaxConnections:   8,
+		SystemProxy:      true,
 		VerifyByDefault:  true,
 		ExtractByDefault: true,
 		DefaultChmod:     "755",
+		Layout:           LayoutFlat,
+		CleanMaxAgeDays:  30,
+		CacheMaxSizeMB:   1024,
+		CacheMaxAgeDays:  30,
+		AutoPruneCache:   true,
+		Agent:            AgentConfig{Interval: "24h"},
+		Retry: RetryConfig{
+			Attempts:    3,
+			BaseDelay:   "500ms",
+			MaxDelay:    "10s",
+			StatusCodes: []int{408, 429, 500, 502, 503, 504},
+		},
 	}
 
 	// Platform-specific defaults
@@ -58,8 +282,208 @@ func (c *Config) Validate() error {
 	if c.Timeout <= 0 {
 		return fmt.Errorf("timeout must be positive")
 	}
+	if c.MaxConnections <= 0 {
+		return fmt.Errorf("max_connections must be positive")
+	}
 	if c.DefaultInstallPath == "" {
 		return fmt.Errorf("default_install_path cannot be empty")
 	}
+	if c.CleanMaxAgeDays < 0 {
+		return fmt.Errorf("clean_max_age_days cannot be negative")
+	}
+	if c.CacheMaxSizeMB < 0 {
+		return fmt.Errorf("cache_max_size_mb cannot be negative")
+	}
+	if c.CacheMaxAgeDays <= 0 {
+		return fmt.Errorf("cache_max_age_days must be positive")
+	}
+	if err := c.Latest.validate(); err != nil {
+		return fmt.Errorf("latest: %w", err)
+	}
+	for _, mirror := range c.Mirrors {
+		if err := ValidateMirror(mirror); err != nil {
+			return err
+		}
+	}
+	if c.Layout != "" && c.Layout != LayoutFlat && c.Layout != LayoutStore {
+		return fmt.Errorf("layout: unknown layout %q (supported: %s, %s)", c.Layout, LayoutFlat, LayoutStore)
+	}
+	if c.CacheServer != "" && !strings.HasPrefix(c.CacheServer, "https://") && !strings.HasPrefix(c.CacheServer, "http://") {
+		return fmt.Errorf("cache_server %q must be an http(s) URL", c.CacheServer)
+	}
+	if c.ProxyAuth != "" && !slices.Contains(proxyAuthSchemes, c.ProxyAuth) {
+		return fmt.Errorf("proxy_auth: unknown scheme %q (supported: %s)", c.ProxyAuth, strings.Join(proxyAuthSchemes, ", "))
+	}
+	if c.ClientKey != "" && c.ClientCert == "" {
+		return fmt.Errorf("client_key needs client_cert")
+	}
+	for event := range c.Hooks {
+		if !slices.Contains(hookEvents, event) {
+			return fmt.Errorf("hooks: unknown event %q (supported: %s)", event, strings.Join(hookEvents, ", "))
+		}
+	}
+	for host, command := range c.CredentialHelpers {
+		if strings.TrimSpace(command) == "" {
+			return fmt.Errorf("credential_helpers.%s: empty command", host)
+		}
+	}
+	if interval, err := time.ParseDuration(c.Agent.Interval); err != nil || interval <= 0 {
+		return fmt.Errorf("agent.interval must be a positive duration such as \"24h\"")
+	}
+	if c.Retry.Attempts < 1 {
+		return fmt.Errorf("retry.attempts must be at least 1")
+	}
+	if d, err := time.ParseDuration(c.Retry.BaseDelay); err != nil || d <= 0 {
+		return fmt.Errorf("retry.base_delay must be a positive duration such as \"500ms\"")
+	}
+	if d, err := time.ParseDuration(c.Retry.MaxDelay); err != nil || d <= 0 {
+		return fmt.Errorf("retry.max_delay must be a positive duration such as \"10s\"")
+	}
+	for _, code := range c.Retry.StatusCodes {
+		if code < 100 || code > 599 {
+			return fmt.Errorf("retry.status_codes: invalid HTTP status %d", code)
+		}
+	}
+	if err := validateUpdatePolicy(c.Agent.Update); err != nil {
+		return fmt.Errorf("agent.update: %w", err)
+	}
+	for name, repo := range c.Repos {
+		if err := validateUpdatePolicy(repo.Update); err != nil {
+			return fmt.Errorf("repos.%s.update: %w", name, err)
+		}
+		for _, source := range repo.Sources {
+			if strings.TrimSpace(source) == "" {
+				return fmt.Errorf("repos.%s.sources: empty source", name)
+			}
+		}
+		for i, rule := range repo.Deprecations {
+			if err := rule.Validate(); err != nil {
+				return fmt.Errorf("repos.%s.deprecations[%d]: %w", name, i, err)
+			}
+		}
+		if repo.Latest == nil {
+			continue
+		}
+		if err := repo.Latest.validate(); err != nil {
+			return fmt.Errorf("repos.%s.latest: %w", name, err)
+		}
+	}
+	return nil
+}
+
+// ValidateMirror checks a mirror URL template, which must be an http(s) URL containing
+// {url} or {path}
+func ValidateMirror(mirror string) error {
+	if !strings.Contains(mirror, "{url}") && !strings.Contains(mirror, "{path}") {
+		return fmt.Errorf("mirror %q must contain {url} or {path}", mirror)
+	}
+	if !strings.HasPrefix(mirror, "https://") && !strings.HasPrefix(mirror, "http://") {
+		return fmt.Errorf("mirror %q must be an http(s) URL", mirror)
+	}
 	return nil
+}
+
+// Load reads the user config file on top of the defaults.
+// A missing config file is not an error.
+func Load() (*Config, error) {
+	config := DefaultConfig()
+
+	path, err := ConfigPath()
+	if err != nil {
+		return config, nil
+	}
+
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return config, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read config file: %w", err)
+	}
+
+	if err := json.Unmarshal(data, config); err != nil {
+		return nil, fmt.Errorf("failed to parse config file %s: %w", path, err)
+	}
+
+	if err := config.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid config file %s: %w", path, err)
+	}
+
+	return config, nil
+}
+
+// ConfigPath returns the location of the user config file
+func ConfigPath() (string, error) {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to get user config directory: %w", err)
+	}
+	return filepath.Join(dir, AppName, "config.json"), nil
+}
+
+// AliasesPath returns the location of the user alias file (name -> source)
+func AliasesPath() (string, error) {
+	configPath, err := ConfigPath()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(filepath.Dir(configPath), "aliases.json"), nil
+}
+
+// DataDir returns the directory for installer-managed data (versioned store, backups)
+func DataDir() (string, error) {
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to get user home directory: %w", err)
+	}
+
+	switch runtime.GOOS {
+	case "windows":
+		localAppData := os.Getenv("LOCALAPPDATA")
+		if localAppData == "" {
+			localAppData = filepath.Join(homeDir, "AppData", "Local")
+		}
+		return filepath.Join(localAppData, AppName), nil
+	default:
+		if xdgData := os.Getenv("XDG_DATA_HOME"); xdgData != "" {
+			return filepath.Join(xdgData, AppName), nil
+		}
+		return filepath.Join(homeDir, ".local", "share", AppName), nil
+	}
+}
+
+// StoreDir returns the root of versioned installs (<store>/<tool>/<version>)
+func StoreDir() (string, error) {
+	dataDir, err := DataDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dataDir, "store"), nil
+}
+
+// BinDir returns the directory of links to the executables of the store layout
+func BinDir() (string, error) {
+	dataDir, err := DataDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dataDir, "bin"), nil
+}
+
+// BackupDir returns the directory where replaced files are kept
+func BackupDir() (string, error) {
+	dataDir, err := DataDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dataDir, "backups"), nil
+}
+
+// CacheDir returns the directory for downloaded artifacts and API responses
+func CacheDir() (string, error) {
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to get user cache directory: %w", err)
+	}
+	return filepath.Join(dir, AppName), nil
 }
\ No newline at end of file